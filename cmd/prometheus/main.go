@@ -24,14 +24,17 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/alecthomas/units"
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/oklog/oklog/pkg/group"
+	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
@@ -43,6 +46,7 @@ import (
 	"github.com/prometheus/common/promlog"
 	promlogflag "github.com/prometheus/common/promlog/flag"
 	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/discovery"
 	"github.com/prometheus/prometheus/notifier"
 	"github.com/prometheus/prometheus/promql"
 	"github.com/prometheus/prometheus/retrieval"
@@ -50,7 +54,11 @@ import (
 	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/storage/remote"
 	"github.com/prometheus/prometheus/storage/tsdb"
+	"github.com/prometheus/prometheus/util/logging"
+	"github.com/prometheus/prometheus/util/tracing"
+	"github.com/prometheus/prometheus/util/watchdog"
 	"github.com/prometheus/prometheus/web"
+	api_v1 "github.com/prometheus/prometheus/web/api/v1"
 )
 
 var (
@@ -78,6 +86,7 @@ func main() {
 
 	cfg := struct {
 		configFile string
+		agent      bool
 
 		localStoragePath string
 		notifier         notifier.Options
@@ -86,12 +95,38 @@ func main() {
 		web              web.Options
 		tsdb             tsdb.Options
 		lookbackDelta    model.Duration
+		maxLookbackDelta model.Duration
 		webTimeout       model.Duration
 		queryTimeout     model.Duration
+		outageTolerance  model.Duration
+		forGracePeriod   model.Duration
+		maxRuleEvals     int
+		managedRulesDir  string
+		maxExemplars     int
 
-		prometheusURL string
+		scrapeDurationBuckets []float64
+		ruleDurationBuckets   []float64
 
-		logLevel promlog.AllowedLevel
+		queryCacheMaxSize units.Base2Bytes
+
+		scrapeDumpDir         string
+		scrapeDumpMaxFileSize units.Base2Bytes
+		scrapeDumpMaxFiles    int
+		scrapeShard           string
+
+		watchdog                   watchdog.Options
+		watchdogHeapThreshold      units.Base2Bytes
+		watchdogCheckInterval      model.Duration
+		watchdogMinCaptureInterval model.Duration
+
+		tracing         tracing.Options
+		tracingExporter string
+
+		prometheusURL   string
+		corsRegexString string
+
+		logLevel  promlog.AllowedLevel
+		logFormat logging.Format
 	}{
 		notifier: notifier.Options{
 			Registerer: prometheus.DefaultRegisterer,
@@ -107,15 +142,24 @@ func main() {
 	a.Flag("config.file", "Prometheus configuration file path.").
 		Default("prometheus.yml").StringVar(&cfg.configFile)
 
-	a.Flag("web.listen-address", "Address to listen on for UI, API, and telemetry.").
+	a.Flag("agent", "Run in agent mode, which only scrapes targets and forwards the samples via remote write. Querying, rule evaluation and alerting are disabled and the local storage only keeps the samples until they are sent. Rule files and alerting are not allowed in the configuration.").
+		Default("false").BoolVar(&cfg.agent)
+
+	a.Flag("web.listen-address", "Address to listen on for UI, API, and telemetry. Use unix:///path/to.sock for a Unix domain socket or systemd://[<n>] for a socket passed by systemd socket activation.").
 		Default("0.0.0.0:9090").StringVar(&cfg.web.ListenAddress)
 
 	a.Flag("web.read-timeout",
 		"Maximum duration before timing out read of the request, and closing idle connections.").
 		Default("5m").SetValue(&cfg.webTimeout)
 
-	a.Flag("web.max-connections", "Maximum number of simultaneous connections.").
-		Default("512").IntVar(&cfg.web.MaxConnections)
+	a.Flag("web.max-requests-per-client", "Maximum number of concurrent HTTP requests per client IP. Further requests are rejected with 429 Too Many Requests. 0 means no limit.").
+		Default("128").IntVar(&cfg.web.MaxRequestsPerClient)
+
+	a.Flag("web.max-expensive-requests-per-client", "Maximum number of concurrent range query and federation requests per client IP. Further requests are rejected with 429 Too Many Requests. 0 means no limit.").
+		Default("32").IntVar(&cfg.web.MaxExpensiveRequestsPerClient)
+
+	a.Flag("web.cors.origin", `Regex for CORS origin. It is fully anchored. Example: 'https?://(domain1|domain2)\.com'`).
+		Default(".*").StringVar(&cfg.corsRegexString)
 
 	a.Flag("web.external-url",
 		"The URL under which Prometheus is externally reachable (for example, if Prometheus is served via a reverse proxy). Used for generating relative and absolute links back to Prometheus itself. If the URL has a path portion, it will be used to prefix all HTTP endpoints served by Prometheus. If omitted, relevant URL components will be derived automatically.").
@@ -134,11 +178,35 @@ func main() {
 	a.Flag("web.enable-admin-api", "Enables API endpoints for admin control actions.").
 		Default("false").BoolVar(&cfg.web.EnableAdminAPI)
 
-	a.Flag("web.console.templates", "Path to the console template directory, available at /consoles.").
-		Default("consoles").StringVar(&cfg.web.ConsoleTemplatesPath)
+	a.Flag("web.tenant-label", "Label holding the tenant of series. If set, queries, series and label requests of the API only select the series of the requesting tenant.").
+		Default("").StringVar(&cfg.web.Tenant.Label)
+
+	a.Flag("web.tenant-header", "HTTP header carrying the tenant of API requests.").
+		Default(api_v1.DefaultTenantHeader).StringVar(&cfg.web.Tenant.Header)
+
+	a.Flag("web.default-tenant", "Tenant of API requests without the tenant header. If empty, such requests are rejected.").
+		Default("").StringVar(&cfg.web.Tenant.Default)
+
+	a.Flag("web.tls-cert-file", "Path to the TLS certificate file. Enables HTTPS together with --web.tls-key-file. Reloaded with the configuration.").
+		PlaceHolder("<path>").StringVar(&cfg.web.TLSCertFile)
 
-	a.Flag("web.console.libraries", "Path to the console library directory.").
-		Default("console_libraries").StringVar(&cfg.web.ConsoleLibrariesPath)
+	a.Flag("web.tls-key-file", "Path to the TLS private key file.").
+		PlaceHolder("<path>").StringVar(&cfg.web.TLSKeyFile)
+
+	a.Flag("web.tls-client-ca-file", "Path to the CA certificate file used to verify client certificates. If set, clients must present a valid certificate.").
+		PlaceHolder("<path>").StringVar(&cfg.web.TLSClientCAFile)
+
+	a.Flag("web.tls-client-auth-optional", "Only verify client certificates if presented instead of requiring them.").
+		Default("false").BoolVar(&cfg.web.TLSClientAuthOptional)
+
+	a.Flag("web.basic-auth-file", "Path to a YAML file with users and bcrypt hashed passwords that are allowed to access the web endpoints (basic_auth_users: {<user>: <hash>}). Reloaded with the configuration.").
+		PlaceHolder("<path>").StringVar(&cfg.web.BasicAuthFile)
+
+	a.Flag("web.console.templates", "Path to a console template directory, available at /consoles. May be repeated, earlier directories take precedence.").
+		Default("consoles").StringsVar(&cfg.web.ConsoleTemplatesPaths)
+
+	a.Flag("web.console.libraries", "Path to a console library directory. May be repeated, earlier directories take precedence.").
+		Default("console_libraries").StringsVar(&cfg.web.ConsoleLibrariesPaths)
 
 	a.Flag("storage.tsdb.path", "Base path for metrics storage.").
 		Default("data/").StringVar(&cfg.localStoragePath)
@@ -153,10 +221,49 @@ func main() {
 	a.Flag("storage.tsdb.retention", "How long to retain samples in the storage.").
 		Default("15d").SetValue(&cfg.tsdb.Retention)
 
+	a.Flag("storage.tsdb.retention.size", "Maximum number of bytes that can be stored for blocks. Units supported: KB, MB, GB, TB, PB. The oldest blocks are deleted first. 0 means no limit.").
+		Default("0").BytesVar(&cfg.tsdb.MaxBytes)
+
+	a.Flag("storage.tsdb.out-of-order-time-window", "How far behind the newest sample out of order samples are still accepted. May be at most half the minimum block duration. 0 rejects all out of order samples.").
+		Default("0s").SetValue(&cfg.tsdb.OutOfOrderTimeWindow)
+
 	a.Flag("storage.tsdb.no-lockfile", "Do not create lockfile in data directory.").
 		Default("false").BoolVar(&cfg.tsdb.NoLockfile)
 
-	a.Flag("alertmanager.notification-queue-capacity", "The capacity of the queue for pending alert manager notifications.").
+	a.Flag("storage.exemplars.max-exemplars", "Maximum number of exemplars kept in memory across all series. The oldest exemplars are dropped first. 0 disables storing exemplars.").
+		Default("100000").IntVar(&cfg.maxExemplars)
+
+	a.Flag("rules.alert.for-outage-tolerance", "Max time to tolerate prometheus outage for restoring 'for' state of alert.").
+		Default("1h").SetValue(&cfg.outageTolerance)
+
+	a.Flag("rules.alert.for-grace-period", "Minimum duration between alert and restored 'for' state. This is maintained only for alerts with configured 'for' time greater than grace period.").
+		Default("10m").SetValue(&cfg.forGracePeriod)
+
+	a.Flag("rules.max-concurrent-evals", "Maximum number of rule groups evaluated concurrently. Groups reading each other's results are never evaluated concurrently. 0 means no limit.").
+		Default("0").IntVar(&cfg.maxRuleEvals)
+
+	a.Flag("rules.managed-dir", "Directory for the rule groups managed through the experimental rules write API. The API is disabled if empty.").
+		Default("").StringVar(&cfg.managedRulesDir)
+
+	a.Flag("rules.evaluation-duration-buckets", "Upper bounds of the buckets of the rule and rule group evaluation duration histograms in seconds. May be repeated. Defaults to buckets from 1ms to 1m.").
+		Float64ListVar(&cfg.ruleDurationBuckets)
+
+	a.Flag("scrape.duration-buckets", "Upper bounds of the buckets of the scrape duration histogram in seconds. May be repeated. Defaults to buckets from 5ms to 1m.").
+		Float64ListVar(&cfg.scrapeDurationBuckets)
+
+	a.Flag("scrape.dump-dir", "Directory to record the raw bodies of all scrapes to for debugging. They can be replayed with 'promtool tsdb replay-scrapes'. Disabled if empty.").
+		Default("").StringVar(&cfg.scrapeDumpDir)
+
+	a.Flag("scrape.dump-max-file-size", "Size after which a new file is started in the scrape dump directory. Units supported: KB, MB, GB.").
+		Default("64MB").BytesVar(&cfg.scrapeDumpMaxFileSize)
+
+	a.Flag("scrape.dump-max-files", "Maximum number of files kept in the scrape dump directory. The oldest files are deleted first.").
+		Default("10").IntVar(&cfg.scrapeDumpMaxFiles)
+
+	a.Flag("scrape.shard", "Only scrape the targets of all jobs in the N-th of M shards, given as N/M with N from 0 to M-1. Targets are assigned to shards by the hash of their address after relabeling, like by a hashmod relabeling with modulus M. Disabled if empty.").
+		Default("").StringVar(&cfg.scrapeShard)
+
+	a.Flag("alertmanager.notification-queue-capacity", "The capacity of the queue for pending alert manager notifications, per Alertmanager.").
 		Default("10000").IntVar(&cfg.notifier.QueueCapacity)
 
 	a.Flag("alertmanager.timeout", "Timeout for sending alerts to Alertmanager.").
@@ -165,14 +272,56 @@ func main() {
 	a.Flag("query.lookback-delta", "The delta difference allowed for retrieving metrics during expression evaluations.").
 		Default("5m").SetValue(&cfg.lookbackDelta)
 
+	a.Flag("query.max-lookback-delta", "Maximum lookback delta that queries of the HTTP API may request. The value of --query.lookback-delta is always allowed.").
+		Default("1h").SetValue(&cfg.maxLookbackDelta)
+
+	a.Flag("query.enable-negative-offset", "Experimental: allow negative offsets in PromQL, which select samples after the evaluation time, e.g. to look forward at delayed or backfilled data in recording rules.").
+		Default("false").BoolVar(&promql.EnableNegativeOffset)
+
 	a.Flag("query.timeout", "Maximum time a query may take before being aborted.").
 		Default("2m").SetValue(&cfg.queryTimeout)
 
 	a.Flag("query.max-concurrency", "Maximum number of queries executed concurrently.").
 		Default("20").IntVar(&cfg.queryEngine.MaxConcurrentQueries)
 
+	a.Flag("query.cache-max-size", "Maximum estimated memory of the cached results of range queries aligned to their step. Units supported: KB, MB, GB. 0 disables the cache.").
+		Default("0").BytesVar(&cfg.queryCacheMaxSize)
+
+	a.Flag("query.max-samples", "Maximum number of samples a single query can load into memory. Note that queries will fail if they would load more samples than this into memory, so this also limits the number of samples a query can return.").
+		Default("50000000").IntVar(&cfg.queryEngine.MaxSamples)
+
+	a.Flag("debug.watchdog.dir", "Directory the runtime watchdog writes heap and goroutine profiles to when a threshold is exceeded. The watchdog is disabled if empty.").
+		Default("").StringVar(&cfg.watchdog.Dir)
+
+	a.Flag("debug.watchdog.heap-threshold", "Heap size above which the runtime watchdog captures profiles. Units supported: KB, MB, GB. 0 disables the threshold.").
+		Default("0").BytesVar(&cfg.watchdogHeapThreshold)
+
+	a.Flag("debug.watchdog.goroutine-threshold", "Number of goroutines above which the runtime watchdog captures profiles. 0 disables the threshold.").
+		Default("0").IntVar(&cfg.watchdog.GoroutineThreshold)
+
+	a.Flag("debug.watchdog.check-interval", "Interval at which the runtime watchdog checks the thresholds.").
+		Default("15s").SetValue(&cfg.watchdogCheckInterval)
+
+	a.Flag("debug.watchdog.min-capture-interval", "Minimum interval between two captures of the runtime watchdog while a threshold remains exceeded.").
+		Default("5m").SetValue(&cfg.watchdogMinCaptureInterval)
+
+	a.Flag("debug.watchdog.max-captures", "Number of most recent captures of the runtime watchdog that are kept.").
+		Default("10").IntVar(&cfg.watchdog.MaxCaptures)
+
+	a.Flag("tracing.exporter", "Exporter of the traces of queries. The jaeger exporter sends them to a Jaeger agent or collector.").
+		Default("none").EnumVar(&cfg.tracingExporter, "none", "jaeger")
+
+	a.Flag("tracing.endpoint", "Address of the Jaeger agent the traces are sent to over UDP, e.g. localhost:6831, or URL of a Jaeger collector, e.g. http://jaeger-collector:14268/api/traces.").
+		Default("").StringVar(&cfg.tracing.Endpoint)
+
+	a.Flag("tracing.sample-rate", "Ratio of queries that are traced, between 0 and 1. Requests continuing a trace of another service are traced if it is.").
+		Default("0.1").Float64Var(&cfg.tracing.SampleRate)
+
 	promlogflag.AddFlags(a, &cfg.logLevel)
 
+	a.Flag(logging.FormatFlagName, logging.FormatFlagHelp).
+		Default("logfmt").SetValue(&cfg.logFormat)
+
 	_, err := a.Parse(os.Args[1:])
 	if err != nil {
 		fmt.Fprintln(os.Stderr, errors.Wrapf(err, "Error parsing commandline arguments"))
@@ -180,11 +329,58 @@ func main() {
 		os.Exit(2)
 	}
 
+	if (cfg.web.TLSCertFile == "") != (cfg.web.TLSKeyFile == "") {
+		fmt.Fprintln(os.Stderr, "Both --web.tls-cert-file and --web.tls-key-file must be set to enable TLS")
+		os.Exit(2)
+	}
+	if cfg.web.TLSClientCAFile != "" && cfg.web.TLSCertFile == "" {
+		fmt.Fprintln(os.Stderr, "--web.tls-client-ca-file requires TLS to be enabled")
+		os.Exit(2)
+	}
+
+	if cfg.tracingExporter != "none" && cfg.tracing.Endpoint == "" {
+		fmt.Fprintln(os.Stderr, "--tracing.endpoint must be set to export traces")
+		os.Exit(2)
+	}
+	if cfg.tracing.SampleRate < 0 || cfg.tracing.SampleRate > 1 {
+		fmt.Fprintln(os.Stderr, "--tracing.sample-rate must be between 0 and 1")
+		os.Exit(2)
+	}
+
+	if cfg.agent && cfg.managedRulesDir != "" {
+		fmt.Fprintln(os.Stderr, "--rules.managed-dir cannot be used in agent mode")
+		os.Exit(2)
+	}
+
+	var shard retrieval.Shard
+	if cfg.scrapeShard != "" {
+		shard, err = retrieval.ParseShard(cfg.scrapeShard)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, errors.Wrapf(err, "invalid --scrape.shard"))
+			os.Exit(2)
+		}
+	}
+
+	if cfg.watchdog.Dir != "" && cfg.watchdogCheckInterval <= 0 {
+		fmt.Fprintln(os.Stderr, "--debug.watchdog.check-interval must be positive")
+		os.Exit(2)
+	}
+
+	cfg.web.CORSOrigin, err = compileCORSRegexString(cfg.corsRegexString)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, errors.Wrapf(err, "could not compile CORS regex string %q", cfg.corsRegexString))
+		os.Exit(2)
+	}
+
 	cfg.web.ExternalURL, err = computeExternalURL(cfg.prometheusURL, cfg.web.ListenAddress)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, errors.Wrapf(err, "parse external URL %q", cfg.prometheusURL))
 		os.Exit(2)
 	}
+	// The inferred external URL must use the scheme we are serving.
+	if cfg.prometheusURL == "" && cfg.web.TLSCertFile != "" {
+		cfg.web.ExternalURL.Scheme = "https"
+	}
 
 	cfg.web.ReadTimeout = time.Duration(cfg.webTimeout)
 	// Default -web.route-prefix to path of -web.external-url.
@@ -194,15 +390,30 @@ func main() {
 	// RoutePrefix must always be at least '/'.
 	cfg.web.RoutePrefix = "/" + strings.Trim(cfg.web.RoutePrefix, "/")
 
+	if cfg.agent {
+		// Remote write reads the samples from the WAL, blocks are deleted
+		// as soon as they are cut from the head.
+		cfg.tsdb.Retention = cfg.tsdb.MinBlockDuration
+		cfg.tsdb.MaxBlockDuration = cfg.tsdb.MinBlockDuration
+	}
 	if cfg.tsdb.MaxBlockDuration == 0 {
 		cfg.tsdb.MaxBlockDuration = cfg.tsdb.Retention / 10
 	}
 
 	promql.LookbackDelta = time.Duration(cfg.lookbackDelta)
+	cfg.web.MaxLookbackDelta = time.Duration(cfg.maxLookbackDelta)
+	cfg.web.QueryCacheMaxBytes = int64(cfg.queryCacheMaxSize)
+	cfg.web.OutOfOrderTimeWindow = time.Duration(cfg.tsdb.OutOfOrderTimeWindow)
 
 	cfg.queryEngine.Timeout = time.Duration(cfg.queryTimeout)
 
-	logger := promlog.New(cfg.logLevel)
+	cfg.watchdog.HeapThreshold = uint64(cfg.watchdogHeapThreshold)
+	cfg.watchdog.CheckInterval = time.Duration(cfg.watchdogCheckInterval)
+	cfg.watchdog.MinCaptureInterval = time.Duration(cfg.watchdogMinCaptureInterval)
+
+	cfg.tracing.FlushInterval = 5 * time.Second
+
+	logger := logging.New(os.Stderr, cfg.logLevel, cfg.logFormat)
 
 	// XXX(fabxc): Kubernetes does background logging which we can only customize by modifying
 	// a global variable.
@@ -217,36 +428,110 @@ func main() {
 	level.Info(logger).Log("build_context", version.BuildContext())
 	level.Info(logger).Log("host_details", Uname())
 
+	if cfg.tracingExporter == "jaeger" {
+		tracer, closer, err := tracing.New("prometheus", cfg.tracing, log.With(logger, "component", "tracing"), prometheus.DefaultRegisterer)
+		if err != nil {
+			level.Error(logger).Log("msg", "Error creating tracer", "err", err)
+			os.Exit(2)
+		}
+		defer closer.Close()
+		opentracing.SetGlobalTracer(tracer)
+	}
+
 	var (
-		localStorage  = &tsdb.ReadyStorage{}
-		remoteStorage = remote.NewStorage(log.With(logger, "component", "remote"), localStorage.StartTime)
-		fanoutStorage = storage.NewFanout(logger, localStorage, remoteStorage)
+		localStorage    = &tsdb.ReadyStorage{}
+		exemplarStorage = storage.NewCircularExemplarStorage(cfg.maxExemplars, prometheus.DefaultRegisterer)
+		remoteStorage   = remote.NewStorage(log.With(logger, "component", "remote"), localStorage.StartTime, cfg.localStoragePath)
+		fanoutStorage   = storage.NewFanout(log.With(logger, "component", "fanout"), localStorage, remoteStorage)
 	)
 
-	cfg.queryEngine.Logger = log.With(logger, "component", "query engine")
+	if cfg.agent {
+		level.Info(logger).Log("msg", "Running in agent mode, querying, rule evaluation and alerting are disabled")
+	} else {
+		cfg.queryEngine.Logger = log.With(logger, "component", "query engine")
+		cfg.queryEngine.ActiveQueryTracker, err = promql.NewActiveQueryTracker(
+			cfg.localStoragePath,
+			cfg.queryEngine.MaxConcurrentQueries,
+			log.With(logger, "component", "active query tracker"),
+		)
+		if err != nil {
+			level.Error(logger).Log("msg", "Opening active query log failed", "err", err)
+			os.Exit(1)
+		}
+	}
+	// Remote write reads the samples from the WAL right after they were committed.
+	localStorage.AddCommitHook(remoteStorage.Notify)
+
+	if len(cfg.scrapeDurationBuckets) > 0 {
+		retrieval.SetScrapeDurationBuckets(cfg.scrapeDurationBuckets)
+	}
+	if len(cfg.ruleDurationBuckets) > 0 {
+		rules.SetDurationBuckets(cfg.ruleDurationBuckets)
+	}
+
 	var (
-		notifier       = notifier.New(&cfg.notifier, log.With(logger, "component", "notifier"))
-		targetManager  = retrieval.NewTargetManager(fanoutStorage, log.With(logger, "component", "target manager"))
-		queryEngine    = promql.NewEngine(fanoutStorage, &cfg.queryEngine)
-		ctx, cancelCtx = context.WithCancel(context.Background())
+		notifier                      = notifier.New(&cfg.notifier, log.With(logger, "component", "notifier"))
+		ctxDiscovery, cancelDiscovery = context.WithCancel(context.Background())
+		discoveryManager              = discovery.NewManager(ctxDiscovery, log.With(logger, "component", "discovery manager scrape"))
+		ctxNotify, cancelNotify       = context.WithCancel(context.Background())
+		discoveryManagerNotify        = discovery.NewManager(ctxNotify, log.With(logger, "component", "discovery manager notify"))
+		scrapeManager                 = retrieval.NewScrapeManager(log.With(logger, "component", "scrape manager"), fanoutStorage)
+		ctx, cancelCtx                = context.WithCancel(context.Background())
 	)
 
-	ruleManager := rules.NewManager(&rules.ManagerOptions{
-		Appendable:  fanoutStorage,
-		Notifier:    notifier,
-		QueryEngine: queryEngine,
-		Context:     ctx,
-		ExternalURL: cfg.web.ExternalURL,
-		Logger:      log.With(logger, "component", "rule manager"),
-	})
+	remoteStorage.SetMetadataSource(scrapeManager)
+
+	if cfg.scrapeDumpDir != "" {
+		dumper, err := retrieval.NewScrapeDumper(cfg.scrapeDumpDir, int64(cfg.scrapeDumpMaxFileSize), cfg.scrapeDumpMaxFiles, log.With(logger, "component", "scrape dumper"))
+		if err != nil {
+			level.Error(logger).Log("msg", "Creating scrape dumper failed", "err", err)
+			os.Exit(1)
+		}
+		defer dumper.Close()
+		level.Warn(logger).Log("msg", "Recording all scrapes for debugging", "dir", cfg.scrapeDumpDir)
+		scrapeManager.SetScrapeDumper(dumper)
+	}
+
+	if shard.Count > 0 {
+		level.Info(logger).Log("msg", "Only scraping the targets of one shard", "shard", shard)
+		scrapeManager.SetShard(shard)
+	}
+
+	// There is no query engine and rule manager in agent mode.
+	var (
+		queryEngine *promql.Engine
+		ruleManager *rules.Manager
+	)
+	if !cfg.agent {
+		queryEngine = promql.NewEngine(fanoutStorage, &cfg.queryEngine)
+		ruleManager = rules.NewManager(&rules.ManagerOptions{
+			Appendable:         fanoutStorage,
+			Queryable:          fanoutStorage,
+			Notifier:           notifier,
+			QueryEngine:        queryEngine,
+			Context:            ctx,
+			ExternalURL:        cfg.web.ExternalURL,
+			Logger:             log.With(logger, "component", "rule manager"),
+			OutageTolerance:    time.Duration(cfg.outageTolerance),
+			ForGracePeriod:     time.Duration(cfg.forGracePeriod),
+			MaxConcurrentEvals: cfg.maxRuleEvals,
+			ManagedDir:         cfg.managedRulesDir,
+		})
+	}
 
 	cfg.web.Context = ctx
 	cfg.web.TSDB = localStorage.Get
+	cfg.web.TSDBHealth = localStorage.Health
+	cfg.web.NotReadyReason = localStorage.NotReadyReason
 	cfg.web.Storage = fanoutStorage
+	cfg.web.ExemplarStorage = exemplarStorage
 	cfg.web.QueryEngine = queryEngine
-	cfg.web.TargetManager = targetManager
+	cfg.web.ScrapeManager = scrapeManager
+	cfg.web.DiscoveryManagerScrape = discoveryManager
+	cfg.web.DiscoveryManagerNotify = discoveryManagerNotify
 	cfg.web.RuleManager = ruleManager
 	cfg.web.Notifier = notifier
+	cfg.web.Agent = cfg.agent
 
 	cfg.web.Version = &web.PrometheusVersion{
 		Version:   version.Version,
@@ -271,14 +556,30 @@ func main() {
 
 	reloadables := []Reloadable{
 		remoteStorage,
-		targetManager,
-		ruleManager,
-		webHandler,
-		notifier,
+		scrapeManager,
+		&scrapeDiscoveryReloader{manager: discoveryManager},
+	}
+	if !cfg.agent {
+		reloadables = append(reloadables,
+			ruleManager,
+			notifier,
+			&notifierDiscoveryReloader{manager: discoveryManagerNotify},
+			&queryLogReloader{engine: queryEngine},
+		)
+	}
+	// The web handler clears the query cache, so it is reloaded after the
+	// rules.
+	reloadables = append(reloadables, webHandler)
+	reloader := &configReloader{
+		filename:    cfg.configFile,
+		logger:      logger,
+		reloadables: reloadables,
+		agent:       cfg.agent,
 	}
 
 	prometheus.MustRegister(configSuccess)
 	prometheus.MustRegister(configSuccessTime)
+	prometheus.MustRegister(localStorage)
 
 	// Start all components while we wait for TSDB to open but only load
 	// initial config and mark ourselves as ready after it completed.
@@ -327,16 +628,15 @@ func main() {
 				for {
 					select {
 					case <-hup:
-						if err := reloadConfig(cfg.configFile, logger, reloadables...); err != nil {
+						if _, err := reloader.reload(); err != nil {
 							level.Error(logger).Log("msg", "Error reloading config", "err", err)
 						}
 					case rc := <-webHandler.Reload():
-						if err := reloadConfig(cfg.configFile, logger, reloadables...); err != nil {
+						changes, err := reloader.reload()
+						if err != nil {
 							level.Error(logger).Log("msg", "Error reloading config", "err", err)
-							rc <- err
-						} else {
-							rc <- nil
 						}
+						rc <- web.ReloadResult{Changes: changes, Err: err}
 					case <-cancel:
 						return nil
 					}
@@ -360,7 +660,7 @@ func main() {
 					return nil
 				}
 
-				if err := reloadConfig(cfg.configFile, logger, reloadables...); err != nil {
+				if _, err := reloader.reload(); err != nil {
 					return fmt.Errorf("Error loading config %s", err)
 				}
 
@@ -376,13 +676,19 @@ func main() {
 		)
 	}
 	{
-		cancel := make(chan struct{})
+		ctxRetention, cancelRetention := context.WithCancel(context.Background())
+		retentionStopped := make(chan struct{})
 		g.Add(
 			func() error {
+				defer close(retentionStopped)
+
 				level.Info(logger).Log("msg", "Starting TSDB ...")
+				if err := localStorage.StartWALReplay(cfg.localStoragePath); err != nil {
+					level.Warn(logger).Log("msg", "Cannot track the progress of the WAL replay", "err", err)
+				}
 				db, err := tsdb.Open(
 					cfg.localStoragePath,
-					log.With(logger, "component", "tsdb"),
+					localStorage.HealthLogger(log.With(logger, "component", "tsdb")),
 					prometheus.DefaultRegisterer,
 					&cfg.tsdb,
 				)
@@ -391,17 +697,49 @@ func main() {
 				}
 				level.Info(logger).Log("msg", "TSDB started")
 
+				var ooo *tsdb.OutOfOrderHead
+				if cfg.tsdb.OutOfOrderTimeWindow > 0 {
+					ooo, err = tsdb.NewOutOfOrderHead(
+						db,
+						&cfg.tsdb,
+						log.With(logger, "component", "tsdb"),
+						prometheus.DefaultRegisterer,
+					)
+					if err != nil {
+						db.Close()
+						return fmt.Errorf("Opening out of order storage failed %s", err)
+					}
+				}
+
 				startTimeMargin := int64(2 * time.Duration(cfg.tsdb.MinBlockDuration).Seconds() * 1000)
-				localStorage.Set(db, startTimeMargin)
+				localStorage.Set(db, startTimeMargin, exemplarStorage, ooo)
+
+				retention := tsdb.NewSizeRetention(
+					db,
+					int64(cfg.tsdb.MaxBytes),
+					log.With(logger, "component", "tsdb"),
+					prometheus.DefaultRegisterer,
+				)
+
 				close(dbOpen)
-				<-cancel
+				if ooo != nil {
+					oooStopped := make(chan struct{})
+					go func() {
+						defer close(oooStopped)
+						ooo.Run(ctxRetention, time.Minute)
+					}()
+					defer func() { <-oooStopped }()
+				}
+				retention.Run(ctxRetention, time.Minute)
 				return nil
 			},
 			func(err error) {
+				// Stop deleting blocks before the storage is closed.
+				cancelRetention()
+				<-retentionStopped
 				if err := fanoutStorage.Close(); err != nil {
 					level.Error(logger).Log("msg", "Error stopping storage", "err", err)
 				}
-				close(cancel)
 			},
 		)
 	}
@@ -421,7 +759,7 @@ func main() {
 			},
 		)
 	}
-	{
+	if !cfg.agent {
 		// TODO(krasi) refactor ruleManager.Run() to be blocking to avoid using an extra blocking channel.
 		cancel := make(chan struct{})
 		g.Add(
@@ -436,12 +774,12 @@ func main() {
 			},
 		)
 	}
-	{
+	if !cfg.agent {
 		// Calling notifier.Stop() before ruleManager.Stop() will cause a panic if the ruleManager isn't running,
 		// so keep this interrupt after the ruleManager.Stop().
 		g.Add(
 			func() error {
-				notifier.Run()
+				notifier.Run(discoveryManagerNotify.SyncCh())
 				return nil
 			},
 			func(err error) {
@@ -450,17 +788,51 @@ func main() {
 		)
 	}
 	{
-		// TODO(krasi) refactor targetManager.Run() to be blocking to avoid using an extra blocking channel.
-		cancel := make(chan struct{})
 		g.Add(
 			func() error {
-				targetManager.Run()
-				<-cancel
+				err := discoveryManager.Run()
+				level.Info(logger).Log("msg", "Discovery manager stopped")
+				return err
+			},
+			func(err error) {
+				level.Info(logger).Log("msg", "Stopping discovery manager...")
+				cancelDiscovery()
+			},
+		)
+	}
+	if !cfg.agent {
+		g.Add(
+			func() error {
+				err := discoveryManagerNotify.Run()
+				level.Info(logger).Log("msg", "Notify discovery manager stopped")
+				return err
+			},
+			func(err error) {
+				level.Info(logger).Log("msg", "Stopping notify discovery manager...")
+				cancelNotify()
+			},
+		)
+	}
+	{
+		g.Add(
+			func() error {
+				return scrapeManager.Run(discoveryManager.SyncCh())
+			},
+			func(err error) {
+				scrapeManager.Stop()
+			},
+		)
+	}
+	if cfg.watchdog.Dir != "" {
+		wd := watchdog.New(cfg.watchdog, log.With(logger, "component", "watchdog"), prometheus.DefaultRegisterer)
+		ctx, cancel := context.WithCancel(context.Background())
+		g.Add(
+			func() error {
+				wd.Run(ctx)
 				return nil
 			},
 			func(err error) {
-				targetManager.Stop()
-				close(cancel)
+				cancel()
 			},
 		)
 	}
@@ -476,8 +848,75 @@ type Reloadable interface {
 	ApplyConfig(*config.Config) error
 }
 
-func reloadConfig(filename string, logger log.Logger, rls ...Reloadable) (err error) {
-	level.Info(logger).Log("msg", "Loading configuration file", "filename", filename)
+// scrapeDiscoveryReloader applies the service discovery configurations of all
+// scrape jobs to the discovery manager.
+type scrapeDiscoveryReloader struct {
+	manager *discovery.Manager
+}
+
+// ApplyConfig implements Reloadable.
+func (r *scrapeDiscoveryReloader) ApplyConfig(conf *config.Config) error {
+	c := make(map[string]config.ServiceDiscoveryConfig, len(conf.ScrapeConfigs))
+	for _, scfg := range conf.ScrapeConfigs {
+		c[scfg.JobName] = scfg.ServiceDiscoveryConfig
+	}
+	return r.manager.ApplyConfig(c)
+}
+
+// notifierDiscoveryReloader applies the service discovery configurations of
+// all Alertmanager sets to the discovery manager of the notifier.
+type notifierDiscoveryReloader struct {
+	manager *discovery.Manager
+}
+
+// ApplyConfig implements Reloadable.
+func (r *notifierDiscoveryReloader) ApplyConfig(conf *config.Config) error {
+	return r.manager.ApplyConfig(notifier.DiscoveryConfigs(conf))
+}
+
+// queryLogReloader opens the query log file configured in the global config
+// for the query engine. The file is reopened on every reload, so that it can
+// be rotated.
+type queryLogReloader struct {
+	engine *promql.Engine
+}
+
+// ApplyConfig implements Reloadable.
+func (r *queryLogReloader) ApplyConfig(conf *config.Config) error {
+	filename := conf.GlobalConfig.QueryLogFile
+	if filename == "" {
+		r.engine.SetQueryLogger(nil)
+		return nil
+	}
+	l, err := logging.NewJSONFileLogger(filename)
+	if err != nil {
+		return fmt.Errorf("error opening query log file %s: %s", filename, err)
+	}
+	r.engine.SetQueryLogger(l)
+	return nil
+}
+
+// configReloader loads the configuration file and applies it to the
+// reloadables. It remembers the configuration applied last to report which
+// sections changed. Subsystems only restart the parts of them whose
+// configuration changed.
+type configReloader struct {
+	filename    string
+	logger      log.Logger
+	reloadables []Reloadable
+	// In agent mode configurations with rules or alerting are rejected.
+	agent bool
+
+	// The configuration that was applied last by all reloadables.
+	last *config.Config
+}
+
+// reload applies the configuration file and returns the sections that
+// changed since the last successful reload. The changes are also returned if
+// only some of the reloadables failed to apply the configuration, as the
+// others did. They are returned again until all reloadables applied them.
+func (r *configReloader) reload() (changes []config.Change, err error) {
+	level.Info(r.logger).Log("msg", "Loading configuration file", "filename", r.filename)
 
 	defer func() {
 		if err == nil {
@@ -488,20 +927,46 @@ func reloadConfig(filename string, logger log.Logger, rls ...Reloadable) (err er
 		}
 	}()
 
-	conf, err := config.LoadFile(filename)
+	conf, err := config.LoadFile(r.filename)
 	if err != nil {
-		return fmt.Errorf("couldn't load configuration (--config.file=%s): %v", filename, err)
+		return nil, fmt.Errorf("couldn't load configuration (--config.file=%s): %v", r.filename, err)
+	}
+	if r.agent {
+		if err := checkAgentConfig(conf); err != nil {
+			return nil, fmt.Errorf("invalid configuration for agent mode (--config.file=%s): %v", r.filename, err)
+		}
 	}
 
 	failed := false
-	for _, rl := range rls {
+	for _, rl := range r.reloadables {
 		if err := rl.ApplyConfig(conf); err != nil {
-			level.Error(logger).Log("msg", "Failed to apply configuration", "err", err)
+			level.Error(r.logger).Log("msg", "Failed to apply configuration", "err", err)
 			failed = true
 		}
 	}
+
+	changes = config.Diff(r.last, conf)
+	// Everything changed with the initial configuration.
+	if r.last != nil {
+		for _, c := range changes {
+			level.Info(r.logger).Log("msg", "Configuration changed", "section", c.Section, "name", c.Name, "kind", c.Kind)
+		}
+	}
 	if failed {
-		return fmt.Errorf("one or more errors occurred while applying the new configuration (--config.file=%s)", filename)
+		return changes, fmt.Errorf("one or more errors occurred while applying the new configuration (--config.file=%s)", r.filename)
+	}
+	r.last = conf
+	return changes, nil
+}
+
+// checkAgentConfig returns an error if the configuration sets up anything
+// that does not run in agent mode.
+func checkAgentConfig(conf *config.Config) error {
+	if len(conf.RuleFiles) > 0 {
+		return fmt.Errorf("rule_files are not allowed in agent mode")
+	}
+	if len(conf.AlertingConfig.AlertmanagerConfigs) > 0 || len(conf.AlertingConfig.AlertRelabelConfigs) > 0 || len(conf.AlertingConfig.Replica.Labels) > 0 {
+		return fmt.Errorf("alerting is not allowed in agent mode")
 	}
 	return nil
 }
@@ -511,6 +976,12 @@ func startsOrEndsWithQuote(s string) bool {
 		strings.HasSuffix(s, "\"") || strings.HasSuffix(s, "'")
 }
 
+// compileCORSRegexString compiles the given CORS origin pattern, anchoring
+// it so that it has to match the whole origin.
+func compileCORSRegexString(s string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + s + ")$")
+}
+
 // computeExternalURL computes a sanitized external URL from a raw input. It infers unset
 // URL parts from the OS and the given listen address.
 func computeExternalURL(u, listenAddr string) (*url.URL, error) {
@@ -519,11 +990,16 @@ func computeExternalURL(u, listenAddr string) (*url.URL, error) {
 		if err != nil {
 			return nil, err
 		}
-		_, port, err := net.SplitHostPort(listenAddr)
-		if err != nil {
-			return nil, err
+		if strings.Contains(listenAddr, "://") {
+			// Unix and systemd socket addresses have no port to infer.
+			u = fmt.Sprintf("http://%s/", hostname)
+		} else {
+			_, port, err := net.SplitHostPort(listenAddr)
+			if err != nil {
+				return nil, err
+			}
+			u = fmt.Sprintf("http://%s:%s/", hostname, port)
 		}
-		u = fmt.Sprintf("http://%s:%s/", hostname, port)
 	}
 
 	if startsOrEndsWithQuote(u) {
@@ -14,8 +14,15 @@
 package main
 
 import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/go-kit/kit/log"
+
+	"github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/util/testutil"
 )
 
@@ -66,4 +73,81 @@ func TestComputeExternalURL(t *testing.T) {
 			testutil.NotOk(t, err)
 		}
 	}
+
+	// Socket listen addresses carry no port to infer.
+	u, err := computeExternalURL("", "unix:///run/prometheus.sock")
+	testutil.Ok(t, err)
+	testutil.Equals(t, "", u.Port())
+}
+
+func TestCheckAgentConfig(t *testing.T) {
+	testutil.Ok(t, checkAgentConfig(&config.Config{
+		ScrapeConfigs: []*config.ScrapeConfig{{JobName: "node"}},
+	}))
+	testutil.NotOk(t, checkAgentConfig(&config.Config{
+		RuleFiles: []string{"rules.yml"},
+	}))
+	testutil.NotOk(t, checkAgentConfig(&config.Config{
+		AlertingConfig: config.AlertingConfig{
+			AlertmanagerConfigs: []*config.AlertmanagerConfig{{}},
+		},
+	}))
+}
+
+type reloadableFunc func(*config.Config) error
+
+func (f reloadableFunc) ApplyConfig(c *config.Config) error {
+	return f(c)
+}
+
+func TestConfigReloaderPartialFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config_reloader")
+	testutil.Ok(t, err)
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "prometheus.yml")
+	writeConfig := func(job string) {
+		testutil.Ok(t, ioutil.WriteFile(filename, []byte("scrape_configs:\n- job_name: "+job+"\n"), 0644))
+	}
+
+	var fail bool
+	r := &configReloader{
+		filename: filename,
+		logger:   log.NewNopLogger(),
+		reloadables: []Reloadable{
+			reloadableFunc(func(*config.Config) error { return nil }),
+			reloadableFunc(func(*config.Config) error {
+				if fail {
+					return errors.New("failed")
+				}
+				return nil
+			}),
+		},
+	}
+
+	writeConfig("node")
+	_, err = r.reload()
+	testutil.Ok(t, err)
+
+	// The changes applied by the other reloadables are reported.
+	fail = true
+	writeConfig("mysql")
+	changes, err := r.reload()
+	testutil.NotOk(t, err)
+	testutil.Assert(t, len(changes) > 0, "expected changes on partial failure")
+
+	// They are reported again until all reloadables applied them.
+	changes2, err := r.reload()
+	testutil.NotOk(t, err)
+	testutil.Equals(t, changes, changes2)
+
+	fail = false
+	changes2, err = r.reload()
+	testutil.Ok(t, err)
+	testutil.Equals(t, changes, changes2)
+
+	// They are not reported again after a successful reload.
+	changes, err = r.reload()
+	testutil.Ok(t, err)
+	testutil.Equals(t, 0, len(changes))
 }
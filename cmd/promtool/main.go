@@ -14,6 +14,9 @@
 package main
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -36,6 +39,8 @@ func main() {
 	app.Version(version.Print("promtool"))
 	app.HelpFlag.Short('h')
 
+	enableNegativeOffset := app.Flag("query.enable-negative-offset", "Allow negative offsets in expressions, as the Prometheus flag of the same name.").Bool()
+
 	checkCmd := app.Command("check", "Check the resources for validity.")
 
 	checkConfigCmd := checkCmd.Command("config", "Check if the config files are valid or not.")
@@ -56,7 +61,50 @@ func main() {
 	updateRulesCmd := updateCmd.Command("rules", "Update rules from the 1.x to 2.x format.")
 	ruleFilesUp := updateRulesCmd.Arg("rule-files", "The rule files to update.").Required().ExistingFiles()
 
-	switch kingpin.MustParse(app.Parse(os.Args[1:])) {
+	testCmd := app.Command("test", "Unit testing.")
+	testRulesCmd := testCmd.Command("rules", "Unit tests for rules.")
+	testRulesFiles := testRulesCmd.Arg(
+		"test-rule-file",
+		"The unit test file.",
+	).Required().ExistingFiles()
+
+	promqlCmd := app.Command("promql", "Format and lint PromQL expressions.")
+	promqlFormatCmd := promqlCmd.Command("format", "Print expressions in the canonical style, splitting long ones across lines.")
+	promqlFormatArgs := promqlFormatCmd.Arg(
+		"expressions",
+		"The expressions, or files containing one each, to format.",
+	).Required().Strings()
+	promqlLintCmd := promqlCmd.Command("lint", "Report deprecated functions and anti-patterns in expressions.")
+	promqlLintArgs := promqlLintCmd.Arg(
+		"expressions",
+		"The expressions, or files containing one each, to lint.",
+	).Required().Strings()
+
+	tsdbCmd := app.Command("tsdb", "Run tsdb commands.")
+	createBlocksCmd := tsdbCmd.Command("create-blocks-from", "Create blocks from historical data.")
+	openMetricsCmd := createBlocksCmd.Command("openmetrics", "Create blocks from samples in the OpenMetrics text format. All samples must have a timestamp.")
+	openMetricsFile := openMetricsCmd.Arg("input-file", "The OpenMetrics file to read samples from.").Required().ExistingFile()
+	openMetricsOutputDir := openMetricsCmd.Arg("output-dir", "The directory to write the blocks to.").Default("data/").String()
+	openMetricsBlockDuration := openMetricsCmd.Flag("block-duration", "The time range covered by each block.").Default("2h").Duration()
+
+	listCmd := tsdbCmd.Command("list", "List the blocks of a database.").Alias("ls")
+	listHumanReadable := listCmd.Flag("human-readable", "Print times in a human-readable format.").Short('r').Bool()
+	listPath := listCmd.Arg("db path", "The database path.").Default("data/").String()
+
+	analyzeCmd := tsdbCmd.Command("analyze", "Analyze the cardinality and churn of a block.")
+	analyzePath := analyzeCmd.Arg("db path", "The database path.").Default("data/").String()
+	analyzeBlockID := analyzeCmd.Arg("block id", "The block to analyze. Defaults to the most recent block.").String()
+	analyzeLimit := analyzeCmd.Flag("limit", "How many items to show in each list.").Default("20").Int()
+
+	replayScrapesCmd := tsdbCmd.Command("replay-scrapes", "Append the samples of scrapes recorded with --scrape.dump-dir to a database to reproduce ingestion problems.")
+	replayScrapesDump := replayScrapesCmd.Arg("dump path", "The dump directory or a single file of it.").Required().ExistingFileOrDir()
+	replayScrapesPath := replayScrapesCmd.Arg("db path", "The database path.").Default("data/").String()
+	replayScrapesConfig := replayScrapesCmd.Flag("config.file", "Prometheus configuration file whose scrape configurations are applied to the scrapes of their jobs.").ExistingFile()
+
+	parsedCmd := kingpin.MustParse(app.Parse(os.Args[1:]))
+	promql.EnableNegativeOffset = *enableNegativeOffset
+
+	switch parsedCmd {
 	case checkConfigCmd.FullCommand():
 		os.Exit(CheckConfig(*configFiles...))
 
@@ -69,6 +117,27 @@ func main() {
 	case updateRulesCmd.FullCommand():
 		os.Exit(UpdateRules(*ruleFilesUp...))
 
+	case testRulesCmd.FullCommand():
+		os.Exit(RulesUnitTest(*testRulesFiles...))
+
+	case promqlFormatCmd.FullCommand():
+		os.Exit(FormatExpressions(*promqlFormatArgs...))
+
+	case promqlLintCmd.FullCommand():
+		os.Exit(LintExpressions(*promqlLintArgs...))
+
+	case openMetricsCmd.FullCommand():
+		os.Exit(CreateBlocksFromOpenMetrics(*openMetricsFile, *openMetricsOutputDir, *openMetricsBlockDuration))
+
+	case listCmd.FullCommand():
+		os.Exit(ListBlocks(*listPath, *listHumanReadable))
+
+	case analyzeCmd.FullCommand():
+		os.Exit(AnalyzeBlock(*analyzePath, *analyzeBlockID, *analyzeLimit))
+
+	case replayScrapesCmd.FullCommand():
+		os.Exit(ReplayScrapes(*replayScrapesDump, *replayScrapesPath, *replayScrapesConfig))
+
 	}
 
 }
@@ -78,9 +147,12 @@ func CheckConfig(files ...string) int {
 	failed := false
 
 	for _, f := range files {
-		ruleFiles, err := checkConfig(f)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "  FAILED:", err)
+		ruleFiles, errs := checkConfig(f)
+		if errs != nil {
+			fmt.Fprintln(os.Stderr, "  FAILED:")
+			for _, e := range errs {
+				fmt.Fprintln(os.Stderr, "   ", e)
+			}
 			failed = true
 		} else {
 			fmt.Printf("  SUCCESS: %d rule files found\n", len(ruleFiles))
@@ -88,8 +160,11 @@ func CheckConfig(files ...string) int {
 		fmt.Println()
 
 		for _, rf := range ruleFiles {
-			if n, err := checkRules(rf); err != nil {
-				fmt.Fprintln(os.Stderr, "  FAILED:", err)
+			if n, errs := checkRules(rf); errs != nil {
+				fmt.Fprintln(os.Stderr, "  FAILED:")
+				for _, e := range errs {
+					fmt.Fprintln(os.Stderr, "   ", e)
+				}
 				failed = true
 			} else {
 				fmt.Printf("  SUCCESS: %d rules found\n", n)
@@ -112,64 +187,172 @@ func checkFileExists(fn string) error {
 	return err
 }
 
-func checkConfig(filename string) ([]string, error) {
+// checkConfig loads the configuration file and checks the files it
+// references. It returns the rule files found along with all
+// problems encountered rather than stopping at the first one.
+func checkConfig(filename string) ([]string, []error) {
 	fmt.Println("Checking", filename)
 
 	cfg, err := config.LoadFile(filename)
 	if err != nil {
-		return nil, err
+		return nil, []error{err}
 	}
 
-	var ruleFiles []string
+	var (
+		ruleFiles []string
+		errs      []error
+	)
 	for _, rf := range cfg.RuleFiles {
 		rfs, err := filepath.Glob(rf)
 		if err != nil {
-			return nil, err
+			errs = append(errs, fmt.Errorf("error globbing rule files %q: %s", rf, err))
+			continue
 		}
 		// If an explicit file was given, error if it is not accessible.
 		if !strings.Contains(rf, "*") {
 			if len(rfs) == 0 {
-				return nil, fmt.Errorf("%q does not point to an existing file", rf)
+				errs = append(errs, fmt.Errorf("%q does not point to an existing file", rf))
+				continue
 			}
 			if err := checkFileExists(rfs[0]); err != nil {
-				return nil, fmt.Errorf("error checking rule file %q: %s", rfs[0], err)
+				errs = append(errs, fmt.Errorf("error checking rule file %q: %s", rfs[0], err))
+				continue
 			}
 		}
 		ruleFiles = append(ruleFiles, rfs...)
 	}
 
 	for _, scfg := range cfg.ScrapeConfigs {
-		if err := checkFileExists(scfg.HTTPClientConfig.BearerTokenFile); err != nil {
-			return nil, fmt.Errorf("error checking bearer token file %q: %s", scfg.HTTPClientConfig.BearerTokenFile, err)
-		}
+		ctx := fmt.Sprintf("scrape job %q", scfg.JobName)
 
-		if err := checkTLSConfig(scfg.HTTPClientConfig.TLSConfig); err != nil {
-			return nil, err
-		}
+		errs = append(errs, withContext(ctx, checkHTTPClientConfig(scfg.HTTPClientConfig))...)
+		errs = append(errs, withContext(ctx, checkServiceDiscoveryConfig(ctx, scfg.ServiceDiscoveryConfig))...)
+	}
 
-		for _, kd := range scfg.ServiceDiscoveryConfig.KubernetesSDConfigs {
-			if err := checkTLSConfig(kd.TLSConfig); err != nil {
-				return nil, err
-			}
+	for i, amcfg := range cfg.AlertingConfig.AlertmanagerConfigs {
+		ctx := fmt.Sprintf("alertmanager config %d", i)
+
+		errs = append(errs, withContext(ctx, checkHTTPClientConfig(amcfg.HTTPClientConfig))...)
+		errs = append(errs, withContext(ctx, checkServiceDiscoveryConfig(ctx, amcfg.ServiceDiscoveryConfig))...)
+	}
+
+	for _, rwcfg := range cfg.RemoteWriteConfigs {
+		ctx := fmt.Sprintf("remote write %q", rwcfg.URL)
+
+		errs = append(errs, withContext(ctx, checkHTTPClientConfig(rwcfg.HTTPClientConfig))...)
+	}
+	for _, rrcfg := range cfg.RemoteReadConfigs {
+		ctx := fmt.Sprintf("remote read %q", rrcfg.URL)
+
+		errs = append(errs, withContext(ctx, checkHTTPClientConfig(rrcfg.HTTPClientConfig))...)
+	}
+
+	return ruleFiles, errs
+}
+
+// withContext prefixes each of the errors with the part of the configuration
+// it was found in.
+func withContext(ctx string, errs []error) []error {
+	for i, err := range errs {
+		errs[i] = fmt.Errorf("%s: %s", ctx, err)
+	}
+	return errs
+}
+
+func checkHTTPClientConfig(cfg config.HTTPClientConfig) []error {
+	var errs []error
+	if err := checkFileExists(cfg.BearerTokenFile); err != nil {
+		errs = append(errs, fmt.Errorf("error checking bearer token file %q: %s", cfg.BearerTokenFile, err))
+	}
+	if err := checkTLSConfig(cfg.TLSConfig); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// checkServiceDiscoveryConfig checks the files referenced by the service
+// discovery configuration of the given part of the configuration.
+func checkServiceDiscoveryConfig(ctx string, cfg config.ServiceDiscoveryConfig) []error {
+	var errs []error
+	for _, c := range cfg.ConsulSDConfigs {
+		if err := checkTLSConfig(c.TLSConfig); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, c := range cfg.MarathonSDConfigs {
+		if err := checkFileExists(c.BearerTokenFile); err != nil {
+			errs = append(errs, fmt.Errorf("error checking bearer token file %q: %s", c.BearerTokenFile, err))
+		}
+		if err := checkTLSConfig(c.TLSConfig); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, c := range cfg.KubernetesSDConfigs {
+		if err := checkFileExists(c.BearerTokenFile); err != nil {
+			errs = append(errs, fmt.Errorf("error checking bearer token file %q: %s", c.BearerTokenFile, err))
+		}
+		if err := checkTLSConfig(c.TLSConfig); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, c := range cfg.TritonSDConfigs {
+		if err := checkTLSConfig(c.TLSConfig); err != nil {
+			errs = append(errs, err)
 		}
+	}
 
-		for _, filesd := range scfg.ServiceDiscoveryConfig.FileSDConfigs {
-			for _, file := range filesd.Files {
-				files, err := filepath.Glob(file)
-				if err != nil {
-					return nil, err
-				}
-				if len(files) != 0 {
-					// There was at least one match for the glob and we can assume checkFileExists
-					// for all matches would pass, we can continue the loop.
-					continue
+	for _, filesd := range cfg.FileSDConfigs {
+		for _, file := range filesd.Files {
+			files, err := filepath.Glob(file)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("error globbing file_sd files %q: %s", file, err))
+				continue
+			}
+			if len(files) == 0 {
+				// File service discovery tolerates missing files, so this is
+				// not an error.
+				fmt.Printf("  WARNING: file %q for file_sd in %s does not exist\n", file, ctx)
+				continue
+			}
+			for _, f := range files {
+				if err := checkSDFile(f); err != nil {
+					errs = append(errs, fmt.Errorf("error checking file_sd file %q: %s", f, err))
 				}
-				fmt.Printf("  WARNING: file %q for file_sd in scrape job %q does not exist\n", file, scfg.JobName)
 			}
 		}
 	}
+	return errs
+}
 
-	return ruleFiles, nil
+// checkSDFile parses a file_sd target file the same way file service
+// discovery does.
+func checkSDFile(filename string) error {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	var targetGroups []*config.TargetGroup
+
+	switch ext := filepath.Ext(filename); strings.ToLower(ext) {
+	case ".json":
+		if err := json.Unmarshal(content, &targetGroups); err != nil {
+			return err
+		}
+	case ".yml", ".yaml":
+		if err := yaml.Unmarshal(content, &targetGroups); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid file extension %q", ext)
+	}
+
+	for i, tg := range targetGroups {
+		if tg == nil {
+			return fmt.Errorf("nil target group item found (index %d)", i)
+		}
+	}
+	return nil
 }
 
 func checkTLSConfig(tlsConfig config.TLSConfig) error {
@@ -187,6 +370,21 @@ func checkTLSConfig(tlsConfig config.TLSConfig) error {
 		return fmt.Errorf("client key file %q specified without client cert file", tlsConfig.KeyFile)
 	}
 
+	if len(tlsConfig.CAFile) > 0 {
+		b, err := ioutil.ReadFile(tlsConfig.CAFile)
+		if err != nil {
+			return fmt.Errorf("error reading CA file %q: %s", tlsConfig.CAFile, err)
+		}
+		if !x509.NewCertPool().AppendCertsFromPEM(b) {
+			return fmt.Errorf("no certificates found in CA file %q", tlsConfig.CAFile)
+		}
+	}
+	if len(tlsConfig.CertFile) > 0 {
+		if _, err := tls.LoadX509KeyPair(tlsConfig.CertFile, tlsConfig.KeyFile); err != nil {
+			return fmt.Errorf("error loading client cert file %q and key file %q: %s", tlsConfig.CertFile, tlsConfig.KeyFile, err)
+		}
+	}
+
 	return nil
 }
 
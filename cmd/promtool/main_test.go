@@ -0,0 +1,73 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckConfig(t *testing.T) {
+	ruleFiles, errs := checkConfig("testdata/config.good.yml")
+	if errs != nil {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(ruleFiles) != 1 || ruleFiles[0] != "testdata/rules.yml" {
+		t.Fatalf("unexpected rule files: %v", ruleFiles)
+	}
+
+	ruleFiles, errs = checkConfig("testdata/config.bad.yml")
+	if len(ruleFiles) != 1 {
+		t.Fatalf("unexpected rule files: %v", ruleFiles)
+	}
+	expected := []string{
+		`"testdata/does_not_exist.yml" does not point to an existing file`,
+		`scrape job "prometheus": error checking bearer token file "testdata/does_not_exist.token"`,
+		`scrape job "prometheus": no certificates found in CA file "testdata/rules.yml"`,
+		`scrape job "prometheus": error checking file_sd file "testdata/sd.bad.yml": nil target group item found (index 1)`,
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors, got %d: %v", len(expected), len(errs), errs)
+	}
+	for i, exp := range expected {
+		if !strings.HasPrefix(errs[i].Error(), exp) {
+			t.Errorf("%d. expected error starting with %q, got %q", i, exp, errs[i])
+		}
+	}
+}
+
+func TestCheckSDFile(t *testing.T) {
+	cases := []struct {
+		file string
+		err  string
+	}{
+		{file: "testdata/sd.good.json"},
+		{file: "testdata/sd.good.yml"},
+		{file: "testdata/sd.bad.yml", err: "nil target group item found (index 1)"},
+		{file: "testdata/rules.yml", err: "yaml: unmarshal errors"},
+		{file: "testdata/unittest.go", err: "no such file or directory"},
+	}
+	for i, c := range cases {
+		err := checkSDFile(c.file)
+		if c.err == "" {
+			if err != nil {
+				t.Errorf("%d. unexpected error for %s: %s", i, c.file, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%d. expected error containing %q for %s, got %v", i, c.err, c.file, err)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/promql"
+)
+
+// FormatExpressions prints PromQL expressions in the canonical style. Each
+// argument is either an expression or a file containing one.
+func FormatExpressions(args ...string) int {
+	failed := false
+
+	for _, arg := range args {
+		source, input, err := readExpression(arg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "  FAILED:", err)
+			failed = true
+			continue
+		}
+		expr, err := promql.ParseExpr(input)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  FAILED: %s: %s\n", source, err)
+			failed = true
+			continue
+		}
+		fmt.Println(promql.Pretty(expr))
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// LintExpressions reports deprecated functions and anti-patterns in PromQL
+// expressions. Each argument is either an expression or a file containing one.
+func LintExpressions(args ...string) int {
+	var failed, problems bool
+
+	for _, arg := range args {
+		source, input, err := readExpression(arg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "  FAILED:", err)
+			failed = true
+			continue
+		}
+		ps, err := lintExpression(input)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  FAILED: %s: %s\n", source, err)
+			failed = true
+		}
+		for _, p := range ps {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", source, p)
+			problems = true
+		}
+	}
+	switch {
+	case failed:
+		return 1
+	case problems:
+		return 3
+	}
+	return 0
+}
+
+// readExpression returns the expression in the argument, or in the file it
+// names, along with a description of where it is from.
+func readExpression(arg string) (source, input string, err error) {
+	fi, err := os.Stat(arg)
+	if err != nil || fi.IsDir() {
+		return "expression", arg, nil
+	}
+	b, err := ioutil.ReadFile(arg)
+	if err != nil {
+		return "", "", err
+	}
+	return arg, string(b), nil
+}
+
+// removedFunctions are the functions and keywords of Prometheus 1.x that were
+// removed in 2.0, with what to use instead.
+var removedFunctions = map[string]string{
+	"count_scalar":       "use scalar(count(...)) instead",
+	"drop_common_labels": "aggregate by the labels to keep instead",
+	"keep_common":        "aggregate by the labels to keep instead",
+}
+
+var removedFunctionsRegexp = regexp.MustCompile(`\b(count_scalar|drop_common_labels|keep_common)\b`)
+
+// counterSuffixes are the suffixes of the names of counters and of the
+// counters exposed for summaries and histograms.
+var counterSuffixes = []string{"_total", "_count", "_sum", "_bucket"}
+
+func isCounterName(name string) bool {
+	for _, s := range counterSuffixes {
+		if strings.HasSuffix(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// lintExpression returns the problems found in the expression. It fails if
+// the expression does not parse, unless it is because of functions that were
+// removed, which are reported as problems.
+func lintExpression(input string) ([]string, error) {
+	expr, err := promql.ParseExpr(input)
+	if err != nil {
+		var problems []string
+		for _, name := range removedFunctionsRegexp.FindAllString(input, -1) {
+			problems = append(problems, fmt.Sprintf("%s was removed in Prometheus 2.0, %s", name, removedFunctions[name]))
+		}
+		if len(problems) > 0 {
+			return problems, nil
+		}
+		return nil, err
+	}
+
+	var problems []string
+	for _, w := range promql.Check(expr) {
+		problems = append(problems, w.Error())
+	}
+	promql.Inspect(expr, func(node promql.Node) bool {
+		switch n := node.(type) {
+		case *promql.Call:
+			problems = append(problems, lintCall(n)...)
+		case *promql.VectorSelector:
+			problems = append(problems, lintMatchers(n.LabelMatchers)...)
+		case *promql.MatrixSelector:
+			problems = append(problems, lintMatchers(n.LabelMatchers)...)
+		}
+		return true
+	})
+	return problems, nil
+}
+
+func lintCall(call *promql.Call) []string {
+	var problems []string
+	switch call.Func.Name {
+	case "rate", "irate", "increase", "resets":
+		if ms, ok := call.Args[0].(*promql.MatrixSelector); ok && ms.Name != "" && !isCounterName(ms.Name) {
+			problems = append(problems, fmt.Sprintf("%s() should only be applied to counters, %q is not named like one", call.Func.Name, ms.Name))
+		}
+	case "delta", "idelta", "deriv", "predict_linear", "holt_winters":
+		if ms, ok := call.Args[0].(*promql.MatrixSelector); ok && strings.HasSuffix(ms.Name, "_total") {
+			problems = append(problems, fmt.Sprintf("%s() should only be applied to gauges, use rate() or increase() for the counter %q", call.Func.Name, ms.Name))
+		}
+	case "histogram_quantile":
+		arg := call.Args[1]
+		for {
+			p, ok := arg.(*promql.ParenExpr)
+			if !ok {
+				break
+			}
+			arg = p.Expr
+		}
+		ae, ok := arg.(*promql.AggregateExpr)
+		if !ok {
+			break
+		}
+		hasLe := false
+		for _, l := range ae.Grouping {
+			if l == "le" {
+				hasLe = true
+			}
+		}
+		if hasLe == ae.Without {
+			problems = append(problems, "histogram_quantile() needs the le label, which the aggregation of its argument removes")
+		}
+	}
+	return problems
+}
+
+func lintMatchers(matchers []*labels.Matcher) []string {
+	var problems []string
+	for _, m := range matchers {
+		if m.Type != labels.MatchRegexp && m.Type != labels.MatchNotRegexp {
+			continue
+		}
+		if regexp.QuoteMeta(m.Value) != m.Value {
+			continue
+		}
+		op := "="
+		if m.Type == labels.MatchNotRegexp {
+			op = "!="
+		}
+		problems = append(problems, fmt.Sprintf("the regular expression of %s matches a literal value, use %s%s%q instead", m, m.Name, op, m.Value))
+	}
+	return problems
+}
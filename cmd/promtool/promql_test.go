@@ -0,0 +1,83 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintExpression(t *testing.T) {
+	cases := []struct {
+		input    string
+		problems []string
+		err      bool
+	}{
+		{
+			input: `sum by (job) (rate(http_requests_total{code=~"5.."}[5m]))`,
+		}, {
+			input: `histogram_quantile(0.9, sum by (le) (rate(request_duration_seconds_bucket[5m])))`,
+		}, {
+			input: `deriv(temperature_celsius[10m])`,
+		}, {
+			input:    `rate(temperature_celsius[5m])`,
+			problems: []string{`rate() should only be applied to counters, "temperature_celsius" is not named like one`},
+		}, {
+			input:    `delta(http_requests_total[5m])`,
+			problems: []string{`delta() should only be applied to gauges, use rate() or increase() for the counter "http_requests_total"`},
+		}, {
+			input:    `histogram_quantile(0.9, (sum by (job) (rate(request_duration_seconds_bucket[5m]))))`,
+			problems: []string{"histogram_quantile() needs the le label"},
+		}, {
+			input:    `histogram_quantile(0.9, sum without (le) (rate(request_duration_seconds_bucket[5m])))`,
+			problems: []string{"histogram_quantile() needs the le label"},
+		}, {
+			input:    `up{job=~"node",instance!~"a"}`,
+			problems: []string{`use job="node" instead`, `use instance!="a" instead`},
+		}, {
+			input:    `sum by (job) (up) / sum(up)`,
+			problems: []string{"never match"},
+		}, {
+			input:    `count_scalar(up)`,
+			problems: []string{"count_scalar was removed in Prometheus 2.0, use scalar(count(...)) instead"},
+		}, {
+			input:    `sum(up) by (job) keep_common`,
+			problems: []string{"keep_common was removed in Prometheus 2.0"},
+		}, {
+			input: `sum(up`,
+			err:   true,
+		},
+	}
+
+	for _, c := range cases {
+		problems, err := lintExpression(c.input)
+		if c.err {
+			if err == nil {
+				t.Fatalf("expected error for %q", c.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %s", c.input, err)
+		}
+		if len(problems) != len(c.problems) {
+			t.Fatalf("expected %d problems for %q, got %v", len(c.problems), c.input, problems)
+		}
+		for i, p := range problems {
+			if !strings.Contains(p, c.problems[i]) {
+				t.Fatalf("expected problem for %q to contain %q, got %q", c.input, c.problems[i], p)
+			}
+		}
+	}
+}
@@ -0,0 +1,353 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/tsdb"
+	"github.com/prometheus/tsdb/labels"
+
+	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/retrieval"
+	promtsdb "github.com/prometheus/prometheus/storage/tsdb"
+)
+
+// CreateBlocksFromOpenMetrics writes the samples of an OpenMetrics file into
+// TSDB blocks in the output directory.
+func CreateBlocksFromOpenMetrics(inputFile, outputDir string, blockDuration time.Duration) int {
+	fmt.Println("Creating blocks from", inputFile)
+
+	input, err := ioutil.ReadFile(inputFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "  FAILED:", err)
+		return 1
+	}
+	if err := os.MkdirAll(outputDir, 0777); err != nil {
+		fmt.Fprintln(os.Stderr, "  FAILED:", err)
+		return 1
+	}
+	if err := promtsdb.CreateBlocksFromOpenMetrics(input, outputDir, blockDuration, nil); err != nil {
+		fmt.Fprintln(os.Stderr, "  FAILED:", err)
+		return 1
+	}
+	fmt.Printf("  SUCCESS: blocks written to %s\n", outputDir)
+	return 0
+}
+
+// ListBlocks prints the blocks of the database directory.
+func ListBlocks(dbDir string, humanReadable bool) int {
+	db, err := promtsdb.OpenReadOnly(dbDir, nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "  FAILED:", err)
+		return 1
+	}
+	defer db.Close()
+
+	printBlocks(os.Stdout, db.Blocks(), humanReadable)
+	return 0
+}
+
+func printBlocks(w io.Writer, blocks []*tsdb.Block, humanReadable bool) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	defer tw.Flush()
+
+	fmt.Fprintln(tw, "BLOCK ULID\tMIN TIME\tMAX TIME\tNUM SAMPLES\tNUM CHUNKS\tNUM SERIES")
+	for _, b := range blocks {
+		meta := b.Meta()
+		fmt.Fprintf(tw,
+			"%v\t%v\t%v\t%v\t%v\t%v\n",
+			meta.ULID,
+			formatTime(meta.MinTime, humanReadable),
+			formatTime(meta.MaxTime, humanReadable),
+			meta.Stats.NumSamples,
+			meta.Stats.NumChunks,
+			meta.Stats.NumSeries,
+		)
+	}
+}
+
+func formatTime(t int64, humanReadable bool) string {
+	if humanReadable {
+		return time.Unix(0, t*int64(time.Millisecond)).UTC().Format(time.RFC3339)
+	}
+	return strconv.FormatInt(t, 10)
+}
+
+// AnalyzeBlock prints statistics about the cardinality of a block in the
+// database directory. If no block is given, the most recent one is analyzed.
+func AnalyzeBlock(dbDir, blockID string, limit int) int {
+	db, err := promtsdb.OpenReadOnly(dbDir, nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "  FAILED:", err)
+		return 1
+	}
+	defer db.Close()
+
+	blocks := db.Blocks()
+	if len(blocks) == 0 {
+		fmt.Fprintln(os.Stderr, "  FAILED: no blocks found in", dbDir)
+		return 1
+	}
+	block := blocks[len(blocks)-1]
+	if blockID != "" {
+		block = nil
+		for _, b := range blocks {
+			if b.Meta().ULID.String() == blockID {
+				block = b
+				break
+			}
+		}
+		if block == nil {
+			fmt.Fprintf(os.Stderr, "  FAILED: block %s not found in %s\n", blockID, dbDir)
+			return 1
+		}
+	}
+
+	if err := analyzeBlock(os.Stdout, block, limit); err != nil {
+		fmt.Fprintln(os.Stderr, "  FAILED:", err)
+		return 1
+	}
+	return 0
+}
+
+// analyzeBlock prints the cardinality of the label names and pairs of the
+// block and which of them are involved in series churn. A series churns if
+// it does not cover the whole time range of the block.
+func analyzeBlock(w io.Writer, b *tsdb.Block, limit int) error {
+	meta := b.Meta()
+	fmt.Fprintf(w, "Block ID: %s\n", meta.ULID)
+	// Presume 1ms resolution that Prometheus uses.
+	fmt.Fprintf(w, "Duration: %s\n", time.Duration(meta.MaxTime-meta.MinTime)*time.Millisecond)
+	fmt.Fprintf(w, "Series: %d\n", meta.Stats.NumSeries)
+
+	ir, err := b.Index()
+	if err != nil {
+		return err
+	}
+	defer ir.Close()
+
+	labelNames, err := ir.LabelIndices()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Label names: %d\n", len(labelNames))
+
+	// The empty label pair lists the postings of all series.
+	p, err := ir.Postings("", "")
+	if err != nil {
+		return err
+	}
+	var (
+		entries             int
+		labelsUncovered     = map[string]uint64{}
+		labelpairsUncovered = map[string]uint64{}
+		labelpairsCount     = map[string]uint64{}
+	)
+	for p.Next() {
+		var (
+			lset labels.Labels
+			chks []tsdb.ChunkMeta
+		)
+		if err := ir.Series(p.At(), &lset, &chks); err != nil {
+			return err
+		}
+		if len(chks) == 0 {
+			continue
+		}
+		// The part of the time range of the block not covered by the series.
+		uncovered := uint64(meta.MaxTime-meta.MinTime) - uint64(chks[len(chks)-1].MaxTime-chks[0].MinTime)
+		for _, l := range lset {
+			pair := l.Name + "=" + l.Value
+			labelsUncovered[l.Name] += uncovered
+			labelpairsUncovered[pair] += uncovered
+			labelpairsCount[pair]++
+			entries++
+		}
+	}
+	if err := p.Err(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Postings (unique label pairs): %d\n", len(labelpairsCount))
+	fmt.Fprintf(w, "Postings entries (total label pairs): %d\n", entries)
+
+	// Churn is reported as the number of series covering the whole block
+	// that would be needed to make up for the uncovered time.
+	blockRange := uint64(meta.MaxTime - meta.MinTime)
+	if blockRange == 0 {
+		blockRange = 1
+	}
+	printTop := func(title string, m map[string]uint64, scale uint64) {
+		fmt.Fprintf(w, "\n%s\n", title)
+		for _, e := range topEntries(m, limit) {
+			fmt.Fprintf(w, "%d %s\n", e.count/scale, e.name)
+		}
+	}
+	printTop("Label pairs most involved in churning:", labelpairsUncovered, blockRange)
+	printTop("Label names most involved in churning:", labelsUncovered, blockRange)
+	printTop("Most common label pairs:", labelpairsCount, 1)
+
+	valueCounts := map[string]uint64{}
+	for _, names := range labelNames {
+		if len(names) != 1 {
+			continue
+		}
+		values, err := ir.LabelValues(names[0])
+		if err != nil {
+			return err
+		}
+		valueCounts[names[0]] = uint64(values.Len())
+	}
+	printTop("Highest cardinality labels:", valueCounts, 1)
+
+	metricNames, err := ir.LabelValues(model.MetricNameLabel)
+	if err != nil {
+		return err
+	}
+	seriesCounts := map[string]uint64{}
+	for i := 0; i < metricNames.Len(); i++ {
+		name, err := metricNames.At(i)
+		if err != nil {
+			return err
+		}
+		p, err := ir.Postings(model.MetricNameLabel, name[0])
+		if err != nil {
+			return err
+		}
+		var n uint64
+		for p.Next() {
+			n++
+		}
+		if err := p.Err(); err != nil {
+			return err
+		}
+		seriesCounts[name[0]] = n
+	}
+	printTop("Highest cardinality metric names:", seriesCounts, 1)
+
+	return nil
+}
+
+type countEntry struct {
+	name  string
+	count uint64
+}
+
+// topEntries returns the limit entries of m with the highest counts,
+// ordered by descending count and name.
+func topEntries(m map[string]uint64, limit int) []countEntry {
+	entries := make([]countEntry, 0, len(m))
+	for name, count := range m {
+		entries = append(entries, countEntry{name: name, count: count})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].count != entries[j].count {
+			return entries[i].count > entries[j].count
+		}
+		return entries[i].name < entries[j].name
+	})
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries
+}
+
+// ReplayScrapes appends the samples of scrapes recorded with
+// --scrape.dump-dir to the database in dbDir, processing them like the
+// scrape configuration of their job in the config file, if any.
+func ReplayScrapes(dumpPath, dbDir, configFile string) int {
+	var cfgs []*config.ScrapeConfig
+	if configFile != "" {
+		cfg, err := config.LoadFile(configFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "  FAILED:", err)
+			return 1
+		}
+		cfgs = cfg.ScrapeConfigs
+	}
+
+	files := []string{dumpPath}
+	if fi, err := os.Stat(dumpPath); err != nil {
+		fmt.Fprintln(os.Stderr, "  FAILED:", err)
+		return 1
+	} else if fi.IsDir() {
+		if files, err = retrieval.ScrapeDumpFiles(dumpPath); err != nil {
+			fmt.Fprintln(os.Stderr, "  FAILED:", err)
+			return 1
+		}
+	}
+
+	db, err := promtsdb.Open(dbDir, nil, nil, &promtsdb.Options{
+		MinBlockDuration: model.Duration(2 * time.Hour),
+		MaxBlockDuration: model.Duration(2 * time.Hour),
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "  FAILED:", err)
+		return 1
+	}
+	defer db.Close()
+
+	failed, err := replayScrapes(os.Stdout, files, promtsdb.Adapter(db, 0), cfgs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "  FAILED:", err)
+		return 1
+	}
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// replayScrapes replays the scrapes recorded in the files and prints the
+// scrapes that failed to be appended along with a summary. It returns the
+// number of failed scrapes.
+func replayScrapes(w io.Writer, files []string, app retrieval.Appendable, cfgs []*config.ScrapeConfig) (int, error) {
+	var (
+		r                             = retrieval.NewScrapeReplayer(app, cfgs, nil)
+		scrapes, failed, total, added int
+	)
+	for _, fn := range files {
+		f, err := os.Open(fn)
+		if err != nil {
+			return failed, err
+		}
+		err = retrieval.ReadScrapeDump(f, func(rec *retrieval.ScrapeRecord) error {
+			t, a, err := r.Replay(rec)
+			scrapes++
+			total += t
+			// The samples of failed scrapes are rolled back.
+			if err != nil {
+				failed++
+				fmt.Fprintf(w, "%s at %d: %s\n", rec.Labels, rec.Timestamp, err)
+				return nil
+			}
+			added += a
+			return nil
+		})
+		f.Close()
+		if err != nil {
+			return failed, fmt.Errorf("reading %s: %s", fn, err)
+		}
+	}
+	fmt.Fprintf(w, "Replayed %d scrapes, %d failed. %d of %d samples were appended.\n", scrapes, failed, added, total)
+	return failed, nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/retrieval"
+	promtsdb "github.com/prometheus/prometheus/storage/tsdb"
+)
+
+func TestListAndAnalyzeBlocks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "promtool_tsdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	input := []byte(`up{job="a"} 1 0
+up{job="a"} 1 5
+up{job="b"} 1 5
+up{job="a"} 1 10
+up{job="b"} 1 10
+up{job="c"} 1 15
+requests_total{job="a",path="/"} 1 15
+# EOF
+`)
+	if err := promtsdb.CreateBlocksFromOpenMetrics(input, dir, 10*time.Second, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := promtsdb.OpenReadOnly(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	blocks := db.Blocks()
+	if len(blocks) != 2 {
+		t.Fatalf("expected 2 blocks, got %d", len(blocks))
+	}
+
+	var buf bytes.Buffer
+	printBlocks(&buf, blocks, true)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and 2 blocks, got:\n%s", buf.String())
+	}
+	if !strings.Contains(lines[1], "1970-01-01T00:00:00Z") || !strings.Contains(lines[2], "1970-01-01T00:00:20Z") {
+		t.Fatalf("unexpected block times:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := analyzeBlock(&buf, blocks[1], 2); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, exp := range []string{
+		"Duration: 10s\n",
+		"Series: 4\n",
+		"Label names: 3\n",
+		"Postings (unique label pairs): 6\n",
+		"Postings entries (total label pairs): 9\n",
+		"Label names most involved in churning:\n4 __name__\n4 job\n",
+		"Most common label pairs:\n3 __name__=up\n2 job=a\n",
+		"Highest cardinality labels:\n3 job\n2 __name__\n",
+		"Highest cardinality metric names:\n3 up\n1 requests_total\n",
+	} {
+		if !strings.Contains(out, exp) {
+			t.Fatalf("expected output to contain %q, got:\n%s", exp, out)
+		}
+	}
+}
+
+func TestReplayScrapes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "promtool_replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	dumpDir := filepath.Join(dir, "dump")
+	d, err := retrieval.NewScrapeDumper(dumpDir, 0, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := labels.FromStrings("job", "a", "instance", "localhost:9100")
+	d.Dump(target, time.Unix(10, 0), "", []byte("up 1\nrequests_total{path=\"/\"} 2\n"))
+	d.Dump(target, time.Unix(20, 0), "", []byte("up 1\nrequests_total{path=} 3\n"))
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+	files, err := retrieval.ScrapeDumpFiles(dumpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := promtsdb.Open(filepath.Join(dir, "data"), nil, nil, &promtsdb.Options{
+		MinBlockDuration: model.Duration(2 * time.Hour),
+		MaxBlockDuration: model.Duration(2 * time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var buf bytes.Buffer
+	failed, err := replayScrapes(&buf, files, promtsdb.Adapter(db, 0), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failed != 1 {
+		t.Fatalf("expected the second scrape to fail, got:\n%s", buf.String())
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], `{instance="localhost:9100", job="a"} at 20000: `) {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+	if lines[1] != "Replayed 2 scrapes, 1 failed. 2 of 3 samples were appended." {
+		t.Fatalf("unexpected summary %q", lines[1])
+	}
+}
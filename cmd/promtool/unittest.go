@@ -0,0 +1,409 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/rules"
+)
+
+// RulesUnitTest runs the rule unit tests defined in the given files.
+func RulesUnitTest(files ...string) int {
+	failed := false
+
+	for _, f := range files {
+		if errs := ruleUnitTest(f); errs != nil {
+			fmt.Fprintln(os.Stderr, "  FAILED:")
+			for _, e := range errs {
+				fmt.Fprintln(os.Stderr, e.Error())
+			}
+			failed = true
+		} else {
+			fmt.Println("  SUCCESS")
+		}
+		fmt.Println()
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+func ruleUnitTest(filename string) []error {
+	fmt.Println("Unit Testing:", filename)
+
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return []error{err}
+	}
+
+	var utf unitTestFile
+	if err := yaml.Unmarshal(b, &utf); err != nil {
+		return []error{err}
+	}
+	if utf.EvaluationInterval == 0 {
+		utf.EvaluationInterval = model.Duration(1 * time.Minute)
+	}
+
+	// Rule files are relative to the test file.
+	var ruleFiles []string
+	for _, pat := range utf.RuleFiles {
+		if !filepath.IsAbs(pat) {
+			pat = filepath.Join(filepath.Dir(filename), pat)
+		}
+		fs, err := filepath.Glob(pat)
+		if err != nil {
+			return []error{errors.Wrapf(err, "invalid rule file pattern %q", pat)}
+		}
+		ruleFiles = append(ruleFiles, fs...)
+	}
+
+	var errs []error
+	for i, tg := range utf.Tests {
+		for _, e := range tg.test(time.Duration(utf.EvaluationInterval), ruleFiles) {
+			errs = append(errs, errors.Wrapf(e, "    test %d", i))
+		}
+	}
+	return errs
+}
+
+// unitTestFile holds the contents of a single unit test file.
+type unitTestFile struct {
+	RuleFiles          []string       `yaml:"rule_files"`
+	EvaluationInterval model.Duration `yaml:"evaluation_interval,omitempty"`
+	Tests              []testGroup    `yaml:"tests"`
+}
+
+// testGroup is a group of input series and tests associated with it.
+type testGroup struct {
+	Interval        model.Duration   `yaml:"interval"`
+	InputSeries     []series         `yaml:"input_series"`
+	AlertRuleTests  []alertTestCase  `yaml:"alert_rule_test,omitempty"`
+	PromqlExprTests []promqlTestCase `yaml:"promql_expr_test,omitempty"`
+}
+
+// series is an input series in the promql test notation, such as
+// `up{job="a"}` with the values `1+0x10`.
+type series struct {
+	Series string `yaml:"series"`
+	Values string `yaml:"values"`
+}
+
+// alertTestCase lists the alerts expected to fire for an alert name at the
+// given time.
+type alertTestCase struct {
+	EvalTime  model.Duration `yaml:"eval_time"`
+	Alertname string         `yaml:"alertname"`
+	ExpAlerts []alert        `yaml:"exp_alerts"`
+}
+
+// alert is an expected alert. Its labels do not include the alert name.
+type alert struct {
+	ExpLabels      map[string]string `yaml:"exp_labels"`
+	ExpAnnotations map[string]string `yaml:"exp_annotations"`
+}
+
+// promqlTestCase is an expression expected to evaluate to the given samples
+// at the given time.
+type promqlTestCase struct {
+	Expr       string         `yaml:"expr"`
+	EvalTime   model.Duration `yaml:"eval_time"`
+	ExpSamples []parsedSample `yaml:"exp_samples"`
+}
+
+// parsedSample is an expected sample with its labels in the series notation.
+type parsedSample struct {
+	Labels string  `yaml:"labels"`
+	Value  float64 `yaml:"value"`
+}
+
+// fatalT reports errors of the test storage, which cannot be recovered from.
+type fatalT struct{}
+
+func (fatalT) Fatal(args ...interface{}) {
+	fmt.Fprintln(os.Stderr, args...)
+	os.Exit(1)
+}
+
+func (fatalT) Fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
+
+// test runs the rules in the given files against the group's input series
+// and returns the failed expectations.
+func (tg *testGroup) test(evalInterval time.Duration, ruleFiles []string) []error {
+	ll, err := promql.NewLazyLoader(fatalT{}, tg.seriesLoadingString())
+	if err != nil {
+		return []error{err}
+	}
+	defer ll.Close()
+
+	m := rules.NewManager(&rules.ManagerOptions{
+		ExternalURL: &url.URL{},
+		QueryEngine: ll.QueryEngine(),
+		Context:     ll.Context(),
+		Appendable:  ll.Storage(),
+		Logger:      log.NewNopLogger(),
+	})
+	groups, errs := m.LoadGroups(evalInterval, false, ruleFiles...)
+	if errs != nil {
+		return errs
+	}
+	orderedGroups := orderGroups(groups, ruleFiles)
+
+	alertTests := make([]alertTestCase, len(tg.AlertRuleTests))
+	copy(alertTests, tg.AlertRuleTests)
+	sort.SliceStable(alertTests, func(i, j int) bool {
+		return alertTests[i].EvalTime < alertTests[j].EvalTime
+	})
+
+	var maxEvalTime time.Duration
+	for _, at := range alertTests {
+		if d := time.Duration(at.EvalTime); d > maxEvalTime {
+			maxEvalTime = d
+		}
+	}
+	for _, pt := range tg.PromqlExprTests {
+		if d := time.Duration(pt.EvalTime); d > maxEvalTime {
+			maxEvalTime = d
+		}
+	}
+
+	// Evaluate all rules at each evaluation interval and check the alerts
+	// expected at times up to the next evaluation.
+	var failures []error
+	for ts := time.Duration(0); ts <= maxEvalTime; ts += evalInterval {
+		t := time.Unix(0, 0).Add(ts)
+
+		ll.WithSamplesTill(t, func(err error) {
+			if err != nil {
+				failures = append(failures, err)
+				return
+			}
+			for _, g := range orderedGroups {
+				g.Eval(t)
+			}
+		})
+		if len(failures) > 0 {
+			return failures
+		}
+
+		for len(alertTests) > 0 && time.Duration(alertTests[0].EvalTime) < ts+evalInterval {
+			if err := alertTests[0].check(orderedGroups); err != nil {
+				failures = append(failures, err)
+			}
+			alertTests = alertTests[1:]
+		}
+	}
+
+	// Expressions may refer to samples written by recording rules, which were
+	// all evaluated above.
+	ll.WithSamplesTill(time.Unix(0, 0).Add(maxEvalTime), func(err error) {
+		if err != nil {
+			failures = append(failures, err)
+			return
+		}
+		for _, pt := range tg.PromqlExprTests {
+			if err := pt.check(ll); err != nil {
+				failures = append(failures, err)
+			}
+		}
+	})
+	return failures
+}
+
+// seriesLoadingString returns the input series as a promql test load command.
+func (tg *testGroup) seriesLoadingString() string {
+	interval := tg.Interval
+	if interval == 0 {
+		interval = model.Duration(1 * time.Minute)
+	}
+	result := fmt.Sprintf("load %v\n", interval)
+	for _, is := range tg.InputSeries {
+		result += fmt.Sprintf("  %v %v\n", is.Series, is.Values)
+	}
+	return result
+}
+
+// orderGroups returns the groups in the order of their rule files, ordered
+// by name within a file.
+func orderGroups(groups map[string]*rules.Group, ruleFiles []string) []*rules.Group {
+	fileIndex := make(map[string]int, len(ruleFiles))
+	for i, fn := range ruleFiles {
+		if _, ok := fileIndex[fn]; !ok {
+			fileIndex[fn] = i
+		}
+	}
+	res := make([]*rules.Group, 0, len(groups))
+	for _, g := range groups {
+		res = append(res, g)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if fi, fj := fileIndex[res[i].File()], fileIndex[res[j].File()]; fi != fj {
+			return fi < fj
+		}
+		return res[i].Name() < res[j].Name()
+	})
+	return res
+}
+
+// labelsAndAnnotations is a firing alert as compared in tests.
+type labelsAndAnnotations struct {
+	Labels      labels.Labels
+	Annotations labels.Labels
+}
+
+func (la labelsAndAnnotations) String() string {
+	return fmt.Sprintf("Labels:%s Annotations:%s", la.Labels, la.Annotations)
+}
+
+type labelsAndAnnotationsList []labelsAndAnnotations
+
+func (l labelsAndAnnotationsList) sort() {
+	sort.Slice(l, func(i, j int) bool {
+		return labels.Compare(l[i].Labels, l[j].Labels) < 0
+	})
+}
+
+func (l labelsAndAnnotationsList) String() string {
+	if len(l) == 0 {
+		return "[]"
+	}
+	s := make([]string, 0, len(l))
+	for _, la := range l {
+		s = append(s, la.String())
+	}
+	return "[\n        " + strings.Join(s, "\n        ") + "\n      ]"
+}
+
+// check compares the alerts firing for the test's alert name to the expected
+// ones.
+func (at *alertTestCase) check(groups []*rules.Group) error {
+	var got labelsAndAnnotationsList
+	for _, g := range groups {
+		for _, r := range g.Rules() {
+			ar, ok := r.(*rules.AlertingRule)
+			if !ok || ar.Name() != at.Alertname {
+				continue
+			}
+			for _, a := range ar.ActiveAlerts() {
+				if a.State == rules.StateFiring {
+					got = append(got, labelsAndAnnotations{
+						Labels:      append(labels.Labels{}, a.Labels...),
+						Annotations: append(labels.Labels{}, a.Annotations...),
+					})
+				}
+			}
+		}
+	}
+
+	var exp labelsAndAnnotationsList
+	for _, a := range at.ExpAlerts {
+		lbls := labels.FromMap(a.ExpLabels)
+		lbls = append(lbls, labels.Label{Name: labels.AlertName, Value: at.Alertname})
+		sort.Sort(lbls)
+		exp = append(exp, labelsAndAnnotations{
+			Labels:      lbls,
+			Annotations: labels.FromMap(a.ExpAnnotations),
+		})
+	}
+
+	got.sort()
+	exp.sort()
+	if len(got) == 0 && len(exp) == 0 {
+		return nil
+	}
+	if !reflect.DeepEqual(exp, got) {
+		return fmt.Errorf("alertname:%s, time:%s,\n      exp:%v,\n      got:%v",
+			at.Alertname, time.Duration(at.EvalTime), exp, got)
+	}
+	return nil
+}
+
+// check evaluates the test's expression and compares the result to the
+// expected samples.
+func (pt *promqlTestCase) check(ll *promql.LazyLoader) error {
+	q, err := ll.QueryEngine().NewInstantQuery(pt.Expr, time.Unix(0, 0).Add(time.Duration(pt.EvalTime)))
+	if err != nil {
+		return errors.Wrapf(err, "expr:%q, time:%s", pt.Expr, time.Duration(pt.EvalTime))
+	}
+	res := q.Exec(ll.Context())
+	if res.Err != nil {
+		return errors.Wrapf(res.Err, "expr:%q, time:%s", pt.Expr, time.Duration(pt.EvalTime))
+	}
+
+	var got promql.Vector
+	switch v := res.Value.(type) {
+	case promql.Vector:
+		got = v
+	case promql.Scalar:
+		got = promql.Vector{{Point: promql.Point{T: v.T, V: v.V}, Metric: labels.Labels{}}}
+	default:
+		return fmt.Errorf("expr:%q, time:%s, unsupported result type %s", pt.Expr, time.Duration(pt.EvalTime), res.Value.Type())
+	}
+
+	var gotSamples, expSamples []parsedSample
+	for _, s := range got {
+		gotSamples = append(gotSamples, parsedSample{Labels: s.Metric.String(), Value: s.V})
+	}
+	for _, s := range pt.ExpSamples {
+		lbls, err := promql.ParseMetric(s.Labels)
+		if err != nil {
+			return errors.Wrapf(err, "expr:%q, time:%s, invalid labels %q", pt.Expr, time.Duration(pt.EvalTime), s.Labels)
+		}
+		expSamples = append(expSamples, parsedSample{Labels: lbls.String(), Value: s.Value})
+	}
+	sortSamples(gotSamples)
+	sortSamples(expSamples)
+
+	if !reflect.DeepEqual(expSamples, gotSamples) {
+		return fmt.Errorf("expr:%q, time:%s,\n      exp:%v,\n      got:%v",
+			pt.Expr, time.Duration(pt.EvalTime), parsedSamplesString(expSamples), parsedSamplesString(gotSamples))
+	}
+	return nil
+}
+
+func sortSamples(s []parsedSample) {
+	sort.Slice(s, func(i, j int) bool {
+		return s[i].Labels < s[j].Labels
+	})
+}
+
+func parsedSamplesString(pss []parsedSample) string {
+	if len(pss) == 0 {
+		return "nil"
+	}
+	s := make([]string, 0, len(pss))
+	for _, ps := range pss {
+		s = append(s, fmt.Sprintf("%s %v", ps.Labels, ps.Value))
+	}
+	return strings.Join(s, ", ")
+}
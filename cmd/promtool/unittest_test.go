@@ -0,0 +1,45 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "testing"
+
+func TestRulesUnitTest(t *testing.T) {
+	cases := []struct {
+		files []string
+		want  int
+	}{
+		{
+			files: []string{"./testdata/unittest.yml"},
+			want:  0,
+		},
+		{
+			files: []string{"./testdata/failing.yml"},
+			want:  1,
+		},
+		{
+			files: []string{"./testdata/unittest.yml", "./testdata/failing.yml"},
+			want:  1,
+		},
+		{
+			files: []string{"./testdata/does_not_exist.yml"},
+			want:  1,
+		},
+	}
+	for i, c := range cases {
+		if got := RulesUnitTest(c.files...); got != c.want {
+			t.Errorf("%d. RulesUnitTest(%v) = %d, want %d", i, c.files, got, c.want)
+		}
+	}
+}
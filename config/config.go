@@ -14,9 +14,11 @@
 package config
 
 import (
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"net/url"
 	"path/filepath"
 	"regexp"
@@ -27,6 +29,8 @@ import (
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/prometheus/common/model"
 	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 var (
@@ -83,15 +87,19 @@ var (
 	DefaultScrapeConfig = ScrapeConfig{
 		// ScrapeTimeout and ScrapeInterval default to the
 		// configured globals.
-		MetricsPath: "/metrics",
-		Scheme:      "http",
-		HonorLabels: false,
+		MetricsPath:     "/metrics",
+		Scheme:          "http",
+		HonorLabels:     false,
+		HonorTimestamps: true,
+		ScrapeJitter:    true,
+		ScrapeFormat:    ScrapeFormatPrometheus,
 	}
 
 	// DefaultAlertmanagerConfig is the default alertmanager configuration.
 	DefaultAlertmanagerConfig = AlertmanagerConfig{
-		Scheme:  "http",
-		Timeout: 10 * time.Second,
+		Scheme:     "http",
+		Timeout:    10 * time.Second,
+		APIVersion: AlertmanagerAPIVersionV1,
 	}
 
 	// DefaultRelabelConfig is the default Relabel configuration.
@@ -172,8 +180,9 @@ var (
 
 	// DefaultRemoteWriteConfig is the default remote write configuration.
 	DefaultRemoteWriteConfig = RemoteWriteConfig{
-		RemoteTimeout: model.Duration(30 * time.Second),
-		QueueConfig:   DefaultQueueConfig,
+		RemoteTimeout:  model.Duration(30 * time.Second),
+		QueueConfig:    DefaultQueueConfig,
+		MetadataConfig: DefaultMetadataConfig,
 	}
 
 	// DefaultQueueConfig is the default remote queue configuration.
@@ -194,10 +203,22 @@ var (
 		MaxBackoff: 100 * time.Millisecond,
 	}
 
+	// DefaultMetadataConfig is the default metadata configuration for a remote write endpoint.
+	DefaultMetadataConfig = MetadataConfig{
+		Send:         true,
+		SendInterval: model.Duration(1 * time.Minute),
+	}
+
 	// DefaultRemoteReadConfig is the default remote read configuration.
 	DefaultRemoteReadConfig = RemoteReadConfig{
 		RemoteTimeout: model.Duration(1 * time.Minute),
 		ReadRecent:    true,
+		// Dashboards send many queries at once, which should not wait
+		// for new connections to be established.
+		HTTPClientConfig: HTTPClientConfig{
+			MaxIdleConnsPerHost: 100,
+			EnableHTTP2:         true,
+		},
 	}
 )
 
@@ -263,6 +284,29 @@ func (s Secret) MarshalYAML() (interface{}, error) {
 	return nil, nil
 }
 
+// ReadSecret returns the secret if it is set, and otherwise the content of
+// the named file with surrounding whitespace trimmed. The secret is empty if
+// neither is set.
+func ReadSecret(secret Secret, filename string) (string, error) {
+	if secret != "" || filename == "" {
+		return string(secret), nil
+	}
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return "", fmt.Errorf("unable to read secret file %s: %s", filename, err)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// checkSecretFile returns an error if both the secret and the file it may be
+// read from are configured.
+func checkSecretFile(secret Secret, filename, secretKey, fileKey string) error {
+	if secret != "" && filename != "" {
+		return fmt.Errorf("at most one of %s & %s must be configured", secretKey, fileKey)
+	}
+	return nil
+}
+
 // resolveFilepaths joins all relative paths in a configuration
 // with a given base directory.
 func resolveFilepaths(baseDir string, cfg *Config) {
@@ -276,9 +320,13 @@ func resolveFilepaths(baseDir string, cfg *Config) {
 	for i, rf := range cfg.RuleFiles {
 		cfg.RuleFiles[i] = join(rf)
 	}
+	cfg.GlobalConfig.QueryLogFile = join(cfg.GlobalConfig.QueryLogFile)
 
 	clientPaths := func(scfg *HTTPClientConfig) {
 		scfg.BearerTokenFile = join(scfg.BearerTokenFile)
+		if scfg.BasicAuth != nil {
+			scfg.BasicAuth.PasswordFile = join(scfg.BasicAuth.PasswordFile)
+		}
 		scfg.TLSConfig.CAFile = join(scfg.TLSConfig.CAFile)
 		scfg.TLSConfig.CertFile = join(scfg.TLSConfig.CertFile)
 		scfg.TLSConfig.KeyFile = join(scfg.TLSConfig.KeyFile)
@@ -286,6 +334,9 @@ func resolveFilepaths(baseDir string, cfg *Config) {
 	sdPaths := func(cfg *ServiceDiscoveryConfig) {
 		for _, kcfg := range cfg.KubernetesSDConfigs {
 			kcfg.BearerTokenFile = join(kcfg.BearerTokenFile)
+			if kcfg.BasicAuth != nil {
+				kcfg.BasicAuth.PasswordFile = join(kcfg.BasicAuth.PasswordFile)
+			}
 			kcfg.TLSConfig.CAFile = join(kcfg.TLSConfig.CAFile)
 			kcfg.TLSConfig.CertFile = join(kcfg.TLSConfig.CertFile)
 			kcfg.TLSConfig.KeyFile = join(kcfg.TLSConfig.KeyFile)
@@ -297,10 +348,21 @@ func resolveFilepaths(baseDir string, cfg *Config) {
 			mcfg.TLSConfig.KeyFile = join(mcfg.TLSConfig.KeyFile)
 		}
 		for _, consulcfg := range cfg.ConsulSDConfigs {
+			consulcfg.TokenFile = join(consulcfg.TokenFile)
+			consulcfg.PasswordFile = join(consulcfg.PasswordFile)
 			consulcfg.TLSConfig.CAFile = join(consulcfg.TLSConfig.CAFile)
 			consulcfg.TLSConfig.CertFile = join(consulcfg.TLSConfig.CertFile)
 			consulcfg.TLSConfig.KeyFile = join(consulcfg.TLSConfig.KeyFile)
 		}
+		for _, ec2cfg := range cfg.EC2SDConfigs {
+			ec2cfg.SecretKeyFile = join(ec2cfg.SecretKeyFile)
+		}
+		for _, oscfg := range cfg.OpenstackSDConfigs {
+			oscfg.PasswordFile = join(oscfg.PasswordFile)
+		}
+		for _, azurecfg := range cfg.AzureSDConfigs {
+			azurecfg.ClientSecretFile = join(azurecfg.ClientSecretFile)
+		}
 		for _, filecfg := range cfg.FileSDConfigs {
 			for i, fn := range filecfg.Files {
 				filecfg.Files[i] = join(fn)
@@ -399,6 +461,8 @@ type GlobalConfig struct {
 	EvaluationInterval model.Duration `yaml:"evaluation_interval,omitempty"`
 	// The labels to add to any timeseries that this Prometheus instance scrapes.
 	ExternalLabels model.LabelSet `yaml:"external_labels,omitempty"`
+	// File to which all executed PromQL queries are logged.
+	QueryLogFile string `yaml:"query_log_file,omitempty"`
 
 	// Catches all undefined fields and must be empty after parsing.
 	XXX map[string]interface{} `yaml:",inline"`
@@ -443,7 +507,8 @@ func (c *GlobalConfig) isZero() bool {
 	return c.ExternalLabels == nil &&
 		c.ScrapeInterval == 0 &&
 		c.ScrapeTimeout == 0 &&
-		c.EvaluationInterval == 0
+		c.EvaluationInterval == 0 &&
+		c.QueryLogFile == ""
 }
 
 // TLSConfig configures the options for TLS connections.
@@ -458,11 +523,75 @@ type TLSConfig struct {
 	ServerName string `yaml:"server_name,omitempty"`
 	// Disable target certificate validation.
 	InsecureSkipVerify bool `yaml:"insecure_skip_verify"`
+	// The minimum TLS version accepted from the targets.
+	MinVersion TLSVersion `yaml:"min_version,omitempty"`
+	// Whether the targets may request renegotiation.
+	Renegotiation TLSRenegotiation `yaml:"renegotiation,omitempty"`
 
 	// Catches all undefined fields and must be empty after parsing.
 	XXX map[string]interface{} `yaml:",inline"`
 }
 
+// TLSVersion is a TLS protocol version.
+type TLSVersion uint16
+
+// TLSVersions maps the configurable TLS versions to their protocol versions.
+var TLSVersions = map[string]TLSVersion{
+	"TLS13": tls.VersionTLS13,
+	"TLS12": tls.VersionTLS12,
+	"TLS11": tls.VersionTLS11,
+	"TLS10": tls.VersionTLS10,
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (v *TLSVersion) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	if version, ok := TLSVersions[s]; ok {
+		*v = version
+		return nil
+	}
+	return fmt.Errorf("unknown TLS version %q", s)
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+func (v TLSVersion) MarshalYAML() (interface{}, error) {
+	for s, version := range TLSVersions {
+		if version == v {
+			return s, nil
+		}
+	}
+	return nil, nil
+}
+
+// TLSRenegotiation configures whether the targets may request renegotiation.
+type TLSRenegotiation string
+
+const (
+	// TLSRenegotiationNever disables renegotiation.
+	TLSRenegotiationNever TLSRenegotiation = "never"
+	// TLSRenegotiationOnce allows a target to renegotiate once per connection.
+	TLSRenegotiationOnce TLSRenegotiation = "once"
+	// TLSRenegotiationFreely allows a target to renegotiate repeatedly.
+	TLSRenegotiationFreely TLSRenegotiation = "freely"
+)
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (r *TLSRenegotiation) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	switch renegotiation := TLSRenegotiation(strings.ToLower(s)); renegotiation {
+	case TLSRenegotiationNever, TLSRenegotiationOnce, TLSRenegotiationFreely:
+		*r = renegotiation
+		return nil
+	}
+	return fmt.Errorf("unknown TLS renegotiation setting %q", s)
+}
+
 // UnmarshalYAML implements the yaml.Unmarshaler interface.
 func (c *TLSConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	type plain TLSConfig
@@ -500,6 +629,9 @@ type ServiceDiscoveryConfig struct {
 	AzureSDConfigs []*AzureSDConfig `yaml:"azure_sd_configs,omitempty"`
 	// List of Triton service discovery configurations.
 	TritonSDConfigs []*TritonSDConfig `yaml:"triton_sd_configs,omitempty"`
+	// List of configurations of service discovery mechanisms registered
+	// with RegisterSDConfig.
+	RegisteredSDConfigs []SDConfig `yaml:"-"`
 
 	// Catches all undefined fields and must be empty after parsing.
 	XXX map[string]interface{} `yaml:",inline"`
@@ -511,9 +643,20 @@ func (c *ServiceDiscoveryConfig) UnmarshalYAML(unmarshal func(interface{}) error
 	if err := unmarshal((*plain)(c)); err != nil {
 		return err
 	}
+	if err := c.unmarshalRegistered(c.XXX); err != nil {
+		return err
+	}
 	return checkOverflow(c.XXX, "service discovery config")
 }
 
+// MarshalYAML implements the yaml.Marshaler interface.
+func (c ServiceDiscoveryConfig) MarshalYAML() (interface{}, error) {
+	type plain ServiceDiscoveryConfig
+	p := plain(c)
+	p.XXX = c.marshalRegistered(c.XXX)
+	return p, nil
+}
+
 // HTTPClientConfig configures an HTTP client.
 type HTTPClientConfig struct {
 	// The HTTP basic authentication credentials for the targets.
@@ -526,6 +669,17 @@ type HTTPClientConfig struct {
 	ProxyURL URL `yaml:"proxy_url,omitempty"`
 	// TLSConfig to use to connect to the targets.
 	TLSConfig TLSConfig `yaml:"tls_config,omitempty"`
+	// The timeout for establishing connections to the targets.
+	DialTimeout model.Duration `yaml:"dial_timeout,omitempty"`
+	// Disable reusing connections to the targets across requests.
+	DisableKeepAlives bool `yaml:"disable_keep_alives,omitempty"`
+	// How long idle connections to the targets are kept open.
+	IdleConnTimeout model.Duration `yaml:"idle_conn_timeout,omitempty"`
+	// The maximum number of idle connections kept open per host. Zero
+	// keeps the default of the Go HTTP client.
+	MaxIdleConnsPerHost int `yaml:"max_idle_conns_per_host,omitempty"`
+	// Whether to use HTTP/2 for TLS connections if the server supports it.
+	EnableHTTP2 bool `yaml:"enable_http2"`
 
 	// Catches all undefined fields and must be empty after parsing.
 	XXX map[string]interface{} `yaml:",inline"`
@@ -538,15 +692,51 @@ func (c *HTTPClientConfig) validate() error {
 	if c.BasicAuth != nil && (len(c.BearerToken) > 0 || len(c.BearerTokenFile) > 0) {
 		return fmt.Errorf("at most one of basic_auth, bearer_token & bearer_token_file must be configured")
 	}
+	if c.MaxIdleConnsPerHost < 0 {
+		return fmt.Errorf("max_idle_conns_per_host must not be negative")
+	}
 	return nil
 }
 
+// ScrapeFormat represents the exposition format requested from scrape targets.
+type ScrapeFormat string
+
+const (
+	// ScrapeFormatPrometheus requests the Prometheus text format.
+	ScrapeFormatPrometheus ScrapeFormat = "prometheus"
+	// ScrapeFormatOpenMetrics requests the OpenMetrics text format and falls
+	// back to the Prometheus text format for targets not supporting it.
+	ScrapeFormatOpenMetrics ScrapeFormat = "openmetrics"
+)
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (f *ScrapeFormat) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	switch format := ScrapeFormat(strings.ToLower(s)); format {
+	case ScrapeFormatPrometheus, ScrapeFormatOpenMetrics:
+		*f = format
+		return nil
+	}
+	return fmt.Errorf("unknown scrape format %q", s)
+}
+
 // ScrapeConfig configures a scraping unit for Prometheus.
 type ScrapeConfig struct {
 	// The job name to which the job label is set by default.
 	JobName string `yaml:"job_name"`
 	// Indicator whether the scraped metrics should remain unmodified.
 	HonorLabels bool `yaml:"honor_labels,omitempty"`
+	// Indicator whether the scraped timestamps should be respected.
+	HonorTimestamps bool `yaml:"honor_timestamps"`
+	// Indicator whether the scrapes of the targets are spread across the
+	// scrape interval. Otherwise all targets are scraped at the start of
+	// the interval.
+	ScrapeJitter bool `yaml:"scrape_jitter"`
+	// The exposition format requested from the targets.
+	ScrapeFormat ScrapeFormat `yaml:"scrape_format,omitempty"`
 	// A set of query parameters with which the target is scraped.
 	Params url.Values `yaml:"params,omitempty"`
 	// How frequently to scrape the targets of this scrape config.
@@ -559,6 +749,12 @@ type ScrapeConfig struct {
 	Scheme string `yaml:"scheme,omitempty"`
 	// More than this many samples post metric-relabelling will cause the scrape to fail.
 	SampleLimit uint `yaml:"sample_limit,omitempty"`
+	// More than this many labels post metric-relabelling will cause the scrape to fail.
+	LabelLimit uint `yaml:"label_limit,omitempty"`
+	// More than this label name length post metric-relabelling will cause the scrape to fail.
+	LabelNameLengthLimit uint `yaml:"label_name_length_limit,omitempty"`
+	// More than this label value length post metric-relabelling will cause the scrape to fail.
+	LabelValueLengthLimit uint `yaml:"label_value_length_limit,omitempty"`
 
 	// We cannot do proper Go type embedding below as the parser will then parse
 	// values arbitrarily into the overflow maps of further-down types.
@@ -583,6 +779,11 @@ func (c *ScrapeConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	if err != nil {
 		return err
 	}
+	// The service discovery config is inlined, so the blocks of registered
+	// mechanisms end up in the fields caught by the scrape config.
+	if err = c.ServiceDiscoveryConfig.unmarshalRegistered(c.XXX); err != nil {
+		return err
+	}
 	if err = checkOverflow(c.XXX, "scrape_config"); err != nil {
 		return err
 	}
@@ -610,10 +811,21 @@ func (c *ScrapeConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return nil
 }
 
+// MarshalYAML implements the yaml.Marshaler interface. The service discovery
+// config is inlined, so the blocks of registered mechanisms are marshalled
+// with the fields caught by the scrape config.
+func (c ScrapeConfig) MarshalYAML() (interface{}, error) {
+	type plain ScrapeConfig
+	p := plain(c)
+	p.XXX = c.ServiceDiscoveryConfig.marshalRegistered(c.XXX)
+	return p, nil
+}
+
 // AlertingConfig configures alerting and alertmanager related configs.
 type AlertingConfig struct {
 	AlertRelabelConfigs []*RelabelConfig      `yaml:"alert_relabel_configs,omitempty"`
 	AlertmanagerConfigs []*AlertmanagerConfig `yaml:"alertmanagers,omitempty"`
+	Replica             AlertReplicaConfig    `yaml:"replica,omitempty"`
 
 	// Catches all undefined fields and must be empty after parsing.
 	XXX map[string]interface{} `yaml:",inline"`
@@ -631,6 +843,64 @@ func (c *AlertingConfig) UnmarshalYAML(unmarshal func(interface{}) error) error
 	return checkOverflow(c.XXX, "alerting config")
 }
 
+// AlertReplicaConfig configures the metadata attached to alerts that allows
+// Alertmanager to deduplicate the alerts of the replicas of a highly available
+// Prometheus setup.
+type AlertReplicaConfig struct {
+	// Labels identifying the replica, attached to all alerts. They take
+	// precedence over external labels of the same names.
+	Labels model.LabelSet `yaml:"labels,omitempty"`
+	// Whether to remove the replica labels from the labels of the alerts,
+	// which make up their identity, and keep them as annotations instead.
+	DropFromIdentity bool `yaml:"drop_from_identity,omitempty"`
+	// Annotation set to the time an alert was sent by the replica.
+	TimestampAnnotation model.LabelName `yaml:"timestamp_annotation,omitempty"`
+
+	// Catches all undefined fields and must be empty after parsing.
+	XXX map[string]interface{} `yaml:",inline"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *AlertReplicaConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = AlertReplicaConfig{}
+	type plain AlertReplicaConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.DropFromIdentity && len(c.Labels) == 0 {
+		return fmt.Errorf("drop_from_identity requires replica labels")
+	}
+	return checkOverflow(c.XXX, "alerting replica config")
+}
+
+// AlertmanagerAPIVersion represents a version of the Alertmanager API alerts
+// are sent to.
+type AlertmanagerAPIVersion string
+
+const (
+	// AlertmanagerAPIVersionV1 sends alerts to the v1 API.
+	AlertmanagerAPIVersionV1 AlertmanagerAPIVersion = "v1"
+	// AlertmanagerAPIVersionV2 sends alerts to the v2 API.
+	AlertmanagerAPIVersionV2 AlertmanagerAPIVersion = "v2"
+	// AlertmanagerAPIVersionAuto sends alerts to the v2 API and falls back
+	// to the v1 API for Alertmanagers not supporting v2.
+	AlertmanagerAPIVersionAuto AlertmanagerAPIVersion = "auto"
+)
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (v *AlertmanagerAPIVersion) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	switch version := AlertmanagerAPIVersion(strings.ToLower(s)); version {
+	case AlertmanagerAPIVersionV1, AlertmanagerAPIVersionV2, AlertmanagerAPIVersionAuto:
+		*v = version
+		return nil
+	}
+	return fmt.Errorf("unknown Alertmanager API version %q", s)
+}
+
 // AlertmanagerConfig configures how Alertmanagers can be discovered and communicated with.
 type AlertmanagerConfig struct {
 	// We cannot do proper Go type embedding below as the parser will then parse
@@ -645,6 +915,8 @@ type AlertmanagerConfig struct {
 	PathPrefix string `yaml:"path_prefix,omitempty"`
 	// The timeout used when sending alerts.
 	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// The version of the Alertmanager API alerts are sent to.
+	APIVersion AlertmanagerAPIVersion `yaml:"api_version,omitempty"`
 
 	// List of Alertmanager relabel configurations.
 	RelabelConfigs []*RelabelConfig `yaml:"relabel_configs,omitempty"`
@@ -660,6 +932,9 @@ func (c *AlertmanagerConfig) UnmarshalYAML(unmarshal func(interface{}) error) er
 	if err := unmarshal((*plain)(c)); err != nil {
 		return err
 	}
+	if err := c.ServiceDiscoveryConfig.unmarshalRegistered(c.XXX); err != nil {
+		return err
+	}
 	if err := checkOverflow(c.XXX, "alertmanager config"); err != nil {
 		return err
 	}
@@ -684,6 +959,16 @@ func (c *AlertmanagerConfig) UnmarshalYAML(unmarshal func(interface{}) error) er
 	return nil
 }
 
+// MarshalYAML implements the yaml.Marshaler interface. The service discovery
+// config is inlined, so the blocks of registered mechanisms are marshalled
+// with the fields caught by the Alertmanager config.
+func (c AlertmanagerConfig) MarshalYAML() (interface{}, error) {
+	type plain AlertmanagerConfig
+	p := plain(c)
+	p.XXX = c.ServiceDiscoveryConfig.marshalRegistered(c.XXX)
+	return p, nil
+}
+
 // CheckTargetAddress checks if target address is valid.
 func CheckTargetAddress(address model.LabelValue) error {
 	// For now check for a URL, we may want to expand this later.
@@ -695,8 +980,9 @@ func CheckTargetAddress(address model.LabelValue) error {
 
 // BasicAuth contains basic HTTP authentication credentials.
 type BasicAuth struct {
-	Username string `yaml:"username"`
-	Password Secret `yaml:"password"`
+	Username     string `yaml:"username"`
+	Password     Secret `yaml:"password,omitempty"`
+	PasswordFile string `yaml:"password_file,omitempty"`
 
 	// Catches all undefined fields and must be empty after parsing.
 	XXX map[string]interface{} `yaml:",inline"`
@@ -718,6 +1004,9 @@ func (a *BasicAuth) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	if err != nil {
 		return err
 	}
+	if err := checkSecretFile(a.Password, a.PasswordFile, "password", "password_file"); err != nil {
+		return err
+	}
 	return checkOverflow(a.XXX, "basic_auth")
 }
 
@@ -796,7 +1085,7 @@ type DNSSDConfig struct {
 	Names           []string       `yaml:"names"`
 	RefreshInterval model.Duration `yaml:"refresh_interval,omitempty"`
 	Type            string         `yaml:"type"`
-	Port            int            `yaml:"port"` // Ignored for SRV records
+	Port            int            `yaml:"port"` // Ignored for SRV records.
 	// Catches all undefined fields and must be empty after parsing.
 	XXX map[string]interface{} `yaml:",inline"`
 }
@@ -817,7 +1106,7 @@ func (c *DNSSDConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	}
 	switch strings.ToUpper(c.Type) {
 	case "SRV":
-	case "A", "AAAA":
+	case "A", "AAAA", "MX":
 		if c.Port == 0 {
 			return fmt.Errorf("a port is required in DNS-SD configs for all record types except SRV")
 		}
@@ -862,14 +1151,22 @@ func (c *FileSDConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 type ConsulSDConfig struct {
 	Server       string `yaml:"server"`
 	Token        Secret `yaml:"token,omitempty"`
+	TokenFile    string `yaml:"token_file,omitempty"`
 	Datacenter   string `yaml:"datacenter,omitempty"`
 	TagSeparator string `yaml:"tag_separator,omitempty"`
 	Scheme       string `yaml:"scheme,omitempty"`
 	Username     string `yaml:"username,omitempty"`
 	Password     Secret `yaml:"password,omitempty"`
+	PasswordFile string `yaml:"password_file,omitempty"`
 	// The list of services for which targets are discovered.
 	// Defaults to all services if empty.
 	Services []string `yaml:"services"`
+	// Only discover instances of services carrying all of these tags.
+	ServiceTags []string `yaml:"tags,omitempty"`
+	// Only discover services on nodes carrying all of this node metadata.
+	NodeMeta map[string]string `yaml:"node_meta,omitempty"`
+	// Allow any Consul server to answer queries, not only the leader.
+	AllowStale bool `yaml:"allow_stale,omitempty"`
 
 	TLSConfig TLSConfig `yaml:"tls_config,omitempty"`
 	// Catches all undefined fields and must be empty after parsing.
@@ -890,7 +1187,10 @@ func (c *ConsulSDConfig) UnmarshalYAML(unmarshal func(interface{}) error) error
 	if strings.TrimSpace(c.Server) == "" {
 		return fmt.Errorf("Consul SD configuration requires a server address")
 	}
-	return nil
+	if err := checkSecretFile(c.Token, c.TokenFile, "token", "token_file"); err != nil {
+		return err
+	}
+	return checkSecretFile(c.Password, c.PasswordFile, "password", "password_file")
 }
 
 // ServersetSDConfig is the configuration for Twitter serversets in Zookeeper based discovery.
@@ -1031,6 +1331,7 @@ type KubernetesSDConfig struct {
 	BearerTokenFile    string                       `yaml:"bearer_token_file,omitempty"`
 	TLSConfig          TLSConfig                    `yaml:"tls_config,omitempty"`
 	NamespaceDiscovery KubernetesNamespaceDiscovery `yaml:"namespaces"`
+	Selectors          []KubernetesSelectorConfig   `yaml:"selectors,omitempty"`
 
 	// Catches all undefined fields and must be empty after parsing.
 	XXX map[string]interface{} `yaml:",inline"`
@@ -1061,6 +1362,58 @@ func (c *KubernetesSDConfig) UnmarshalYAML(unmarshal func(interface{}) error) er
 			c.TLSConfig.CAFile != "" || c.TLSConfig.CertFile != "" || c.TLSConfig.KeyFile != "") {
 		return fmt.Errorf("to use custom authentication please provide the 'api_server' URL explicitly")
 	}
+
+	// Endpoints discovery also watches the pods and services backing the
+	// endpoints, so their objects may be filtered as well.
+	allowedSelectors := map[KubernetesRole]bool{c.Role: true}
+	if c.Role == KubernetesRoleEndpoint {
+		allowedSelectors[KubernetesRolePod] = true
+		allowedSelectors[KubernetesRoleService] = true
+	}
+	seen := map[KubernetesRole]bool{}
+	for _, sel := range c.Selectors {
+		if !allowedSelectors[sel.Role] {
+			return fmt.Errorf("selector role %q is not allowed for Kubernetes SD role %q", sel.Role, c.Role)
+		}
+		if seen[sel.Role] {
+			return fmt.Errorf("duplicate selector role %q", sel.Role)
+		}
+		seen[sel.Role] = true
+	}
+	return nil
+}
+
+// KubernetesSelectorConfig restricts the objects of a role watched by
+// Kubernetes service discovery to those matching the label and field
+// selectors.
+type KubernetesSelectorConfig struct {
+	Role  KubernetesRole `yaml:"role"`
+	Label string         `yaml:"label,omitempty"`
+	Field string         `yaml:"field,omitempty"`
+
+	// Catches all undefined fields and must be empty after parsing.
+	XXX map[string]interface{} `yaml:",inline"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *KubernetesSelectorConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = KubernetesSelectorConfig{}
+	type plain KubernetesSelectorConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if err := checkOverflow(c.XXX, "selectors"); err != nil {
+		return err
+	}
+	if c.Role == "" {
+		return fmt.Errorf("selector role missing")
+	}
+	if _, err := labels.Parse(c.Label); err != nil {
+		return fmt.Errorf("invalid label selector %q for role %q: %s", c.Label, c.Role, err)
+	}
+	if _, err := fields.ParseSelector(c.Field); err != nil {
+		return fmt.Errorf("invalid field selector %q for role %q: %s", c.Field, c.Role, err)
+	}
 	return nil
 }
 
@@ -1130,15 +1483,45 @@ type EC2SDConfig struct {
 	Region          string         `yaml:"region"`
 	AccessKey       string         `yaml:"access_key,omitempty"`
 	SecretKey       Secret         `yaml:"secret_key,omitempty"`
+	SecretKeyFile   string         `yaml:"secret_key_file,omitempty"`
 	Profile         string         `yaml:"profile,omitempty"`
 	RoleARN         string         `yaml:"role_arn,omitempty"`
 	RefreshInterval model.Duration `yaml:"refresh_interval,omitempty"`
 	Port            int            `yaml:"port"`
+	Filters         []*EC2Filter   `yaml:"filters,omitempty"`
+
+	// Catches all undefined fields and must be empty after parsing.
+	XXX map[string]interface{} `yaml:",inline"`
+}
+
+// EC2Filter is the configuration for filtering EC2 instances.
+type EC2Filter struct {
+	Name   string   `yaml:"name"`
+	Values []string `yaml:"values"`
 
 	// Catches all undefined fields and must be empty after parsing.
 	XXX map[string]interface{} `yaml:",inline"`
 }
 
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *EC2Filter) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = EC2Filter{}
+	type plain EC2Filter
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if err := checkOverflow(c.XXX, "ec2 filter"); err != nil {
+		return err
+	}
+	if c.Name == "" {
+		return fmt.Errorf("EC2 SD filter requires a name")
+	}
+	if len(c.Values) == 0 {
+		return fmt.Errorf("EC2 SD filter %q requires at least one value", c.Name)
+	}
+	return nil
+}
+
 // UnmarshalYAML implements the yaml.Unmarshaler interface.
 func (c *EC2SDConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	*c = DefaultEC2SDConfig
@@ -1150,6 +1533,9 @@ func (c *EC2SDConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	if err := checkOverflow(c.XXX, "ec2_sd_config"); err != nil {
 		return err
 	}
+	if err := checkSecretFile(c.SecretKey, c.SecretKeyFile, "secret_key", "secret_key_file"); err != nil {
+		return err
+	}
 	if c.Region == "" {
 		sess, err := session.NewSession()
 		if err != nil {
@@ -1170,7 +1556,8 @@ type OpenstackSDConfig struct {
 	IdentityEndpoint string         `yaml:"identity_endpoint"`
 	Username         string         `yaml:"username"`
 	UserID           string         `yaml:"userid"`
-	Password         Secret         `yaml:"password"`
+	Password         Secret         `yaml:"password,omitempty"`
+	PasswordFile     string         `yaml:"password_file,omitempty"`
 	ProjectName      string         `yaml:"project_name"`
 	ProjectID        string         `yaml:"project_id"`
 	DomainName       string         `yaml:"domain_name"`
@@ -1221,17 +1608,21 @@ func (c *OpenstackSDConfig) UnmarshalYAML(unmarshal func(interface{}) error) err
 	if c.Role == "" {
 		return fmt.Errorf("role missing (one of: instance, hypervisor)")
 	}
+	if err := checkSecretFile(c.Password, c.PasswordFile, "password", "password_file"); err != nil {
+		return err
+	}
 	return checkOverflow(c.XXX, "openstack_sd_config")
 }
 
 // AzureSDConfig is the configuration for Azure based service discovery.
 type AzureSDConfig struct {
-	Port            int            `yaml:"port"`
-	SubscriptionID  string         `yaml:"subscription_id"`
-	TenantID        string         `yaml:"tenant_id,omitempty"`
-	ClientID        string         `yaml:"client_id,omitempty"`
-	ClientSecret    Secret         `yaml:"client_secret,omitempty"`
-	RefreshInterval model.Duration `yaml:"refresh_interval,omitempty"`
+	Port             int            `yaml:"port"`
+	SubscriptionID   string         `yaml:"subscription_id"`
+	TenantID         string         `yaml:"tenant_id,omitempty"`
+	ClientID         string         `yaml:"client_id,omitempty"`
+	ClientSecret     Secret         `yaml:"client_secret,omitempty"`
+	ClientSecretFile string         `yaml:"client_secret_file,omitempty"`
+	RefreshInterval  model.Duration `yaml:"refresh_interval,omitempty"`
 
 	// Catches all undefined fields and must be empty after parsing.
 	XXX map[string]interface{} `yaml:",inline"`
@@ -1245,7 +1636,9 @@ func (c *AzureSDConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	if err != nil {
 		return err
 	}
-
+	if err := checkSecretFile(c.ClientSecret, c.ClientSecretFile, "client_secret", "client_secret_file"); err != nil {
+		return err
+	}
 	return checkOverflow(c.XXX, "azure_sd_config")
 }
 
@@ -1303,6 +1696,14 @@ const (
 	RelabelLabelDrop RelabelAction = "labeldrop"
 	// RelabelLabelKeep drops any label not matching the regex.
 	RelabelLabelKeep RelabelAction = "labelkeep"
+	// RelabelLowercase sets a label to the lowercased concatenation of labels.
+	RelabelLowercase RelabelAction = "lowercase"
+	// RelabelUppercase sets a label to the uppercased concatenation of labels.
+	RelabelUppercase RelabelAction = "uppercase"
+	// RelabelKeepEqual drops targets for which the input does not equal the target label.
+	RelabelKeepEqual RelabelAction = "keepequal"
+	// RelabelDropEqual drops targets for which the input equals the target label.
+	RelabelDropEqual RelabelAction = "dropequal"
 )
 
 // UnmarshalYAML implements the yaml.Unmarshaler interface.
@@ -1312,7 +1713,8 @@ func (a *RelabelAction) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		return err
 	}
 	switch act := RelabelAction(strings.ToLower(s)); act {
-	case RelabelReplace, RelabelKeep, RelabelDrop, RelabelHashMod, RelabelLabelMap, RelabelLabelDrop, RelabelLabelKeep:
+	case RelabelReplace, RelabelKeep, RelabelDrop, RelabelHashMod, RelabelLabelMap, RelabelLabelDrop, RelabelLabelKeep,
+		RelabelLowercase, RelabelUppercase, RelabelKeepEqual, RelabelDropEqual:
 		*a = act
 		return nil
 	}
@@ -1330,10 +1732,12 @@ type RelabelConfig struct {
 	Regex Regexp `yaml:"regex,omitempty"`
 	// Modulus to take of the hash of concatenated values from the source labels.
 	Modulus uint64 `yaml:"modulus,omitempty"`
-	// TargetLabel is the label to which the resulting string is written in a replacement.
+	// TargetLabel is the label to which the resulting string is written in a replacement,
+	// or whose value is compared to it for the keepequal and dropequal actions.
 	// Regexp interpolation is allowed for the replace action.
 	TargetLabel string `yaml:"target_label,omitempty"`
-	// Replacement is the regex replacement pattern to be used.
+	// Replacement is the regex replacement pattern to be used. For the labelmap
+	// action it is the template of the new label names.
 	Replacement string `yaml:"replacement,omitempty"`
 	// Action is the action to be performed for the relabeling.
 	Action RelabelAction `yaml:"action,omitempty"`
@@ -1358,14 +1762,23 @@ func (c *RelabelConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	if c.Modulus == 0 && c.Action == RelabelHashMod {
 		return fmt.Errorf("relabel configuration for hashmod requires non-zero modulus")
 	}
-	if (c.Action == RelabelReplace || c.Action == RelabelHashMod) && c.TargetLabel == "" {
-		return fmt.Errorf("relabel configuration for %s action requires 'target_label' value", c.Action)
+	switch c.Action {
+	case RelabelReplace, RelabelHashMod, RelabelLowercase, RelabelUppercase, RelabelKeepEqual, RelabelDropEqual:
+		if c.TargetLabel == "" {
+			return fmt.Errorf("relabel configuration for %s action requires 'target_label' value", c.Action)
+		}
 	}
 	if c.Action == RelabelReplace && !relabelTarget.MatchString(c.TargetLabel) {
 		return fmt.Errorf("%q is invalid 'target_label' for %s action", c.TargetLabel, c.Action)
 	}
-	if c.Action == RelabelHashMod && !model.LabelName(c.TargetLabel).IsValid() {
-		return fmt.Errorf("%q is invalid 'target_label' for %s action", c.TargetLabel, c.Action)
+	switch c.Action {
+	case RelabelHashMod, RelabelLowercase, RelabelUppercase, RelabelKeepEqual, RelabelDropEqual:
+		if !model.LabelName(c.TargetLabel).IsValid() {
+			return fmt.Errorf("%q is invalid 'target_label' for %s action", c.TargetLabel, c.Action)
+		}
+	}
+	if c.Action == RelabelLabelMap && !relabelTarget.MatchString(c.Replacement) {
+		return fmt.Errorf("%q is invalid 'replacement' for %s action", c.Replacement, c.Action)
 	}
 
 	if c.Action == RelabelLabelDrop || c.Action == RelabelLabelKeep {
@@ -1378,6 +1791,14 @@ func (c *RelabelConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		}
 	}
 
+	if c.Action == RelabelKeepEqual || c.Action == RelabelDropEqual {
+		if c.Regex.original != DefaultRelabelConfig.Regex.original ||
+			c.Modulus != DefaultRelabelConfig.Modulus ||
+			c.Replacement != DefaultRelabelConfig.Replacement {
+			return fmt.Errorf("%s action requires only 'source_labels', 'separator' and 'target_label', and no other fields", c.Action)
+		}
+	}
+
 	return nil
 }
 
@@ -1433,11 +1854,17 @@ type RemoteWriteConfig struct {
 	URL                 *URL             `yaml:"url"`
 	RemoteTimeout       model.Duration   `yaml:"remote_timeout,omitempty"`
 	WriteRelabelConfigs []*RelabelConfig `yaml:"write_relabel_configs,omitempty"`
+	// Custom HTTP headers sent along with each request.
+	Headers map[string]string `yaml:"headers,omitempty"`
+	// Labels added to the samples sent to the endpoint. They take
+	// precedence over the global external labels.
+	ExternalLabels model.LabelSet `yaml:"external_labels,omitempty"`
 
 	// We cannot do proper Go type embedding below as the parser will then parse
 	// values arbitrarily into the overflow maps of further-down types.
 	HTTPClientConfig HTTPClientConfig `yaml:",inline"`
 	QueueConfig      QueueConfig      `yaml:"queue_config,omitempty"`
+	MetadataConfig   MetadataConfig   `yaml:"metadata_config,omitempty"`
 
 	// Catches all undefined fields and must be empty after parsing.
 	XXX map[string]interface{} `yaml:",inline"`
@@ -1453,6 +1880,9 @@ func (c *RemoteWriteConfig) UnmarshalYAML(unmarshal func(interface{}) error) err
 	if c.URL == nil {
 		return fmt.Errorf("url for remote_write is empty")
 	}
+	if err := validateHeaders(c.Headers); err != nil {
+		return err
+	}
 
 	// The UnmarshalYAML method of HTTPClientConfig is not being called because it's not a pointer.
 	// We cannot make it a pointer as the parser panics for inlined pointer structs.
@@ -1464,6 +1894,32 @@ func (c *RemoteWriteConfig) UnmarshalYAML(unmarshal func(interface{}) error) err
 	return checkOverflow(c.XXX, "remote_write")
 }
 
+// reservedHeaders are HTTP headers that are set by the remote storage client
+// itself or through other configuration options and cannot be overridden.
+var reservedHeaders = map[string]struct{}{
+	"Authorization":                     {},
+	"Host":                              {},
+	"Content-Encoding":                  {},
+	"Content-Length":                    {},
+	"Content-Type":                      {},
+	"User-Agent":                        {},
+	"Connection":                        {},
+	"Keep-Alive":                        {},
+	"Proxy-Authorization":               {},
+	"Transfer-Encoding":                 {},
+	"X-Prometheus-Remote-Write-Version": {},
+	"X-Prometheus-Remote-Read-Version":  {},
+}
+
+func validateHeaders(headers map[string]string) error {
+	for name := range headers {
+		if _, ok := reservedHeaders[http.CanonicalHeaderKey(name)]; ok {
+			return fmt.Errorf("%s is a reserved header and cannot be configured", name)
+		}
+	}
+	return nil
+}
+
 // QueueConfig is the configuration for the queue used to write to remote
 // storage.
 type QueueConfig struct {
@@ -1487,11 +1943,45 @@ type QueueConfig struct {
 	MaxBackoff time.Duration `yaml:"max_backoff,omitempty"`
 }
 
+// MetadataConfig is the configuration for sending the metadata of the
+// scraped metric families to a remote write endpoint.
+type MetadataConfig struct {
+	// Send controls whether metadata is sent.
+	Send bool `yaml:"send"`
+	// SendInterval controls how frequently metadata is sent.
+	SendInterval model.Duration `yaml:"send_interval"`
+
+	// Catches all undefined fields and must be empty after parsing.
+	XXX map[string]interface{} `yaml:",inline"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *MetadataConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultMetadataConfig
+	type plain MetadataConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if err := checkOverflow(c.XXX, "metadata_config"); err != nil {
+		return err
+	}
+	if c.Send && c.SendInterval <= 0 {
+		return fmt.Errorf("send_interval for metadata_config must be positive")
+	}
+	return nil
+}
+
 // RemoteReadConfig is the configuration for reading from remote storage.
 type RemoteReadConfig struct {
 	URL           *URL           `yaml:"url"`
 	RemoteTimeout model.Duration `yaml:"remote_timeout,omitempty"`
 	ReadRecent    bool           `yaml:"read_recent,omitempty"`
+	// An optional list of equality matchers which have to be present
+	// in a selector to query the remote read endpoint.
+	RequiredMatchers model.LabelSet `yaml:"required_matchers,omitempty"`
+	// Custom HTTP headers sent along with each request.
+	Headers map[string]string `yaml:"headers,omitempty"`
+
 	// We cannot do proper Go type embedding below as the parser will then parse
 	// values arbitrarily into the overflow maps of further-down types.
 	HTTPClientConfig HTTPClientConfig `yaml:",inline"`
@@ -1510,6 +2000,9 @@ func (c *RemoteReadConfig) UnmarshalYAML(unmarshal func(interface{}) error) erro
 	if c.URL == nil {
 		return fmt.Errorf("url for remote_read is empty")
 	}
+	if err := validateHeaders(c.Headers); err != nil {
+		return err
+	}
 
 	// The UnmarshalYAML method of HTTPClientConfig is not being called because it's not a pointer.
 	// We cannot make it a pointer as the parser panics for inlined pointer structs.
@@ -14,9 +14,11 @@
 package config
 
 import (
+	"crypto/tls"
 	"encoding/json"
 	"io/ioutil"
 	"net/url"
+	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -46,6 +48,7 @@ var expectedConf = &Config{
 			"monitor": "codelab",
 			"foo":     "bar",
 		},
+		QueryLogFile: filepath.FromSlash("testdata/query.log"),
 	},
 
 	RuleFiles: []string{
@@ -66,25 +69,37 @@ var expectedConf = &Config{
 					Action:       RelabelDrop,
 				},
 			},
-			QueueConfig: DefaultQueueConfig,
+			QueueConfig:    DefaultQueueConfig,
+			MetadataConfig: DefaultMetadataConfig,
 		},
 		{
-			URL:           mustParseURL("http://remote2/push"),
-			RemoteTimeout: model.Duration(30 * time.Second),
-			QueueConfig:   DefaultQueueConfig,
+			URL:            mustParseURL("http://remote2/push"),
+			RemoteTimeout:  model.Duration(30 * time.Second),
+			Headers:        map[string]string{"X-Scope-OrgID": "tenant-1"},
+			ExternalLabels: model.LabelSet{"replica": "b"},
+			QueueConfig:    DefaultQueueConfig,
+			MetadataConfig: MetadataConfig{
+				Send:         true,
+				SendInterval: model.Duration(5 * time.Minute),
+			},
 		},
 	},
 
 	RemoteReadConfigs: []*RemoteReadConfig{
 		{
-			URL:           mustParseURL("http://remote1/read"),
-			RemoteTimeout: model.Duration(1 * time.Minute),
-			ReadRecent:    true,
+			URL:              mustParseURL("http://remote1/read"),
+			RemoteTimeout:    model.Duration(1 * time.Minute),
+			ReadRecent:       true,
+			HTTPClientConfig: DefaultRemoteReadConfig.HTTPClientConfig,
 		},
 		{
-			URL:           mustParseURL("http://remote3/read"),
-			RemoteTimeout: model.Duration(1 * time.Minute),
-			ReadRecent:    false,
+			URL:              mustParseURL("http://remote3/read"),
+			RemoteTimeout:    model.Duration(1 * time.Minute),
+			ReadRecent:       false,
+			RequiredMatchers: model.LabelSet{"job": "special"},
+			HTTPClientConfig: HTTPClientConfig{
+				MaxIdleConnsPerHost: 10,
+			},
 		},
 	},
 
@@ -92,9 +107,12 @@ var expectedConf = &Config{
 		{
 			JobName: "prometheus",
 
-			HonorLabels:    true,
-			ScrapeInterval: model.Duration(15 * time.Second),
-			ScrapeTimeout:  DefaultGlobalConfig.ScrapeTimeout,
+			HonorTimestamps: true,
+			ScrapeJitter:    true,
+			ScrapeFormat:    ScrapeFormatPrometheus,
+			HonorLabels:     true,
+			ScrapeInterval:  model.Duration(15 * time.Second),
+			ScrapeTimeout:   DefaultGlobalConfig.ScrapeTimeout,
 
 			MetricsPath: DefaultScrapeConfig.MetricsPath,
 			Scheme:      DefaultScrapeConfig.Scheme,
@@ -163,9 +181,15 @@ var expectedConf = &Config{
 
 			JobName: "service-x",
 
-			ScrapeInterval: model.Duration(50 * time.Second),
-			ScrapeTimeout:  model.Duration(5 * time.Second),
-			SampleLimit:    1000,
+			HonorTimestamps:       false,
+			ScrapeJitter:          false,
+			ScrapeFormat:          ScrapeFormatOpenMetrics,
+			ScrapeInterval:        model.Duration(50 * time.Second),
+			ScrapeTimeout:         model.Duration(5 * time.Second),
+			SampleLimit:           1000,
+			LabelLimit:            30,
+			LabelNameLengthLimit:  200,
+			LabelValueLengthLimit: 200,
 
 			HTTPClientConfig: HTTPClientConfig{
 				BasicAuth: &BasicAuth{
@@ -252,8 +276,11 @@ var expectedConf = &Config{
 		{
 			JobName: "service-y",
 
-			ScrapeInterval: model.Duration(15 * time.Second),
-			ScrapeTimeout:  DefaultGlobalConfig.ScrapeTimeout,
+			HonorTimestamps: true,
+			ScrapeJitter:    true,
+			ScrapeFormat:    ScrapeFormatPrometheus,
+			ScrapeInterval:  model.Duration(15 * time.Second),
+			ScrapeTimeout:   DefaultGlobalConfig.ScrapeTimeout,
 
 			MetricsPath: DefaultScrapeConfig.MetricsPath,
 			Scheme:      DefaultScrapeConfig.Scheme,
@@ -264,6 +291,9 @@ var expectedConf = &Config{
 						Server:       "localhost:1234",
 						Token:        "mysecret",
 						Services:     []string{"nginx", "cache", "mysql"},
+						ServiceTags:  []string{"canary", "v1"},
+						NodeMeta:     map[string]string{"rack": "123"},
+						AllowStale:   true,
 						TagSeparator: DefaultConsulSDConfig.TagSeparator,
 						Scheme:       "https",
 						TLSConfig: TLSConfig{
@@ -290,26 +320,38 @@ var expectedConf = &Config{
 		{
 			JobName: "service-z",
 
-			ScrapeInterval: model.Duration(15 * time.Second),
-			ScrapeTimeout:  model.Duration(10 * time.Second),
+			HonorTimestamps: true,
+			ScrapeJitter:    true,
+			ScrapeFormat:    ScrapeFormatPrometheus,
+			ScrapeInterval:  model.Duration(15 * time.Second),
+			ScrapeTimeout:   model.Duration(10 * time.Second),
 
 			MetricsPath: "/metrics",
 			Scheme:      "http",
 
 			HTTPClientConfig: HTTPClientConfig{
 				TLSConfig: TLSConfig{
-					CertFile: filepath.FromSlash("testdata/valid_cert_file"),
-					KeyFile:  filepath.FromSlash("testdata/valid_key_file"),
+					CertFile:      filepath.FromSlash("testdata/valid_cert_file"),
+					KeyFile:       filepath.FromSlash("testdata/valid_key_file"),
+					MinVersion:    TLSVersion(tls.VersionTLS12),
+					Renegotiation: TLSRenegotiationOnce,
 				},
 
 				BearerToken: "mysecret",
+
+				DialTimeout:       model.Duration(10 * time.Second),
+				DisableKeepAlives: true,
+				IdleConnTimeout:   model.Duration(time.Minute),
 			},
 		},
 		{
 			JobName: "service-kubernetes",
 
-			ScrapeInterval: model.Duration(15 * time.Second),
-			ScrapeTimeout:  DefaultGlobalConfig.ScrapeTimeout,
+			HonorTimestamps: true,
+			ScrapeJitter:    true,
+			ScrapeFormat:    ScrapeFormatPrometheus,
+			ScrapeInterval:  model.Duration(15 * time.Second),
+			ScrapeTimeout:   DefaultGlobalConfig.ScrapeTimeout,
 
 			MetricsPath: DefaultScrapeConfig.MetricsPath,
 			Scheme:      DefaultScrapeConfig.Scheme,
@@ -331,8 +373,11 @@ var expectedConf = &Config{
 		{
 			JobName: "service-kubernetes-namespaces",
 
-			ScrapeInterval: model.Duration(15 * time.Second),
-			ScrapeTimeout:  DefaultGlobalConfig.ScrapeTimeout,
+			HonorTimestamps: true,
+			ScrapeJitter:    true,
+			ScrapeFormat:    ScrapeFormatPrometheus,
+			ScrapeInterval:  model.Duration(15 * time.Second),
+			ScrapeTimeout:   DefaultGlobalConfig.ScrapeTimeout,
 
 			MetricsPath: DefaultScrapeConfig.MetricsPath,
 			Scheme:      DefaultScrapeConfig.Scheme,
@@ -347,6 +392,16 @@ var expectedConf = &Config{
 								"default",
 							},
 						},
+						Selectors: []KubernetesSelectorConfig{
+							{
+								Role:  KubernetesRolePod,
+								Label: "app=frontend",
+							},
+							{
+								Role:  KubernetesRoleEndpoint,
+								Field: "metadata.name!=kubernetes",
+							},
+						},
 					},
 				},
 			},
@@ -354,8 +409,11 @@ var expectedConf = &Config{
 		{
 			JobName: "service-marathon",
 
-			ScrapeInterval: model.Duration(15 * time.Second),
-			ScrapeTimeout:  DefaultGlobalConfig.ScrapeTimeout,
+			HonorTimestamps: true,
+			ScrapeJitter:    true,
+			ScrapeFormat:    ScrapeFormatPrometheus,
+			ScrapeInterval:  model.Duration(15 * time.Second),
+			ScrapeTimeout:   DefaultGlobalConfig.ScrapeTimeout,
 
 			MetricsPath: DefaultScrapeConfig.MetricsPath,
 			Scheme:      DefaultScrapeConfig.Scheme,
@@ -379,8 +437,11 @@ var expectedConf = &Config{
 		{
 			JobName: "service-ec2",
 
-			ScrapeInterval: model.Duration(15 * time.Second),
-			ScrapeTimeout:  DefaultGlobalConfig.ScrapeTimeout,
+			HonorTimestamps: true,
+			ScrapeJitter:    true,
+			ScrapeFormat:    ScrapeFormatPrometheus,
+			ScrapeInterval:  model.Duration(15 * time.Second),
+			ScrapeTimeout:   DefaultGlobalConfig.ScrapeTimeout,
 
 			MetricsPath: DefaultScrapeConfig.MetricsPath,
 			Scheme:      DefaultScrapeConfig.Scheme,
@@ -394,6 +455,16 @@ var expectedConf = &Config{
 						Profile:         "profile",
 						RefreshInterval: model.Duration(60 * time.Second),
 						Port:            80,
+						Filters: []*EC2Filter{
+							{
+								Name:   "tag:environment",
+								Values: []string{"prod"},
+							},
+							{
+								Name:   "instance-state-name",
+								Values: []string{"running", "pending"},
+							},
+						},
 					},
 				},
 			},
@@ -401,8 +472,11 @@ var expectedConf = &Config{
 		{
 			JobName: "service-azure",
 
-			ScrapeInterval: model.Duration(15 * time.Second),
-			ScrapeTimeout:  DefaultGlobalConfig.ScrapeTimeout,
+			HonorTimestamps: true,
+			ScrapeJitter:    true,
+			ScrapeFormat:    ScrapeFormatPrometheus,
+			ScrapeInterval:  model.Duration(15 * time.Second),
+			ScrapeTimeout:   DefaultGlobalConfig.ScrapeTimeout,
 
 			MetricsPath: DefaultScrapeConfig.MetricsPath,
 			Scheme:      DefaultScrapeConfig.Scheme,
@@ -423,8 +497,11 @@ var expectedConf = &Config{
 		{
 			JobName: "service-nerve",
 
-			ScrapeInterval: model.Duration(15 * time.Second),
-			ScrapeTimeout:  DefaultGlobalConfig.ScrapeTimeout,
+			HonorTimestamps: true,
+			ScrapeJitter:    true,
+			ScrapeFormat:    ScrapeFormatPrometheus,
+			ScrapeInterval:  model.Duration(15 * time.Second),
+			ScrapeTimeout:   DefaultGlobalConfig.ScrapeTimeout,
 
 			MetricsPath: DefaultScrapeConfig.MetricsPath,
 			Scheme:      DefaultScrapeConfig.Scheme,
@@ -442,8 +519,11 @@ var expectedConf = &Config{
 		{
 			JobName: "0123service-xxx",
 
-			ScrapeInterval: model.Duration(15 * time.Second),
-			ScrapeTimeout:  DefaultGlobalConfig.ScrapeTimeout,
+			HonorTimestamps: true,
+			ScrapeJitter:    true,
+			ScrapeFormat:    ScrapeFormatPrometheus,
+			ScrapeInterval:  model.Duration(15 * time.Second),
+			ScrapeTimeout:   DefaultGlobalConfig.ScrapeTimeout,
 
 			MetricsPath: DefaultScrapeConfig.MetricsPath,
 			Scheme:      DefaultScrapeConfig.Scheme,
@@ -461,8 +541,11 @@ var expectedConf = &Config{
 		{
 			JobName: "測試",
 
-			ScrapeInterval: model.Duration(15 * time.Second),
-			ScrapeTimeout:  DefaultGlobalConfig.ScrapeTimeout,
+			HonorTimestamps: true,
+			ScrapeJitter:    true,
+			ScrapeFormat:    ScrapeFormatPrometheus,
+			ScrapeInterval:  model.Duration(15 * time.Second),
+			ScrapeTimeout:   DefaultGlobalConfig.ScrapeTimeout,
 
 			MetricsPath: DefaultScrapeConfig.MetricsPath,
 			Scheme:      DefaultScrapeConfig.Scheme,
@@ -480,8 +563,11 @@ var expectedConf = &Config{
 		{
 			JobName: "service-triton",
 
-			ScrapeInterval: model.Duration(15 * time.Second),
-			ScrapeTimeout:  DefaultGlobalConfig.ScrapeTimeout,
+			HonorTimestamps: true,
+			ScrapeJitter:    true,
+			ScrapeFormat:    ScrapeFormatPrometheus,
+			ScrapeInterval:  model.Duration(15 * time.Second),
+			ScrapeTimeout:   DefaultGlobalConfig.ScrapeTimeout,
 
 			MetricsPath: DefaultScrapeConfig.MetricsPath,
 			Scheme:      DefaultScrapeConfig.Scheme,
@@ -506,10 +592,16 @@ var expectedConf = &Config{
 		},
 	},
 	AlertingConfig: AlertingConfig{
+		Replica: AlertReplicaConfig{
+			Labels:              model.LabelSet{"replica": "a"},
+			DropFromIdentity:    true,
+			TimestampAnnotation: "sent_at",
+		},
 		AlertmanagerConfigs: []*AlertmanagerConfig{
 			{
-				Scheme:  "https",
-				Timeout: 10 * time.Second,
+				Scheme:     "https",
+				Timeout:    10 * time.Second,
+				APIVersion: AlertmanagerAPIVersionAuto,
 				ServiceDiscoveryConfig: ServiceDiscoveryConfig{
 					StaticConfigs: []*TargetGroup{
 						{
@@ -557,6 +649,44 @@ func TestElideSecrets(t *testing.T) {
 		"yaml marshal reveals authentication credentials.")
 }
 
+// Settings that differ from a true default must survive YAML marshalling.
+func TestMarshalEnableHTTP2(t *testing.T) {
+	c, err := LoadFile("testdata/conf.good.yml")
+	testutil.Ok(t, err)
+	testutil.Assert(t, !c.RemoteReadConfigs[1].HTTPClientConfig.EnableHTTP2, "expected HTTP/2 to be disabled")
+
+	out, err := yaml.Marshal(c)
+	testutil.Ok(t, err)
+	got, err := Load(string(out))
+	testutil.Ok(t, err)
+	testutil.Equals(t, c.RemoteReadConfigs[0].HTTPClientConfig.EnableHTTP2, got.RemoteReadConfigs[0].HTTPClientConfig.EnableHTTP2)
+	testutil.Equals(t, c.RemoteReadConfigs[1].HTTPClientConfig.EnableHTTP2, got.RemoteReadConfigs[1].HTTPClientConfig.EnableHTTP2)
+}
+
+func TestReadSecret(t *testing.T) {
+	f, err := ioutil.TempFile("", "secret")
+	testutil.Ok(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString("filesecret\n")
+	testutil.Ok(t, err)
+	testutil.Ok(t, f.Close())
+
+	s, err := ReadSecret("mysecret", f.Name())
+	testutil.Ok(t, err)
+	testutil.Equals(t, "mysecret", s)
+
+	s, err = ReadSecret("", f.Name())
+	testutil.Ok(t, err)
+	testutil.Equals(t, "filesecret", s)
+
+	s, err = ReadSecret("", "")
+	testutil.Ok(t, err)
+	testutil.Equals(t, "", s)
+
+	_, err = ReadSecret("", "testdata/missing.secret")
+	testutil.NotOk(t, err)
+}
+
 func TestLoadConfigRuleFilesAbsolutePath(t *testing.T) {
 	// Parse a valid file that sets a rule files with an absolute path
 	c, err := LoadFile(ruleFilesConfigFile)
@@ -621,6 +751,21 @@ var expectedErrors = []struct {
 	}, {
 		filename: "labeldrop5.bad.yml",
 		errMsg:   "labeldrop action requires only 'regex', and no other fields",
+	}, {
+		filename: "lowercase_target_label.bad.yml",
+		errMsg:   `"${1}" is invalid 'target_label' for lowercase action`,
+	}, {
+		filename: "uppercase_target_label_missing.bad.yml",
+		errMsg:   "relabel configuration for uppercase action requires 'target_label' value",
+	}, {
+		filename: "keepequal.bad.yml",
+		errMsg:   "keepequal action requires only 'source_labels', 'separator' and 'target_label', and no other fields",
+	}, {
+		filename: "dropequal.bad.yml",
+		errMsg:   "dropequal action requires only 'source_labels', 'separator' and 'target_label', and no other fields",
+	}, {
+		filename: "labelmap_replacement.bad.yml",
+		errMsg:   `"meta-${1}" is invalid 'replacement' for labelmap action`,
 	}, {
 		filename: "rules.bad.yml",
 		errMsg:   "invalid rule file path",
@@ -633,6 +778,15 @@ var expectedErrors = []struct {
 	}, {
 		filename: "bearertoken_basicauth.bad.yml",
 		errMsg:   "at most one of basic_auth, bearer_token & bearer_token_file must be configured",
+	}, {
+		filename: "basicauth_password_file.bad.yml",
+		errMsg:   "at most one of password & password_file must be configured",
+	}, {
+		filename: "consul_token_file.bad.yml",
+		errMsg:   "at most one of token & token_file must be configured",
+	}, {
+		filename: "azure_client_secret_file.bad.yml",
+		errMsg:   "at most one of client_secret & client_secret_file must be configured",
 	}, {
 		filename: "kubernetes_bearertoken.bad.yml",
 		errMsg:   "at most one of bearer_token & bearer_token_file must be configured",
@@ -642,6 +796,21 @@ var expectedErrors = []struct {
 	}, {
 		filename: "kubernetes_namespace_discovery.bad.yml",
 		errMsg:   "unknown fields in namespaces",
+	}, {
+		filename: "ec2_filters_empty_values.bad.yml",
+		errMsg:   `EC2 SD filter "tag:environment" requires at least one value`,
+	}, {
+		filename: "kubernetes_selectors_role.bad.yml",
+		errMsg:   `selector role "service" is not allowed for Kubernetes SD role "pod"`,
+	}, {
+		filename: "kubernetes_selectors_duplicate.bad.yml",
+		errMsg:   `duplicate selector role "pod"`,
+	}, {
+		filename: "kubernetes_selectors_label.bad.yml",
+		errMsg:   `invalid label selector "app in (frontend" for role "pod"`,
+	}, {
+		filename: "kubernetes_selectors_field.bad.yml",
+		errMsg:   `invalid field selector "metadata.name" for role "pod"`,
 	}, {
 		filename: "kubernetes_bearertoken_basicauth.bad.yml",
 		errMsg:   "at most one of basic_auth, bearer_token & bearer_token_file must be configured",
@@ -666,6 +835,30 @@ var expectedErrors = []struct {
 	}, {
 		filename: "remote_write_url_missing.bad.yml",
 		errMsg:   `url for remote_write is empty`,
+	}, {
+		filename: "remote_write_metadata_interval.bad.yml",
+		errMsg:   `send_interval for metadata_config must be positive`,
+	}, {
+		filename: "remote_write_reserved_header.bad.yml",
+		errMsg:   `Authorization is a reserved header and cannot be configured`,
+	}, {
+		filename: "remote_read_reserved_header.bad.yml",
+		errMsg:   `x-prometheus-remote-read-version is a reserved header and cannot be configured`,
+	}, {
+		filename: "alert_replica_drop.bad.yml",
+		errMsg:   "drop_from_identity requires replica labels",
+	}, {
+		filename: "alertmanager_api_version.bad.yml",
+		errMsg:   `unknown Alertmanager API version "v3"`,
+	}, {
+		filename: "scrape_format.bad.yml",
+		errMsg:   `unknown scrape format "protobuf"`,
+	}, {
+		filename: "tls_min_version.bad.yml",
+		errMsg:   `unknown TLS version "TLS9"`,
+	}, {
+		filename: "tls_renegotiation.bad.yml",
+		errMsg:   `unknown TLS renegotiation setting "always"`,
 	},
 }
 
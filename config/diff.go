@@ -0,0 +1,154 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// ChangeKind describes how a section of the configuration changed.
+type ChangeKind string
+
+// The kinds of changes of a configuration section.
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeChanged ChangeKind = "changed"
+)
+
+// Change is a section of the configuration that differs between two
+// configurations.
+type Change struct {
+	// Section is the key of the section in the configuration file.
+	Section string `json:"section"`
+	// Name identifies the entry of a list section. It is the job name of
+	// scrape configurations and the index of remote write and read
+	// configurations.
+	Name string     `json:"name,omitempty"`
+	Kind ChangeKind `json:"kind"`
+}
+
+func (c Change) String() string {
+	if c.Name == "" {
+		return fmt.Sprintf("%s %s", c.Section, c.Kind)
+	}
+	return fmt.Sprintf("%s[%s] %s", c.Section, c.Name, c.Kind)
+}
+
+// Diff returns the changes from the old to the new configuration in the
+// order of the sections in the configuration file. If old is nil, all
+// sections set in the new configuration are reported as added.
+func Diff(old, new *Config) []Change {
+	if old == nil {
+		old = &Config{}
+	}
+	var changes []Change
+
+	diffSection := func(section string, o, n interface{}) {
+		zero := reflect.Zero(reflect.TypeOf(o)).Interface()
+		switch {
+		case reflect.DeepEqual(o, n):
+		case reflect.DeepEqual(o, zero):
+			changes = append(changes, Change{Section: section, Kind: ChangeAdded})
+		case reflect.DeepEqual(n, zero):
+			changes = append(changes, Change{Section: section, Kind: ChangeRemoved})
+		default:
+			changes = append(changes, Change{Section: section, Kind: ChangeChanged})
+		}
+	}
+	// diffList compares the entries of a list section by name. The
+	// entries are given as maps from names to values, and names lists the
+	// names of both in order of appearance.
+	diffList := func(section string, names []string, o, n map[string]interface{}) {
+		for _, name := range names {
+			ov, inOld := o[name]
+			nv, inNew := n[name]
+			switch {
+			case !inOld:
+				changes = append(changes, Change{Section: section, Name: name, Kind: ChangeAdded})
+			case !inNew:
+				changes = append(changes, Change{Section: section, Name: name, Kind: ChangeRemoved})
+			case !reflect.DeepEqual(ov, nv):
+				changes = append(changes, Change{Section: section, Name: name, Kind: ChangeChanged})
+			}
+		}
+	}
+
+	diffSection("global", old.GlobalConfig, new.GlobalConfig)
+	diffSection("alerting", old.AlertingConfig, new.AlertingConfig)
+	diffSection("rule_files", old.RuleFiles, new.RuleFiles)
+
+	var (
+		names  []string
+		oldScr = map[string]interface{}{}
+		newScr = map[string]interface{}{}
+	)
+	for _, c := range new.ScrapeConfigs {
+		names = append(names, c.JobName)
+		newScr[c.JobName] = c
+	}
+	for _, c := range old.ScrapeConfigs {
+		if _, ok := newScr[c.JobName]; !ok {
+			names = append(names, c.JobName)
+		}
+		oldScr[c.JobName] = c
+	}
+	diffList("scrape_configs", names, oldScr, newScr)
+
+	oldRW := make([]interface{}, 0, len(old.RemoteWriteConfigs))
+	for _, c := range old.RemoteWriteConfigs {
+		oldRW = append(oldRW, c)
+	}
+	newRW := make([]interface{}, 0, len(new.RemoteWriteConfigs))
+	for _, c := range new.RemoteWriteConfigs {
+		newRW = append(newRW, c)
+	}
+	names, oldByIndex, newByIndex := indexNames(oldRW, newRW)
+	diffList("remote_write", names, oldByIndex, newByIndex)
+
+	oldRR := make([]interface{}, 0, len(old.RemoteReadConfigs))
+	for _, c := range old.RemoteReadConfigs {
+		oldRR = append(oldRR, c)
+	}
+	newRR := make([]interface{}, 0, len(new.RemoteReadConfigs))
+	for _, c := range new.RemoteReadConfigs {
+		newRR = append(newRR, c)
+	}
+	names, oldByIndex, newByIndex = indexNames(oldRR, newRR)
+	diffList("remote_read", names, oldByIndex, newByIndex)
+
+	return changes
+}
+
+// indexNames maps the entries of two lists by their index for diffing.
+func indexNames(o, n []interface{}) ([]string, map[string]interface{}, map[string]interface{}) {
+	var (
+		names []string
+		om    = make(map[string]interface{}, len(o))
+		nm    = make(map[string]interface{}, len(n))
+	)
+	for i := 0; i < len(o) || i < len(n); i++ {
+		name := strconv.Itoa(i)
+		names = append(names, name)
+		if i < len(o) {
+			om[name] = o[i]
+		}
+		if i < len(n) {
+			nm[name] = n[i]
+		}
+	}
+	return names, om, nm
+}
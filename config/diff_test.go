@@ -0,0 +1,70 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/util/testutil"
+)
+
+func TestDiff(t *testing.T) {
+	oldConf, err := Load(`
+global:
+  scrape_interval: 15s
+rule_files:
+  - first.rules
+scrape_configs:
+  - job_name: unchanged
+  - job_name: changed
+  - job_name: removed
+remote_write:
+  - url: http://remote1/push
+  - url: http://remote2/push
+`)
+	testutil.Ok(t, err)
+
+	newConf, err := Load(`
+global:
+  scrape_interval: 15s
+rule_files:
+  - first.rules
+  - second.rules
+scrape_configs:
+  - job_name: added
+  - job_name: unchanged
+  - job_name: changed
+    metrics_path: /other
+remote_write:
+  - url: http://remote1/push
+remote_read:
+  - url: http://remote1/read
+`)
+	testutil.Ok(t, err)
+
+	testutil.Equals(t, []Change{
+		{Section: "rule_files", Kind: ChangeChanged},
+		{Section: "scrape_configs", Name: "added", Kind: ChangeAdded},
+		{Section: "scrape_configs", Name: "changed", Kind: ChangeChanged},
+		{Section: "scrape_configs", Name: "removed", Kind: ChangeRemoved},
+		{Section: "remote_write", Name: "1", Kind: ChangeRemoved},
+		{Section: "remote_read", Name: "0", Kind: ChangeAdded},
+	}, Diff(oldConf, newConf))
+
+	testutil.Equals(t, []Change(nil), Diff(newConf, newConf))
+
+	changes := Diff(nil, oldConf)
+	testutil.Equals(t, Change{Section: "global", Kind: ChangeAdded}, changes[0])
+	testutil.Equals(t, "scrape_configs[unchanged] added", changes[2].String())
+}
@@ -0,0 +1,124 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+// SDConfig is the configuration of a service discovery mechanism that is
+// not built into this package but registered with RegisterSDConfig.
+type SDConfig interface {
+	// Name returns the name of the mechanism. Its configurations are read
+	// from the <name>_sd_configs block.
+	Name() string
+}
+
+var (
+	sdConfigsMtx sync.RWMutex
+	// Constructors of empty registered configurations by block name.
+	sdConfigs = map[string]func() SDConfig{}
+)
+
+// sdConfigKey returns the name of the configuration block of the named
+// service discovery mechanism.
+func sdConfigKey(name string) string {
+	return name + "_sd_configs"
+}
+
+// RegisterSDConfig registers a service discovery configuration so that its
+// <name>_sd_configs block is accepted in scrape and Alertmanager configurations.
+// newConfig must return a pointer to a new configuration with its default
+// values set, which the YAML block is unmarshalled into.
+//
+// It panics if the name is already taken by a built-in or registered mechanism.
+// It is meant to be called from init functions.
+func RegisterSDConfig(name string, newConfig func() SDConfig) {
+	sdConfigsMtx.Lock()
+	defer sdConfigsMtx.Unlock()
+
+	key := sdConfigKey(name)
+	if _, ok := sdConfigs[key]; ok {
+		panic(fmt.Sprintf("service discovery config %q registered twice", name))
+	}
+	t := reflect.TypeOf(ServiceDiscoveryConfig{})
+	for i := 0; i < t.NumField(); i++ {
+		if strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0] == key {
+			panic(fmt.Sprintf("service discovery config %q is built in", name))
+		}
+	}
+	sdConfigs[key] = newConfig
+}
+
+// unmarshalRegistered moves the blocks of registered service discovery
+// mechanisms from the fields caught by xxx into the configuration.
+func (c *ServiceDiscoveryConfig) unmarshalRegistered(xxx map[string]interface{}) error {
+	sdConfigsMtx.RLock()
+	defer sdConfigsMtx.RUnlock()
+
+	// Sort the blocks so that the order of the resulting configurations
+	// does not depend on map iteration.
+	keys := make([]string, 0, len(xxx))
+	for key := range xxx {
+		if _, ok := sdConfigs[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		items, ok := xxx[key].([]interface{})
+		if !ok && xxx[key] != nil {
+			return fmt.Errorf("%s must be a list", key)
+		}
+		for _, item := range items {
+			b, err := yaml.Marshal(item)
+			if err != nil {
+				return err
+			}
+			cfg := sdConfigs[key]()
+			if err := yaml.Unmarshal(b, cfg); err != nil {
+				return fmt.Errorf("parsing %s: %s", key, err)
+			}
+			c.RegisteredSDConfigs = append(c.RegisteredSDConfigs, cfg)
+		}
+		delete(xxx, key)
+	}
+	return nil
+}
+
+// marshalRegistered returns the fields caught by xxx together with the blocks
+// of the registered service discovery configurations, which are not
+// marshalled otherwise.
+func (c *ServiceDiscoveryConfig) marshalRegistered(xxx map[string]interface{}) map[string]interface{} {
+	if len(c.RegisteredSDConfigs) == 0 {
+		return xxx
+	}
+	m := make(map[string]interface{}, len(xxx)+1)
+	for k, v := range xxx {
+		m[k] = v
+	}
+	for _, cfg := range c.RegisteredSDConfigs {
+		key := sdConfigKey(cfg.Name())
+		cfgs, _ := m[key].([]SDConfig)
+		m[key] = append(cfgs, cfg)
+	}
+	return m
+}
@@ -0,0 +1,91 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/prometheus/util/testutil"
+	"gopkg.in/yaml.v2"
+)
+
+type testSDConfig struct {
+	Server string `yaml:"server"`
+	Port   int    `yaml:"port,omitempty"`
+
+	XXX map[string]interface{} `yaml:",inline"`
+}
+
+func (*testSDConfig) Name() string { return "test" }
+
+func (c *testSDConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain testSDConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	return checkOverflow(c.XXX, "test_sd_config")
+}
+
+func init() {
+	RegisterSDConfig("test", func() SDConfig { return &testSDConfig{Port: 80} })
+}
+
+func TestRegisteredSDConfigs(t *testing.T) {
+	c, err := Load(`
+scrape_configs:
+- job_name: prometheus
+  test_sd_configs:
+  - server: a
+  - server: b
+    port: 8080
+alerting:
+  alertmanagers:
+  - test_sd_configs:
+    - server: c
+`)
+	testutil.Ok(t, err)
+
+	testutil.Equals(t, []SDConfig{
+		&testSDConfig{Server: "a", Port: 80},
+		&testSDConfig{Server: "b", Port: 8080},
+	}, c.ScrapeConfigs[0].ServiceDiscoveryConfig.RegisteredSDConfigs)
+	testutil.Equals(t, []SDConfig{
+		&testSDConfig{Server: "c", Port: 80},
+	}, c.AlertingConfig.AlertmanagerConfigs[0].ServiceDiscoveryConfig.RegisteredSDConfigs)
+
+	// Registered configurations survive marshalling.
+	out, err := yaml.Marshal(c)
+	testutil.Ok(t, err)
+	c2, err := Load(string(out))
+	testutil.Ok(t, err)
+	testutil.Equals(t, c.ScrapeConfigs[0].ServiceDiscoveryConfig, c2.ScrapeConfigs[0].ServiceDiscoveryConfig)
+	testutil.Equals(t, c.AlertingConfig.AlertmanagerConfigs[0].ServiceDiscoveryConfig, c2.AlertingConfig.AlertmanagerConfigs[0].ServiceDiscoveryConfig)
+
+	_, err = Load(`
+scrape_configs:
+- job_name: prometheus
+  test_sd_configs:
+  - server: a
+    unknown: b
+`)
+	testutil.Assert(t, err != nil && strings.Contains(err.Error(), "unknown fields in test_sd_config: unknown"), "expected parsing error but got %v", err)
+}
+
+func TestRegisterSDConfigBuiltIn(t *testing.T) {
+	defer func() {
+		testutil.Assert(t, recover() != nil, "expected panic registering a built-in service discovery config")
+	}()
+	RegisterSDConfig("consul", func() SDConfig { return &testSDConfig{} })
+}
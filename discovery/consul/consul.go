@@ -93,7 +93,10 @@ type Discovery struct {
 	clientConf       *consul.Config
 	clientDatacenter string
 	tagSeparator     string
-	watchedServices  []string // Set of services which will be discovered.
+	watchedServices  []string          // Set of services which will be discovered.
+	watchedTags      []string          // Tags that all discovered service instances must have.
+	watchedNodeMeta  map[string]string // Node metadata that all discovered nodes must have.
+	allowStale       bool
 	logger           log.Logger
 }
 
@@ -119,14 +122,22 @@ func NewDiscovery(conf *config.ConsulSDConfig, logger log.Logger) (*Discovery, e
 		Timeout:   35 * time.Second,
 	}
 
+	token, err := config.ReadSecret(conf.Token, conf.TokenFile)
+	if err != nil {
+		return nil, err
+	}
+	password, err := config.ReadSecret(conf.Password, conf.PasswordFile)
+	if err != nil {
+		return nil, err
+	}
 	clientConf := &consul.Config{
 		Address:    conf.Server,
 		Scheme:     conf.Scheme,
 		Datacenter: conf.Datacenter,
-		Token:      string(conf.Token),
+		Token:      token,
 		HttpAuth: &consul.HttpBasicAuth{
 			Username: conf.Username,
-			Password: string(conf.Password),
+			Password: password,
 		},
 		HttpClient: wrapper,
 	}
@@ -139,14 +150,22 @@ func NewDiscovery(conf *config.ConsulSDConfig, logger log.Logger) (*Discovery, e
 		clientConf:       clientConf,
 		tagSeparator:     conf.TagSeparator,
 		watchedServices:  conf.Services,
+		watchedTags:      conf.ServiceTags,
+		watchedNodeMeta:  conf.NodeMeta,
+		allowStale:       conf.AllowStale,
 		clientDatacenter: clientConf.Datacenter,
 		logger:           logger,
 	}
 	return cd, nil
 }
 
-// shouldWatch returns whether the service of the given name should be watched.
-func (d *Discovery) shouldWatch(name string) bool {
+// shouldWatch returns whether the service of the given name and tags should be watched.
+func (d *Discovery) shouldWatch(name string, tags []string) bool {
+	return d.shouldWatchFromName(name) && hasAllTags(tags, d.watchedTags)
+}
+
+// shouldWatchFromName returns whether the service of the given name should be watched.
+func (d *Discovery) shouldWatchFromName(name string) bool {
 	// If there's no fixed set of watched services, we watch everything.
 	if len(d.watchedServices) == 0 {
 		return true
@@ -159,6 +178,34 @@ func (d *Discovery) shouldWatch(name string) bool {
 	return false
 }
 
+// hasAllTags returns whether all wanted tags are contained in tags.
+func hasAllTags(tags, wanted []string) bool {
+	for _, w := range wanted {
+		found := false
+		for _, t := range tags {
+			if t == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// queryOptions returns the options for a blocking query waiting for changes
+// after the given index.
+func (d *Discovery) queryOptions(index uint64) *consul.QueryOptions {
+	return &consul.QueryOptions{
+		WaitIndex:  index,
+		WaitTime:   watchTimeout,
+		AllowStale: d.allowStale,
+		NodeMeta:   d.watchedNodeMeta,
+	}
+}
+
 // Run implements the TargetProvider interface.
 func (d *Discovery) Run(ctx context.Context, ch chan<- []*config.TargetGroup) {
 	// Watched services and their cancelation functions.
@@ -168,10 +215,7 @@ func (d *Discovery) Run(ctx context.Context, ch chan<- []*config.TargetGroup) {
 	for {
 		catalog := d.client.Catalog()
 		t0 := time.Now()
-		srvs, meta, err := catalog.Services(&consul.QueryOptions{
-			WaitIndex: lastIndex,
-			WaitTime:  watchTimeout,
-		})
+		srvs, meta, err := catalog.Services(d.queryOptions(lastIndex))
 		rpcDuration.WithLabelValues("catalog", "services").Observe(time.Since(t0).Seconds())
 
 		// We have to check the context at least once. The checks during channel sends
@@ -207,8 +251,8 @@ func (d *Discovery) Run(ctx context.Context, ch chan<- []*config.TargetGroup) {
 		}
 
 		// Check for new services.
-		for name := range srvs {
-			if !d.shouldWatch(name) {
+		for name, tags := range srvs {
+			if !d.shouldWatch(name, tags) {
 				continue
 			}
 			if _, ok := services[name]; ok {
@@ -216,8 +260,9 @@ func (d *Discovery) Run(ctx context.Context, ch chan<- []*config.TargetGroup) {
 			}
 
 			srv := &consulService{
-				client: d.client,
-				name:   name,
+				discovery: d,
+				client:    d.client,
+				name:      name,
 				labels: model.LabelSet{
 					serviceLabel:    model.LabelValue(name),
 					datacenterLabel: model.LabelValue(d.clientDatacenter),
@@ -234,7 +279,7 @@ func (d *Discovery) Run(ctx context.Context, ch chan<- []*config.TargetGroup) {
 
 		// Check for removed services.
 		for name, cancel := range services {
-			if _, ok := srvs[name]; !ok {
+			if tags, ok := srvs[name]; !ok || !d.shouldWatch(name, tags) {
 				// Call the watch cancelation function.
 				cancel()
 				delete(services, name)
@@ -252,6 +297,7 @@ func (d *Discovery) Run(ctx context.Context, ch chan<- []*config.TargetGroup) {
 
 // consulService contains data belonging to the same service.
 type consulService struct {
+	discovery    *Discovery
 	name         string
 	labels       model.LabelSet
 	client       *consul.Client
@@ -265,10 +311,13 @@ func (srv *consulService) watch(ctx context.Context, ch chan<- []*config.TargetG
 	lastIndex := uint64(0)
 	for {
 		t0 := time.Now()
-		nodes, meta, err := catalog.Service(srv.name, "", &consul.QueryOptions{
-			WaitIndex: lastIndex,
-			WaitTime:  watchTimeout,
-		})
+		// Consul filters by a single tag only, the remaining ones are
+		// checked for each node below.
+		var tag string
+		if len(srv.discovery.watchedTags) > 0 {
+			tag = srv.discovery.watchedTags[0]
+		}
+		nodes, meta, err := catalog.Service(srv.name, tag, srv.discovery.queryOptions(lastIndex))
 		rpcDuration.WithLabelValues("catalog", "service").Observe(time.Since(t0).Seconds())
 
 		// Check the context before potentially falling in a continue-loop.
@@ -298,6 +347,9 @@ func (srv *consulService) watch(ctx context.Context, ch chan<- []*config.TargetG
 		}
 
 		for _, node := range nodes {
+			if !hasAllTags(node.ServiceTags, srv.discovery.watchedTags) {
+				continue
+			}
 
 			// We surround the separated list with the separator as well. This way regular expressions
 			// in relabeling rules don't have to consider tag positions.
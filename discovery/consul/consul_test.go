@@ -14,8 +14,14 @@
 package consul
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/config"
 )
 
@@ -27,10 +33,10 @@ func TestConfiguredService(t *testing.T) {
 	if err != nil {
 		t.Errorf("Unexpected error when initialising discovery %v", err)
 	}
-	if !consulDiscovery.shouldWatch("configuredServiceName") {
+	if !consulDiscovery.shouldWatch("configuredServiceName", nil) {
 		t.Errorf("Expected service %s to be watched", "configuredServiceName")
 	}
-	if consulDiscovery.shouldWatch("nonConfiguredServiceName") {
+	if consulDiscovery.shouldWatch("nonConfiguredServiceName", nil) {
 		t.Errorf("Expected service %s to not be watched", "nonConfiguredServiceName")
 	}
 }
@@ -42,7 +48,98 @@ func TestNonConfiguredService(t *testing.T) {
 	if err != nil {
 		t.Errorf("Unexpected error when initialising discovery %v", err)
 	}
-	if !consulDiscovery.shouldWatch("nonConfiguredServiceName") {
+	if !consulDiscovery.shouldWatch("nonConfiguredServiceName", nil) {
 		t.Errorf("Expected service %s to be watched", "nonConfiguredServiceName")
 	}
 }
+
+func TestConfiguredServiceWithTags(t *testing.T) {
+	conf := &config.ConsulSDConfig{
+		Services:    []string{"configuredServiceName"},
+		ServiceTags: []string{"http", "v1"},
+	}
+	consulDiscovery, err := NewDiscovery(conf, nil)
+
+	if err != nil {
+		t.Errorf("Unexpected error when initialising discovery %v", err)
+	}
+	if !consulDiscovery.shouldWatch("configuredServiceName", []string{"v1", "http", "other"}) {
+		t.Errorf("Expected service %s with all tags to be watched", "configuredServiceName")
+	}
+	if consulDiscovery.shouldWatch("configuredServiceName", []string{"http"}) {
+		t.Errorf("Expected service %s with missing tags to not be watched", "configuredServiceName")
+	}
+	if consulDiscovery.shouldWatch("nonConfiguredServiceName", []string{"http", "v1"}) {
+		t.Errorf("Expected service %s to not be watched", "nonConfiguredServiceName")
+	}
+}
+
+func TestWatchFiltersNodes(t *testing.T) {
+	quit := make(chan struct{})
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("node-meta") != "rack:1" {
+			t.Errorf("Unexpected node-meta filter %q", q.Get("node-meta"))
+		}
+		if _, ok := q["stale"]; !ok {
+			t.Errorf("Expected stale query")
+		}
+		if q.Get("index") == "1" {
+			// Block until the test is done.
+			select {
+			case <-r.Context().Done():
+			case <-quit:
+			}
+			return
+		}
+		w.Header().Set("X-Consul-Index", "1")
+		switch r.URL.Path {
+		case "/v1/catalog/services":
+			fmt.Fprint(w, `{"web": ["http", "canary"], "db": ["sql"]}`)
+		case "/v1/catalog/service/web":
+			if q.Get("tag") != "http" {
+				t.Errorf("Unexpected tag filter %q", q.Get("tag"))
+			}
+			fmt.Fprint(w, `[
+				{"Node": "a", "Address": "10.0.0.1", "ServiceID": "web-1", "ServicePort": 80, "ServiceTags": ["http", "canary"]},
+				{"Node": "b", "Address": "10.0.0.2", "ServiceID": "web-2", "ServicePort": 80, "ServiceTags": ["http"]}
+			]`)
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer stub.Close()
+	defer close(quit)
+
+	conf := &config.ConsulSDConfig{
+		Server:      stub.URL[len("http://"):],
+		Datacenter:  "dc1",
+		ServiceTags: []string{"http", "canary"},
+		NodeMeta:    map[string]string{"rack": "1"},
+		AllowStale:  true,
+	}
+	d, err := NewDiscovery(conf, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error when initialising discovery %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := make(chan []*config.TargetGroup)
+	go d.Run(ctx, ch)
+
+	select {
+	case tgs := <-ch:
+		if len(tgs) != 1 || tgs[0].Source != "web" {
+			t.Fatalf("Unexpected target groups %v", tgs)
+		}
+		if len(tgs[0].Targets) != 1 {
+			t.Fatalf("Expected 1 target but got %d", len(tgs[0].Targets))
+		}
+		if addr := tgs[0].Targets[0][model.AddressLabel]; addr != "10.0.0.1:80" {
+			t.Fatalf("Unexpected target address %q", addr)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for targets")
+	}
+}
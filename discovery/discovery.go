@@ -96,7 +96,12 @@ func ProvidersFromConfig(cfg config.ServiceDiscoveryConfig, logger log.Logger) m
 		app("nerve", i, zookeeper.NewNerveDiscovery(c, log.With(logger, "discovery", "nerve")))
 	}
 	for i, c := range cfg.EC2SDConfigs {
-		app("ec2", i, ec2.NewDiscovery(c, log.With(logger, "discovery", "ec2")))
+		e, err := ec2.NewDiscovery(c, log.With(logger, "discovery", "ec2"))
+		if err != nil {
+			level.Error(logger).Log("msg", "Cannot create EC2 discovery", "err", err)
+			continue
+		}
+		app("ec2", i, e)
 	}
 	for i, c := range cfg.OpenstackSDConfigs {
 		openstackd, err := openstack.NewDiscovery(c, log.With(logger, "discovery", "openstack"))
@@ -126,6 +131,19 @@ func ProvidersFromConfig(cfg config.ServiceDiscoveryConfig, logger log.Logger) m
 		}
 		app("triton", i, t)
 	}
+	for i, c := range cfg.RegisteredSDConfigs {
+		rc, ok := c.(Config)
+		if !ok {
+			level.Error(logger).Log("msg", "Service discovery config does not provide a target provider", "discovery", c.Name())
+			continue
+		}
+		tp, err := rc.NewTargetProvider(log.With(logger, "discovery", c.Name()))
+		if err != nil {
+			level.Error(logger).Log("msg", "Cannot create service discovery", "discovery", c.Name(), "err", err)
+			continue
+		}
+		app(c.Name(), i, tp)
+	}
 	if len(cfg.StaticConfigs) > 0 {
 		app("static", 0, NewStaticProvider(cfg.StaticConfigs))
 	}
@@ -139,12 +157,16 @@ type StaticProvider struct {
 }
 
 // NewStaticProvider returns a StaticProvider configured with the given
-// target groups.
+// target groups. The groups are copied so that the configuration they stem
+// from is left unchanged.
 func NewStaticProvider(groups []*config.TargetGroup) *StaticProvider {
+	tgs := make([]*config.TargetGroup, 0, len(groups))
 	for i, tg := range groups {
-		tg.Source = fmt.Sprintf("%d", i)
+		c := *tg
+		c.Source = fmt.Sprintf("%d", i)
+		tgs = append(tgs, &c)
 	}
-	return &StaticProvider{groups}
+	return &StaticProvider{tgs}
 }
 
 // Run implements the TargetProvider interface.
@@ -33,25 +33,37 @@ import (
 const (
 	resolvConf = "/etc/resolv.conf"
 
-	dnsNameLabel = model.MetaLabelPrefix + "dns_name"
+	dnsNameLabel            = model.MetaLabelPrefix + "dns_name"
+	dnsSrvRecordPrefix      = model.MetaLabelPrefix + "dns_srv_record_"
+	dnsSrvRecordTargetLabel = dnsSrvRecordPrefix + "target"
+	dnsSrvRecordPortLabel   = dnsSrvRecordPrefix + "port"
+	dnsMxRecordTargetLabel  = model.MetaLabelPrefix + "dns_mx_record_target"
+
+	// maxBackoffIntervals limits the number of refresh intervals for which
+	// lookups of names that do not exist are suspended.
+	maxBackoffIntervals = 32
 
 	// Constants for instrumentation.
 	namespace = "prometheus"
 )
 
 var (
-	dnsSDLookupsCount = prometheus.NewCounter(
+	dnsSDLookupsCount = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: namespace,
 			Name:      "sd_dns_lookups_total",
 			Help:      "The number of DNS-SD lookups.",
-		})
-	dnsSDLookupFailuresCount = prometheus.NewCounter(
+		},
+		[]string{"name", "type"},
+	)
+	dnsSDLookupFailuresCount = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: namespace,
 			Name:      "sd_dns_lookup_failures_total",
 			Help:      "The number of DNS-SD lookup failures.",
-		})
+		},
+		[]string{"name", "type"},
+	)
 )
 
 func init() {
@@ -68,6 +80,19 @@ type Discovery struct {
 	port     int
 	qtype    uint16
 	logger   log.Logger
+	lookupFn func(name string, qtype uint16, logger log.Logger) (*dns.Msg, error)
+
+	mtx sync.Mutex
+	// Backoff state of names that did not exist when last looked up.
+	backoff map[string]*nxdomainBackoff
+}
+
+// nxdomainBackoff tracks the consecutive NXDOMAIN responses for a name.
+type nxdomainBackoff struct {
+	// Number of consecutive NXDOMAIN responses.
+	count int
+	// Number of refreshes to skip before the next lookup.
+	skip int
 }
 
 // NewDiscovery returns a new Discovery which periodically refreshes its targets.
@@ -82,6 +107,8 @@ func NewDiscovery(conf *config.DNSSDConfig, logger log.Logger) *Discovery {
 		qtype = dns.TypeA
 	case "AAAA":
 		qtype = dns.TypeAAAA
+	case "MX":
+		qtype = dns.TypeMX
 	case "SRV":
 		qtype = dns.TypeSRV
 	}
@@ -91,6 +118,8 @@ func NewDiscovery(conf *config.DNSSDConfig, logger log.Logger) *Discovery {
 		qtype:    qtype,
 		port:     conf.Port,
 		logger:   logger,
+		lookupFn: lookupWithSearchPath,
+		backoff:  map[string]*nxdomainBackoff{},
 	}
 }
 
@@ -115,8 +144,11 @@ func (d *Discovery) Run(ctx context.Context, ch chan<- []*config.TargetGroup) {
 func (d *Discovery) refreshAll(ctx context.Context, ch chan<- []*config.TargetGroup) {
 	var wg sync.WaitGroup
 
-	wg.Add(len(d.names))
 	for _, name := range d.names {
+		if d.skipRefresh(name) {
+			continue
+		}
+		wg.Add(1)
 		go func(n string) {
 			if err := d.refresh(ctx, n, ch); err != nil {
 				level.Error(d.logger).Log("msg", "Error refreshing DNS targets", "err", err)
@@ -128,13 +160,53 @@ func (d *Discovery) refreshAll(ctx context.Context, ch chan<- []*config.TargetGr
 	wg.Wait()
 }
 
+// skipRefresh returns whether the refresh of the name is suspended because
+// it did not exist in previous lookups.
+func (d *Discovery) skipRefresh(name string) bool {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	b, ok := d.backoff[name]
+	if !ok || b.skip == 0 {
+		return false
+	}
+	b.skip--
+	return true
+}
+
+// updateBackoff records the outcome of a successful lookup of the name. Each
+// consecutive NXDOMAIN response doubles the number of refresh intervals until
+// the name is looked up again, up to maxBackoffIntervals.
+func (d *Discovery) updateBackoff(name string, nxdomain bool) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if !nxdomain {
+		delete(d.backoff, name)
+		return
+	}
+	b, ok := d.backoff[name]
+	if !ok {
+		b = &nxdomainBackoff{}
+		d.backoff[name] = b
+	}
+	b.count++
+	intervals := maxBackoffIntervals
+	if b.count <= 5 {
+		intervals = 1 << uint(b.count-1)
+	}
+	b.skip = intervals - 1
+}
+
 func (d *Discovery) refresh(ctx context.Context, name string, ch chan<- []*config.TargetGroup) error {
-	response, err := lookupWithSearchPath(name, d.qtype, d.logger)
-	dnsSDLookupsCount.Inc()
+	qtype := dns.TypeToString[d.qtype]
+	response, err := d.lookupFn(name, d.qtype, d.logger)
+	dnsSDLookupsCount.WithLabelValues(name, qtype).Inc()
 	if err != nil {
-		dnsSDLookupFailuresCount.Inc()
+		dnsSDLookupFailuresCount.WithLabelValues(name, qtype).Inc()
 		return err
 	}
+	d.updateBackoff(name, response.Rcode == dns.RcodeNameError)
 
 	tg := &config.TargetGroup{}
 	hostPort := func(a string, p int) model.LabelValue {
@@ -142,24 +214,36 @@ func (d *Discovery) refresh(ctx context.Context, name string, ch chan<- []*confi
 	}
 
 	for _, record := range response.Answer {
-		target := model.LabelValue("")
+		var target, dnsSrvRecordTarget, dnsSrvRecordPort, dnsMxRecordTarget model.LabelValue
+
 		switch addr := record.(type) {
 		case *dns.SRV:
 			// Remove the final dot from rooted DNS names to make them look more usual.
 			addr.Target = strings.TrimRight(addr.Target, ".")
 
 			target = hostPort(addr.Target, int(addr.Port))
+			dnsSrvRecordTarget = model.LabelValue(addr.Target)
+			dnsSrvRecordPort = model.LabelValue(fmt.Sprintf("%d", addr.Port))
+		case *dns.MX:
+			// Remove the final dot from rooted DNS names to make them look more usual.
+			addr.Mx = strings.TrimRight(addr.Mx, ".")
+
+			target = hostPort(addr.Mx, d.port)
+			dnsMxRecordTarget = model.LabelValue(addr.Mx)
 		case *dns.A:
 			target = hostPort(addr.A.String(), d.port)
 		case *dns.AAAA:
 			target = hostPort(addr.AAAA.String(), d.port)
 		default:
-			level.Warn(d.logger).Log("msg", "Invalid SRV record", "record", record)
+			level.Warn(d.logger).Log("msg", "Invalid record", "record", record)
 			continue
 		}
 		tg.Targets = append(tg.Targets, model.LabelSet{
-			model.AddressLabel: target,
-			dnsNameLabel:       model.LabelValue(name),
+			model.AddressLabel:      target,
+			dnsNameLabel:            model.LabelValue(name),
+			dnsSrvRecordTargetLabel: dnsSrvRecordTarget,
+			dnsSrvRecordPortLabel:   dnsSrvRecordPort,
+			dnsMxRecordTargetLabel:  dnsMxRecordTarget,
 		})
 	}
 
@@ -226,7 +310,9 @@ func lookupWithSearchPath(name string, qtype uint16, logger log.Logger) (*dns.Ms
 
 	if allResponsesValid {
 		// Outcome 2: everyone says NXDOMAIN, that's good enough for me
-		return &dns.Msg{}, nil
+		msg := &dns.Msg{}
+		msg.Rcode = dns.RcodeNameError
+		return msg, nil
 	}
 	// Outcome 3: boned.
 	return nil, fmt.Errorf("could not resolve %q: all servers responded with errors to at least one search domain", name)
@@ -0,0 +1,180 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/miekg/dns"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/prometheus/config"
+)
+
+func TestDNSRefreshLabels(t *testing.T) {
+	cases := []struct {
+		qtype    string
+		port     int
+		answer   []dns.RR
+		expected []model.LabelSet
+	}{
+		{
+			qtype: "A",
+			port:  9100,
+			answer: []dns.RR{
+				&dns.A{A: net.IPv4(192, 0, 2, 2)},
+			},
+			expected: []model.LabelSet{
+				{
+					"__address__":                  "192.0.2.2:9100",
+					"__meta_dns_name":              "web.example.com.",
+					"__meta_dns_srv_record_target": "",
+					"__meta_dns_srv_record_port":   "",
+					"__meta_dns_mx_record_target":  "",
+				},
+			},
+		},
+		{
+			qtype: "SRV",
+			answer: []dns.RR{
+				&dns.SRV{Port: 3306, Target: "db1.example.com."},
+			},
+			expected: []model.LabelSet{
+				{
+					"__address__":                  "db1.example.com:3306",
+					"__meta_dns_name":              "web.example.com.",
+					"__meta_dns_srv_record_target": "db1.example.com",
+					"__meta_dns_srv_record_port":   "3306",
+					"__meta_dns_mx_record_target":  "",
+				},
+			},
+		},
+		{
+			qtype: "MX",
+			port:  25,
+			answer: []dns.RR{
+				&dns.MX{Preference: 10, Mx: "mail.example.com."},
+			},
+			expected: []model.LabelSet{
+				{
+					"__address__":                  "mail.example.com:25",
+					"__meta_dns_name":              "web.example.com.",
+					"__meta_dns_srv_record_target": "",
+					"__meta_dns_srv_record_port":   "",
+					"__meta_dns_mx_record_target":  "mail.example.com",
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		d := NewDiscovery(&config.DNSSDConfig{Names: []string{"web.example.com."}, Type: c.qtype, Port: c.port}, nil)
+		d.lookupFn = func(name string, qtype uint16, logger log.Logger) (*dns.Msg, error) {
+			return &dns.Msg{Answer: c.answer}, nil
+		}
+
+		ch := make(chan []*config.TargetGroup, 1)
+		if err := d.refresh(context.Background(), "web.example.com.", ch); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		tgs := <-ch
+		if !reflect.DeepEqual(c.expected, tgs[0].Targets) {
+			t.Fatalf("Unexpected targets for %s records: %v", c.qtype, tgs[0].Targets)
+		}
+	}
+}
+
+func TestDNSLookupMetrics(t *testing.T) {
+	fail := false
+
+	d := NewDiscovery(&config.DNSSDConfig{Names: []string{"metrics.example.com."}, Type: "A", Port: 80}, nil)
+	d.lookupFn = func(name string, qtype uint16, logger log.Logger) (*dns.Msg, error) {
+		if fail {
+			return nil, fmt.Errorf("lookup failed")
+		}
+		return &dns.Msg{}, nil
+	}
+
+	ch := make(chan []*config.TargetGroup, 10)
+	d.refresh(context.Background(), "metrics.example.com.", ch)
+	fail = true
+	d.refresh(context.Background(), "metrics.example.com.", ch)
+
+	for _, c := range []struct {
+		counter  *prometheus.CounterVec
+		expected float64
+	}{
+		{counter: dnsSDLookupsCount, expected: 2},
+		{counter: dnsSDLookupFailuresCount, expected: 1},
+	} {
+		m := &dto.Metric{}
+		if err := c.counter.WithLabelValues("metrics.example.com.", "A").Write(m); err != nil {
+			t.Fatal(err)
+		}
+		if v := m.GetCounter().GetValue(); v != c.expected {
+			t.Fatalf("Expected %v but got %v", c.expected, v)
+		}
+	}
+}
+
+func TestDNSNXDomainBackoff(t *testing.T) {
+	var lookups int
+	nxdomain := true
+
+	d := NewDiscovery(&config.DNSSDConfig{Names: []string{"gone.example.com."}, Type: "SRV"}, nil)
+	d.lookupFn = func(name string, qtype uint16, logger log.Logger) (*dns.Msg, error) {
+		lookups++
+		msg := &dns.Msg{}
+		if nxdomain {
+			msg.Rcode = dns.RcodeNameError
+		}
+		return msg, nil
+	}
+
+	ch := make(chan []*config.TargetGroup, 100)
+	refresh := func(n int) {
+		for i := 0; i < n; i++ {
+			d.refreshAll(context.Background(), ch)
+		}
+	}
+
+	// Consecutive NXDOMAIN responses double the intervals between lookups:
+	// 1, 2, 4, 8, 16 and finally 32 intervals.
+	refresh(1 + 1 + 2 + 4 + 8 + 16 + 32)
+	if lookups != 7 {
+		t.Fatalf("Expected 7 lookups but got %d", lookups)
+	}
+	refresh(32)
+	if lookups != 8 {
+		t.Fatalf("Expected backoff to be capped at %d intervals but got %d lookups", maxBackoffIntervals, lookups)
+	}
+
+	// Once the name exists again, it is looked up on every refresh.
+	nxdomain = false
+	refresh(32)
+	if lookups != 9 {
+		t.Fatalf("Expected 9 lookups but got %d", lookups)
+	}
+	refresh(3)
+	if lookups != 12 {
+		t.Fatalf("Expected 12 lookups but got %d", lookups)
+	}
+}
@@ -77,13 +77,18 @@ type Discovery struct {
 	profile  string
 	roleARN  string
 	port     int
+	filters  []*config.EC2Filter
 	logger   log.Logger
 }
 
 // NewDiscovery returns a new EC2Discovery which periodically refreshes its targets.
-func NewDiscovery(conf *config.EC2SDConfig, logger log.Logger) *Discovery {
-	creds := credentials.NewStaticCredentials(conf.AccessKey, string(conf.SecretKey), "")
-	if conf.AccessKey == "" && conf.SecretKey == "" {
+func NewDiscovery(conf *config.EC2SDConfig, logger log.Logger) (*Discovery, error) {
+	secretKey, err := config.ReadSecret(conf.SecretKey, conf.SecretKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	creds := credentials.NewStaticCredentials(conf.AccessKey, secretKey, "")
+	if conf.AccessKey == "" && secretKey == "" {
 		creds = nil
 	}
 	if logger == nil {
@@ -98,8 +103,9 @@ func NewDiscovery(conf *config.EC2SDConfig, logger log.Logger) *Discovery {
 		roleARN:  conf.RoleARN,
 		interval: time.Duration(conf.RefreshInterval),
 		port:     conf.Port,
+		filters:  conf.Filters,
 		logger:   logger,
-	}
+	}, nil
 }
 
 // Run implements the TargetProvider interface.
@@ -149,6 +155,18 @@ func (d *Discovery) ec2MetadataAvailable(sess *session.Session) (isAvailable boo
 	return isAvailable
 }
 
+// ec2Filters returns the configured filters in the form expected by the EC2 API.
+func (d *Discovery) ec2Filters() []*ec2.Filter {
+	var filters []*ec2.Filter
+	for _, f := range d.filters {
+		filters = append(filters, &ec2.Filter{
+			Name:   aws.String(f.Name),
+			Values: aws.StringSlice(f.Values),
+		})
+	}
+	return filters
+}
+
 func (d *Discovery) refresh() (tg *config.TargetGroup, err error) {
 	t0 := time.Now()
 	defer func() {
@@ -161,6 +179,9 @@ func (d *Discovery) refresh() (tg *config.TargetGroup, err error) {
 	sess, err := session.NewSessionWithOptions(session.Options{
 		Config:  *d.aws,
 		Profile: d.profile,
+		// Load the shared config file so that profiles assuming roles
+		// from other profiles can be resolved.
+		SharedConfigState: session.SharedConfigEnable,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("could not create aws session: %s", err)
@@ -181,7 +202,8 @@ func (d *Discovery) refresh() (tg *config.TargetGroup, err error) {
 	tg = &config.TargetGroup{
 		Source: *d.aws.Region,
 	}
-	if err = ec2s.DescribeInstancesPages(nil, func(p *ec2.DescribeInstancesOutput, lastPage bool) bool {
+	input := &ec2.DescribeInstancesInput{Filters: d.ec2Filters()}
+	if err = ec2s.DescribeInstancesPages(input, func(p *ec2.DescribeInstancesOutput, lastPage bool) bool {
 		for _, r := range p.Reservations {
 			for _, inst := range r.Instances {
 				if inst.PrivateIpAddress == nil {
@@ -19,8 +19,10 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-kit/kit/log"
@@ -46,11 +48,74 @@ var (
 			Name: "prometheus_sd_file_read_errors_total",
 			Help: "The number of File-SD read errors.",
 		})
+	fileSDTimeStamp = newTimestampCollector()
 )
 
 func init() {
 	prometheus.MustRegister(fileSDScanDuration)
 	prometheus.MustRegister(fileSDReadErrorsCount)
+	prometheus.MustRegister(fileSDTimeStamp)
+}
+
+// timestampCollector exposes the modification times of the files read by the
+// running file discoveries, so that those of files no longer read, because
+// they disappeared or their discovery stopped, are not exposed anymore.
+type timestampCollector struct {
+	desc *prometheus.Desc
+
+	mtx         sync.RWMutex
+	discoveries map[*Discovery]struct{}
+}
+
+func newTimestampCollector() *timestampCollector {
+	return &timestampCollector{
+		desc: prometheus.NewDesc(
+			"prometheus_sd_file_mtime_seconds",
+			"Timestamp (mtime) of files read by FileSD. Timestamp is set at read time.",
+			[]string{"filename"},
+			nil,
+		),
+		discoveries: map[*Discovery]struct{}{},
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *timestampCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector.
+func (c *timestampCollector) Collect(ch chan<- prometheus.Metric) {
+	// Several discoveries may read the same file.
+	mtimes := map[string]float64{}
+
+	c.mtx.RLock()
+	for d := range c.discoveries {
+		d.mtx.RLock()
+		for filename, mtime := range d.mtimes {
+			mtimes[filename] = mtime
+		}
+		d.mtx.RUnlock()
+	}
+	c.mtx.RUnlock()
+
+	for filename, mtime := range mtimes {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, mtime, filename)
+	}
+}
+
+func (c *timestampCollector) add(d *Discovery) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.discoveries[d] = struct{}{}
+}
+
+func (c *timestampCollector) remove(d *Discovery) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	delete(c.discoveries, d)
 }
 
 // Discovery provides service discovery functionality based
@@ -66,6 +131,10 @@ type Discovery struct {
 	// This is used to detect deleted target groups.
 	lastRefresh map[string]int
 	logger      log.Logger
+
+	mtx sync.RWMutex
+	// The modification times of the files read, in seconds.
+	mtimes map[string]float64
 }
 
 // NewDiscovery returns a new file discovery for the given paths.
@@ -77,9 +146,24 @@ func NewDiscovery(conf *config.FileSDConfig, logger log.Logger) *Discovery {
 		paths:    conf.Files,
 		interval: time.Duration(conf.RefreshInterval),
 		logger:   logger,
+		mtimes:   map[string]float64{},
 	}
 }
 
+func (d *Discovery) setMtime(filename string, mtime time.Time) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	d.mtimes[filename] = float64(mtime.Unix())
+}
+
+func (d *Discovery) deleteMtime(filename string) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	delete(d.mtimes, filename)
+}
+
 // listFiles returns a list of all files that match the configured patterns.
 func (d *Discovery) listFiles() []string {
 	var paths []string
@@ -123,6 +207,9 @@ func (d *Discovery) Run(ctx context.Context, ch chan<- []*config.TargetGroup) {
 	}
 	d.watcher = watcher
 
+	fileSDTimeStamp.add(d)
+	defer fileSDTimeStamp.remove(d)
+
 	d.refresh(ctx, ch)
 
 	ticker := time.NewTicker(d.interval)
@@ -198,7 +285,7 @@ func (d *Discovery) refresh(ctx context.Context, ch chan<- []*config.TargetGroup
 
 	ref := map[string]int{}
 	for _, p := range d.listFiles() {
-		tgroups, err := readFile(p)
+		tgroups, mtime, err := readFile(p)
 		if err != nil {
 			fileSDReadErrorsCount.Inc()
 
@@ -207,6 +294,8 @@ func (d *Discovery) refresh(ctx context.Context, ch chan<- []*config.TargetGroup
 			ref[p] = d.lastRefresh[p]
 			continue
 		}
+		d.setMtime(p, mtime)
+
 		select {
 		case ch <- tgroups:
 		case <-ctx.Done():
@@ -218,6 +307,9 @@ func (d *Discovery) refresh(ctx context.Context, ch chan<- []*config.TargetGroup
 	// Send empty updates for sources that disappeared.
 	for f, n := range d.lastRefresh {
 		m, ok := ref[f]
+		if !ok {
+			d.deleteMtime(f)
+		}
 		if !ok || n > m {
 			for i := m; i < n; i++ {
 				select {
@@ -239,11 +331,22 @@ func fileSource(filename string, i int) string {
 }
 
 // readFile reads a JSON or YAML list of targets groups from the file, depending on its
-// file extension. It returns full configuration target groups.
-func readFile(filename string) ([]*config.TargetGroup, error) {
-	content, err := ioutil.ReadFile(filename)
+// file extension. It returns full configuration target groups and the
+// modification time of the file.
+func readFile(filename string) ([]*config.TargetGroup, time.Time, error) {
+	fd, err := os.Open(filename)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer fd.Close()
+
+	content, err := ioutil.ReadAll(fd)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	info, err := fd.Stat()
 	if err != nil {
-		return nil, err
+		return nil, time.Time{}, err
 	}
 
 	var targetGroups []*config.TargetGroup
@@ -251,11 +354,11 @@ func readFile(filename string) ([]*config.TargetGroup, error) {
 	switch ext := filepath.Ext(filename); strings.ToLower(ext) {
 	case ".json":
 		if err := json.Unmarshal(content, &targetGroups); err != nil {
-			return nil, err
+			return nil, time.Time{}, err
 		}
 	case ".yml", ".yaml":
 		if err := yaml.Unmarshal(content, &targetGroups); err != nil {
-			return nil, err
+			return nil, time.Time{}, err
 		}
 	default:
 		panic(fmt.Errorf("retrieval.FileDiscovery.readFile: unhandled file extension %q", ext))
@@ -264,7 +367,7 @@ func readFile(filename string) ([]*config.TargetGroup, error) {
 	for i, tg := range targetGroups {
 		if tg == nil {
 			err = errors.New("nil target group item found")
-			return nil, err
+			return nil, time.Time{}, err
 		}
 
 		tg.Source = fileSource(filename, i)
@@ -273,5 +376,5 @@ func readFile(filename string) ([]*config.TargetGroup, error) {
 		}
 		tg.Labels[fileSDFilepathLabel] = model.LabelValue(filename)
 	}
-	return targetGroups, nil
+	return targetGroups, info.ModTime(), nil
 }
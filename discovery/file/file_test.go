@@ -16,11 +16,14 @@ package file
 import (
 	"context"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/model"
 
 	"github.com/prometheus/prometheus/config"
@@ -168,3 +171,90 @@ retry:
 	cancel()
 	<-drained
 }
+
+func TestFileSDMtime(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file_sd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	name := filepath.Join(dir, "targets.yml")
+	if err := ioutil.WriteFile(name, []byte("- targets: ['localhost:9090']\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Unix(1500000000, 0)
+	if err := os.Chtimes(name, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		fsd         = NewDiscovery(&config.FileSDConfig{Files: []string{filepath.Join(dir, "*.yml")}, RefreshInterval: model.Duration(time.Hour)}, nil)
+		ctx, cancel = context.WithCancel(context.Background())
+		ch          = make(chan []*config.TargetGroup)
+		done        = make(chan struct{})
+	)
+	go func() {
+		fsd.Run(ctx, ch)
+		close(done)
+	}()
+
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected target groups but got none")
+	}
+	if v, ok := collectMtimes(t)[name]; !ok || v != float64(mtime.Unix()) {
+		t.Fatalf("Expected mtime %d for %s but got %v", mtime.Unix(), name, v)
+	}
+
+	// Removing the file drops its mtime.
+	if err := os.Remove(name); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected target groups of the removed file but got none")
+	}
+	if _, ok := collectMtimes(t)[name]; ok {
+		t.Fatalf("Expected mtime of removed file %s to be deleted", name)
+	}
+
+	// Stopping the discovery drops the mtimes of the files it read.
+	if err := ioutil.WriteFile(name, []byte("- targets: ['localhost:9090']\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected target groups of the recreated file but got none")
+	}
+	if _, ok := collectMtimes(t)[name]; !ok {
+		t.Fatalf("Expected mtime of recreated file %s", name)
+	}
+	cancel()
+	<-done
+	if _, ok := collectMtimes(t)[name]; ok {
+		t.Fatalf("Expected mtime of %s to be deleted after the discovery stopped", name)
+	}
+}
+
+// collectMtimes returns the file mtimes currently exposed, by file name.
+func collectMtimes(t *testing.T) map[string]float64 {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		fileSDTimeStamp.Collect(ch)
+		close(ch)
+	}()
+
+	mtimes := map[string]float64{}
+	for metric := range ch {
+		m := &dto.Metric{}
+		if err := metric.Write(m); err != nil {
+			t.Fatal(err)
+		}
+		mtimes[m.GetLabel()[0].GetValue()] = m.GetGauge().GetValue()
+	}
+	return mtimes
+}
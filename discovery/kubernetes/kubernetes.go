@@ -23,6 +23,9 @@ import (
 	"github.com/go-kit/kit/log/level"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/pkg/api"
 	apiv1 "k8s.io/client-go/pkg/api/v1"
@@ -68,6 +71,14 @@ type Discovery struct {
 	role               config.KubernetesRole
 	logger             log.Logger
 	namespaceDiscovery *config.KubernetesNamespaceDiscovery
+	selectors          map[config.KubernetesRole]selector
+}
+
+// selector holds the label and field selectors restricting the objects
+// watched for a role.
+type selector struct {
+	label string
+	field string
 }
 
 func (d *Discovery) getNamespaces() []string {
@@ -132,13 +143,22 @@ func New(l log.Logger, conf *config.KubernetesSDConfig) (*Discovery, error) {
 		kcfg.BearerToken = token
 
 		if conf.BasicAuth != nil {
+			password, err := config.ReadSecret(conf.BasicAuth.Password, conf.BasicAuth.PasswordFile)
+			if err != nil {
+				return nil, err
+			}
 			kcfg.Username = conf.BasicAuth.Username
-			kcfg.Password = string(conf.BasicAuth.Password)
+			kcfg.Password = password
 		}
 	}
 
 	kcfg.UserAgent = "prometheus/discovery"
 
+	selectors := make(map[config.KubernetesRole]selector, len(conf.Selectors))
+	for _, sc := range conf.Selectors {
+		selectors[sc.Role] = selector{label: sc.Label, field: sc.Field}
+	}
+
 	c, err := kubernetes.NewForConfig(kcfg)
 	if err != nil {
 		return nil, err
@@ -148,9 +168,38 @@ func New(l log.Logger, conf *config.KubernetesSDConfig) (*Discovery, error) {
 		logger:             l,
 		role:               conf.Role,
 		namespaceDiscovery: &conf.NamespaceDiscovery,
+		selectors:          selectors,
 	}, nil
 }
 
+// listWatch returns a ListWatch for the resource of the given role in the
+// namespace, restricted by the selectors configured for the role.
+func (d *Discovery) listWatch(c cache.Getter, role config.KubernetesRole, resource, namespace string) *cache.ListWatch {
+	sel := d.selectors[role]
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = sel.label
+			options.FieldSelector = sel.field
+			return c.Get().
+				Namespace(namespace).
+				Resource(resource).
+				VersionedParams(&options, metav1.ParameterCodec).
+				Do().
+				Get()
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.Watch = true
+			options.LabelSelector = sel.label
+			options.FieldSelector = sel.field
+			return c.Get().
+				Namespace(namespace).
+				Resource(resource).
+				VersionedParams(&options, metav1.ParameterCodec).
+				Watch()
+		},
+	}
+}
+
 const resyncPeriod = 10 * time.Minute
 
 // Run implements the TargetProvider interface.
@@ -165,9 +214,9 @@ func (d *Discovery) Run(ctx context.Context, ch chan<- []*config.TargetGroup) {
 		var wg sync.WaitGroup
 
 		for _, namespace := range namespaces {
-			elw := cache.NewListWatchFromClient(rclient, "endpoints", namespace, nil)
-			slw := cache.NewListWatchFromClient(rclient, "services", namespace, nil)
-			plw := cache.NewListWatchFromClient(rclient, "pods", namespace, nil)
+			elw := d.listWatch(rclient, config.KubernetesRoleEndpoint, "endpoints", namespace)
+			slw := d.listWatch(rclient, config.KubernetesRoleService, "services", namespace)
+			plw := d.listWatch(rclient, config.KubernetesRolePod, "pods", namespace)
 			eps := NewEndpoints(
 				log.With(d.logger, "role", "endpoint"),
 				cache.NewSharedInformer(slw, &apiv1.Service{}, resyncPeriod),
@@ -197,7 +246,7 @@ func (d *Discovery) Run(ctx context.Context, ch chan<- []*config.TargetGroup) {
 	case "pod":
 		var wg sync.WaitGroup
 		for _, namespace := range namespaces {
-			plw := cache.NewListWatchFromClient(rclient, "pods", namespace, nil)
+			plw := d.listWatch(rclient, config.KubernetesRolePod, "pods", namespace)
 			pod := NewPod(
 				log.With(d.logger, "role", "pod"),
 				cache.NewSharedInformer(plw, &apiv1.Pod{}, resyncPeriod),
@@ -217,7 +266,7 @@ func (d *Discovery) Run(ctx context.Context, ch chan<- []*config.TargetGroup) {
 	case "service":
 		var wg sync.WaitGroup
 		for _, namespace := range namespaces {
-			slw := cache.NewListWatchFromClient(rclient, "services", namespace, nil)
+			slw := d.listWatch(rclient, config.KubernetesRoleService, "services", namespace)
 			svc := NewService(
 				log.With(d.logger, "role", "service"),
 				cache.NewSharedInformer(slw, &apiv1.Service{}, resyncPeriod),
@@ -237,7 +286,7 @@ func (d *Discovery) Run(ctx context.Context, ch chan<- []*config.TargetGroup) {
 	case "ingress":
 		var wg sync.WaitGroup
 		for _, namespace := range namespaces {
-			ilw := cache.NewListWatchFromClient(reclient, "ingresses", namespace, nil)
+			ilw := d.listWatch(reclient, config.KubernetesRoleIngress, "ingresses", namespace)
 			ingress := NewIngress(
 				log.With(d.logger, "role", "ingress"),
 				cache.NewSharedInformer(ilw, &extensionsv1beta1.Ingress{}, resyncPeriod),
@@ -255,7 +304,7 @@ func (d *Discovery) Run(ctx context.Context, ch chan<- []*config.TargetGroup) {
 		}
 		wg.Wait()
 	case "node":
-		nlw := cache.NewListWatchFromClient(rclient, "nodes", api.NamespaceAll, nil)
+		nlw := d.listWatch(rclient, config.KubernetesRoleNode, "nodes", api.NamespaceAll)
 		node := NewNode(
 			log.With(d.logger, "role", "node"),
 			cache.NewSharedInformer(nlw, &apiv1.Node{}, resyncPeriod),
@@ -0,0 +1,43 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/prometheus/config"
+)
+
+func TestNewSelectors(t *testing.T) {
+	u, err := url.Parse("https://localhost:1234")
+	require.NoError(t, err)
+
+	conf := &config.KubernetesSDConfig{
+		APIServer: config.URL{URL: u},
+		Role:      config.KubernetesRoleEndpoint,
+		Selectors: []config.KubernetesSelectorConfig{
+			{Role: config.KubernetesRolePod, Label: "app=frontend,tier!=cache"},
+			{Role: config.KubernetesRoleEndpoint, Field: "metadata.name!=kubernetes"},
+		},
+	}
+	d, err := New(nil, conf)
+	require.NoError(t, err)
+	require.Equal(t, map[config.KubernetesRole]selector{
+		config.KubernetesRolePod:      {label: "app=frontend,tier!=cache"},
+		config.KubernetesRoleEndpoint: {field: "metadata.name!=kubernetes"},
+	}, d.selectors)
+}
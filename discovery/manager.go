@@ -0,0 +1,351 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discovery
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/prometheus/prometheus/config"
+)
+
+// Manager maintains the target providers of a number of named target sets and
+// sends the target groups they discover through a channel, so that consumers
+// are decoupled from how their targets are discovered.
+//
+// Every update sent on the channel contains the full set of target groups of
+// all target sets, keyed by the name of the target set.
+type Manager struct {
+	logger log.Logger
+	ctx    context.Context
+
+	mtx  sync.Mutex
+	sets map[string]*discoverySet
+
+	// Updates are sent at most once per updatert.
+	updatert    time.Duration
+	triggerSend chan struct{}
+	syncCh      chan map[string][]*config.TargetGroup
+}
+
+// discoverySet holds the target providers of a target set and the target groups
+// they discovered.
+type discoverySet struct {
+	cfg    config.ServiceDiscoveryConfig
+	cancel func()
+	// Incremented whenever the providers are replaced so that updates from
+	// previous providers are ignored.
+	gen int
+	// The generation of providers whose target groups are sent.
+	published int
+	// Target groups by provider name and source. Nil until the providers
+	// delivered their initial target groups.
+	groups map[string]*config.TargetGroup
+	// State of the current providers by provider name.
+	providers map[string]*providerState
+}
+
+// ProviderStatus describes the state of a target provider of a target set.
+type ProviderStatus struct {
+	// Name of the target set the provider belongs to.
+	TargetSet string
+	// Name of the provider, made up of its mechanism and index, e.g. "consul/0".
+	Provider string
+	// Time of the last update received from the provider. Zero if none was
+	// received yet.
+	LastUpdate time.Time
+	// Number of updates received from the provider.
+	Updates int
+	// Number of times the provider did not deliver its initial target groups
+	// in time or stopped without delivering them.
+	Failures int
+	// Number of non-empty target groups and of targets last received from
+	// the provider.
+	TargetGroups int
+	Targets      int
+}
+
+// providerState tracks the status of a provider along with the number of
+// targets of its target groups by source.
+type providerState struct {
+	status  ProviderStatus
+	targets map[string]int
+}
+
+func (ps *providerState) update(tgs []*config.TargetGroup, now time.Time) {
+	ps.status.LastUpdate = now
+	ps.status.Updates++
+
+	for _, tg := range tgs {
+		if tg == nil {
+			continue
+		}
+		if len(tg.Targets) == 0 {
+			delete(ps.targets, tg.Source)
+		} else {
+			ps.targets[tg.Source] = len(tg.Targets)
+		}
+	}
+	ps.status.TargetGroups = len(ps.targets)
+	ps.status.Targets = 0
+	for _, n := range ps.targets {
+		ps.status.Targets += n
+	}
+}
+
+// NewManager returns a new discovery manager. Its target providers are stopped
+// once the context is canceled.
+func NewManager(ctx context.Context, logger log.Logger) *Manager {
+	if logger == nil {
+		logger = log.NewNopLogger()
+	}
+	return &Manager{
+		logger:      logger,
+		ctx:         ctx,
+		sets:        map[string]*discoverySet{},
+		updatert:    5 * time.Second,
+		triggerSend: make(chan struct{}, 1),
+		syncCh:      make(chan map[string][]*config.TargetGroup),
+	}
+}
+
+// Run sends the discovered target groups through the sync channel whenever
+// they changed. It blocks until the context of the manager is canceled.
+func (m *Manager) Run() error {
+	ticker := time.NewTicker(m.updatert)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return m.ctx.Err()
+		case <-ticker.C:
+			// Throttle updates to once per updatert.
+			select {
+			case <-m.triggerSend:
+			default:
+				continue
+			}
+			select {
+			case m.syncCh <- m.allGroups():
+			case <-m.ctx.Done():
+				return m.ctx.Err()
+			}
+		}
+	}
+}
+
+// SyncCh returns a read only channel used by all target sets to send their
+// discovered target groups.
+func (m *Manager) SyncCh() <-chan map[string][]*config.TargetGroup {
+	return m.syncCh
+}
+
+// ApplyConfig starts the target providers of new target sets and stops those
+// of removed ones. Target sets whose configuration did not change keep their
+// providers and target groups. Target sets with a changed configuration keep
+// their previous target groups until the new providers delivered theirs.
+func (m *Manager) ApplyConfig(cfg map[string]config.ServiceDiscoveryConfig) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	for name, ds := range m.sets {
+		if _, ok := cfg[name]; !ok {
+			ds.cancel()
+			delete(m.sets, name)
+			m.setTriggerSend()
+		}
+	}
+	for name, sdCfg := range cfg {
+		ds, ok := m.sets[name]
+		if ok && reflect.DeepEqual(ds.cfg, sdCfg) {
+			continue
+		}
+		if !ok {
+			ds = &discoverySet{}
+			m.sets[name] = ds
+		} else {
+			ds.cancel()
+		}
+		ds.cfg = sdCfg
+		m.startProviders(name, ds)
+	}
+	return nil
+}
+
+// startProviders starts the target providers of the target set. It must be
+// called with the manager's lock held.
+func (m *Manager) startProviders(name string, ds *discoverySet) {
+	ctx, cancel := context.WithCancel(m.ctx)
+	ds.cancel = cancel
+	ds.gen++
+
+	var (
+		gen    = ds.gen
+		groups = map[string]*config.TargetGroup{}
+		wg     sync.WaitGroup
+	)
+	ds.providers = map[string]*providerState{}
+
+	for pname, prov := range ProvidersFromConfig(ds.cfg, log.With(m.logger, "target_set", name)) {
+		ds.providers[pname] = &providerState{
+			status:  ProviderStatus{TargetSet: name, Provider: pname},
+			targets: map[string]int{},
+		}
+		wg.Add(1)
+
+		updates := make(chan []*config.TargetGroup)
+		go prov.Run(ctx, updates)
+
+		go func(pname string) {
+			select {
+			case <-ctx.Done():
+			case initial, ok := <-updates:
+				// Handle the case that a target provider exits and closes the channel
+				// before the context is done.
+				if ok {
+					m.update(ds, gen, groups, pname, initial)
+				} else {
+					m.providerFailed(ds, gen, pname)
+				}
+			case <-time.After(5 * time.Second):
+				// Initial set didn't arrive. Act as if it was empty
+				// and wait for updates later on.
+				m.providerFailed(ds, gen, pname)
+			}
+			wg.Done()
+
+			// Start listening for further updates.
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case tgs, ok := <-updates:
+					if !ok {
+						return
+					}
+					m.update(ds, gen, groups, pname, tgs)
+				}
+			}
+		}(pname)
+	}
+
+	// Publish the target groups of the new providers at once, once all of
+	// them delivered their initial target groups.
+	go func() {
+		wg.Wait()
+
+		m.mtx.Lock()
+		defer m.mtx.Unlock()
+
+		if ds.gen != gen {
+			return
+		}
+		ds.groups = groups
+		ds.published = gen
+		m.setTriggerSend()
+	}()
+}
+
+// update stores target groups received from a provider of the given generation
+// of the target set.
+func (m *Manager) update(ds *discoverySet, gen int, groups map[string]*config.TargetGroup, pname string, tgs []*config.TargetGroup) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if ds.gen != gen {
+		level.Debug(m.logger).Log("msg", "Discarding target groups of stopped provider", "provider", pname)
+		return
+	}
+	for _, tg := range tgs {
+		if tg != nil {
+			groups[pname+"/"+tg.Source] = tg
+		}
+	}
+	if ps, ok := ds.providers[pname]; ok {
+		ps.update(tgs, time.Now())
+	}
+	// Until published, the groups are sent together once all providers
+	// delivered their initial target groups.
+	if ds.published == gen {
+		m.setTriggerSend()
+	}
+}
+
+// providerFailed records that a provider of the given generation of the target
+// set failed to deliver target groups.
+func (m *Manager) providerFailed(ds *discoverySet, gen int, pname string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if ds.gen != gen {
+		return
+	}
+	if ps, ok := ds.providers[pname]; ok {
+		ps.status.Failures++
+	}
+}
+
+// ProviderStatus returns the status of the target providers of all target
+// sets, ordered by target set and provider name.
+func (m *Manager) ProviderStatus() []ProviderStatus {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	res := []ProviderStatus{}
+	for _, ds := range m.sets {
+		for _, ps := range ds.providers {
+			res = append(res, ps.status)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].TargetSet != res[j].TargetSet {
+			return res[i].TargetSet < res[j].TargetSet
+		}
+		return res[i].Provider < res[j].Provider
+	})
+	return res
+}
+
+// setTriggerSend signals that the target groups changed.
+func (m *Manager) setTriggerSend() {
+	select {
+	case m.triggerSend <- struct{}{}:
+	default:
+	}
+}
+
+func (m *Manager) allGroups() map[string][]*config.TargetGroup {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	tSets := make(map[string][]*config.TargetGroup, len(m.sets))
+	for name, ds := range m.sets {
+		if ds.groups == nil {
+			continue
+		}
+		tgs := make([]*config.TargetGroup, 0, len(ds.groups))
+		for _, tg := range ds.groups {
+			tgs = append(tgs, tg)
+		}
+		tSets[name] = tgs
+	}
+	return tSets
+}
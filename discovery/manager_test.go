@@ -0,0 +1,207 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discovery
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/config"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func mustLoadSDConfigs(t *testing.T, s string) map[string]config.ServiceDiscoveryConfig {
+	var cfg config.Config
+	if err := yaml.Unmarshal([]byte(s), &cfg); err != nil {
+		t.Fatalf("Unable to load YAML config: %s", err)
+	}
+	c := map[string]config.ServiceDiscoveryConfig{}
+	for _, scfg := range cfg.ScrapeConfigs {
+		c[scfg.JobName] = scfg.ServiceDiscoveryConfig
+	}
+	return c
+}
+
+// receiveTargets waits for the next update of the manager and returns the
+// sorted targets by target set.
+func receiveTargets(t *testing.T, m *Manager) map[string][]string {
+	select {
+	case tsets := <-m.SyncCh():
+		res := map[string][]string{}
+		for name, tgs := range tsets {
+			targets := []string{}
+			for _, tg := range tgs {
+				for _, t := range tg.Targets {
+					targets = append(targets, string(t["__address__"]))
+				}
+			}
+			sort.Strings(targets)
+			res[name] = targets
+		}
+		return res
+	case <-time.After(5 * time.Second):
+		t.Fatalf("No target groups received")
+	}
+	return nil
+}
+
+func TestManagerApplyConfig(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := NewManager(ctx, nil)
+	m.updatert = 10 * time.Millisecond
+	go m.Run()
+
+	const cfgText = `
+scrape_configs:
+ - job_name: 'prometheus'
+   static_configs:
+   - targets: ["foo:9090"]
+ - job_name: 'node'
+   static_configs:
+   - targets: ["foo:9100", "bar:9100"]
+`
+	if err := m.ApplyConfig(mustLoadSDConfigs(t, cfgText)); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string][]string{
+		"prometheus": {"foo:9090"},
+		"node":       {"bar:9100", "foo:9100"},
+	}
+	if res := receiveTargets(t, m); !reflect.DeepEqual(expected, res) {
+		t.Fatalf("Expected targets %v but got %v", expected, res)
+	}
+
+	// Applying an identical configuration, loaded anew, must keep the
+	// providers running.
+	gens := map[string]int{}
+	m.mtx.Lock()
+	for name, ds := range m.sets {
+		gens[name] = ds.gen
+	}
+	m.mtx.Unlock()
+
+	if err := m.ApplyConfig(mustLoadSDConfigs(t, cfgText)); err != nil {
+		t.Fatal(err)
+	}
+	m.mtx.Lock()
+	for name, ds := range m.sets {
+		if ds.gen != gens[name] {
+			t.Errorf("Providers of unchanged target set %q were restarted", name)
+		}
+	}
+	m.mtx.Unlock()
+
+	// Changed target sets are updated and removed ones are no longer sent.
+	if err := m.ApplyConfig(mustLoadSDConfigs(t, `
+scrape_configs:
+ - job_name: 'prometheus'
+   static_configs:
+   - targets: ["foo:9090", "bar:9090"]
+`)); err != nil {
+		t.Fatal(err)
+	}
+	expected = map[string][]string{
+		"prometheus": {"bar:9090", "foo:9090"},
+	}
+	if res := receiveTargets(t, m); !reflect.DeepEqual(expected, res) {
+		t.Fatalf("Expected targets %v but got %v", expected, res)
+	}
+}
+
+func TestManagerKeepsTargetGroupsUntilInitialSet(t *testing.T) {
+	m := NewManager(context.Background(), nil)
+
+	ds := &discoverySet{
+		gen:       1,
+		published: 1,
+		groups: map[string]*config.TargetGroup{
+			"static/0/0": {Source: "0"},
+		},
+	}
+	m.sets["job"] = ds
+
+	// While the new providers did not deliver their initial target groups,
+	// the previous ones are sent.
+	ds.gen = 2
+	m.update(ds, 2, map[string]*config.TargetGroup{}, "static/0", []*config.TargetGroup{{Source: "1"}})
+
+	tsets := m.allGroups()
+	if len(tsets["job"]) != 1 || tsets["job"][0].Source != "0" {
+		t.Fatalf("Expected previous target groups to be kept but got %v", tsets["job"])
+	}
+	select {
+	case <-m.triggerSend:
+		t.Fatalf("Unexpected update for unpublished target groups")
+	default:
+	}
+
+	// Updates of stopped providers are discarded.
+	m.update(ds, 1, ds.groups, "static/0", []*config.TargetGroup{{Source: "2"}})
+	if len(ds.groups) != 1 {
+		t.Fatalf("Expected updates of stopped providers to be discarded but got %v", ds.groups)
+	}
+}
+
+func TestManagerProviderStatus(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := NewManager(ctx, nil)
+	m.updatert = 10 * time.Millisecond
+	go m.Run()
+
+	if err := m.ApplyConfig(mustLoadSDConfigs(t, `
+scrape_configs:
+ - job_name: 'node'
+   static_configs:
+   - targets: ["foo:9100", "bar:9100"]
+   - targets: ["baz:9100"]
+`)); err != nil {
+		t.Fatal(err)
+	}
+	receiveTargets(t, m)
+
+	status := m.ProviderStatus()
+	if len(status) != 1 {
+		t.Fatalf("Expected status of 1 provider but got %v", status)
+	}
+	s := status[0]
+	if s.TargetSet != "node" || s.Provider != "static/0" {
+		t.Fatalf("Unexpected provider %s/%s", s.TargetSet, s.Provider)
+	}
+	if s.LastUpdate.IsZero() || s.Updates != 1 || s.Failures != 0 {
+		t.Fatalf("Unexpected update status %+v", s)
+	}
+	if s.TargetGroups != 2 || s.Targets != 3 {
+		t.Fatalf("Expected 2 target groups with 3 targets but got %+v", s)
+	}
+
+	// Target groups without targets are not counted.
+	m.mtx.Lock()
+	ds := m.sets["node"]
+	gen := ds.gen
+	m.mtx.Unlock()
+	m.update(ds, gen, ds.groups, "static/0", []*config.TargetGroup{{Source: "1"}})
+	m.providerFailed(ds, gen, "static/0")
+
+	s = m.ProviderStatus()[0]
+	if s.Updates != 2 || s.Failures != 1 || s.TargetGroups != 1 || s.Targets != 2 {
+		t.Fatalf("Unexpected status after update %+v", s)
+	}
+}
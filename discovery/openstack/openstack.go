@@ -61,11 +61,15 @@ func NewDiscovery(conf *config.OpenstackSDConfig, l log.Logger) (Discovery, erro
 			return nil, err
 		}
 	} else {
+		password, err := config.ReadSecret(conf.Password, conf.PasswordFile)
+		if err != nil {
+			return nil, err
+		}
 		opts = gophercloud.AuthOptions{
 			IdentityEndpoint: conf.IdentityEndpoint,
 			Username:         conf.Username,
 			UserID:           conf.UserID,
-			Password:         string(conf.Password),
+			Password:         password,
 			TenantName:       conf.ProjectName,
 			TenantID:         conf.ProjectID,
 			DomainName:       conf.DomainName,
@@ -0,0 +1,100 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discovery
+
+import (
+	"reflect"
+
+	"github.com/go-kit/kit/log"
+
+	"github.com/prometheus/prometheus/config"
+)
+
+// Config is the configuration of a service discovery mechanism that is
+// compiled in by registering it with RegisterConfig instead of being built
+// into the config package.
+type Config interface {
+	config.SDConfig
+
+	// NewTargetProvider returns a TargetProvider for the configuration.
+	NewTargetProvider(logger log.Logger) (TargetProvider, error)
+}
+
+// RegisterConfig registers the service discovery mechanism of the given
+// configuration. Its configurations are read from the <name>_sd_configs block
+// of scrape and Alertmanager configurations into new values of the same type
+// as c, which must be a pointer. Deep copies of the values of c serve as
+// defaults.
+//
+// It panics if the name is already taken. It is meant to be called from init
+// functions.
+func RegisterConfig(c Config) {
+	t := reflect.TypeOf(c)
+	if t.Kind() != reflect.Ptr {
+		panic("discovery: registered config must be a pointer")
+	}
+	config.RegisterSDConfig(c.Name(), func() config.SDConfig {
+		return copyValue(reflect.ValueOf(c)).Interface().(config.SDConfig)
+	})
+}
+
+// copyValue returns a deep copy of v, so that configurations do not share
+// slices, maps and pointers with the registered defaults they are read into.
+// Unexported struct fields are copied shallowly.
+func copyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(copyValue(v.Elem()))
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(copyValue(v.Elem()))
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(copyValue(v.Field(i)))
+			}
+		}
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(copyValue(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMap(v.Type())
+		for _, k := range v.MapKeys() {
+			c.SetMapIndex(k, copyValue(v.MapIndex(k)))
+		}
+		return c
+	}
+	return v
+}
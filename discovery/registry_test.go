@@ -0,0 +1,82 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discovery
+
+import (
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/prometheus/config"
+)
+
+type registeredConfig struct {
+	Targets []string          `yaml:"targets"`
+	Port    string            `yaml:"port"`
+	Labels  map[string]string `yaml:"labels,omitempty"`
+}
+
+func (*registeredConfig) Name() string { return "registered" }
+
+func (c *registeredConfig) NewTargetProvider(log.Logger) (TargetProvider, error) {
+	tg := &config.TargetGroup{}
+	for _, t := range c.Targets {
+		tg.Targets = append(tg.Targets, model.LabelSet{
+			model.AddressLabel: model.LabelValue(t + ":" + c.Port),
+		})
+	}
+	return NewStaticProvider([]*config.TargetGroup{tg}), nil
+}
+
+func init() {
+	RegisterConfig(&registeredConfig{Port: "9090", Labels: map[string]string{"env": "prod"}})
+}
+
+func TestRegisteredConfigProviders(t *testing.T) {
+	cfg, err := config.Load(`
+scrape_configs:
+- job_name: prometheus
+  registered_sd_configs:
+  - targets: [a, b]
+  - targets: [c]
+    port: "80"
+    labels:
+      team: a
+`)
+	if err != nil {
+		t.Fatalf("Unexpected error loading config: %s", err)
+	}
+
+	providers := ProvidersFromConfig(cfg.ScrapeConfigs[0].ServiceDiscoveryConfig, nil)
+	if len(providers) != 2 {
+		t.Fatalf("Expected 2 providers but got %d", len(providers))
+	}
+	p, ok := providers["registered/1"].(*StaticProvider)
+	if !ok {
+		t.Fatalf("Expected static provider for registered/1 but got %T", providers["registered/1"])
+	}
+	if addr := p.TargetGroups[0].Targets[0][model.AddressLabel]; addr != "c:80" {
+		t.Fatalf("Expected target address c:80 but got %q", addr)
+	}
+	p = providers["registered/0"].(*StaticProvider)
+	if addr := p.TargetGroups[0].Targets[1][model.AddressLabel]; addr != "b:9090" {
+		t.Fatalf("Expected default port to be applied but got %q", addr)
+	}
+
+	// Configurations must not share the defaults they are read into.
+	if labels := cfg.ScrapeConfigs[0].ServiceDiscoveryConfig.RegisteredSDConfigs[0].(*registeredConfig).Labels; len(labels) != 1 {
+		t.Fatalf("Expected only the default labels but got %v", labels)
+	}
+}
@@ -18,13 +18,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"net/url"
 	"path"
 	"strings"
 	"sync"
-	"sync/atomic"
 	"time"
 
 	"github.com/go-kit/kit/log"
@@ -35,15 +36,18 @@ import (
 	"golang.org/x/net/context/ctxhttp"
 
 	"github.com/prometheus/prometheus/config"
-	"github.com/prometheus/prometheus/discovery"
 	"github.com/prometheus/prometheus/pkg/labels"
 	"github.com/prometheus/prometheus/pkg/relabel"
 	"github.com/prometheus/prometheus/util/httputil"
 )
 
 const (
-	alertPushEndpoint = "/api/v1/alerts"
-	contentTypeJSON   = "application/json"
+	alertPushEndpointV1 = "/api/v1/alerts"
+	alertPushEndpointV2 = "/api/v2/alerts"
+	contentTypeJSON     = "application/json"
+
+	// Maximum number of bytes of an error response included in the error.
+	maxErrMsgLen = 256
 )
 
 // String constants for instrumentation.
@@ -101,21 +105,40 @@ func (a *Alert) ResolvedAt(ts time.Time) bool {
 }
 
 // Notifier is responsible for dispatching alert notifications to an
-// alert manager service.
+// alert manager service. Every Alertmanager has its own queue, so that an
+// Alertmanager failing to receive notifications does not delay the others.
 type Notifier struct {
-	queue []*Alert
-	opts  *Options
+	opts *Options
 
 	metrics *alertMetrics
 
-	more   chan struct{}
-	mtx    sync.RWMutex
-	ctx    context.Context
-	cancel func()
+	// Closed once the notifier runs, no notifications are sent before.
+	running chan struct{}
+	mtx     sync.RWMutex
+	ctx     context.Context
+	cancel  func()
+
+	alertmanagers []*alertmanagerSet
+	cancelSets    func()
+	// The target groups last received for the Alertmanager sets.
+	targetSets map[string][]*config.TargetGroup
+	// Alerts sent while no Alertmanager is discovered. They are queued for
+	// the Alertmanagers once there are any.
+	pending []*Alert
+
+	// The number of queues holding each alert, so that an alert that no
+	// Alertmanager received is counted as dropped once.
+	refsMtx sync.Mutex
+	refs    map[*Alert]*alertRef
 
-	alertmanagers   []*alertmanagerSet
-	cancelDiscovery func()
-	logger          log.Logger
+	logger log.Logger
+	now    func() time.Time
+}
+
+// alertRef tracks the delivery of an alert queued for several Alertmanagers.
+type alertRef struct {
+	held int
+	sent bool
 }
 
 // Options are the configurable parameters of a Handler.
@@ -123,6 +146,8 @@ type Options struct {
 	QueueCapacity  int
 	ExternalLabels model.LabelSet
 	RelabelConfigs []*config.RelabelConfig
+	// Metadata of the alerts for the deduplication by Alertmanager.
+	Replica config.AlertReplicaConfig
 	// Used for sending HTTP requests to the Alertmanager.
 	Do func(ctx old_ctx.Context, client *http.Client, req *http.Request) (*http.Response, error)
 
@@ -133,8 +158,11 @@ type alertMetrics struct {
 	latency                 *prometheus.SummaryVec
 	errors                  *prometheus.CounterVec
 	sent                    *prometheus.CounterVec
+	retries                 *prometheus.CounterVec
 	dropped                 prometheus.Counter
+	amDropped               *prometheus.CounterVec
 	queueLength             prometheus.GaugeFunc
+	amQueueLength           *prometheus.GaugeVec
 	queueCapacity           prometheus.Gauge
 	alertmanagersDiscovered prometheus.GaugeFunc
 }
@@ -165,23 +193,47 @@ func newAlertMetrics(r prometheus.Registerer, queueCap int, queueLen, alertmanag
 		},
 			[]string{alertmanagerLabel},
 		),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "retries_total",
+			Help:      "Total number of retries sending alert notifications.",
+		},
+			[]string{alertmanagerLabel},
+		),
 		dropped: prometheus.NewCounter(prometheus.CounterOpts{
 			Namespace: namespace,
 			Subsystem: subsystem,
 			Name:      "dropped_total",
 			Help:      "Total number of alerts dropped due to errors when sending to Alertmanager.",
 		}),
+		amDropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "alertmanager_dropped_total",
+			Help:      "Total number of alerts dropped per Alertmanager due to errors or a full queue.",
+		},
+			[]string{alertmanagerLabel},
+		),
 		queueLength: prometheus.NewGaugeFunc(prometheus.GaugeOpts{
 			Namespace: namespace,
 			Subsystem: subsystem,
 			Name:      "queue_length",
-			Help:      "The number of alert notifications in the queue.",
+			Help:      "The number of alert notifications in the queues of all Alertmanagers.",
 		}, queueLen),
+		amQueueLength: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "alertmanager_queue_length",
+			Help:      "The number of alert notifications in the queue of an Alertmanager.",
+		},
+			[]string{alertmanagerLabel},
+		),
 		queueCapacity: prometheus.NewGauge(prometheus.GaugeOpts{
 			Namespace: namespace,
 			Subsystem: subsystem,
 			Name:      "queue_capacity",
-			Help:      "The capacity of the alert notifications queue.",
+			Help:      "The capacity of the alert notifications queue of each Alertmanager.",
 		}),
 		alertmanagersDiscovered: prometheus.NewGaugeFunc(prometheus.GaugeOpts{
 			Name: "prometheus_notifications_alertmanagers_discovered",
@@ -196,8 +248,11 @@ func newAlertMetrics(r prometheus.Registerer, queueCap int, queueLen, alertmanag
 			m.latency,
 			m.errors,
 			m.sent,
+			m.retries,
 			m.dropped,
+			m.amDropped,
 			m.queueLength,
+			m.amQueueLength,
 			m.queueCapacity,
 			m.alertmanagersDiscovered,
 		)
@@ -218,12 +273,13 @@ func New(o *Options, logger log.Logger) *Notifier {
 	}
 
 	n := &Notifier{
-		queue:  make([]*Alert, 0, o.QueueCapacity),
-		ctx:    ctx,
-		cancel: cancel,
-		more:   make(chan struct{}, 1),
-		opts:   o,
-		logger: logger,
+		ctx:     ctx,
+		cancel:  cancel,
+		running: make(chan struct{}),
+		refs:    map[*Alert]*alertRef{},
+		opts:    o,
+		logger:  logger,
+		now:     time.Now,
 	}
 
 	queueLenFunc := func() float64 { return float64(n.queueLen()) }
@@ -246,85 +302,155 @@ func (n *Notifier) ApplyConfig(conf *config.Config) error {
 
 	n.opts.ExternalLabels = conf.GlobalConfig.ExternalLabels
 	n.opts.RelabelConfigs = conf.AlertingConfig.AlertRelabelConfigs
+	n.opts.Replica = conf.AlertingConfig.Replica
 
 	amSets := []*alertmanagerSet{}
 	ctx, cancel := context.WithCancel(n.ctx)
 
-	for _, cfg := range conf.AlertingConfig.AlertmanagerConfigs {
-		ams, err := newAlertmanagerSet(cfg, n.logger)
+	for i, cfg := range conf.AlertingConfig.AlertmanagerConfigs {
+		ams, err := newAlertmanagerSet(ctx, alertmanagerSetName(i), cfg, n, n.logger)
 		if err != nil {
+			cancel()
 			return err
 		}
 
-		ams.metrics = n.metrics
-
 		amSets = append(amSets, ams)
 	}
 
-	// After all sets were created successfully, start them and cancel the
-	// old ones.
+	// After all sets were created successfully, sync them with the target
+	// groups known so far and cancel the old ones. The alerts not yet sent
+	// by the old sets are moved to the new ones.
 	for _, ams := range amSets {
-		go ams.ts.Run(ctx)
-		ams.ts.UpdateProviders(discovery.ProvidersFromConfig(ams.cfg.ServiceDiscoveryConfig, n.logger))
+		if tgs, ok := n.targetSets[ams.name]; ok {
+			ams.Sync(tgs)
+		}
 	}
-	if n.cancelDiscovery != nil {
-		n.cancelDiscovery()
+	var held []*Alert
+	for _, ams := range n.alertmanagers {
+		held = append(held, ams.drain()...)
+	}
+	if n.cancelSets != nil {
+		n.cancelSets()
 	}
 
-	n.cancelDiscovery = cancel
+	n.cancelSets = cancel
 	n.alertmanagers = amSets
 
+	n.flushPending()
+	n.enqueue(uniqueAlerts(held))
+	n.release(held, false)
+
 	return nil
 }
 
-const maxBatchSize = 64
+// uniqueAlerts returns the alerts without duplicates, keeping the first
+// occurrence of each.
+func uniqueAlerts(alerts []*Alert) []*Alert {
+	seen := make(map[*Alert]struct{}, len(alerts))
+	res := make([]*Alert, 0, len(alerts))
+	for _, a := range alerts {
+		if _, ok := seen[a]; ok {
+			continue
+		}
+		seen[a] = struct{}{}
+		res = append(res, a)
+	}
+	return res
+}
+
+// alertmanagerSetName returns the name of the target set of the i-th
+// Alertmanager configuration.
+func alertmanagerSetName(i int) string {
+	return fmt.Sprintf("config-%d", i)
+}
+
+// DiscoveryConfigs returns the service discovery configurations of the
+// Alertmanagers in conf by the names of the target sets whose target groups
+// are expected by Run.
+func DiscoveryConfigs(conf *config.Config) map[string]config.ServiceDiscoveryConfig {
+	c := make(map[string]config.ServiceDiscoveryConfig, len(conf.AlertingConfig.AlertmanagerConfigs))
+	for i, amcfg := range conf.AlertingConfig.AlertmanagerConfigs {
+		c[alertmanagerSetName(i)] = amcfg.ServiceDiscoveryConfig
+	}
+	return c
+}
+
+const (
+	maxBatchSize = 64
+	// Number of times sending a batch of alerts to an Alertmanager is
+	// attempted before the batch is dropped.
+	maxSendAttempts = 3
+	// Time to wait before the first retry, doubled with every retry.
+	retryBackoff = 500 * time.Millisecond
+)
 
 func (n *Notifier) queueLen() int {
 	n.mtx.RLock()
-	defer n.mtx.RUnlock()
+	l := len(n.pending)
+	n.mtx.RUnlock()
 
-	return len(n.queue)
+	for _, q := range n.queues() {
+		l += q.len()
+	}
+	return l
 }
 
-func (n *Notifier) nextBatch() []*Alert {
-	n.mtx.Lock()
-	defer n.mtx.Unlock()
+// queues returns the queues of all discovered Alertmanagers.
+func (n *Notifier) queues() []*sendQueue {
+	n.mtx.RLock()
+	amSets := n.alertmanagers
+	n.mtx.RUnlock()
 
-	var alerts []*Alert
+	return setQueues(amSets)
+}
 
-	if len(n.queue) > maxBatchSize {
-		alerts = append(make([]*Alert, 0, maxBatchSize), n.queue[:maxBatchSize]...)
-		n.queue = n.queue[maxBatchSize:]
-	} else {
-		alerts = append(make([]*Alert, 0, len(n.queue)), n.queue...)
-		n.queue = n.queue[:0]
+// setQueues returns the queues of the discovered Alertmanagers of the sets.
+func setQueues(amSets []*alertmanagerSet) []*sendQueue {
+	var res []*sendQueue
+	for _, ams := range amSets {
+		ams.mtx.RLock()
+		for _, am := range ams.ams {
+			if q, ok := ams.queues[am.url().String()]; ok {
+				res = append(res, q)
+			}
+		}
+		ams.mtx.RUnlock()
 	}
-
-	return alerts
+	return res
 }
 
-// Run dispatches notifications continuously.
-func (n *Notifier) Run() {
+// Run dispatches notifications continuously until the notifier is stopped.
+// The Alertmanagers to send to are discovered from the target groups received
+// through tsets, keyed by the target set names of DiscoveryConfigs.
+func (n *Notifier) Run(tsets <-chan map[string][]*config.TargetGroup) {
+	close(n.running)
+
 	for {
 		select {
 		case <-n.ctx.Done():
 			return
-		case <-n.more:
+		case ts := <-tsets:
+			n.reload(ts)
 		}
-		alerts := n.nextBatch()
+	}
+}
 
-		if !n.sendAll(alerts...) {
-			n.metrics.dropped.Add(float64(len(alerts)))
-		}
-		// If the queue still has items left, kick off the next iteration.
-		if n.queueLen() > 0 {
-			n.setMore()
+// reload syncs the Alertmanager sets with the received target groups.
+func (n *Notifier) reload(tsets map[string][]*config.TargetGroup) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	n.targetSets = tsets
+	for _, ams := range n.alertmanagers {
+		if tgs, ok := tsets[ams.name]; ok {
+			ams.Sync(tgs)
 		}
 	}
+	n.flushPending()
 }
 
 // Send queues the given notification requests for processing.
-// Panics if called on a handler that is not running.
+// Alerts sent before any Alertmanager is discovered are kept until there is one.
 func (n *Notifier) Send(alerts ...*Alert) {
 	n.mtx.Lock()
 	defer n.mtx.Unlock()
@@ -338,11 +464,17 @@ func (n *Notifier) Send(alerts ...*Alert) {
 				lb.Set(string(ln), string(lv))
 			}
 		}
+		// The labels identifying the replica take precedence over the
+		// external labels, which the rule manager attaches as well.
+		for ln, lv := range n.opts.Replica.Labels {
+			lb.Set(string(ln), string(lv))
+		}
 
 		a.Labels = lb.Labels()
 	}
 
 	alerts = n.relabelAlerts(alerts)
+	n.addReplicaMetadata(alerts)
 
 	// Queue capacity should be significantly larger than a single alert
 	// batch could be.
@@ -352,19 +484,88 @@ func (n *Notifier) Send(alerts ...*Alert) {
 		level.Warn(n.logger).Log("msg", "Alert batch larger than queue capacity, dropping alerts", "num_dropped", d)
 		n.metrics.dropped.Add(float64(d))
 	}
+	n.enqueue(alerts)
+}
+
+// enqueue pushes the alerts to the queues of all discovered Alertmanagers or
+// keeps them until there are any. The notifier lock must be held.
+func (n *Notifier) enqueue(alerts []*Alert) {
+	if len(alerts) == 0 {
+		return
+	}
+	queues := setQueues(n.alertmanagers)
+	if len(queues) > 0 {
+		for _, q := range queues {
+			q.push(alerts)
+		}
+		return
+	}
 
-	// If the queue is full, remove the oldest alerts in favor
-	// of newer ones.
-	if d := (len(n.queue) + len(alerts)) - n.opts.QueueCapacity; d > 0 {
-		n.queue = n.queue[d:]
+	n.hold(alerts)
+	n.pending = append(n.pending, alerts...)
 
-		level.Warn(n.logger).Log("msg", "Alert notification queue full, dropping alerts", "num_dropped", d)
-		n.metrics.dropped.Add(float64(d))
+	if d := len(n.pending) - n.opts.QueueCapacity; d > 0 {
+		level.Warn(n.logger).Log("msg", "No Alertmanagers discovered and alert queue full, dropping alerts", "num_dropped", d)
+		n.release(n.pending[:d], false)
+		n.pending = n.pending[d:]
+	}
+}
+
+// flushPending pushes the alerts kept while no Alertmanager was discovered to
+// the queues of the Alertmanagers, if there are any now. The notifier lock
+// must be held.
+func (n *Notifier) flushPending() {
+	if len(n.pending) == 0 || len(setQueues(n.alertmanagers)) == 0 {
+		return
 	}
-	n.queue = append(n.queue, alerts...)
+	pending := n.pending
+	n.pending = nil
 
-	// Notify sending goroutine that there are alerts to be processed.
-	n.setMore()
+	n.enqueue(pending)
+	n.release(pending, false)
+}
+
+// hold records that the alerts were added to a queue.
+func (n *Notifier) hold(alerts []*Alert) {
+	n.refsMtx.Lock()
+	defer n.refsMtx.Unlock()
+
+	for _, a := range alerts {
+		r, ok := n.refs[a]
+		if !ok {
+			r = &alertRef{}
+			n.refs[a] = r
+		}
+		r.held++
+	}
+}
+
+// release records that the alerts were removed from a queue and whether they
+// were sent from it. Alerts removed from their last queue without having been
+// sent to any Alertmanager are counted as dropped.
+func (n *Notifier) release(alerts []*Alert, sent bool) {
+	var dropped int
+
+	n.refsMtx.Lock()
+	for _, a := range alerts {
+		r, ok := n.refs[a]
+		if !ok {
+			continue
+		}
+		r.sent = r.sent || sent
+		if r.held--; r.held > 0 {
+			continue
+		}
+		delete(n.refs, a)
+		if !r.sent {
+			dropped++
+		}
+	}
+	n.refsMtx.Unlock()
+
+	if dropped > 0 {
+		n.metrics.dropped.Add(float64(dropped))
+	}
 }
 
 func (n *Notifier) relabelAlerts(alerts []*Alert) []*Alert {
@@ -380,13 +581,34 @@ func (n *Notifier) relabelAlerts(alerts []*Alert) []*Alert {
 	return relabeledAlerts
 }
 
-// setMore signals that the alert queue has items.
-func (n *Notifier) setMore() {
-	// If we cannot send on the channel, it means the signal already exists
-	// and has not been consumed yet.
-	select {
-	case n.more <- struct{}{}:
-	default:
+// addReplicaMetadata moves the replica labels of the alerts to their
+// annotations, so that the alerts of all replicas have the same identity, and
+// annotates the alerts with the time they are sent, as configured.
+func (n *Notifier) addReplicaMetadata(alerts []*Alert) {
+	r := n.opts.Replica
+	if !r.DropFromIdentity && r.TimestampAnnotation == "" {
+		return
+	}
+	now := n.now().UTC().Format(time.RFC3339Nano)
+
+	for _, a := range alerts {
+		lb := labels.NewBuilder(a.Labels)
+		ab := labels.NewBuilder(a.Annotations)
+
+		if r.DropFromIdentity {
+			for ln := range r.Labels {
+				if lv := a.Labels.Get(string(ln)); lv != "" {
+					lb.Del(string(ln))
+					ab.Set(string(ln), lv)
+				}
+			}
+		}
+		if r.TimestampAnnotation != "" {
+			ab.Set(string(r.TimestampAnnotation), now)
+		}
+
+		a.Labels = lb.Labels()
+		a.Annotations = ab.Labels()
 	}
 }
 
@@ -409,54 +631,35 @@ func (n *Notifier) Alertmanagers() []*url.URL {
 	return res
 }
 
-// sendAll sends the alerts to all configured Alertmanagers concurrently.
-// It returns true if the alerts could be sent successfully to at least one Alertmanager.
-func (n *Notifier) sendAll(alerts ...*Alert) bool {
-	begin := time.Now()
-
-	b, err := json.Marshal(alerts)
-	if err != nil {
-		level.Error(n.logger).Log("msg", "Encoding alerts failed", "err", err)
-		return false
-	}
-
+// DroppedAlertmanagers returns a slice of the URLs of the Alertmanagers
+// dropped by relabelling.
+func (n *Notifier) DroppedAlertmanagers() []*url.URL {
 	n.mtx.RLock()
 	amSets := n.alertmanagers
 	n.mtx.RUnlock()
 
-	var (
-		wg         sync.WaitGroup
-		numSuccess uint64
-	)
+	var res []*url.URL
+
 	for _, ams := range amSets {
 		ams.mtx.RLock()
-
-		for _, am := range ams.ams {
-			wg.Add(1)
-
-			ctx, cancel := context.WithTimeout(n.ctx, ams.cfg.Timeout)
-			defer cancel()
-
-			go func(am alertmanager) {
-				u := am.url().String()
-
-				if err := n.sendOne(ctx, ams.client, u, b); err != nil {
-					level.Error(n.logger).Log("alertmanager", u, "count", len(alerts), "msg", "Error sending alert", "err", err)
-					n.metrics.errors.WithLabelValues(u).Inc()
-				} else {
-					atomic.AddUint64(&numSuccess, 1)
-				}
-				n.metrics.latency.WithLabelValues(u).Observe(time.Since(begin).Seconds())
-				n.metrics.sent.WithLabelValues(u).Add(float64(len(alerts)))
-
-				wg.Done()
-			}(am)
+		for _, dam := range ams.droppedAms {
+			res = append(res, dam.url())
 		}
 		ams.mtx.RUnlock()
 	}
-	wg.Wait()
 
-	return numSuccess > 0
+	return res
+}
+
+// errClient is returned by sendOne for responses indicating that the request
+// will not succeed when retried.
+type errClient struct {
+	code int
+	msg  string
+}
+
+func (e errClient) Error() string {
+	return fmt.Sprintf("bad response status %v", e.msg)
 }
 
 func (n *Notifier) sendOne(ctx context.Context, c *http.Client, url string, b []byte) error {
@@ -472,10 +675,250 @@ func (n *Notifier) sendOne(ctx context.Context, c *http.Client, url string, b []
 	defer resp.Body.Close()
 
 	// Any HTTP status 2xx is OK.
-	if resp.StatusCode/100 != 2 {
-		return fmt.Errorf("bad response status %v", resp.Status)
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+
+	// The Alertmanager API v2 reports why alerts were rejected in the
+	// response body.
+	msg := resp.Status
+	if b, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrMsgLen)); err == nil && len(bytes.TrimSpace(b)) > 0 {
+		msg = fmt.Sprintf("%s: %s", msg, bytes.TrimSpace(b))
 	}
-	return err
+	if resp.StatusCode/100 == 4 {
+		return errClient{code: resp.StatusCode, msg: msg}
+	}
+	return fmt.Errorf("bad response status %v", msg)
+}
+
+// sendQueue holds the alerts to be sent to a single Alertmanager and sends
+// them independently of other Alertmanagers.
+type sendQueue struct {
+	n       *Notifier
+	url     string
+	client  *http.Client
+	timeout time.Duration
+
+	// The URL alerts are pushed to and the API version it belongs to. Both
+	// change if the version is negotiated and the Alertmanager does not
+	// support the v2 API.
+	pushURL   string
+	version   config.AlertmanagerAPIVersion
+	negotiate bool
+
+	mtx   sync.Mutex
+	queue []*Alert
+	more  chan struct{}
+
+	ctx    context.Context
+	cancel func()
+	logger log.Logger
+}
+
+func newSendQueue(ctx context.Context, n *Notifier, url string, client *http.Client, cfg *config.AlertmanagerConfig) *sendQueue {
+	ctx, cancel := context.WithCancel(ctx)
+
+	// This will initialise the Counters for the AM to 0.
+	n.metrics.sent.WithLabelValues(url)
+	n.metrics.errors.WithLabelValues(url)
+	n.metrics.retries.WithLabelValues(url)
+	n.metrics.amDropped.WithLabelValues(url)
+	n.metrics.amQueueLength.WithLabelValues(url).Set(0)
+
+	version := config.AlertmanagerAPIVersionV1
+	switch cfg.APIVersion {
+	case config.AlertmanagerAPIVersionV2, config.AlertmanagerAPIVersionAuto:
+		version = config.AlertmanagerAPIVersionV2
+	}
+
+	return &sendQueue{
+		n:         n,
+		url:       url,
+		client:    client,
+		timeout:   cfg.Timeout,
+		pushURL:   url,
+		version:   version,
+		negotiate: cfg.APIVersion == config.AlertmanagerAPIVersionAuto,
+		queue:     make([]*Alert, 0, n.opts.QueueCapacity),
+		more:      make(chan struct{}, 1),
+		ctx:       ctx,
+		cancel:    cancel,
+		logger:    log.With(n.logger, "alertmanager", url),
+	}
+}
+
+func (q *sendQueue) len() int {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	return len(q.queue)
+}
+
+// push adds the alerts to the queue. If the queue is full, the oldest alerts
+// are removed in favor of newer ones.
+func (q *sendQueue) push(alerts []*Alert) {
+	q.n.hold(alerts)
+
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	q.queue = append(q.queue, alerts...)
+	if d := len(q.queue) - q.n.opts.QueueCapacity; d > 0 {
+		level.Warn(q.logger).Log("msg", "Alert notification queue full, dropping alerts", "num_dropped", d)
+		q.drop(q.queue[:d])
+
+		q.queue = q.queue[d:]
+	}
+	q.n.metrics.amQueueLength.WithLabelValues(q.url).Set(float64(len(q.queue)))
+
+	q.setMore()
+}
+
+func (q *sendQueue) nextBatch() []*Alert {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	var alerts []*Alert
+
+	if len(q.queue) > maxBatchSize {
+		alerts = append(make([]*Alert, 0, maxBatchSize), q.queue[:maxBatchSize]...)
+		q.queue = q.queue[maxBatchSize:]
+	} else {
+		alerts = append(make([]*Alert, 0, len(q.queue)), q.queue...)
+		q.queue = q.queue[:0]
+	}
+	q.n.metrics.amQueueLength.WithLabelValues(q.url).Set(float64(len(q.queue)))
+
+	return alerts
+}
+
+// setMore signals that the alert queue has items.
+func (q *sendQueue) setMore() {
+	// If we cannot send on the channel, it means the signal already exists
+	// and has not been consumed yet.
+	select {
+	case q.more <- struct{}{}:
+	default:
+	}
+}
+
+// drain removes all alerts from the queue and returns them.
+func (q *sendQueue) drain() []*Alert {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	alerts := q.queue
+	q.queue = make([]*Alert, 0, q.n.opts.QueueCapacity)
+	q.n.metrics.amQueueLength.WithLabelValues(q.url).Set(0)
+
+	return alerts
+}
+
+// drop counts the alerts as dropped for the Alertmanager and, if no other
+// Alertmanager received or still holds them, in total.
+func (q *sendQueue) drop(alerts []*Alert) {
+	q.n.metrics.amDropped.WithLabelValues(q.url).Add(float64(len(alerts)))
+	q.n.release(alerts, false)
+}
+
+// run sends the queued alerts until the queue is stopped.
+func (q *sendQueue) run() {
+	defer func() {
+		// Alerts left in the queue of a removed Alertmanager are lost.
+		q.drop(q.drain())
+		q.n.metrics.amQueueLength.DeleteLabelValues(q.url)
+	}()
+
+	select {
+	case <-q.ctx.Done():
+		return
+	case <-q.n.running:
+	}
+	for {
+		select {
+		case <-q.ctx.Done():
+			return
+		case <-q.more:
+		}
+		alerts := q.nextBatch()
+
+		if q.send(alerts) {
+			q.n.release(alerts, true)
+		} else {
+			q.drop(alerts)
+		}
+		// If the queue still has items left, kick off the next iteration.
+		if q.len() > 0 {
+			q.setMore()
+		}
+	}
+}
+
+// send sends the alerts to the Alertmanager, retrying on failures. It returns
+// whether the alerts were sent successfully.
+func (q *sendQueue) send(alerts []*Alert) bool {
+	if len(alerts) == 0 {
+		return true
+	}
+	b, err := json.Marshal(alerts)
+	if err != nil {
+		level.Error(q.logger).Log("msg", "Encoding alerts failed", "err", err)
+		return false
+	}
+
+	backoff := retryBackoff
+	for attempt := 1; ; attempt++ {
+		begin := time.Now()
+		ctx, cancel := context.WithTimeout(q.ctx, q.timeout)
+		err := q.n.sendOne(ctx, q.client, q.pushURL, b)
+		cancel()
+		q.n.metrics.latency.WithLabelValues(q.url).Observe(time.Since(begin).Seconds())
+
+		if err == nil {
+			q.n.metrics.sent.WithLabelValues(q.url).Add(float64(len(alerts)))
+			q.negotiate = false
+			return true
+		}
+		if q.negotiate && q.fallback(err) {
+			attempt--
+			continue
+		}
+		level.Error(q.logger).Log("count", len(alerts), "msg", "Error sending alert", "err", err, "attempt", attempt)
+		q.n.metrics.errors.WithLabelValues(q.url).Inc()
+
+		if _, ok := err.(errClient); ok || attempt >= maxSendAttempts {
+			return false
+		}
+		select {
+		case <-q.ctx.Done():
+			return false
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		q.n.metrics.retries.WithLabelValues(q.url).Inc()
+	}
+}
+
+// fallback switches to the v1 API if the error indicates that the
+// Alertmanager does not support the v2 API. It returns whether it did so.
+func (q *sendQueue) fallback(err error) bool {
+	q.negotiate = false
+
+	if e, ok := err.(errClient); !ok || e.code != http.StatusNotFound {
+		return false
+	}
+	if !strings.HasSuffix(q.pushURL, alertPushEndpointV2) {
+		return false
+	}
+	q.pushURL = strings.TrimSuffix(q.pushURL, alertPushEndpointV2) + alertPushEndpointV1
+	q.version = config.AlertmanagerAPIVersionV1
+
+	level.Info(q.logger).Log("msg", "Alertmanager does not support API v2, falling back to v1")
+	return true
+}
+
+func (q *sendQueue) stop() {
+	q.cancel()
 }
 
 // Stop shuts down the notification handler.
@@ -504,29 +947,35 @@ func (a alertmanagerLabels) url() *url.URL {
 // alertmanagerSet contains a set of Alertmanagers discovered via a group of service
 // discovery definitions that have a common configuration on how alerts should be sent.
 type alertmanagerSet struct {
-	ts     *discovery.TargetSet
+	name   string
 	cfg    *config.AlertmanagerConfig
 	client *http.Client
 
-	metrics *alertMetrics
+	// The queues of the Alertmanagers are stopped once ctx is done.
+	ctx      context.Context
+	notifier *Notifier
 
-	mtx    sync.RWMutex
-	ams    []alertmanager
-	logger log.Logger
+	mtx        sync.RWMutex
+	ams        []alertmanager
+	droppedAms []alertmanager
+	queues     map[string]*sendQueue // By URL.
+	logger     log.Logger
 }
 
-func newAlertmanagerSet(cfg *config.AlertmanagerConfig, logger log.Logger) (*alertmanagerSet, error) {
+func newAlertmanagerSet(ctx context.Context, name string, cfg *config.AlertmanagerConfig, n *Notifier, logger log.Logger) (*alertmanagerSet, error) {
 	client, err := httputil.NewClientFromConfig(cfg.HTTPClientConfig, "alertmanager")
 	if err != nil {
 		return nil, err
 	}
 	s := &alertmanagerSet{
-		client: client,
-		cfg:    cfg,
-		logger: logger,
+		name:     name,
+		client:   client,
+		cfg:      cfg,
+		ctx:      ctx,
+		notifier: n,
+		queues:   map[string]*sendQueue{},
+		logger:   logger,
 	}
-	s.ts = discovery.NewTargetSet(s)
-
 	return s, nil
 }
 
@@ -534,20 +983,23 @@ func newAlertmanagerSet(cfg *config.AlertmanagerConfig, logger log.Logger) (*ale
 // of target groups definitions.
 func (s *alertmanagerSet) Sync(tgs []*config.TargetGroup) {
 	all := []alertmanager{}
+	allDropped := []alertmanager{}
 
 	for _, tg := range tgs {
-		ams, err := alertmanagerFromGroup(tg, s.cfg)
+		ams, dropped, err := alertmanagerFromGroup(tg, s.cfg)
 		if err != nil {
 			level.Error(s.logger).Log("msg", "Creating discovered Alertmanagers failed", "err", err)
 			continue
 		}
 		all = append(all, ams...)
+		allDropped = append(allDropped, dropped...)
 	}
 
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
 	// Set new Alertmanagers and deduplicate them along their unique URL.
 	s.ams = []alertmanager{}
+	s.droppedAms = allDropped
 	seen := map[string]struct{}{}
 
 	for _, am := range all {
@@ -556,23 +1008,65 @@ func (s *alertmanagerSet) Sync(tgs []*config.TargetGroup) {
 			continue
 		}
 
-		// This will initialise the Counters for the AM to 0.
-		s.metrics.sent.WithLabelValues(us)
-		s.metrics.errors.WithLabelValues(us)
-
 		seen[us] = struct{}{}
 		s.ams = append(s.ams, am)
 	}
+	s.syncQueues()
+}
+
+// syncQueues starts a queue for every new Alertmanager and stops the queues
+// of the Alertmanagers no longer present. Queues of remaining Alertmanagers
+// keep their alerts.
+func (s *alertmanagerSet) syncQueues() {
+	present := make(map[string]struct{}, len(s.ams))
+	for _, am := range s.ams {
+		us := am.url().String()
+		present[us] = struct{}{}
+
+		if _, ok := s.queues[us]; ok {
+			continue
+		}
+		q := newSendQueue(s.ctx, s.notifier, us, s.client, s.cfg)
+		s.queues[us] = q
+		go q.run()
+	}
+	for us, q := range s.queues {
+		if _, ok := present[us]; !ok {
+			q.stop()
+			delete(s.queues, us)
+		}
+	}
 }
 
-func postPath(pre string) string {
-	return path.Join("/", pre, alertPushEndpoint)
+// drain removes the alerts from the queues of all Alertmanagers of the set
+// and returns them. An alert is returned once for every queue it was in.
+func (s *alertmanagerSet) drain() []*Alert {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	var alerts []*Alert
+	for _, q := range s.queues {
+		alerts = append(alerts, q.drain()...)
+	}
+	return alerts
+}
+
+// postPath returns the path alerts are pushed to for the given path prefix and
+// API version. Negotiation starts out with the v2 API.
+func postPath(pre string, v config.AlertmanagerAPIVersion) string {
+	switch v {
+	case config.AlertmanagerAPIVersionV2, config.AlertmanagerAPIVersionAuto:
+		return path.Join("/", pre, alertPushEndpointV2)
+	}
+	return path.Join("/", pre, alertPushEndpointV1)
 }
 
 // alertmanagersFromGroup extracts a list of alertmanagers from a target group and an associcated
-// AlertmanagerConfig.
-func alertmanagerFromGroup(tg *config.TargetGroup, cfg *config.AlertmanagerConfig) ([]alertmanager, error) {
+// AlertmanagerConfig. Alertmanagers dropped by relabelling are returned separately with
+// the labels they had before relabelling.
+func alertmanagerFromGroup(tg *config.TargetGroup, cfg *config.AlertmanagerConfig) ([]alertmanager, []alertmanager, error) {
 	var res []alertmanager
+	var droppedAlertmanagers []alertmanager
 
 	for _, tlset := range tg.Targets {
 		lbls := make([]labels.Label, 0, len(tlset)+2+len(tg.Labels))
@@ -582,7 +1076,7 @@ func alertmanagerFromGroup(tg *config.TargetGroup, cfg *config.AlertmanagerConfi
 		}
 		// Set configured scheme as the initial scheme label for overwrite.
 		lbls = append(lbls, labels.Label{Name: model.SchemeLabel, Value: cfg.Scheme})
-		lbls = append(lbls, labels.Label{Name: pathLabel, Value: postPath(cfg.PathPrefix)})
+		lbls = append(lbls, labels.Label{Name: pathLabel, Value: postPath(cfg.PathPrefix, cfg.APIVersion)})
 
 		// Combine target labels with target group labels.
 		for ln, lv := range tg.Labels {
@@ -593,6 +1087,7 @@ func alertmanagerFromGroup(tg *config.TargetGroup, cfg *config.AlertmanagerConfi
 
 		lset := relabel.Process(labels.New(lbls...), cfg.RelabelConfigs...)
 		if lset == nil {
+			droppedAlertmanagers = append(droppedAlertmanagers, alertmanagerLabels{labels.New(lbls...)})
 			continue
 		}
 
@@ -620,13 +1115,13 @@ func alertmanagerFromGroup(tg *config.TargetGroup, cfg *config.AlertmanagerConfi
 			case "https":
 				addr = addr + ":443"
 			default:
-				return nil, fmt.Errorf("invalid scheme: %q", cfg.Scheme)
+				return nil, nil, fmt.Errorf("invalid scheme: %q", cfg.Scheme)
 			}
 			lb.Set(model.AddressLabel, addr)
 		}
 
 		if err := config.CheckTargetAddress(model.LabelValue(addr)); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		// Meta labels are deleted after relabelling. Other internal labels propagate to
@@ -639,5 +1134,5 @@ func alertmanagerFromGroup(tg *config.TargetGroup, cfg *config.AlertmanagerConfi
 
 		res = append(res, alertmanagerLabels{lset})
 	}
-	return res, nil
+	return res, droppedAlertmanagers, nil
 }
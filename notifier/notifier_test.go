@@ -27,6 +27,8 @@ import (
 
 	old_ctx "golang.org/x/net/context"
 
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/pkg/labels"
@@ -35,11 +37,27 @@ import (
 func TestPostPath(t *testing.T) {
 	var cases = []struct {
 		in, out string
+		version config.AlertmanagerAPIVersion
 	}{
 		{
 			in:  "",
 			out: "/api/v1/alerts",
 		},
+		{
+			in:      "",
+			out:     "/api/v1/alerts",
+			version: config.AlertmanagerAPIVersionV1,
+		},
+		{
+			in:      "/prefix",
+			out:     "/prefix/api/v2/alerts",
+			version: config.AlertmanagerAPIVersionV2,
+		},
+		{
+			in:      "/prefix//",
+			out:     "/prefix/api/v2/alerts",
+			version: config.AlertmanagerAPIVersionAuto,
+		},
 		{
 			in:  "/",
 			out: "/api/v1/alerts",
@@ -58,24 +76,49 @@ func TestPostPath(t *testing.T) {
 		},
 	}
 	for _, c := range cases {
-		if res := postPath(c.in); res != c.out {
-			t.Errorf("Expected post path %q for %q but got %q", c.out, c.in, res)
+		if res := postPath(c.in, c.version); res != c.out {
+			t.Errorf("Expected post path %q for %q with API version %q but got %q", c.out, c.in, c.version, res)
 		}
 	}
 }
 
+// newTestAlertmanagerSet adds an Alertmanager set with the given URLs to the
+// notifier and starts their queues.
+func newTestAlertmanagerSet(h *Notifier, urls ...string) *alertmanagerSet {
+	s := &alertmanagerSet{
+		cfg: &config.AlertmanagerConfig{
+			Timeout: time.Second,
+		},
+		ctx:      h.ctx,
+		notifier: h,
+		queues:   map[string]*sendQueue{},
+		logger:   h.logger,
+	}
+	for _, u := range urls {
+		u := u
+		s.ams = append(s.ams, alertmanagerMock{
+			urlf: func() string { return u },
+		})
+	}
+	s.syncQueues()
+
+	h.alertmanagers = append(h.alertmanagers, s)
+	return s
+}
+
 func TestHandlerNextBatch(t *testing.T) {
 	h := New(&Options{}, nil)
+	q := newSendQueue(h.ctx, h, "http://localhost:9093/api/v1/alerts", nil, &config.AlertmanagerConfig{})
 
 	for i := range make([]struct{}, 2*maxBatchSize+1) {
-		h.queue = append(h.queue, &Alert{
+		q.queue = append(q.queue, &Alert{
 			Labels: labels.FromStrings("alertname", fmt.Sprintf("%d", i)),
 		})
 	}
 
-	expected := append([]*Alert{}, h.queue...)
+	expected := append([]*Alert{}, q.queue...)
 
-	b := h.nextBatch()
+	b := q.nextBatch()
 
 	if len(b) != maxBatchSize {
 		t.Fatalf("Expected first batch of length %d, but got %d", maxBatchSize, len(b))
@@ -84,7 +127,7 @@ func TestHandlerNextBatch(t *testing.T) {
 		t.Fatalf("First batch did not match")
 	}
 
-	b = h.nextBatch()
+	b = q.nextBatch()
 
 	if len(b) != maxBatchSize {
 		t.Fatalf("Expected second batch of length %d, but got %d", maxBatchSize, len(b))
@@ -93,7 +136,7 @@ func TestHandlerNextBatch(t *testing.T) {
 		t.Fatalf("Second batch did not match")
 	}
 
-	b = h.nextBatch()
+	b = q.nextBatch()
 
 	if len(b) != 1 {
 		t.Fatalf("Expected third batch of length %d, but got %d", 1, len(b))
@@ -102,8 +145,8 @@ func TestHandlerNextBatch(t *testing.T) {
 		t.Fatalf("Third batch did not match")
 	}
 
-	if len(h.queue) != 0 {
-		t.Fatalf("Expected queue to be empty but got %d alerts", len(h.queue))
+	if len(q.queue) != 0 {
+		t.Fatalf("Expected queue to be empty but got %d alerts", len(q.queue))
 	}
 }
 
@@ -121,14 +164,16 @@ func alertsEqual(a, b []*Alert) bool {
 	return true
 }
 
-func TestHandlerSendAll(t *testing.T) {
+func TestSendQueueSend(t *testing.T) {
 	var (
-		expected         []*Alert
-		status1, status2 int
+		expected []*Alert
+		status   int
+		requests int
 	)
 
-	f := func(w http.ResponseWriter, r *http.Request) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer r.Body.Close()
+		requests++
 
 		var alerts []*Alert
 		if err := json.NewDecoder(r.Body).Decode(&alerts); err != nil {
@@ -139,57 +184,186 @@ func TestHandlerSendAll(t *testing.T) {
 			t.Errorf("%#v %#v", *alerts[0], *expected[0])
 			t.Fatalf("Unexpected alerts received %v exp %v", alerts, expected)
 		}
-	}
-	server1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		f(w, r)
-		w.WriteHeader(status1)
+		w.WriteHeader(status)
 	}))
-	server2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		f(w, r)
-		w.WriteHeader(status2)
-	}))
-
-	defer server1.Close()
-	defer server2.Close()
+	defer server.Close()
 
 	h := New(&Options{}, nil)
-	h.alertmanagers = append(h.alertmanagers, &alertmanagerSet{
-		ams: []alertmanager{
-			alertmanagerMock{
-				urlf: func() string { return server1.URL },
-			},
-			alertmanagerMock{
-				urlf: func() string { return server2.URL },
-			},
-		},
-		cfg: &config.AlertmanagerConfig{
-			Timeout: time.Second,
-		},
+	q := newSendQueue(h.ctx, h, server.URL, nil, &config.AlertmanagerConfig{
+		Timeout: time.Second,
 	})
 
 	for i := range make([]struct{}, maxBatchSize) {
-		h.queue = append(h.queue, &Alert{
-			Labels: labels.FromStrings("alertname", fmt.Sprintf("%d", i)),
-		})
 		expected = append(expected, &Alert{
 			Labels: labels.FromStrings("alertname", fmt.Sprintf("%d", i)),
 		})
 	}
 
-	status1 = http.StatusOK
-	status2 = http.StatusOK
-	if !h.sendAll(h.queue...) {
-		t.Fatalf("all sends failed unexpectedly")
+	status = http.StatusOK
+	if !q.send(expected) {
+		t.Fatalf("send failed unexpectedly")
+	}
+	if requests != 1 {
+		t.Fatalf("Expected 1 request but got %d", requests)
 	}
 
-	status1 = http.StatusNotFound
-	if !h.sendAll(h.queue...) {
-		t.Fatalf("all sends failed unexpectedly")
+	// Client errors are not retried.
+	requests = 0
+	status = http.StatusNotFound
+	if q.send(expected) {
+		t.Fatalf("send succeeded unexpectedly")
+	}
+	if requests != 1 {
+		t.Fatalf("Expected 1 request but got %d", requests)
 	}
 
-	status2 = http.StatusInternalServerError
-	if h.sendAll(h.queue...) {
-		t.Fatalf("all sends succeeded unexpectedly")
+	// Server errors are retried until the maximum number of attempts.
+	requests = 0
+	status = http.StatusInternalServerError
+	if q.send(expected) {
+		t.Fatalf("send succeeded unexpectedly")
+	}
+	if requests != maxSendAttempts {
+		t.Fatalf("Expected %d requests but got %d", maxSendAttempts, requests)
+	}
+}
+
+func TestSendQueueNegotiateVersion(t *testing.T) {
+	var (
+		paths []string
+		v2    bool
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == alertPushEndpointV2 && !v2 {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	h := New(&Options{}, nil)
+	alerts := []*Alert{
+		{Labels: labels.FromStrings("alertname", "test")},
+	}
+	cfg := &config.AlertmanagerConfig{
+		Timeout:    time.Second,
+		APIVersion: config.AlertmanagerAPIVersionAuto,
+	}
+
+	// An Alertmanager supporting v2 keeps being sent to via v2.
+	v2 = true
+	q := newSendQueue(h.ctx, h, server.URL+alertPushEndpointV2, nil, cfg)
+	for i := 0; i < 2; i++ {
+		if !q.send(alerts) {
+			t.Fatalf("send failed unexpectedly")
+		}
+	}
+	expected := []string{alertPushEndpointV2, alertPushEndpointV2}
+	if !reflect.DeepEqual(expected, paths) {
+		t.Fatalf("Expected requests to %v but got %v", expected, paths)
+	}
+	if q.version != config.AlertmanagerAPIVersionV2 {
+		t.Fatalf("Expected API version %q but got %q", config.AlertmanagerAPIVersionV2, q.version)
+	}
+
+	// An Alertmanager not supporting v2 is sent to via v1 after the first
+	// attempt.
+	v2 = false
+	paths = nil
+	q = newSendQueue(h.ctx, h, server.URL+alertPushEndpointV2, nil, cfg)
+	for i := 0; i < 2; i++ {
+		if !q.send(alerts) {
+			t.Fatalf("send failed unexpectedly")
+		}
+	}
+	expected = []string{alertPushEndpointV2, alertPushEndpointV1, alertPushEndpointV1}
+	if !reflect.DeepEqual(expected, paths) {
+		t.Fatalf("Expected requests to %v but got %v", expected, paths)
+	}
+	if q.version != config.AlertmanagerAPIVersionV1 {
+		t.Fatalf("Expected API version %q but got %q", config.AlertmanagerAPIVersionV1, q.version)
+	}
+
+	// Without negotiation, a missing v2 API is an error.
+	paths = nil
+	cfg.APIVersion = config.AlertmanagerAPIVersionV2
+	q = newSendQueue(h.ctx, h, server.URL+alertPushEndpointV2, nil, cfg)
+	if q.send(alerts) {
+		t.Fatalf("send succeeded unexpectedly")
+	}
+	expected = []string{alertPushEndpointV2}
+	if !reflect.DeepEqual(expected, paths) {
+		t.Fatalf("Expected requests to %v but got %v", expected, paths)
+	}
+}
+
+func TestSendOneErrorBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintln(w, `"start time must be before end time"`)
+	}))
+	defer server.Close()
+
+	h := New(&Options{}, nil)
+	err := h.sendOne(context.Background(), http.DefaultClient, server.URL, []byte("[]"))
+	if err == nil {
+		t.Fatalf("Expected error but got none")
+	}
+	expected := `bad response status 400 Bad Request: "start time must be before end time"`
+	if err.Error() != expected {
+		t.Fatalf("Expected error %q but got %q", expected, err)
+	}
+}
+
+func TestHandlerFailingAlertmanager(t *testing.T) {
+	var (
+		unblock = make(chan struct{})
+		healthy = make(chan []*Alert, 1)
+	)
+
+	blocking := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer blocking.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+
+		var alerts []*Alert
+		if err := json.NewDecoder(r.Body).Decode(&alerts); err != nil {
+			t.Fatalf("Unexpected error on input decoding: %s", err)
+		}
+		healthy <- alerts
+	}))
+	defer server.Close()
+	defer close(unblock)
+
+	h := New(&Options{
+		QueueCapacity: 3 * maxBatchSize,
+	}, nil)
+	newTestAlertmanagerSet(h, blocking.URL, server.URL)
+
+	go h.Run(nil)
+	defer h.Stop()
+
+	for i := 0; i < 2; i++ {
+		expected := []*Alert{
+			{Labels: labels.FromStrings("alertname", fmt.Sprintf("%d", i))},
+		}
+		h.Send(expected...)
+
+		// The healthy Alertmanager must receive every batch while the other
+		// one is still blocked on the first.
+		select {
+		case alerts := <-healthy:
+			if !alertsEqual(expected, alerts) {
+				t.Fatalf("Expected alerts %v, got %v", expected, alerts)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Alerts were not pushed to the healthy Alertmanager")
+		}
 	}
 }
 
@@ -212,7 +386,8 @@ func TestCustomDo(t *testing.T) {
 				t.Fatalf("Unexpected URL; want %v, got %v", testURL, req.URL.String())
 			}
 			return &http.Response{
-				Body: ioutil.NopCloser(nil),
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(nil),
 			}, nil
 		},
 	}, nil)
@@ -238,6 +413,7 @@ func TestExternalLabels(t *testing.T) {
 			},
 		},
 	}, nil)
+	ams := newTestAlertmanagerSet(h, "http://localhost:9093/api/v1/alerts")
 
 	// This alert should get the external label attached.
 	h.Send(&Alert{
@@ -255,8 +431,9 @@ func TestExternalLabels(t *testing.T) {
 		{Labels: labels.FromStrings("alertname", "externalrelabelthis", "a", "c")},
 	}
 
-	if !alertsEqual(expected, h.queue) {
-		t.Errorf("Expected alerts %v, got %v", expected, h.queue)
+	q := ams.queues["http://localhost:9093/api/v1/alerts"]
+	if !alertsEqual(expected, q.queue) {
+		t.Errorf("Expected alerts %v, got %v", expected, q.queue)
 	}
 }
 
@@ -278,6 +455,7 @@ func TestHandlerRelabel(t *testing.T) {
 			},
 		},
 	}, nil)
+	ams := newTestAlertmanagerSet(h, "http://localhost:9093/api/v1/alerts")
 
 	// This alert should be dropped due to the configuration
 	h.Send(&Alert{
@@ -293,8 +471,41 @@ func TestHandlerRelabel(t *testing.T) {
 		{Labels: labels.FromStrings("alertname", "renamed")},
 	}
 
-	if !alertsEqual(expected, h.queue) {
-		t.Errorf("Expected alerts %v, got %v", expected, h.queue)
+	q := ams.queues["http://localhost:9093/api/v1/alerts"]
+	if !alertsEqual(expected, q.queue) {
+		t.Errorf("Expected alerts %v, got %v", expected, q.queue)
+	}
+}
+
+func TestReplicaMetadata(t *testing.T) {
+	h := New(&Options{
+		QueueCapacity:  3 * maxBatchSize,
+		ExternalLabels: model.LabelSet{"cluster": "eu", "replica": "external"},
+		Replica: config.AlertReplicaConfig{
+			Labels:              model.LabelSet{"replica": "a"},
+			DropFromIdentity:    true,
+			TimestampAnnotation: "sent_at",
+		},
+	}, nil)
+	h.now = func() time.Time { return time.Unix(1500000000, 0) }
+	ams := newTestAlertmanagerSet(h, "http://localhost:9093/api/v1/alerts")
+
+	h.Send(&Alert{
+		Labels:      labels.FromStrings("alertname", "test", "replica", "rule"),
+		Annotations: labels.FromStrings("summary", "test"),
+	})
+
+	q := ams.queues["http://localhost:9093/api/v1/alerts"]
+	if len(q.queue) != 1 {
+		t.Fatalf("Expected one alert, got %v", q.queue)
+	}
+	a := q.queue[0]
+	if exp := labels.FromStrings("alertname", "test", "cluster", "eu"); !labels.Equal(exp, a.Labels) {
+		t.Errorf("Expected labels %v, got %v", exp, a.Labels)
+	}
+	exp := labels.FromStrings("replica", "a", "sent_at", "2017-07-14T02:40:00Z", "summary", "test")
+	if !labels.Equal(exp, a.Annotations) {
+		t.Errorf("Expected annotations %v, got %v", exp, a.Annotations)
 	}
 }
 
@@ -326,16 +537,7 @@ func TestHandlerQueueing(t *testing.T) {
 	},
 		nil,
 	)
-	h.alertmanagers = append(h.alertmanagers, &alertmanagerSet{
-		ams: []alertmanager{
-			alertmanagerMock{
-				urlf: func() string { return server.URL },
-			},
-		},
-		cfg: &config.AlertmanagerConfig{
-			Timeout: time.Second,
-		},
-	})
+	newTestAlertmanagerSet(h, server.URL)
 
 	var alerts []*Alert
 
@@ -345,7 +547,7 @@ func TestHandlerQueueing(t *testing.T) {
 		})
 	}
 
-	go h.Run()
+	go h.Run(nil)
 	defer h.Stop()
 
 	h.Send(alerts[:4*maxBatchSize]...)
@@ -403,7 +605,7 @@ func (a alertmanagerMock) url() *url.URL {
 
 func TestLabelSetNotReused(t *testing.T) {
 	tg := makeInputTargetGroup()
-	_, err := alertmanagerFromGroup(tg, &config.AlertmanagerConfig{})
+	_, _, err := alertmanagerFromGroup(tg, &config.AlertmanagerConfig{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -413,6 +615,49 @@ func TestLabelSetNotReused(t *testing.T) {
 	}
 }
 
+func TestDroppedAlertmanagers(t *testing.T) {
+	h := New(&Options{}, nil)
+	ams := newTestAlertmanagerSet(h)
+	ams.cfg = &config.AlertmanagerConfig{
+		Scheme:     "http",
+		Timeout:    time.Second,
+		APIVersion: config.AlertmanagerAPIVersionV1,
+		RelabelConfigs: []*config.RelabelConfig{
+			{
+				SourceLabels: model.LabelNames{model.AddressLabel},
+				Action:       config.RelabelDrop,
+				Regex:        config.MustNewRegexp("alertmanager2:9093"),
+			},
+		},
+	}
+
+	ams.Sync([]*config.TargetGroup{
+		{
+			Targets: []model.LabelSet{
+				{model.AddressLabel: "alertmanager1:9093"},
+				{model.AddressLabel: "alertmanager2:9093"},
+			},
+		},
+	})
+
+	expected := []string{"http://alertmanager1:9093/api/v1/alerts"}
+	if res := urlStrings(h.Alertmanagers()); !reflect.DeepEqual(expected, res) {
+		t.Fatalf("Expected active Alertmanagers %v but got %v", expected, res)
+	}
+	expected = []string{"http://alertmanager2:9093/api/v1/alerts"}
+	if res := urlStrings(h.DroppedAlertmanagers()); !reflect.DeepEqual(expected, res) {
+		t.Fatalf("Expected dropped Alertmanagers %v but got %v", expected, res)
+	}
+}
+
+func urlStrings(urls []*url.URL) []string {
+	res := make([]string, 0, len(urls))
+	for _, u := range urls {
+		res = append(res, u.String())
+	}
+	return res
+}
+
 func makeInputTargetGroup() *config.TargetGroup {
 	return &config.TargetGroup{
 		Targets: []model.LabelSet{
@@ -427,3 +672,142 @@ func makeInputTargetGroup() *config.TargetGroup {
 		Source: "testsource",
 	}
 }
+
+func TestNotifierSyncsDiscoveredAlertmanagers(t *testing.T) {
+	n := New(&Options{QueueCapacity: 10}, nil)
+	defer n.Stop()
+
+	conf, err := config.Load(`
+alerting:
+  alertmanagers:
+  - static_configs:
+    - targets: ['ignored:9093']
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sdConfigs := DiscoveryConfigs(conf)
+	if _, ok := sdConfigs["config-0"]; !ok || len(sdConfigs) != 1 {
+		t.Fatalf("Unexpected discovery configs %v", sdConfigs)
+	}
+	if err := n.ApplyConfig(conf); err != nil {
+		t.Fatal(err)
+	}
+
+	tsets := make(chan map[string][]*config.TargetGroup)
+	go n.Run(tsets)
+
+	tsets <- map[string][]*config.TargetGroup{
+		"config-0": {{Targets: []model.LabelSet{{model.AddressLabel: "alertmanager:9093"}}}},
+	}
+	// Sending another update ensures that the first one was processed.
+	tsets <- map[string][]*config.TargetGroup{
+		"config-0": {{Targets: []model.LabelSet{{model.AddressLabel: "alertmanager:9093"}}}},
+	}
+	expected := "http://alertmanager:9093/api/v1/alerts"
+	if ams := n.Alertmanagers(); len(ams) != 1 || ams[0].String() != expected {
+		t.Fatalf("Expected Alertmanager %s but got %v", expected, ams)
+	}
+
+	// Alertmanager sets created on reload are synced with the target groups
+	// received before.
+	if err := n.ApplyConfig(conf); err != nil {
+		t.Fatal(err)
+	}
+	if ams := n.Alertmanagers(); len(ams) != 1 || ams[0].String() != expected {
+		t.Fatalf("Expected Alertmanager %s after reload but got %v", expected, ams)
+	}
+}
+
+func TestSendBeforeDiscovery(t *testing.T) {
+	n := New(&Options{QueueCapacity: 2}, nil)
+	defer n.Stop()
+
+	conf, err := config.Load(`
+alerting:
+  alertmanagers:
+  - static_configs:
+    - targets: ['ignored:9093']
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.ApplyConfig(conf); err != nil {
+		t.Fatal(err)
+	}
+
+	// Alerts sent before any Alertmanager is discovered are kept up to the
+	// queue capacity.
+	n.Send(
+		&Alert{Labels: labels.FromStrings("alertname", "0")},
+		&Alert{Labels: labels.FromStrings("alertname", "1")},
+	)
+	n.Send(&Alert{Labels: labels.FromStrings("alertname", "2")})
+	if l := n.queueLen(); l != 2 {
+		t.Fatalf("Expected 2 pending alerts but got %d", l)
+	}
+	if d := counterValue(t, n.metrics.dropped); d != 1 {
+		t.Fatalf("Expected 1 dropped alert but got %v", d)
+	}
+
+	n.reload(map[string][]*config.TargetGroup{
+		"config-0": {{Targets: []model.LabelSet{{model.AddressLabel: "alertmanager:9093"}}}},
+	})
+	expected := []*Alert{
+		{Labels: labels.FromStrings("alertname", "1")},
+		{Labels: labels.FromStrings("alertname", "2")},
+	}
+	q := n.queues()[0]
+	if !alertsEqual(expected, q.queue) {
+		t.Fatalf("Expected queued alerts %v, got %v", expected, q.queue)
+	}
+
+	// Reloading the configuration moves the queued alerts to the new queues.
+	if err := n.ApplyConfig(conf); err != nil {
+		t.Fatal(err)
+	}
+	queues := n.queues()
+	if len(queues) != 1 || queues[0] == q {
+		t.Fatalf("Expected one new queue but got %v", queues)
+	}
+	if !alertsEqual(expected, queues[0].queue) {
+		t.Fatalf("Expected queued alerts %v after reload, got %v", expected, queues[0].queue)
+	}
+	if d := counterValue(t, n.metrics.dropped); d != 1 {
+		t.Fatalf("Expected 1 dropped alert after reload but got %v", d)
+	}
+}
+
+func TestDroppedCountedOnce(t *testing.T) {
+	h := New(&Options{QueueCapacity: 1}, nil)
+	s := newTestAlertmanagerSet(h, "http://alertmanager1:9093", "http://alertmanager2:9093")
+	q1 := s.queues["http://alertmanager1:9093"]
+	q2 := s.queues["http://alertmanager2:9093"]
+
+	h.Send(&Alert{Labels: labels.FromStrings("alertname", "0")})
+	h.Send(&Alert{Labels: labels.FromStrings("alertname", "1")})
+
+	// The first alert was pushed out of the queues of both Alertmanagers.
+	if d := counterValue(t, h.metrics.dropped); d != 1 {
+		t.Fatalf("Expected 1 dropped alert but got %v", d)
+	}
+
+	// An alert received by one Alertmanager is not dropped in total.
+	alerts := q1.nextBatch()
+	h.release(alerts, true)
+	q2.drop(q2.nextBatch())
+	if d := counterValue(t, h.metrics.dropped); d != 1 {
+		t.Fatalf("Expected 1 dropped alert but got %v", d)
+	}
+	if d := counterValue(t, h.metrics.amDropped.WithLabelValues(q2.url)); d != 2 {
+		t.Fatalf("Expected 2 alerts dropped for the Alertmanager but got %v", d)
+	}
+}
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	var m dto.Metric
+	if err := c.Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetCounter().GetValue()
+}
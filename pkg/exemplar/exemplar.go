@@ -0,0 +1,63 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exemplar
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+)
+
+// MaxLabelSetLength is the maximum number of UTF-8 characters of the label
+// names and values of an exemplar allowed by OpenMetrics.
+const MaxLabelSetLength = 128
+
+// Exemplar is a sample that references an individual event, e.g. a trace,
+// which contributed to the value of a series.
+type Exemplar struct {
+	// Labels identify the event, e.g. by a trace ID.
+	Labels labels.Labels
+	Value  float64
+	// Ts is the timestamp of the exemplar in milliseconds. If HasTs is
+	// false, it was not exposed and set to the timestamp of the sample.
+	Ts    int64
+	HasTs bool
+}
+
+// Equals returns whether the labels, value and timestamp of both exemplars
+// are the same.
+func (e Exemplar) Equals(o Exemplar) bool {
+	return labels.Equal(e.Labels, o.Labels) && e.Value == o.Value && e.Ts == o.Ts
+}
+
+// MarshalJSON implements json.Marshaler. Like the values of samples, the
+// value is encoded as a string and the timestamp in seconds.
+func (e Exemplar) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Labels    labels.Labels `json:"labels"`
+		Value     string        `json:"value"`
+		Timestamp float64       `json:"timestamp"`
+	}{
+		Labels:    e.Labels,
+		Value:     strconv.FormatFloat(e.Value, 'f', -1, 64),
+		Timestamp: float64(e.Ts) / 1000,
+	})
+}
+
+// QueryResult holds the exemplars of a series selected by a query.
+type QueryResult struct {
+	SeriesLabels labels.Labels `json:"seriesLabels"`
+	Exemplars    []Exemplar    `json:"exemplars"`
+}
@@ -29,13 +29,20 @@ import (
 // If a label set is dropped, nil is returned.
 // May return the input labelSet modified.
 func Process(labels labels.Labels, cfgs ...*config.RelabelConfig) labels.Labels {
-	for _, cfg := range cfgs {
+	labels, _ = ProcessDropped(labels, cfgs...)
+	return labels
+}
+
+// ProcessDropped is like Process, but also returns the index of the relabel
+// configuration that dropped the label set, or -1 if it was not dropped.
+func ProcessDropped(labels labels.Labels, cfgs ...*config.RelabelConfig) (labels.Labels, int) {
+	for i, cfg := range cfgs {
 		labels = relabel(labels, cfg)
 		if labels == nil {
-			return nil
+			return nil, i
 		}
 	}
-	return labels
+	return labels, -1
 }
 
 func relabel(lset labels.Labels, cfg *config.RelabelConfig) labels.Labels {
@@ -73,13 +80,29 @@ func relabel(lset labels.Labels, cfg *config.RelabelConfig) labels.Labels {
 			break
 		}
 		lb.Set(string(target), string(res))
+	case config.RelabelKeepEqual:
+		if lset.Get(cfg.TargetLabel) != val {
+			return nil
+		}
+	case config.RelabelDropEqual:
+		if lset.Get(cfg.TargetLabel) == val {
+			return nil
+		}
 	case config.RelabelHashMod:
 		mod := sum64(md5.Sum([]byte(val))) % cfg.Modulus
 		lb.Set(cfg.TargetLabel, fmt.Sprintf("%d", mod))
+	case config.RelabelLowercase:
+		lb.Set(cfg.TargetLabel, strings.ToLower(val))
+	case config.RelabelUppercase:
+		lb.Set(cfg.TargetLabel, strings.ToUpper(val))
 	case config.RelabelLabelMap:
 		for _, l := range lset {
 			if cfg.Regex.MatchString(l.Name) {
 				res := cfg.Regex.ReplaceAllString(l.Name, cfg.Replacement)
+				// The replacement template may expand to an invalid label name.
+				if !model.LabelName(res).IsValid() {
+					continue
+				}
 				lb.Set(res, l.Value)
 			}
 		}
@@ -411,6 +411,110 @@ func TestRelabel(t *testing.T) {
 				"a": "foo",
 			}),
 		},
+		{
+			input: labels.FromMap(map[string]string{
+				"a": "Foo",
+				"b": "BAR",
+			}),
+			relabel: []*config.RelabelConfig{
+				{
+					SourceLabels: model.LabelNames{"a", "b"},
+					Separator:    "-",
+					Action:       config.RelabelLowercase,
+					TargetLabel:  "c",
+				},
+				{
+					SourceLabels: model.LabelNames{"a"},
+					Action:       config.RelabelUppercase,
+					TargetLabel:  "a",
+				},
+			},
+			output: labels.FromMap(map[string]string{
+				"a": "FOO",
+				"b": "BAR",
+				"c": "foo-bar",
+			}),
+		},
+		{
+			input: labels.FromMap(map[string]string{
+				"a": "foo",
+				"b": "foo",
+			}),
+			relabel: []*config.RelabelConfig{
+				{
+					SourceLabels: model.LabelNames{"a"},
+					Action:       config.RelabelKeepEqual,
+					TargetLabel:  "b",
+				},
+			},
+			output: labels.FromMap(map[string]string{
+				"a": "foo",
+				"b": "foo",
+			}),
+		},
+		{
+			input: labels.FromMap(map[string]string{
+				"a": "foo",
+				"b": "bar",
+			}),
+			relabel: []*config.RelabelConfig{
+				{
+					SourceLabels: model.LabelNames{"a"},
+					Action:       config.RelabelKeepEqual,
+					TargetLabel:  "b",
+				},
+			},
+			output: nil,
+		},
+		{
+			input: labels.FromMap(map[string]string{
+				"a": "foo",
+				"b": "foo",
+			}),
+			relabel: []*config.RelabelConfig{
+				{
+					SourceLabels: model.LabelNames{"a"},
+					Action:       config.RelabelDropEqual,
+					TargetLabel:  "b",
+				},
+			},
+			output: nil,
+		},
+		{
+			input: labels.FromMap(map[string]string{
+				"a": "foo",
+				"b": "bar",
+			}),
+			relabel: []*config.RelabelConfig{
+				{
+					SourceLabels: model.LabelNames{"a"},
+					Action:       config.RelabelDropEqual,
+					TargetLabel:  "b",
+				},
+			},
+			output: labels.FromMap(map[string]string{
+				"a": "foo",
+				"b": "bar",
+			}),
+		},
+		{ // labelmap skips invalid label names
+			input: labels.FromMap(map[string]string{
+				"__meta_x_a":   "foo",
+				"__meta_x_b-c": "bar",
+			}),
+			relabel: []*config.RelabelConfig{
+				{
+					Regex:       config.MustNewRegexp("__meta_x_(.+)"),
+					Replacement: "x_${1}",
+					Action:      config.RelabelLabelMap,
+				},
+			},
+			output: labels.FromMap(map[string]string{
+				"__meta_x_a":   "foo",
+				"__meta_x_b-c": "bar",
+				"x_a":          "foo",
+			}),
+		},
 	}
 
 	for i, test := range tests {
@@ -421,3 +525,29 @@ func TestRelabel(t *testing.T) {
 		}
 	}
 }
+
+func TestProcessDropped(t *testing.T) {
+	cfgs := []*config.RelabelConfig{
+		{
+			SourceLabels: model.LabelNames{"a"},
+			Regex:        config.MustNewRegexp("bar"),
+			Separator:    ";",
+			Action:       config.RelabelDrop,
+		},
+		{
+			SourceLabels: model.LabelNames{"a"},
+			Regex:        config.MustNewRegexp("foo"),
+			Separator:    ";",
+			Action:       config.RelabelDrop,
+		},
+	}
+
+	res, i := ProcessDropped(labels.FromStrings("a", "foo"), cfgs...)
+	if res != nil || i != 1 {
+		t.Errorf("Expected label set to be dropped by config 1, got %v and %d", res, i)
+	}
+	res, i = ProcessDropped(labels.FromStrings("a", "baz"), cfgs...)
+	if !reflect.DeepEqual(res, labels.FromStrings("a", "baz")) || i != -1 {
+		t.Errorf("Expected label set to be kept, got %v and %d", res, i)
+	}
+}
@@ -63,6 +63,19 @@ func (g *RuleGroups) Validate() (errs []error) {
 			errs = append(errs, errors.Wrapf(err, "Group: %s", g.Name))
 		}
 
+		if g.Limit < 0 {
+			errs = append(errs, errors.Errorf("Group: %s: limit must not be negative", g.Name))
+		}
+
+		for k, v := range g.ExternalLabels {
+			if !model.LabelName(k).IsValid() {
+				errs = append(errs, errors.Errorf("Group: %s: invalid external label name: %s", g.Name, k))
+			}
+			if !model.LabelValue(v).IsValid() {
+				errs = append(errs, errors.Errorf("Group: %s: invalid external label value: %s", g.Name, v))
+			}
+		}
+
 		set[g.Name] = struct{}{}
 
 		for i, r := range g.Rules {
@@ -87,7 +100,12 @@ func (g *RuleGroups) Validate() (errs []error) {
 type RuleGroup struct {
 	Name     string         `yaml:"name"`
 	Interval model.Duration `yaml:"interval,omitempty"`
-	Rules    []Rule         `yaml:"rules"`
+	Limit    int            `yaml:"limit,omitempty"`
+	// Labels added to the alerts and series of the group's rules unless
+	// they already have them. They take precedence over the global
+	// external labels.
+	ExternalLabels map[string]string `yaml:"external_labels,omitempty"`
+	Rules          []Rule            `yaml:"rules"`
 
 	// Catches all undefined fields and must be empty after parsing.
 	XXX map[string]interface{} `yaml:",inline"`
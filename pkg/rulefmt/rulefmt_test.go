@@ -65,6 +65,14 @@ func TestParseFileFailure(t *testing.T) {
 			filename: "invalid_record_name.bad.yaml",
 			errMsg:   "invalid recording rule name",
 		},
+		{
+			filename: "negative_limit.bad.yaml",
+			errMsg:   "limit must not be negative",
+		},
+		{
+			filename: "invalid_external_label.bad.yaml",
+			errMsg:   "invalid external label name",
+		},
 	}
 
 	for _, c := range table {
@@ -428,6 +428,7 @@ yyrule1: // \0
 	}
 yyrule2: // #[^\r\n]*\n
 	{
+		l.comment(l.b[l.mstart:l.i])
 		l.mstart = l.i
 		goto yystate0
 	}
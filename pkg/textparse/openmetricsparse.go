@@ -0,0 +1,320 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textparse
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/prometheus/prometheus/pkg/exemplar"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/value"
+)
+
+// OpenMetricsParser parses samples from a byte slice of samples in the
+// OpenMetrics text exposition format.
+type OpenMetricsParser struct {
+	b []byte
+	// Start of the next line.
+	i int
+	// Whether the terminating "# EOF" line was read.
+	eof bool
+
+	mstart, mend int
+	offsets      []int
+	val          float64
+	ts           *int64
+	meta         []Metadata
+
+	// The exemplar of the current sample, if any. Its label offsets
+	// reference the parsed byte slice like the offsets of the sample.
+	hasExemplar  bool
+	estart, eend int
+	eoffsets     []int
+	eval         float64
+	ets          *int64
+
+	err error
+}
+
+// NewOpenMetricsParser returns a new parser of the byte slice.
+func NewOpenMetricsParser(b []byte) *OpenMetricsParser {
+	return &OpenMetricsParser{b: b}
+}
+
+// Next advances the parser to the next sample. It returns false if no
+// more samples were read or an error occurred.
+func (p *OpenMetricsParser) Next() bool {
+	for p.err == nil {
+		if p.i >= len(p.b) {
+			if !p.eof {
+				p.err = errors.New("data does not end with # EOF")
+			}
+			return false
+		}
+		start, end := p.i, len(p.b)
+		if j := bytes.IndexByte(p.b[start:], '\n'); j >= 0 {
+			end = start + j
+		}
+		p.i = end + 1
+
+		line := p.b[start:end]
+		switch {
+		case p.eof:
+			p.err = errors.New("unexpected data after # EOF")
+		case len(line) == 0:
+			p.err = errors.New("unexpected empty line")
+		case string(line) == "# EOF":
+			p.eof = true
+		case line[0] == '#':
+			if m, ok := parseMetadata(line[1:], replacer); ok {
+				p.meta = append(p.meta, m)
+			}
+		default:
+			p.err = p.parseSample(start, end)
+			return p.err == nil
+		}
+	}
+	return false
+}
+
+// parseSample parses the sample line b[start:end].
+func (p *OpenMetricsParser) parseSample(start, end int) error {
+	p.mstart = start
+	p.offsets = p.offsets[:0]
+	p.ts = nil
+	p.hasExemplar = false
+
+	i := p.name(start, end, true)
+	if i == start {
+		return fmt.Errorf("invalid metric name in line %q", p.b[start:end])
+	}
+	p.offsets = append(p.offsets, i)
+
+	if i < end && p.b[i] == '{' {
+		var err error
+		if i, err = p.labels(i+1, end, &p.offsets); err != nil {
+			return err
+		}
+	}
+	p.mend = i
+
+	val, i := nextToken(p.b, i, end)
+	if len(val) == 0 {
+		return fmt.Errorf("missing value in line %q", p.b[start:end])
+	}
+	v, err := strconv.ParseFloat(yoloString(val), 64)
+	if err != nil {
+		return err
+	}
+	if math.IsNaN(v) {
+		v = math.Float64frombits(value.NormalNaN)
+	}
+	p.val = v
+
+	tok, i := nextToken(p.b, i, end)
+	if len(tok) > 0 && tok[0] != '#' {
+		// Timestamps are given in seconds.
+		s, err := strconv.ParseFloat(yoloString(tok), 64)
+		if err != nil {
+			return err
+		}
+		ts := int64(math.Round(s * 1000))
+		p.ts = &ts
+
+		tok, i = nextToken(p.b, i, end)
+	}
+	if len(tok) == 0 {
+		return nil
+	}
+	// The only thing allowed to follow is an exemplar.
+	if string(tok) != "#" || !bytes.HasPrefix(bytes.TrimLeft(p.b[i:end], " \t"), []byte("{")) {
+		return fmt.Errorf("unexpected data %q in line %q", tok, p.b[start:end])
+	}
+	return p.parseExemplar(i, end)
+}
+
+// parseExemplar parses the exemplar in b[i:end] following the "#" of a
+// sample line.
+func (p *OpenMetricsParser) parseExemplar(i, end int) error {
+	p.eoffsets = p.eoffsets[:0]
+	p.ets = nil
+
+	for p.b[i] != '{' {
+		i++
+	}
+	p.estart = i
+
+	i, err := p.labels(i+1, end, &p.eoffsets)
+	if err != nil {
+		return err
+	}
+	p.eend = i
+
+	var n int
+	for j := 0; j < len(p.eoffsets); j += 2 {
+		n += utf8.RuneCount(p.b[p.eoffsets[j]:p.eoffsets[j+1]])
+	}
+	if n > exemplar.MaxLabelSetLength {
+		return fmt.Errorf("exemplar labels exceed %d characters in line %q", exemplar.MaxLabelSetLength, p.b[p.mstart:end])
+	}
+
+	val, i := nextToken(p.b, i, end)
+	if len(val) == 0 {
+		return fmt.Errorf("missing exemplar value in line %q", p.b[p.mstart:end])
+	}
+	if p.eval, err = strconv.ParseFloat(yoloString(val), 64); err != nil {
+		return err
+	}
+
+	tok, i := nextToken(p.b, i, end)
+	if len(tok) > 0 {
+		s, err := strconv.ParseFloat(yoloString(tok), 64)
+		if err != nil {
+			return err
+		}
+		ts := int64(math.Round(s * 1000))
+		p.ets = &ts
+	}
+	if tok, _ = nextToken(p.b, i, end); len(tok) > 0 {
+		return fmt.Errorf("unexpected data %q in line %q", tok, p.b[p.mstart:end])
+	}
+	p.hasExemplar = true
+	return nil
+}
+
+// name returns the end of the metric or label name starting at i.
+func (p *OpenMetricsParser) name(i, end int, metric bool) int {
+	for j := i; j < end; j++ {
+		c := p.b[j]
+		switch {
+		case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		case c == ':' && metric:
+		case c >= '0' && c <= '9' && j > i:
+		default:
+			return j
+		}
+	}
+	return end
+}
+
+// labels parses the label pairs following the opening brace at i-1 into
+// offsets and returns the position after the closing brace.
+func (p *OpenMetricsParser) labels(i, end int, offsets *[]int) (int, error) {
+	for i < end {
+		if p.b[i] == '}' {
+			return i + 1, nil
+		}
+		j := p.name(i, end, false)
+		if j == i || j+1 >= end || p.b[j] != '=' || p.b[j+1] != '"' {
+			return 0, fmt.Errorf("invalid label pair in line %q", p.b[p.mstart:end])
+		}
+		*offsets = append(*offsets, i, j)
+
+		i = j + 2
+		j = i
+		for ; j < end && p.b[j] != '"'; j++ {
+			if p.b[j] == '\\' {
+				j++
+			}
+		}
+		if j >= end {
+			return 0, fmt.Errorf("unterminated label value in line %q", p.b[p.mstart:end])
+		}
+		if !utf8.Valid(p.b[i:j]) {
+			return 0, errors.New("invalid UTF-8 label value")
+		}
+		*offsets = append(*offsets, i, j)
+
+		i = j + 1
+		if i < end && p.b[i] == ',' {
+			i++
+		}
+	}
+	return 0, fmt.Errorf("unterminated label set in line %q", p.b[p.mstart:end])
+}
+
+// nextToken returns the next blank-separated token of b[i:end] and the
+// position following it.
+func nextToken(b []byte, i, end int) ([]byte, int) {
+	for i < end && (b[i] == ' ' || b[i] == '\t') {
+		i++
+	}
+	j := i
+	for j < end && b[j] != ' ' && b[j] != '\t' {
+		j++
+	}
+	return b[i:j], j
+}
+
+// At returns the bytes of the metric, the timestamp if set, and the value
+// of the current sample.
+func (p *OpenMetricsParser) At() ([]byte, *int64, float64) {
+	return p.b[p.mstart:p.mend], p.ts, p.val
+}
+
+// Err returns the current error.
+func (p *OpenMetricsParser) Err() error {
+	return p.err
+}
+
+// Metadata returns the metadata comments read so far. The returned byte
+// slices reference the parsed byte slice unless they had to be unescaped.
+func (p *OpenMetricsParser) Metadata() []Metadata {
+	return p.meta
+}
+
+// Metric writes the labels of the current sample into the passed labels.
+// It returns the string from which the metric was parsed.
+func (p *OpenMetricsParser) Metric(l *labels.Labels) string {
+	return metric(p.b, p.mstart, p.mend, p.offsets, l)
+}
+
+// Exemplar writes the exemplar of the current sample into e. It returns
+// false if the sample has no exemplar. The exemplar has no timestamp if
+// none was exposed.
+func (p *OpenMetricsParser) Exemplar(e *exemplar.Exemplar) bool {
+	if !p.hasExemplar {
+		return false
+	}
+	s := string(p.b[p.estart:p.eend])
+
+	e.Labels = make(labels.Labels, 0, len(p.eoffsets)/4)
+	for i := 0; i < len(p.eoffsets); i += 4 {
+		a := p.eoffsets[i] - p.estart
+		b := p.eoffsets[i+1] - p.estart
+		c := p.eoffsets[i+2] - p.estart
+		d := p.eoffsets[i+3] - p.estart
+
+		v := s[c:d]
+		if strings.IndexByte(v, byte('\\')) >= 0 {
+			v = replacer.Replace(v)
+		}
+		e.Labels = append(e.Labels, labels.Label{Name: s[a:b], Value: v})
+	}
+	sort.Sort(e.Labels)
+
+	e.Value = p.eval
+	e.Ts, e.HasTs = 0, p.ets != nil
+	if e.HasTs {
+		e.Ts = *p.ets
+	}
+	return true
+}
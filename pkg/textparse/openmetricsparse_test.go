@@ -0,0 +1,189 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textparse
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/prometheus/pkg/exemplar"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/value"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenMetricsParse(t *testing.T) {
+	input := `# HELP go_gc_duration_seconds A summary of the GC invocation durations.
+# TYPE go_gc_duration_seconds summary
+# UNIT go_gc_duration_seconds seconds
+go_gc_duration_seconds{quantile="0"} 4.9351e-05
+go_gc_duration_seconds{quantile="0.25",a="b"} 7.424100000000001e-05
+go_gc_duration_seconds_count 99
+# HELP http_requests Requests with a "quoted" help text.
+# TYPE http_requests counter
+http_requests_total{path="/foo\"bar\\"} 17 1520879607.789
+http_requests_total{path="/"} 3 # {trace_id="KOO5S4vxi0o"} 0.67
+http_requests_total{path="/ex"} 4 1520879607 # {trace_id="oHg5SJYRHA0"} 9.8 1520879607.789
+nan_metric NaN
+# EOF
+`
+	int64p := func(x int64) *int64 { return &x }
+
+	exp := []struct {
+		lset labels.Labels
+		m    string
+		t    *int64
+		v    float64
+		e    *exemplar.Exemplar
+	}{
+		{
+			m:    `go_gc_duration_seconds{quantile="0"}`,
+			v:    4.9351e-05,
+			lset: labels.FromStrings("__name__", "go_gc_duration_seconds", "quantile", "0"),
+		}, {
+			m:    `go_gc_duration_seconds{quantile="0.25",a="b"}`,
+			v:    7.424100000000001e-05,
+			lset: labels.FromStrings("__name__", "go_gc_duration_seconds", "quantile", "0.25", "a", "b"),
+		}, {
+			m:    `go_gc_duration_seconds_count`,
+			v:    99,
+			lset: labels.FromStrings("__name__", "go_gc_duration_seconds_count"),
+		}, {
+			m:    `http_requests_total{path="/foo\"bar\\"}`,
+			v:    17,
+			t:    int64p(1520879607789),
+			lset: labels.FromStrings("__name__", "http_requests_total", "path", `/foo"bar\`),
+		}, {
+			m:    `http_requests_total{path="/"}`,
+			v:    3,
+			lset: labels.FromStrings("__name__", "http_requests_total", "path", "/"),
+			e:    &exemplar.Exemplar{Labels: labels.FromStrings("trace_id", "KOO5S4vxi0o"), Value: 0.67},
+		}, {
+			m:    `http_requests_total{path="/ex"}`,
+			v:    4,
+			t:    int64p(1520879607000),
+			lset: labels.FromStrings("__name__", "http_requests_total", "path", "/ex"),
+			e:    &exemplar.Exemplar{Labels: labels.FromStrings("trace_id", "oHg5SJYRHA0"), Value: 9.8, Ts: 1520879607789, HasTs: true},
+		}, {
+			m:    `nan_metric`,
+			v:    math.Float64frombits(value.NormalNaN),
+			lset: labels.FromStrings("__name__", "nan_metric"),
+		},
+	}
+
+	p := New([]byte(input), "application/openmetrics-text; version=0.0.1; charset=utf-8")
+	i := 0
+
+	var res labels.Labels
+
+	for p.Next() {
+		m, ts, v := p.At()
+
+		p.Metric(&res)
+
+		require.Equal(t, exp[i].m, string(m))
+		require.Equal(t, exp[i].t, ts)
+		require.Equal(t, math.Float64bits(exp[i].v), math.Float64bits(v))
+		require.Equal(t, exp[i].lset, res)
+
+		var e exemplar.Exemplar
+		if exp[i].e == nil {
+			require.False(t, p.Exemplar(&e))
+		} else {
+			require.True(t, p.Exemplar(&e))
+			require.Equal(t, *exp[i].e, e)
+		}
+
+		i++
+		res = res[:0]
+	}
+	require.NoError(t, p.Err())
+	require.Equal(t, len(exp), i)
+
+	meta := p.Metadata()
+	require.Equal(t, 5, len(meta))
+	require.Equal(t, MetadataUnit, meta[2].Kind)
+	require.Equal(t, "seconds", string(meta[2].Text))
+	require.Equal(t, `Requests with a "quoted" help text.`, string(meta[3].Text))
+}
+
+func TestOpenMetricsParseErrors(t *testing.T) {
+	cases := []struct {
+		input string
+		err   string
+	}{
+		{
+			input: "a 1\n",
+			err:   "data does not end with # EOF",
+		},
+		{
+			input: "a 1\n# EOF\nb 1\n",
+			err:   "unexpected data after # EOF",
+		},
+		{
+			input: "a 1\n\n# EOF\n",
+			err:   "unexpected empty line",
+		},
+		{
+			input: "a{b='c'} 1\n# EOF\n",
+			err:   `invalid label pair in line "a{b='c'} 1"`,
+		},
+		{
+			input: "a{b=\"\xff\"} 1\n# EOF\n",
+			err:   "invalid UTF-8 label value",
+		},
+		{
+			input: "a true\n# EOF\n",
+			err:   "strconv.ParseFloat: parsing \"true\": invalid syntax",
+		},
+		{
+			input: "a 1 2 3\n# EOF\n",
+			err:   `unexpected data "3" in line "a 1 2 3"`,
+		},
+		{
+			input: "a 1 # trace\n# EOF\n",
+			err:   `unexpected data "#" in line "a 1 # trace"`,
+		},
+		{
+			input: "a 1 # {b=\"c\"}\n# EOF\n",
+			err:   `missing exemplar value in line "a 1 # {b=\"c\"}"`,
+		},
+		{
+			input: "a 1 # {b=\"c\"} 1 2 3\n# EOF\n",
+			err:   `unexpected data "3" in line "a 1 # {b=\"c\"} 1 2 3"`,
+		},
+		{
+			input: "a 1 # {b=\"" + strings.Repeat("c", 128) + "\"} 1\n# EOF\n",
+			err:   `exemplar labels exceed 128 characters in line "a 1 # {b=\"` + strings.Repeat("c", 128) + `\"} 1"`,
+		},
+	}
+
+	for _, c := range cases {
+		p := NewOpenMetricsParser([]byte(c.input))
+		for p.Next() {
+		}
+		require.NotNil(t, p.Err())
+		require.Equal(t, c.err, p.Err().Error())
+	}
+}
+
+func TestNewParserContentType(t *testing.T) {
+	_, ok := New(nil, "application/openmetrics-text; version=0.0.1").(*OpenMetricsParser)
+	require.True(t, ok)
+	_, ok = New(nil, "text/plain; version=0.0.4").(*PromParser)
+	require.True(t, ok)
+	_, ok = New(nil, "").(*PromParser)
+	require.True(t, ok)
+}
@@ -14,16 +14,20 @@
 //go:generate go get github.com/cznic/golex
 //go:generate golex -o=lex.l.go lex.l
 
-// Package textparse contains an efficient parser for the Prometheus text format.
+// Package textparse contains efficient parsers for the Prometheus and
+// OpenMetrics text formats.
 package textparse
 
 import (
+	"bytes"
 	"errors"
 	"io"
+	"mime"
 	"sort"
 	"strings"
 	"unsafe"
 
+	"github.com/prometheus/prometheus/pkg/exemplar"
 	"github.com/prometheus/prometheus/pkg/labels"
 )
 
@@ -39,6 +43,7 @@ type lexer struct {
 	offsets      []int
 	mstart, mend int
 	nextMstart   int
+	meta         []Metadata
 
 	state int
 }
@@ -64,22 +69,142 @@ func (l *lexer) Error(es string) {
 	l.err = errors.New(es)
 }
 
-// Parser parses samples from a byte slice of samples in the official
+// comment records the metadata held by a HELP, TYPE or UNIT comment line.
+// All other comments are ignored.
+func (l *lexer) comment(b []byte) {
+	if m, ok := parseMetadata(bytes.TrimRight(b[1:], "\r\n"), helpReplacer); ok {
+		l.meta = append(l.meta, m)
+	}
+}
+
+// parseMetadata parses the text of a HELP, TYPE or UNIT comment following
+// the '#'. Help texts are unescaped with the given replacer.
+func parseMetadata(b []byte, unescape *strings.Replacer) (Metadata, bool) {
+	kw, b := nextField(b)
+	var kind MetadataKind
+
+	switch string(kw) {
+	case "HELP":
+		kind = MetadataHelp
+	case "TYPE":
+		kind = MetadataType
+	case "UNIT":
+		kind = MetadataUnit
+	default:
+		return Metadata{}, false
+	}
+	name, b := nextField(b)
+	if len(name) == 0 {
+		return Metadata{}, false
+	}
+	text := bytes.TrimLeft(b, " \t")
+
+	switch kind {
+	case MetadataHelp:
+		// Replacer causes allocations. Replace only when necessary.
+		if bytes.IndexByte(text, byte('\\')) >= 0 {
+			text = []byte(unescape.Replace(string(text)))
+		}
+	default:
+		text = bytes.TrimRight(text, " \t")
+	}
+	return Metadata{Kind: kind, Metric: name, Text: text}, true
+}
+
+// nextField returns the next blank-separated field of b and the remainder
+// following it.
+func nextField(b []byte) ([]byte, []byte) {
+	b = bytes.TrimLeft(b, " \t")
+	i := bytes.IndexAny(b, " \t")
+	if i < 0 {
+		return b, nil
+	}
+	return b[:i], b[i:]
+}
+
+// MetricType represents metric type values.
+type MetricType string
+
+// The metric types of the text exposition formats.
+const (
+	MetricTypeCounter        = MetricType("counter")
+	MetricTypeGauge          = MetricType("gauge")
+	MetricTypeHistogram      = MetricType("histogram")
+	MetricTypeGaugeHistogram = MetricType("gaugehistogram")
+	MetricTypeSummary        = MetricType("summary")
+	MetricTypeInfo           = MetricType("info")
+	MetricTypeStateset       = MetricType("stateset")
+	MetricTypeUnknown        = MetricType("unknown")
+	MetricTypeUntyped        = MetricType("untyped")
+)
+
+// MetadataKind is the kind of information held by a metadata comment.
+type MetadataKind int
+
+// The possible kinds of metadata comments.
+const (
+	MetadataHelp MetadataKind = iota
+	MetadataType
+	MetadataUnit
+)
+
+// Metadata is the information of a HELP, TYPE or UNIT comment about the
+// metric family with the given name.
+type Metadata struct {
+	Kind   MetadataKind
+	Metric []byte
+	Text   []byte
+}
+
+// Parser parses samples from a byte slice of samples in one of the text
+// exposition formats.
+type Parser interface {
+	// Next advances the parser to the next sample. It returns false if no
+	// more samples were read or an error occurred.
+	Next() bool
+	// At returns the bytes of the metric, the timestamp if set, and the value
+	// of the current sample.
+	At() ([]byte, *int64, float64)
+	// Err returns the current error.
+	Err() error
+	// Metadata returns the metadata comments read so far. The returned byte
+	// slices reference the parsed byte slice unless they had to be unescaped.
+	Metadata() []Metadata
+	// Metric writes the labels of the current sample into the passed labels.
+	// It returns the string from which the metric was parsed.
+	Metric(l *labels.Labels) string
+	// Exemplar writes the exemplar of the current sample into e. It returns
+	// false if the sample has no exemplar.
+	Exemplar(e *exemplar.Exemplar) bool
+}
+
+// New returns a parser of the byte slice for the format indicated by the
+// content type of a scrape response. Unknown content types are parsed as the
+// Prometheus text format.
+func New(b []byte, contentType string) Parser {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil && mediaType == "application/openmetrics-text" {
+		return NewOpenMetricsParser(b)
+	}
+	return NewPromParser(b)
+}
+
+// PromParser parses samples from a byte slice of samples in the official
 // Prometheus text exposition format.
-type Parser struct {
+type PromParser struct {
 	l   *lexer
 	err error
 	val float64
 }
 
-// New returns a new parser of the byte slice.
-func New(b []byte) *Parser {
-	return &Parser{l: &lexer{b: b}}
+// NewPromParser returns a new parser of the byte slice.
+func NewPromParser(b []byte) *PromParser {
+	return &PromParser{l: &lexer{b: b}}
 }
 
 // Next advances the parser to the next sample. It returns false if no
 // more samples were read or an error occurred.
-func (p *Parser) Next() bool {
+func (p *PromParser) Next() bool {
 	switch p.l.Lex() {
 	case -1, eof:
 		return false
@@ -91,12 +216,12 @@ func (p *Parser) Next() bool {
 
 // At returns the bytes of the metric, the timestamp if set, and the value
 // of the current sample.
-func (p *Parser) At() ([]byte, *int64, float64) {
+func (p *PromParser) At() ([]byte, *int64, float64) {
 	return p.l.b[p.l.mstart:p.l.mend], p.l.ts, p.l.val
 }
 
 // Err returns the current error.
-func (p *Parser) Err() error {
+func (p *PromParser) Err() error {
 	if p.err != nil {
 		return p.err
 	}
@@ -106,23 +231,42 @@ func (p *Parser) Err() error {
 	return p.l.err
 }
 
+// Metadata returns the metadata comments read so far. The returned byte
+// slices reference the parsed byte slice unless they had to be unescaped.
+func (p *PromParser) Metadata() []Metadata {
+	return p.l.meta
+}
+
 // Metric writes the labels of the current sample into the passed labels.
 // It returns the string from which the metric was parsed.
-func (p *Parser) Metric(l *labels.Labels) string {
+func (p *PromParser) Metric(l *labels.Labels) string {
+	return metric(p.l.b, p.l.mstart, p.l.mend, p.l.offsets, l)
+}
+
+// Exemplar implements the Parser interface. The Prometheus text format
+// has no exemplars, so it always returns false.
+func (p *PromParser) Exemplar(*exemplar.Exemplar) bool {
+	return false
+}
+
+// metric writes the labels of the metric b[mstart:mend] into the passed
+// labels. The first offset is the end of the metric name, followed by the
+// start and end of each label name and value.
+func metric(b []byte, mstart, mend int, offsets []int, l *labels.Labels) string {
 	// Allocate the full immutable string immediately, so we just
 	// have to create references on it below.
-	s := string(p.l.b[p.l.mstart:p.l.mend])
+	s := string(b[mstart:mend])
 
 	*l = append(*l, labels.Label{
 		Name:  labels.MetricName,
-		Value: s[:p.l.offsets[0]-p.l.mstart],
+		Value: s[:offsets[0]-mstart],
 	})
 
-	for i := 1; i < len(p.l.offsets); i += 4 {
-		a := p.l.offsets[i] - p.l.mstart
-		b := p.l.offsets[i+1] - p.l.mstart
-		c := p.l.offsets[i+2] - p.l.mstart
-		d := p.l.offsets[i+3] - p.l.mstart
+	for i := 1; i < len(offsets); i += 4 {
+		a := offsets[i] - mstart
+		b := offsets[i+1] - mstart
+		c := offsets[i+2] - mstart
+		d := offsets[i+3] - mstart
 
 		// Replacer causes allocations. Replace only when necessary.
 		if strings.IndexByte(s[c:d], byte('\\')) >= 0 {
@@ -146,6 +290,12 @@ var replacer = strings.NewReplacer(
 	`\t`, `	`,
 )
 
+var helpReplacer = strings.NewReplacer(
+	`\\`, `\`,
+	`\n`, `
+`,
+)
+
 func yoloString(b []byte) string {
 	return *((*string)(unsafe.Pointer(&b)))
 }
@@ -125,7 +125,7 @@ testmetric{label="\"bar\""} 1`
 		},
 	}
 
-	p := New([]byte(input))
+	p := NewPromParser([]byte(input))
 	i := 0
 
 	var res labels.Labels
@@ -149,6 +149,45 @@ testmetric{label="\"bar\""} 1`
 
 }
 
+func TestParseMetadata(t *testing.T) {
+	input := `# HELP go_gc_duration_seconds A summary of the GC invocation durations.
+# TYPE go_gc_duration_seconds summary
+go_gc_duration_seconds_count 99
+# Some other comment.
+# HELP  go_goroutines	Number of goroutines  that currently exist.\nWith a \\ backslash.
+# TYPE go_goroutines gauge 
+# UNIT go_memstats_alloc_bytes bytes
+# HELP empty_help
+go_goroutines 33
+`
+
+	exp := []struct {
+		kind         MetadataKind
+		metric, text string
+	}{
+		{kind: MetadataHelp, metric: "go_gc_duration_seconds", text: "A summary of the GC invocation durations."},
+		{kind: MetadataType, metric: "go_gc_duration_seconds", text: "summary"},
+		{kind: MetadataHelp, metric: "go_goroutines", text: "Number of goroutines  that currently exist.\nWith a \\ backslash."},
+		{kind: MetadataType, metric: "go_goroutines", text: "gauge"},
+		{kind: MetadataUnit, metric: "go_memstats_alloc_bytes", text: "bytes"},
+		{kind: MetadataHelp, metric: "empty_help", text: ""},
+	}
+
+	p := NewPromParser([]byte(input))
+	for p.Next() {
+	}
+	require.NoError(t, p.Err())
+
+	meta := p.Metadata()
+	require.Equal(t, len(exp), len(meta))
+
+	for i, m := range meta {
+		require.Equal(t, exp[i].kind, m.Kind)
+		require.Equal(t, exp[i].metric, string(m.Metric))
+		require.Equal(t, exp[i].text, string(m.Text))
+	}
+}
+
 func TestParseErrors(t *testing.T) {
 	cases := []struct {
 		input string
@@ -185,7 +224,7 @@ func TestParseErrors(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		p := New([]byte(c.input))
+		p := NewPromParser([]byte(c.input))
 		for p.Next() {
 		}
 		require.NotNil(t, p.Err())
@@ -233,7 +272,7 @@ func TestNullByteHandling(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		p := New([]byte(c.input))
+		p := NewPromParser([]byte(c.input))
 		for p.Next() {
 		}
 
@@ -268,7 +307,7 @@ func BenchmarkParse(b *testing.B) {
 			b.ResetTimer()
 
 			for i := 0; i < b.N; i += testdataSampleCount {
-				p := New(buf)
+				p := NewPromParser(buf)
 
 				for p.Next() && i < b.N {
 					m, _, _ := p.At()
@@ -288,7 +327,7 @@ func BenchmarkParse(b *testing.B) {
 			b.ResetTimer()
 
 			for i := 0; i < b.N; i += testdataSampleCount {
-				p := New(buf)
+				p := NewPromParser(buf)
 
 				for p.Next() && i < b.N {
 					m, _, _ := p.At()
@@ -312,7 +351,7 @@ func BenchmarkParse(b *testing.B) {
 			b.ResetTimer()
 
 			for i := 0; i < b.N; i += testdataSampleCount {
-				p := New(buf)
+				p := NewPromParser(buf)
 
 				for p.Next() && i < b.N {
 					m, _, _ := p.At()
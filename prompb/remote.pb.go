@@ -14,7 +14,10 @@
 		ReadRequest
 		ReadResponse
 		Query
+		ReadHints
 		QueryResult
+		LabelQuery
+		LabelQueryResult
 		TSDBSnapshotRequest
 		TSDBSnapshotResponse
 		SeriesDeleteRequest
@@ -24,6 +27,7 @@
 		Label
 		Labels
 		LabelMatcher
+		MetricMetadata
 */
 package prompb
 
@@ -45,7 +49,8 @@ var _ = math.Inf
 const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 
 type WriteRequest struct {
-	Timeseries []*TimeSeries `protobuf:"bytes,1,rep,name=timeseries" json:"timeseries,omitempty"`
+	Timeseries []*TimeSeries     `protobuf:"bytes,1,rep,name=timeseries" json:"timeseries,omitempty"`
+	Metadata   []*MetricMetadata `protobuf:"bytes,3,rep,name=metadata" json:"metadata,omitempty"`
 }
 
 func (m *WriteRequest) Reset()                    { *m = WriteRequest{} }
@@ -60,8 +65,16 @@ func (m *WriteRequest) GetTimeseries() []*TimeSeries {
 	return nil
 }
 
+func (m *WriteRequest) GetMetadata() []*MetricMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
 type ReadRequest struct {
-	Queries []*Query `protobuf:"bytes,1,rep,name=queries" json:"queries,omitempty"`
+	Queries      []*Query      `protobuf:"bytes,1,rep,name=queries" json:"queries,omitempty"`
+	LabelQueries []*LabelQuery `protobuf:"bytes,2,rep,name=label_queries,json=labelQueries" json:"label_queries,omitempty"`
 }
 
 func (m *ReadRequest) Reset()                    { *m = ReadRequest{} }
@@ -76,9 +89,18 @@ func (m *ReadRequest) GetQueries() []*Query {
 	return nil
 }
 
+func (m *ReadRequest) GetLabelQueries() []*LabelQuery {
+	if m != nil {
+		return m.LabelQueries
+	}
+	return nil
+}
+
 type ReadResponse struct {
 	// In same order as the request's queries.
-	Results []*QueryResult `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
+	Results      []*QueryResult      `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
+	// In same order as the request's label queries.
+	LabelResults []*LabelQueryResult `protobuf:"bytes,2,rep,name=label_results,json=labelResults" json:"label_results,omitempty"`
 }
 
 func (m *ReadResponse) Reset()                    { *m = ReadResponse{} }
@@ -93,10 +115,18 @@ func (m *ReadResponse) GetResults() []*QueryResult {
 	return nil
 }
 
+func (m *ReadResponse) GetLabelResults() []*LabelQueryResult {
+	if m != nil {
+		return m.LabelResults
+	}
+	return nil
+}
+
 type Query struct {
 	StartTimestampMs int64           `protobuf:"varint,1,opt,name=start_timestamp_ms,json=startTimestampMs,proto3" json:"start_timestamp_ms,omitempty"`
 	EndTimestampMs   int64           `protobuf:"varint,2,opt,name=end_timestamp_ms,json=endTimestampMs,proto3" json:"end_timestamp_ms,omitempty"`
 	Matchers         []*LabelMatcher `protobuf:"bytes,3,rep,name=matchers" json:"matchers,omitempty"`
+	Hints            *ReadHints      `protobuf:"bytes,4,opt,name=hints" json:"hints,omitempty"`
 }
 
 func (m *Query) Reset()                    { *m = Query{} }
@@ -125,6 +155,86 @@ func (m *Query) GetMatchers() []*LabelMatcher {
 	return nil
 }
 
+func (m *Query) GetHints() *ReadHints {
+	if m != nil {
+		return m.Hints
+	}
+	return nil
+}
+
+// ReadHints describe the query a selection is made for, so that remote
+// storages may pre-aggregate the returned data. Hints may be ignored.
+type ReadHints struct {
+	// Query step size in milliseconds.
+	StepMs   int64    `protobuf:"varint,1,opt,name=step_ms,json=stepMs,proto3" json:"step_ms,omitempty"`
+	// String representation of the surrounding function or aggregation.
+	Func     string   `protobuf:"bytes,2,opt,name=func,proto3" json:"func,omitempty"`
+	// Start time of the selection in milliseconds.
+	StartMs  int64    `protobuf:"varint,3,opt,name=start_ms,json=startMs,proto3" json:"start_ms,omitempty"`
+	// End time of the selection in milliseconds.
+	EndMs    int64    `protobuf:"varint,4,opt,name=end_ms,json=endMs,proto3" json:"end_ms,omitempty"`
+	// Labels of the surrounding aggregation.
+	Grouping []string `protobuf:"bytes,5,rep,name=grouping" json:"grouping,omitempty"`
+	// Whether the series are grouped by or without the grouping labels.
+	By       bool     `protobuf:"varint,6,opt,name=by,proto3" json:"by,omitempty"`
+	// Range of the selector in milliseconds, for range vector selectors.
+	RangeMs  int64    `protobuf:"varint,7,opt,name=range_ms,json=rangeMs,proto3" json:"range_ms,omitempty"`
+}
+
+func (m *ReadHints) Reset()                    { *m = ReadHints{} }
+func (m *ReadHints) String() string            { return proto.CompactTextString(m) }
+func (*ReadHints) ProtoMessage()               {}
+func (*ReadHints) Descriptor() ([]byte, []int) { return fileDescriptorRemote, []int{4} }
+
+func (m *ReadHints) GetStepMs() int64 {
+	if m != nil {
+		return m.StepMs
+	}
+	return 0
+}
+
+func (m *ReadHints) GetFunc() string {
+	if m != nil {
+		return m.Func
+	}
+	return ""
+}
+
+func (m *ReadHints) GetStartMs() int64 {
+	if m != nil {
+		return m.StartMs
+	}
+	return 0
+}
+
+func (m *ReadHints) GetEndMs() int64 {
+	if m != nil {
+		return m.EndMs
+	}
+	return 0
+}
+
+func (m *ReadHints) GetGrouping() []string {
+	if m != nil {
+		return m.Grouping
+	}
+	return nil
+}
+
+func (m *ReadHints) GetBy() bool {
+	if m != nil {
+		return m.By
+	}
+	return false
+}
+
+func (m *ReadHints) GetRangeMs() int64 {
+	if m != nil {
+		return m.RangeMs
+	}
+	return 0
+}
+
 type QueryResult struct {
 	Timeseries []*TimeSeries `protobuf:"bytes,1,rep,name=timeseries" json:"timeseries,omitempty"`
 }
@@ -132,7 +242,7 @@ type QueryResult struct {
 func (m *QueryResult) Reset()                    { *m = QueryResult{} }
 func (m *QueryResult) String() string            { return proto.CompactTextString(m) }
 func (*QueryResult) ProtoMessage()               {}
-func (*QueryResult) Descriptor() ([]byte, []int) { return fileDescriptorRemote, []int{4} }
+func (*QueryResult) Descriptor() ([]byte, []int) { return fileDescriptorRemote, []int{5} }
 
 func (m *QueryResult) GetTimeseries() []*TimeSeries {
 	if m != nil {
@@ -141,12 +251,66 @@ func (m *QueryResult) GetTimeseries() []*TimeSeries {
 	return nil
 }
 
+// LabelQuery requests the label names, or the values of a single label,
+// of the series within a time range.
+type LabelQuery struct {
+	StartTimestampMs int64  `protobuf:"varint,1,opt,name=start_timestamp_ms,json=startTimestampMs,proto3" json:"start_timestamp_ms,omitempty"`
+	EndTimestampMs   int64  `protobuf:"varint,2,opt,name=end_timestamp_ms,json=endTimestampMs,proto3" json:"end_timestamp_ms,omitempty"`
+	// The label name to return the values of. Label names are returned if empty.
+	Name             string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (m *LabelQuery) Reset()                    { *m = LabelQuery{} }
+func (m *LabelQuery) String() string            { return proto.CompactTextString(m) }
+func (*LabelQuery) ProtoMessage()               {}
+func (*LabelQuery) Descriptor() ([]byte, []int) { return fileDescriptorRemote, []int{6} }
+
+func (m *LabelQuery) GetStartTimestampMs() int64 {
+	if m != nil {
+		return m.StartTimestampMs
+	}
+	return 0
+}
+
+func (m *LabelQuery) GetEndTimestampMs() int64 {
+	if m != nil {
+		return m.EndTimestampMs
+	}
+	return 0
+}
+
+func (m *LabelQuery) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type LabelQueryResult struct {
+	Values []string `protobuf:"bytes,1,rep,name=values" json:"values,omitempty"`
+}
+
+func (m *LabelQueryResult) Reset()                    { *m = LabelQueryResult{} }
+func (m *LabelQueryResult) String() string            { return proto.CompactTextString(m) }
+func (*LabelQueryResult) ProtoMessage()               {}
+func (*LabelQueryResult) Descriptor() ([]byte, []int) { return fileDescriptorRemote, []int{7} }
+
+func (m *LabelQueryResult) GetValues() []string {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*WriteRequest)(nil), "prometheus.WriteRequest")
 	proto.RegisterType((*ReadRequest)(nil), "prometheus.ReadRequest")
 	proto.RegisterType((*ReadResponse)(nil), "prometheus.ReadResponse")
 	proto.RegisterType((*Query)(nil), "prometheus.Query")
+	proto.RegisterType((*ReadHints)(nil), "prometheus.ReadHints")
 	proto.RegisterType((*QueryResult)(nil), "prometheus.QueryResult")
+	proto.RegisterType((*LabelQuery)(nil), "prometheus.LabelQuery")
+	proto.RegisterType((*LabelQueryResult)(nil), "prometheus.LabelQueryResult")
 }
 func (m *WriteRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
@@ -175,6 +339,18 @@ func (m *WriteRequest) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
+	if len(m.Metadata) > 0 {
+		for _, msg := range m.Metadata {
+			dAtA[i] = 0x1a
+			i++
+			i = encodeVarintRemote(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	return i, nil
 }
 
@@ -205,6 +381,18 @@ func (m *ReadRequest) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
+	if len(m.LabelQueries) > 0 {
+		for _, msg := range m.LabelQueries {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintRemote(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	return i, nil
 }
 
@@ -235,6 +423,18 @@ func (m *ReadResponse) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
+	if len(m.LabelResults) > 0 {
+		for _, msg := range m.LabelResults {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintRemote(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	return i, nil
 }
 
@@ -275,6 +475,85 @@ func (m *Query) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
+	if m.Hints != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintRemote(dAtA, i, uint64(m.Hints.Size()))
+		n1, err := m.Hints.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	return i, nil
+}
+
+func (m *ReadHints) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReadHints) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.StepMs != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintRemote(dAtA, i, uint64(m.StepMs))
+	}
+	if len(m.Func) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintRemote(dAtA, i, uint64(len(m.Func)))
+		i += copy(dAtA[i:], m.Func)
+	}
+	if m.StartMs != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintRemote(dAtA, i, uint64(m.StartMs))
+	}
+	if m.EndMs != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintRemote(dAtA, i, uint64(m.EndMs))
+	}
+	if len(m.Grouping) > 0 {
+		for _, s := range m.Grouping {
+			dAtA[i] = 0x2a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if m.By {
+		dAtA[i] = 0x30
+		i++
+		if m.By {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.RangeMs != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintRemote(dAtA, i, uint64(m.RangeMs))
+	}
 	return i, nil
 }
 
@@ -308,6 +587,73 @@ func (m *QueryResult) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *LabelQuery) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LabelQuery) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.StartTimestampMs != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintRemote(dAtA, i, uint64(m.StartTimestampMs))
+	}
+	if m.EndTimestampMs != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintRemote(dAtA, i, uint64(m.EndTimestampMs))
+	}
+	if len(m.Name) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintRemote(dAtA, i, uint64(len(m.Name)))
+		i += copy(dAtA[i:], m.Name)
+	}
+	return i, nil
+}
+
+func (m *LabelQueryResult) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LabelQueryResult) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Values) > 0 {
+		for _, s := range m.Values {
+			dAtA[i] = 0xa
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	return i, nil
+}
+
 func encodeVarintRemote(dAtA []byte, offset int, v uint64) int {
 	for v >= 1<<7 {
 		dAtA[offset] = uint8(v&0x7f | 0x80)
@@ -326,6 +672,12 @@ func (m *WriteRequest) Size() (n int) {
 			n += 1 + l + sovRemote(uint64(l))
 		}
 	}
+	if len(m.Metadata) > 0 {
+		for _, e := range m.Metadata {
+			l = e.Size()
+			n += 1 + l + sovRemote(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -338,6 +690,12 @@ func (m *ReadRequest) Size() (n int) {
 			n += 1 + l + sovRemote(uint64(l))
 		}
 	}
+	if len(m.LabelQueries) > 0 {
+		for _, e := range m.LabelQueries {
+			l = e.Size()
+			n += 1 + l + sovRemote(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -350,6 +708,12 @@ func (m *ReadResponse) Size() (n int) {
 			n += 1 + l + sovRemote(uint64(l))
 		}
 	}
+	if len(m.LabelResults) > 0 {
+		for _, e := range m.LabelResults {
+			l = e.Size()
+			n += 1 + l + sovRemote(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -368,6 +732,41 @@ func (m *Query) Size() (n int) {
 			n += 1 + l + sovRemote(uint64(l))
 		}
 	}
+	if m.Hints != nil {
+		l = m.Hints.Size()
+		n += 1 + l + sovRemote(uint64(l))
+	}
+	return n
+}
+
+func (m *ReadHints) Size() (n int) {
+	var l int
+	_ = l
+	if m.StepMs != 0 {
+		n += 1 + sovRemote(uint64(m.StepMs))
+	}
+	l = len(m.Func)
+	if l > 0 {
+		n += 1 + l + sovRemote(uint64(l))
+	}
+	if m.StartMs != 0 {
+		n += 1 + sovRemote(uint64(m.StartMs))
+	}
+	if m.EndMs != 0 {
+		n += 1 + sovRemote(uint64(m.EndMs))
+	}
+	if len(m.Grouping) > 0 {
+		for _, s := range m.Grouping {
+			l = len(s)
+			n += 1 + l + sovRemote(uint64(l))
+		}
+	}
+	if m.By {
+		n += 2
+	}
+	if m.RangeMs != 0 {
+		n += 1 + sovRemote(uint64(m.RangeMs))
+	}
 	return n
 }
 
@@ -383,6 +782,34 @@ func (m *QueryResult) Size() (n int) {
 	return n
 }
 
+func (m *LabelQuery) Size() (n int) {
+	var l int
+	_ = l
+	if m.StartTimestampMs != 0 {
+		n += 1 + sovRemote(uint64(m.StartTimestampMs))
+	}
+	if m.EndTimestampMs != 0 {
+		n += 1 + sovRemote(uint64(m.EndTimestampMs))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovRemote(uint64(l))
+	}
+	return n
+}
+
+func (m *LabelQueryResult) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Values) > 0 {
+		for _, s := range m.Values {
+			l = len(s)
+			n += 1 + l + sovRemote(uint64(l))
+		}
+	}
+	return n
+}
+
 func sovRemote(x uint64) (n int) {
 	for {
 		n++
@@ -456,13 +883,44 @@ func (m *WriteRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipRemote(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
 			}
-			if skippy < 0 {
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemote
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRemote
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Metadata = append(m.Metadata, &MetricMetadata{})
+			if err := m.Metadata[len(m.Metadata)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRemote(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
 				return ErrInvalidLengthRemote
 			}
 			if (iNdEx + skippy) > l {
@@ -537,6 +995,37 @@ func (m *ReadRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LabelQueries", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemote
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRemote
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LabelQueries = append(m.LabelQueries, &LabelQuery{})
+			if err := m.LabelQueries[len(m.LabelQueries)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRemote(dAtA[iNdEx:])
@@ -618,6 +1107,37 @@ func (m *ReadResponse) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LabelResults", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemote
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRemote
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LabelResults = append(m.LabelResults, &LabelQueryResult{})
+			if err := m.LabelResults[len(m.LabelResults)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRemote(dAtA[iNdEx:])
@@ -737,6 +1257,243 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hints", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemote
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRemote
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Hints == nil {
+				m.Hints = &ReadHints{}
+			}
+			if err := m.Hints.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRemote(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRemote
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ReadHints) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRemote
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ReadHints: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ReadHints: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StepMs", wireType)
+			}
+			m.StepMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemote
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StepMs |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Func", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemote
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRemote
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Func = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartMs", wireType)
+			}
+			m.StartMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemote
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StartMs |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EndMs", wireType)
+			}
+			m.EndMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemote
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EndMs |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Grouping", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemote
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRemote
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Grouping = append(m.Grouping, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field By", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemote
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.By = bool(v != 0)
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RangeMs", wireType)
+			}
+			m.RangeMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemote
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RangeMs |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRemote(dAtA[iNdEx:])
@@ -839,6 +1596,202 @@ func (m *QueryResult) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *LabelQuery) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRemote
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LabelQuery: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LabelQuery: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTimestampMs", wireType)
+			}
+			m.StartTimestampMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemote
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StartTimestampMs |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EndTimestampMs", wireType)
+			}
+			m.EndTimestampMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemote
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EndTimestampMs |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemote
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRemote
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRemote(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRemote
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LabelQueryResult) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRemote
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LabelQueryResult: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LabelQueryResult: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Values", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemote
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRemote
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Values = append(m.Values, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRemote(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRemote
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipRemote(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -947,23 +1900,38 @@ var (
 func init() { proto.RegisterFile("remote.proto", fileDescriptorRemote) }
 
 var fileDescriptorRemote = []byte{
-	// 284 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x91, 0xcf, 0x4a, 0xc3, 0x40,
-	0x10, 0xc6, 0x59, 0x8b, 0xad, 0x4c, 0x8a, 0xd4, 0x45, 0x34, 0x78, 0x08, 0x92, 0x53, 0x40, 0x09,
-	0xf8, 0x07, 0x0f, 0xde, 0x14, 0xf4, 0x64, 0x0f, 0xae, 0x05, 0xc1, 0x4b, 0x49, 0xcc, 0x40, 0x03,
-	0xdd, 0x64, 0xbb, 0x33, 0x39, 0xf4, 0x41, 0x7c, 0x27, 0x8f, 0x3e, 0x82, 0xe4, 0x49, 0xa4, 0x1b,
-	0xa2, 0x2b, 0xde, 0x3c, 0xff, 0x7e, 0xdf, 0xc7, 0xc7, 0x0c, 0x8c, 0x2d, 0xea, 0x9a, 0x31, 0x35,
-	0xb6, 0xe6, 0x5a, 0x82, 0xb1, 0xb5, 0x46, 0x5e, 0x60, 0x43, 0x47, 0x01, 0xaf, 0x0d, 0x52, 0x07,
-	0xe2, 0x7b, 0x18, 0x3f, 0xdb, 0x92, 0x51, 0xe1, 0xaa, 0x41, 0x62, 0x79, 0x05, 0xc0, 0xa5, 0x46,
-	0x42, 0x5b, 0x22, 0x85, 0xe2, 0x78, 0x90, 0x04, 0xe7, 0x07, 0xe9, 0x4f, 0x3a, 0x9d, 0x95, 0x1a,
-	0x9f, 0x1c, 0x55, 0x9e, 0x19, 0x5f, 0x43, 0xa0, 0x30, 0x2b, 0xfa, 0x9a, 0x13, 0x18, 0xad, 0x1a,
-	0xbf, 0x63, 0xcf, 0xef, 0x78, 0x6c, 0xd0, 0xae, 0x55, 0x6f, 0xc4, 0x37, 0x30, 0xee, 0xb2, 0x64,
-	0xea, 0x8a, 0x50, 0x9e, 0xc1, 0xc8, 0x22, 0x35, 0x4b, 0xee, 0xc3, 0x87, 0x7f, 0xc3, 0x8e, 0xab,
-	0xde, 0x8b, 0xdf, 0x04, 0x6c, 0x3b, 0x20, 0x4f, 0x41, 0x12, 0x67, 0x96, 0xe7, 0x6e, 0x1c, 0x67,
-	0xda, 0xcc, 0xf5, 0xa6, 0x47, 0x24, 0x03, 0x35, 0x71, 0x64, 0xd6, 0x83, 0x29, 0xc9, 0x04, 0x26,
-	0x58, 0x15, 0xbf, 0xdd, 0x2d, 0xe7, 0xee, 0x62, 0x55, 0xf8, 0xe6, 0x25, 0xec, 0xe8, 0x8c, 0x5f,
-	0x17, 0x68, 0x29, 0x1c, 0xb8, 0x55, 0xa1, 0xbf, 0xea, 0x21, 0xcb, 0x71, 0x39, 0xed, 0x04, 0xf5,
-	0x6d, 0xc6, 0x77, 0x10, 0x78, 0x7b, 0xff, 0x7b, 0xdd, 0xdb, 0xfd, 0xf7, 0x36, 0x12, 0x1f, 0x6d,
-	0x24, 0x3e, 0xdb, 0x48, 0xbc, 0x0c, 0x37, 0x01, 0x93, 0xe7, 0x43, 0xf7, 0xc2, 0x8b, 0xaf, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0x0a, 0x31, 0xaa, 0x60, 0xeb, 0x01, 0x00, 0x00,
+	// 514 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x94, 0x41, 0x8b, 0xd3, 0x4e,
+	0x18, 0xc6, 0x99, 0xa6, 0x4d, 0xd3, 0xb7, 0xfd, 0x2f, 0xfd, 0x0f, 0xee, 0x6e, 0x2c, 0x52, 0x4a,
+	0x4e, 0xc1, 0x95, 0x82, 0xab, 0xec, 0xc5, 0x93, 0x82, 0x20, 0x62, 0x0e, 0x8e, 0x0b, 0x82, 0x97,
+	0x32, 0x6d, 0x5e, 0xdb, 0x40, 0x26, 0xc9, 0xce, 0x4c, 0xd4, 0xde, 0xfd, 0x42, 0x7e, 0x05, 0x4f,
+	0x1e, 0xfd, 0x08, 0xd2, 0x4f, 0x22, 0x33, 0xd3, 0x74, 0xa3, 0xc5, 0x8b, 0xe0, 0x6d, 0xde, 0x3e,
+	0xcf, 0xf3, 0xce, 0xef, 0x9d, 0x37, 0x14, 0x46, 0x12, 0x45, 0xa9, 0x71, 0x5e, 0xc9, 0x52, 0x97,
+	0x14, 0x2a, 0x59, 0x0a, 0xd4, 0x1b, 0xac, 0xd5, 0x64, 0xa8, 0xb7, 0x15, 0x2a, 0x27, 0x44, 0x9f,
+	0x09, 0x8c, 0xde, 0xca, 0x4c, 0x23, 0xc3, 0x9b, 0x1a, 0x95, 0xa6, 0x57, 0x00, 0x3a, 0x13, 0xa8,
+	0x50, 0x66, 0xa8, 0x42, 0x32, 0xf3, 0xe2, 0xe1, 0xe5, 0xd9, 0xfc, 0x36, 0x3e, 0xbf, 0xce, 0x04,
+	0xbe, 0xb1, 0x2a, 0x6b, 0x39, 0xe9, 0x15, 0x04, 0x02, 0x35, 0x4f, 0xb9, 0xe6, 0xa1, 0x67, 0x53,
+	0x93, 0x76, 0x2a, 0x41, 0x2d, 0xb3, 0x55, 0xb2, 0x77, 0xb0, 0x83, 0xf7, 0x65, 0x37, 0xe8, 0x8c,
+	0xbd, 0xe8, 0x23, 0x0c, 0x19, 0xf2, 0xb4, 0x81, 0xb8, 0x80, 0xfe, 0x4d, 0xdd, 0x26, 0xf8, 0xbf,
+	0xdd, 0xeb, 0x75, 0x8d, 0x72, 0xcb, 0x1a, 0x07, 0x7d, 0x02, 0xff, 0xe5, 0x7c, 0x89, 0xf9, 0xa2,
+	0x89, 0x74, 0x8e, 0xa1, 0x5f, 0x19, 0x83, 0xcb, 0x8d, 0xf2, 0xe6, 0x9c, 0xa1, 0xb2, 0xf3, 0xbb,
+	0x9b, 0x55, 0x55, 0x16, 0x0a, 0xe9, 0x43, 0xe8, 0x4b, 0x54, 0x75, 0xae, 0x9b, 0xab, 0xcf, 0x8f,
+	0xaf, 0xb6, 0x3a, 0x6b, 0x7c, 0xf4, 0x69, 0x03, 0xd0, 0x04, 0x1d, 0xc0, 0xbd, 0x3f, 0x00, 0xb8,
+	0xb4, 0xc3, 0x70, 0x85, 0x8a, 0xbe, 0x12, 0xe8, 0x59, 0x95, 0x3e, 0x00, 0xaa, 0x34, 0x97, 0x7a,
+	0x61, 0xdf, 0x56, 0x73, 0x51, 0x2d, 0x84, 0x41, 0x21, 0xb1, 0xc7, 0xc6, 0x56, 0xb9, 0x6e, 0x84,
+	0x44, 0xd1, 0x18, 0xc6, 0x58, 0xa4, 0xbf, 0x7a, 0x3b, 0xd6, 0x7b, 0x82, 0x45, 0xda, 0x76, 0x3e,
+	0x86, 0x40, 0x70, 0xbd, 0xda, 0xa0, 0x54, 0xfb, 0xfd, 0x84, 0x47, 0x7c, 0x89, 0x33, 0xb0, 0x83,
+	0x93, 0x5e, 0x40, 0x6f, 0x93, 0x15, 0x5a, 0x85, 0xdd, 0x19, 0x89, 0x87, 0x97, 0xa7, 0xed, 0x88,
+	0x79, 0xb6, 0x17, 0x46, 0x64, 0xce, 0x13, 0x7d, 0x21, 0x30, 0x38, 0xfc, 0x48, 0xcf, 0xa1, 0xaf,
+	0x34, 0xb6, 0xe8, 0x7d, 0x53, 0x26, 0x8a, 0x52, 0xe8, 0xbe, 0xaf, 0x8b, 0x95, 0xe5, 0x1c, 0x30,
+	0x7b, 0xa6, 0x77, 0x21, 0x70, 0x53, 0x0b, 0x43, 0x67, 0xdc, 0x7d, 0x5b, 0x27, 0x8a, 0x9e, 0x82,
+	0x6f, 0x46, 0x14, 0x8e, 0xc1, 0x63, 0x3d, 0x2c, 0xd2, 0x44, 0xd1, 0x09, 0x04, 0x6b, 0x59, 0xd6,
+	0x55, 0x56, 0xac, 0xc3, 0xde, 0xcc, 0x8b, 0x07, 0xec, 0x50, 0xd3, 0x13, 0xe8, 0x2c, 0xb7, 0xa1,
+	0x3f, 0x23, 0x71, 0xc0, 0x3a, 0xcb, 0xad, 0xe9, 0x2e, 0x79, 0xb1, 0x46, 0xd3, 0xa4, 0xef, 0xba,
+	0xdb, 0x3a, 0x51, 0xd1, 0x73, 0x18, 0xb6, 0xb6, 0xf2, 0xb7, 0x5f, 0x7f, 0xf4, 0x09, 0xe0, 0x76,
+	0xc3, 0xff, 0x6c, 0x87, 0x14, 0xba, 0x05, 0x17, 0x68, 0x5f, 0x68, 0xc0, 0xec, 0x39, 0xba, 0x0f,
+	0xe3, 0xdf, 0xbf, 0x2d, 0x7a, 0x06, 0xfe, 0x07, 0x9e, 0xd7, 0xfb, 0x09, 0x06, 0x6c, 0x5f, 0x3d,
+	0xbb, 0xf3, 0x6d, 0x37, 0x25, 0xdf, 0x77, 0x53, 0xf2, 0x63, 0x37, 0x25, 0xef, 0x7c, 0x33, 0x56,
+	0xb5, 0x5c, 0xfa, 0xf6, 0x9f, 0xe0, 0xd1, 0xcf, 0x01, 0x00, 0xaa, 0xb7, 0x99, 0x0a, 0x32, 0x04,
+	0x00, 0x00,
 }
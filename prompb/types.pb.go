@@ -43,6 +43,47 @@ func (x LabelMatcher_Type) String() string {
 }
 func (LabelMatcher_Type) EnumDescriptor() ([]byte, []int) { return fileDescriptorTypes, []int{4, 0} }
 
+type MetricMetadata_MetricType int32
+
+const (
+	MetricMetadata_UNKNOWN        MetricMetadata_MetricType = 0
+	MetricMetadata_COUNTER        MetricMetadata_MetricType = 1
+	MetricMetadata_GAUGE          MetricMetadata_MetricType = 2
+	MetricMetadata_HISTOGRAM      MetricMetadata_MetricType = 3
+	MetricMetadata_GAUGEHISTOGRAM MetricMetadata_MetricType = 4
+	MetricMetadata_SUMMARY        MetricMetadata_MetricType = 5
+	MetricMetadata_INFO           MetricMetadata_MetricType = 6
+	MetricMetadata_STATESET       MetricMetadata_MetricType = 7
+)
+
+var MetricMetadata_MetricType_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "COUNTER",
+	2: "GAUGE",
+	3: "HISTOGRAM",
+	4: "GAUGEHISTOGRAM",
+	5: "SUMMARY",
+	6: "INFO",
+	7: "STATESET",
+}
+var MetricMetadata_MetricType_value = map[string]int32{
+	"UNKNOWN":        0,
+	"COUNTER":        1,
+	"GAUGE":          2,
+	"HISTOGRAM":      3,
+	"GAUGEHISTOGRAM": 4,
+	"SUMMARY":        5,
+	"INFO":           6,
+	"STATESET":       7,
+}
+
+func (x MetricMetadata_MetricType) String() string {
+	return proto.EnumName(MetricMetadata_MetricType_name, int32(x))
+}
+func (MetricMetadata_MetricType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptorTypes, []int{5, 0}
+}
+
 type Sample struct {
 	Value     float64 `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
 	Timestamp int64   `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
@@ -164,13 +205,57 @@ func (m *LabelMatcher) GetValue() string {
 	return ""
 }
 
+// MetricMetadata is the metadata of a metric family as exposed by the
+// scraped targets.
+type MetricMetadata struct {
+	Type             MetricMetadata_MetricType `protobuf:"varint,1,opt,name=type,proto3,enum=prometheus.MetricMetadata_MetricType" json:"type,omitempty"`
+	MetricFamilyName string                    `protobuf:"bytes,2,opt,name=metric_family_name,json=metricFamilyName,proto3" json:"metric_family_name,omitempty"`
+	Help             string                    `protobuf:"bytes,4,opt,name=help,proto3" json:"help,omitempty"`
+	Unit             string                    `protobuf:"bytes,5,opt,name=unit,proto3" json:"unit,omitempty"`
+}
+
+func (m *MetricMetadata) Reset()                    { *m = MetricMetadata{} }
+func (m *MetricMetadata) String() string            { return proto.CompactTextString(m) }
+func (*MetricMetadata) ProtoMessage()               {}
+func (*MetricMetadata) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{5} }
+
+func (m *MetricMetadata) GetType() MetricMetadata_MetricType {
+	if m != nil {
+		return m.Type
+	}
+	return MetricMetadata_UNKNOWN
+}
+
+func (m *MetricMetadata) GetMetricFamilyName() string {
+	if m != nil {
+		return m.MetricFamilyName
+	}
+	return ""
+}
+
+func (m *MetricMetadata) GetHelp() string {
+	if m != nil {
+		return m.Help
+	}
+	return ""
+}
+
+func (m *MetricMetadata) GetUnit() string {
+	if m != nil {
+		return m.Unit
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Sample)(nil), "prometheus.Sample")
 	proto.RegisterType((*TimeSeries)(nil), "prometheus.TimeSeries")
 	proto.RegisterType((*Label)(nil), "prometheus.Label")
 	proto.RegisterType((*Labels)(nil), "prometheus.Labels")
 	proto.RegisterType((*LabelMatcher)(nil), "prometheus.LabelMatcher")
+	proto.RegisterType((*MetricMetadata)(nil), "prometheus.MetricMetadata")
 	proto.RegisterEnum("prometheus.LabelMatcher_Type", LabelMatcher_Type_name, LabelMatcher_Type_value)
+	proto.RegisterEnum("prometheus.MetricMetadata_MetricType", MetricMetadata_MetricType_name, MetricMetadata_MetricType_value)
 }
 func (m *Sample) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
@@ -338,6 +423,47 @@ func (m *LabelMatcher) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *MetricMetadata) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MetricMetadata) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Type != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(m.Type))
+	}
+	if len(m.MetricFamilyName) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.MetricFamilyName)))
+		i += copy(dAtA[i:], m.MetricFamilyName)
+	}
+	if len(m.Help) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Help)))
+		i += copy(dAtA[i:], m.Help)
+	}
+	if len(m.Unit) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Unit)))
+		i += copy(dAtA[i:], m.Unit)
+	}
+	return i, nil
+}
+
 func encodeVarintTypes(dAtA []byte, offset int, v uint64) int {
 	for v >= 1<<7 {
 		dAtA[offset] = uint8(v&0x7f | 0x80)
@@ -420,6 +546,27 @@ func (m *LabelMatcher) Size() (n int) {
 	return n
 }
 
+func (m *MetricMetadata) Size() (n int) {
+	var l int
+	_ = l
+	if m.Type != 0 {
+		n += 1 + sovTypes(uint64(m.Type))
+	}
+	l = len(m.MetricFamilyName)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.Help)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.Unit)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+
 func sovTypes(x uint64) (n int) {
 	for {
 		n++
@@ -941,6 +1088,162 @@ func (m *LabelMatcher) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *MetricMetadata) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MetricMetadata: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MetricMetadata: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			m.Type = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Type |= (MetricMetadata_MetricType(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MetricFamilyName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MetricFamilyName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Help", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Help = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Unit", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Unit = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipTypes(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -1049,25 +1352,35 @@ var (
 func init() { proto.RegisterFile("types.proto", fileDescriptorTypes) }
 
 var fileDescriptorTypes = []byte{
-	// 316 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x91, 0xc1, 0x6a, 0xea, 0x40,
-	0x14, 0x86, 0x9d, 0x24, 0x46, 0x3c, 0x5e, 0x2e, 0xe9, 0xe0, 0x22, 0x94, 0xd6, 0x4a, 0x56, 0x29,
-	0x94, 0x88, 0x76, 0x55, 0xe8, 0x4a, 0xc8, 0xce, 0x16, 0x1c, 0x5d, 0x75, 0x37, 0x96, 0x83, 0x0a,
-	0x99, 0x66, 0xc8, 0x8c, 0x05, 0x1f, 0xa4, 0xef, 0xe4, 0xb2, 0x4f, 0x50, 0x8a, 0x4f, 0x52, 0x66,
-	0x46, 0x1b, 0xa1, 0x85, 0xee, 0xce, 0xf9, 0xf3, 0x1d, 0xfe, 0x8f, 0x0c, 0x74, 0xf4, 0x56, 0xa2,
-	0xca, 0x64, 0x55, 0xea, 0x92, 0x82, 0xac, 0x4a, 0x81, 0x7a, 0x85, 0x1b, 0x75, 0xde, 0x5d, 0x96,
-	0xcb, 0xd2, 0xc6, 0x03, 0x33, 0x39, 0x22, 0xb9, 0x87, 0x70, 0xc6, 0x85, 0x2c, 0x90, 0x76, 0xa1,
-	0xf9, 0xca, 0x8b, 0x0d, 0xc6, 0xa4, 0x4f, 0x52, 0xc2, 0xdc, 0x42, 0x2f, 0xa0, 0xad, 0xd7, 0x02,
-	0x95, 0xe6, 0x42, 0xc6, 0x5e, 0x9f, 0xa4, 0x3e, 0xab, 0x83, 0x04, 0x01, 0xe6, 0x6b, 0x81, 0x33,
-	0xac, 0xd6, 0xa8, 0xe8, 0x35, 0x84, 0x05, 0x5f, 0x60, 0xa1, 0x62, 0xd2, 0xf7, 0xd3, 0xce, 0xe8,
-	0x2c, 0xab, 0xeb, 0xb3, 0x89, 0xf9, 0xc2, 0x0e, 0x00, 0xbd, 0x81, 0x96, 0xb2, 0xb5, 0x2a, 0xf6,
-	0x2c, 0x4b, 0x4f, 0x59, 0x67, 0xc4, 0x8e, 0x48, 0x32, 0x84, 0xa6, 0x3d, 0xa7, 0x14, 0x82, 0x17,
-	0x2e, 0x9c, 0x62, 0x9b, 0xd9, 0xb9, 0xf6, 0xf6, 0x6c, 0xe8, 0x96, 0xe4, 0x0e, 0xc2, 0x89, 0xab,
-	0x1a, 0xfc, 0x69, 0x35, 0x0e, 0x76, 0x1f, 0x57, 0x8d, 0xa3, 0x5b, 0xf2, 0x46, 0xe0, 0x9f, 0xcd,
-	0x1f, 0xb8, 0x7e, 0x5e, 0x61, 0x45, 0x87, 0x10, 0x98, 0x9f, 0x6a, 0x5b, 0xff, 0x8f, 0x2e, 0x7f,
-	0xdc, 0x1f, 0xb8, 0x6c, 0xbe, 0x95, 0xc8, 0x2c, 0xfa, 0x2d, 0xea, 0xfd, 0x26, 0xea, 0x9f, 0x8a,
-	0xa6, 0x10, 0x98, 0x3b, 0x1a, 0x82, 0x97, 0x4f, 0xa3, 0x06, 0x6d, 0x81, 0xff, 0x98, 0x4f, 0x23,
-	0x62, 0x02, 0x96, 0x47, 0x9e, 0x0d, 0x58, 0x1e, 0xf9, 0xe3, 0xee, 0x6e, 0xdf, 0x23, 0xef, 0xfb,
-	0x1e, 0xf9, 0xdc, 0xf7, 0xc8, 0x53, 0x68, 0x2c, 0xe4, 0x62, 0x11, 0xda, 0x77, 0xbc, 0xfd, 0x0a,
-	0x00, 0x00, 0xff, 0xff, 0x6c, 0xee, 0x68, 0x81, 0xf8, 0x01, 0x00, 0x00,
+	// 470 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x92, 0xc1, 0x6a, 0xdb, 0x40,
+	0x10, 0x86, 0xbd, 0x92, 0x2c, 0xc7, 0xe3, 0xd4, 0x6c, 0x07, 0x1f, 0x44, 0x69, 0x5d, 0x23, 0x28,
+	0xb8, 0x10, 0x1c, 0x92, 0x9e, 0x02, 0xbd, 0x38, 0x45, 0x71, 0x43, 0x23, 0x99, 0xac, 0x64, 0x4a,
+	0x7b, 0x09, 0xeb, 0x74, 0x1b, 0x0b, 0xa4, 0x48, 0x48, 0xeb, 0x82, 0x1f, 0xa4, 0xb7, 0x3e, 0x50,
+	0x8e, 0x7d, 0x82, 0x52, 0xfc, 0x24, 0x65, 0x57, 0x4e, 0xa5, 0xd0, 0x42, 0x6e, 0x33, 0xdf, 0xfc,
+	0xc3, 0x7c, 0x5a, 0x04, 0x3d, 0xb9, 0xc9, 0x45, 0x39, 0xc9, 0x8b, 0x4c, 0x66, 0x08, 0x79, 0x91,
+	0xa5, 0x42, 0xae, 0xc4, 0xba, 0x7c, 0x36, 0xb8, 0xc9, 0x6e, 0x32, 0x8d, 0x0f, 0x55, 0x55, 0x25,
+	0xdc, 0xb7, 0x60, 0x87, 0x3c, 0xcd, 0x13, 0x81, 0x03, 0x68, 0x7f, 0xe3, 0xc9, 0x5a, 0x38, 0x64,
+	0x44, 0xc6, 0x84, 0x55, 0x0d, 0x3e, 0x87, 0xae, 0x8c, 0x53, 0x51, 0x4a, 0x9e, 0xe6, 0x8e, 0x31,
+	0x22, 0x63, 0x93, 0xd5, 0xc0, 0x15, 0x00, 0x51, 0x9c, 0x8a, 0x50, 0x14, 0xb1, 0x28, 0xf1, 0x35,
+	0xd8, 0x09, 0x5f, 0x8a, 0xa4, 0x74, 0xc8, 0xc8, 0x1c, 0xf7, 0x8e, 0x9f, 0x4e, 0xea, 0xf3, 0x93,
+	0x0b, 0x35, 0x61, 0xbb, 0x00, 0x1e, 0x40, 0xa7, 0xd4, 0x67, 0x4b, 0xc7, 0xd0, 0x59, 0x6c, 0x66,
+	0x2b, 0x23, 0x76, 0x1f, 0x71, 0x8f, 0xa0, 0xad, 0xd7, 0x11, 0xc1, 0xba, 0xe5, 0x69, 0xa5, 0xd8,
+	0x65, 0xba, 0xae, 0xbd, 0x0d, 0x0d, 0xab, 0xc6, 0x3d, 0x01, 0xfb, 0xa2, 0x3a, 0x75, 0xf8, 0xa8,
+	0xd5, 0xa9, 0x75, 0xf7, 0xeb, 0x65, 0xeb, 0xde, 0xcd, 0xfd, 0x4e, 0x60, 0x5f, 0x73, 0x9f, 0xcb,
+	0xeb, 0x95, 0x28, 0xf0, 0x08, 0x2c, 0xf5, 0xa8, 0xfa, 0x6a, 0xff, 0xf8, 0xc5, 0x3f, 0xfb, 0xbb,
+	0xdc, 0x24, 0xda, 0xe4, 0x82, 0xe9, 0xe8, 0x5f, 0x51, 0xe3, 0x7f, 0xa2, 0x66, 0x53, 0x74, 0x0c,
+	0x96, 0xda, 0x43, 0x1b, 0x0c, 0xef, 0x92, 0xb6, 0xb0, 0x03, 0x66, 0xe0, 0x5d, 0x52, 0xa2, 0x00,
+	0xf3, 0xa8, 0xa1, 0x01, 0xf3, 0xa8, 0xe9, 0xfe, 0x30, 0xa0, 0xef, 0x0b, 0x59, 0xc4, 0xd7, 0xbe,
+	0x90, 0xfc, 0x0b, 0x97, 0x1c, 0x4f, 0x1e, 0x98, 0xbd, 0x6a, 0x9a, 0x3d, 0x4c, 0xee, 0xda, 0x86,
+	0xe1, 0x01, 0x60, 0xaa, 0xd9, 0xd5, 0x57, 0x9e, 0xc6, 0xc9, 0xe6, 0xaa, 0xe1, 0x4b, 0xab, 0xc9,
+	0x99, 0x1e, 0x04, 0xca, 0x1d, 0xc1, 0x5a, 0x89, 0x24, 0x77, 0xac, 0xea, 0x7b, 0x54, 0xad, 0xd8,
+	0xfa, 0x36, 0x96, 0x4e, 0xbb, 0x62, 0xaa, 0x76, 0x37, 0x00, 0xf5, 0x25, 0xec, 0x41, 0x67, 0x11,
+	0x7c, 0x08, 0xe6, 0x1f, 0x03, 0xda, 0x52, 0xcd, 0xbb, 0xf9, 0x22, 0x88, 0x3c, 0x46, 0x09, 0x76,
+	0xa1, 0x3d, 0x9b, 0x2e, 0x66, 0xea, 0xfb, 0x9e, 0x40, 0xf7, 0xfd, 0x79, 0x18, 0xcd, 0x67, 0x6c,
+	0xea, 0x53, 0x13, 0x11, 0xfa, 0x7a, 0x52, 0x33, 0x4b, 0xad, 0x86, 0x0b, 0xdf, 0x9f, 0xb2, 0x4f,
+	0xb4, 0x8d, 0x7b, 0x60, 0x9d, 0x07, 0x67, 0x73, 0x6a, 0xe3, 0x3e, 0xec, 0x85, 0xd1, 0x34, 0xf2,
+	0x42, 0x2f, 0xa2, 0x9d, 0xd3, 0xc1, 0xdd, 0x76, 0x48, 0x7e, 0x6e, 0x87, 0xe4, 0xf7, 0x76, 0x48,
+	0x3e, 0xdb, 0xea, 0x29, 0xf2, 0xe5, 0xd2, 0xd6, 0xbf, 0xf9, 0x9b, 0x3f, 0x03, 0x00, 0xe7, 0xd0,
+	0xd1, 0xcc, 0x17, 0x03, 0x00, 0x00,
 }
@@ -70,6 +70,17 @@ type EvalStmt struct {
 	Start, End time.Time
 	// Time between two evaluated instants for the range [Start:End].
 	Interval time.Duration
+	// The time since the last sample after which a time series is
+	// considered stale. If zero, LookbackDelta applies.
+	LookbackDelta time.Duration
+}
+
+// lookbackDelta returns the lookback delta of the statement's evaluation.
+func (s *EvalStmt) lookbackDelta() time.Duration {
+	if s.LookbackDelta > 0 {
+		return s.LookbackDelta
+	}
+	return LookbackDelta
 }
 
 // RecordStmt represents an added recording rule.
@@ -314,3 +325,38 @@ func (f inspector) Visit(node Node) Visitor {
 func Inspect(node Node, f func(Node) bool) {
 	Walk(inspector(f), node)
 }
+
+// ExtractSelectors returns the label matchers of all vector and matrix
+// selectors of the expression.
+func ExtractSelectors(expr Expr) [][]*labels.Matcher {
+	var selectors [][]*labels.Matcher
+	Inspect(expr, func(node Node) bool {
+		switch n := node.(type) {
+		case *VectorSelector:
+			selectors = append(selectors, n.LabelMatchers)
+		case *MatrixSelector:
+			selectors = append(selectors, n.LabelMatchers)
+		}
+		return true
+	})
+	return selectors
+}
+
+type pathInspector struct {
+	f    func(Node, []Node) bool
+	path []Node
+}
+
+func (v pathInspector) Visit(node Node) Visitor {
+	if node == nil || !v.f(node, v.path) {
+		return nil
+	}
+	// Copy on append as siblings share the path of their parent.
+	return pathInspector{f: v.f, path: append(v.path[:len(v.path):len(v.path)], node)}
+}
+
+// inspectWithPath is like Inspect but also passes the ancestors of each node
+// to f, starting with the root node.
+func inspectWithPath(node Node, f func(Node, []Node) bool) {
+	Walk(pathInspector{f: f}, node)
+}
@@ -0,0 +1,350 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+)
+
+// Check inspects the vector matching of the binary operations of an
+// expression and returns warnings about matchings that are valid but most
+// likely do not do what was intended, like matching on labels that one side
+// never has. The warnings are based on the labels that are known to be present
+// on each side, so they never report false positives for plain selectors.
+func Check(expr Expr) []error {
+	if expr == nil {
+		return nil
+	}
+	var warnings []error
+	Inspect(expr, func(node Node) bool {
+		if n, ok := node.(*BinaryExpr); ok && n.VectorMatching != nil {
+			warnings = append(warnings, checkVectorMatching(n)...)
+		}
+		return true
+	})
+	return warnings
+}
+
+// outputLabels describes what is known about the labels of the elements of a
+// Vector expression.
+type outputLabels struct {
+	// Whether the label names are known exactly. If not, names are only
+	// ever present.
+	known bool
+	// The label names apart from the metric name, if known.
+	names map[string]struct{}
+	// Whether the elements can have a metric name at all.
+	hasName bool
+}
+
+func (o outputLabels) has(name string) bool {
+	if name == labels.MetricName {
+		return o.hasName
+	}
+	if !o.known {
+		return true
+	}
+	_, ok := o.names[name]
+	return ok
+}
+
+func (o outputLabels) equal(other outputLabels) bool {
+	if !o.known || !other.known || len(o.names) != len(other.names) {
+		return false
+	}
+	for n := range o.names {
+		if _, ok := other.names[n]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (o outputLabels) sorted() []string {
+	names := make([]string, 0, len(o.names))
+	for n := range o.names {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func unknownLabels(hasName bool) outputLabels {
+	return outputLabels{hasName: hasName}
+}
+
+func knownLabels(hasName bool, names ...string) outputLabels {
+	o := outputLabels{known: true, names: make(map[string]struct{}, len(names)), hasName: hasName}
+	for _, n := range names {
+		if n != labels.MetricName {
+			o.names[n] = struct{}{}
+		}
+	}
+	return o
+}
+
+// withoutName returns a copy of the labels without the metric name.
+func (o outputLabels) withoutName() outputLabels {
+	o.hasName = false
+	return o
+}
+
+// inferLabels returns what is known about the labels of the elements the
+// expression evaluates to. It follows the rules the engine applies to the
+// labels of results.
+func inferLabels(expr Expr) outputLabels {
+	switch e := expr.(type) {
+	case *ParenExpr:
+		return inferLabels(e.Expr)
+
+	case *UnaryExpr:
+		return inferLabels(e.Expr)
+
+	case *AggregateExpr:
+		switch {
+		case e.Op == itemTopK || e.Op == itemBottomK:
+			return inferLabels(e.Expr)
+		case e.Without:
+			return unknownLabels(false)
+		}
+		grouping := e.Grouping
+		if s, ok := e.Param.(*StringLiteral); ok && e.Op == itemCountValues {
+			grouping = append(grouping[:len(grouping):len(grouping)], s.Val)
+		}
+		return knownLabels(containsLabel(grouping, labels.MetricName), grouping...)
+
+	case *BinaryExpr:
+		return inferBinaryLabels(e)
+
+	case *Call:
+		switch e.Func.Name {
+		case "vector":
+			return knownLabels(false)
+		case "sort", "sort_desc":
+			return inferLabels(e.Args[0])
+		case "label_replace", "label_join":
+			o := inferLabels(e.Args[0])
+			dst, ok := e.Args[1].(*StringLiteral)
+			if !ok {
+				return o
+			}
+			if dst.Val == labels.MetricName {
+				o.hasName = true
+			} else if o.known {
+				o = knownLabels(o.hasName, append(o.sorted(), dst.Val)...)
+			}
+			return o
+		}
+		// All other functions drop the metric name. Those taking a Vector
+		// keep the other labels.
+		for _, arg := range e.Args {
+			if arg.Type() != ValueTypeVector {
+				continue
+			}
+			o := inferLabels(arg).withoutName()
+			if e.Func.Name == "histogram_quantile" && o.known {
+				delete(o.names, "le")
+			}
+			return o
+		}
+		return unknownLabels(false)
+	}
+	return unknownLabels(true)
+}
+
+func inferBinaryLabels(e *BinaryExpr) outputLabels {
+	lt, rt := e.LHS.Type(), e.RHS.Type()
+	switch {
+	case lt != ValueTypeVector && rt != ValueTypeVector:
+		return unknownLabels(false)
+	case lt != ValueTypeVector:
+		return dropNameIf(inferLabels(e.RHS), shouldDropMetricName(e.Op))
+	case rt != ValueTypeVector:
+		return dropNameIf(inferLabels(e.LHS), shouldDropMetricName(e.Op))
+	}
+
+	lhs, rhs := inferLabels(e.LHS), inferLabels(e.RHS)
+	vm := e.VectorMatching
+	switch e.Op {
+	case itemLAND, itemLUnless:
+		return lhs
+	case itemLOR:
+		return unknownLabels(lhs.hasName || rhs.hasName)
+	}
+
+	var o outputLabels
+	switch vm.Card {
+	case CardOneToOne:
+		if vm.On {
+			names := vm.MatchingLabels
+			if lhs.known {
+				// Labels the left-hand side does not have are not part of
+				// the result.
+				names = intersectLabels(lhs, names)
+			}
+			o = knownLabels(lhs.hasName && containsLabel(names, labels.MetricName), names...)
+		} else {
+			o = lhs
+			o.hasName = lhs.hasName && !containsLabel(vm.MatchingLabels, labels.MetricName)
+			if o.known {
+				o = knownLabels(o.hasName, subtractLabels(o, vm.MatchingLabels)...)
+			}
+		}
+	case CardManyToOne:
+		o = includeLabels(lhs, vm.Include)
+	case CardOneToMany:
+		o = includeLabels(rhs, vm.Include)
+	}
+	return dropNameIf(o, shouldDropMetricName(e.Op))
+}
+
+func dropNameIf(o outputLabels, drop bool) outputLabels {
+	if drop {
+		return o.withoutName()
+	}
+	return o
+}
+
+func includeLabels(o outputLabels, include []string) outputLabels {
+	if !o.known {
+		return o
+	}
+	return knownLabels(o.hasName, append(o.sorted(), include...)...)
+}
+
+func intersectLabels(o outputLabels, names []string) []string {
+	var res []string
+	for _, n := range names {
+		if o.has(n) {
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+func subtractLabels(o outputLabels, names []string) []string {
+	var res []string
+	for _, n := range o.sorted() {
+		if !containsLabel(names, n) {
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+func containsLabel(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func checkVectorMatching(e *BinaryExpr) []error {
+	var (
+		vm       = e.VectorMatching
+		lhs, rhs = inferLabels(e.LHS), inferLabels(e.RHS)
+		warnings []error
+	)
+	warnf := func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Errorf("binary expression %q: %s", e.String(), fmt.Sprintf(format, args...)))
+	}
+
+	// Matching on labels that one side never has.
+	if vm.On {
+		for _, side := range []struct {
+			name   string
+			labels outputLabels
+		}{{"left-hand", lhs}, {"right-hand", rhs}} {
+			for _, n := range vm.MatchingLabels {
+				if side.labels.has(n) {
+					continue
+				}
+				if n == labels.MetricName {
+					warnf("the %s side never has a metric name to match on", side.name)
+				} else {
+					warnf("label %q in on() is never present on the %s side", n, side.name)
+				}
+			}
+		}
+	}
+
+	// Without on() or ignoring() all labels must be equal. If the labels of
+	// both sides are known to differ, no elements ever match.
+	if len(vm.MatchingLabels) == 0 && !vm.On && e.Op != itemLOR {
+		if lhs.known && rhs.known && !lhs.equal(rhs) {
+			warnf("the sides have different labels %s and %s and never match, use on() or ignoring() to match on a subset of labels",
+				formatLabelNames(lhs.sorted()), formatLabelNames(rhs.sorted()))
+		}
+	}
+
+	// Group modifiers copy labels from the "one" side.
+	switch vm.Card {
+	case CardManyToOne:
+		checkIncludedLabels(vm, rhs, "right-hand", warnf)
+	case CardOneToMany:
+		checkIncludedLabels(vm, lhs, "left-hand", warnf)
+	}
+
+	// The side that is not the "many" side must have one element per set of
+	// matching labels. Sides with known labels that are not all matched on
+	// have several elements for each.
+	if e.Op.isSetOperator() {
+		return warnings
+	}
+	for _, side := range []struct {
+		name   string
+		labels outputLabels
+		many   VectorMatchCardinality
+		group  string
+	}{{"left-hand", lhs, CardManyToOne, "group_left"}, {"right-hand", rhs, CardOneToMany, "group_right"}} {
+		if vm.Card == side.many || !side.labels.known {
+			continue
+		}
+		var extra []string
+		for _, n := range side.labels.sorted() {
+			if containsLabel(vm.MatchingLabels, n) != vm.On {
+				extra = append(extra, n)
+			}
+		}
+		if len(extra) == 0 || (!vm.On && len(vm.MatchingLabels) == 0) {
+			continue
+		}
+		if vm.Card == CardOneToOne {
+			warnf("the %s side has several elements for each match because of the unmatched labels %s, which requires %s",
+				side.name, formatLabelNames(extra), side.group)
+		} else {
+			warnf("both sides have several elements for each match because of the unmatched labels %s on the %s side, which is not allowed",
+				formatLabelNames(extra), side.name)
+		}
+	}
+	return warnings
+}
+
+func checkIncludedLabels(vm *VectorMatching, one outputLabels, side string, warnf func(string, ...interface{})) {
+	for _, n := range vm.Include {
+		if !one.has(n) {
+			warnf("label %q to include is never present on the %s side", n, side)
+		}
+	}
+}
+
+func formatLabelNames(names []string) string {
+	return "(" + strings.Join(names, ", ") + ")"
+}
@@ -0,0 +1,106 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	cases := []struct {
+		input    string
+		warnings []string
+	}{
+		{
+			input: "foo / bar",
+		}, {
+			input: "foo / on(instance) bar",
+		}, {
+			input: "foo / ignoring(code) group_left bar",
+		}, {
+			input: "sum by(job)(foo) / sum by(job)(bar)",
+		}, {
+			input: "sum by(job)(foo) / on(job) group_left(team) bar",
+		}, {
+			input: "sum by(job)(foo) or sum(bar)",
+		}, {
+			input: "rate(foo[5m]) / on(job) bar",
+		}, {
+			input: "foo > on(__name__) bar",
+		}, {
+			input:    "sum by(job)(foo) / sum(bar)",
+			warnings: []string{"different labels (job) and () and never match"},
+		}, {
+			input:    "sum by(job)(foo) and sum by(instance)(bar)",
+			warnings: []string{"different labels (job) and (instance) and never match"},
+		}, {
+			input: "sum by(job)(foo) / on(instance) bar",
+			warnings: []string{
+				`label "instance" in on() is never present on the left-hand side`,
+				"the left-hand side has several elements for each match because of the unmatched labels (job), which requires group_left",
+			},
+		}, {
+			input: "sum by(job, instance)(foo) / on(job) sum by(job)(bar)",
+			warnings: []string{
+				"the left-hand side has several elements for each match because of the unmatched labels (instance), which requires group_left",
+			},
+		}, {
+			input: "sum by(job)(foo) / ignoring(instance) sum by(job, instance)(bar)",
+			warnings: []string{
+				"the right-hand side has several elements for each match because of the unmatched labels (instance), which requires group_right",
+			},
+		}, {
+			input: "foo / on(job) group_left sum by(job, instance)(bar)",
+			warnings: []string{
+				"both sides have several elements for each match because of the unmatched labels (instance) on the right-hand side",
+			},
+		}, {
+			input:    "foo / on(job) group_left(team) sum by(job)(bar)",
+			warnings: []string{`label "team" to include is never present on the right-hand side`},
+		}, {
+			input:    "(foo * 2) > on(__name__) bar",
+			warnings: []string{"the left-hand side never has a metric name to match on"},
+		}, {
+			input:    "foo and on(__name__) rate(bar[5m])",
+			warnings: []string{"the right-hand side never has a metric name to match on"},
+		}, {
+			input:    `label_replace(sum by(job)(foo), "team", "$1", "job", "(.*)") / sum by(job)(bar)`,
+			warnings: []string{"different labels (job, team) and (job) and never match"},
+		}, {
+			input:    "histogram_quantile(0.9, sum by(le, job)(rate(foo[5m]))) / on(job, le) bar",
+			warnings: []string{`label "le" in on() is never present on the left-hand side`},
+		}, {
+			// Nested binary expressions are checked as well.
+			input:    "foo / (sum by(job)(bar) - sum(baz))",
+			warnings: []string{"different labels (job) and () and never match"},
+		},
+	}
+
+	for _, c := range cases {
+		expr, err := ParseExpr(c.input)
+		if err != nil {
+			t.Fatalf("unexpected error parsing %q: %s", c.input, err)
+		}
+		warnings := Check(expr)
+		if len(warnings) != len(c.warnings) {
+			t.Fatalf("expected %d warnings for %q, got %v", len(c.warnings), c.input, warnings)
+		}
+		for i, w := range warnings {
+			if !strings.Contains(w.Error(), c.warnings[i]) {
+				t.Fatalf("expected warning for %q to contain %q, got %q", c.input, c.warnings[i], w)
+			}
+		}
+	}
+}
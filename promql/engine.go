@@ -60,6 +60,15 @@ var (
 		Name:      "queries_concurrent_max",
 		Help:      "The max number of concurrent queries.",
 	})
+	queryQueueTime = prometheus.NewSummary(
+		prometheus.SummaryOpts{
+			Namespace:   namespace,
+			Subsystem:   subsystem,
+			Name:        "query_duration_seconds",
+			Help:        "Query timings",
+			ConstLabels: prometheus.Labels{"slice": "queue_time"},
+		},
+	)
 	queryPrepareTime = prometheus.NewSummary(
 		prometheus.SummaryOpts{
 			Namespace:   namespace,
@@ -101,6 +110,7 @@ var (
 func init() {
 	prometheus.MustRegister(currentQueries)
 	prometheus.MustRegister(maxConcurrentQueries)
+	prometheus.MustRegister(queryQueueTime)
 	prometheus.MustRegister(queryPrepareTime)
 	prometheus.MustRegister(queryInnerEval)
 	prometheus.MustRegister(queryResultAppend)
@@ -117,6 +127,9 @@ type (
 	ErrQueryTimeout string
 	// ErrQueryCanceled is returned if a query was canceled during processing.
 	ErrQueryCanceled string
+	// ErrTooManySamples is returned if a query would load more samples into
+	// memory than allowed.
+	ErrTooManySamples string
 	// ErrStorage is returned if an error was encountered in the storage layer
 	// during query handling.
 	ErrStorage error
@@ -124,6 +137,9 @@ type (
 
 func (e ErrQueryTimeout) Error() string  { return fmt.Sprintf("query timed out in %s", string(e)) }
 func (e ErrQueryCanceled) Error() string { return fmt.Sprintf("query was canceled in %s", string(e)) }
+func (e ErrTooManySamples) Error() string {
+	return fmt.Sprintf("query processing would load too many samples into memory in %s", string(e))
+}
 
 // A Query is derived from an a raw query string and can be run against an engine
 // it is associated with.
@@ -134,6 +150,9 @@ type Query interface {
 	Statement() Statement
 	// Stats returns statistics about the lifetime of the query.
 	Stats() *stats.TimerGroup
+	// SampleStats returns the number of series and samples processed by
+	// the query.
+	SampleStats() *stats.QuerySamples
 	// Cancel signals that a running query execution should be aborted.
 	Cancel()
 }
@@ -146,6 +165,8 @@ type query struct {
 	stmt Statement
 	// Timer stats for the query execution.
 	stats *stats.TimerGroup
+	// Series and sample counts of the query execution.
+	samples *stats.QuerySamples
 	// Cancellation function for the query.
 	cancel func()
 
@@ -163,6 +184,11 @@ func (q *query) Stats() *stats.TimerGroup {
 	return q.stats
 }
 
+// SampleStats implements the Query interface.
+func (q *query) SampleStats() *stats.QuerySamples {
+	return q.samples
+}
+
 // Cancel implements the Query interface.
 func (q *query) Cancel() {
 	if q.cancel != nil {
@@ -176,8 +202,8 @@ func (q *query) Exec(ctx context.Context) *Result {
 		span.SetTag(queryTag, q.stmt.String())
 	}
 
-	res, err := q.ng.exec(ctx, q)
-	return &Result{Err: err, Value: res}
+	res, warnings, err := q.ng.exec(ctx, q)
+	return &Result{Err: err, Value: res, Warnings: warnings}
 }
 
 // contextDone returns an error if the context was canceled or timed out.
@@ -208,6 +234,15 @@ type Engine struct {
 	options *EngineOptions
 
 	logger log.Logger
+
+	queryLoggerLock sync.RWMutex
+	queryLogger     QueryLogger
+}
+
+// QueryLogger logs every query executed by an engine.
+type QueryLogger interface {
+	Log(...interface{}) error
+	Close() error
 }
 
 // Queryable allows opening a storage querier.
@@ -232,25 +267,50 @@ func NewEngine(queryable Queryable, o *EngineOptions) *Engine {
 // EngineOptions contains configuration parameters for an Engine.
 type EngineOptions struct {
 	MaxConcurrentQueries int
-	Timeout              time.Duration
-	Logger               log.Logger
+	// The maximum number of samples a single query may hold in memory at
+	// once. Zero means no limit.
+	MaxSamples int
+	Timeout    time.Duration
+	Logger     log.Logger
+	// ActiveQueryTracker records running queries so they can be reported
+	// after a crash. It may be nil.
+	ActiveQueryTracker *ActiveQueryTracker
 }
 
 // DefaultEngineOptions are the default engine options.
 var DefaultEngineOptions = &EngineOptions{
 	MaxConcurrentQueries: 20,
+	MaxSamples:           50000000,
 	Timeout:              2 * time.Minute,
 	Logger:               log.NewNopLogger(),
 }
 
+// SetQueryLogger sets the logger every executed query is logged to and closes
+// the previous one. A nil logger disables query logging.
+func (ng *Engine) SetQueryLogger(l QueryLogger) {
+	ng.queryLoggerLock.Lock()
+	defer ng.queryLoggerLock.Unlock()
+
+	if ng.queryLogger != nil {
+		if err := ng.queryLogger.Close(); err != nil {
+			level.Error(ng.logger).Log("msg", "Failed to close query logger", "err", err)
+		}
+	}
+	ng.queryLogger = l
+}
+
 // NewInstantQuery returns an evaluation query for the given expression at the given time.
 func (ng *Engine) NewInstantQuery(qs string, ts time.Time) (Query, error) {
+	tg := stats.NewTimerGroup()
+	parseTimer := tg.GetTimer(stats.QueryParseTime).Start()
 	expr, err := ParseExpr(qs)
+	parseTimer.Stop()
 	if err != nil {
 		return nil, err
 	}
 	qry := ng.newQuery(expr, ts, ts, 0)
 	qry.q = qs
+	qry.stats = tg
 
 	return qry, nil
 }
@@ -258,7 +318,10 @@ func (ng *Engine) NewInstantQuery(qs string, ts time.Time) (Query, error) {
 // NewRangeQuery returns an evaluation query for the given time range and with
 // the resolution set by the interval.
 func (ng *Engine) NewRangeQuery(qs string, start, end time.Time, interval time.Duration) (Query, error) {
+	tg := stats.NewTimerGroup()
+	parseTimer := tg.GetTimer(stats.QueryParseTime).Start()
 	expr, err := ParseExpr(qs)
+	parseTimer.Stop()
 	if err != nil {
 		return nil, err
 	}
@@ -267,6 +330,7 @@ func (ng *Engine) NewRangeQuery(qs string, start, end time.Time, interval time.D
 	}
 	qry := ng.newQuery(expr, start, end, interval)
 	qry.q = qs
+	qry.stats = tg
 
 	return qry, nil
 }
@@ -279,9 +343,10 @@ func (ng *Engine) newQuery(expr Expr, start, end time.Time, interval time.Durati
 		Interval: interval,
 	}
 	qry := &query{
-		stmt:  es,
-		ng:    ng,
-		stats: stats.NewTimerGroup(),
+		stmt:    es,
+		ng:      ng,
+		stats:   stats.NewTimerGroup(),
+		samples: &stats.QuerySamples{},
 	}
 	return qry
 }
@@ -295,10 +360,11 @@ func (testStmt) stmt()          {}
 
 func (ng *Engine) newTestQuery(f func(context.Context) error) Query {
 	qry := &query{
-		q:     "test statement",
-		stmt:  testStmt(f),
-		ng:    ng,
-		stats: stats.NewTimerGroup(),
+		q:       "test statement",
+		stmt:    testStmt(f),
+		ng:      ng,
+		stats:   stats.NewTimerGroup(),
+		samples: &stats.QuerySamples{},
 	}
 	return qry
 }
@@ -307,45 +373,99 @@ func (ng *Engine) newTestQuery(f func(context.Context) error) Query {
 //
 // At this point per query only one EvalStmt is evaluated. Alert and record
 // statements are not handled by the Engine.
-func (ng *Engine) exec(ctx context.Context, q *query) (Value, error) {
+func (ng *Engine) exec(ctx context.Context, q *query) (v Value, w storage.Warnings, err error) {
 	currentQueries.Inc()
 	defer currentQueries.Dec()
 
+	defer func(start time.Time) {
+		ng.logQuery(ctx, q, time.Since(start), err)
+	}(time.Now())
+
 	ctx, cancel := context.WithTimeout(ctx, ng.options.Timeout)
 	q.cancel = cancel
 
-	queueTimer := q.stats.GetTimer(stats.ExecQueueTime).Start()
+	// Queries beyond the concurrency limit wait here until a running query
+	// finishes or their context is done.
+	queueSpanTimer, _ := stats.NewSpanTimer(ctx, q.stats, stats.ExecQueueTime, queryQueueTime)
+	err = ng.gate.Start(ctx)
+	queueSpanTimer.Finish()
 
-	if err := ng.gate.Start(ctx); err != nil {
-		return nil, err
+	if err != nil {
+		return nil, nil, err
 	}
 	defer ng.gate.Done()
 
-	queueTimer.Stop()
+	if t := ng.options.ActiveQueryTracker; t != nil {
+		defer t.Delete(t.Insert(q.q))
+	}
 
 	// Cancel when execution is done or an error was raised.
 	defer q.cancel()
 
 	const env = "query execution"
 
-	evalTimer := q.stats.GetTimer(stats.TotalEvalTime).Start()
-	defer evalTimer.Stop()
+	evalSpanTimer, ctx := stats.NewSpanTimer(ctx, q.stats, stats.TotalEvalTime)
+	defer evalSpanTimer.Finish()
 
 	// The base context might already be canceled on the first iteration (e.g. during shutdown).
 	if err := contextDone(ctx, env); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	switch s := q.Statement().(type) {
 	case *EvalStmt:
-		return ng.execEvalStmt(ctx, q, s)
+		v, w, err := ng.execEvalStmt(ctx, q, s)
+		return v, append(w, Check(s.Expr)...), err
 	case testStmt:
-		return nil, s(ctx)
+		return nil, nil, s(ctx)
 	}
 
 	panic(fmt.Errorf("promql.Engine.exec: unhandled statement of type %T", q.Statement()))
 }
 
+// logQuery logs the executed query to the query logger, if any.
+func (ng *Engine) logQuery(ctx context.Context, q *query, d time.Duration, err error) {
+	ng.queryLoggerLock.RLock()
+	defer ng.queryLoggerLock.RUnlock()
+
+	if ng.queryLogger == nil {
+		return
+	}
+	params := map[string]interface{}{
+		"query": q.q,
+	}
+	if s, ok := q.Statement().(*EvalStmt); ok {
+		params["start"] = s.Start
+		params["end"] = s.End
+		if s.Interval > 0 {
+			params["step"] = s.Interval.Seconds()
+		}
+	}
+	kv := []interface{}{"params", params, "duration", d.Seconds()}
+	if err != nil {
+		kv = append(kv, "error", err)
+	}
+	for k, v := range originFromContext(ctx) {
+		kv = append(kv, k, v)
+	}
+	if lerr := ng.queryLogger.Log(kv...); lerr != nil {
+		level.Error(ng.logger).Log("msg", "Failed to log query", "err", lerr)
+	}
+}
+
+type originKey struct{}
+
+// NewOriginContext returns a context carrying information about the origin
+// of the queries executed with it, which is added to the query log.
+func NewOriginContext(ctx context.Context, data map[string]interface{}) context.Context {
+	return context.WithValue(ctx, originKey{}, data)
+}
+
+func originFromContext(ctx context.Context) map[string]interface{} {
+	data, _ := ctx.Value(originKey{}).(map[string]interface{})
+	return data
+}
+
 func timeMilliseconds(t time.Time) int64 {
 	return t.UnixNano() / int64(time.Millisecond/time.Nanosecond)
 }
@@ -355,12 +475,11 @@ func durationMilliseconds(d time.Duration) int64 {
 }
 
 // execEvalStmt evaluates the expression of an evaluation statement for the given time range.
-func (ng *Engine) execEvalStmt(ctx context.Context, query *query, s *EvalStmt) (Value, error) {
+func (ng *Engine) execEvalStmt(ctx context.Context, query *query, s *EvalStmt) (Value, storage.Warnings, error) {
 
-	prepareTimer := query.stats.GetTimer(stats.QueryPreparationTime).Start()
-	querier, err := ng.populateIterators(ctx, s)
-	prepareTimer.Stop()
-	queryPrepareTime.Observe(prepareTimer.ElapsedTime().Seconds())
+	prepareSpanTimer, ctxPrepare := stats.NewSpanTimer(ctx, query.stats, stats.QueryPreparationTime, queryPrepareTime)
+	querier, warnings, err := ng.populateIterators(ctxPrepare, s)
+	prepareSpanTimer.Finish()
 
 	// XXX(fabxc): the querier returned by populateIterators might be instantiated
 	// we must not return without closing irrespective of the error.
@@ -368,27 +487,41 @@ func (ng *Engine) execEvalStmt(ctx context.Context, query *query, s *EvalStmt) (
 	if querier != nil {
 		defer querier.Close()
 	}
+	// The results hold copies of the buffered samples, so the buffers can be
+	// reused by other queries once the evaluation is done.
+	defer releaseIterators(s.Expr)
 
 	if err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
+	Inspect(s.Expr, func(node Node) bool {
+		switch n := node.(type) {
+		case *VectorSelector:
+			query.samples.Series += len(n.series)
+		case *MatrixSelector:
+			query.samples.Series += len(n.series)
+		}
+		return true
+	})
 
-	evalTimer := query.stats.GetTimer(stats.InnerEvalTime).Start()
+	evalSpanTimer, _ := stats.NewSpanTimer(ctx, query.stats, stats.InnerEvalTime, queryInnerEval)
 	// Instant evaluation.
 	if s.Start == s.End && s.Interval == 0 {
 		start := timeMilliseconds(s.Start)
 		evaluator := &evaluator{
-			Timestamp: start,
-			ctx:       ctx,
-			logger:    ng.logger,
+			Timestamp:     start,
+			ctx:           ctx,
+			maxSamples:    ng.options.MaxSamples,
+			samples:       query.samples,
+			lookbackDelta: durationMilliseconds(s.lookbackDelta()),
+			logger:        ng.logger,
 		}
 		val, err := evaluator.Eval(s.Expr)
+		evalSpanTimer.Finish()
 		if err != nil {
-			return nil, err
+			return nil, warnings, err
 		}
 
-		evalTimer.Stop()
-		queryInnerEval.Observe(evalTimer.ElapsedTime().Seconds())
 		// Point might have a different timestamp, force it to the evaluation
 		// timestamp as that is when we ran the evaluation.
 		switch v := val.(type) {
@@ -400,27 +533,38 @@ func (ng *Engine) execEvalStmt(ctx context.Context, query *query, s *EvalStmt) (
 			}
 		}
 
-		return val, nil
+		return val, warnings, nil
 	}
 	numSteps := int(s.End.Sub(s.Start) / s.Interval)
 
 	// Range evaluation.
-	Seriess := map[uint64]Series{}
+	var (
+		Seriess = map[uint64]Series{}
+		// The number of points in the result so far.
+		numPoints int
+	)
 	for ts := s.Start; !ts.After(s.End); ts = ts.Add(s.Interval) {
 
 		if err := contextDone(ctx, "range evaluation"); err != nil {
-			return nil, err
+			evalSpanTimer.Finish()
+			return nil, warnings, err
 		}
 
 		t := timeMilliseconds(ts)
 		evaluator := &evaluator{
 			Timestamp: t,
 			ctx:       ctx,
-			logger:    ng.logger,
+			// The points of the result so far are held in memory as well.
+			currentSamples: numPoints,
+			maxSamples:     ng.options.MaxSamples,
+			samples:        query.samples,
+			lookbackDelta:  durationMilliseconds(s.lookbackDelta()),
+			logger:         ng.logger,
 		}
 		val, err := evaluator.Eval(s.Expr)
 		if err != nil {
-			return nil, err
+			evalSpanTimer.Finish()
+			return nil, warnings, err
 		}
 
 		switch v := val.(type) {
@@ -434,6 +578,7 @@ func (ng *Engine) execEvalStmt(ctx context.Context, query *query, s *EvalStmt) (
 			}
 			ss.Points = append(ss.Points, Point{V: v.V, T: t})
 			Seriess[0] = ss
+			numPoints++
 		case Vector:
 			for _, sample := range v {
 				h := sample.Metric.Hash()
@@ -449,50 +594,55 @@ func (ng *Engine) execEvalStmt(ctx context.Context, query *query, s *EvalStmt) (
 				ss.Points = append(ss.Points, sample.Point)
 				Seriess[h] = ss
 			}
+			numPoints += len(v)
 		default:
 			panic(fmt.Errorf("promql.Engine.exec: invalid expression type %q", val.Type()))
 		}
 	}
-	evalTimer.Stop()
-	queryInnerEval.Observe(evalTimer.ElapsedTime().Seconds())
+	evalSpanTimer.Finish()
 
 	if err := contextDone(ctx, "expression evaluation"); err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
 
-	appendTimer := query.stats.GetTimer(stats.ResultAppendTime).Start()
+	appendSpanTimer, _ := stats.NewSpanTimer(ctx, query.stats, stats.ResultAppendTime, queryResultAppend)
 	mat := Matrix{}
 	for _, ss := range Seriess {
 		mat = append(mat, ss)
 	}
-	appendTimer.Stop()
-	queryResultAppend.Observe(appendTimer.ElapsedTime().Seconds())
+	appendSpanTimer.Finish()
 
 	if err := contextDone(ctx, "expression evaluation"); err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
 
 	// TODO(fabxc): order ensured by storage?
 	// TODO(fabxc): where to ensure metric labels are a copy from the storage internals.
-	sortTimer := query.stats.GetTimer(stats.ResultSortTime).Start()
+	sortSpanTimer, _ := stats.NewSpanTimer(ctx, query.stats, stats.ResultSortTime, queryResultSort)
 	sort.Sort(mat)
-	sortTimer.Stop()
+	sortSpanTimer.Finish()
 
-	queryResultSort.Observe(sortTimer.ElapsedTime().Seconds())
-	return mat, nil
+	return mat, warnings, nil
 }
 
-func (ng *Engine) populateIterators(ctx context.Context, s *EvalStmt) (storage.Querier, error) {
-	var maxOffset time.Duration
+func (ng *Engine) populateIterators(ctx context.Context, s *EvalStmt) (storage.Querier, storage.Warnings, error) {
+	var (
+		maxOffset     time.Duration
+		minOffset     time.Duration
+		lookbackDelta = s.lookbackDelta()
+	)
 
 	Inspect(s.Expr, func(node Node) bool {
 		switch n := node.(type) {
 		case *VectorSelector:
-			if maxOffset < LookbackDelta {
-				maxOffset = LookbackDelta
+			if maxOffset < lookbackDelta {
+				maxOffset = lookbackDelta
+			}
+			if n.Offset+lookbackDelta > maxOffset {
+				maxOffset = n.Offset + lookbackDelta
 			}
-			if n.Offset+LookbackDelta > maxOffset {
-				maxOffset = n.Offset + LookbackDelta
+			if n.Offset < minOffset {
+				minOffset = n.Offset
 			}
 		case *MatrixSelector:
 			if maxOffset < n.Range {
@@ -501,33 +651,67 @@ func (ng *Engine) populateIterators(ctx context.Context, s *EvalStmt) (storage.Q
 			if n.Offset+n.Range > maxOffset {
 				maxOffset = n.Offset + n.Range
 			}
+			if n.Offset < minOffset {
+				minOffset = n.Offset
+			}
 		}
 		return true
 	})
 
 	mint := s.Start.Add(-maxOffset)
+	// Negative offsets select samples after the end of the query.
+	maxt := s.End.Add(-minOffset)
 
-	querier, err := ng.queryable.Querier(ctx, timestamp.FromTime(mint), timestamp.FromTime(s.End))
+	querier, err := ng.queryable.Querier(ctx, timestamp.FromTime(mint), timestamp.FromTime(maxt))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	Inspect(s.Expr, func(node Node) bool {
+	var warnings storage.Warnings
+
+	inspectWithPath(s.Expr, func(node Node, path []Node) bool {
+		params := &storage.SelectParams{
+			Start: timestamp.FromTime(s.Start),
+			End:   timestamp.FromTime(s.End),
+			Step:  durationMilliseconds(s.Interval),
+		}
+
 		switch n := node.(type) {
 		case *VectorSelector:
-			n.series, err = expandSeriesSet(querier.Select(n.LabelMatchers...))
+			params.Start = params.Start - durationMilliseconds(n.Offset+lookbackDelta)
+			params.End = params.End - durationMilliseconds(n.Offset)
+			setFuncAndGrouping(params, path)
+
+			var set storage.SeriesSet
+			var wrn storage.Warnings
+			set, wrn, err = querier.Select(params, n.LabelMatchers...)
+			warnings = append(warnings, wrn...)
+			if err == nil {
+				n.series, err = expandSeriesSet(set)
+			}
 			if err != nil {
 				// TODO(fabxc): use multi-error.
 				level.Error(ng.logger).Log("msg", "error expanding series set", "err", err)
 				return false
 			}
 			for _, s := range n.series {
-				it := storage.NewBuffer(s.Iterator(), durationMilliseconds(LookbackDelta))
+				it := storage.NewBuffer(s.Iterator(), durationMilliseconds(lookbackDelta))
 				n.iterators = append(n.iterators, it)
 			}
 
 		case *MatrixSelector:
-			n.series, err = expandSeriesSet(querier.Select(n.LabelMatchers...))
+			params.Range = durationMilliseconds(n.Range)
+			params.Start = params.Start - durationMilliseconds(n.Offset+n.Range)
+			params.End = params.End - durationMilliseconds(n.Offset)
+			setFuncAndGrouping(params, path)
+
+			var set storage.SeriesSet
+			var wrn storage.Warnings
+			set, wrn, err = querier.Select(params, n.LabelMatchers...)
+			warnings = append(warnings, wrn...)
+			if err == nil {
+				n.series, err = expandSeriesSet(set)
+			}
 			if err != nil {
 				level.Error(ng.logger).Log("msg", "error expanding series set", "err", err)
 				return false
@@ -539,7 +723,65 @@ func (ng *Engine) populateIterators(ctx context.Context, s *EvalStmt) (storage.Q
 		}
 		return true
 	})
-	return querier, err
+	return querier, warnings, err
+}
+
+// releaseIterators releases the buffers of the series iterators of all
+// selectors in the expression.
+func releaseIterators(expr Expr) {
+	Inspect(expr, func(node Node) bool {
+		switch n := node.(type) {
+		case *VectorSelector:
+			for _, it := range n.iterators {
+				it.Release()
+			}
+			n.iterators = nil
+		case *MatrixSelector:
+			for _, it := range n.iterators {
+				it.Release()
+			}
+			n.iterators = nil
+		}
+		return true
+	})
+}
+
+// setFuncAndGrouping sets the function or aggregation a selector with the
+// given ancestors is passed to, and the grouping of an aggregation applied
+// directly to the result of that function.
+func setFuncAndGrouping(p *storage.SelectParams, path []Node) {
+	i := parentIndex(path, len(path))
+	if i < 0 {
+		return
+	}
+	switch n := path[i].(type) {
+	case *Call:
+		p.Func = n.Func.Name
+		if j := parentIndex(path, i); j >= 0 {
+			if agg, ok := path[j].(*AggregateExpr); ok {
+				p.Grouping = agg.Grouping
+				p.By = !agg.Without
+			}
+		}
+	case *AggregateExpr:
+		p.Func = n.Op.String()
+		p.Grouping = n.Grouping
+		p.By = !n.Without
+	}
+}
+
+// parentIndex returns the index of the closest ancestor before index i in
+// the path that is neither a parenthesized expression nor an argument list,
+// or -1.
+func parentIndex(path []Node, i int) int {
+	for i--; i >= 0; i-- {
+		switch path[i].(type) {
+		case *ParenExpr, Expressions:
+		default:
+			return i
+		}
+	}
+	return -1
 }
 
 func expandSeriesSet(it storage.SeriesSet) (res []storage.Series, err error) {
@@ -559,9 +801,33 @@ type evaluator struct {
 
 	finalizers []func()
 
+	// The number of samples currently loaded by the evaluation and the
+	// maximum allowed. A maximum of zero means no limit.
+	currentSamples int
+	maxSamples     int
+	// Statistics about the loaded samples. It may be nil.
+	samples *stats.QuerySamples
+	// The lookback delta in milliseconds.
+	lookbackDelta int64
+
 	logger log.Logger
 }
 
+// loadSamples accounts for n samples being loaded into memory and errors if
+// that exceeds the maximum.
+func (ev *evaluator) loadSamples(n int) {
+	ev.currentSamples += n
+	if ev.samples != nil {
+		ev.samples.TotalSamples += n
+		if ev.currentSamples > ev.samples.PeakSamples {
+			ev.samples.PeakSamples = ev.currentSamples
+		}
+	}
+	if ev.maxSamples > 0 && ev.currentSamples > ev.maxSamples {
+		ev.error(ErrTooManySamples("expression evaluation"))
+	}
+}
+
 func (ev *evaluator) close() {
 	for _, f := range ev.finalizers {
 		f()
@@ -772,7 +1038,7 @@ func (ev *evaluator) vectorSelector(node *VectorSelector) Vector {
 		if !ok || t > refTime {
 			t, v, ok = it.PeekBack(peek)
 			peek++
-			if !ok || t < refTime-durationMilliseconds(LookbackDelta) {
+			if !ok || t < refTime-ev.lookbackDelta {
 				continue
 			}
 		}
@@ -784,6 +1050,7 @@ func (ev *evaluator) vectorSelector(node *VectorSelector) Vector {
 			Metric: node.series[i].Labels(),
 			Point:  Point{V: v, T: t},
 		})
+		ev.loadSamples(1)
 	}
 	return vec
 }
@@ -866,6 +1133,7 @@ func (ev *evaluator) matrixSelector(node *MatrixSelector) Matrix {
 		}
 
 		ss.Points = allPoints[start:]
+		ev.loadSamples(len(ss.Points))
 
 		if len(ss.Points) > 0 {
 			matrix = append(matrix, ss)
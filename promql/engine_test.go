@@ -17,11 +17,13 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-kit/kit/log"
 	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/storage"
 )
 
 func TestQueryConcurrency(t *testing.T) {
@@ -75,6 +77,54 @@ func TestQueryConcurrency(t *testing.T) {
 	}
 }
 
+func TestQueuedQueryCancel(t *testing.T) {
+	engine := NewEngine(nil, &EngineOptions{
+		Timeout:              time.Minute,
+		MaxConcurrentQueries: 1,
+	})
+
+	block := make(chan struct{})
+	processing := make(chan struct{})
+
+	running := engine.newTestQuery(func(context.Context) error {
+		processing <- struct{}{}
+		<-block
+		return nil
+	})
+	go running.Exec(context.Background())
+	<-processing
+	defer close(block)
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	queued := engine.newTestQuery(func(context.Context) error {
+		t.Errorf("queued query was executed")
+		return nil
+	})
+
+	done := make(chan *Result)
+	go func() {
+		done <- queued.Exec(ctx)
+	}()
+
+	select {
+	case <-done:
+		t.Fatalf("query above concurrency threshold returned before being canceled")
+	case <-time.After(20 * time.Millisecond):
+		// Expected.
+	}
+
+	cancelCtx()
+
+	select {
+	case res := <-done:
+		if _, ok := res.Err.(ErrQueryCanceled); !ok {
+			t.Fatalf("expected cancellation error, got %v", res.Err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("queued query not aborted after its context was canceled")
+	}
+}
+
 func TestQueryTimeout(t *testing.T) {
 	engine := NewEngine(nil, &EngineOptions{
 		Timeout:              5 * time.Millisecond,
@@ -294,6 +344,273 @@ load 10s
 
 }
 
+func TestMaxSamples(t *testing.T) {
+	test, err := NewTest(t, `
+load 10s
+  metric{a="1"} 1 2 3 4 5
+  metric{a="2"} 1 2 3 4 5
+`)
+	if err != nil {
+		t.Fatalf("unexpected error creating test: %q", err)
+	}
+	defer test.Close()
+
+	if err := test.Run(); err != nil {
+		t.Fatalf("unexpected error initializing test: %q", err)
+	}
+
+	cases := []struct {
+		Query      string
+		Start, End time.Time
+		Interval   time.Duration
+		MaxSamples int
+		Fail       bool
+	}{
+		{Query: "metric", Start: time.Unix(40, 0), MaxSamples: 2},
+		{Query: "metric", Start: time.Unix(40, 0), MaxSamples: 1, Fail: true},
+		{Query: "metric[50s]", Start: time.Unix(40, 0), MaxSamples: 10},
+		{Query: "metric[50s]", Start: time.Unix(40, 0), MaxSamples: 9, Fail: true},
+		// Samples of all steps of a range query count towards the limit.
+		{Query: "metric", Start: time.Unix(0, 0), End: time.Unix(40, 0), Interval: 10 * time.Second, MaxSamples: 10},
+		{Query: "metric", Start: time.Unix(0, 0), End: time.Unix(40, 0), Interval: 10 * time.Second, MaxSamples: 9, Fail: true},
+	}
+
+	for _, c := range cases {
+		engine := NewEngine(test.Storage(), &EngineOptions{
+			MaxConcurrentQueries: 20,
+			Timeout:              10 * time.Second,
+			MaxSamples:           c.MaxSamples,
+		})
+
+		var qry Query
+		if c.Interval == 0 {
+			qry, err = engine.NewInstantQuery(c.Query, c.Start)
+		} else {
+			qry, err = engine.NewRangeQuery(c.Query, c.Start, c.End, c.Interval)
+		}
+		if err != nil {
+			t.Fatalf("unexpected error creating query: %q", err)
+		}
+		res := qry.Exec(test.Context())
+		if _, ok := res.Err.(ErrTooManySamples); ok != c.Fail {
+			t.Fatalf("unexpected error for query %q with max samples %d: %v", c.Query, c.MaxSamples, res.Err)
+		}
+		if !c.Fail && res.Err != nil {
+			t.Fatalf("unexpected error running query %q: %q", c.Query, res.Err)
+		}
+	}
+}
+
+type testQueryLogger struct {
+	entries [][]interface{}
+	closed  bool
+}
+
+func (l *testQueryLogger) Log(kv ...interface{}) error {
+	l.entries = append(l.entries, kv)
+	return nil
+}
+
+func (l *testQueryLogger) Close() error {
+	l.closed = true
+	return nil
+}
+
+func TestQueryLogger(t *testing.T) {
+	engine := NewEngine(nil, nil)
+
+	ql := &testQueryLogger{}
+	engine.SetQueryLogger(ql)
+
+	ctx := NewOriginContext(context.Background(), map[string]interface{}{"origin": "test"})
+	res := engine.newTestQuery(func(context.Context) error {
+		return fmt.Errorf("failed")
+	}).Exec(ctx)
+	if res.Err == nil {
+		t.Fatalf("expected error")
+	}
+
+	if len(ql.entries) != 1 {
+		t.Fatalf("expected 1 logged query, got %d", len(ql.entries))
+	}
+	entry := map[interface{}]interface{}{}
+	for i := 0; i+1 < len(ql.entries[0]); i += 2 {
+		entry[ql.entries[0][i]] = ql.entries[0][i+1]
+	}
+	if params, ok := entry["params"].(map[string]interface{}); !ok || params["query"] != "test statement" {
+		t.Fatalf("unexpected params: %v", entry["params"])
+	}
+	if entry["error"] != res.Err {
+		t.Fatalf("unexpected error: %v", entry["error"])
+	}
+	if entry["origin"] != "test" {
+		t.Fatalf("unexpected origin: %v", entry["origin"])
+	}
+
+	engine.SetQueryLogger(nil)
+	if !ql.closed {
+		t.Fatalf("query logger not closed")
+	}
+	engine.newTestQuery(func(context.Context) error { return nil }).Exec(ctx)
+	if len(ql.entries) != 1 {
+		t.Fatalf("query logged after the logger was unset")
+	}
+}
+
+// paramsRecordingQuerier records the select params of all selections.
+type paramsRecordingQuerier struct {
+	params []*storage.SelectParams
+	// warnings are returned by every selection.
+	warnings storage.Warnings
+}
+
+func (q *paramsRecordingQuerier) Select(p *storage.SelectParams, _ ...*labels.Matcher) (storage.SeriesSet, storage.Warnings, error) {
+	q.params = append(q.params, p)
+	return storage.NoopSeriesSet(), q.warnings, nil
+}
+
+func (q *paramsRecordingQuerier) Querier(context.Context, int64, int64) (storage.Querier, error) {
+	return q, nil
+}
+
+func (q *paramsRecordingQuerier) LabelValues(string) ([]string, error) { return nil, nil }
+func (q *paramsRecordingQuerier) LabelNames() ([]string, error)        { return nil, nil }
+func (q *paramsRecordingQuerier) Close() error                         { return nil }
+
+func TestSelectParams(t *testing.T) {
+	lookback := durationMilliseconds(LookbackDelta)
+
+	cases := []struct {
+		query      string
+		start, end int64
+		step       time.Duration
+		params     *storage.SelectParams
+	}{
+		{
+			query: "foo", start: 10000,
+			params: &storage.SelectParams{Start: 10000 - lookback, End: 10000},
+		}, {
+			query: "foo offset 1m", start: 10000,
+			params: &storage.SelectParams{Start: 10000 - 60000 - lookback, End: 10000 - 60000},
+		}, {
+			query: "rate(foo[2m])", start: 0, end: 60000, step: 10 * time.Second,
+			params: &storage.SelectParams{Start: -120000, End: 60000, Step: 10000, Range: 120000, Func: "rate"},
+		}, {
+			query: "sum by (job) ((rate(foo[2m])))", start: 10000,
+			params: &storage.SelectParams{Start: 10000 - 120000, End: 10000, Range: 120000, Func: "rate", Grouping: []string{"job"}, By: true},
+		}, {
+			query: "max without (instance) (foo)", start: 10000,
+			params: &storage.SelectParams{Start: 10000 - lookback, End: 10000, Func: "max", Grouping: []string{"instance"}},
+		}, {
+			// Grouping does not apply through binary operations.
+			query: "sum(foo * 2)", start: 10000,
+			params: &storage.SelectParams{Start: 10000 - lookback, End: 10000},
+		},
+	}
+
+	for _, c := range cases {
+		q := &paramsRecordingQuerier{}
+		engine := NewEngine(q, nil)
+
+		var (
+			qry Query
+			err error
+		)
+		if c.step == 0 {
+			qry, err = engine.NewInstantQuery(c.query, time.Unix(0, c.start*int64(time.Millisecond)))
+		} else {
+			qry, err = engine.NewRangeQuery(c.query, time.Unix(0, c.start*int64(time.Millisecond)), time.Unix(0, c.end*int64(time.Millisecond)), c.step)
+		}
+		if err != nil {
+			t.Fatalf("unexpected error creating query %q: %s", c.query, err)
+		}
+		if res := qry.Exec(context.Background()); res.Err != nil {
+			t.Fatalf("unexpected error running query %q: %s", c.query, res.Err)
+		}
+		if len(q.params) != 1 || !reflect.DeepEqual(q.params[0], c.params) {
+			t.Fatalf("unexpected select params for query %q: expected %+v, got %+v", c.query, c.params, q.params[0])
+		}
+	}
+}
+
+func TestNegativeOffset(t *testing.T) {
+	EnableNegativeOffset = true
+	defer func() { EnableNegativeOffset = false }()
+
+	test, err := NewTest(t, `
+load 1m
+  metric 0+1x10
+`)
+	if err != nil {
+		t.Fatalf("unexpected error creating test: %q", err)
+	}
+	defer test.Close()
+
+	if err := test.Run(); err != nil {
+		t.Fatalf("unexpected error initializing test: %q", err)
+	}
+
+	cases := []struct {
+		query string
+		start time.Time
+		value float64
+	}{
+		// The samples after the evaluation time are selected.
+		{query: "metric offset -2m", start: time.Unix(300, 0), value: 7},
+		{query: "sum_over_time(metric[2m] offset -5m)", start: time.Unix(300, 0), value: 8 + 9 + 10},
+		{query: "metric offset -2m - metric", start: time.Unix(300, 0), value: 2},
+	}
+	for _, c := range cases {
+		qry, err := test.QueryEngine().NewInstantQuery(c.query, c.start)
+		if err != nil {
+			t.Fatalf("unexpected error creating query %q: %s", c.query, err)
+		}
+		res := qry.Exec(test.Context())
+		if res.Err != nil {
+			t.Fatalf("unexpected error running query %q: %s", c.query, res.Err)
+		}
+		vec, err := res.Vector()
+		if err != nil {
+			t.Fatalf("unexpected result of query %q: %s", c.query, err)
+		}
+		if len(vec) != 1 || vec[0].V != c.value {
+			t.Fatalf("expected %v for query %q, got %v", c.value, c.query, vec)
+		}
+	}
+}
+
+func TestQueryWarnings(t *testing.T) {
+	q := &paramsRecordingQuerier{
+		warnings: storage.Warnings{fmt.Errorf("remote read failed")},
+	}
+	engine := NewEngine(q, nil)
+
+	qry, err := engine.NewInstantQuery("foo + bar", time.Unix(0, 0))
+	if err != nil {
+		t.Fatalf("unexpected error creating query: %s", err)
+	}
+	res := qry.Exec(context.Background())
+	if res.Err != nil {
+		t.Fatalf("unexpected error running query: %s", res.Err)
+	}
+	if len(res.Warnings) != 2 || res.Warnings[0].Error() != "remote read failed" {
+		t.Fatalf("expected a warning per selector, got %v", res.Warnings)
+	}
+
+	// Suspicious vector matching is reported after the warnings of the storage.
+	qry, err = engine.NewInstantQuery("sum by(job)(foo) / sum(bar)", time.Unix(0, 0))
+	if err != nil {
+		t.Fatalf("unexpected error creating query: %s", err)
+	}
+	res = qry.Exec(context.Background())
+	if res.Err != nil {
+		t.Fatalf("unexpected error running query: %s", res.Err)
+	}
+	if len(res.Warnings) != 3 || !strings.Contains(res.Warnings[2].Error(), "never match") {
+		t.Fatalf("expected a warning about the vector matching, got %v", res.Warnings)
+	}
+}
+
 func TestRecoverEvaluatorRuntime(t *testing.T) {
 	ev := &evaluator{logger: log.NewNopLogger()}
 
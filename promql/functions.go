@@ -28,8 +28,11 @@ import (
 // Function represents a function of the expression language and is
 // used by function nodes.
 type Function struct {
-	Name       string
-	ArgTypes   []ValueType
+	Name     string
+	ArgTypes []ValueType
+	// Variadic is the number of trailing arguments that may be omitted. If
+	// negative, the last argument may be omitted or repeated any number of
+	// times.
 	Variadic   int
 	ReturnType ValueType
 	Call       func(ev *evaluator, args Expressions) Value
@@ -884,6 +887,14 @@ func funcLabelJoin(ev *evaluator, args Expressions) Value {
 
 // Common code for date related functions.
 func dateWrapper(ev *evaluator, args Expressions, f func(time.Time) float64) Value {
+	// The optional second argument is the offset of the time zone to
+	// evaluate the dates in, in hours east of UTC.
+	loc := time.UTC
+	if len(args) > 1 {
+		offset := ev.evalFloat(args[1])
+		loc = time.FixedZone("", int(offset*3600))
+	}
+
 	var v Vector
 	if len(args) == 0 {
 		v = Vector{
@@ -899,55 +910,55 @@ func dateWrapper(ev *evaluator, args Expressions, f func(time.Time) float64) Val
 		el := &v[i]
 
 		el.Metric = dropMetricName(el.Metric)
-		t := time.Unix(int64(el.V), 0).UTC()
+		t := time.Unix(int64(el.V), 0).In(loc)
 		el.V = f(t)
 	}
 	return v
 }
 
-// === days_in_month(v Vector) Scalar ===
+// === days_in_month(v Vector, offset Scalar) Scalar ===
 func funcDaysInMonth(ev *evaluator, args Expressions) Value {
 	return dateWrapper(ev, args, func(t time.Time) float64 {
-		return float64(32 - time.Date(t.Year(), t.Month(), 32, 0, 0, 0, 0, time.UTC).Day())
+		return float64(32 - time.Date(t.Year(), t.Month(), 32, 0, 0, 0, 0, t.Location()).Day())
 	})
 }
 
-// === day_of_month(v Vector) Scalar ===
+// === day_of_month(v Vector, offset Scalar) Scalar ===
 func funcDayOfMonth(ev *evaluator, args Expressions) Value {
 	return dateWrapper(ev, args, func(t time.Time) float64 {
 		return float64(t.Day())
 	})
 }
 
-// === day_of_week(v Vector) Scalar ===
+// === day_of_week(v Vector, offset Scalar) Scalar ===
 func funcDayOfWeek(ev *evaluator, args Expressions) Value {
 	return dateWrapper(ev, args, func(t time.Time) float64 {
 		return float64(t.Weekday())
 	})
 }
 
-// === hour(v Vector) Scalar ===
+// === hour(v Vector, offset Scalar) Scalar ===
 func funcHour(ev *evaluator, args Expressions) Value {
 	return dateWrapper(ev, args, func(t time.Time) float64 {
 		return float64(t.Hour())
 	})
 }
 
-// === minute(v Vector) Scalar ===
+// === minute(v Vector, offset Scalar) Scalar ===
 func funcMinute(ev *evaluator, args Expressions) Value {
 	return dateWrapper(ev, args, func(t time.Time) float64 {
 		return float64(t.Minute())
 	})
 }
 
-// === month(v Vector) Scalar ===
+// === month(v Vector, offset Scalar) Scalar ===
 func funcMonth(ev *evaluator, args Expressions) Value {
 	return dateWrapper(ev, args, func(t time.Time) float64 {
 		return float64(t.Month())
 	})
 }
 
-// === year(v Vector) Scalar ===
+// === year(v Vector, offset Scalar) Scalar ===
 func funcYear(ev *evaluator, args Expressions) Value {
 	return dateWrapper(ev, args, func(t time.Time) float64 {
 		return float64(t.Year())
@@ -1005,22 +1016,22 @@ var functions = map[string]*Function{
 	},
 	"days_in_month": {
 		Name:       "days_in_month",
-		ArgTypes:   []ValueType{ValueTypeVector},
-		Variadic:   1,
+		ArgTypes:   []ValueType{ValueTypeVector, ValueTypeScalar},
+		Variadic:   2,
 		ReturnType: ValueTypeVector,
 		Call:       funcDaysInMonth,
 	},
 	"day_of_month": {
 		Name:       "day_of_month",
-		ArgTypes:   []ValueType{ValueTypeVector},
-		Variadic:   1,
+		ArgTypes:   []ValueType{ValueTypeVector, ValueTypeScalar},
+		Variadic:   2,
 		ReturnType: ValueTypeVector,
 		Call:       funcDayOfMonth,
 	},
 	"day_of_week": {
 		Name:       "day_of_week",
-		ArgTypes:   []ValueType{ValueTypeVector},
-		Variadic:   1,
+		ArgTypes:   []ValueType{ValueTypeVector, ValueTypeScalar},
+		Variadic:   2,
 		ReturnType: ValueTypeVector,
 		Call:       funcDayOfWeek,
 	},
@@ -1062,8 +1073,8 @@ var functions = map[string]*Function{
 	},
 	"hour": {
 		Name:       "hour",
-		ArgTypes:   []ValueType{ValueTypeVector},
-		Variadic:   1,
+		ArgTypes:   []ValueType{ValueTypeVector, ValueTypeScalar},
+		Variadic:   2,
 		ReturnType: ValueTypeVector,
 		Call:       funcHour,
 	},
@@ -1130,15 +1141,15 @@ var functions = map[string]*Function{
 	},
 	"minute": {
 		Name:       "minute",
-		ArgTypes:   []ValueType{ValueTypeVector},
-		Variadic:   1,
+		ArgTypes:   []ValueType{ValueTypeVector, ValueTypeScalar},
+		Variadic:   2,
 		ReturnType: ValueTypeVector,
 		Call:       funcMinute,
 	},
 	"month": {
 		Name:       "month",
-		ArgTypes:   []ValueType{ValueTypeVector},
-		Variadic:   1,
+		ArgTypes:   []ValueType{ValueTypeVector, ValueTypeScalar},
+		Variadic:   2,
 		ReturnType: ValueTypeVector,
 		Call:       funcMonth,
 	},
@@ -1235,8 +1246,8 @@ var functions = map[string]*Function{
 	},
 	"year": {
 		Name:       "year",
-		ArgTypes:   []ValueType{ValueTypeVector},
-		Variadic:   1,
+		ArgTypes:   []ValueType{ValueTypeVector, ValueTypeScalar},
+		Variadic:   2,
 		ReturnType: ValueTypeVector,
 		Call:       funcYear,
 	},
@@ -50,7 +50,7 @@ const (
 // Note that his is not the parser for the text-based exposition-format; that
 // lives in github.com/prometheus/client_golang/text.
 func FuzzParseMetric(in []byte) int {
-	p := textparse.New(in)
+	p := textparse.NewPromParser(in)
 	for p.Next() {
 	}
 
@@ -329,6 +329,8 @@ type lexer struct {
 	start   Pos       // Start position of this item.
 	width   Pos       // Width of last rune read from input.
 	lastPos Pos       // Position of most recent item returned by nextItem.
+	lastEnd Pos       // End position of most recent item returned by nextItem.
+	errEnd  Pos       // Position at which lexing failed.
 	items   chan item // Channel of scanned items.
 
 	parenDepth  int  // Nesting depth of ( ) exprs.
@@ -413,6 +415,7 @@ func (l *lexer) linePosition() int {
 // errorf returns an error token and terminates the scan by passing
 // back a nil pointer that will be the next state, terminating l.nextItem.
 func (l *lexer) errorf(format string, args ...interface{}) stateFn {
+	l.errEnd = l.pos
 	l.items <- item{itemError, l.start, fmt.Sprintf(format, args...)}
 	return nil
 }
@@ -421,9 +424,33 @@ func (l *lexer) errorf(format string, args ...interface{}) stateFn {
 func (l *lexer) nextItem() item {
 	item := <-l.items
 	l.lastPos = item.pos
+	switch item.typ {
+	case itemError:
+		// The value of an error item is the error message, the erroneous
+		// input extends to where lexing stopped.
+		l.lastEnd = l.errEnd
+	case itemEOF:
+		l.lastEnd = item.pos
+	default:
+		l.lastEnd = item.pos + Pos(len(item.val))
+	}
 	return item
 }
 
+// lastPosRange returns the position range of the most recent item returned
+// by nextItem.
+func (l *lexer) lastPosRange() PositionRange {
+	end := l.lastEnd
+	if end <= l.lastPos {
+		end = l.lastPos
+		// Cover at least a single character unless at the end of the input.
+		if int(end) < len(l.input) {
+			end++
+		}
+	}
+	return PositionRange{Start: l.lastPos, End: end}
+}
+
 // lex creates a new scanner for the input string.
 func lex(input string) *lexer {
 	l := &lexer{
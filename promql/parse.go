@@ -41,7 +41,16 @@ type parser struct {
 // from the error string.
 type ParseErr struct {
 	Line, Pos int
-	Err       error
+	// PositionRange is the range of the offending token in the input.
+	PositionRange PositionRange
+	Err           error
+}
+
+// PositionRange describes the range of a token in the parsed input. Start
+// and End are byte offsets, End is exclusive.
+type PositionRange struct {
+	Start Pos `json:"start"`
+	End   Pos `json:"end"`
 }
 
 func (e *ParseErr) Error() string {
@@ -303,9 +312,10 @@ func (p *parser) errorf(format string, args ...interface{}) {
 // error terminates processing.
 func (p *parser) error(err error) {
 	perr := &ParseErr{
-		Line: p.lex.lineNumber(),
-		Pos:  p.lex.linePosition(),
-		Err:  err,
+		Line:          p.lex.lineNumber(),
+		Pos:           p.lex.linePosition(),
+		PositionRange: p.lex.lastPosRange(),
+		Err:           err,
 	}
 	if strings.Count(strings.TrimSpace(p.lex.input), "\n") == 0 {
 		perr.Line = 0
@@ -913,20 +923,35 @@ func (p *parser) metric() labels.Labels {
 	return m
 }
 
+// EnableNegativeOffset allows offset modifiers with negative durations, which
+// select samples after the evaluation time.
+var EnableNegativeOffset = false
+
 // offset parses an offset modifier.
 //
-//		offset <duration>
+//		offset [-]<duration>
 //
 func (p *parser) offset() time.Duration {
 	const ctx = "offset"
 
 	p.next()
+	negative := false
+	if t := p.peek(); t.typ == itemSUB {
+		if !EnableNegativeOffset {
+			p.errorf("negative offsets are not enabled")
+		}
+		p.next()
+		negative = true
+	}
 	offi := p.expect(itemDuration, ctx)
 
 	offset, err := parseDuration(offi.val)
 	if err != nil {
 		p.error(err)
 	}
+	if negative {
+		offset = -offset
+	}
 
 	return offset
 }
@@ -1086,10 +1111,13 @@ func (p *parser) checkType(node Node) (typ ValueType) {
 			}
 		} else {
 			na := nargs - 1
+			if n.Func.Variadic > 0 {
+				na = nargs - n.Func.Variadic
+			}
 			if na > len(n.Args) {
 				p.errorf("expected at least %d argument(s) in call to %q, got %d", na, n.Func.Name, len(n.Args))
-			} else if nargsmax := na + n.Func.Variadic; n.Func.Variadic > 0 && nargsmax < len(n.Args) {
-				p.errorf("expected at most %d argument(s) in call to %q, got %d", nargsmax, n.Func.Name, len(n.Args))
+			} else if n.Func.Variadic > 0 && nargs < len(n.Args) {
+				p.errorf("expected at most %d argument(s) in call to %q, got %d", nargs, n.Func.Name, len(n.Args))
 			}
 		}
 
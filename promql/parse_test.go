@@ -1046,6 +1046,10 @@ var testExpr = []struct {
 		input:  `some_metric[5m] OFFSET`,
 		fail:   true,
 		errMsg: "unexpected end of input in offset, expected duration",
+	}, {
+		input:  `some_metric OFFSET -5m`,
+		fail:   true,
+		errMsg: "negative offsets are not enabled",
 	}, {
 		input:  `some_metric OFFSET 1m[5m]`,
 		fail:   true,
@@ -1301,6 +1305,32 @@ var testExpr = []struct {
 				&NumberLiteral{5},
 			},
 		},
+	}, {
+		input: "hour(some_metric, 2)",
+		expected: &Call{
+			Func: mustGetFunction("hour"),
+			Args: Expressions{
+				&VectorSelector{
+					Name: "some_metric",
+					LabelMatchers: []*labels.Matcher{
+						mustLabelMatcher(labels.MatchEqual, string(model.MetricNameLabel), "some_metric"),
+					},
+				},
+				&NumberLiteral{2},
+			},
+		},
+	}, {
+		input:  "hour(some_metric, 2, 3)",
+		fail:   true,
+		errMsg: "expected at most 2 argument(s) in call to \"hour\", got 3",
+	}, {
+		input:  "hour(some_metric, other_metric)",
+		fail:   true,
+		errMsg: "expected type scalar in call to function \"hour\", got instant vector",
+	}, {
+		input:  "round()",
+		fail:   true,
+		errMsg: "expected at least 1 argument(s) in call to \"round\", got 0",
 	}, {
 		input:  "floor()",
 		fail:   true,
@@ -1394,6 +1424,29 @@ var testExpr = []struct {
 	},
 }
 
+func TestParseErrorPositions(t *testing.T) {
+	for _, c := range []struct {
+		input string
+		pos   PositionRange
+	}{
+		{input: "1 + )", pos: PositionRange{Start: 4, End: 5}},
+		{input: "a\n+ )", pos: PositionRange{Start: 4, End: 5}},
+		{input: "sum(foo) bar", pos: PositionRange{Start: 9, End: 12}},
+		{input: "foo[5mm]", pos: PositionRange{Start: 4, End: 7}},
+		{input: `foo{a="b`, pos: PositionRange{Start: 6, End: 8}},
+		{input: "rate(foo[5m]) +", pos: PositionRange{Start: 15, End: 15}},
+	} {
+		_, err := ParseExpr(c.input)
+		perr, ok := err.(*ParseErr)
+		if !ok {
+			t.Fatalf("expected parse error for input %q, got %v", c.input, err)
+		}
+		if perr.PositionRange != c.pos {
+			t.Fatalf("unexpected position of error %q for input %q: expected %v, got %v", perr, c.input, c.pos, perr.PositionRange)
+		}
+	}
+}
+
 func TestParseExpressions(t *testing.T) {
 	for _, test := range testExpr {
 		parser := newParser(test.input)
@@ -1442,6 +1495,37 @@ func TestParseExpressions(t *testing.T) {
 	}
 }
 
+func TestParseNegativeOffset(t *testing.T) {
+	EnableNegativeOffset = true
+	defer func() { EnableNegativeOffset = false }()
+
+	for _, c := range []struct {
+		input, output string
+		offset        time.Duration
+	}{
+		{input: "foo offset -5m", output: "foo OFFSET -5m", offset: -5 * time.Minute},
+		{input: "foo[1m] OFFSET -1h", output: "foo[1m] OFFSET -1h", offset: -time.Hour},
+	} {
+		expr, err := ParseExpr(c.input)
+		if err != nil {
+			t.Fatalf("could not parse %q: %s", c.input, err)
+		}
+		var offset time.Duration
+		switch e := expr.(type) {
+		case *VectorSelector:
+			offset = e.Offset
+		case *MatrixSelector:
+			offset = e.Offset
+		}
+		if offset != c.offset {
+			t.Fatalf("expected offset %s for %q, got %s", c.offset, c.input, offset)
+		}
+		if expr.String() != c.output {
+			t.Fatalf("expected %q to be printed as %q, got %q", c.input, c.output, expr.String())
+		}
+	}
+}
+
 // NaN has no equality. Thus, we need a separate test for it.
 func TestNaNExpression(t *testing.T) {
 	parser := newParser("NaN")
@@ -0,0 +1,166 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// PrettyMaxWidth is the width up to which Pretty prints expressions on a
+// single line.
+const PrettyMaxWidth = 80
+
+const prettyIndent = "  "
+
+// Pretty returns the expression in the canonical style: keywords are lower
+// case, modifiers of aggregations precede their arguments and the label
+// matchers of selectors are sorted. Expressions wider than PrettyMaxWidth are
+// split across lines, with the arguments of aggregations, functions and
+// parentheses and the operands of binary operations indented on lines of
+// their own.
+func Pretty(expr Expr) string {
+	return pretty(expr, "")
+}
+
+func pretty(expr Expr, indent string) string {
+	s := indent + prettyLine(expr)
+	if len(s) <= PrettyMaxWidth {
+		return s
+	}
+
+	inner := indent + prettyIndent
+	switch e := expr.(type) {
+	case *AggregateExpr:
+		args := []string{}
+		if e.Op.isAggregatorWithParam() {
+			args = append(args, pretty(e.Param, inner))
+		}
+		args = append(args, pretty(e.Expr, inner))
+		return fmt.Sprintf("%s%s(\n%s\n%s)", indent, prettyAggregator(e), strings.Join(args, ",\n"), indent)
+
+	case *BinaryExpr:
+		return fmt.Sprintf("%s\n%s%s\n%s", pretty(e.LHS, indent), indent, prettyOperator(e), pretty(e.RHS, indent))
+
+	case *Call:
+		if len(e.Args) == 0 {
+			return s
+		}
+		args := make([]string, 0, len(e.Args))
+		for _, a := range e.Args {
+			args = append(args, pretty(a, inner))
+		}
+		return fmt.Sprintf("%s%s(\n%s\n%s)", indent, e.Func.Name, strings.Join(args, ",\n"), indent)
+
+	case *ParenExpr:
+		return fmt.Sprintf("%s(\n%s\n%s)", indent, pretty(e.Expr, inner), indent)
+
+	case *UnaryExpr:
+		return fmt.Sprintf("%s%s%s", indent, e.Op, strings.TrimPrefix(pretty(e.Expr, indent), indent))
+	}
+	// Selectors and literals cannot be split.
+	return s
+}
+
+// prettyLine returns the expression in the canonical style on a single line.
+func prettyLine(expr Expr) string {
+	switch e := expr.(type) {
+	case *AggregateExpr:
+		args := prettyLine(e.Expr)
+		if e.Op.isAggregatorWithParam() {
+			args = prettyLine(e.Param) + ", " + args
+		}
+		return fmt.Sprintf("%s(%s)", prettyAggregator(e), args)
+
+	case *BinaryExpr:
+		return fmt.Sprintf("%s %s %s", prettyLine(e.LHS), prettyOperator(e), prettyLine(e.RHS))
+
+	case *Call:
+		args := make([]string, 0, len(e.Args))
+		for _, a := range e.Args {
+			args = append(args, prettyLine(a))
+		}
+		return fmt.Sprintf("%s(%s)", e.Func.Name, strings.Join(args, ", "))
+
+	case *MatrixSelector:
+		vs := &VectorSelector{Name: e.Name, LabelMatchers: e.LabelMatchers}
+		return fmt.Sprintf("%s[%s]%s", vs, model.Duration(e.Range), prettyOffset(e.Offset))
+
+	case *NumberLiteral:
+		return strconv.FormatFloat(e.Val, 'g', -1, 64)
+
+	case *ParenExpr:
+		return fmt.Sprintf("(%s)", prettyLine(e.Expr))
+
+	case *UnaryExpr:
+		return fmt.Sprintf("%s%s", e.Op, prettyLine(e.Expr))
+
+	case *VectorSelector:
+		// Apart from the offset, selectors are printed canonically already.
+		vs := &VectorSelector{Name: e.Name, LabelMatchers: e.LabelMatchers}
+		return vs.String() + prettyOffset(e.Offset)
+	}
+	// String literals.
+	return expr.String()
+}
+
+// prettyAggregator returns the aggregation operator with its grouping, if any.
+func prettyAggregator(e *AggregateExpr) string {
+	modifier := "by"
+	if e.Without {
+		modifier = "without"
+	} else if len(e.Grouping) == 0 {
+		return e.Op.String()
+	}
+	return fmt.Sprintf("%s %s (%s) ", e.Op, modifier, strings.Join(e.Grouping, ", "))
+}
+
+func prettyOperator(e *BinaryExpr) string {
+	s := e.Op.String()
+	if e.ReturnBool {
+		s += " bool"
+	}
+	vm := e.VectorMatching
+	if vm == nil || (len(vm.MatchingLabels) == 0 && !vm.On) {
+		return s
+	}
+	if vm.On {
+		s += fmt.Sprintf(" on (%s)", strings.Join(vm.MatchingLabels, ", "))
+	} else {
+		s += fmt.Sprintf(" ignoring (%s)", strings.Join(vm.MatchingLabels, ", "))
+	}
+	switch vm.Card {
+	case CardManyToOne:
+		s += " group_left"
+	case CardOneToMany:
+		s += " group_right"
+	default:
+		return s
+	}
+	if len(vm.Include) > 0 {
+		s += fmt.Sprintf(" (%s)", strings.Join(vm.Include, ", "))
+	}
+	return s
+}
+
+func prettyOffset(offset time.Duration) string {
+	if offset == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" offset %s", model.Duration(offset))
+}
@@ -0,0 +1,122 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promql
+
+import (
+	"testing"
+)
+
+func TestPretty(t *testing.T) {
+	// A list of valid expressions that are expected to be returned as out
+	// by Pretty. If out is empty the output is expected to equal the input.
+	inputs := []struct {
+		in, out string
+	}{
+		{
+			in:  `SUM(foo{job="s"}) BY (code)`,
+			out: `sum by (code) (foo{job="s"})`,
+		},
+		{
+			in:  `sum(foo) BY ()`,
+			out: `sum(foo)`,
+		},
+		{
+			in:  `sum(foo) WITHOUT ()`,
+			out: `sum without () (foo)`,
+		},
+		{
+			in: `topk by (job) (5, foo)`,
+		},
+		{
+			in:  `foo{b="2",a="1"} OFFSET 5m`,
+			out: `foo{a="1",b="2"} offset 5m`,
+		},
+		{
+			in:  `rate(foo[300s] OFFSET 1h)`,
+			out: `rate(foo[5m] offset 1h)`,
+		},
+		{
+			in:  `a - ON(b) GROUP_LEFT(x, y) c`,
+			out: `a - on (b) group_left (x, y) c`,
+		},
+		{
+			in:  `a - IGNORING(b) GROUP_RIGHT c`,
+			out: `a - ignoring (b) group_right c`,
+		},
+		{
+			in:  `a AND ON() b`,
+			out: `a and on () b`,
+		},
+		{
+			in:  `up > BOOL 0`,
+			out: `up > bool 0`,
+		},
+		{
+			in:  `-(1e6 * foo)`,
+			out: `-(1e+06 * foo)`,
+		},
+		{
+			in: `label_replace(up, "a", "$1", "job", "(.*)")`,
+		},
+		{
+			in: `sum by (job) (rate(http_requests_total{code=~"5..",handler!="/metrics"}[5m])) / sum by (job) (rate(http_requests_total[5m]))`,
+			out: `sum by (job) (rate(http_requests_total{code=~"5..",handler!="/metrics"}[5m]))
+/
+sum by (job) (rate(http_requests_total[5m]))`,
+		},
+		{
+			in: `histogram_quantile(0.99, sum without (instance) (rate(http_request_duration_seconds_bucket{job="api"}[5m])))`,
+			out: `histogram_quantile(
+  0.99,
+  sum without (instance) (
+    rate(http_request_duration_seconds_bucket{job="api"}[5m])
+  )
+)`,
+		},
+		{
+			in: `(node_filesystem_avail_bytes{mountpoint="/"} / node_filesystem_size_bytes{mountpoint="/"}) < 0.1`,
+			out: `(
+  node_filesystem_avail_bytes{mountpoint="/"}
+  /
+  node_filesystem_size_bytes{mountpoint="/"}
+)
+<
+0.1`,
+		},
+	}
+
+	for _, test := range inputs {
+		expr, err := ParseExpr(test.in)
+		if err != nil {
+			t.Fatalf("parsing error for %q: %s", test.in, err)
+		}
+		exp := test.in
+		if test.out != "" {
+			exp = test.out
+		}
+		got := Pretty(expr)
+		if got != exp {
+			t.Fatalf("expected %q to be formatted as:\n%s\ngot:\n%s\n", test.in, exp, got)
+		}
+
+		// Formatting must not change the meaning of the expression.
+		reparsed, err := ParseExpr(got)
+		if err != nil {
+			t.Fatalf("parsing error for formatted %q: %s", got, err)
+		}
+		if reparsed.String() != expr.String() {
+			t.Fatalf("expected formatted %q to parse as:\n%s\ngot:\n%s\n", test.in, expr, reparsed)
+		}
+	}
+}
@@ -15,7 +15,9 @@ package promql
 
 import (
 	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestEvaluations(t *testing.T) {
@@ -35,3 +37,56 @@ func TestEvaluations(t *testing.T) {
 		test.Close()
 	}
 }
+
+func TestLazyLoader(t *testing.T) {
+	ll, err := NewLazyLoader(t, `
+load 1m
+	metric{job="a"} 1 2 3 4
+	metric{job="b"} 10 _ 30
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ll.Close()
+
+	for _, c := range []struct {
+		at       time.Duration
+		expected map[string]float64
+	}{
+		// Samples after the given time must not be loaded yet.
+		{at: 1 * time.Minute, expected: map[string]float64{"a": 2, "b": 1}},
+		{at: 2 * time.Minute, expected: map[string]float64{"a": 3, "b": 2}},
+		{at: 5 * time.Minute, expected: map[string]float64{"a": 4, "b": 2}},
+	} {
+		ts := time.Unix(0, 0).Add(c.at)
+		ll.WithSamplesTill(ts, func(err error) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Query far into the future to see all loaded samples.
+			q, err := ll.QueryEngine().NewInstantQuery("count_over_time(metric[1h])", ts.Add(30*time.Minute))
+			if err != nil {
+				t.Fatal(err)
+			}
+			res := q.Exec(ll.Context())
+			if res.Err != nil {
+				t.Fatal(res.Err)
+			}
+			vec, err := res.Vector()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := map[string]float64{}
+			for _, s := range vec {
+				got[s.Metric.Get("job")] = s.V
+			}
+			if !reflect.DeepEqual(c.expected, got) {
+				t.Fatalf("at %s: expected %v, got %v", c.at, c.expected, got)
+			}
+		})
+	}
+
+	if _, err := NewLazyLoader(t, "eval instant at 0 metric"); err == nil {
+		t.Fatal("expected error for non-load command")
+	}
+}
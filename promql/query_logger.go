@@ -0,0 +1,165 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promql
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/prometheus/prometheus/util/mmap"
+)
+
+const (
+	// activeQueryFile is the name of the active query log within the
+	// data directory.
+	activeQueryFile = "queries.active"
+	// entrySize is the number of bytes reserved per query in the active
+	// query log.
+	entrySize = 1000
+)
+
+// ActiveQueryTracker records the currently running queries in a memory mapped
+// file. As the operating system persists the file even if Prometheus crashes,
+// the queries that were running at the time of a crash are logged on the next
+// start.
+type ActiveQueryTracker struct {
+	file   *mmap.MappedFile
+	slots  chan int
+	logger log.Logger
+}
+
+type activeQuery struct {
+	Query     string `json:"query"`
+	Timestamp int64  `json:"timestamp_sec"`
+}
+
+// NewActiveQueryTracker returns a tracker that keeps the active query log in
+// the given directory, with room for maxConcurrent queries. Queries left in the
+// log by a previous run are logged as unfinished.
+func NewActiveQueryTracker(dir string, maxConcurrent int, logger log.Logger) (*ActiveQueryTracker, error) {
+	if logger == nil {
+		logger = log.NewNopLogger()
+	}
+	filename := filepath.Join(dir, activeQueryFile)
+	logUnfinishedQueries(filename, logger)
+
+	f, err := mmap.Create(filename, maxConcurrent*entrySize)
+	if err != nil {
+		return nil, err
+	}
+	t := &ActiveQueryTracker{
+		file:   f,
+		slots:  make(chan int, maxConcurrent),
+		logger: logger,
+	}
+	for i := 0; i < maxConcurrent; i++ {
+		t.clear(i)
+		t.slots <- i
+	}
+	return t, nil
+}
+
+// logUnfinishedQueries logs all queries remaining in the active query log of
+// a previous run.
+func logUnfinishedQueries(filename string, logger log.Logger) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			level.Error(logger).Log("msg", "Failed to read active query log", "file", filename, "err", err)
+		}
+		return
+	}
+	var queries []activeQuery
+	for len(b) > 0 {
+		n := entrySize
+		if n > len(b) {
+			n = len(b)
+		}
+		entry := bytes.TrimSpace(bytes.Trim(b[:n], "\x00"))
+		b = b[n:]
+
+		if len(entry) == 0 {
+			continue
+		}
+		var q activeQuery
+		if err := json.Unmarshal(entry, &q); err != nil {
+			level.Error(logger).Log("msg", "Failed to parse active query log entry", "entry", string(entry), "err", err)
+			continue
+		}
+		queries = append(queries, q)
+	}
+	if len(queries) == 0 {
+		return
+	}
+	level.Warn(logger).Log("msg", "These queries didn't finish in Prometheus' last run")
+	for _, q := range queries {
+		level.Warn(logger).Log("query", q.Query, "started", time.Unix(q.Timestamp, 0).UTC())
+	}
+}
+
+// encodeActiveQuery returns the JSON encoding of the query, truncating the
+// query so the encoding fits into an entry.
+func encodeActiveQuery(query string, ts time.Time) []byte {
+	for {
+		b, err := json.Marshal(activeQuery{Query: query, Timestamp: ts.Unix()})
+		if err != nil {
+			return nil
+		}
+		// Keep the last byte of each entry for a newline.
+		excess := len(b) - (entrySize - 1)
+		if excess <= 0 {
+			return b
+		}
+		if excess > len(query) {
+			excess = len(query)
+		}
+		query = query[:len(query)-excess]
+	}
+}
+
+// clear marks the entry at the given index as unused.
+func (t *ActiveQueryTracker) clear(i int) {
+	entry := t.file.Bytes()[i*entrySize : (i+1)*entrySize]
+	for j := range entry {
+		entry[j] = ' '
+	}
+	entry[entrySize-1] = '\n'
+}
+
+// Insert records the query as active and returns the index of its entry. It
+// blocks until an entry is free.
+func (t *ActiveQueryTracker) Insert(query string) int {
+	i := <-t.slots
+	t.clear(i)
+	copy(t.file.Bytes()[i*entrySize:], encodeActiveQuery(query, time.Now()))
+	return i
+}
+
+// Delete removes the entry at the given index returned by Insert.
+func (t *ActiveQueryTracker) Delete(i int) {
+	t.clear(i)
+	t.slots <- i
+}
+
+// Close closes the active query log.
+func (t *ActiveQueryTracker) Close() error {
+	return t.file.Close()
+}
@@ -0,0 +1,85 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promql
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+)
+
+func TestActiveQueryTracker(t *testing.T) {
+	dir, err := ioutil.TempDir("", "active_query_tracker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tracker, err := NewActiveQueryTracker(dir, 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i := tracker.Insert(`rate(http_requests_total[5m])`)
+	j := tracker.Insert(strings.Repeat("a", 2*entrySize))
+	tracker.Delete(i)
+	tracker.Insert(`up == 0`)
+
+	if len(tracker.file.Bytes()) != 2*entrySize {
+		t.Fatalf("unexpected size of active query log: %d", len(tracker.file.Bytes()))
+	}
+	// Simulate a crash by not closing the tracker before reading the log.
+	var buf bytes.Buffer
+	logUnfinishedQueries(filepath.Join(dir, activeQueryFile), log.NewLogfmtLogger(&buf))
+
+	out := buf.String()
+	if !strings.Contains(out, `query="up == 0"`) {
+		t.Fatalf("running query not logged: %s", out)
+	}
+	if strings.Contains(out, "rate(http_requests_total[5m])") {
+		t.Fatalf("finished query logged: %s", out)
+	}
+	if !strings.Contains(out, strings.Repeat("a", entrySize/2)) {
+		t.Fatalf("truncated query not logged: %s", out)
+	}
+
+	tracker.Delete(j)
+	if err := tracker.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The log of a restarted tracker starts out empty.
+	buf.Reset()
+	tracker, err = NewActiveQueryTracker(dir, 2, log.NewLogfmtLogger(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tracker.Close()
+
+	if !strings.Contains(buf.String(), `query="up == 0"`) {
+		t.Fatalf("unfinished query not logged on start: %s", buf.String())
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, activeQueryFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bytes.TrimSpace(b)) != 0 {
+		t.Fatalf("active query log not empty: %q", b)
+	}
+}
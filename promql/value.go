@@ -20,6 +20,7 @@ import (
 	"strings"
 
 	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/storage"
 )
 
 // Value is a generic interface for values resulting from a query evaluation.
@@ -160,10 +161,12 @@ func (m Matrix) Less(i, j int) bool { return labels.Compare(m[i].Metric, m[j].Me
 func (m Matrix) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
 
 // Result holds the resulting value of an execution or an error
-// if any occurred.
+// if any occurred. Warnings are problems of the storage that still
+// allowed to compute a possibly partial result.
 type Result struct {
-	Err   error
-	Value Value
+	Err      error
+	Value    Value
+	Warnings storage.Warnings
 }
 
 // Vector returns a Vector if the result value is one. An error is returned if
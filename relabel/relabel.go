@@ -70,9 +70,21 @@ func relabel(labels model.LabelSet, cfg *config.RelabelConfig) model.LabelSet {
 			break
 		}
 		labels[target] = model.LabelValue(res)
+	case config.RelabelKeepEqual:
+		if string(labels[model.LabelName(cfg.TargetLabel)]) != val {
+			return nil
+		}
+	case config.RelabelDropEqual:
+		if string(labels[model.LabelName(cfg.TargetLabel)]) == val {
+			return nil
+		}
 	case config.RelabelHashMod:
 		mod := sum64(md5.Sum([]byte(val))) % cfg.Modulus
 		labels[model.LabelName(cfg.TargetLabel)] = model.LabelValue(fmt.Sprintf("%d", mod))
+	case config.RelabelLowercase:
+		labels[model.LabelName(cfg.TargetLabel)] = model.LabelValue(strings.ToLower(val))
+	case config.RelabelUppercase:
+		labels[model.LabelName(cfg.TargetLabel)] = model.LabelValue(strings.ToUpper(val))
 	case config.RelabelLabelMap:
 		out := make(model.LabelSet, len(labels))
 		// Take a copy to avoid infinite loops.
@@ -81,8 +93,12 @@ func relabel(labels model.LabelSet, cfg *config.RelabelConfig) model.LabelSet {
 		}
 		for ln, lv := range labels {
 			if cfg.Regex.MatchString(string(ln)) {
-				res := cfg.Regex.ReplaceAllString(string(ln), cfg.Replacement)
-				out[model.LabelName(res)] = lv
+				res := model.LabelName(cfg.Regex.ReplaceAllString(string(ln), cfg.Replacement))
+				// The replacement template may expand to an invalid label name.
+				if !res.IsValid() {
+					continue
+				}
+				out[res] = lv
 			}
 		}
 		labels = out
@@ -410,6 +410,110 @@ func TestRelabel(t *testing.T) {
 				"a": "foo",
 			},
 		},
+		{
+			input: model.LabelSet{
+				"a": "Foo",
+				"b": "BAR",
+			},
+			relabel: []*config.RelabelConfig{
+				{
+					SourceLabels: model.LabelNames{"a", "b"},
+					Separator:    "-",
+					Action:       config.RelabelLowercase,
+					TargetLabel:  "c",
+				},
+				{
+					SourceLabels: model.LabelNames{"a"},
+					Action:       config.RelabelUppercase,
+					TargetLabel:  "a",
+				},
+			},
+			output: model.LabelSet{
+				"a": "FOO",
+				"b": "BAR",
+				"c": "foo-bar",
+			},
+		},
+		{
+			input: model.LabelSet{
+				"a": "foo",
+				"b": "foo",
+			},
+			relabel: []*config.RelabelConfig{
+				{
+					SourceLabels: model.LabelNames{"a"},
+					Action:       config.RelabelKeepEqual,
+					TargetLabel:  "b",
+				},
+			},
+			output: model.LabelSet{
+				"a": "foo",
+				"b": "foo",
+			},
+		},
+		{
+			input: model.LabelSet{
+				"a": "foo",
+				"b": "bar",
+			},
+			relabel: []*config.RelabelConfig{
+				{
+					SourceLabels: model.LabelNames{"a"},
+					Action:       config.RelabelKeepEqual,
+					TargetLabel:  "b",
+				},
+			},
+			output: nil,
+		},
+		{
+			input: model.LabelSet{
+				"a": "foo",
+				"b": "foo",
+			},
+			relabel: []*config.RelabelConfig{
+				{
+					SourceLabels: model.LabelNames{"a"},
+					Action:       config.RelabelDropEqual,
+					TargetLabel:  "b",
+				},
+			},
+			output: nil,
+		},
+		{
+			input: model.LabelSet{
+				"a": "foo",
+				"b": "bar",
+			},
+			relabel: []*config.RelabelConfig{
+				{
+					SourceLabels: model.LabelNames{"a"},
+					Action:       config.RelabelDropEqual,
+					TargetLabel:  "b",
+				},
+			},
+			output: model.LabelSet{
+				"a": "foo",
+				"b": "bar",
+			},
+		},
+		{ // labelmap skips invalid label names
+			input: model.LabelSet{
+				"__meta_x_a":   "foo",
+				"__meta_x_b-c": "bar",
+			},
+			relabel: []*config.RelabelConfig{
+				{
+					Regex:       config.MustNewRegexp("__meta_x_(.+)"),
+					Replacement: "x_${1}",
+					Action:      config.RelabelLabelMap,
+				},
+			},
+			output: model.LabelSet{
+				"__meta_x_a":   "foo",
+				"__meta_x_b-c": "bar",
+				"x_a":          "foo",
+			},
+		},
 	}
 
 	for i, test := range tests {
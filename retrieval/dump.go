@@ -0,0 +1,259 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrieval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/timestamp"
+)
+
+const (
+	scrapeDumpPrefix = "scrapes-"
+	scrapeDumpSuffix = ".json"
+)
+
+// ScrapeRecord is a scrape as recorded by a ScrapeDumper.
+type ScrapeRecord struct {
+	// The labels of the scraped target.
+	Labels labels.Labels `json:"labels"`
+	// The start of the scrape in milliseconds since the epoch.
+	Timestamp   int64  `json:"timestamp"`
+	ContentType string `json:"contentType"`
+	Body        []byte `json:"body"`
+}
+
+// ScrapeDumper records the raw bodies of scrapes to an archive of files in a
+// directory, to reproduce problems with the ingestion of the samples later on.
+// The scrapes are written as a stream of JSON objects. Once a file exceeds the
+// maximum size a new one is started and the oldest files beyond the maximum
+// number of files are deleted.
+type ScrapeDumper struct {
+	dir         string
+	maxFileSize int64
+	maxFiles    int
+	logger      log.Logger
+
+	mtx  sync.Mutex
+	f    *os.File
+	size int64
+}
+
+// NewScrapeDumper returns a ScrapeDumper writing to files in dir, which is
+// created if it does not exist.
+func NewScrapeDumper(dir string, maxFileSize int64, maxFiles int, logger log.Logger) (*ScrapeDumper, error) {
+	if logger == nil {
+		logger = log.NewNopLogger()
+	}
+	if maxFiles < 1 {
+		return nil, fmt.Errorf("at least one scrape dump file must be kept")
+	}
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return nil, err
+	}
+	return &ScrapeDumper{
+		dir:         dir,
+		maxFileSize: maxFileSize,
+		maxFiles:    maxFiles,
+		logger:      logger,
+	}, nil
+}
+
+// Dump records a scrape of the target with the given labels. Failures are
+// logged as the scrape itself is not affected by them.
+func (d *ScrapeDumper) Dump(lset labels.Labels, ts time.Time, contentType string, body []byte) {
+	b, err := json.Marshal(&ScrapeRecord{
+		Labels:      lset,
+		Timestamp:   timestamp.FromTime(ts),
+		ContentType: contentType,
+		Body:        body,
+	})
+	if err != nil {
+		level.Warn(d.logger).Log("msg", "Encoding scrape for dump failed", "err", err)
+		return
+	}
+	b = append(b, '\n')
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if d.f == nil || (d.maxFileSize > 0 && d.size >= d.maxFileSize) {
+		if err := d.rotate(); err != nil {
+			level.Warn(d.logger).Log("msg", "Rotating scrape dump failed", "err", err)
+			return
+		}
+	}
+	n, err := d.f.Write(b)
+	d.size += int64(n)
+	if err != nil {
+		level.Warn(d.logger).Log("msg", "Writing scrape dump failed", "err", err)
+	}
+}
+
+// rotate starts a new file and deletes the oldest files exceeding the maximum
+// number of files.
+func (d *ScrapeDumper) rotate() error {
+	if d.f != nil {
+		if err := d.f.Close(); err != nil {
+			return err
+		}
+		d.f = nil
+	}
+	// Zero padding makes the files sort by their creation time.
+	name := fmt.Sprintf("%s%020d%s", scrapeDumpPrefix, time.Now().UnixNano(), scrapeDumpSuffix)
+	f, err := os.Create(filepath.Join(d.dir, name))
+	if err != nil {
+		return err
+	}
+	d.f, d.size = f, 0
+
+	files, err := ScrapeDumpFiles(d.dir)
+	if err != nil {
+		return err
+	}
+	for len(files) > d.maxFiles {
+		if err := os.Remove(files[0]); err != nil {
+			return err
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+// Close closes the current file of the dumper.
+func (d *ScrapeDumper) Close() error {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if d.f == nil {
+		return nil
+	}
+	err := d.f.Close()
+	d.f = nil
+	return err
+}
+
+// ScrapeDumpFiles returns the files written by a ScrapeDumper to the
+// directory, oldest first.
+func ScrapeDumpFiles(dir string) ([]string, error) {
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, fi := range fis {
+		if fi.IsDir() || !strings.HasPrefix(fi.Name(), scrapeDumpPrefix) || !strings.HasSuffix(fi.Name(), scrapeDumpSuffix) {
+			continue
+		}
+		files = append(files, filepath.Join(dir, fi.Name()))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// ReadScrapeDump calls f for each scrape recorded in r, in order. It stops at
+// the first error returned by f.
+func ReadScrapeDump(r io.Reader, f func(*ScrapeRecord) error) error {
+	dec := json.NewDecoder(r)
+	for {
+		var rec ScrapeRecord
+		if err := dec.Decode(&rec); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := f(&rec); err != nil {
+			return err
+		}
+	}
+}
+
+// ScrapeReplayer feeds recorded scrapes through the parser and appender of
+// scrape loops again, as if the targets were scraped at the recorded times.
+// Report samples like 'up' are not appended.
+type ScrapeReplayer struct {
+	app    Appendable
+	logger log.Logger
+	ctx    context.Context
+
+	configs map[string]*config.ScrapeConfig
+	pools   map[string]*scrapePool
+	loops   map[uint64]*scrapeLoop
+}
+
+// NewScrapeReplayer returns a ScrapeReplayer appending to app. The scrapes of
+// each job are processed according to its configuration in cfgs, like its
+// metric relabeling, or the default configuration if there is none.
+func NewScrapeReplayer(app Appendable, cfgs []*config.ScrapeConfig, logger log.Logger) *ScrapeReplayer {
+	if logger == nil {
+		logger = log.NewNopLogger()
+	}
+	r := &ScrapeReplayer{
+		app:     app,
+		logger:  logger,
+		ctx:     context.Background(),
+		configs: make(map[string]*config.ScrapeConfig, len(cfgs)),
+		pools:   map[string]*scrapePool{},
+		loops:   map[uint64]*scrapeLoop{},
+	}
+	for _, cfg := range cfgs {
+		r.configs[cfg.JobName] = cfg
+	}
+	return r
+}
+
+// Replay appends the samples of a recorded scrape. It returns the number of
+// samples in the scrape and the number of samples appended.
+func (r *ScrapeReplayer) Replay(rec *ScrapeRecord) (total, added int, err error) {
+	job := rec.Labels.Get(model.JobLabel)
+
+	sp, ok := r.pools[job]
+	if !ok {
+		cfg, ok := r.configs[job]
+		if !ok {
+			c := config.DefaultScrapeConfig
+			c.JobName = job
+			cfg = &c
+		}
+		sp = newScrapePool(r.ctx, cfg, r.app, log.With(r.logger, "scrape_pool", job))
+		r.pools[job] = sp
+	}
+
+	hash := rec.Labels.Hash()
+	sl, ok := r.loops[hash]
+	if !ok {
+		t := NewTarget(rec.Labels, nil, nil)
+		sl = sp.newLoop(t, nil, nil).(*scrapeLoop)
+		r.loops[hash] = sl
+	}
+
+	total, added, _, err = sl.append(rec.Body, rec.ContentType, timestamp.Time(rec.Timestamp))
+	return total, added, err
+}
@@ -0,0 +1,142 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrieval
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/storage"
+)
+
+type collectResultAppendable struct {
+	*collectResultAppender
+}
+
+func (a collectResultAppendable) Appender() (storage.Appender, error) {
+	return a.collectResultAppender, nil
+}
+
+func TestScrapeDumper(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scrape_dump")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Every scrape exceeds the file size and starts a new file.
+	d, err := NewScrapeDumper(dir, 1, 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := labels.FromStrings("job", "node", "instance", "a:9100")
+	for i := 0; i < 3; i++ {
+		d.Dump(target, time.Unix(int64(i), 0), "text/plain", []byte("metric 1\n"))
+		// The files are named by their creation time.
+		time.Sleep(time.Millisecond)
+	}
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := ScrapeDumpFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 dump files, got %v", files)
+	}
+
+	// Only the last two scrapes are left.
+	var records []*ScrapeRecord
+	for _, fn := range files {
+		f, err := os.Open(fn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = ReadScrapeDump(f, func(rec *ScrapeRecord) error {
+			records = append(records, rec)
+			return nil
+		})
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := []*ScrapeRecord{
+		{Labels: target, Timestamp: 1000, ContentType: "text/plain", Body: []byte("metric 1\n")},
+		{Labels: target, Timestamp: 2000, ContentType: "text/plain", Body: []byte("metric 1\n")},
+	}
+	if !reflect.DeepEqual(records, expected) {
+		t.Fatalf("expected records %v, got %v", expected, records)
+	}
+}
+
+func TestScrapeReplayer(t *testing.T) {
+	app := &collectResultAppender{}
+	r := NewScrapeReplayer(collectResultAppendable{app}, []*config.ScrapeConfig{{
+		JobName:         "node",
+		HonorTimestamps: true,
+		MetricRelabelConfigs: []*config.RelabelConfig{{
+			SourceLabels: []model.LabelName{"__name__"},
+			Regex:        config.MustNewRegexp("dropped"),
+			Action:       config.RelabelDrop,
+		}},
+	}}, nil)
+
+	total, added, err := r.Replay(&ScrapeRecord{
+		Labels:    labels.FromStrings("job", "node", "instance", "a:9100"),
+		Timestamp: 1000,
+		Body:      []byte("metric{a=\"1\"} 1\ndropped 2\nmetric{a=\"2\"} 3 500\n"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 3 || added != 2 {
+		t.Fatalf("expected 2 of 3 samples to be added, got %d of %d", added, total)
+	}
+
+	// Scrapes of unknown jobs use the default configuration.
+	if _, _, err := r.Replay(&ScrapeRecord{
+		Labels:    labels.FromStrings("job", "other", "instance", "b:9100"),
+		Timestamp: 2000,
+		Body:      []byte("metric 4\n"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []sample{
+		{metric: labels.FromStrings("__name__", "metric", "a", "1", "instance", "a:9100", "job", "node"), t: 1000, v: 1},
+		{metric: labels.FromStrings("__name__", "metric", "a", "2", "instance", "a:9100", "job", "node"), t: 500, v: 3},
+		{metric: labels.FromStrings("__name__", "metric", "instance", "b:9100", "job", "other"), t: 2000, v: 4},
+	}
+	if !reflect.DeepEqual(app.result, expected) {
+		t.Fatalf("expected samples %v, got %v", expected, app.result)
+	}
+
+	// Parse errors are reproduced.
+	if _, _, err := r.Replay(&ScrapeRecord{
+		Labels: labels.FromStrings("job", "node", "instance", "a:9100"),
+		Body:   []byte("metric{a=} 1\n"),
+	}); err == nil {
+		t.Fatal("expected parse error")
+	}
+}
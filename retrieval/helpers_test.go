@@ -15,6 +15,7 @@ package retrieval
 
 import (
 	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/pkg/exemplar"
 	"github.com/prometheus/prometheus/pkg/labels"
 	"github.com/prometheus/prometheus/storage"
 )
@@ -29,14 +30,17 @@ type nopAppender struct{}
 
 func (a nopAppender) Add(labels.Labels, int64, float64) (uint64, error)   { return 0, nil }
 func (a nopAppender) AddFast(labels.Labels, uint64, int64, float64) error { return nil }
+func (a nopAppender) AddExemplar(labels.Labels, exemplar.Exemplar) error  { return nil }
+func (a nopAppender) AddBatch([]storage.Sample) error                     { return nil }
 func (a nopAppender) Commit() error                                       { return nil }
 func (a nopAppender) Rollback() error                                     { return nil }
 
-// collectResultAppender records all samples that were added through the appender.
+// collectResultAppender records all samples and exemplars that were added through the appender.
 // It can be used as its zero value or be backed by another appender it writes samples through.
 type collectResultAppender struct {
-	next   storage.Appender
-	result []sample
+	next      storage.Appender
+	result    []sample
+	exemplars []exemplar.Exemplar
 }
 
 func (a *collectResultAppender) AddFast(m labels.Labels, ref uint64, t int64, v float64) error {
@@ -67,6 +71,18 @@ func (a *collectResultAppender) Add(m labels.Labels, t int64, v float64) (uint64
 	return a.next.Add(m, t, v)
 }
 
+func (a *collectResultAppender) AddExemplar(m labels.Labels, e exemplar.Exemplar) error {
+	a.exemplars = append(a.exemplars, e)
+	if a.next == nil {
+		return nil
+	}
+	return a.next.AddExemplar(m, e)
+}
+
+func (a *collectResultAppender) AddBatch(samples []storage.Sample) error {
+	return storage.AppendBatch(a, samples)
+}
+
 func (a *collectResultAppender) Commit() error   { return nil }
 func (a *collectResultAppender) Rollback() error { return nil }
 
@@ -0,0 +1,232 @@
+// Copyright 2013 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrieval
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/storage"
+)
+
+// Appendable returns an Appender.
+type Appendable interface {
+	Appender() (storage.Appender, error)
+}
+
+// ScrapeManager maintains a set of scrape pools and synchronizes their targets
+// with the target groups it receives from the discovery manager.
+type ScrapeManager struct {
+	logger log.Logger
+	append Appendable
+	ctx    context.Context
+	cancel func()
+
+	mtx           sync.RWMutex
+	scrapeConfigs map[string]*config.ScrapeConfig
+	// Scrape pools by job name.
+	scrapePools map[string]*scrapePool
+	// The last target groups received by job name. They are applied to pools
+	// created for jobs added by a configuration reload.
+	targetSets map[string][]*config.TargetGroup
+	dumper     *ScrapeDumper
+	shard      Shard
+	stopped    bool
+}
+
+// NewScrapeManager creates a new ScrapeManager.
+func NewScrapeManager(logger log.Logger, app Appendable) *ScrapeManager {
+	if logger == nil {
+		logger = log.NewNopLogger()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &ScrapeManager{
+		logger:        logger,
+		append:        app,
+		ctx:           ctx,
+		cancel:        cancel,
+		scrapeConfigs: map[string]*config.ScrapeConfig{},
+		scrapePools:   map[string]*scrapePool{},
+		targetSets:    map[string][]*config.TargetGroup{},
+	}
+}
+
+// SetScrapeDumper sets the dumper recording the scrapes of all targets.
+// It must be called before the manager is run.
+func (m *ScrapeManager) SetScrapeDumper(d *ScrapeDumper) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.dumper = d
+}
+
+// SetShard restricts the scraped targets of all jobs to those in the shard.
+// It must be called before the manager is run.
+func (m *ScrapeManager) SetShard(s Shard) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.shard = s
+}
+
+// Run receives target group updates and synchronizes the scrape pools with
+// them. It blocks until the manager is stopped.
+func (m *ScrapeManager) Run(tsets <-chan map[string][]*config.TargetGroup) error {
+	level.Info(m.logger).Log("msg", "Starting scrape manager...")
+
+	for {
+		select {
+		case ts := <-tsets:
+			m.sync(ts)
+		case <-m.ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Stop cancels all in-flight scrapes and stops all scrape pools.
+func (m *ScrapeManager) Stop() {
+	level.Info(m.logger).Log("msg", "Stopping scrape manager...")
+
+	// Cancel the base context first so that all in-flight scrapes abort
+	// immediately. Started inserts will be finished before terminating.
+	m.cancel()
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	for name, sp := range m.scrapePools {
+		sp.stop()
+		delete(m.scrapePools, name)
+	}
+	m.stopped = true
+
+	level.Info(m.logger).Log("msg", "Scrape manager stopped")
+}
+
+// ApplyConfig resets the manager's job configurations as defined by the new cfg.
+// Scrape pools of removed jobs are stopped and those of jobs with a changed
+// configuration are reloaded and get their targets rebuilt. Scrape pools of unchanged jobs keep scraping
+// without interruption.
+func (m *ScrapeManager) ApplyConfig(cfg *config.Config) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	c := make(map[string]*config.ScrapeConfig, len(cfg.ScrapeConfigs))
+	for _, scfg := range cfg.ScrapeConfigs {
+		c[scfg.JobName] = scfg
+	}
+	m.scrapeConfigs = c
+
+	for name, sp := range m.scrapePools {
+		scfg, ok := m.scrapeConfigs[name]
+		if !ok {
+			sp.stop()
+			targetScrapeDuration.DeleteLabelValues(name)
+			delete(m.scrapePools, name)
+			delete(m.targetSets, name)
+			continue
+		}
+		if !reflect.DeepEqual(sp.config, scfg) {
+			sp.reload(scfg)
+			// The discovery manager does not send the target groups of
+			// unchanged service discovery configurations again, so the
+			// targets are rebuilt from the last ones for changes like
+			// relabeling to take effect.
+			if tgs, ok := m.targetSets[name]; ok {
+				sp.Sync(tgs)
+			}
+		}
+	}
+	// Jobs which were added get their pools once their targets are known.
+	for name, tgs := range m.targetSets {
+		if _, ok := m.scrapePools[name]; !ok {
+			m.syncPool(name, tgs)
+		}
+	}
+	return nil
+}
+
+// sync synchronizes the scrape pools with the given target groups by job name.
+// Only targets which were added or removed start or stop scraping.
+func (m *ScrapeManager) sync(tsets map[string][]*config.TargetGroup) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	for name, tgs := range tsets {
+		m.targetSets[name] = tgs
+		m.syncPool(name, tgs)
+	}
+}
+
+// syncPool synchronizes the scrape pool of the job with the target groups,
+// creating the pool if necessary. It must be called with the manager's lock held.
+func (m *ScrapeManager) syncPool(name string, tgs []*config.TargetGroup) {
+	if m.stopped {
+		return
+	}
+	scfg, ok := m.scrapeConfigs[name]
+	if !ok {
+		level.Debug(m.logger).Log("msg", "Ignoring target groups of unknown job", "job", name)
+		return
+	}
+	sp, ok := m.scrapePools[name]
+	if !ok {
+		sp = newScrapePool(m.ctx, scfg, m.append, log.With(m.logger, "scrape_pool", name))
+		sp.dumper = m.dumper
+		sp.shard = m.shard
+		m.scrapePools[name] = sp
+	}
+	sp.Sync(tgs)
+}
+
+// Targets returns the targets currently being scraped.
+func (m *ScrapeManager) Targets() []*Target {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	targets := []*Target{}
+	for _, sp := range m.scrapePools {
+		sp.mtx.RLock()
+
+		for _, t := range sp.targets {
+			targets = append(targets, t)
+		}
+
+		sp.mtx.RUnlock()
+	}
+
+	return targets
+}
+
+// DroppedTargets returns the targets dropped during relabeling.
+func (m *ScrapeManager) DroppedTargets() []*Target {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	targets := []*Target{}
+	for _, sp := range m.scrapePools {
+		sp.mtx.RLock()
+		targets = append(targets, sp.droppedTargets...)
+		sp.mtx.RUnlock()
+	}
+
+	return targets
+}
@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/config"
@@ -196,6 +197,33 @@ func TestPopulateLabels(t *testing.T) {
 				"custom":               "host:1234",
 			}),
 		},
+		// Target dropped during relabelling.
+		{
+			in: labels.FromMap(map[string]string{
+				model.AddressLabel: "1.2.3.4:1000",
+				"custom":           "drop",
+			}),
+			cfg: &config.ScrapeConfig{
+				Scheme:      "https",
+				MetricsPath: "/metrics",
+				JobName:     "job",
+				RelabelConfigs: []*config.RelabelConfig{
+					{
+						Action:       config.RelabelDrop,
+						Regex:        mustNewRegexp("drop"),
+						SourceLabels: model.LabelNames{"custom"},
+					},
+				},
+			},
+			res: nil,
+			resOrig: labels.FromMap(map[string]string{
+				model.AddressLabel:     "1.2.3.4:1000",
+				model.SchemeLabel:      "https",
+				model.MetricsPathLabel: "/metrics",
+				model.JobLabel:         "job",
+				"custom":               "drop",
+			}),
+		},
 		// Invalid UTF-8 in label.
 		{
 			in: labels.FromMap(map[string]string{
@@ -215,7 +243,7 @@ func TestPopulateLabels(t *testing.T) {
 	for i, c := range cases {
 		in := c.in.Copy()
 
-		res, orig, err := populateLabels(c.in, c.cfg)
+		res, orig, _, err := populateLabels(c.in, c.cfg)
 		if !reflect.DeepEqual(err, c.err) {
 			t.Fatalf("case %d: wanted %v error, got %v", i, c.err, err)
 		}
@@ -230,3 +258,202 @@ func TestPopulateLabels(t *testing.T) {
 		}
 	}
 }
+
+func mustLoadConfig(t *testing.T, s string) *config.Config {
+	cfg, err := config.Load(s)
+	if err != nil {
+		t.Fatalf("Unable to load config: %s", err)
+	}
+	return cfg
+}
+
+func TestScrapeManagerApplyConfig(t *testing.T) {
+	const cfgText = `
+scrape_configs:
+ - job_name: job1
+   relabel_configs:
+   - source_labels: [__address__]
+     regex: 'foo:.*'
+     action: keep
+`
+	m := NewScrapeManager(nil, &nopAppendable{})
+	defer m.Stop()
+
+	if err := m.ApplyConfig(mustLoadConfig(t, cfgText)); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := false
+	noopLoop := func() loop {
+		return &testLoop{
+			startFunc: func(interval, timeout time.Duration, errc chan<- error) {},
+			stopFunc:  func() {},
+		}
+	}
+	m.scrapePools["job1"] = &scrapePool{
+		config:  m.scrapeConfigs["job1"],
+		targets: map[uint64]*Target{1: {}},
+		loops:   map[uint64]loop{1: noopLoop()},
+		newLoop: func(*Target, scraper, *scrapeCache) loop {
+			reloaded = true
+			return noopLoop()
+		},
+	}
+
+	// An unchanged configuration, loaded anew, must not restart scraping.
+	if err := m.ApplyConfig(mustLoadConfig(t, cfgText)); err != nil {
+		t.Fatal(err)
+	}
+	if reloaded {
+		t.Fatalf("Scrape pool with unchanged configuration was reloaded")
+	}
+
+	if err := m.ApplyConfig(mustLoadConfig(t, cfgText+"   scrape_interval: 10s\n")); err != nil {
+		t.Fatal(err)
+	}
+	if !reloaded {
+		t.Fatalf("Scrape pool with changed configuration was not reloaded")
+	}
+
+	if err := m.ApplyConfig(mustLoadConfig(t, "")); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m.scrapePools["job1"]; ok {
+		t.Fatalf("Scrape pool of removed job was not stopped")
+	}
+}
+
+func TestScrapeManagerApplyConfigRelabeling(t *testing.T) {
+	cfgText := func(value string) string {
+		return `
+scrape_configs:
+ - job_name: job1
+   relabel_configs:
+   - target_label: foo
+     replacement: ` + value + `
+`
+	}
+	m := NewScrapeManager(nil, &nopAppendable{})
+	defer m.Stop()
+
+	if err := m.ApplyConfig(mustLoadConfig(t, cfgText("bar"))); err != nil {
+		t.Fatal(err)
+	}
+	m.sync(map[string][]*config.TargetGroup{
+		"job1": {{Source: "0", Targets: []model.LabelSet{{model.AddressLabel: "localhost:9100"}}}},
+	})
+	if targets := m.Targets(); len(targets) != 1 || targets[0].Labels().Get("foo") != "bar" {
+		t.Fatalf("Expected one target with foo=bar but got %v", targets)
+	}
+
+	// Changed relabeling applies to the known targets without new target
+	// groups from the discovery.
+	if err := m.ApplyConfig(mustLoadConfig(t, cfgText("baz"))); err != nil {
+		t.Fatal(err)
+	}
+	if targets := m.Targets(); len(targets) != 1 || targets[0].Labels().Get("foo") != "baz" {
+		t.Fatalf("Expected one target with foo=baz but got %v", targets)
+	}
+}
+
+func TestScrapeManagerSync(t *testing.T) {
+	m := NewScrapeManager(nil, &nopAppendable{})
+	defer m.Stop()
+
+	if err := m.ApplyConfig(mustLoadConfig(t, `
+scrape_configs:
+ - job_name: job1
+`)); err != nil {
+		t.Fatal(err)
+	}
+
+	tgs := func(targets ...string) []*config.TargetGroup {
+		tg := &config.TargetGroup{Source: "0"}
+		for _, t := range targets {
+			tg.Targets = append(tg.Targets, model.LabelSet{model.AddressLabel: model.LabelValue(t)})
+		}
+		return []*config.TargetGroup{tg}
+	}
+	m.sync(map[string][]*config.TargetGroup{
+		"job1": tgs("localhost:9100"),
+		"job2": tgs("localhost:9101", "localhost:9102"),
+	})
+
+	if len(m.scrapePools) != 1 || len(m.Targets()) != 1 {
+		t.Fatalf("Expected one scrape pool with one target but got %d pools and %d targets", len(m.scrapePools), len(m.Targets()))
+	}
+	loop := m.scrapePools["job1"].loops[m.Targets()[0].hash()]
+
+	// Adding a target must leave the scrape loops of existing targets running.
+	m.sync(map[string][]*config.TargetGroup{
+		"job1": tgs("localhost:9100", "localhost:9103"),
+	})
+	sp := m.scrapePools["job1"]
+	if len(sp.targets) != 2 {
+		t.Fatalf("Expected 2 targets but got %d", len(sp.targets))
+	}
+	found := false
+	for _, l := range sp.loops {
+		if l == loop {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Scrape loop of unchanged target was restarted")
+	}
+
+	// Target groups of jobs not configured yet are applied once they are.
+	if err := m.ApplyConfig(mustLoadConfig(t, `
+scrape_configs:
+ - job_name: job1
+ - job_name: job2
+`)); err != nil {
+		t.Fatal(err)
+	}
+	if sp, ok := m.scrapePools["job2"]; !ok || len(sp.targets) != 2 {
+		t.Fatalf("Expected scrape pool for job2 with 2 targets")
+	}
+}
+
+func TestScrapeManagerDroppedTargets(t *testing.T) {
+	m := NewScrapeManager(nil, &nopAppendable{})
+	defer m.Stop()
+
+	if err := m.ApplyConfig(mustLoadConfig(t, `
+scrape_configs:
+ - job_name: job1
+   relabel_configs:
+   - source_labels: [__address__]
+     regex: 'localhost:9102'
+     action: drop
+   - source_labels: [__address__]
+     regex: 'localhost:9101'
+     action: drop
+`)); err != nil {
+		t.Fatal(err)
+	}
+
+	m.sync(map[string][]*config.TargetGroup{
+		"job1": {{
+			Source: "0",
+			Targets: []model.LabelSet{
+				{model.AddressLabel: "localhost:9100"},
+				{model.AddressLabel: "localhost:9101"},
+			},
+		}},
+	})
+
+	if len(m.Targets()) != 1 {
+		t.Fatalf("Expected one active target but got %d", len(m.Targets()))
+	}
+	dropped := m.DroppedTargets()
+	if len(dropped) != 1 {
+		t.Fatalf("Expected one dropped target but got %d", len(dropped))
+	}
+	if addr := dropped[0].DiscoveredLabels().Get(model.AddressLabel); addr != "localhost:9101" {
+		t.Fatalf("Expected dropped target localhost:9101 but got %q", addr)
+	}
+	if i, rc := dropped[0].DroppedBy(); i != 1 || rc.Regex.String() != "^(?:localhost:9101)$" {
+		t.Fatalf("Expected target to be dropped by relabel config 1 but got %d", i)
+	}
+}
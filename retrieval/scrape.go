@@ -22,6 +22,7 @@ import (
 	"io"
 	"math"
 	"net/http"
+	"reflect"
 	"sync"
 	"time"
 	"unsafe"
@@ -34,6 +35,7 @@ import (
 	"golang.org/x/net/context/ctxhttp"
 
 	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/pkg/exemplar"
 	"github.com/prometheus/prometheus/pkg/labels"
 	"github.com/prometheus/prometheus/pkg/pool"
 	"github.com/prometheus/prometheus/pkg/relabel"
@@ -44,7 +46,12 @@ import (
 	"github.com/prometheus/prometheus/util/httputil"
 )
 
+// DefaultScrapeDurationBuckets are the buckets of the scrape duration
+// histogram unless set otherwise with SetScrapeDurationBuckets.
+var DefaultScrapeDurationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60}
+
 var (
+	targetScrapeDuration = newTargetScrapeDuration(DefaultScrapeDurationBuckets)
 	targetIntervalLength = prometheus.NewSummaryVec(
 		prometheus.SummaryOpts{
 			Name:       "prometheus_target_interval_length_seconds",
@@ -82,6 +89,12 @@ var (
 			Help: "Total number of scrapes that hit the sample limit and were rejected.",
 		},
 	)
+	targetScrapeLabelLimit = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "prometheus_target_scrapes_exceeded_label_limits_total",
+			Help: "Total number of scrapes that hit the label limits and were rejected.",
+		},
+	)
 	targetScrapeSampleDuplicate = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Name: "prometheus_target_scrapes_sample_duplicate_timestamp_total",
@@ -103,16 +116,38 @@ var (
 )
 
 func init() {
+	prometheus.MustRegister(targetScrapeDuration)
 	prometheus.MustRegister(targetIntervalLength)
 	prometheus.MustRegister(targetReloadIntervalLength)
 	prometheus.MustRegister(targetSyncIntervalLength)
 	prometheus.MustRegister(targetScrapePoolSyncsCounter)
 	prometheus.MustRegister(targetScrapeSampleLimit)
+	prometheus.MustRegister(targetScrapeLabelLimit)
 	prometheus.MustRegister(targetScrapeSampleDuplicate)
 	prometheus.MustRegister(targetScrapeSampleOutOfOrder)
 	prometheus.MustRegister(targetScrapeSampleOutOfBounds)
 }
 
+func newTargetScrapeDuration(buckets []float64) *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "prometheus_target_scrape_duration_seconds",
+			Help:    "Duration of scrapes, including appending the scraped samples.",
+			Buckets: buckets,
+		},
+		[]string{"scrape_job"},
+	)
+}
+
+// SetScrapeDurationBuckets replaces the histogram of scrape durations with
+// one using the given buckets. It must be called before any target is
+// scraped.
+func SetScrapeDurationBuckets(buckets []float64) {
+	prometheus.Unregister(targetScrapeDuration)
+	targetScrapeDuration = newTargetScrapeDuration(buckets)
+	prometheus.MustRegister(targetScrapeDuration)
+}
+
 // scrapePool manages scrapes for sets of targets.
 type scrapePool struct {
 	appendable Appendable
@@ -126,9 +161,16 @@ type scrapePool struct {
 	// set of hashes.
 	targets map[uint64]*Target
 	loops   map[uint64]loop
+	// Targets dropped during relabeling in the last sync.
+	droppedTargets []*Target
+	// Records the bodies of the scrapes if set.
+	dumper *ScrapeDumper
+	// Only targets in the shard are scraped.
+	shard Shard
 
 	// Constructor for new scrape loops. This is settable for testing convenience.
-	newLoop func(*Target, scraper) loop
+	// A nil cache creates a new one.
+	newLoop func(*Target, scraper, *scrapeCache) loop
 }
 
 const maxAheadTime = 10 * time.Minute
@@ -157,14 +199,29 @@ func newScrapePool(ctx context.Context, cfg *config.ScrapeConfig, app Appendable
 		loops:      map[uint64]loop{},
 		logger:     logger,
 	}
-	sp.newLoop = func(t *Target, s scraper) loop {
-		return newScrapeLoop(sp.ctx, s,
+	sp.newLoop = func(t *Target, s scraper, cache *scrapeCache) loop {
+		l := newScrapeLoop(sp.ctx, s,
 			log.With(logger, "target", t),
 			buffers,
 			func(l labels.Labels) labels.Labels { return sp.mutateSampleLabels(l, t) },
 			func(l labels.Labels) labels.Labels { return sp.mutateReportSampleLabels(l, t) },
 			sp.appender,
+			cache,
+			&labelLimits{
+				labelLimit:            int(sp.config.LabelLimit),
+				labelNameLengthLimit:  int(sp.config.LabelNameLengthLimit),
+				labelValueLengthLimit: int(sp.config.LabelValueLengthLimit),
+			},
+			sp.config.HonorTimestamps,
 		)
+		l.duration = targetScrapeDuration.WithLabelValues(sp.config.JobName)
+		if d := sp.dumper; d != nil {
+			l.dump = func(b []byte, contentType string, ts time.Time) {
+				d.Dump(t.Labels(), ts, contentType, b)
+			}
+		}
+		t.SetMetadataStore(l.cache)
+		return l
 	}
 
 	return sp
@@ -206,6 +263,7 @@ func (sp *scrapePool) reload(cfg *config.ScrapeConfig) {
 		// Any errors that could occur here should be caught during config validation.
 		level.Error(sp.logger).Log("msg", "Error creating HTTP client", "err", err)
 	}
+	reuseCache := reusableCache(sp.config, cfg)
 	sp.config = cfg
 	sp.client = client
 
@@ -216,10 +274,17 @@ func (sp *scrapePool) reload(cfg *config.ScrapeConfig) {
 	)
 
 	for fp, oldLoop := range sp.loops {
+		var cache *scrapeCache
+		if reuseCache {
+			// The new loop continues the series of the old one, which must
+			// thus not mark them stale nor touch the cache after it stopped.
+			oldLoop.disableEndOfRunStalenessMarkers()
+			cache = oldLoop.getCache()
+		}
 		var (
 			t       = sp.targets[fp]
-			s       = &targetScraper{Target: t, client: sp.client, timeout: timeout}
-			newLoop = sp.newLoop(t, s)
+			s       = &targetScraper{Target: t, client: sp.client, timeout: timeout, jitter: cfg.ScrapeJitter, format: cfg.ScrapeFormat}
+			newLoop = sp.newLoop(t, s, cache)
 		)
 		wg.Add(1)
 
@@ -239,20 +304,55 @@ func (sp *scrapePool) reload(cfg *config.ScrapeConfig) {
 	)
 }
 
+// reusableCache returns whether the scrape caches of the loops of a pool can be
+// kept when reloading it from configuration r to l. The cached label sets
+// do not depend on how and how often targets are scraped.
+func reusableCache(r, l *config.ScrapeConfig) bool {
+	if r == nil || l == nil {
+		return false
+	}
+	return reflect.DeepEqual(zeroCacheIrrelevant(r), zeroCacheIrrelevant(l))
+}
+
+// zeroCacheIrrelevant returns a copy of the configuration with the fields not
+// affecting the scrape cache zeroed.
+func zeroCacheIrrelevant(c *config.ScrapeConfig) *config.ScrapeConfig {
+	z := *c
+	z.ScrapeInterval = 0
+	z.ScrapeTimeout = 0
+	z.ScrapeJitter = false
+	z.SampleLimit = 0
+	z.HTTPClientConfig = config.HTTPClientConfig{}
+	return &z
+}
+
 // Sync converts target groups into actual scrape targets and synchronizes
 // the currently running scraper with the resulting set.
 func (sp *scrapePool) Sync(tgs []*config.TargetGroup) {
 	start := time.Now()
 
-	var all []*Target
+	var all, dropped []*Target
 	for _, tg := range tgs {
-		targets, err := targetsFromGroup(tg, sp.config)
+		targets, droppedTargets, err := targetsFromGroup(tg, sp.config)
 		if err != nil {
 			level.Error(sp.logger).Log("msg", "creating targets failed", "err", err)
 			continue
 		}
-		all = append(all, targets...)
+		for _, t := range targets {
+			// Targets of other shards are dropped like by a hashmod
+			// relabeling.
+			if sp.shard.contains(t) {
+				all = append(all, t)
+			} else {
+				dropped = append(dropped, t)
+			}
+		}
+		dropped = append(dropped, droppedTargets...)
 	}
+	sp.mtx.Lock()
+	sp.droppedTargets = dropped
+	sp.mtx.Unlock()
+
 	sp.sync(all)
 
 	targetSyncIntervalLength.WithLabelValues(sp.config.JobName).Observe(
@@ -280,8 +380,8 @@ func (sp *scrapePool) sync(targets []*Target) {
 		uniqueTargets[hash] = struct{}{}
 
 		if _, ok := sp.targets[hash]; !ok {
-			s := &targetScraper{Target: t, client: sp.client, timeout: timeout}
-			l := sp.newLoop(t, s)
+			s := &targetScraper{Target: t, client: sp.client, timeout: timeout, jitter: sp.config.ScrapeJitter, format: sp.config.ScrapeFormat}
+			l := sp.newLoop(t, s, nil)
 
 			sp.targets[hash] = t
 			sp.loops[hash] = l
@@ -379,7 +479,7 @@ func (sp *scrapePool) appender() storage.Appender {
 
 // A scraper retrieves samples and accepts a status report at the end.
 type scraper interface {
-	scrape(ctx context.Context, w io.Writer) error
+	scrape(ctx context.Context, w io.Writer) (string, error)
 	report(start time.Time, dur time.Duration, err error)
 	offset(interval time.Duration) time.Duration
 }
@@ -391,22 +491,48 @@ type targetScraper struct {
 	client  *http.Client
 	req     *http.Request
 	timeout time.Duration
+	// Whether the scrapes of the target are spread across the interval.
+	jitter bool
+	// The exposition format requested from the target.
+	format config.ScrapeFormat
 
 	gzipr *gzip.Reader
 	buf   *bufio.Reader
 }
 
-const acceptHeader = `text/plain;version=0.0.4;q=1,*/*;q=0.1`
+// offset returns the time until the next scrape cycle for the target. Without
+// jitter, scrapes start at multiples of the interval.
+func (s *targetScraper) offset(interval time.Duration) time.Duration {
+	if s.jitter {
+		return s.Target.offset(interval)
+	}
+	return interval - time.Duration(time.Now().UnixNano()%int64(interval))
+}
+
+const (
+	promAcceptHeader        = `text/plain;version=0.0.4;q=1,*/*;q=0.1`
+	openMetricsAcceptHeader = `application/openmetrics-text;version=0.0.1;q=1,text/plain;version=0.0.4;q=0.5,*/*;q=0.1`
+)
+
+// acceptHeader returns the Accept header requesting the given format.
+func acceptHeader(format config.ScrapeFormat) string {
+	if format == config.ScrapeFormatOpenMetrics {
+		return openMetricsAcceptHeader
+	}
+	return promAcceptHeader
+}
 
 var userAgentHeader = fmt.Sprintf("Prometheus/%s", version.Version)
 
-func (s *targetScraper) scrape(ctx context.Context, w io.Writer) error {
+// scrape writes the decompressed response body of the target into w and
+// returns its content type.
+func (s *targetScraper) scrape(ctx context.Context, w io.Writer) (string, error) {
 	if s.req == nil {
 		req, err := http.NewRequest("GET", s.URL().String(), nil)
 		if err != nil {
-			return err
+			return "", err
 		}
-		req.Header.Add("Accept", acceptHeader)
+		req.Header.Add("Accept", acceptHeader(s.format))
 		req.Header.Add("Accept-Encoding", "gzip")
 		req.Header.Set("User-Agent", userAgentHeader)
 		req.Header.Set("X-Prometheus-Scrape-Timeout-Seconds", fmt.Sprintf("%f", s.timeout.Seconds()))
@@ -416,39 +542,45 @@ func (s *targetScraper) scrape(ctx context.Context, w io.Writer) error {
 
 	resp, err := ctxhttp.Do(ctx, s.client, s.req)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("server returned HTTP status %s", resp.Status)
+		return "", fmt.Errorf("server returned HTTP status %s", resp.Status)
 	}
+	contentType := resp.Header.Get("Content-Type")
 
 	if resp.Header.Get("Content-Encoding") != "gzip" {
 		_, err = io.Copy(w, resp.Body)
-		return err
+		return contentType, err
 	}
 
+	// The body is decompressed while it is read.
 	if s.gzipr == nil {
 		s.buf = bufio.NewReader(resp.Body)
 		s.gzipr, err = gzip.NewReader(s.buf)
 		if err != nil {
-			return err
+			return "", err
 		}
 	} else {
 		s.buf.Reset(resp.Body)
-		s.gzipr.Reset(s.buf)
+		if err = s.gzipr.Reset(s.buf); err != nil {
+			return "", err
+		}
 	}
 
 	_, err = io.Copy(w, s.gzipr)
 	s.gzipr.Close()
-	return err
+	return contentType, err
 }
 
 // A loop can run and be stopped again. It must not be reused after it was stopped.
 type loop interface {
 	run(interval, timeout time.Duration, errc chan<- error)
 	stop()
+	getCache() *scrapeCache
+	disableEndOfRunStalenessMarkers()
 }
 
 type cacheEntry struct {
@@ -468,11 +600,23 @@ type scrapeLoop struct {
 	appender            func() storage.Appender
 	sampleMutator       labelsMutator
 	reportSampleMutator labelsMutator
+	labelLimits         *labelLimits
+	honorTimestamps     bool
+	// Observes the scrape durations, may be nil.
+	duration prometheus.Observer
+	// Records the bodies of successful scrapes, may be nil.
+	dump func(b []byte, contentType string, ts time.Time)
 
 	ctx       context.Context
 	scrapeCtx context.Context
 	cancel    func()
 	stopped   chan struct{}
+
+	disabledEndOfRunStalenessMarkers bool
+
+	// The samples of the scrape being appended, reused across scrapes.
+	batch        []storage.Sample
+	batchEntries []batchEntry
 }
 
 // scrapeCache tracks mappings of exposed metric strings to label sets and
@@ -495,6 +639,20 @@ type scrapeCache struct {
 	// We hold two maps and swap them out to save allocations.
 	seriesCur  map[uint64]labels.Labels
 	seriesPrev map[uint64]labels.Labels
+
+	// The metadata of the exposed metric families. It is read concurrently
+	// through the target the cache is registered with.
+	metaMtx  sync.Mutex
+	metadata map[string]*metaEntry
+}
+
+// metaEntry holds the metadata of a metric family and the last iteration
+// in which it was exposed.
+type metaEntry struct {
+	lastIter uint64
+	typ      textparse.MetricType
+	help     string
+	unit     string
 }
 
 func newScrapeCache() *scrapeCache {
@@ -503,6 +661,7 @@ func newScrapeCache() *scrapeCache {
 		dropped:    map[string]*uint64{},
 		seriesCur:  map[uint64]labels.Labels{},
 		seriesPrev: map[uint64]labels.Labels{},
+		metadata:   map[string]*metaEntry{},
 	}
 }
 
@@ -521,6 +680,16 @@ func (c *scrapeCache) iterDone() {
 		}
 	}
 
+	c.metaMtx.Lock()
+	for m, e := range c.metadata {
+		// Keep metadata around for a while to not lose it when a target
+		// briefly fails to expose a metric family.
+		if c.iter-e.lastIter > 10 {
+			delete(c.metadata, m)
+		}
+	}
+	c.metaMtx.Unlock()
+
 	// Swap current and previous series.
 	c.seriesPrev, c.seriesCur = c.seriesCur, c.seriesPrev
 
@@ -575,6 +744,72 @@ func (c *scrapeCache) forEachStale(f func(labels.Labels) bool) {
 	}
 }
 
+// setMetadata updates the cached metadata with the metadata comments read
+// in the current scrape. Strings are only allocated for changed values.
+func (c *scrapeCache) setMetadata(meta []textparse.Metadata) {
+	c.metaMtx.Lock()
+	defer c.metaMtx.Unlock()
+
+	for _, m := range meta {
+		e, ok := c.metadata[yoloString(m.Metric)]
+		if !ok {
+			e = &metaEntry{typ: textparse.MetricTypeUntyped}
+			c.metadata[string(m.Metric)] = e
+		}
+		e.lastIter = c.iter
+
+		switch m.Kind {
+		case textparse.MetadataHelp:
+			if e.help != yoloString(m.Text) {
+				e.help = string(m.Text)
+			}
+		case textparse.MetadataType:
+			if string(e.typ) != yoloString(m.Text) {
+				e.typ = textparse.MetricType(m.Text)
+			}
+		case textparse.MetadataUnit:
+			if e.unit != yoloString(m.Text) {
+				e.unit = string(m.Text)
+			}
+		}
+	}
+}
+
+// GetMetadata returns the metadata of the metric family with the given name.
+func (c *scrapeCache) GetMetadata(metric string) (MetricMetadata, bool) {
+	c.metaMtx.Lock()
+	defer c.metaMtx.Unlock()
+
+	e, ok := c.metadata[metric]
+	if !ok {
+		return MetricMetadata{}, false
+	}
+	return MetricMetadata{
+		Metric: metric,
+		Type:   e.typ,
+		Help:   e.help,
+		Unit:   e.unit,
+	}, true
+}
+
+// ListMetadata returns the metadata of all cached metric families.
+func (c *scrapeCache) ListMetadata() []MetricMetadata {
+	c.metaMtx.Lock()
+	defer c.metaMtx.Unlock()
+
+	res := make([]MetricMetadata, 0, len(c.metadata))
+
+	for m, e := range c.metadata {
+		res = append(res, MetricMetadata{
+			Metric: m,
+			Type:   e.typ,
+			Help:   e.help,
+			Unit:   e.unit,
+		})
+	}
+	return res
+}
+
 func newScrapeLoop(
 	ctx context.Context,
 	sc scraper,
@@ -583,6 +818,9 @@ func newScrapeLoop(
 	sampleMutator labelsMutator,
 	reportSampleMutator labelsMutator,
 	appender func() storage.Appender,
+	cache *scrapeCache,
+	labelLimits *labelLimits,
+	honorTimestamps bool,
 ) *scrapeLoop {
 	if l == nil {
 		l = log.NewNopLogger()
@@ -590,13 +828,18 @@ func newScrapeLoop(
 	if buffers == nil {
 		buffers = pool.NewBytesPool(1e3, 1e6, 3)
 	}
+	if cache == nil {
+		cache = newScrapeCache()
+	}
 	sl := &scrapeLoop{
 		scraper:             sc,
 		buffers:             buffers,
-		cache:               newScrapeCache(),
+		cache:               cache,
 		appender:            appender,
 		sampleMutator:       sampleMutator,
 		reportSampleMutator: reportSampleMutator,
+		labelLimits:         labelLimits,
+		honorTimestamps:     honorTimestamps,
 		stopped:             make(chan struct{}),
 		ctx:                 ctx,
 		l:                   l,
@@ -648,7 +891,7 @@ mainLoop:
 		b := sl.buffers.Get(sl.lastScrapeSize)
 		buf := bytes.NewBuffer(b)
 
-		scrapeErr := sl.scraper.scrape(scrapeCtx, buf)
+		contentType, scrapeErr := sl.scraper.scrape(scrapeCtx, buf)
 		cancel()
 
 		if scrapeErr == nil {
@@ -659,6 +902,9 @@ mainLoop:
 			if len(b) > 0 {
 				sl.lastScrapeSize = len(b)
 			}
+			if sl.dump != nil {
+				sl.dump(b, contentType, start)
+			}
 		} else {
 			level.Debug(sl.l).Log("msg", "Scrape failed", "err", scrapeErr.Error())
 			if errc != nil {
@@ -668,23 +914,31 @@ mainLoop:
 
 		// A failed scrape is the same as an empty scrape,
 		// we still call sl.append to trigger stale markers.
-		total, added, appErr := sl.append(b, start)
+		total, added, seriesAdded, appErr := sl.append(b, contentType, start)
 		if appErr != nil {
 			level.Warn(sl.l).Log("msg", "append failed", "err", appErr)
 			// The append failed, probably due to a parse error or sample limit.
 			// Call sl.append again with an empty scrape to trigger stale markers.
-			if _, _, err := sl.append([]byte{}, start); err != nil {
+			if _, _, _, err := sl.append([]byte{}, "", start); err != nil {
 				level.Warn(sl.l).Log("msg", "append failed", "err", err)
 			}
 		}
 
+		var bodySize int
+		if scrapeErr == nil {
+			bodySize = len(b)
+		}
 		sl.buffers.Put(b)
 
 		if scrapeErr == nil {
 			scrapeErr = appErr
 		}
 
-		sl.report(start, time.Since(start), total, added, scrapeErr)
+		d := time.Since(start)
+		if sl.duration != nil {
+			sl.duration.Observe(d.Seconds())
+		}
+		sl.report(start, d, total, added, seriesAdded, bodySize, scrapeErr)
 		last = start
 
 		select {
@@ -699,7 +953,9 @@ mainLoop:
 
 	close(sl.stopped)
 
-	sl.endOfRunStaleness(last, ticker, interval)
+	if !sl.disabledEndOfRunStalenessMarkers {
+		sl.endOfRunStaleness(last, ticker, interval)
+	}
 }
 
 func (sl *scrapeLoop) endOfRunStaleness(last time.Time, ticker *time.Ticker, interval time.Duration) {
@@ -742,7 +998,7 @@ func (sl *scrapeLoop) endOfRunStaleness(last time.Time, ticker *time.Ticker, int
 	// Call sl.append again with an empty scrape to trigger stale markers.
 	// If the target has since been recreated and scraped, the
 	// stale markers will be out of order and ignored.
-	if _, _, err := sl.append([]byte{}, staleTime); err != nil {
+	if _, _, _, err := sl.append([]byte{}, "", staleTime); err != nil {
 		level.Error(sl.l).Log("msg", "stale append failed", "err", err)
 	}
 	if err := sl.reportStale(staleTime); err != nil {
@@ -757,6 +1013,16 @@ func (sl *scrapeLoop) stop() {
 	<-sl.stopped
 }
 
+func (sl *scrapeLoop) getCache() *scrapeCache {
+	return sl.cache
+}
+
+// disableEndOfRunStalenessMarkers prevents the loop from writing stale markers
+// for its series once stopped. It must be called before the loop is stopped.
+func (sl *scrapeLoop) disableEndOfRunStalenessMarkers() {
+	sl.disabledEndOfRunStalenessMarkers = true
+}
+
 type sample struct {
 	metric labels.Labels
 	t      int64
@@ -778,10 +1044,14 @@ func (s samples) Less(i, j int) bool {
 	return s[i].t < s[j].t
 }
 
-func (sl *scrapeLoop) append(b []byte, ts time.Time) (total, added int, err error) {
+// append appends the samples of the scrape b to the storage and marks series
+// no longer exposed stale. It returns the number of scraped samples, the number
+// of samples appended after relabeling, and the number of series not seen in
+// previous scrapes.
+func (sl *scrapeLoop) append(b []byte, contentType string, ts time.Time) (total, added, seriesAdded int, err error) {
 	var (
 		app            = sl.appender()
-		p              = textparse.New(b)
+		p              = textparse.New(b, contentType)
 		defTime        = timestamp.FromTime(ts)
 		numOutOfOrder  = 0
 		numDuplicates  = 0
@@ -789,12 +1059,26 @@ func (sl *scrapeLoop) append(b []byte, ts time.Time) (total, added int, err erro
 	)
 	var sampleLimitErr error
 
+	// The samples of the scrape are collected first and added in a single
+	// batch afterwards.
+	sl.batch, sl.batchEntries = sl.batch[:0], sl.batchEntries[:0]
+	defer func() {
+		// Do not retain the label sets of the scrape.
+		for i := range sl.batch {
+			sl.batch[i] = storage.Sample{}
+			sl.batchEntries[i] = batchEntry{}
+		}
+	}()
+
 loop:
 	for p.Next() {
 		total++
 
 		t := defTime
 		met, tp, v := p.At()
+		if !sl.honorTimestamps {
+			tp = nil
+		}
 		if tp != nil {
 			t = *tp
 		}
@@ -802,41 +1086,14 @@ loop:
 		if sl.cache.getDropped(yoloString(met)) {
 			continue
 		}
-		ce, ok := sl.cache.get(yoloString(met))
-		if ok {
-			switch err = app.AddFast(ce.lset, ce.ref, t, v); err {
-			case nil:
-				if tp == nil {
-					sl.cache.trackStaleness(ce.hash, ce.lset)
-				}
-			case storage.ErrNotFound:
-				ok = false
-			case storage.ErrOutOfOrderSample:
-				numOutOfOrder++
-				level.Debug(sl.l).Log("msg", "Out of order sample", "series", string(met))
-				targetScrapeSampleOutOfOrder.Inc()
-				continue
-			case storage.ErrDuplicateSampleForTimestamp:
-				numDuplicates++
-				level.Debug(sl.l).Log("msg", "Duplicate sample for timestamp", "series", string(met))
-				targetScrapeSampleDuplicate.Inc()
-				continue
-			case storage.ErrOutOfBounds:
-				numOutOfBounds++
-				level.Debug(sl.l).Log("msg", "Out of bounds metric", "series", string(met))
-				targetScrapeSampleOutOfBounds.Inc()
-				continue
-			case errSampleLimit:
-				// Keep on parsing output if we hit the limit, so we report the correct
-				// total number of samples scraped.
-				sampleLimitErr = err
-				added++
-				continue
-			default:
-				break loop
-			}
-		}
-		if !ok {
+		var (
+			s = storage.Sample{T: t, V: v}
+			e = batchEntry{trackStaleness: tp == nil}
+		)
+		if ce, ok := sl.cache.get(yoloString(met)); ok {
+			s.Labels, s.Ref = ce.lset, ce.ref
+			e.ce, e.hash = ce, ce.hash
+		} else {
 			var lset labels.Labels
 
 			mets := p.Metric(&lset)
@@ -852,48 +1109,70 @@ loop:
 				continue
 			}
 
-			var ref uint64
-			ref, err = app.Add(lset, t, v)
-			// TODO(fabxc): also add a dropped-cache?
-			switch err {
-			case nil:
-			case storage.ErrOutOfOrderSample:
-				err = nil
-				numOutOfOrder++
-				level.Debug(sl.l).Log("msg", "Out of order sample", "series", string(met))
-				targetScrapeSampleOutOfOrder.Inc()
-				continue
-			case storage.ErrDuplicateSampleForTimestamp:
-				err = nil
-				numDuplicates++
-				level.Debug(sl.l).Log("msg", "Duplicate sample for timestamp", "series", string(met))
-				targetScrapeSampleDuplicate.Inc()
-				continue
-			case storage.ErrOutOfBounds:
-				err = nil
-				numOutOfBounds++
-				level.Debug(sl.l).Log("msg", "Out of bounds metric", "series", string(met))
-				targetScrapeSampleOutOfBounds.Inc()
-				continue
-			case errSampleLimit:
-				sampleLimitErr = err
-				added++
-				continue
-			default:
-				level.Debug(sl.l).Log("msg", "unexpected error", "series", string(met), "err", err)
+			// Series exceeding the label limits fail the entire scrape.
+			if err = verifyLabelLimits(lset, sl.labelLimits); err != nil {
+				targetScrapeLabelLimit.Inc()
 				break loop
 			}
-			if tp == nil {
-				// Bypass staleness logic if there is an explicit timestamp.
-				sl.cache.trackStaleness(hash, lset)
-			}
-			sl.cache.addRef(mets, ref, lset, hash)
+			s.Labels = lset
+			e.met, e.hash = mets, hash
+		}
+		if e.hasExemplar = p.Exemplar(&e.exemplar); e.hasExemplar && !e.exemplar.HasTs {
+			e.exemplar.Ts = t
 		}
-		added++
+		sl.batch = append(sl.batch, s)
+		sl.batchEntries = append(sl.batchEntries, e)
 	}
 	if err == nil {
 		err = p.Err()
 	}
+
+	// Samples that cannot be added are skipped and the remaining ones are
+	// added in another batch.
+	for i := 0; err == nil && i < len(sl.batch); i++ {
+		batchErr := app.AddBatch(sl.batch[i:])
+		n := len(sl.batch)
+		berr, ok := batchErr.(*storage.BatchError)
+		if ok {
+			n = i + berr.Index
+		} else if batchErr != nil {
+			err = batchErr
+			break
+		}
+		for ; i < n; i++ {
+			if sl.appended(app, &sl.batch[i], &sl.batchEntries[i]) {
+				seriesAdded++
+			}
+			added++
+		}
+		if batchErr == nil {
+			break
+		}
+
+		met := sl.batch[i].Labels
+		switch berr.Err {
+		case storage.ErrOutOfOrderSample:
+			numOutOfOrder++
+			level.Debug(sl.l).Log("msg", "Out of order sample", "series", met)
+			targetScrapeSampleOutOfOrder.Inc()
+		case storage.ErrDuplicateSampleForTimestamp:
+			numDuplicates++
+			level.Debug(sl.l).Log("msg", "Duplicate sample for timestamp", "series", met)
+			targetScrapeSampleDuplicate.Inc()
+		case storage.ErrOutOfBounds:
+			numOutOfBounds++
+			level.Debug(sl.l).Log("msg", "Out of bounds metric", "series", met)
+			targetScrapeSampleOutOfBounds.Inc()
+		case errSampleLimit:
+			// Keep on adding the samples if we hit the limit, so we report the correct
+			// number of samples added.
+			sampleLimitErr = berr.Err
+			added++
+		default:
+			level.Debug(sl.l).Log("msg", "unexpected error", "series", met, "err", berr.Err)
+			err = berr.Err
+		}
+	}
 	if err == nil && sampleLimitErr != nil {
 		targetScrapeSampleLimit.Inc()
 		err = sampleLimitErr
@@ -922,15 +1201,49 @@ loop:
 	}
 	if err != nil {
 		app.Rollback()
-		return total, added, err
+		return total, added, seriesAdded, err
 	}
 	if err := app.Commit(); err != nil {
-		return total, added, err
+		return total, added, seriesAdded, err
 	}
 
+	sl.cache.setMetadata(p.Metadata())
 	sl.cache.iterDone()
 
-	return total, added, nil
+	return total, added, seriesAdded, nil
+}
+
+// labelLimits holds the limits on the labels of the series of a scrape.
+// Zero values mean no limit.
+type labelLimits struct {
+	labelLimit            int
+	labelNameLengthLimit  int
+	labelValueLengthLimit int
+}
+
+// verifyLabelLimits returns an error if the label set, including the target
+// labels, exceeds the limits.
+func verifyLabelLimits(lset labels.Labels, limits *labelLimits) error {
+	if limits == nil {
+		return nil
+	}
+
+	met := lset.Get(labels.MetricName)
+	if limits.labelLimit > 0 && len(lset) > limits.labelLimit {
+		return fmt.Errorf("label_limit exceeded (metric: %.50s, number of labels: %d, limit: %d)", met, len(lset), limits.labelLimit)
+	}
+	if limits.labelNameLengthLimit == 0 && limits.labelValueLengthLimit == 0 {
+		return nil
+	}
+	for _, l := range lset {
+		if limits.labelNameLengthLimit > 0 && len(l.Name) > limits.labelNameLengthLimit {
+			return fmt.Errorf("label_name_length_limit exceeded (metric: %.50s, label name: %.50s, length: %d, limit: %d)", met, l.Name, len(l.Name), limits.labelNameLengthLimit)
+		}
+		if limits.labelValueLengthLimit > 0 && len(l.Value) > limits.labelValueLengthLimit {
+			return fmt.Errorf("label_value_length_limit exceeded (metric: %.50s, label name: %.50s, value: %.50q, length: %d, limit: %d)", met, l.Name, l.Value, len(l.Value), limits.labelValueLengthLimit)
+		}
+	}
+	return nil
 }
 
 func yoloString(b []byte) string {
@@ -944,9 +1257,11 @@ const (
 	scrapeDurationMetricName     = "scrape_duration_seconds" + "\xff"
 	scrapeSamplesMetricName      = "scrape_samples_scraped" + "\xff"
 	samplesPostRelabelMetricName = "scrape_samples_post_metric_relabeling" + "\xff"
+	scrapeSeriesAddedMetricName  = "scrape_series_added" + "\xff"
+	scrapeBodySizeMetricName     = "scrape_body_size_bytes" + "\xff"
 )
 
-func (sl *scrapeLoop) report(start time.Time, duration time.Duration, scraped, appended int, err error) error {
+func (sl *scrapeLoop) report(start time.Time, duration time.Duration, scraped, appended, seriesAdded, bodySize int, err error) error {
 	sl.scraper.report(start, duration, err)
 
 	ts := timestamp.FromTime(start)
@@ -973,6 +1288,14 @@ func (sl *scrapeLoop) report(start time.Time, duration time.Duration, scraped, a
 		app.Rollback()
 		return err
 	}
+	if err := sl.addReportSample(app, scrapeSeriesAddedMetricName, ts, float64(seriesAdded)); err != nil {
+		app.Rollback()
+		return err
+	}
+	if err := sl.addReportSample(app, scrapeBodySizeMetricName, ts, float64(bodySize)); err != nil {
+		app.Rollback()
+		return err
+	}
 	return app.Commit()
 }
 
@@ -998,9 +1321,59 @@ func (sl *scrapeLoop) reportStale(start time.Time) error {
 		app.Rollback()
 		return err
 	}
+	if err := sl.addReportSample(app, scrapeSeriesAddedMetricName, ts, stale); err != nil {
+		app.Rollback()
+		return err
+	}
+	if err := sl.addReportSample(app, scrapeBodySizeMetricName, ts, stale); err != nil {
+		app.Rollback()
+		return err
+	}
 	return app.Commit()
 }
 
+// batchEntry holds what the scrape loop tracks about a sample of the batch
+// of a scrape besides the sample itself.
+type batchEntry struct {
+	// The cache entry of the series, or nil if it is not cached yet.
+	ce *cacheEntry
+	// The metric string and the hash of the label set as it is seen local
+	// to the target, used to cache new series.
+	met  string
+	hash uint64
+	// Samples with an explicit timestamp bypass the staleness logic.
+	trackStaleness bool
+
+	exemplar    exemplar.Exemplar
+	hasExemplar bool
+}
+
+// appended updates the cache for the sample of the batch that was added and
+// appends its exemplar, if any. It returns true if the series was added to
+// the storage.
+func (sl *scrapeLoop) appended(app storage.Appender, s *storage.Sample, e *batchEntry) bool {
+	var added bool
+	switch {
+	case e.ce == nil:
+		sl.cache.addRef(e.met, s.Ref, s.Labels, e.hash)
+		added = true
+	case e.ce.ref != s.Ref:
+		// The cached reference was unknown to the storage, which added
+		// the series again.
+		e.ce.ref = s.Ref
+		added = true
+	}
+	if e.trackStaleness {
+		sl.cache.trackStaleness(e.hash, s.Labels)
+	}
+	if e.hasExemplar {
+		if err := app.AddExemplar(s.Labels, e.exemplar); err != nil {
+			level.Debug(sl.l).Log("msg", "Error adding exemplar", "series", s.Labels, "err", err)
+		}
+	}
+	return added
+}
+
 func (sl *scrapeLoop) addReportSample(app storage.Appender, s string, t int64, v float64) error {
 	ce, ok := sl.cache.get(s)
 	if ok {
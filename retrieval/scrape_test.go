@@ -15,6 +15,7 @@ package retrieval
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
@@ -33,7 +34,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/pkg/exemplar"
 	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/textparse"
 	"github.com/prometheus/prometheus/pkg/timestamp"
 	"github.com/prometheus/prometheus/pkg/value"
 	"github.com/prometheus/prometheus/storage"
@@ -71,6 +74,12 @@ func (l *testLoop) stop() {
 	l.stopFunc()
 }
 
+func (l *testLoop) getCache() *scrapeCache {
+	return nil
+}
+
+func (l *testLoop) disableEndOfRunStalenessMarkers() {}
+
 func TestScrapePoolStop(t *testing.T) {
 	sp := &scrapePool{
 		targets: map[uint64]*Target{},
@@ -145,7 +154,7 @@ func TestScrapePoolReload(t *testing.T) {
 	}
 	// On starting to run, new loops created on reload check whether their preceding
 	// equivalents have been stopped.
-	newLoop := func(_ *Target, s scraper) loop {
+	newLoop := func(_ *Target, s scraper, _ *scrapeCache) loop {
 		l := &testLoop{}
 		l.startFunc = func(interval, timeout time.Duration, errc chan<- error) {
 			if interval != 3*time.Second {
@@ -228,6 +237,67 @@ func TestScrapePoolReload(t *testing.T) {
 	}
 }
 
+func TestScrapePoolReloadReusesCache(t *testing.T) {
+	cfg := &config.ScrapeConfig{
+		ScrapeInterval: model.Duration(3 * time.Second),
+		ScrapeTimeout:  model.Duration(2 * time.Second),
+	}
+	for _, c := range []struct {
+		reloadCfg *config.ScrapeConfig
+		reused    bool
+	}{
+		{
+			reloadCfg: &config.ScrapeConfig{
+				ScrapeInterval: model.Duration(5 * time.Second),
+				ScrapeTimeout:  model.Duration(4 * time.Second),
+				SampleLimit:    100,
+			},
+			reused: true,
+		}, {
+			reloadCfg: &config.ScrapeConfig{
+				ScrapeInterval: model.Duration(3 * time.Second),
+				ScrapeTimeout:  model.Duration(2 * time.Second),
+				HonorLabels:    true,
+			},
+			reused: false,
+		},
+	} {
+		oldLoop := newScrapeLoop(context.Background(),
+			&testScraper{offsetDur: time.Hour},
+			nil, nil,
+			nopMutator,
+			nopMutator,
+			nil,
+			nil,
+			nil,
+			true,
+		)
+		go oldLoop.run(time.Hour, time.Hour, nil)
+
+		var newCache *scrapeCache
+		sp := &scrapePool{
+			config:  cfg,
+			targets: map[uint64]*Target{1: {}},
+			loops:   map[uint64]loop{1: oldLoop},
+			newLoop: func(_ *Target, _ scraper, cache *scrapeCache) loop {
+				newCache = cache
+				return &testLoop{
+					startFunc: func(time.Duration, time.Duration, chan<- error) {},
+					stopFunc:  func() {},
+				}
+			},
+		}
+		sp.reload(c.reloadCfg)
+
+		if reused := newCache == oldLoop.cache; reused != c.reused {
+			t.Fatalf("Expected cache reuse to be %t for reload to %+v", c.reused, c.reloadCfg)
+		}
+		if oldLoop.disabledEndOfRunStalenessMarkers != c.reused {
+			t.Fatalf("Expected stale markers of the old loop to be disabled only if its cache is reused")
+		}
+	}
+}
+
 func TestScrapePoolAppender(t *testing.T) {
 	cfg := &config.ScrapeConfig{}
 	app := &nopAppendable{}
@@ -269,6 +339,9 @@ func TestScrapeLoopStopBeforeRun(t *testing.T) {
 		nopMutator,
 		nopMutator,
 		nil,
+		nil,
+		nil,
+		true,
 	)
 
 	// The scrape pool synchronizes on stopping scrape loops. However, new scrape
@@ -331,6 +404,9 @@ func TestScrapeLoopStop(t *testing.T) {
 		nopMutator,
 		nopMutator,
 		app,
+		nil,
+		nil,
+		true,
 	)
 
 	// Terminate loop after 2 scrapes.
@@ -356,22 +432,22 @@ func TestScrapeLoopStop(t *testing.T) {
 		t.Fatalf("Scrape wasn't stopped.")
 	}
 
-	// We expected 1 actual sample for each scrape plus 4 for report samples.
+	// We expected 1 actual sample for each scrape plus 6 for report samples.
 	// At least 2 scrapes were made, plus the final stale markers.
-	if len(appender.result) < 5*3 || len(appender.result)%5 != 0 {
-		t.Fatalf("Expected at least 3 scrapes with 4 samples each, got %d samples", len(appender.result))
+	if len(appender.result) < 7*3 || len(appender.result)%7 != 0 {
+		t.Fatalf("Expected at least 3 scrapes with 7 samples each, got %d samples", len(appender.result))
 	}
 	// All samples in a scrape must have the same timestmap.
 	var ts int64
 	for i, s := range appender.result {
-		if i%5 == 0 {
+		if i%7 == 0 {
 			ts = s.t
 		} else if s.t != ts {
 			t.Fatalf("Unexpected multiple timestamps within single scrape")
 		}
 	}
 	// All samples from the last scrape must be stale markers.
-	for _, s := range appender.result[len(appender.result)-5:] {
+	for _, s := range appender.result[len(appender.result)-7:] {
 		if !value.IsStaleNaN(s.v) {
 			t.Fatalf("Appended last sample not as expected. Wanted: stale NaN Got: %x", math.Float64bits(s.v))
 		}
@@ -395,6 +471,9 @@ func TestScrapeLoopRun(t *testing.T) {
 		nopMutator,
 		nopMutator,
 		app,
+		nil,
+		nil,
+		true,
 	)
 
 	// The loop must terminate during the initial offset if the context
@@ -439,6 +518,9 @@ func TestScrapeLoopRun(t *testing.T) {
 		nopMutator,
 		nopMutator,
 		app,
+		nil,
+		nil,
+		true,
 	)
 
 	go func() {
@@ -487,6 +569,9 @@ func TestScrapeLoopRunCreatesStaleMarkersOnFailedScrape(t *testing.T) {
 		nopMutator,
 		nopMutator,
 		app,
+		nil,
+		nil,
+		true,
 	)
 	// Succeed once, several failures, then stop.
 	numScrapes := 0
@@ -514,16 +599,16 @@ func TestScrapeLoopRunCreatesStaleMarkersOnFailedScrape(t *testing.T) {
 		t.Fatalf("Scrape wasn't stopped.")
 	}
 
-	// 1 successfully scraped sample, 1 stale marker after first fail, 4 report samples for
+	// 1 successfully scraped sample, 1 stale marker after first fail, 6 report samples for
 	// each scrape successful or not.
-	if len(appender.result) != 22 {
-		t.Fatalf("Appended samples not as expected. Wanted: %d samples Got: %d", 22, len(appender.result))
+	if len(appender.result) != 32 {
+		t.Fatalf("Appended samples not as expected. Wanted: %d samples Got: %d", 32, len(appender.result))
 	}
 	if appender.result[0].v != 42.0 {
 		t.Fatalf("Appended first sample not as expected. Wanted: %f Got: %f", appender.result[0].v, 42.0)
 	}
-	if !value.IsStaleNaN(appender.result[5].v) {
-		t.Fatalf("Appended second sample not as expected. Wanted: stale NaN Got: %x", math.Float64bits(appender.result[5].v))
+	if !value.IsStaleNaN(appender.result[7].v) {
+		t.Fatalf("Appended second sample not as expected. Wanted: stale NaN Got: %x", math.Float64bits(appender.result[7].v))
 	}
 }
 
@@ -544,6 +629,9 @@ func TestScrapeLoopRunCreatesStaleMarkersOnParseFailure(t *testing.T) {
 		nopMutator,
 		nopMutator,
 		app,
+		nil,
+		nil,
+		true,
 	)
 
 	// Succeed once, several failures, then stop.
@@ -573,16 +661,16 @@ func TestScrapeLoopRunCreatesStaleMarkersOnParseFailure(t *testing.T) {
 		t.Fatalf("Scrape wasn't stopped.")
 	}
 
-	// 1 successfully scraped sample, 1 stale marker after first fail, 4 report samples for
+	// 1 successfully scraped sample, 1 stale marker after first fail, 6 report samples for
 	// each scrape successful or not.
-	if len(appender.result) != 14 {
-		t.Fatalf("Appended samples not as expected. Wanted: %d samples Got: %d", 22, len(appender.result))
+	if len(appender.result) != 20 {
+		t.Fatalf("Appended samples not as expected. Wanted: %d samples Got: %d", 20, len(appender.result))
 	}
 	if appender.result[0].v != 42.0 {
 		t.Fatalf("Appended first sample not as expected. Wanted: %f Got: %f", appender.result[0].v, 42.0)
 	}
-	if !value.IsStaleNaN(appender.result[5].v) {
-		t.Fatalf("Appended second sample not as expected. Wanted: stale NaN Got: %x", math.Float64bits(appender.result[5].v))
+	if !value.IsStaleNaN(appender.result[7].v) {
+		t.Fatalf("Appended second sample not as expected. Wanted: stale NaN Got: %x", math.Float64bits(appender.result[7].v))
 	}
 }
 
@@ -594,10 +682,13 @@ func TestScrapeLoopAppend(t *testing.T) {
 		nopMutator,
 		nopMutator,
 		func() storage.Appender { return app },
+		nil,
+		nil,
+		true,
 	)
 
 	now := time.Now()
-	_, _, err := sl.append([]byte("metric_a 1\nmetric_b NaN\n"), now)
+	_, _, _, err := sl.append([]byte("metric_a 1\nmetric_b NaN\n"), "", now)
 	if err != nil {
 		t.Fatalf("Unexpected append error: %s", err)
 	}
@@ -626,6 +717,153 @@ func TestScrapeLoopAppend(t *testing.T) {
 	}
 }
 
+func TestScrapeLoopAppendExemplars(t *testing.T) {
+	app := &collectResultAppender{}
+
+	sl := newScrapeLoop(context.Background(),
+		nil, nil, nil,
+		nopMutator,
+		nopMutator,
+		func() storage.Appender { return app },
+		nil,
+		nil,
+		true,
+	)
+
+	now := time.Now()
+	input := "metric_a 1 # {trace_id=\"a\"} 0.5\nmetric_b 2 # {trace_id=\"b\"} 1.5 1520879607.789\nmetric_c 3\n# EOF\n"
+	// Exemplars of cached series are appended as well.
+	for i := 0; i < 2; i++ {
+		app.exemplars = nil
+		if _, _, _, err := sl.append([]byte(input), "application/openmetrics-text; version=0.0.1", now); err != nil {
+			t.Fatalf("Unexpected append error: %s", err)
+		}
+		want := []exemplar.Exemplar{
+			{Labels: labels.FromStrings("trace_id", "a"), Value: 0.5, Ts: timestamp.FromTime(now)},
+			{Labels: labels.FromStrings("trace_id", "b"), Value: 1.5, Ts: 1520879607789, HasTs: true},
+		}
+		if !reflect.DeepEqual(want, app.exemplars) {
+			t.Fatalf("Appended exemplars not as expected. Wanted: %+v Got: %+v", want, app.exemplars)
+		}
+	}
+}
+
+func TestScrapeLoopAppendLabelLimits(t *testing.T) {
+	cases := []struct {
+		title   string
+		scraped string
+		limits  *labelLimits
+		err     string
+	}{
+		{
+			title:   "No limits",
+			scraped: `metric{a="1",b="2"} 1`,
+		},
+		{
+			title:   "Within limits",
+			scraped: `metric{a="1",b="2"} 1`,
+			limits:  &labelLimits{labelLimit: 3, labelNameLengthLimit: 8, labelValueLengthLimit: 6},
+		},
+		{
+			title:   "Label limit exceeded",
+			scraped: "metric_a 1\nmetric_b{a=\"1\",b=\"2\"} 1\n",
+			limits:  &labelLimits{labelLimit: 2},
+			err:     "label_limit exceeded (metric: metric_b, number of labels: 3, limit: 2)",
+		},
+		{
+			title:   "Label name length limit exceeded",
+			scraped: `metric{long_name="1"} 1`,
+			limits:  &labelLimits{labelNameLengthLimit: 8},
+			err:     "label_name_length_limit exceeded (metric: metric, label name: long_name, length: 9, limit: 8)",
+		},
+		{
+			title:   "Label value length limit exceeded",
+			scraped: `metric{a="long value"} 1`,
+			limits:  &labelLimits{labelValueLengthLimit: 6},
+			err:     `label_value_length_limit exceeded (metric: metric, label name: a, value: "long value", length: 10, limit: 6)`,
+		},
+	}
+	for _, c := range cases {
+		app := &collectResultAppender{}
+
+		sl := newScrapeLoop(context.Background(),
+			nil, nil, nil,
+			nopMutator,
+			nopMutator,
+			func() storage.Appender { return app },
+			nil,
+			c.limits,
+			true,
+		)
+
+		_, _, _, err := sl.append([]byte(c.scraped), "", time.Now())
+		if c.err == "" {
+			if err != nil {
+				t.Fatalf("%s: Unexpected append error: %s", c.title, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != c.err {
+			t.Fatalf("%s: Expected error %q but got %v", c.title, c.err, err)
+		}
+	}
+}
+
+func TestScrapeLoopAppendMetadata(t *testing.T) {
+	app := &collectResultAppender{}
+
+	sl := newScrapeLoop(context.Background(),
+		nil, nil, nil,
+		nopMutator,
+		nopMutator,
+		func() storage.Appender { return app },
+		nil,
+		nil,
+		true,
+	)
+
+	_, _, _, err := sl.append([]byte(`# TYPE metric_a counter
+# HELP metric_a Some help text.
+metric_a 1
+# HELP metric_b No type.
+metric_b 1
+`), "", time.Now())
+	if err != nil {
+		t.Fatalf("Unexpected append error: %s", err)
+	}
+
+	md, ok := sl.cache.GetMetadata("metric_a")
+	if !ok {
+		t.Fatal("expected metadata for metric_a")
+	}
+	want := MetricMetadata{Metric: "metric_a", Type: textparse.MetricTypeCounter, Help: "Some help text."}
+	if md != want {
+		t.Fatalf("unexpected metadata, wanted: %+v, got: %+v", want, md)
+	}
+	md, ok = sl.cache.GetMetadata("metric_b")
+	if !ok {
+		t.Fatal("expected metadata for metric_b")
+	}
+	want = MetricMetadata{Metric: "metric_b", Type: textparse.MetricTypeUntyped, Help: "No type."}
+	if md != want {
+		t.Fatalf("unexpected metadata, wanted: %+v, got: %+v", want, md)
+	}
+	if len(sl.cache.ListMetadata()) != 2 {
+		t.Fatalf("expected metadata of 2 metrics, got %v", sl.cache.ListMetadata())
+	}
+
+	// Changed metadata must replace the previous one.
+	_, _, _, err = sl.append([]byte("# HELP metric_a Other help text.\nmetric_a 2\n"), "", time.Now())
+	if err != nil {
+		t.Fatalf("Unexpected append error: %s", err)
+	}
+	md, _ = sl.cache.GetMetadata("metric_a")
+	want = MetricMetadata{Metric: "metric_a", Type: textparse.MetricTypeCounter, Help: "Other help text."}
+	if md != want {
+		t.Fatalf("unexpected metadata, wanted: %+v, got: %+v", want, md)
+	}
+}
+
 func TestScrapeLoop_ChangingMetricString(t *testing.T) {
 	// This is a regression test for the scrape loop cache not properly maintaining
 	// IDs when the string representation of a metric changes across a scrape. Thus
@@ -644,14 +882,17 @@ func TestScrapeLoop_ChangingMetricString(t *testing.T) {
 		nopMutator,
 		nopMutator,
 		func() storage.Appender { return capp },
+		nil,
+		nil,
+		true,
 	)
 
 	now := time.Now()
-	_, _, err = sl.append([]byte(`metric_a{a="1",b="1"} 1`), now)
+	_, _, _, err = sl.append([]byte(`metric_a{a="1",b="1"} 1`), "", now)
 	if err != nil {
 		t.Fatalf("Unexpected append error: %s", err)
 	}
-	_, _, err = sl.append([]byte(`metric_a{b="1",a="1"} 2`), now.Add(time.Minute))
+	_, _, _, err = sl.append([]byte(`metric_a{b="1",a="1"} 2`), "", now.Add(time.Minute))
 	if err != nil {
 		t.Fatalf("Unexpected append error: %s", err)
 	}
@@ -682,14 +923,17 @@ func TestScrapeLoopAppendStaleness(t *testing.T) {
 		nopMutator,
 		nopMutator,
 		func() storage.Appender { return app },
+		nil,
+		nil,
+		true,
 	)
 
 	now := time.Now()
-	_, _, err := sl.append([]byte("metric_a 1\n"), now)
+	_, _, _, err := sl.append([]byte("metric_a 1\n"), "", now)
 	if err != nil {
 		t.Fatalf("Unexpected append error: %s", err)
 	}
-	_, _, err = sl.append([]byte(""), now.Add(time.Second))
+	_, _, _, err = sl.append([]byte(""), "", now.Add(time.Second))
 	if err != nil {
 		t.Fatalf("Unexpected append error: %s", err)
 	}
@@ -719,6 +963,86 @@ func TestScrapeLoopAppendStaleness(t *testing.T) {
 
 }
 
+func TestScrapeLoopAppendSeriesAdded(t *testing.T) {
+	// Series are only cached with the references of a real storage.
+	s := testutil.NewStorage(t)
+	defer s.Close()
+
+	sl := newScrapeLoop(context.Background(),
+		nil, nil, nil,
+		nopMutator,
+		nopMutator,
+		func() storage.Appender {
+			app, err := s.Appender()
+			if err != nil {
+				t.Fatal(err)
+			}
+			return app
+		},
+		nil,
+		nil,
+		true,
+	)
+
+	now := time.Now()
+	for i, c := range []struct {
+		scraped     string
+		seriesAdded int
+	}{
+		{scraped: "metric_a 1\nmetric_b 1\n", seriesAdded: 2},
+		{scraped: "metric_a 2\nmetric_b 2\n", seriesAdded: 0},
+		{scraped: "metric_a 3\nmetric_c 3\n", seriesAdded: 1},
+	} {
+		_, _, seriesAdded, err := sl.append([]byte(c.scraped), "", now.Add(time.Duration(i)*time.Second))
+		if err != nil {
+			t.Fatalf("Unexpected append error: %s", err)
+		}
+		if seriesAdded != c.seriesAdded {
+			t.Fatalf("Scrape %d: expected %d series added but got %d", i, c.seriesAdded, seriesAdded)
+		}
+	}
+}
+
+func TestScrapeLoopAppendOpenMetrics(t *testing.T) {
+	app := &collectResultAppender{}
+
+	sl := newScrapeLoop(context.Background(),
+		nil, nil, nil,
+		nopMutator,
+		nopMutator,
+		func() storage.Appender { return app },
+		nil,
+		nil,
+		true,
+	)
+
+	now := time.Now()
+	_, _, _, err := sl.append([]byte(`# TYPE metric_a counter
+metric_a_total 1 # {trace_id="abc"} 1.0
+metric_b 2 1520879607.789 # {trace_id="def"} 2.0 1520879607.789
+# EOF
+`), "application/openmetrics-text; version=0.0.1", now)
+	if err != nil {
+		t.Fatalf("Unexpected append error: %s", err)
+	}
+
+	want := []sample{
+		{
+			metric: labels.FromStrings(model.MetricNameLabel, "metric_a_total"),
+			t:      timestamp.FromTime(now),
+			v:      1,
+		},
+		{
+			metric: labels.FromStrings(model.MetricNameLabel, "metric_b"),
+			t:      1520879607789,
+			v:      2,
+		},
+	}
+	if !reflect.DeepEqual(want, app.result) {
+		t.Fatalf("Appended samples not as expected. Wanted: %+v Got: %+v", want, app.result)
+	}
+}
+
 func TestScrapeLoopAppendNoStalenessIfTimestamp(t *testing.T) {
 	app := &collectResultAppender{}
 	sl := newScrapeLoop(context.Background(),
@@ -726,14 +1050,17 @@ func TestScrapeLoopAppendNoStalenessIfTimestamp(t *testing.T) {
 		nopMutator,
 		nopMutator,
 		func() storage.Appender { return app },
+		nil,
+		nil,
+		true,
 	)
 
 	now := time.Now()
-	_, _, err := sl.append([]byte("metric_a 1 1000\n"), now)
+	_, _, _, err := sl.append([]byte("metric_a 1 1000\n"), "", now)
 	if err != nil {
 		t.Fatalf("Unexpected append error: %s", err)
 	}
-	_, _, err = sl.append([]byte(""), now.Add(time.Second))
+	_, _, _, err = sl.append([]byte(""), "", now.Add(time.Second))
 	if err != nil {
 		t.Fatalf("Unexpected append error: %s", err)
 	}
@@ -750,6 +1077,70 @@ func TestScrapeLoopAppendNoStalenessIfTimestamp(t *testing.T) {
 	}
 }
 
+func TestScrapeLoopAppendNoHonorTimestamps(t *testing.T) {
+	app := &collectResultAppender{}
+	sl := newScrapeLoop(context.Background(),
+		nil, nil, nil,
+		nopMutator,
+		nopMutator,
+		func() storage.Appender { return app },
+		nil,
+		nil,
+		false,
+	)
+
+	now := time.Now()
+	_, _, _, err := sl.append([]byte("metric_a 1 1000\n"), "", now)
+	if err != nil {
+		t.Fatalf("Unexpected append error: %s", err)
+	}
+	_, _, _, err = sl.append([]byte(""), "", now.Add(time.Second))
+	if err != nil {
+		t.Fatalf("Unexpected append error: %s", err)
+	}
+
+	// The exposed timestamp is replaced with the scrape time and the series
+	// becomes stale like any series without a timestamp.
+	if len(app.result) != 2 {
+		t.Fatalf("Expected 2 samples but got %d: %+v", len(app.result), app.result)
+	}
+	if !value.IsStaleNaN(app.result[1].v) {
+		t.Fatalf("Appended second sample not as expected. Wanted: stale NaN Got: %x", math.Float64bits(app.result[1].v))
+	}
+	app.result[1].v = 42
+	want := []sample{
+		{
+			metric: labels.FromStrings(model.MetricNameLabel, "metric_a"),
+			t:      timestamp.FromTime(now),
+			v:      1,
+		},
+		{
+			metric: labels.FromStrings(model.MetricNameLabel, "metric_a"),
+			t:      timestamp.FromTime(now.Add(time.Second)),
+			v:      42,
+		},
+	}
+	if !reflect.DeepEqual(want, app.result) {
+		t.Fatalf("Appended samples not as expected. Wanted: %+v Got: %+v", want, app.result)
+	}
+}
+
+func TestTargetScraperOffsetNoJitter(t *testing.T) {
+	interval := 10 * time.Second
+
+	ts := &targetScraper{
+		Target: newTestTarget("example.com:80", 0, labels.FromStrings("label", "1")),
+	}
+	offset := ts.offset(interval)
+	if offset <= 0 || offset > interval {
+		t.Fatalf("Offset %v out of bounds", offset)
+	}
+	// Without jitter, the next scrape starts at a multiple of the interval.
+	if next := time.Now().Add(offset).UnixNano() % int64(interval); next > int64(time.Second) && next < int64(interval-time.Second) {
+		t.Fatalf("Next scrape at %v into the interval, expected its start", time.Duration(next))
+	}
+}
+
 func TestScrapeLoopRunReportsTargetDownOnScrapeError(t *testing.T) {
 	var (
 		scraper  = &testScraper{}
@@ -764,6 +1155,9 @@ func TestScrapeLoopRunReportsTargetDownOnScrapeError(t *testing.T) {
 		nopMutator,
 		nopMutator,
 		app,
+		nil,
+		nil,
+		true,
 	)
 
 	scraper.scrapeFunc = func(ctx context.Context, w io.Writer) error {
@@ -792,6 +1186,9 @@ func TestScrapeLoopRunReportsTargetDownOnInvalidUTF8(t *testing.T) {
 		nopMutator,
 		nopMutator,
 		app,
+		nil,
+		nil,
+		true,
 	)
 
 	scraper.scrapeFunc = func(ctx context.Context, w io.Writer) error {
@@ -828,6 +1225,10 @@ func (app *errorAppender) AddFast(lset labels.Labels, ref uint64, t int64, v flo
 	return app.collectResultAppender.AddFast(lset, ref, t, v)
 }
 
+func (app *errorAppender) AddBatch(samples []storage.Sample) error {
+	return storage.AppendBatch(app, samples)
+}
+
 func TestScrapeLoopAppendGracefullyIfAmendOrOutOfOrderOrOutOfBounds(t *testing.T) {
 	app := &errorAppender{}
 
@@ -837,10 +1238,13 @@ func TestScrapeLoopAppendGracefullyIfAmendOrOutOfOrderOrOutOfBounds(t *testing.T
 		nopMutator,
 		nopMutator,
 		func() storage.Appender { return app },
+		nil,
+		nil,
+		true,
 	)
 
 	now := time.Unix(1, 0)
-	_, _, err := sl.append([]byte("out_of_order 1\namend 1\nnormal 1\nout_of_bounds 1\n"), now)
+	_, _, _, err := sl.append([]byte("out_of_order 1\namend 1\nnormal 1\nout_of_bounds 1\n"), "", now)
 	if err != nil {
 		t.Fatalf("Unexpected append error: %s", err)
 	}
@@ -869,10 +1273,13 @@ func TestScrapeLoopOutOfBoundsTimeError(t *testing.T) {
 				maxTime:  timestamp.FromTime(time.Now().Add(10 * time.Minute)),
 			}
 		},
+		nil,
+		nil,
+		true,
 	)
 
 	now := time.Now().Add(20 * time.Minute)
-	total, added, err := sl.append([]byte("normal 1\n"), now)
+	total, added, _, err := sl.append([]byte("normal 1\n"), "", now)
 	if total != 1 {
 		t.Error("expected 1 metric")
 		return
@@ -928,12 +1335,60 @@ func TestTargetScraperScrapeOK(t *testing.T) {
 	}
 	var buf bytes.Buffer
 
-	if err := ts.scrape(context.Background(), &buf); err != nil {
+	contentType, err := ts.scrape(context.Background(), &buf)
+	if err != nil {
 		t.Fatalf("Unexpected scrape error: %s", err)
 	}
+	require.Equal(t, "text/plain; version=0.0.4", contentType)
 	require.Equal(t, "metric_a 1\nmetric_b 2\n", buf.String())
 }
 
+func TestTargetScraperScrapeOpenMetrics(t *testing.T) {
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			accept := r.Header.Get("Accept")
+			if !strings.HasPrefix(accept, "application/openmetrics-text;") {
+				t.Errorf("Expected Accept header to prefer application/openmetrics-text, got %q", accept)
+			}
+
+			w.Header().Set("Content-Type", "application/openmetrics-text; version=0.0.1")
+			w.Header().Set("Content-Encoding", "gzip")
+			gw := gzip.NewWriter(w)
+			gw.Write([]byte("metric_a 1\n# EOF\n"))
+			gw.Close()
+		}),
+	)
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		panic(err)
+	}
+
+	ts := &targetScraper{
+		Target: &Target{
+			labels: labels.FromStrings(
+				model.SchemeLabel, serverURL.Scheme,
+				model.AddressLabel, serverURL.Host,
+			),
+		},
+		client: http.DefaultClient,
+		format: config.ScrapeFormatOpenMetrics,
+	}
+
+	// Scrape twice to exercise reusing the gzip reader.
+	for i := 0; i < 2; i++ {
+		var buf bytes.Buffer
+
+		contentType, err := ts.scrape(context.Background(), &buf)
+		if err != nil {
+			t.Fatalf("Unexpected scrape error: %s", err)
+		}
+		require.Equal(t, "application/openmetrics-text; version=0.0.1", contentType)
+		require.Equal(t, "metric_a 1\n# EOF\n", buf.String())
+	}
+}
+
 func TestTargetScrapeScrapeCancel(t *testing.T) {
 	block := make(chan struct{})
 
@@ -968,7 +1423,7 @@ func TestTargetScrapeScrapeCancel(t *testing.T) {
 	}()
 
 	go func() {
-		if err := ts.scrape(ctx, ioutil.Discard); err != context.Canceled {
+		if _, err := ts.scrape(ctx, ioutil.Discard); err != context.Canceled {
 			errc <- fmt.Errorf("Expected context cancelation error but got: %s", err)
 		}
 		close(errc)
@@ -1010,7 +1465,7 @@ func TestTargetScrapeScrapeNotFound(t *testing.T) {
 		client: http.DefaultClient,
 	}
 
-	if err := ts.scrape(context.Background(), ioutil.Discard); !strings.Contains(err.Error(), "404") {
+	if _, err := ts.scrape(context.Background(), ioutil.Discard); !strings.Contains(err.Error(), "404") {
 		t.Fatalf("Expected \"404 NotFound\" error but got: %s", err)
 	}
 }
@@ -1038,9 +1493,9 @@ func (ts *testScraper) report(start time.Time, duration time.Duration, err error
 	ts.lastError = err
 }
 
-func (ts *testScraper) scrape(ctx context.Context, w io.Writer) error {
+func (ts *testScraper) scrape(ctx context.Context, w io.Writer) (string, error) {
 	if ts.scrapeFunc != nil {
-		return ts.scrapeFunc(ctx, w)
+		return "", ts.scrapeFunc(ctx, w)
 	}
-	return ts.scrapeErr
+	return "", ts.scrapeErr
 }
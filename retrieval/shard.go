@@ -0,0 +1,73 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrieval
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/common/model"
+)
+
+// Shard selects the targets scraped by one of several Prometheus servers
+// splitting the targets of all jobs among them. The zero value selects all
+// targets.
+type Shard struct {
+	// Index is the number of the shard, from 0 to Count-1.
+	Index uint64
+	// Count is the total number of shards.
+	Count uint64
+}
+
+// ParseShard parses a shard in the form "N/M", the N-th of M shards.
+func ParseShard(s string) (Shard, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 {
+		return Shard{}, fmt.Errorf("invalid shard %q, expected N/M", s)
+	}
+	index, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return Shard{}, fmt.Errorf("invalid shard index %q: %s", parts[0], err)
+	}
+	count, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return Shard{}, fmt.Errorf("invalid shard count %q: %s", parts[1], err)
+	}
+	if count == 0 {
+		return Shard{}, fmt.Errorf("shard count must be positive")
+	}
+	if index >= count {
+		return Shard{}, fmt.Errorf("shard index %d must be less than the shard count %d", index, count)
+	}
+	return Shard{Index: index, Count: count}, nil
+}
+
+func (s Shard) String() string {
+	return fmt.Sprintf("%d/%d", s.Index, s.Count)
+}
+
+// contains returns whether the target belongs to the shard. Targets are
+// assigned by the hash of their address after relabeling, which equals the
+// result of a hashmod relabeling of the address with the shard count as
+// modulus.
+func (s Shard) contains(t *Target) bool {
+	if s.Count <= 1 {
+		return true
+	}
+	h := md5.Sum([]byte(t.labels.Get(model.AddressLabel)))
+	return binary.BigEndian.Uint64(h[md5.Size-8:])%s.Count == s.Index
+}
@@ -0,0 +1,117 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrieval
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/relabel"
+)
+
+func TestParseShard(t *testing.T) {
+	tests := []struct {
+		input string
+		shard Shard
+		fail  bool
+	}{
+		{input: "0/1", shard: Shard{Index: 0, Count: 1}},
+		{input: "2/3", shard: Shard{Index: 2, Count: 3}},
+		{input: "3/3", fail: true},
+		{input: "0/0", fail: true},
+		{input: "-1/3", fail: true},
+		{input: "1", fail: true},
+		{input: "1/2/3", fail: true},
+		{input: "a/b", fail: true},
+	}
+	for _, test := range tests {
+		s, err := ParseShard(test.input)
+		if test.fail {
+			if err == nil {
+				t.Fatalf("Expected error for %q but got none", test.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %s", test.input, err)
+		}
+		if s != test.shard {
+			t.Fatalf("Expected shard %v for %q but got %v", test.shard, test.input, s)
+		}
+		if s.String() != test.input {
+			t.Fatalf("Expected %q to be printed as itself but got %q", test.input, s)
+		}
+	}
+}
+
+func TestScrapeManagerShard(t *testing.T) {
+	const shards = 3
+
+	var targets []model.LabelSet
+	for i := 0; i < 30; i++ {
+		targets = append(targets, model.LabelSet{model.AddressLabel: model.LabelValue(fmt.Sprintf("node-%d:9100", i))})
+	}
+
+	seen := map[string]uint64{}
+	for i := uint64(0); i < shards; i++ {
+		m := NewScrapeManager(nil, &nopAppendable{})
+		m.SetShard(Shard{Index: i, Count: shards})
+
+		if err := m.ApplyConfig(mustLoadConfig(t, `
+scrape_configs:
+ - job_name: job1
+`)); err != nil {
+			t.Fatal(err)
+		}
+		m.sync(map[string][]*config.TargetGroup{
+			"job1": {{Source: "0", Targets: targets}},
+		})
+
+		active := m.Targets()
+		if len(active) == 0 || len(active) == len(targets) {
+			t.Fatalf("Expected shard %d to scrape some of the targets but got %d", i, len(active))
+		}
+		if len(active)+len(m.DroppedTargets()) != len(targets) {
+			t.Fatalf("Expected the targets of other shards to be dropped")
+		}
+		for _, target := range active {
+			addr := target.labels.Get(model.AddressLabel)
+			if s, ok := seen[addr]; ok {
+				t.Fatalf("Target %s scraped by shards %d and %d", addr, s, i)
+			}
+			seen[addr] = i
+		}
+		m.Stop()
+	}
+	if len(seen) != len(targets) {
+		t.Fatalf("Expected all %d targets to be scraped but got %d", len(targets), len(seen))
+	}
+
+	// Targets are assigned like by a hashmod relabeling of their address.
+	for _, lset := range targets {
+		res := relabel.Process(lset.Clone(), &config.RelabelConfig{
+			SourceLabels: model.LabelNames{model.AddressLabel},
+			Modulus:      shards,
+			TargetLabel:  "__tmp_shard",
+			Action:       config.RelabelHashMod,
+		})
+		addr := string(lset[model.AddressLabel])
+		if exp := fmt.Sprint(seen[addr]); string(res["__tmp_shard"]) != exp {
+			t.Fatalf("Expected target %s in shard %s but got %s", addr, res["__tmp_shard"], exp)
+		}
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/pkg/labels"
 	"github.com/prometheus/prometheus/pkg/relabel"
+	"github.com/prometheus/prometheus/pkg/textparse"
 	"github.com/prometheus/prometheus/pkg/value"
 	"github.com/prometheus/prometheus/storage"
 )
@@ -50,11 +51,32 @@ type Target struct {
 	labels labels.Labels
 	// Additional URL parmeters that are part of the target URL.
 	params url.Values
+	// The relabel configuration that dropped the target and its index in
+	// the scrape config, if it was dropped.
+	dropIndex  int
+	dropConfig *config.RelabelConfig
+
+	mtx                sync.RWMutex
+	lastError          error
+	lastScrape         time.Time
+	lastScrapeDuration time.Duration
+	health             TargetHealth
+	metadata           MetricMetadataStore
+}
+
+// MetricMetadataStore provides access to the metadata of the metric families
+// exposed by a target.
+type MetricMetadataStore interface {
+	ListMetadata() []MetricMetadata
+	GetMetadata(metric string) (MetricMetadata, bool)
+}
 
-	mtx        sync.RWMutex
-	lastError  error
-	lastScrape time.Time
-	health     TargetHealth
+// MetricMetadata is the metadata of a metric family as exposed by a target.
+type MetricMetadata struct {
+	Metric string
+	Type   textparse.MetricType
+	Help   string
+	Unit   string
 }
 
 // NewTarget creates a reasonably configured target for querying.
@@ -64,13 +86,54 @@ func NewTarget(labels, discoveredLabels labels.Labels, params url.Values) *Targe
 		discoveredLabels: discoveredLabels,
 		params:           params,
 		health:           HealthUnknown,
+		dropIndex:        -1,
 	}
 }
 
+// NewDroppedTarget returns a target with the given discovered labels that was
+// dropped by the relabel configuration with the given index.
+func NewDroppedTarget(discoveredLabels labels.Labels, params url.Values, index int, cfg *config.RelabelConfig) *Target {
+	t := NewTarget(nil, discoveredLabels, params)
+	t.dropIndex = index
+	t.dropConfig = cfg
+	return t
+}
+
 func (t *Target) String() string {
 	return t.URL().String()
 }
 
+// MetadataList returns the metadata of all metric families the target
+// exposed in its recent scrapes.
+func (t *Target) MetadataList() []MetricMetadata {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+
+	if t.metadata == nil {
+		return nil
+	}
+	return t.metadata.ListMetadata()
+}
+
+// Metadata returns the metadata of the metric family with the given name.
+func (t *Target) Metadata(metric string) (MetricMetadata, bool) {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+
+	if t.metadata == nil {
+		return MetricMetadata{}, false
+	}
+	return t.metadata.GetMetadata(metric)
+}
+
+// SetMetadataStore sets the store the target's metadata is read from.
+func (t *Target) SetMetadataStore(s MetricMetadataStore) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.metadata = s
+}
+
 // hash returns an identifying hash for the target.
 func (t *Target) hash() uint64 {
 	h := fnv.New64a()
@@ -114,6 +177,12 @@ func (t *Target) DiscoveredLabels() labels.Labels {
 	return lset
 }
 
+// DroppedBy returns the index in the scrape config and the relabel
+// configuration that dropped the target, or -1 and nil if it was not dropped.
+func (t *Target) DroppedBy() (int, *config.RelabelConfig) {
+	return t.dropIndex, t.dropConfig
+}
+
 // URL returns a copy of the target's URL.
 func (t *Target) URL() *url.URL {
 	params := url.Values{}
@@ -155,6 +224,7 @@ func (t *Target) report(start time.Time, dur time.Duration, err error) {
 
 	t.lastError = err
 	t.lastScrape = start
+	t.lastScrapeDuration = dur
 }
 
 // LastError returns the error encountered during the last scrape.
@@ -173,6 +243,14 @@ func (t *Target) LastScrape() time.Time {
 	return t.lastScrape
 }
 
+// LastScrapeDuration returns how long the last scrape of the target took.
+func (t *Target) LastScrapeDuration() time.Duration {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+
+	return t.lastScrapeDuration
+}
+
 // Health returns the last known health state of the target.
 func (t *Target) Health() TargetHealth {
 	t.mtx.RLock()
@@ -223,6 +301,10 @@ func (app *limitAppender) AddFast(lset labels.Labels, ref uint64, t int64, v flo
 	return err
 }
 
+func (app *limitAppender) AddBatch(samples []storage.Sample) error {
+	return storage.AppendBatch(app, samples)
+}
+
 type timeLimitAppender struct {
 	storage.Appender
 
@@ -249,10 +331,16 @@ func (app *timeLimitAppender) AddFast(lset labels.Labels, ref uint64, t int64, v
 	return err
 }
 
+func (app *timeLimitAppender) AddBatch(samples []storage.Sample) error {
+	return storage.AppendBatch(app, samples)
+}
+
 // populateLabels builds a label set from the given label set and scrape configuration.
 // It returns a label set before relabeling was applied as the second return value.
-// Returns a nil label set if the target is dropped during relabeling.
-func populateLabels(lset labels.Labels, cfg *config.ScrapeConfig) (res, orig labels.Labels, err error) {
+// Returns a nil label set if the target is dropped during relabeling, along with
+// the label set before relabeling and the index of the relabel configuration
+// that dropped it.
+func populateLabels(lset labels.Labels, cfg *config.ScrapeConfig) (res, orig labels.Labels, dropIndex int, err error) {
 	// Copy labels into the labelset for the target if they are not set already.
 	scrapeLabels := []labels.Label{
 		{Name: model.JobLabel, Value: cfg.JobName},
@@ -274,14 +362,14 @@ func populateLabels(lset labels.Labels, cfg *config.ScrapeConfig) (res, orig lab
 	}
 
 	preRelabelLabels := lb.Labels()
-	lset = relabel.Process(preRelabelLabels, cfg.RelabelConfigs...)
+	lset, dropIndex = relabel.ProcessDropped(preRelabelLabels, cfg.RelabelConfigs...)
 
 	// Check if the target was dropped.
 	if lset == nil {
-		return nil, nil, nil
+		return nil, preRelabelLabels, dropIndex, nil
 	}
 	if v := lset.Get(model.AddressLabel); v == "" {
-		return nil, nil, fmt.Errorf("no address")
+		return nil, nil, -1, fmt.Errorf("no address")
 	}
 
 	lb = labels.NewBuilder(lset)
@@ -308,13 +396,13 @@ func populateLabels(lset labels.Labels, cfg *config.ScrapeConfig) (res, orig lab
 		case "https":
 			addr = addr + ":443"
 		default:
-			return nil, nil, fmt.Errorf("invalid scheme: %q", cfg.Scheme)
+			return nil, nil, -1, fmt.Errorf("invalid scheme: %q", cfg.Scheme)
 		}
 		lb.Set(model.AddressLabel, addr)
 	}
 
 	if err := config.CheckTargetAddress(model.LabelValue(addr)); err != nil {
-		return nil, nil, err
+		return nil, nil, -1, err
 	}
 
 	// Meta labels are deleted after relabelling. Other internal labels propagate to
@@ -334,15 +422,17 @@ func populateLabels(lset labels.Labels, cfg *config.ScrapeConfig) (res, orig lab
 	for _, l := range res {
 		// Check label values are valid, drop the target if not.
 		if !model.LabelValue(l.Value).IsValid() {
-			return nil, nil, fmt.Errorf("invalid label value for %q: %q", l.Name, l.Value)
+			return nil, nil, -1, fmt.Errorf("invalid label value for %q: %q", l.Name, l.Value)
 		}
 	}
-	return res, preRelabelLabels, nil
+	return res, preRelabelLabels, -1, nil
 }
 
 // targetsFromGroup builds targets based on the given TargetGroup and config.
-func targetsFromGroup(tg *config.TargetGroup, cfg *config.ScrapeConfig) ([]*Target, error) {
-	targets := make([]*Target, 0, len(tg.Targets))
+// Targets dropped during relabeling are returned separately and only hold
+// their discovered labels and the relabel configuration that dropped them.
+func targetsFromGroup(tg *config.TargetGroup, cfg *config.ScrapeConfig) (targets, dropped []*Target, err error) {
+	targets = make([]*Target, 0, len(tg.Targets))
 
 	for i, tlset := range tg.Targets {
 		lbls := make([]labels.Label, 0, len(tlset)+len(tg.Labels))
@@ -358,13 +448,15 @@ func targetsFromGroup(tg *config.TargetGroup, cfg *config.ScrapeConfig) ([]*Targ
 
 		lset := labels.New(lbls...)
 
-		lbls, origLabels, err := populateLabels(lset, cfg)
+		lbls, origLabels, dropIndex, err := populateLabels(lset, cfg)
 		if err != nil {
-			return nil, fmt.Errorf("instance %d in group %s: %s", i, tg, err)
+			return nil, nil, fmt.Errorf("instance %d in group %s: %s", i, tg, err)
 		}
 		if lbls != nil {
 			targets = append(targets, NewTarget(lbls, origLabels, cfg.Params))
+		} else {
+			dropped = append(dropped, NewDroppedTarget(origLabels, cfg.Params, dropIndex, cfg.RelabelConfigs[dropIndex]))
 		}
 	}
-	return targets, nil
+	return targets, dropped, nil
 }
@@ -39,6 +39,8 @@ import (
 const (
 	// AlertMetricName is the metric name for synthetic alert timeseries.
 	alertMetricName = "ALERTS"
+	// AlertForStateMetricName is the metric name for 'for' state of alert.
+	alertForStateMetricName = "ALERTS_FOR_STATE"
 
 	// AlertNameLabel is the label name indicating the name of an alert.
 	alertNameLabel = "alertname"
@@ -99,12 +101,23 @@ type AlertingRule struct {
 	labels labels.Labels
 	// Non-identifying key/value pairs.
 	annotations labels.Labels
+	// Warnings about the vector matching of the expression.
+	warnings []error
 
 	// Protects the below.
 	mtx sync.Mutex
 	// A map of alerts which are currently active (Pending or Firing), keyed by
 	// the fingerprint of the labelset they correspond to.
 	active map[uint64]*Alert
+	// The health of the alerting rule and the error of its last evaluation.
+	health    RuleHealth
+	lastError error
+	// The duration and the timestamp of the last evaluation.
+	evaluationDuration time.Duration
+	lastEvaluation     time.Time
+	// Whether the 'for' state of the active alerts has been restored after
+	// a restart. No samples are produced until it has.
+	restored bool
 
 	logger log.Logger
 }
@@ -117,7 +130,10 @@ func NewAlertingRule(name string, vec promql.Expr, hold time.Duration, lbls, ann
 		holdDuration: hold,
 		labels:       lbls,
 		annotations:  anns,
+		warnings:     promql.Check(vec),
 		active:       map[uint64]*Alert{},
+		health:       HealthUnknown,
+		restored:     true,
 		logger:       logger,
 	}
 }
@@ -127,6 +143,104 @@ func (r *AlertingRule) Name() string {
 	return r.name
 }
 
+// Query returns the query expression of the alerting rule.
+func (r *AlertingRule) Query() promql.Expr {
+	return r.vector
+}
+
+// Warnings returns the warnings about the query expression of the alerting
+// rule.
+func (r *AlertingRule) Warnings() []error {
+	return r.warnings
+}
+
+// Duration returns the hold duration of the alerting rule.
+func (r *AlertingRule) Duration() time.Duration {
+	return r.holdDuration
+}
+
+// Labels returns the labels of the alerting rule.
+func (r *AlertingRule) Labels() labels.Labels {
+	return r.labels
+}
+
+// Annotations returns the annotations of the alerting rule.
+func (r *AlertingRule) Annotations() labels.Labels {
+	return r.annotations
+}
+
+// SetHealth sets the health of the rule.
+func (r *AlertingRule) SetHealth(health RuleHealth) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.health = health
+}
+
+// Health returns the health of the rule.
+func (r *AlertingRule) Health() RuleHealth {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.health
+}
+
+// SetLastError sets the error of the last evaluation of the rule.
+func (r *AlertingRule) SetLastError(err error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.lastError = err
+}
+
+// LastError returns the error of the last evaluation of the rule.
+func (r *AlertingRule) LastError() error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.lastError
+}
+
+// SetEvaluationDuration sets the duration of the last evaluation of the rule.
+func (r *AlertingRule) SetEvaluationDuration(d time.Duration) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.evaluationDuration = d
+}
+
+// EvaluationDuration returns the duration of the last evaluation of the rule.
+func (r *AlertingRule) EvaluationDuration() time.Duration {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.evaluationDuration
+}
+
+// SetLastEvaluation sets the timestamp of the last evaluation of the rule.
+func (r *AlertingRule) SetLastEvaluation(ts time.Time) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.lastEvaluation = ts
+}
+
+// LastEvaluation returns the timestamp of the last evaluation of the rule.
+func (r *AlertingRule) LastEvaluation() time.Time {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.lastEvaluation
+}
+
+// SetRestored sets whether the 'for' state of the rule's alerts has been
+// restored.
+func (r *AlertingRule) SetRestored(restored bool) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.restored = restored
+}
+
+// Restored returns whether the 'for' state of the rule's alerts has been
+// restored.
+func (r *AlertingRule) Restored() bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.restored
+}
+
 func (r *AlertingRule) equal(o *AlertingRule) bool {
 	return r.name == o.name && labels.Equal(r.labels, o.labels)
 }
@@ -149,13 +263,34 @@ func (r *AlertingRule) sample(alert *Alert, ts time.Time) promql.Sample {
 	return s
 }
 
+// forStateSample returns the sample for ALERTS_FOR_STATE, which records the
+// time the alert became active as its value.
+func (r *AlertingRule) forStateSample(alert *Alert, ts time.Time, v float64) promql.Sample {
+	lb := labels.NewBuilder(r.labels)
+
+	for _, l := range alert.Labels {
+		lb.Set(l.Name, l.Value)
+	}
+
+	lb.Set(labels.MetricName, alertForStateMetricName)
+	lb.Set(labels.AlertName, r.name)
+
+	s := promql.Sample{
+		Metric: lb.Labels(),
+		Point:  promql.Point{T: timestamp.FromTime(ts), V: v},
+	}
+	return s
+}
+
 // resolvedRetention is the duration for which a resolved alert instance
 // is kept in memory state and consequentally repeatedly sent to the AlertManager.
 const resolvedRetention = 15 * time.Minute
 
 // Eval evaluates the rule expression and then creates pending alerts and fires
 // or removes previously pending alerts accordingly.
-func (r *AlertingRule) Eval(ctx context.Context, ts time.Time, engine *promql.Engine, externalURL *url.URL) (promql.Vector, error) {
+// If the rule has more than limit active alerts, they are dropped and the
+// evaluation fails, unless limit is 0.
+func (r *AlertingRule) Eval(ctx context.Context, ts time.Time, engine *promql.Engine, externalURL *url.URL, limit int) (promql.Vector, error) {
 	query, err := engine.NewInstantQuery(r.vector.String(), ts)
 	if err != nil {
 		return nil, err
@@ -240,7 +375,10 @@ func (r *AlertingRule) Eval(ctx context.Context, ts time.Time, engine *promql.En
 		}
 	}
 
-	var vec promql.Vector
+	var (
+		vec       promql.Vector
+		numActive int
+	)
 	// Check if any pending alerts should be removed or fire now. Write out alert timeseries.
 	for fp, a := range r.active {
 		if _, ok := resultFPs[fp]; !ok {
@@ -255,12 +393,23 @@ func (r *AlertingRule) Eval(ctx context.Context, ts time.Time, engine *promql.En
 			}
 			continue
 		}
+		numActive++
 
 		if a.State == StatePending && ts.Sub(a.ActiveAt) >= r.holdDuration {
 			a.State = StateFiring
 		}
 
-		vec = append(vec, r.sample(a, ts))
+		// Writing samples before the 'for' state is restored would overwrite
+		// the state persisted before a restart.
+		if r.restored {
+			vec = append(vec, r.sample(a, ts))
+			vec = append(vec, r.forStateSample(a, ts, float64(a.ActiveAt.Unix())))
+		}
+	}
+
+	if limit > 0 && numActive > limit {
+		r.active = map[uint64]*Alert{}
+		return nil, fmt.Errorf("exceeded limit of %d with %d alerts", limit, numActive)
 	}
 
 	return vec, nil
@@ -281,6 +430,17 @@ func (r *AlertingRule) State() AlertState {
 	return maxState
 }
 
+// forEachActiveAlert calls f for each of the rule's active alerts. The alerts
+// may be modified.
+func (r *AlertingRule) forEachActiveAlert(f func(*Alert)) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	for _, a := range r.active {
+		f(a)
+	}
+}
+
 // ActiveAlerts returns a slice of active alerts.
 func (r *AlertingRule) ActiveAlerts() []*Alert {
 	var res []*Alert
@@ -0,0 +1,141 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/prometheus/prometheus/pkg/rulefmt"
+)
+
+// managedFileExt is the extension of the files of managed rule groups.
+const managedFileExt = ".yml"
+
+var (
+	// ErrManagedRulesDisabled is returned when changing managed rule groups
+	// without a managed rules directory.
+	ErrManagedRulesDisabled = errors.New("managed rule groups disabled")
+	// ErrManagedGroupNotFound is returned when deleting a managed rule group
+	// that does not exist.
+	ErrManagedGroupNotFound = errors.New("managed rule group not found")
+)
+
+// SetManagedGroup creates or replaces the managed rule group with the name
+// of rg and reloads the rules. The group is persisted in its own file in the
+// managed rules directory. The previous file is restored if the rules fail
+// to reload.
+func (m *Manager) SetManagedGroup(rg rulefmt.RuleGroup) error {
+	if m.opts.ManagedDir == "" {
+		return ErrManagedRulesDisabled
+	}
+	rgs := rulefmt.RuleGroups{Groups: []rulefmt.RuleGroup{rg}}
+	if errs := rgs.Validate(); len(errs) > 0 {
+		return fmt.Errorf("invalid rule group: %s", errs[0])
+	}
+	b, err := yaml.Marshal(rgs)
+	if err != nil {
+		return err
+	}
+
+	m.managedMtx.Lock()
+	defer m.managedMtx.Unlock()
+
+	if err := os.MkdirAll(m.opts.ManagedDir, 0777); err != nil {
+		return err
+	}
+	fn := m.managedFile(rg.Name)
+	prev, err := ioutil.ReadFile(fn)
+	existed := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := writeManagedFile(fn, b); err != nil {
+		return err
+	}
+	if err := m.reloadManaged(); err != nil {
+		if existed {
+			writeManagedFile(fn, prev)
+		} else {
+			os.Remove(fn)
+		}
+		return err
+	}
+	return nil
+}
+
+// DeleteManagedGroup deletes the managed rule group with the given name and
+// reloads the rules. The file of the group is restored if the rules fail to
+// reload.
+func (m *Manager) DeleteManagedGroup(name string) error {
+	if m.opts.ManagedDir == "" {
+		return ErrManagedRulesDisabled
+	}
+
+	m.managedMtx.Lock()
+	defer m.managedMtx.Unlock()
+
+	fn := m.managedFile(name)
+	prev, err := ioutil.ReadFile(fn)
+	if os.IsNotExist(err) {
+		return ErrManagedGroupNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(fn); err != nil {
+		return err
+	}
+	if err := m.reloadManaged(); err != nil {
+		writeManagedFile(fn, prev)
+		return err
+	}
+	return nil
+}
+
+// reloadManaged reloads the rules with the configuration applied last.
+// Without one, the managed rule groups are loaded along with the first
+// configuration.
+func (m *Manager) reloadManaged() error {
+	m.mtx.RLock()
+	conf := m.config
+	m.mtx.RUnlock()
+
+	if conf == nil {
+		return nil
+	}
+	return m.ApplyConfig(conf)
+}
+
+// managedFile returns the file of the managed rule group with the given name.
+// The name is escaped as group names may contain path separators.
+func (m *Manager) managedFile(name string) string {
+	return filepath.Join(m.opts.ManagedDir, url.PathEscape(name)+managedFileExt)
+}
+
+// writeManagedFile replaces the content of fn atomically. The temporary file
+// does not have the extension of managed files and is never loaded.
+func writeManagedFile(fn string, b []byte) error {
+	tmp := fn + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0666); err != nil {
+		return err
+	}
+	return os.Rename(tmp, fn)
+}
@@ -44,15 +44,14 @@ import (
 // Constants for instrumentation.
 const namespace = "prometheus"
 
+// DefaultDurationBuckets are the buckets of the rule evaluation duration
+// histograms unless set otherwise with SetDurationBuckets.
+var DefaultDurationBuckets = []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60}
+
 var (
-	evalDuration = prometheus.NewSummaryVec(
-		prometheus.SummaryOpts{
-			Namespace: namespace,
-			Name:      "rule_evaluation_duration_seconds",
-			Help:      "The duration for a rule to execute.",
-		},
-		[]string{"rule_type"},
-	)
+	evalDuration      = newEvalDuration(DefaultDurationBuckets)
+	iterationDuration = newIterationDuration(DefaultDurationBuckets)
+
 	evalFailures = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: namespace,
@@ -69,12 +68,6 @@ var (
 		},
 		[]string{"rule_type"},
 	)
-	iterationDuration = prometheus.NewSummary(prometheus.SummaryOpts{
-		Namespace:  namespace,
-		Name:       "evaluator_duration_seconds",
-		Help:       "The duration of rule group evaluations.",
-		Objectives: map[float64]float64{0.01: 0.001, 0.05: 0.005, 0.5: 0.05, 0.90: 0.01, 0.99: 0.001},
-	})
 	iterationsSkipped = prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace: namespace,
 		Name:      "evaluator_iterations_skipped_total",
@@ -106,6 +99,44 @@ func init() {
 	prometheus.MustRegister(evalDuration)
 }
 
+func newEvalDuration(buckets []float64) *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "rule_evaluation_duration_seconds",
+			Help:      "The duration for a rule to execute.",
+			Buckets:   buckets,
+		},
+		[]string{"rule_group", "rule_type"},
+	)
+}
+
+func newIterationDuration(buckets []float64) *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "evaluator_duration_seconds",
+			Help:      "The duration of rule group evaluations.",
+			Buckets:   buckets,
+		},
+		[]string{"rule_group"},
+	)
+}
+
+// SetDurationBuckets replaces the histograms of rule and rule group
+// evaluation durations with ones using the given buckets. It must be called
+// before any rule group is evaluated.
+func SetDurationBuckets(buckets []float64) {
+	prometheus.Unregister(evalDuration)
+	prometheus.Unregister(iterationDuration)
+
+	evalDuration = newEvalDuration(buckets)
+	iterationDuration = newIterationDuration(buckets)
+
+	prometheus.MustRegister(evalDuration)
+	prometheus.MustRegister(iterationDuration)
+}
+
 type ruleType string
 
 const (
@@ -113,17 +144,51 @@ const (
 	ruleTypeRecording = "recording"
 )
 
+// RuleHealth describes the health state of a rule.
+type RuleHealth string
+
+// The possible health states of a rule based on its last evaluation.
+const (
+	HealthUnknown RuleHealth = "unknown"
+	HealthGood    RuleHealth = "ok"
+	HealthBad     RuleHealth = "err"
+)
+
 // A Rule encapsulates a vector expression which is evaluated at a specified
 // interval and acted upon (currently either recorded or used for alerting).
 type Rule interface {
 	Name() string
+	// Query returns the rule's query expression.
+	Query() promql.Expr
+	// Labels returns the labels attached to the rule's results.
+	Labels() labels.Labels
+	// Warnings returns the warnings about suspicious vector matching in the
+	// rule's query expression.
+	Warnings() []error
 	// eval evaluates the rule, including any associated recording or alerting actions.
-	Eval(context.Context, time.Time, *promql.Engine, *url.URL) (promql.Vector, error)
+	// It fails if the result has more than limit elements, unless limit is 0.
+	Eval(ctx context.Context, ts time.Time, engine *promql.Engine, externalURL *url.URL, limit int) (promql.Vector, error)
 	// String returns a human-readable string representation of the rule.
 	String() string
 	// HTMLSnippet returns a human-readable string representation of the rule,
 	// decorated with HTML elements for use the web frontend.
 	HTMLSnippet(pathPrefix string) html_template.HTML
+
+	// SetHealth and Health track the health of the rule based on its last
+	// evaluation.
+	SetHealth(RuleHealth)
+	Health() RuleHealth
+	// SetLastError and LastError track the error of the last evaluation.
+	SetLastError(error)
+	LastError() error
+	// SetEvaluationDuration and EvaluationDuration track how long the last
+	// evaluation took.
+	SetEvaluationDuration(time.Duration)
+	EvaluationDuration() time.Duration
+	// SetLastEvaluation and LastEvaluation track when the rule was last
+	// evaluated.
+	SetLastEvaluation(time.Time)
+	LastEvaluation() time.Time
 }
 
 // Group is a set of rules that have a logical relation.
@@ -131,9 +196,25 @@ type Group struct {
 	name                 string
 	file                 string
 	interval             time.Duration
+	limit                int
+	externalLabels       labels.Labels
 	rules                []Rule
 	seriesInPreviousEval []map[string]labels.Labels // One per Rule.
 	opts                 *ManagerOptions
+	// Whether the 'for' state of alerts has to be restored from storage
+	// before the group's alerting rules produce samples.
+	shouldRestore bool
+
+	// Shared with the groups that read the output of this group or whose
+	// output this group reads, so that they are not evaluated concurrently.
+	evalMtx *sync.Mutex
+	// Bounds the number of groups evaluated concurrently, nil if unbounded.
+	evalSem chan struct{}
+
+	// Protects the evaluation timing of the group.
+	mtx                sync.Mutex
+	evaluationDuration time.Duration
+	lastEvaluation     time.Time
 
 	done       chan struct{}
 	terminated chan struct{}
@@ -165,6 +246,39 @@ func (g *Group) File() string { return g.file }
 // Rules returns the group's rules.
 func (g *Group) Rules() []Rule { return g.rules }
 
+// Interval returns the group's evaluation interval.
+func (g *Group) Interval() time.Duration { return g.interval }
+
+// Limit returns the maximum number of series or alerts a rule of the group
+// may produce, 0 meaning no limit.
+func (g *Group) Limit() int { return g.limit }
+
+// ExternalLabels returns the labels added to the alerts and series of the
+// group's rules that do not have them already.
+func (g *Group) ExternalLabels() labels.Labels { return g.externalLabels }
+
+// EvaluationDuration returns the time it took to evaluate the group's rules
+// in the last iteration.
+func (g *Group) EvaluationDuration() time.Duration {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	return g.evaluationDuration
+}
+
+// LastEvaluation returns the time the last evaluation of the group started.
+func (g *Group) LastEvaluation() time.Time {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	return g.lastEvaluation
+}
+
+func (g *Group) setEvaluationTiming(start time.Time, d time.Duration) {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	g.lastEvaluation = start
+	g.evaluationDuration = d
+}
+
 func (g *Group) run() {
 	defer close(g.terminated)
 
@@ -178,10 +292,15 @@ func (g *Group) run() {
 	iter := func() {
 		iterationsScheduled.Inc()
 
+		unlock := g.lockEval()
+		defer unlock()
+
 		start := time.Now()
 		g.Eval(start)
 
-		iterationDuration.Observe(time.Since(start).Seconds())
+		d := time.Since(start)
+		iterationDuration.WithLabelValues(groupKey(g.name, g.file)).Observe(d.Seconds())
+		g.setEvaluationTiming(start, d)
 	}
 	lastTriggered := time.Now()
 	iter()
@@ -189,6 +308,26 @@ func (g *Group) run() {
 	tick := time.NewTicker(g.interval)
 	defer tick.Stop()
 
+	if g.shouldRestore {
+		// Wait for another evaluation before restoring. The first one may
+		// have happened before enough data was scraped and before recording
+		// rules that alerts depend on were updated.
+		select {
+		case <-g.done:
+			return
+		case <-tick.C:
+			missed := (time.Since(lastTriggered).Nanoseconds() / g.interval.Nanoseconds()) - 1
+			if missed > 0 {
+				iterationsMissed.Add(float64(missed))
+				iterationsScheduled.Add(float64(missed))
+			}
+			lastTriggered = time.Now()
+			iter()
+		}
+		g.RestoreForState(time.Now())
+		g.shouldRestore = false
+	}
+
 	for {
 		select {
 		case <-g.done:
@@ -210,6 +349,26 @@ func (g *Group) run() {
 	}
 }
 
+// lockEval blocks until the group may be evaluated and returns a function
+// to call once the evaluation is done.
+func (g *Group) lockEval() func() {
+	// Wait for dependent groups first to not take a slot while waiting.
+	if g.evalMtx != nil {
+		g.evalMtx.Lock()
+	}
+	if g.evalSem != nil {
+		g.evalSem <- struct{}{}
+	}
+	return func() {
+		if g.evalSem != nil {
+			<-g.evalSem
+		}
+		if g.evalMtx != nil {
+			g.evalMtx.Unlock()
+		}
+	}
+}
+
 func (g *Group) stop() {
 	close(g.done)
 	<-g.terminated
@@ -299,13 +458,18 @@ func (g *Group) Eval(ts time.Time) {
 
 		func(i int, rule Rule) {
 			defer func(t time.Time) {
-				evalDuration.WithLabelValues(rtyp).Observe(time.Since(t).Seconds())
+				d := time.Since(t)
+				evalDuration.WithLabelValues(groupKey(g.name, g.file), rtyp).Observe(d.Seconds())
+				rule.SetEvaluationDuration(d)
+				rule.SetLastEvaluation(t)
 			}(time.Now())
 
 			evalTotal.WithLabelValues(rtyp).Inc()
 
-			vector, err := rule.Eval(g.opts.Context, ts, g.opts.QueryEngine, g.opts.ExternalURL)
+			vector, err := rule.Eval(g.opts.Context, ts, g.opts.QueryEngine, g.opts.ExternalURL, g.limit)
+			rule.SetLastError(err)
 			if err != nil {
+				rule.SetHealth(HealthBad)
 				// Canceled queries are intentional termination of queries. This normally
 				// happens on shutdown and thus we skip logging of any errors here.
 				if _, ok := err.(promql.ErrQueryCanceled); !ok {
@@ -314,6 +478,11 @@ func (g *Group) Eval(ts time.Time) {
 				evalFailures.WithLabelValues(rtyp).Inc()
 				return
 			}
+			rule.SetHealth(HealthGood)
+
+			for j := range vector {
+				vector[j].Metric = g.addExternalLabels(vector[j].Metric)
+			}
 
 			if ar, ok := rule.(*AlertingRule); ok {
 				g.sendAlerts(ar)
@@ -376,6 +545,185 @@ func (g *Group) Eval(ts time.Time) {
 	}
 }
 
+// markStale appends stale markers at ts for the series the group's rules
+// returned in their previous evaluation, except for those in keep. It is used
+// for the rules of a stopped group that are not continued by another one.
+func (g *Group) markStale(ts time.Time, keep map[string]struct{}) {
+	var stale []labels.Labels
+	for _, series := range g.seriesInPreviousEval {
+		for metric, lset := range series {
+			if _, ok := keep[metric]; !ok {
+				stale = append(stale, lset)
+			}
+		}
+	}
+	if len(stale) == 0 {
+		return
+	}
+	app, err := g.opts.Appendable.Appender()
+	if err != nil {
+		level.Warn(g.logger).Log("msg", "creating appender failed", "err", err)
+		return
+	}
+	for _, lset := range stale {
+		_, err := app.Add(lset, timestamp.FromTime(ts), math.Float64frombits(value.StaleNaN))
+		switch err {
+		case nil:
+		case storage.ErrOutOfOrderSample, storage.ErrDuplicateSampleForTimestamp:
+			// The series may be exposed by a rule of another group.
+		default:
+			level.Warn(g.logger).Log("msg", "adding stale sample failed", "sample", lset, "err", err)
+		}
+	}
+	if err := app.Commit(); err != nil {
+		level.Warn(g.logger).Log("msg", "stale sample appending failed", "err", err)
+	}
+}
+
+// seriesKeys returns the series the group's rules returned in their previous
+// evaluation.
+func (g *Group) seriesKeys() map[string]struct{} {
+	keys := map[string]struct{}{}
+	for _, series := range g.seriesInPreviousEval {
+		for metric := range series {
+			keys[metric] = struct{}{}
+		}
+	}
+	return keys
+}
+
+// RestoreForState restores the 'for' state of the alerts of the group's
+// alerting rules from the ALERTS_FOR_STATE series in storage. The time the
+// alerts were down is not counted towards their pending duration.
+func (g *Group) RestoreForState(ts time.Time) {
+	var alertRules []*AlertingRule
+	for _, rule := range g.rules {
+		if ar, ok := rule.(*AlertingRule); ok {
+			alertRules = append(alertRules, ar)
+		}
+	}
+	defer func() {
+		for _, ar := range alertRules {
+			ar.SetRestored(true)
+		}
+	}()
+
+	if g.opts.Queryable == nil {
+		return
+	}
+	// Alerts are only restored if they were active within the outage tolerance.
+	mint := timestamp.FromTime(ts.Add(-g.opts.OutageTolerance))
+	q, err := g.opts.Queryable.Querier(g.opts.Context, mint, timestamp.FromTime(ts))
+	if err != nil {
+		level.Error(g.logger).Log("msg", "Failed to get querier for restoring alert state", "err", err)
+		return
+	}
+	defer q.Close()
+
+	for _, ar := range alertRules {
+		hold := ar.Duration()
+		if hold < g.opts.ForGracePeriod {
+			// Restoring would make the alert wait for at least the grace
+			// period, which is longer than its own hold duration.
+			continue
+		}
+
+		ar.forEachActiveAlert(func(a *Alert) {
+			if a.State == StateInactive {
+				return
+			}
+			smpl := ar.forStateSample(a, ts, 0)
+			// The stored series carry the external labels of the group.
+			smpl.Metric = g.addExternalLabels(smpl.Metric)
+			matchers := make([]*labels.Matcher, 0, len(smpl.Metric))
+			for _, l := range smpl.Metric {
+				m, err := labels.NewMatcher(labels.MatchEqual, l.Name, l.Value)
+				if err != nil {
+					level.Error(g.logger).Log("msg", "Failed to create matcher for restoring alert state", "err", err)
+					return
+				}
+				matchers = append(matchers, m)
+			}
+
+			var series storage.Series
+			ss, _, err := q.Select(nil, matchers...)
+			if err != nil {
+				level.Error(g.logger).Log("msg", "Failed to select series for restoring alert state", "alert", a.Labels, "err", err)
+				return
+			}
+			for ss.Next() {
+				// The series contains all the matched labels, so equal length
+				// means equal label sets.
+				if len(ss.At().Labels()) == len(smpl.Metric) {
+					series = ss.At()
+					break
+				}
+			}
+			if err := ss.Err(); err != nil {
+				level.Error(g.logger).Log("msg", "Failed to select series for restoring alert state", "alert", a.Labels, "err", err)
+				return
+			}
+			if series == nil {
+				return
+			}
+
+			var (
+				t  int64
+				v  float64
+				ok bool
+			)
+			it := series.Iterator()
+			for it.Next() {
+				t, v = it.At()
+				ok = true
+			}
+			if err := it.Err(); err != nil {
+				level.Error(g.logger).Log("msg", "Failed to iterate series for restoring alert state", "alert", a.Labels, "err", err)
+				return
+			}
+			if !ok || value.IsStaleNaN(v) {
+				// The alert was not active before the restart.
+				return
+			}
+
+			var (
+				downAt    = timestamp.Time(t)
+				activeAt  = time.Unix(int64(v), 0)
+				remaining = hold - downAt.Sub(activeAt)
+			)
+			switch {
+			case remaining <= 0:
+				// The alert was firing before the restart and fires again at
+				// the next evaluation if it still holds.
+			case remaining < g.opts.ForGracePeriod:
+				// Give the alert at least the grace period before it fires.
+				activeAt = ts.Add(g.opts.ForGracePeriod).Add(-hold)
+			default:
+				// Shift the active time by the time the alert was down.
+				activeAt = activeAt.Add(ts.Sub(downAt))
+			}
+			a.ActiveAt = activeAt
+
+			level.Debug(g.logger).Log("msg", "'for' state restored", "alert", a.Labels, "active_at", activeAt)
+		})
+	}
+}
+
+// addExternalLabels returns the labels with the external labels of the group
+// added, unless they are already set.
+func (g *Group) addExternalLabels(lset labels.Labels) labels.Labels {
+	if len(g.externalLabels) == 0 {
+		return lset
+	}
+	lb := labels.NewBuilder(lset)
+	for _, l := range g.externalLabels {
+		if lset.Get(l.Name) == "" {
+			lb.Set(l.Name, l.Value)
+		}
+	}
+	return lb.Labels()
+}
+
 // sendAlerts sends alert notifications for the given rule.
 func (g *Group) sendAlerts(rule *AlertingRule) error {
 	var alerts []*notifier.Alert
@@ -388,7 +736,7 @@ func (g *Group) sendAlerts(rule *AlertingRule) error {
 
 		a := &notifier.Alert{
 			StartsAt:     alert.ActiveAt.Add(rule.holdDuration),
-			Labels:       alert.Labels,
+			Labels:       g.addExternalLabels(alert.Labels),
 			Annotations:  alert.Annotations,
 			GeneratorURL: g.opts.ExternalURL.String() + strutil.TableLinkForExpression(rule.vector.String()),
 		}
@@ -399,7 +747,8 @@ func (g *Group) sendAlerts(rule *AlertingRule) error {
 		alerts = append(alerts, a)
 	}
 
-	if len(alerts) > 0 {
+	// There is no notifier when rules are evaluated in unit tests.
+	if len(alerts) > 0 && g.opts.Notifier != nil {
 		g.opts.Notifier.Send(alerts...)
 	}
 
@@ -408,10 +757,17 @@ func (g *Group) sendAlerts(rule *AlertingRule) error {
 
 // The Manager manages recording and alerting rules.
 type Manager struct {
-	opts   *ManagerOptions
-	groups map[string]*Group
-	mtx    sync.RWMutex
-	block  chan struct{}
+	opts     *ManagerOptions
+	groups   map[string]*Group
+	mtx      sync.RWMutex
+	block    chan struct{}
+	restored bool
+	evalSem  chan struct{}
+	// The configuration applied last, which managed rule groups are
+	// reloaded with.
+	config *config.Config
+	// Serializes changes of managed rule groups.
+	managedMtx sync.Mutex
 
 	logger log.Logger
 }
@@ -421,6 +777,11 @@ type Appendable interface {
 	Appender() (storage.Appender, error)
 }
 
+// Queryable returns a Querier.
+type Queryable interface {
+	Querier(ctx context.Context, mint, maxt int64) (storage.Querier, error)
+}
+
 // ManagerOptions bundles options for the Manager.
 type ManagerOptions struct {
 	ExternalURL *url.URL
@@ -429,17 +790,39 @@ type ManagerOptions struct {
 	Notifier    *notifier.Notifier
 	Appendable  Appendable
 	Logger      log.Logger
+
+	// Queryable is used to restore the 'for' state of alerts on startup.
+	// Restoring is skipped if it is nil.
+	Queryable Queryable
+	// OutageTolerance is the maximum time Prometheus may have been down for
+	// the 'for' state of alerts to be restored.
+	OutageTolerance time.Duration
+	// ForGracePeriod is the minimum time a restored alert stays pending
+	// before it fires again.
+	ForGracePeriod time.Duration
+	// MaxConcurrentEvals is the maximum number of rule groups evaluated
+	// concurrently, 0 meaning no limit.
+	MaxConcurrentEvals int
+	// ManagedDir is the directory holding the rule groups managed through
+	// SetManagedGroup and DeleteManagedGroup. They are loaded in addition
+	// to the rule files of the configuration. Managing rule groups is
+	// disabled if it is empty.
+	ManagedDir string
 }
 
 // NewManager returns an implementation of Manager, ready to be started
 // by calling the Run method.
 func NewManager(o *ManagerOptions) *Manager {
-	return &Manager{
+	m := &Manager{
 		groups: map[string]*Group{},
 		opts:   o,
 		block:  make(chan struct{}),
 		logger: o.Logger,
 	}
+	if o.MaxConcurrentEvals > 0 {
+		m.evalSem = make(chan struct{}, o.MaxConcurrentEvals)
+	}
+	return m
 }
 
 // Run starts processing of the rule manager.
@@ -477,15 +860,27 @@ func (m *Manager) ApplyConfig(conf *config.Config) error {
 		}
 		files = append(files, fs...)
 	}
+	if m.opts.ManagedDir != "" {
+		fs, err := filepath.Glob(filepath.Join(m.opts.ManagedDir, "*"+managedFileExt))
+		if err != nil {
+			return fmt.Errorf("error retrieving managed rule files: %s", err)
+		}
+		files = append(files, fs...)
+	}
 
-	// To be replaced with a configurable per-group interval.
-	groups, errs := m.loadGroups(time.Duration(conf.GlobalConfig.EvaluationInterval), files...)
+	// Groups without an explicit interval are evaluated at the global one.
+	// The 'for' state of alerts is only restored for the groups loaded on startup.
+	groups, errs := m.LoadGroups(time.Duration(conf.GlobalConfig.EvaluationInterval), !m.restored, files...)
 	if errs != nil {
 		for _, e := range errs {
 			level.Error(m.logger).Log("msg", "loading groups failed", "err", e)
 		}
 		return errors.New("error loading rules, previous rule set restored")
 	}
+	for key, mtx := range evalLocks(groups) {
+		groups[key].evalMtx = mtx
+		groups[key].evalSem = m.evalSem
+	}
 
 	var wg sync.WaitGroup
 
@@ -502,6 +897,9 @@ func (m *Manager) ApplyConfig(conf *config.Config) error {
 			if ok {
 				oldg.stop()
 				newg.copyState(oldg)
+				// Series of rules which were removed from the group are
+				// marked stale right away.
+				oldg.markStale(time.Now(), newg.seriesKeys())
 			}
 			go func() {
 				// Wait with starting evaluation until the rule manager
@@ -514,21 +912,26 @@ func (m *Manager) ApplyConfig(conf *config.Config) error {
 		}(newg)
 	}
 
-	// Stop remaining old groups.
+	// Stop remaining old groups and mark their series stale.
 	for _, oldg := range m.groups {
 		oldg.stop()
+		oldg.markStale(time.Now(), nil)
+		oldg.deleteMetrics()
 	}
 
 	wg.Wait()
 	m.groups = groups
+	m.restored = true
+	m.config = conf
 
 	return nil
 }
 
-// loadGroups reads groups from a list of files.
-// As there's currently no group syntax a single group named "default" containing
-// all rules will be returned.
-func (m *Manager) loadGroups(interval time.Duration, filenames ...string) (map[string]*Group, []error) {
+// LoadGroups reads groups from a list of files. The groups are keyed by
+// their name and file and are not evaluated until the manager runs them.
+// If shouldRestore is set, the alerting rules of the groups produce no
+// samples until their 'for' state has been restored.
+func (m *Manager) LoadGroups(interval time.Duration, shouldRestore bool, filenames ...string) (map[string]*Group, []error) {
 	groups := make(map[string]*Group)
 
 	for _, fn := range filenames {
@@ -551,14 +954,16 @@ func (m *Manager) loadGroups(interval time.Duration, filenames ...string) (map[s
 				}
 
 				if r.Alert != "" {
-					rules = append(rules, NewAlertingRule(
+					ar := NewAlertingRule(
 						r.Alert,
 						expr,
 						time.Duration(r.For),
 						labels.FromMap(r.Labels),
 						labels.FromMap(r.Annotations),
 						log.With(m.logger, "alert", r.Alert),
-					))
+					)
+					ar.restored = !shouldRestore
+					rules = append(rules, ar)
 					continue
 				}
 				rules = append(rules, NewRecordingRule(
@@ -567,14 +972,144 @@ func (m *Manager) loadGroups(interval time.Duration, filenames ...string) (map[s
 					labels.FromMap(r.Labels),
 				))
 			}
+			for _, r := range rules {
+				for _, w := range r.Warnings() {
+					level.Warn(m.logger).Log("msg", "Suspicious vector matching in rule", "file", fn, "group", rg.Name, "rule", r.Name(), "warning", w)
+				}
+			}
 
-			groups[groupKey(rg.Name, fn)] = NewGroup(rg.Name, fn, itv, rules, m.opts)
+			g := NewGroup(rg.Name, fn, itv, rules, m.opts)
+			g.limit = rg.Limit
+			g.externalLabels = labels.FromMap(rg.ExternalLabels)
+			g.shouldRestore = shouldRestore
+			groups[groupKey(rg.Name, fn)] = g
 		}
 	}
 
 	return groups, nil
 }
 
+// evalLocks returns a lock for each group, keyed like the groups. Groups
+// reading the output of one another, directly or through other groups, share
+// a lock.
+func evalLocks(groups map[string]*Group) map[string]*sync.Mutex {
+	var (
+		keys    = make([]string, 0, len(groups))
+		parents = make(map[string]string, len(groups))
+	)
+	for key := range groups {
+		keys = append(keys, key)
+		parents[key] = key
+	}
+	// Sorting makes the assignment of locks deterministic.
+	sort.Strings(keys)
+
+	var find func(string) string
+	find = func(key string) string {
+		if parents[key] != key {
+			parents[key] = find(parents[key])
+		}
+		return parents[key]
+	}
+
+	outputs := make(map[string]map[string]struct{}, len(groups))
+	for _, key := range keys {
+		outputs[key] = groups[key].outputs()
+	}
+	for _, key := range keys {
+		inputs, all := groups[key].inputs()
+		for _, other := range keys {
+			if other == key || len(outputs[other]) == 0 {
+				continue
+			}
+			dependent := all
+			for name := range inputs {
+				if _, ok := outputs[other][name]; ok {
+					dependent = true
+					break
+				}
+			}
+			if dependent {
+				parents[find(key)] = find(other)
+			}
+		}
+	}
+
+	var (
+		locks = make(map[string]*sync.Mutex, len(groups))
+		roots = make(map[string]*sync.Mutex, len(groups))
+	)
+	for _, key := range keys {
+		root := find(key)
+		mtx, ok := roots[root]
+		if !ok {
+			mtx = &sync.Mutex{}
+			roots[root] = mtx
+		}
+		locks[key] = mtx
+	}
+	return locks
+}
+
+// outputs returns the names of the metrics written by the group's rules.
+func (g *Group) outputs() map[string]struct{} {
+	names := map[string]struct{}{}
+	for _, rule := range g.rules {
+		switch rule.(type) {
+		case *AlertingRule:
+			names[alertMetricName] = struct{}{}
+			names[alertForStateMetricName] = struct{}{}
+		default:
+			names[rule.Name()] = struct{}{}
+		}
+	}
+	return names
+}
+
+// inputs returns the names of the metrics read by the group's rules. If a
+// selector does not match a single metric name, the group may read any
+// metric and all is true.
+func (g *Group) inputs() (names map[string]struct{}, all bool) {
+	names = map[string]struct{}{}
+
+	add := func(name string, matchers []*labels.Matcher) {
+		if name != "" {
+			names[name] = struct{}{}
+			return
+		}
+		for _, m := range matchers {
+			if m.Name == labels.MetricName && m.Type == labels.MatchEqual {
+				names[m.Value] = struct{}{}
+				return
+			}
+		}
+		all = true
+	}
+	for _, rule := range g.rules {
+		if rule.Query() == nil {
+			continue
+		}
+		promql.Inspect(rule.Query(), func(node promql.Node) bool {
+			switch n := node.(type) {
+			case *promql.VectorSelector:
+				add(n.Name, n.LabelMatchers)
+			case *promql.MatrixSelector:
+				add(n.Name, n.LabelMatchers)
+			}
+			return true
+		})
+	}
+	return names, all
+}
+
+// deleteMetrics removes the series of the group's metrics.
+func (g *Group) deleteMetrics() {
+	key := groupKey(g.name, g.file)
+	iterationDuration.DeleteLabelValues(key)
+	evalDuration.DeleteLabelValues(key, ruleTypeAlert)
+	evalDuration.DeleteLabelValues(key, ruleTypeRecording)
+}
+
 // Group names need not be unique across filenames.
 func groupKey(name, file string) string {
 	return name + ";" + file
@@ -591,7 +1126,10 @@ func (m *Manager) RuleGroups() []*Group {
 	}
 
 	sort.Slice(rgs, func(i, j int) bool {
-		return rgs[i].file < rgs[j].file && rgs[i].name < rgs[j].name
+		if rgs[i].file != rgs[j].file {
+			return rgs[i].file < rgs[j].file
+		}
+		return rgs[i].name < rgs[j].name
 	})
 
 	return rgs
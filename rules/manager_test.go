@@ -16,16 +16,23 @@ package rules
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"math"
+	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/model"
 
 	"github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/rulefmt"
 	"github.com/prometheus/prometheus/pkg/timestamp"
 	"github.com/prometheus/prometheus/pkg/value"
 	"github.com/prometheus/prometheus/promql"
@@ -66,18 +73,23 @@ func TestAlertingRule(t *testing.T) {
 			time: 0,
 			result: []string{
 				`{__name__="ALERTS", alertname="HTTPRequestRateLow", alertstate="pending", group="canary", instance="0", job="app-server", severity="critical"} => 1 @[%v]`,
+				`{__name__="ALERTS_FOR_STATE", alertname="HTTPRequestRateLow", group="canary", instance="0", job="app-server", severity="critical"} => 0 @[%v]`,
 				`{__name__="ALERTS", alertname="HTTPRequestRateLow", alertstate="pending", group="canary", instance="1", job="app-server", severity="critical"} => 1 @[%v]`,
+				`{__name__="ALERTS_FOR_STATE", alertname="HTTPRequestRateLow", group="canary", instance="1", job="app-server", severity="critical"} => 0 @[%v]`,
 			},
 		}, {
 			time: 5 * time.Minute,
 			result: []string{
 				`{__name__="ALERTS", alertname="HTTPRequestRateLow", alertstate="firing", group="canary", instance="0", job="app-server", severity="critical"} => 1 @[%v]`,
+				`{__name__="ALERTS_FOR_STATE", alertname="HTTPRequestRateLow", group="canary", instance="0", job="app-server", severity="critical"} => 0 @[%v]`,
 				`{__name__="ALERTS", alertname="HTTPRequestRateLow", alertstate="firing", group="canary", instance="1", job="app-server", severity="critical"} => 1 @[%v]`,
+				`{__name__="ALERTS_FOR_STATE", alertname="HTTPRequestRateLow", group="canary", instance="1", job="app-server", severity="critical"} => 0 @[%v]`,
 			},
 		}, {
 			time: 10 * time.Minute,
 			result: []string{
 				`{__name__="ALERTS", alertname="HTTPRequestRateLow", alertstate="firing", group="canary", instance="0", job="app-server", severity="critical"} => 1 @[%v]`,
+				`{__name__="ALERTS_FOR_STATE", alertname="HTTPRequestRateLow", group="canary", instance="0", job="app-server", severity="critical"} => 0 @[%v]`,
 			},
 		},
 		{
@@ -92,12 +104,14 @@ func TestAlertingRule(t *testing.T) {
 			time: 25 * time.Minute,
 			result: []string{
 				`{__name__="ALERTS", alertname="HTTPRequestRateLow", alertstate="pending", group="canary", instance="0", job="app-server", severity="critical"} => 1 @[%v]`,
+				`{__name__="ALERTS_FOR_STATE", alertname="HTTPRequestRateLow", group="canary", instance="0", job="app-server", severity="critical"} => 1500 @[%v]`,
 			},
 		},
 		{
 			time: 30 * time.Minute,
 			result: []string{
 				`{__name__="ALERTS", alertname="HTTPRequestRateLow", alertstate="firing", group="canary", instance="0", job="app-server", severity="critical"} => 1 @[%v]`,
+				`{__name__="ALERTS_FOR_STATE", alertname="HTTPRequestRateLow", group="canary", instance="0", job="app-server", severity="critical"} => 1500 @[%v]`,
 			},
 		},
 	}
@@ -105,7 +119,7 @@ func TestAlertingRule(t *testing.T) {
 	for i, test := range tests {
 		evalTime := baseTime.Add(test.time)
 
-		res, err := rule.Eval(suite.Context(), evalTime, suite.QueryEngine(), nil)
+		res, err := rule.Eval(suite.Context(), evalTime, suite.QueryEngine(), nil, 0)
 		testutil.Ok(t, err)
 
 		actual := strings.Split(res.String(), "\n")
@@ -173,7 +187,9 @@ func TestStaleness(t *testing.T) {
 	testutil.Ok(t, err)
 	defer querier.Close()
 	matcher, _ := labels.NewMatcher(labels.MatchEqual, model.MetricNameLabel, "a_plus_one")
-	samples, err := readSeriesSet(querier.Select(matcher))
+	set, _, err := querier.Select(nil, matcher)
+	testutil.Ok(t, err)
+	samples, err := readSeriesSet(set)
 	testutil.Ok(t, err)
 	metric := labels.FromStrings(model.MetricNameLabel, "a_plus_one").String()
 	metricSample, ok := samples[metric]
@@ -189,6 +205,78 @@ func TestStaleness(t *testing.T) {
 	testutil.Equals(t, want, samples)
 }
 
+func TestGroupMarkStale(t *testing.T) {
+	storage := testutil.NewStorage(t)
+	defer storage.Close()
+	opts := &ManagerOptions{
+		QueryEngine: promql.NewEngine(storage, nil),
+		Appendable:  storage,
+		Context:     context.Background(),
+		Logger:      log.NewNopLogger(),
+	}
+
+	expr, err := promql.ParseExpr("vector(1)")
+	testutil.Ok(t, err)
+	group := NewGroup("default", "", time.Second, []Rule{
+		NewRecordingRule("kept", expr, labels.Labels{}),
+		NewRecordingRule("removed", expr, labels.Labels{}),
+	}, opts)
+	group.Eval(time.Unix(0, 0))
+
+	kept := labels.FromStrings(model.MetricNameLabel, "kept").String()
+	removed := labels.FromStrings(model.MetricNameLabel, "removed").String()
+	group.markStale(time.Unix(1, 0), map[string]struct{}{kept: {}})
+
+	querier, err := storage.Querier(context.Background(), 0, 2000)
+	testutil.Ok(t, err)
+	defer querier.Close()
+	matcher, _ := labels.NewMatcher(labels.MatchRegexp, model.MetricNameLabel, "kept|removed")
+	set, _, err := querier.Select(nil, matcher)
+	testutil.Ok(t, err)
+	samples, err := readSeriesSet(set)
+	testutil.Ok(t, err)
+
+	testutil.Equals(t, []promql.Point{{0, 1}}, samples[kept])
+	testutil.Equals(t, 2, len(samples[removed]))
+	testutil.Assert(t, value.IsStaleNaN(samples[removed][1].V), "Expected stale marker for removed rule. Got: %x", math.Float64bits(samples[removed][1].V))
+}
+
+func TestRuleHealth(t *testing.T) {
+	storage := testutil.NewStorage(t)
+	defer storage.Close()
+	opts := &ManagerOptions{
+		QueryEngine: promql.NewEngine(storage, nil),
+		Appendable:  storage,
+		Context:     context.Background(),
+		Logger:      log.NewNopLogger(),
+	}
+
+	expr, err := promql.ParseExpr("vector(1)")
+	testutil.Ok(t, err)
+	good := NewRecordingRule("good", expr, labels.Labels{})
+
+	expr, err = promql.ParseExpr(`"foo"`)
+	testutil.Ok(t, err)
+	bad := NewRecordingRule("bad", expr, labels.Labels{})
+
+	for _, r := range []Rule{good, bad} {
+		testutil.Equals(t, HealthUnknown, r.Health())
+	}
+
+	group := NewGroup("default", "", time.Second, []Rule{good, bad}, opts)
+	ts := time.Unix(1, 0)
+	group.Eval(ts)
+
+	testutil.Equals(t, HealthGood, good.Health())
+	testutil.Ok(t, good.LastError())
+	testutil.Equals(t, HealthBad, bad.Health())
+	testutil.NotOk(t, bad.LastError())
+
+	// The evaluation timestamp is the wall clock time the evaluation started at.
+	testutil.Assert(t, !good.LastEvaluation().IsZero(), "evaluation timestamp not set")
+	testutil.Assert(t, !bad.LastEvaluation().IsZero(), "evaluation timestamp not set")
+}
+
 // Convert a SeriesSet into a form useable with reflect.DeepEqual.
 func readSeriesSet(ss storage.SeriesSet) (map[string][]promql.Point, error) {
 	result := map[string][]promql.Point{}
@@ -288,3 +376,404 @@ func TestApplyConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestGroupEvalSequential(t *testing.T) {
+	storage := testutil.NewStorage(t)
+	defer storage.Close()
+	opts := &ManagerOptions{
+		QueryEngine: promql.NewEngine(storage, nil),
+		Appendable:  storage,
+		Context:     context.Background(),
+		Logger:      log.NewNopLogger(),
+	}
+
+	expr, err := promql.ParseExpr("vector(1)")
+	testutil.Ok(t, err)
+	first := NewRecordingRule("first", expr, labels.Labels{})
+
+	// The second rule reads the result of the first one at the same timestamp.
+	expr, err = promql.ParseExpr("first + 1")
+	testutil.Ok(t, err)
+	second := NewRecordingRule("second", expr, labels.Labels{})
+
+	group := NewGroup("default", "", time.Second, []Rule{first, second}, opts)
+	group.Eval(time.Unix(0, 0))
+
+	querier, err := storage.Querier(context.Background(), 0, 0)
+	testutil.Ok(t, err)
+	defer querier.Close()
+	matcher, _ := labels.NewMatcher(labels.MatchEqual, model.MetricNameLabel, "second")
+	set, _, err := querier.Select(nil, matcher)
+	testutil.Ok(t, err)
+	samples, err := readSeriesSet(set)
+	testutil.Ok(t, err)
+
+	want := map[string][]promql.Point{
+		labels.FromStrings(model.MetricNameLabel, "second").String(): []promql.Point{{T: 0, V: 2}},
+	}
+	testutil.Equals(t, want, samples)
+}
+
+func TestLoadGroupsInterval(t *testing.T) {
+	m := NewManager(&ManagerOptions{
+		Context: context.Background(),
+		Logger:  log.NewNopLogger(),
+	})
+	groups, errs := m.LoadGroups(time.Minute, false, "testdata/groups.yml")
+	testutil.Assert(t, errs == nil, "unexpected errors: %v", errs)
+	testutil.Equals(t, 2, len(groups))
+
+	g := groups[groupKey("default", "testdata/groups.yml")]
+	testutil.Assert(t, g != nil, "group default not loaded")
+	testutil.Equals(t, time.Minute, g.Interval())
+
+	g = groups[groupKey("slow", "testdata/groups.yml")]
+	testutil.Assert(t, g != nil, "group slow not loaded")
+	testutil.Equals(t, 5*time.Minute, g.Interval())
+	testutil.Equals(t, 10, g.Limit())
+	testutil.Equals(t, labels.FromStrings("replica", "backfill"), g.ExternalLabels())
+	testutil.Equals(t, 2, len(g.Rules()))
+
+	// Groups start at a stable offset within their interval.
+	for _, g := range groups {
+		off := g.offset()
+		testutil.Assert(t, off >= 0 && off <= g.Interval(), "offset %s outside of interval %s", off, g.Interval())
+	}
+}
+
+func TestForStateRestore(t *testing.T) {
+	suite, err := promql.NewTest(t, `
+		load 1m
+			up{job="app-server", instance="0"}	0+0x60
+	`)
+	testutil.Ok(t, err)
+	defer suite.Close()
+
+	err = suite.Run()
+	testutil.Ok(t, err)
+
+	expr, err := promql.ParseExpr(`up == 0`)
+	testutil.Ok(t, err)
+
+	opts := &ManagerOptions{
+		QueryEngine:     suite.QueryEngine(),
+		Appendable:      suite.Storage(),
+		Queryable:       suite.Storage(),
+		Context:         context.Background(),
+		Logger:          log.NewNopLogger(),
+		OutageTolerance: 30 * time.Minute,
+		ForGracePeriod:  10 * time.Minute,
+	}
+	baseTime := time.Unix(0, 0)
+
+	// Evaluate the alert before the restart, it becomes active at 0 and the
+	// last state is written at 10m.
+	rule := NewAlertingRule("InstanceDown", expr, 30*time.Minute, labels.FromStrings("severity", "page"), nil, nil)
+	group := NewGroup("default", "", time.Minute, []Rule{rule}, opts)
+	for _, d := range []time.Duration{0, 5 * time.Minute, 10 * time.Minute} {
+		group.Eval(baseTime.Add(d))
+	}
+
+	tests := []struct {
+		hold      time.Duration
+		restartAt time.Duration
+		activeAt  time.Duration
+	}{
+		{
+			// The time the alert was down is not counted.
+			hold:      30 * time.Minute,
+			restartAt: 15 * time.Minute,
+			activeAt:  5 * time.Minute,
+		}, {
+			// Less than the grace period was left, the alert fires after it.
+			hold:      15 * time.Minute,
+			restartAt: 15 * time.Minute,
+			activeAt:  10 * time.Minute,
+		}, {
+			// The alert was firing before the restart.
+			hold:      10 * time.Minute,
+			restartAt: 15 * time.Minute,
+			activeAt:  0,
+		}, {
+			// The hold duration is below the grace period, nothing is restored.
+			hold:      5 * time.Minute,
+			restartAt: 15 * time.Minute,
+			activeAt:  15 * time.Minute,
+		}, {
+			// The outage was longer than the tolerance, nothing is restored.
+			hold:      30 * time.Minute,
+			restartAt: 45 * time.Minute,
+			activeAt:  45 * time.Minute,
+		},
+	}
+
+	for i, test := range tests {
+		rule := NewAlertingRule("InstanceDown", expr, test.hold, labels.FromStrings("severity", "page"), nil, nil)
+		rule.SetRestored(false)
+		group := NewGroup("default", "", time.Minute, []Rule{rule}, opts)
+
+		ts := baseTime.Add(test.restartAt)
+		group.Eval(ts)
+		group.RestoreForState(ts)
+
+		testutil.Assert(t, rule.Restored(), "%d. rule not marked as restored", i)
+		alerts := rule.ActiveAlerts()
+		testutil.Equals(t, 1, len(alerts))
+		testutil.Assert(t, alerts[0].ActiveAt.Equal(baseTime.Add(test.activeAt)), "%d. unexpected active time %s, want %s", i, alerts[0].ActiveAt.Sub(baseTime), test.activeAt)
+	}
+}
+
+func TestGroupLimit(t *testing.T) {
+	suite, err := promql.NewTest(t, `
+		load 1m
+			up{job="app-server", instance="0"}	0
+			up{job="app-server", instance="1"}	0
+			up{job="app-server", instance="2"}	0
+	`)
+	testutil.Ok(t, err)
+	defer suite.Close()
+
+	err = suite.Run()
+	testutil.Ok(t, err)
+
+	expr, err := promql.ParseExpr(`up == 0`)
+	testutil.Ok(t, err)
+
+	tests := []struct {
+		limit int
+		err   bool
+	}{
+		{limit: 0},
+		{limit: 3},
+		{limit: 2, err: true},
+	}
+
+	for i, test := range tests {
+		recording := NewRecordingRule("job:up", expr, nil)
+		_, err := recording.Eval(suite.Context(), time.Unix(0, 0), suite.QueryEngine(), nil, test.limit)
+		testutil.Assert(t, (err != nil) == test.err, "%d. unexpected error for recording rule: %v", i, err)
+
+		alerting := NewAlertingRule("InstanceDown", expr, 0, nil, nil, nil)
+		_, err = alerting.Eval(suite.Context(), time.Unix(0, 0), suite.QueryEngine(), nil, test.limit)
+		testutil.Assert(t, (err != nil) == test.err, "%d. unexpected error for alerting rule: %v", i, err)
+
+		if test.err {
+			// Alerts exceeding the limit are dropped.
+			testutil.Equals(t, 0, len(alerting.ActiveAlerts()))
+		} else {
+			testutil.Equals(t, 3, len(alerting.ActiveAlerts()))
+		}
+	}
+}
+
+func TestGroupExternalLabels(t *testing.T) {
+	storage := testutil.NewStorage(t)
+	defer storage.Close()
+	opts := &ManagerOptions{
+		QueryEngine: promql.NewEngine(storage, nil),
+		Appendable:  storage,
+		Context:     context.Background(),
+		ExternalURL: &url.URL{},
+		Logger:      log.NewNopLogger(),
+	}
+
+	app, _ := storage.Appender()
+	app.Add(labels.FromStrings(model.MetricNameLabel, "up", "job", "app", "region", "eu"), 0, 0)
+	testutil.Ok(t, app.Commit())
+
+	expr, err := promql.ParseExpr("up == 0")
+	testutil.Ok(t, err)
+	recording := NewRecordingRule("job:up:zero", expr, labels.FromStrings("source", "rule"))
+	alerting := NewAlertingRule("AppDown", expr, 0, nil, nil, nil)
+
+	group := NewGroup("default", "", time.Minute, []Rule{recording, alerting}, opts)
+	// The labels of the rules and the series take precedence.
+	group.externalLabels = labels.FromStrings("region", "us", "source", "group", "tenant", "a")
+	group.Eval(time.Unix(0, 0))
+
+	querier, err := storage.Querier(context.Background(), 0, 0)
+	testutil.Ok(t, err)
+	defer querier.Close()
+	matcher, _ := labels.NewMatcher(labels.MatchEqual, "job", "app")
+	set, _, err := querier.Select(nil, matcher)
+	testutil.Ok(t, err)
+	samples, err := readSeriesSet(set)
+	testutil.Ok(t, err)
+
+	for _, lset := range []labels.Labels{
+		labels.FromStrings(model.MetricNameLabel, "up", "job", "app", "region", "eu"),
+		labels.FromStrings(model.MetricNameLabel, "job:up:zero", "job", "app", "region", "eu", "source", "rule", "tenant", "a"),
+		labels.FromStrings(model.MetricNameLabel, "ALERTS", "alertname", "AppDown", "alertstate", "firing", "job", "app", "region", "eu", "source", "group", "tenant", "a"),
+		labels.FromStrings(model.MetricNameLabel, "ALERTS_FOR_STATE", "alertname", "AppDown", "job", "app", "region", "eu", "source", "group", "tenant", "a"),
+	} {
+		_, ok := samples[lset.String()]
+		testutil.Assert(t, ok, "series %s not found in %v", lset, samples)
+	}
+	testutil.Equals(t, 4, len(samples))
+}
+
+func TestEvalLocks(t *testing.T) {
+	newGroup := func(name string, rules ...Rule) *Group {
+		return NewGroup(name, "", time.Minute, rules, &ManagerOptions{Logger: log.NewNopLogger()})
+	}
+	newRecording := func(name, expr string) Rule {
+		e, err := promql.ParseExpr(expr)
+		testutil.Ok(t, err)
+		return NewRecordingRule(name, e, nil)
+	}
+	newAlerting := func(name, expr string) Rule {
+		e, err := promql.ParseExpr(expr)
+		testutil.Ok(t, err)
+		return NewAlertingRule(name, e, 0, nil, nil, nil)
+	}
+
+	groups := map[string]*Group{
+		"producer": newGroup("producer", newRecording("job:up:sum", "sum(up) by (job)")),
+		"consumer": newGroup("consumer", newAlerting("JobDown", "job:up:sum == 0")),
+		"alerts":   newGroup("alerts", newRecording("alerts:count", `count({__name__="ALERTS"})`)),
+		"other":    newGroup("other", newRecording("job:foo:rate5m", "rate(foo[5m])")),
+	}
+	locks := evalLocks(groups)
+	testutil.Equals(t, 4, len(locks))
+	// The consumer reads the producer, the alerts group reads the consumer.
+	testutil.Assert(t, locks["producer"] == locks["consumer"], "producer and consumer do not share a lock")
+	testutil.Assert(t, locks["consumer"] == locks["alerts"], "consumer and alerts do not share a lock")
+	testutil.Assert(t, locks["producer"] != locks["other"], "independent groups share a lock")
+
+	// A group whose input cannot be determined depends on all groups.
+	groups["any"] = newGroup("any", newRecording("count", `count({job="foo"})`))
+	locks = evalLocks(groups)
+	for key := range groups {
+		testutil.Assert(t, locks[key] == locks["any"], "group %s does not share a lock with group any", key)
+	}
+}
+
+func TestLockEvalConcurrency(t *testing.T) {
+	m := NewManager(&ManagerOptions{
+		Logger:             log.NewNopLogger(),
+		MaxConcurrentEvals: 1,
+	})
+	g1 := NewGroup("g1", "", time.Minute, nil, m.opts)
+	g2 := NewGroup("g2", "", time.Minute, nil, m.opts)
+	g1.evalSem, g2.evalSem = m.evalSem, m.evalSem
+
+	unlock := g1.lockEval()
+	locked := make(chan struct{})
+	go func() {
+		defer g2.lockEval()()
+		close(locked)
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("second group evaluated while the first one was")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlock()
+	select {
+	case <-locked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("second group not evaluated after the first one finished")
+	}
+}
+
+func TestGroupDurationMetrics(t *testing.T) {
+	storage := testutil.NewStorage(t)
+	defer storage.Close()
+	opts := &ManagerOptions{
+		QueryEngine: promql.NewEngine(storage, nil),
+		Appendable:  storage,
+		Context:     context.Background(),
+		Logger:      log.NewNopLogger(),
+	}
+
+	expr, err := promql.ParseExpr("vector(1)")
+	testutil.Ok(t, err)
+	group := NewGroup("durations", "file", time.Second, []Rule{
+		NewRecordingRule("one", expr, labels.Labels{}),
+	}, opts)
+	group.Eval(time.Unix(0, 0))
+
+	countSeries := func() int {
+		ch := make(chan prometheus.Metric, 10)
+		evalDuration.Collect(ch)
+		close(ch)
+
+		n := 0
+		for m := range ch {
+			var pb dto.Metric
+			testutil.Ok(t, m.Write(&pb))
+			for _, lp := range pb.GetLabel() {
+				if lp.GetName() == "rule_group" && lp.GetValue() == groupKey("durations", "file") {
+					n++
+				}
+			}
+		}
+		return n
+	}
+	testutil.Equals(t, 1, countSeries())
+
+	group.deleteMetrics()
+	testutil.Equals(t, 0, countSeries())
+}
+
+func TestManagedGroups(t *testing.T) {
+	dir, err := ioutil.TempDir("", "managed_rules")
+	testutil.Ok(t, err)
+	defer os.RemoveAll(dir)
+
+	storage := testutil.NewStorage(t)
+	defer storage.Close()
+	ruleManager := NewManager(&ManagerOptions{
+		QueryEngine: promql.NewEngine(storage, nil),
+		Appendable:  storage,
+		Context:     context.Background(),
+		Logger:      log.NewNopLogger(),
+		ManagedDir:  filepath.Join(dir, "rules"),
+	})
+	ruleManager.Run()
+	defer ruleManager.Stop()
+
+	conf := &config.Config{GlobalConfig: config.DefaultGlobalConfig}
+	testutil.Ok(t, ruleManager.ApplyConfig(conf))
+
+	groupNames := func() []string {
+		var names []string
+		for _, g := range ruleManager.RuleGroups() {
+			names = append(names, g.Name())
+		}
+		return names
+	}
+
+	rg := rulefmt.RuleGroup{
+		Name: "team/a",
+		Rules: []rulefmt.Rule{
+			{Record: "one", Expr: "vector(1)"},
+		},
+	}
+	testutil.Ok(t, ruleManager.SetManagedGroup(rg))
+	testutil.Equals(t, []string{"team/a"}, groupNames())
+	testutil.Equals(t, 1, len(ruleManager.Rules()))
+
+	// Replacing the group keeps a single group.
+	rg.Rules = append(rg.Rules, rulefmt.Rule{Record: "two", Expr: "vector(2)"})
+	testutil.Ok(t, ruleManager.SetManagedGroup(rg))
+	testutil.Equals(t, []string{"team/a"}, groupNames())
+	testutil.Equals(t, 2, len(ruleManager.Rules()))
+
+	// Invalid groups are rejected without changing the rules.
+	rg.Rules[1].Expr = "vector("
+	testutil.NotOk(t, ruleManager.SetManagedGroup(rg))
+	testutil.Equals(t, 2, len(ruleManager.Rules()))
+
+	// Managed groups are loaded with the configuration.
+	testutil.Ok(t, ruleManager.ApplyConfig(conf))
+	testutil.Equals(t, []string{"team/a"}, groupNames())
+
+	testutil.Ok(t, ruleManager.DeleteManagedGroup("team/a"))
+	testutil.Equals(t, 0, len(groupNames()))
+	testutil.Equals(t, ErrManagedGroupNotFound, ruleManager.DeleteManagedGroup("team/a"))
+
+	ruleManager = NewManager(&ManagerOptions{Logger: log.NewNopLogger()})
+	testutil.Equals(t, ErrManagedRulesDisabled, ruleManager.SetManagedGroup(rg))
+}
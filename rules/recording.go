@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"html/template"
 	"net/url"
+	"sync"
 	"time"
 
 	yaml "gopkg.in/yaml.v2"
@@ -30,27 +31,54 @@ import (
 
 // A RecordingRule records its vector expression into new timeseries.
 type RecordingRule struct {
-	name   string
-	vector promql.Expr
-	labels labels.Labels
+	name     string
+	vector   promql.Expr
+	labels   labels.Labels
+	warnings []error
+
+	// Protects the below.
+	mtx sync.Mutex
+	// The health of the recording rule and the error of its last evaluation.
+	health    RuleHealth
+	lastError error
+	// The duration and the timestamp of the last evaluation.
+	evaluationDuration time.Duration
+	lastEvaluation     time.Time
 }
 
 // NewRecordingRule returns a new recording rule.
 func NewRecordingRule(name string, vector promql.Expr, lset labels.Labels) *RecordingRule {
 	return &RecordingRule{
-		name:   name,
-		vector: vector,
-		labels: lset,
+		name:     name,
+		vector:   vector,
+		labels:   lset,
+		warnings: promql.Check(vector),
+		health:   HealthUnknown,
 	}
 }
 
 // Name returns the rule name.
-func (rule RecordingRule) Name() string {
+func (rule *RecordingRule) Name() string {
 	return rule.name
 }
 
+// Query returns the rule query expression.
+func (rule *RecordingRule) Query() promql.Expr {
+	return rule.vector
+}
+
+// Warnings returns the warnings about the rule query expression.
+func (rule *RecordingRule) Warnings() []error {
+	return rule.warnings
+}
+
+// Labels returns the labels attached to the recorded series.
+func (rule *RecordingRule) Labels() labels.Labels {
+	return rule.labels
+}
+
 // Eval evaluates the rule and then overrides the metric names and labels accordingly.
-func (rule RecordingRule) Eval(ctx context.Context, ts time.Time, engine *promql.Engine, _ *url.URL) (promql.Vector, error) {
+func (rule *RecordingRule) Eval(ctx context.Context, ts time.Time, engine *promql.Engine, _ *url.URL, limit int) (promql.Vector, error) {
 	query, err := engine.NewInstantQuery(rule.vector.String(), ts)
 	if err != nil {
 		return nil, err
@@ -61,7 +89,7 @@ func (rule RecordingRule) Eval(ctx context.Context, ts time.Time, engine *promql
 		vector promql.Vector
 	)
 	if result.Err != nil {
-		return nil, err
+		return nil, result.Err
 	}
 
 	switch v := result.Value.(type) {
@@ -76,6 +104,10 @@ func (rule RecordingRule) Eval(ctx context.Context, ts time.Time, engine *promql
 		return nil, fmt.Errorf("rule result is not a vector or scalar")
 	}
 
+	if limit > 0 && len(vector) > limit {
+		return nil, fmt.Errorf("exceeded limit of %d with %d series", limit, len(vector))
+	}
+
 	// Override the metric name and labels.
 	for i := range vector {
 		sample := &vector[i]
@@ -98,7 +130,7 @@ func (rule RecordingRule) Eval(ctx context.Context, ts time.Time, engine *promql
 	return vector, nil
 }
 
-func (rule RecordingRule) String() string {
+func (rule *RecordingRule) String() string {
 	r := rulefmt.Rule{
 		Record: rule.name,
 		Expr:   rule.vector.String(),
@@ -114,7 +146,7 @@ func (rule RecordingRule) String() string {
 }
 
 // HTMLSnippet returns an HTML snippet representing this rule.
-func (rule RecordingRule) HTMLSnippet(pathPrefix string) template.HTML {
+func (rule *RecordingRule) HTMLSnippet(pathPrefix string) template.HTML {
 	ruleExpr := rule.vector.String()
 	labels := make(map[string]string, len(rule.labels))
 	for _, l := range rule.labels {
@@ -134,3 +166,59 @@ func (rule RecordingRule) HTMLSnippet(pathPrefix string) template.HTML {
 
 	return template.HTML(byt)
 }
+
+// SetHealth sets the health of the rule.
+func (rule *RecordingRule) SetHealth(health RuleHealth) {
+	rule.mtx.Lock()
+	defer rule.mtx.Unlock()
+	rule.health = health
+}
+
+// Health returns the health of the rule.
+func (rule *RecordingRule) Health() RuleHealth {
+	rule.mtx.Lock()
+	defer rule.mtx.Unlock()
+	return rule.health
+}
+
+// SetLastError sets the error of the last evaluation of the rule.
+func (rule *RecordingRule) SetLastError(err error) {
+	rule.mtx.Lock()
+	defer rule.mtx.Unlock()
+	rule.lastError = err
+}
+
+// LastError returns the error of the last evaluation of the rule.
+func (rule *RecordingRule) LastError() error {
+	rule.mtx.Lock()
+	defer rule.mtx.Unlock()
+	return rule.lastError
+}
+
+// SetEvaluationDuration sets the duration of the last evaluation of the rule.
+func (rule *RecordingRule) SetEvaluationDuration(d time.Duration) {
+	rule.mtx.Lock()
+	defer rule.mtx.Unlock()
+	rule.evaluationDuration = d
+}
+
+// EvaluationDuration returns the duration of the last evaluation of the rule.
+func (rule *RecordingRule) EvaluationDuration() time.Duration {
+	rule.mtx.Lock()
+	defer rule.mtx.Unlock()
+	return rule.evaluationDuration
+}
+
+// SetLastEvaluation sets the timestamp of the last evaluation of the rule.
+func (rule *RecordingRule) SetLastEvaluation(ts time.Time) {
+	rule.mtx.Lock()
+	defer rule.mtx.Unlock()
+	rule.lastEvaluation = ts
+}
+
+// LastEvaluation returns the timestamp of the last evaluation of the rule.
+func (rule *RecordingRule) LastEvaluation() time.Time {
+	rule.mtx.Lock()
+	defer rule.mtx.Unlock()
+	return rule.lastEvaluation
+}
@@ -62,7 +62,7 @@ func TestRuleEval(t *testing.T) {
 
 	for _, test := range suite {
 		rule := NewRecordingRule(test.name, test.expr, test.labels)
-		result, err := rule.Eval(ctx, now, engine, nil)
+		result, err := rule.Eval(ctx, now, engine, nil, 0)
 		testutil.Ok(t, err)
 		testutil.Equals(t, result, test.result)
 	}
@@ -82,3 +82,15 @@ labels:
 	got := rule.HTMLSnippet("/test/prefix")
 	testutil.Assert(t, want == got, "incorrect HTML snippet; want:\n\n%s\n\ngot:\n\n%s", want, got)
 }
+
+func TestRecordingRuleWarnings(t *testing.T) {
+	expr, err := promql.ParseExpr(`sum by(job)(foo) / on(instance) sum by(instance)(bar)`)
+	testutil.Ok(t, err)
+	rule := NewRecordingRule("testrule", expr, nil)
+	testutil.Equals(t, 2, len(rule.Warnings()))
+
+	expr, err = promql.ParseExpr(`sum by(job)(foo) / sum by(job)(bar)`)
+	testutil.Ok(t, err)
+	rule = NewRecordingRule("testrule", expr, nil)
+	testutil.Equals(t, 0, len(rule.Warnings()))
+}
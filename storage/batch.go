@@ -0,0 +1,56 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import "fmt"
+
+// BatchError is returned by AddBatch for the sample that could not be added.
+type BatchError struct {
+	// The index of the sample in the batch.
+	Index int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("add sample %d of batch: %s", e.Index, e.Err)
+}
+
+// Cause returns the error of the sample.
+func (e *BatchError) Cause() error {
+	return e.Err
+}
+
+// AppendBatch implements AddBatch for app using its Add and AddFast methods.
+// Samples with a reference are added with AddFast and fall back to Add if
+// the reference is unknown.
+func AppendBatch(app Appender, samples []Sample) error {
+	for i := range samples {
+		s := &samples[i]
+		if s.Ref != 0 {
+			err := app.AddFast(s.Labels, s.Ref, s.T, s.V)
+			if err == nil {
+				continue
+			}
+			if err != ErrNotFound {
+				return &BatchError{Index: i, Err: err}
+			}
+		}
+		ref, err := app.Add(s.Labels, s.T, s.V)
+		if err != nil {
+			return &BatchError{Index: i, Err: err}
+		}
+		s.Ref = ref
+	}
+	return nil
+}
@@ -15,6 +15,8 @@ package storage
 
 import (
 	"math"
+	"math/bits"
+	"sync"
 )
 
 // BufferedSeriesIterator wraps an iterator with a look-back buffer.
@@ -106,6 +108,13 @@ func (b *BufferedSeriesIterator) Err() error {
 	return b.it.Err()
 }
 
+// Release returns the buffer of the iterator to a pool, from which the
+// buffers of new iterators are taken. Neither the iterator nor iterators
+// returned by Buffer must be used afterwards.
+func (b *BufferedSeriesIterator) Release() {
+	b.buf.release()
+}
+
 type sample struct {
 	t int64
 	v float64
@@ -114,19 +123,54 @@ type sample struct {
 type sampleRing struct {
 	delta int64
 
-	buf []sample // lookback buffer
-	i   int      // position of most recent element in ring buffer
-	f   int      // position of first element in ring buffer
-	l   int      // number of elements in buffer
+	buf  []sample  // lookback buffer
+	bufp *[]sample // pointer to buf to return it to its pool
+	i    int       // position of most recent element in ring buffer
+	f    int       // position of first element in ring buffer
+	l    int       // number of elements in buffer
+}
+
+// samplePools hold the buffers of released sample rings. Only buffers whose
+// size is a power of two are pooled, in the pool at the index of the binary
+// logarithm of their size. Buffers of queries over large ranges grow big,
+// so reusing them considerably reduces allocations. The pools hold pointers
+// to the buffers as putting a slice into an interface allocates.
+var samplePools [64]sync.Pool
+
+// getSampleBuf returns a buffer of the given size. Its contents are undefined.
+func getSampleBuf(sz int) *[]sample {
+	if sz > 0 && sz&(sz-1) == 0 {
+		if b, ok := samplePools[bits.Len(uint(sz))-1].Get().(*[]sample); ok {
+			return b
+		}
+	}
+	b := make([]sample, sz)
+	return &b
+}
+
+func putSampleBuf(b *[]sample) {
+	if sz := len(*b); sz > 0 && sz&(sz-1) == 0 {
+		samplePools[bits.Len(uint(sz))-1].Put(b)
+	}
 }
 
 func newSampleRing(delta int64, sz int) *sampleRing {
-	r := &sampleRing{delta: delta, buf: make([]sample, sz)}
+	bufp := getSampleBuf(sz)
+	r := &sampleRing{delta: delta, buf: *bufp, bufp: bufp}
 	r.reset()
 
 	return r
 }
 
+// release returns the buffer of the ring to its pool.
+func (r *sampleRing) release() {
+	if r.bufp != nil {
+		putSampleBuf(r.bufp)
+		r.buf, r.bufp = nil, nil
+	}
+	r.reset()
+}
+
 func (r *sampleRing) reset() {
 	r.l = 0
 	r.i = -1
@@ -171,11 +215,13 @@ func (r *sampleRing) add(t int64, v float64) {
 	l := len(r.buf)
 	// Grow the ring buffer if it fits no more elements.
 	if l == r.l {
-		buf := make([]sample, 2*l)
+		bufp := getSampleBuf(2 * l)
+		buf := *bufp
 		copy(buf[l+r.f:], r.buf[r.f:])
 		copy(buf, r.buf[:r.f])
 
-		r.buf = buf
+		putSampleBuf(r.bufp)
+		r.buf, r.bufp = buf, bufp
 		r.i = r.f
 		r.f += l
 	} else {
@@ -157,6 +157,51 @@ func TestBufferedSeriesIteratorNoBadAt(t *testing.T) {
 	it.Next()
 }
 
+// Iterators reusing released buffers must not see samples of previous ones.
+func TestBufferedSeriesIteratorRelease(t *testing.T) {
+	samples := func(n int, v float64) []sample {
+		var s []sample
+		for i := 0; i < n; i++ {
+			s = append(s, sample{t: int64(i), v: v})
+		}
+		return s
+	}
+
+	for i := 0; i < 3; i++ {
+		it := NewBuffer(newListSeriesIterator(samples(100, float64(i))), 1000)
+		for it.Next() {
+		}
+		require.NoError(t, it.Err())
+
+		var buffered []sample
+		bit := it.Buffer()
+		for bit.Next() {
+			t, v := bit.At()
+			buffered = append(buffered, sample{t: t, v: v})
+		}
+		require.Equal(t, samples(100, float64(i)), buffered)
+		it.Release()
+	}
+}
+
+// Simulate a 5 minute rate over many series with released buffers.
+func BenchmarkBufferedSeriesIteratorRelease(b *testing.B) {
+	var samples []sample
+	for i := int64(0); i < 1000; i++ {
+		samples = append(samples, sample{t: i * 30, v: 123})
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		it := NewBuffer(newListSeriesIterator(samples), 5*60*1000)
+		for it.Next() {
+		}
+		it.Release()
+	}
+}
+
 func BenchmarkBufferedSeriesIterator(b *testing.B) {
 	var (
 		samples []sample
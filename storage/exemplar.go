@@ -0,0 +1,184 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/prometheus/prometheus/pkg/exemplar"
+	"github.com/prometheus/prometheus/pkg/labels"
+)
+
+// CircularExemplarStorage keeps the most recent exemplars of all series in
+// a circular buffer of fixed size. Once the buffer is full, every new
+// exemplar replaces the oldest one.
+type CircularExemplarStorage struct {
+	mtx       sync.RWMutex
+	exemplars []circularBufferEntry
+	// The position of the next exemplar in the buffer.
+	next int
+	// The exemplars of each series by the string of its labels.
+	index map[string]*exemplarIndexEntry
+
+	appended   prometheus.Counter
+	outOfOrder prometheus.Counter
+	series     prometheus.Gauge
+}
+
+// exemplarIndexEntry links the exemplars of a series in the buffer.
+type exemplarIndexEntry struct {
+	key            string
+	lset           labels.Labels
+	oldest, newest int
+}
+
+type circularBufferEntry struct {
+	e exemplar.Exemplar
+	// The series of the exemplar, or nil if the entry is unused.
+	series *exemplarIndexEntry
+	// The position of the next exemplar of the series, or -1 if the
+	// exemplar is the newest one.
+	next int
+}
+
+// NewCircularExemplarStorage returns a new exemplar storage that keeps at
+// most size exemplars. A size of zero or less drops all exemplars.
+func NewCircularExemplarStorage(size int, r prometheus.Registerer) *CircularExemplarStorage {
+	if size < 0 {
+		size = 0
+	}
+	s := &CircularExemplarStorage{
+		exemplars: make([]circularBufferEntry, size),
+		index:     map[string]*exemplarIndexEntry{},
+		appended: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "prometheus_tsdb_exemplar_exemplars_appended_total",
+			Help: "Total number of appended exemplars.",
+		}),
+		outOfOrder: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "prometheus_tsdb_exemplar_out_of_order_exemplars_total",
+			Help: "Total number of out of order exemplars that were dropped.",
+		}),
+		series: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "prometheus_tsdb_exemplar_series_with_exemplars_in_storage",
+			Help: "Number of series with exemplars currently in the exemplar storage.",
+		}),
+	}
+	if r != nil {
+		maxExemplars := prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "prometheus_tsdb_exemplar_max_exemplars",
+			Help: "Total number of exemplars the exemplar storage can hold.",
+		})
+		maxExemplars.Set(float64(size))
+		r.MustRegister(s.appended, s.outOfOrder, s.series, maxExemplars)
+	}
+	return s
+}
+
+// AddExemplar implements ExemplarStorage. Exemplars older than the newest
+// exemplar of the series are rejected, and so is the newest exemplar of
+// the series if it is added again.
+func (s *CircularExemplarStorage) AddExemplar(l labels.Labels, e exemplar.Exemplar) error {
+	if len(s.exemplars) == 0 {
+		return nil
+	}
+	key := l.String()
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if idx, ok := s.index[key]; ok {
+		newest := s.exemplars[idx.newest].e
+		if newest.Equals(e) {
+			return ErrDuplicateExemplar
+		}
+		if e.Ts < newest.Ts {
+			s.outOfOrder.Inc()
+			return ErrOutOfOrderExemplar
+		}
+	}
+
+	// Unlink the oldest exemplar, which is overwritten.
+	entry := &s.exemplars[s.next]
+	if old := entry.series; old != nil {
+		if entry.next == -1 {
+			delete(s.index, old.key)
+		} else {
+			old.oldest = entry.next
+		}
+	}
+
+	idx, ok := s.index[key]
+	if !ok {
+		idx = &exemplarIndexEntry{key: key, lset: l, oldest: s.next}
+		s.index[key] = idx
+	} else {
+		s.exemplars[idx.newest].next = s.next
+	}
+	idx.newest = s.next
+	*entry = circularBufferEntry{e: e, series: idx, next: -1}
+
+	s.next = (s.next + 1) % len(s.exemplars)
+	s.appended.Inc()
+	s.series.Set(float64(len(s.index)))
+	return nil
+}
+
+// SelectExemplars implements ExemplarQuerier. The results are sorted by the
+// labels of the series and the exemplars of each series by time.
+func (s *CircularExemplarStorage) SelectExemplars(mint, maxt int64, matcherSets ...[]*labels.Matcher) ([]exemplar.QueryResult, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	var res []exemplar.QueryResult
+	for _, idx := range s.index {
+		if !matchesAnySet(idx.lset, matcherSets) {
+			continue
+		}
+		var es []exemplar.Exemplar
+		for i := idx.oldest; i != -1; i = s.exemplars[i].next {
+			e := s.exemplars[i].e
+			if e.Ts > maxt {
+				break
+			}
+			if e.Ts >= mint {
+				es = append(es, e)
+			}
+		}
+		if len(es) > 0 {
+			res = append(res, exemplar.QueryResult{SeriesLabels: idx.lset, Exemplars: es})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return labels.Compare(res[i].SeriesLabels, res[j].SeriesLabels) < 0
+	})
+	return res, nil
+}
+
+// matchesAnySet returns whether the labels match all matchers of any of the
+// matcher sets.
+func matchesAnySet(lset labels.Labels, matcherSets [][]*labels.Matcher) bool {
+Sets:
+	for _, ms := range matcherSets {
+		for _, m := range ms {
+			if !m.Matches(lset.Get(m.Name)) {
+				continue Sets
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/pkg/exemplar"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircularExemplarStorage(t *testing.T) {
+	s := NewCircularExemplarStorage(3, nil)
+
+	a := labels.FromStrings("__name__", "a", "job", "foo")
+	b := labels.FromStrings("__name__", "b", "job", "foo")
+	ex := func(id string, ts int64) exemplar.Exemplar {
+		return exemplar.Exemplar{Labels: labels.FromStrings("trace_id", id), Value: 1, Ts: ts}
+	}
+
+	require.NoError(t, s.AddExemplar(a, ex("1", 10)))
+	require.Equal(t, ErrDuplicateExemplar, s.AddExemplar(a, ex("1", 10)))
+	require.Equal(t, ErrOutOfOrderExemplar, s.AddExemplar(a, ex("2", 5)))
+	require.NoError(t, s.AddExemplar(b, ex("3", 10)))
+	require.NoError(t, s.AddExemplar(a, ex("4", 20)))
+
+	matcher := func(name, value string) []*labels.Matcher {
+		m, err := labels.NewMatcher(labels.MatchEqual, name, value)
+		require.NoError(t, err)
+		return []*labels.Matcher{m}
+	}
+	jobFoo := matcher("job", "foo")
+	res, err := s.SelectExemplars(0, 100, jobFoo)
+	require.NoError(t, err)
+	require.Equal(t, []exemplar.QueryResult{
+		{SeriesLabels: a, Exemplars: []exemplar.Exemplar{ex("1", 10), ex("4", 20)}},
+		{SeriesLabels: b, Exemplars: []exemplar.Exemplar{ex("3", 10)}},
+	}, res)
+
+	// The time range and the matchers limit the results.
+	res, err = s.SelectExemplars(15, 100, jobFoo)
+	require.NoError(t, err)
+	require.Equal(t, []exemplar.QueryResult{
+		{SeriesLabels: a, Exemplars: []exemplar.Exemplar{ex("4", 20)}},
+	}, res)
+
+	res, err = s.SelectExemplars(0, 100, matcher("__name__", "b"))
+	require.NoError(t, err)
+	require.Equal(t, []exemplar.QueryResult{
+		{SeriesLabels: b, Exemplars: []exemplar.Exemplar{ex("3", 10)}},
+	}, res)
+
+	// Once the buffer is full, the oldest exemplars are overwritten, which
+	// drops series without exemplars left.
+	require.NoError(t, s.AddExemplar(a, ex("5", 30)))
+	require.NoError(t, s.AddExemplar(a, ex("6", 40)))
+	res, err = s.SelectExemplars(0, 100, jobFoo)
+	require.NoError(t, err)
+	require.Equal(t, []exemplar.QueryResult{
+		{SeriesLabels: a, Exemplars: []exemplar.Exemplar{ex("4", 20), ex("5", 30), ex("6", 40)}},
+	}, res)
+
+	// Storages without a size drop all exemplars.
+	s = NewCircularExemplarStorage(0, nil)
+	require.NoError(t, s.AddExemplar(a, ex("1", 10)))
+	res, err = s.SelectExemplars(0, 100, jobFoo)
+	require.NoError(t, err)
+	require.Empty(t, res)
+}
@@ -17,10 +17,14 @@ import (
 	"container/heap"
 	"context"
 	"strings"
+	"sync"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/exemplar"
 	"github.com/prometheus/prometheus/pkg/labels"
 )
 
@@ -63,26 +67,27 @@ func (f *fanout) StartTime() (int64, error) {
 }
 
 func (f *fanout) Querier(ctx context.Context, mint, maxt int64) (Querier, error) {
-	queriers := make([]Querier, 0, 1+len(f.secondaries))
-
-	// Add primary querier
-	querier, err := f.primary.Querier(ctx, mint, maxt)
+	primary, err := f.primary.Querier(ctx, mint, maxt)
 	if err != nil {
 		return nil, err
 	}
-	queriers = append(queriers, querier)
 
-	// Add secondary queriers
+	secondaries := make([]Querier, 0, len(f.secondaries))
 	for _, storage := range f.secondaries {
 		querier, err := storage.Querier(ctx, mint, maxt)
 		if err != nil {
-			NewMergeQuerier(queriers).Close()
+			NewMergeQuerier(primary, secondaries).Close()
 			return nil, err
 		}
-		queriers = append(queriers, querier)
+		secondaries = append(secondaries, querier)
 	}
 
-	return NewMergeQuerier(queriers), nil
+	q := NewMergeQuerier(primary, secondaries)
+	// Trace the selections fanned out as part of the query the querier is for.
+	if mq, ok := q.(*mergeQuerier); ok {
+		mq.ctx = ctx
+	}
+	return q, nil
 }
 
 func (f *fanout) Appender() (Appender, error) {
@@ -157,6 +162,23 @@ func (f *fanoutAppender) AddFast(l labels.Labels, ref uint64, t int64, v float64
 	return nil
 }
 
+func (f *fanoutAppender) AddExemplar(l labels.Labels, e exemplar.Exemplar) error {
+	if err := f.primary.AddExemplar(l, e); err != nil {
+		return err
+	}
+
+	for _, appender := range f.secondaries {
+		if err := appender.AddExemplar(l, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fanoutAppender) AddBatch(samples []Sample) error {
+	return AppendBatch(f, samples)
+}
+
 func (f *fanoutAppender) Commit() (err error) {
 	err = f.primary.Commit()
 
@@ -188,40 +210,103 @@ func (f *fanoutAppender) Rollback() (err error) {
 
 // mergeQuerier implements Querier.
 type mergeQuerier struct {
+	// primary is also part of queriers unless it is nil.
+	primary  Querier
 	queriers []Querier
+	// The context of the query the selections are traced as part of.
+	ctx context.Context
 }
 
 // NewMergeQuerier returns a new Querier that merges results of input queriers.
+// Selection errors of the primary querier, which may be nil, are returned
+// while those of the secondary queriers are only returned as warnings.
 // NB NewMergeQuerier will return NoopQuerier if no queriers are passed to it,
 // and will filter NoopQueriers from its arguments, in order to reduce overhead
 // when only one querier is passed.
-func NewMergeQuerier(queriers []Querier) Querier {
-	filtered := make([]Querier, 0, len(queriers))
-	for _, querier := range queriers {
+func NewMergeQuerier(primary Querier, secondaries []Querier) Querier {
+	filtered := make([]Querier, 0, 1+len(secondaries))
+	if primary != nil && primary != NoopQuerier() {
+		filtered = append(filtered, primary)
+	} else {
+		primary = nil
+	}
+	for _, querier := range secondaries {
 		if querier != NoopQuerier() {
 			filtered = append(filtered, querier)
 		}
 	}
 
-	switch len(filtered) {
-	case 0:
+	switch {
+	case len(filtered) == 0:
 		return NoopQuerier()
-	case 1:
-		return filtered[0]
+	case len(filtered) == 1 && primary != nil:
+		return primary
 	default:
 		return &mergeQuerier{
+			primary:  primary,
 			queriers: filtered,
+			ctx:      context.Background(),
 		}
 	}
 }
 
 // Select returns a set of series that matches the given label matchers.
-func (q *mergeQuerier) Select(matchers ...*labels.Matcher) SeriesSet {
-	seriesSets := make([]SeriesSet, 0, len(q.queriers))
-	for _, querier := range q.queriers {
-		seriesSets = append(seriesSets, querier.Select(matchers...))
+// The underlying queriers are selected from concurrently, as some of them,
+// e.g. remote read endpoints, may block for a considerable amount of time.
+// If a secondary querier fails, the series of the others are returned along
+// with its error as a warning.
+func (q *mergeQuerier) Select(params *SelectParams, matchers ...*labels.Matcher) (SeriesSet, Warnings, error) {
+	var (
+		seriesSets = make([]SeriesSet, len(q.queriers))
+		warnings   = make([]Warnings, len(q.queriers))
+		errs       = make([]error, len(q.queriers))
+		wg         sync.WaitGroup
+	)
+	// Spans are only started for queries that are traced already, so that
+	// selections do not start traces of their own.
+	var span opentracing.Span
+	if parent := opentracing.SpanFromContext(q.ctx); parent != nil {
+		span = parent.Tracer().StartSpan("fanoutSelect", opentracing.ChildOf(parent.Context()))
+		defer span.Finish()
+	}
+	for i, querier := range q.queriers {
+		wg.Add(1)
+		go func(i int, querier Querier) {
+			defer wg.Done()
+			if span != nil {
+				qspan := span.Tracer().StartSpan("fanoutSelectQuerier",
+					opentracing.ChildOf(span.Context()),
+					opentracing.Tag{Key: "primary", Value: querier == q.primary},
+				)
+				defer func() {
+					if errs[i] != nil {
+						ext.Error.Set(qspan, true)
+						qspan.LogKV("error", errs[i].Error())
+					}
+					qspan.Finish()
+				}()
+			}
+			seriesSets[i], warnings[i], errs[i] = querier.Select(params, matchers...)
+		}(i, querier)
+	}
+	wg.Wait()
+
+	var (
+		sets []SeriesSet
+		ws   Warnings
+	)
+	for i, querier := range q.queriers {
+		ws = append(ws, warnings[i]...)
+		if errs[i] != nil {
+			if querier == q.primary {
+				return nil, nil, errs[i]
+			}
+			ws = append(ws, errs[i])
+			continue
+		}
+		sets = append(sets, seriesSets[i])
 	}
-	return newMergeSeriesSet(seriesSets)
+	return newMergeSeriesSet(sets), ws, nil
 }
 
 // LabelValues returns all potential values for a label name.
@@ -237,6 +322,19 @@ func (q *mergeQuerier) LabelValues(name string) ([]string, error) {
 	return mergeStringSlices(results), nil
 }
 
+// LabelNames returns all the unique label names present in the underlying queriers.
+func (q *mergeQuerier) LabelNames() ([]string, error) {
+	var results [][]string
+	for _, querier := range q.queriers {
+		names, err := querier.LabelNames()
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, names)
+	}
+	return mergeStringSlices(results), nil
+}
+
 func mergeStringSlices(ss [][]string) []string {
 	switch len(ss) {
 	case 0:
@@ -392,6 +490,9 @@ func (m *mergeSeries) Iterator() SeriesIterator {
 	return newMergeIterator(iterators)
 }
 
+// mergeIterator merges the samples of multiple iterators in timestamp order.
+// Samples with the same timestamp are only returned once; they are expected
+// to have the same value.
 type mergeIterator struct {
 	iterators []SeriesIterator
 	h         seriesIteratorHeap
@@ -419,7 +520,6 @@ func (c *mergeIterator) At() (t int64, v float64) {
 		panic("mergeIterator.At() called after .Next() returned false.")
 	}
 
-	// TODO do I need to dedupe or just merge?
 	return c.h[0].At()
 }
 
@@ -437,9 +537,18 @@ func (c *mergeIterator) Next() bool {
 		return false
 	}
 
-	iter := heap.Pop(&c.h).(SeriesIterator)
-	if iter.Next() {
-		heap.Push(&c.h, iter)
+	// Advance all iterators positioned at the current timestamp to drop
+	// duplicate samples.
+	currt, _ := c.At()
+	for len(c.h) > 0 {
+		nextt, _ := c.h[0].At()
+		if nextt != currt {
+			break
+		}
+		iter := heap.Pop(&c.h).(SeriesIterator)
+		if iter.Next() {
+			heap.Push(&c.h, iter)
+		}
 	}
 
 	return len(c.h) > 0
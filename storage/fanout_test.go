@@ -14,9 +14,15 @@
 package storage
 
 import (
+	"context"
+	"errors"
+	"sync"
 	"testing"
+	"time"
 
+	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/stretchr/testify/require"
+	jaeger "github.com/uber/jaeger-client-go"
 
 	"github.com/prometheus/prometheus/pkg/labels"
 )
@@ -135,6 +141,14 @@ func TestMergeIterator(t *testing.T) {
 			},
 			expected: []sample{{0, 0}, {1, 1}, {2, 2}, {3, 3}, {4, 4}, {5, 5}},
 		},
+		{
+			input: []SeriesIterator{
+				newListSeriesIterator([]sample{{0, 0}, {1, 1}, {2, 2}}),
+				newListSeriesIterator([]sample{{1, 1}, {2, 2}, {3, 3}}),
+				newListSeriesIterator([]sample{{2, 2}}),
+			},
+			expected: []sample{{0, 0}, {1, 1}, {2, 2}, {3, 3}},
+		},
 	} {
 		merged := newMergeIterator(tc.input)
 		actual := drainSamples(merged)
@@ -172,6 +186,14 @@ func TestMergeIteratorSeek(t *testing.T) {
 			seek:     2,
 			expected: []sample{{2, 2}, {3, 3}, {4, 4}, {5, 5}},
 		},
+		{
+			input: []SeriesIterator{
+				newListSeriesIterator([]sample{{0, 0}, {1, 1}, {2, 2}}),
+				newListSeriesIterator([]sample{{1, 1}, {2, 2}, {3, 3}}),
+			},
+			seek:     1,
+			expected: []sample{{1, 1}, {2, 2}, {3, 3}},
+		},
 	} {
 		merged := newMergeIterator(tc.input)
 		actual := []sample{}
@@ -228,3 +250,120 @@ func newMockSeries(lset labels.Labels, samples []sample) Series {
 		},
 	}
 }
+
+// blockingQuerier returns its series only once all other blockingQueriers
+// sharing the same WaitGroup have been selected from.
+type blockingQuerier struct {
+	wg     *sync.WaitGroup
+	series Series
+}
+
+func (q *blockingQuerier) Select(*SelectParams, ...*labels.Matcher) (SeriesSet, Warnings, error) {
+	q.wg.Done()
+	q.wg.Wait()
+	return newMockSeriesSet(q.series), nil, nil
+}
+func (q *blockingQuerier) LabelValues(name string) ([]string, error) { return nil, nil }
+func (q *blockingQuerier) LabelNames() ([]string, error)             { return nil, nil }
+func (q *blockingQuerier) Close() error                              { return nil }
+
+func TestMergeQuerierSelectConcurrent(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+
+	q := NewMergeQuerier(
+		&blockingQuerier{wg: wg, series: newMockSeries(labels.FromStrings("foo", "bar"), []sample{{0, 0}, {1, 1}})},
+		[]Querier{&blockingQuerier{wg: wg, series: newMockSeries(labels.FromStrings("foo", "bar"), []sample{{1, 1}, {2, 2}})}},
+	)
+
+	done := make(chan SeriesSet)
+	go func() {
+		set, _, err := q.Select(nil)
+		require.NoError(t, err)
+		done <- set
+	}()
+
+	var set SeriesSet
+	select {
+	case set = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("queriers were not selected from concurrently")
+	}
+
+	require.True(t, set.Next())
+	require.Equal(t, labels.FromStrings("foo", "bar"), set.At().Labels())
+	require.Equal(t, []sample{{0, 0}, {1, 1}, {2, 2}}, drainSamples(set.At().Iterator()))
+	require.False(t, set.Next())
+}
+
+// errQuerier fails all selections with its error.
+type errQuerier struct {
+	err error
+}
+
+func (q errQuerier) Select(*SelectParams, ...*labels.Matcher) (SeriesSet, Warnings, error) {
+	return nil, nil, q.err
+}
+func (errQuerier) LabelValues(name string) ([]string, error) { return nil, nil }
+func (errQuerier) LabelNames() ([]string, error)             { return nil, nil }
+func (errQuerier) Close() error                              { return nil }
+
+func TestMergeQuerierSelectErrors(t *testing.T) {
+	var (
+		wg     = &sync.WaitGroup{}
+		series = newMockSeries(labels.FromStrings("foo", "bar"), []sample{{0, 0}})
+		errA   = errors.New("a failed")
+		errB   = errors.New("b failed")
+	)
+
+	// Failing secondary queriers only cause warnings.
+	wg.Add(1)
+	q := NewMergeQuerier(&blockingQuerier{wg: wg, series: series}, []Querier{errQuerier{errA}, errQuerier{errB}})
+	set, warnings, err := q.Select(nil)
+	require.NoError(t, err)
+	require.Equal(t, Warnings{errA, errB}, warnings)
+	require.True(t, set.Next())
+	require.Equal(t, labels.FromStrings("foo", "bar"), set.At().Labels())
+	require.False(t, set.Next())
+
+	// A failing primary querier fails the selection.
+	wg.Add(1)
+	q = NewMergeQuerier(errQuerier{errA}, []Querier{&blockingQuerier{wg: wg, series: series}})
+	_, _, err = q.Select(nil)
+	require.Equal(t, errA, err)
+
+	// Without a primary querier all errors are warnings.
+	q = NewMergeQuerier(nil, []Querier{errQuerier{errA}})
+	_, warnings, err = q.Select(nil)
+	require.NoError(t, err)
+	require.Equal(t, Warnings{errA}, warnings)
+}
+
+func TestMergeQuerierSelectTraced(t *testing.T) {
+	reporter := jaeger.NewInMemoryReporter()
+	tracer, closer := jaeger.NewTracer("test", jaeger.NewConstSampler(true), reporter)
+	defer closer.Close()
+
+	var (
+		wg     = &sync.WaitGroup{}
+		series = newMockSeries(labels.FromStrings("foo", "bar"), []sample{{0, 0}})
+		q      = NewMergeQuerier(errQuerier{errors.New("a failed")}, []Querier{&blockingQuerier{wg: wg, series: series}})
+	)
+
+	// Selections of queries that are not traced are not traced either.
+	wg.Add(1)
+	q.Select(nil)
+	require.Equal(t, 0, reporter.SpansSubmitted())
+
+	parent := tracer.StartSpan("query")
+	q.(*mergeQuerier).ctx = opentracing.ContextWithSpan(context.Background(), parent)
+	wg.Add(1)
+	q.Select(nil)
+	parent.Finish()
+
+	operations := map[string]int{}
+	for _, s := range reporter.GetSpans() {
+		operations[s.(*jaeger.Span).OperationName()]++
+	}
+	require.Equal(t, map[string]int{"query": 1, "fanoutSelect": 1, "fanoutSelectQuerier": 2}, operations)
+}
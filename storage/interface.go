@@ -17,6 +17,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/prometheus/prometheus/pkg/exemplar"
 	"github.com/prometheus/prometheus/pkg/labels"
 )
 
@@ -26,6 +27,8 @@ var (
 	ErrOutOfOrderSample            = errors.New("out of order sample")
 	ErrDuplicateSampleForTimestamp = errors.New("duplicate sample for timestamp")
 	ErrOutOfBounds                 = errors.New("out of bounds")
+	ErrOutOfOrderExemplar          = errors.New("out of order exemplar")
+	ErrDuplicateExemplar           = errors.New("duplicate exemplar")
 )
 
 // Storage ingests and manages samples, along with various indexes. All methods
@@ -44,14 +47,38 @@ type Storage interface {
 	Close() error
 }
 
+// SelectParams specifies hints derived from a query that data selections may
+// use to pre-aggregate the returned data. Implementations are free to ignore
+// them and return raw samples.
+type SelectParams struct {
+	Start int64 // Start time of the selection in milliseconds.
+	End   int64 // End time of the selection in milliseconds.
+	Step  int64 // Query step size in milliseconds.
+	Range int64 // Range of a range vector selector in milliseconds.
+
+	// String representation of the function or aggregation the selected
+	// series are passed to, empty if none.
+	Func string
+	// Labels of the surrounding aggregation and whether they are kept (By)
+	// or dropped by it.
+	Grouping []string
+	By       bool
+}
+
 // Querier provides reading access to time series data.
 type Querier interface {
 	// Select returns a set of series that matches the given label matchers.
-	Select(...*labels.Matcher) SeriesSet
+	// The params are hints about the query the selection is made for and may
+	// be nil. Warnings report problems that still allowed to return a
+	// possibly partial result.
+	Select(*SelectParams, ...*labels.Matcher) (SeriesSet, Warnings, error)
 
 	// LabelValues returns all potential values for a label name.
 	LabelValues(name string) ([]string, error)
 
+	// LabelNames returns all the unique label names present in the block in sorted order.
+	LabelNames() ([]string, error)
+
 	// Close releases the resources of the Querier.
 	Close() error
 }
@@ -62,12 +89,56 @@ type Appender interface {
 
 	AddFast(l labels.Labels, ref uint64, t int64, v float64) error
 
+	// AddExemplar adds an exemplar of the series with the given labels. It
+	// is stored along with the samples on commit. Storages that do not keep
+	// exemplars drop it.
+	AddExemplar(l labels.Labels, e exemplar.Exemplar) error
+
+	// AddBatch adds the samples in order. It stops at the first sample
+	// that cannot be added and returns a *BatchError for it. The samples
+	// added before are kept and committed or rolled back with the others.
+	AddBatch(samples []Sample) error
+
 	// Commit submits the collected samples and purges the batch.
 	Commit() error
 
 	Rollback() error
 }
 
+// Sample is a sample of a series to be added in a batch.
+type Sample struct {
+	Labels labels.Labels
+	// The reference of the series returned by a previous add, or 0 if
+	// unknown. It is set to the reference of the series once the sample
+	// was added.
+	Ref uint64
+	T   int64
+	V   float64
+}
+
+// CommitHook is called after every batch of samples committed to a storage.
+// It must not block.
+type CommitHook func()
+
+// ExemplarStorage keeps the exemplars of series.
+type ExemplarStorage interface {
+	ExemplarQuerier
+
+	// AddExemplar stores an exemplar of the series with the given labels.
+	AddExemplar(l labels.Labels, e exemplar.Exemplar) error
+}
+
+// ExemplarQuerier provides querying access to the exemplars of series.
+type ExemplarQuerier interface {
+	// SelectExemplars returns the exemplars within [mint, maxt] of the series
+	// matching all matchers of any of the matcher sets.
+	SelectExemplars(mint, maxt int64, matcherSets ...[]*labels.Matcher) ([]exemplar.QueryResult, error)
+}
+
+// Warnings are errors that did not prevent a selection from returning
+// results, e.g. because only one of several remote endpoints failed.
+type Warnings []error
+
 // SeriesSet contains a set of series.
 type SeriesSet interface {
 	Next() bool
@@ -75,6 +146,20 @@ type SeriesSet interface {
 	Err() error
 }
 
+type errSeriesSet struct {
+	err error
+}
+
+// ErrSeriesSet returns a SeriesSet that contains no series and fails with
+// the given error.
+func ErrSeriesSet(err error) SeriesSet {
+	return errSeriesSet{err: err}
+}
+
+func (errSeriesSet) Next() bool   { return false }
+func (errSeriesSet) At() Series   { return nil }
+func (e errSeriesSet) Err() error { return e.err }
+
 // Series represents a single time series.
 type Series interface {
 	// Labels returns the complete set of labels identifying the series.
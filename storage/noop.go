@@ -22,14 +22,18 @@ func NoopQuerier() Querier {
 	return noopQuerier{}
 }
 
-func (noopQuerier) Select(...*labels.Matcher) SeriesSet {
-	return NoopSeriesSet()
+func (noopQuerier) Select(*SelectParams, ...*labels.Matcher) (SeriesSet, Warnings, error) {
+	return NoopSeriesSet(), nil, nil
 }
 
 func (noopQuerier) LabelValues(name string) ([]string, error) {
 	return nil, nil
 }
 
+func (noopQuerier) LabelNames() ([]string, error) {
+	return nil, nil
+}
+
 func (noopQuerier) Close() error {
 	return nil
 }
@@ -21,10 +21,13 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/golang/snappy"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
 	"github.com/prometheus/common/model"
 	"golang.org/x/net/context/ctxhttp"
 
@@ -35,20 +38,24 @@ import (
 
 const maxErrMsgLen = 256
 
-// Client allows reading and writing from/to a remote HTTP endpoint.
+// Client allows reading and writing from/to a remote HTTP endpoint. It
+// implements WriteStorage and ReadClient.
 type Client struct {
-	index      int // Used to differentiate metrics.
-	url        *config.URL
-	client     *http.Client
-	timeout    time.Duration
-	readRecent bool
+	index   int // Used to differentiate metrics.
+	url     *config.URL
+	client  *http.Client
+	timeout time.Duration
+	headers map[string]string
 }
 
-// ClientConfig configures a Client.
+// ClientConfig configures a Client or a registered WriteStorage or
+// ReadClient. ReadRecent and RequiredMatchers are applied by the Storage.
 type ClientConfig struct {
 	URL              *config.URL
 	Timeout          model.Duration
 	ReadRecent       bool
+	RequiredMatchers model.LabelSet
+	Headers          map[string]string
 	HTTPClientConfig config.HTTPClientConfig
 }
 
@@ -60,16 +67,19 @@ func NewClient(index int, conf *ClientConfig) (*Client, error) {
 	}
 
 	return &Client{
-		index:      index,
-		url:        conf.URL,
-		client:     httpClient,
-		timeout:    time.Duration(conf.Timeout),
-		readRecent: conf.ReadRecent,
+		index:   index,
+		url:     conf.URL,
+		client:  httpClient,
+		timeout: time.Duration(conf.Timeout),
+		headers: conf.Headers,
 	}, nil
 }
 
+// recoverableError is an error for which the request may be retried.
 type recoverableError struct {
 	error
+	// How long the server asked to wait before retrying, if at all.
+	retryAfter time.Duration
 }
 
 // Store sends a batch of samples to the HTTP endpoint.
@@ -89,6 +99,7 @@ func (c *Client) Store(req *prompb.WriteRequest) error {
 	httpReq.Header.Add("Content-Encoding", "snappy")
 	httpReq.Header.Set("Content-Type", "application/x-protobuf")
 	httpReq.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	c.setHeaders(httpReq)
 
 	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
 	defer cancel()
@@ -97,7 +108,7 @@ func (c *Client) Store(req *prompb.WriteRequest) error {
 	if err != nil {
 		// Errors from client.Do are from (for example) network errors, so are
 		// recoverable.
-		return recoverableError{err}
+		return recoverableError{err, 0}
 	}
 	defer httpResp.Body.Close()
 
@@ -109,12 +120,39 @@ func (c *Client) Store(req *prompb.WriteRequest) error {
 		}
 		err = fmt.Errorf("server returned HTTP status %s: %s", httpResp.Status, line)
 	}
-	if httpResp.StatusCode/100 == 5 {
-		return recoverableError{err}
+	if httpResp.StatusCode/100 == 5 || httpResp.StatusCode == http.StatusTooManyRequests {
+		return recoverableError{err, retryAfter(httpResp.Header.Get("Retry-After"))}
 	}
 	return err
 }
 
+// retryAfter parses the value of a Retry-After header, which is either a
+// number of seconds or an HTTP date. It returns 0 if the value is invalid.
+func retryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if s, err := strconv.Atoi(v); err == nil {
+		if s < 0 {
+			return 0
+		}
+		return time.Duration(s) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// setHeaders sets the configured custom headers on a request.
+func (c *Client) setHeaders(req *http.Request) {
+	for name, value := range c.headers {
+		req.Header.Set(name, value)
+	}
+}
+
 // Name identifies the client.
 func (c Client) Name() string {
 	return fmt.Sprintf("%d:%s", c.index, c.url)
@@ -129,6 +167,43 @@ func (c *Client) Read(ctx context.Context, query *prompb.Query) (*prompb.QueryRe
 			query,
 		},
 	}
+	resp, err := c.read(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Results) != len(req.Queries) {
+		return nil, fmt.Errorf("responses: want %d, got %d", len(req.Queries), len(resp.Results))
+	}
+
+	return resp.Results[0], nil
+}
+
+// ReadLabels reads label names or values from a remote endpoint.
+func (c *Client) ReadLabels(ctx context.Context, query *prompb.LabelQuery) (*prompb.LabelQueryResult, error) {
+	req := &prompb.ReadRequest{
+		LabelQueries: []*prompb.LabelQuery{
+			query,
+		},
+	}
+	resp, err := c.read(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Endpoints that do not support label queries yet silently ignore them.
+	// Treat them as not having any labels rather than failing.
+	if len(resp.LabelResults) == 0 {
+		return &prompb.LabelQueryResult{}, nil
+	}
+	if len(resp.LabelResults) != len(req.LabelQueries) {
+		return nil, fmt.Errorf("label responses: want %d, got %d", len(req.LabelQueries), len(resp.LabelResults))
+	}
+
+	return resp.LabelResults[0], nil
+}
+
+func (c *Client) read(ctx context.Context, req *prompb.ReadRequest) (*prompb.ReadResponse, error) {
 	data, err := proto.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("unable to marshal read request: %v", err)
@@ -142,16 +217,34 @@ func (c *Client) Read(ctx context.Context, query *prompb.Query) (*prompb.QueryRe
 	httpReq.Header.Add("Content-Encoding", "snappy")
 	httpReq.Header.Set("Content-Type", "application/x-protobuf")
 	httpReq.Header.Set("X-Prometheus-Remote-Read-Version", "0.1.0")
+	c.setHeaders(httpReq)
 
 	ctx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
 
+	// Propagate the trace, so that the remote endpoint can continue it.
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Remote Read", opentracing.Tags{
+		"client":              c.Name(),
+		"queries":             len(req.Queries),
+		"label_queries":       len(req.LabelQueries),
+		string(ext.Component): "remote",
+	})
+	defer span.Finish()
+	ext.SpanKindRPCClient.Set(span)
+	ext.HTTPMethod.Set(span, httpReq.Method)
+	ext.HTTPUrl.Set(span, httpReq.URL.String())
+	span.Tracer().Inject(span.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(httpReq.Header))
+
 	httpResp, err := ctxhttp.Do(ctx, c.client, httpReq)
 	if err != nil {
+		ext.Error.Set(span, true)
+		span.LogKV("err", err)
 		return nil, fmt.Errorf("error sending request: %v", err)
 	}
 	defer httpResp.Body.Close()
+	ext.HTTPStatusCode.Set(span, uint16(httpResp.StatusCode))
 	if httpResp.StatusCode/100 != 2 {
+		ext.Error.Set(span, true)
 		return nil, fmt.Errorf("server returned HTTP status %s", httpResp.Status)
 	}
 
@@ -171,9 +264,5 @@ func (c *Client) Read(ctx context.Context, query *prompb.Query) (*prompb.QueryRe
 		return nil, fmt.Errorf("unable to unmarshal response body: %v", err)
 	}
 
-	if len(resp.Results) != len(req.Queries) {
-		return nil, fmt.Errorf("responses: want %d, got %d", len(req.Queries), len(resp.Results))
-	}
-
-	return resp.Results[0], nil
+	return &resp, nil
 }
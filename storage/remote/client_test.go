@@ -14,6 +14,8 @@
 package remote
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -23,17 +25,20 @@ import (
 	"testing"
 	"time"
 
+	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/prompb"
+	jaeger "github.com/uber/jaeger-client-go"
 )
 
 var longErrMessage = strings.Repeat("error message", maxErrMsgLen)
 
 func TestStoreHTTPErrorHandling(t *testing.T) {
 	tests := []struct {
-		code int
-		err  error
+		code       int
+		retryAfter string
+		err        error
 	}{
 		{
 			code: 200,
@@ -41,21 +46,34 @@ func TestStoreHTTPErrorHandling(t *testing.T) {
 		},
 		{
 			code: 300,
-			err:  fmt.Errorf("server returned HTTP status 300 Multiple Choices: " + longErrMessage[:maxErrMsgLen]),
+			err:  errors.New("server returned HTTP status 300 Multiple Choices: " + longErrMessage[:maxErrMsgLen]),
 		},
 		{
 			code: 404,
-			err:  fmt.Errorf("server returned HTTP status 404 Not Found: " + longErrMessage[:maxErrMsgLen]),
+			err:  errors.New("server returned HTTP status 404 Not Found: " + longErrMessage[:maxErrMsgLen]),
 		},
 		{
 			code: 500,
-			err:  recoverableError{fmt.Errorf("server returned HTTP status 500 Internal Server Error: " + longErrMessage[:maxErrMsgLen])},
+			err:  recoverableError{errors.New("server returned HTTP status 500 Internal Server Error: " + longErrMessage[:maxErrMsgLen]), 0},
+		},
+		{
+			code:       503,
+			retryAfter: "5",
+			err:        recoverableError{errors.New("server returned HTTP status 503 Service Unavailable: " + longErrMessage[:maxErrMsgLen]), 5 * time.Second},
+		},
+		{
+			code:       429,
+			retryAfter: "invalid",
+			err:        recoverableError{errors.New("server returned HTTP status 429 Too Many Requests: " + longErrMessage[:maxErrMsgLen]), 0},
 		},
 	}
 
 	for i, test := range tests {
 		server := httptest.NewServer(
 			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if test.retryAfter != "" {
+					w.Header().Set("Retry-After", test.retryAfter)
+				}
 				http.Error(w, longErrMessage, test.code)
 			}),
 		)
@@ -81,3 +99,104 @@ func TestStoreHTTPErrorHandling(t *testing.T) {
 		server.Close()
 	}
 }
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		min   time.Duration
+		max   time.Duration
+	}{
+		{value: "", min: 0, max: 0},
+		{value: "120", min: 2 * time.Minute, max: 2 * time.Minute},
+		{value: "-1", min: 0, max: 0},
+		{value: time.Now().Add(time.Minute).UTC().Format(http.TimeFormat), min: 58 * time.Second, max: time.Minute},
+		{value: time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), min: 0, max: 0},
+	}
+
+	for i, test := range tests {
+		if d := retryAfter(test.value); d < test.min || d > test.max {
+			t.Errorf("%d. Unexpected duration for %q; want between %v and %v, got %v", i, test.value, test.min, test.max, d)
+		}
+	}
+}
+
+func TestClientHeaders(t *testing.T) {
+	headers := map[string]string{
+		"X-Scope-OrgID": "tenant-1",
+		"X-Custom":      "value",
+	}
+
+	var received http.Header
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r.Header
+		}),
+	)
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewClient(0, &ClientConfig{
+		URL:     &config.URL{URL: serverURL},
+		Timeout: model.Duration(time.Second),
+		Headers: headers,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Store(&prompb.WriteRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range headers {
+		if got := received.Get(name); got != value {
+			t.Errorf("Unexpected value of header %q; want %q, got %q", name, value, got)
+		}
+	}
+	if got := received.Get("Content-Type"); got != "application/x-protobuf" {
+		t.Errorf("Unexpected content type %q", got)
+	}
+}
+
+func TestClientReadPropagatesTrace(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r.Header
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		}),
+	)
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewClient(0, &ClientConfig{
+		URL:     &config.URL{URL: serverURL},
+		Timeout: model.Duration(time.Second),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tracer, closer := jaeger.NewTracer("test", jaeger.NewConstSampler(true), jaeger.NewInMemoryReporter())
+	defer closer.Close()
+	opentracing.SetGlobalTracer(tracer)
+	defer opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+
+	span, ctx := opentracing.StartSpanFromContext(context.Background(), "query")
+	defer span.Finish()
+
+	if _, err := c.Read(ctx, &prompb.Query{}); err == nil {
+		t.Fatal("expected error")
+	}
+	// The header carries the span context as {trace-id}:{span-id}:...
+	traceID := func(s string) string { return strings.SplitN(s, ":", 2)[0] }
+	if got, want := traceID(received.Get("Uber-Trace-Id")), traceID(fmt.Sprint(span.Context())); got != want {
+		t.Fatalf("Expected request to continue trace %q, got %q", want, got)
+	}
+}
@@ -18,12 +18,14 @@ import (
 	"io/ioutil"
 	"net/http"
 	"sort"
+	"strings"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/golang/snappy"
 	"github.com/prometheus/common/model"
 
 	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/textparse"
 	"github.com/prometheus/prometheus/prompb"
 	"github.com/prometheus/prometheus/storage"
 )
@@ -85,27 +87,78 @@ func ToWriteRequest(samples []*model.Sample) *prompb.WriteRequest {
 	return req
 }
 
+// metricTypeToMetricTypeProto returns the protobuf type of a metric type.
+func metricTypeToMetricTypeProto(t textparse.MetricType) prompb.MetricMetadata_MetricType {
+	mt := strings.ToUpper(string(t))
+	v, ok := prompb.MetricMetadata_MetricType_value[mt]
+	if !ok {
+		return prompb.MetricMetadata_UNKNOWN
+	}
+	return prompb.MetricMetadata_MetricType(v)
+}
+
 // ToQuery builds a Query proto.
-func ToQuery(from, to int64, matchers []*labels.Matcher) (*prompb.Query, error) {
+func ToQuery(from, to int64, matchers []*labels.Matcher, p *storage.SelectParams) (*prompb.Query, error) {
 	ms, err := toLabelMatchers(matchers)
 	if err != nil {
 		return nil, err
 	}
 
+	var rp *prompb.ReadHints
+	if p != nil {
+		rp = &prompb.ReadHints{
+			StartMs:  p.Start,
+			EndMs:    p.End,
+			StepMs:   p.Step,
+			RangeMs:  p.Range,
+			Func:     p.Func,
+			Grouping: p.Grouping,
+			By:       p.By,
+		}
+	}
+
 	return &prompb.Query{
 		StartTimestampMs: from,
 		EndTimestampMs:   to,
 		Matchers:         ms,
+		Hints:            rp,
 	}, nil
 }
 
 // FromQuery unpacks a Query proto.
-func FromQuery(req *prompb.Query) (int64, int64, []*labels.Matcher, error) {
+func FromQuery(req *prompb.Query) (int64, int64, []*labels.Matcher, *storage.SelectParams, error) {
 	matchers, err := fromLabelMatchers(req.Matchers)
 	if err != nil {
-		return 0, 0, nil, err
+		return 0, 0, nil, nil, err
+	}
+	var p *storage.SelectParams
+	if h := req.Hints; h != nil {
+		p = &storage.SelectParams{
+			Start:    h.StartMs,
+			End:      h.EndMs,
+			Step:     h.StepMs,
+			Range:    h.RangeMs,
+			Func:     h.Func,
+			Grouping: h.Grouping,
+			By:       h.By,
+		}
+	}
+	return req.StartTimestampMs, req.EndTimestampMs, matchers, p, nil
+}
+
+// ToLabelQuery builds a LabelQuery proto for the values of the named label,
+// or for all label names if name is empty.
+func ToLabelQuery(from, to int64, name string) *prompb.LabelQuery {
+	return &prompb.LabelQuery{
+		StartTimestampMs: from,
+		EndTimestampMs:   to,
+		Name:             name,
 	}
-	return req.StartTimestampMs, req.EndTimestampMs, matchers, nil
+}
+
+// FromLabelQuery unpacks a LabelQuery proto.
+func FromLabelQuery(req *prompb.LabelQuery) (int64, int64, string) {
+	return req.StartTimestampMs, req.EndTimestampMs, req.Name
 }
 
 // ToQueryResult builds a QueryResult proto.
@@ -144,7 +197,7 @@ func FromQueryResult(res *prompb.QueryResult) storage.SeriesSet {
 	for _, ts := range res.Timeseries {
 		labels := labelProtosToLabels(ts.Labels)
 		if err := validateLabelsAndMetricName(labels); err != nil {
-			return errSeriesSet{err: err}
+			return storage.ErrSeriesSet(err)
 		}
 
 		series = append(series, &concreteSeries{
@@ -158,23 +211,6 @@ func FromQueryResult(res *prompb.QueryResult) storage.SeriesSet {
 	}
 }
 
-// errSeriesSet implements storage.SeriesSet, just returning an error.
-type errSeriesSet struct {
-	err error
-}
-
-func (errSeriesSet) Next() bool {
-	return false
-}
-
-func (errSeriesSet) At() storage.Series {
-	return nil
-}
-
-func (e errSeriesSet) Err() error {
-	return e.err
-}
-
 // concreteSeriesSet implements storage.SeriesSet.
 type concreteSeriesSet struct {
 	cur    int
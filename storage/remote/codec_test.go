@@ -14,6 +14,7 @@
 package remote
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/prometheus/prometheus/pkg/labels"
@@ -124,3 +125,33 @@ func TestConcreteSeriesSet(t *testing.T) {
 		t.Fatalf("Expected Next() to be false.")
 	}
 }
+
+func TestQueryHints(t *testing.T) {
+	m, err := labels.NewMatcher(labels.MatchEqual, "job", "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	matchers := []*labels.Matcher{m}
+	for _, p := range []*storage.SelectParams{
+		nil,
+		{Start: 1, End: 2, Step: 3, Range: 4, Func: "rate", Grouping: []string{"instance"}, By: true},
+	} {
+		q, err := ToQuery(1, 2, matchers, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (q.Hints == nil) != (p == nil) {
+			t.Fatalf("Unexpected hints %v for params %v", q.Hints, p)
+		}
+		from, to, ms, params, err := FromQuery(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if from != 1 || to != 2 || !reflect.DeepEqual(ms, matchers) {
+			t.Fatalf("Unexpected query %d, %d, %v", from, to, ms)
+		}
+		if !reflect.DeepEqual(params, p) {
+			t.Fatalf("Expected params %v, got %v", p, params)
+		}
+	}
+}
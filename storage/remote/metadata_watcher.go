@@ -0,0 +1,117 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/prometheus/prometheus/retrieval"
+)
+
+// MetadataSource provides the targets whose metric metadata is sent to the
+// remote write endpoints, like the scrape manager.
+type MetadataSource interface {
+	Targets() []*retrieval.Target
+}
+
+// MetadataAppender sends metric metadata to a remote write endpoint.
+type MetadataAppender interface {
+	AppendMetadata(context.Context, []retrieval.MetricMetadata)
+}
+
+// MetadataWatcher periodically collects the metadata of the metric families
+// exposed by the targets of a source and passes it on to an appender.
+type MetadataWatcher struct {
+	logger   log.Logger
+	source   MetadataSource
+	appender MetadataAppender
+	interval time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewMetadataWatcher builds a new MetadataWatcher.
+func NewMetadataWatcher(l log.Logger, source MetadataSource, appender MetadataAppender, interval time.Duration) *MetadataWatcher {
+	if l == nil {
+		l = log.NewNopLogger()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &MetadataWatcher{
+		logger:   l,
+		source:   source,
+		appender: appender,
+		interval: interval,
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+}
+
+// Start the MetadataWatcher. Does not block.
+func (mw *MetadataWatcher) Start() {
+	level.Info(mw.logger).Log("msg", "Starting scraped metadata watcher")
+	go mw.loop()
+}
+
+// Stop the MetadataWatcher, aborting metadata being sent.
+func (mw *MetadataWatcher) Stop() {
+	level.Info(mw.logger).Log("msg", "Stopping metadata watcher...")
+	mw.cancel()
+	<-mw.done
+	level.Info(mw.logger).Log("msg", "Scraped metadata watcher stopped")
+}
+
+func (mw *MetadataWatcher) loop() {
+	defer close(mw.done)
+
+	ticker := time.NewTicker(mw.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-mw.ctx.Done():
+			return
+		case <-ticker.C:
+			mw.collect()
+		}
+	}
+}
+
+// collect passes the metadata of all targets to the appender. Metadata
+// exposed identically by several targets is only passed on once.
+func (mw *MetadataWatcher) collect() {
+	var (
+		seen     = map[retrieval.MetricMetadata]struct{}{}
+		metadata []retrieval.MetricMetadata
+	)
+	for _, t := range mw.source.Targets() {
+		for _, m := range t.MetadataList() {
+			if _, ok := seen[m]; ok {
+				continue
+			}
+			seen[m] = struct{}{}
+			metadata = append(metadata, m)
+		}
+	}
+	if len(metadata) == 0 {
+		return
+	}
+	mw.appender.AppendMetadata(mw.ctx, metadata)
+}
@@ -0,0 +1,88 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/textparse"
+	"github.com/prometheus/prometheus/retrieval"
+)
+
+type testMetadataStore []retrieval.MetricMetadata
+
+func (s testMetadataStore) ListMetadata() []retrieval.MetricMetadata { return s }
+
+func (s testMetadataStore) GetMetadata(metric string) (retrieval.MetricMetadata, bool) {
+	for _, m := range s {
+		if m.Metric == metric {
+			return m, true
+		}
+	}
+	return retrieval.MetricMetadata{}, false
+}
+
+type testMetadataSource []*retrieval.Target
+
+func (s testMetadataSource) Targets() []*retrieval.Target { return s }
+
+type testMetadataAppender struct {
+	metadata []retrieval.MetricMetadata
+}
+
+func (a *testMetadataAppender) AppendMetadata(_ context.Context, metadata []retrieval.MetricMetadata) {
+	a.metadata = append(a.metadata, metadata...)
+}
+
+func TestMetadataWatcherCollect(t *testing.T) {
+	up := retrieval.MetricMetadata{Metric: "up", Type: textparse.MetricTypeGauge, Help: "Target is up."}
+	requests := retrieval.MetricMetadata{Metric: "requests_total", Type: textparse.MetricTypeCounter, Help: "Requests."}
+	otherRequests := retrieval.MetricMetadata{Metric: "requests_total", Type: textparse.MetricTypeCounter, Help: "Other requests."}
+
+	var source testMetadataSource
+	for _, tc := range []struct {
+		instance string
+		store    testMetadataStore
+	}{
+		{instance: "a", store: testMetadataStore{up, requests}},
+		{instance: "b", store: testMetadataStore{up, otherRequests}},
+		// Targets which have not been scraped yet have no metadata store.
+		{instance: "c"},
+	} {
+		target := retrieval.NewTarget(labels.FromStrings("instance", tc.instance), nil, nil)
+		if tc.store != nil {
+			target.SetMetadataStore(tc.store)
+		}
+		source = append(source, target)
+	}
+
+	appender := &testMetadataAppender{}
+	mw := NewMetadataWatcher(nil, source, appender, 0)
+	mw.collect()
+
+	expected := []retrieval.MetricMetadata{up, requests, otherRequests}
+	if !reflect.DeepEqual(appender.metadata, expected) {
+		t.Fatalf("unexpected metadata: got %v, want %v", appender.metadata, expected)
+	}
+
+	// Nothing is passed on without metadata.
+	appender.metadata = nil
+	NewMetadataWatcher(nil, testMetadataSource{source[2]}, appender, 0).collect()
+	if appender.metadata != nil {
+		t.Fatalf("unexpected metadata: %v", appender.metadata)
+	}
+}
@@ -14,8 +14,10 @@
 package remote
 
 import (
+	"context"
 	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/time/rate"
@@ -27,6 +29,7 @@ import (
 	"github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/prompb"
 	"github.com/prometheus/prometheus/relabel"
+	"github.com/prometheus/prometheus/retrieval"
 )
 
 // String constants for instrumentation.
@@ -67,6 +70,15 @@ var (
 		},
 		[]string{queue},
 	)
+	retriesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "retries_total",
+			Help:      "Total number of times sending a batch of samples to remote storage was retried.",
+		},
+		[]string{queue},
+	)
 	droppedSamplesTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: namespace,
@@ -104,6 +116,24 @@ var (
 		},
 		[]string{queue},
 	)
+	succeededMetadataTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "succeeded_metadata_total",
+			Help:      "Total number of metric metadata entries successfully sent to remote storage.",
+		},
+		[]string{queue},
+	)
+	failedMetadataTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "failed_metadata_total",
+			Help:      "Total number of metric metadata entries which failed on send to remote storage.",
+		},
+		[]string{queue},
+	)
 	numShards = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: namespace,
@@ -118,36 +148,36 @@ var (
 func init() {
 	prometheus.MustRegister(succeededSamplesTotal)
 	prometheus.MustRegister(failedSamplesTotal)
+	prometheus.MustRegister(retriesTotal)
 	prometheus.MustRegister(droppedSamplesTotal)
 	prometheus.MustRegister(sentBatchDuration)
 	prometheus.MustRegister(queueLength)
 	prometheus.MustRegister(queueCapacity)
 	prometheus.MustRegister(numShards)
-}
-
-// StorageClient defines an interface for sending a batch of samples to an
-// external timeseries database.
-type StorageClient interface {
-	// Store stores the given samples in the remote storage.
-	Store(*prompb.WriteRequest) error
-	// Name identifies the remote storage implementation.
-	Name() string
+	prometheus.MustRegister(succeededMetadataTotal)
+	prometheus.MustRegister(failedMetadataTotal)
 }
 
 // QueueManager manages a queue of samples to be sent to the Storage
-// indicated by the provided StorageClient.
+// indicated by the provided WriteStorage.
 type QueueManager struct {
 	logger log.Logger
 
 	cfg            config.QueueConfig
 	externalLabels model.LabelSet
 	relabelConfigs []*config.RelabelConfig
-	client         StorageClient
+	client         WriteStorage
 	queueName      string
 	logLimiter     *rate.Limiter
+	// Whether batches failing with recoverable errors are retried until they
+	// are sent, ignoring MaxRetries, rather than dropped. Set for samples read
+	// from the WAL, which are read again after a restart unless handled.
+	retryUntilSent bool
 
 	shardsMtx   sync.Mutex
 	shards      *shards
+	lastSeq     uint64 // Sequence number of the last enqueued sample.
+	handledSeq  uint64 // See HandledSeq.
 	numShards   int
 	reshardChan chan int
 	quit        chan struct{}
@@ -158,7 +188,7 @@ type QueueManager struct {
 }
 
 // NewQueueManager builds a new QueueManager.
-func NewQueueManager(logger log.Logger, cfg config.QueueConfig, externalLabels model.LabelSet, relabelConfigs []*config.RelabelConfig, client StorageClient) *QueueManager {
+func NewQueueManager(logger log.Logger, cfg config.QueueConfig, externalLabels model.LabelSet, relabelConfigs []*config.RelabelConfig, client WriteStorage) *QueueManager {
 	if logger == nil {
 		logger = log.NewNopLogger()
 	}
@@ -187,15 +217,116 @@ func NewQueueManager(logger log.Logger, cfg config.QueueConfig, externalLabels m
 	sentBatchDuration.WithLabelValues(t.queueName)
 	succeededSamplesTotal.WithLabelValues(t.queueName)
 	failedSamplesTotal.WithLabelValues(t.queueName)
+	retriesTotal.WithLabelValues(t.queueName)
 	droppedSamplesTotal.WithLabelValues(t.queueName)
+	succeededMetadataTotal.WithLabelValues(t.queueName)
+	failedMetadataTotal.WithLabelValues(t.queueName)
 
 	return t
 }
 
+// AppendMetadata sends the metric metadata to the remote storage in batches
+// of at most MaxSamplesPerSend entries. Failed batches are dropped after the
+// configured retries or once the context is canceled.
+func (t *QueueManager) AppendMetadata(ctx context.Context, metadata []retrieval.MetricMetadata) {
+	mm := make([]*prompb.MetricMetadata, 0, len(metadata))
+	for _, m := range metadata {
+		mm = append(mm, &prompb.MetricMetadata{
+			MetricFamilyName: m.Metric,
+			Type:             metricTypeToMetricTypeProto(m.Type),
+			Help:             m.Help,
+			Unit:             m.Unit,
+		})
+	}
+
+	for len(mm) > 0 {
+		n := t.cfg.MaxSamplesPerSend
+		if n > len(mm) {
+			n = len(mm)
+		}
+		if err := t.sendMetadataWithBackoff(ctx, mm[:n]); err != nil {
+			failedMetadataTotal.WithLabelValues(t.queueName).Add(float64(n))
+			level.Error(t.logger).Log("msg", "Non-recoverable error while sending metadata", "count", n, "err", err)
+		} else {
+			succeededMetadataTotal.WithLabelValues(t.queueName).Add(float64(n))
+		}
+		mm = mm[n:]
+	}
+}
+
+// sendMetadataWithBackoff sends the metadata to the remote storage with
+// backoff for recoverable errors.
+func (t *QueueManager) sendMetadataWithBackoff(ctx context.Context, metadata []*prompb.MetricMetadata) error {
+	req := &prompb.WriteRequest{Metadata: metadata}
+	backoff := t.cfg.MinBackoff
+	for retries := t.cfg.MaxRetries; ; retries-- {
+		err := t.client.Store(req)
+		if err == nil {
+			return nil
+		}
+		rerr, ok := err.(recoverableError)
+		if !ok || retries <= 1 {
+			return err
+		}
+		level.Warn(t.logger).Log("msg", "Error sending metadata to remote storage, retrying", "count", len(metadata), "err", err)
+
+		sleep := backoff
+		if rerr.retryAfter > sleep {
+			sleep = rerr.retryAfter
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(sleep):
+		}
+		backoff = backoff * 2
+		if backoff > t.cfg.MaxBackoff {
+			backoff = t.cfg.MaxBackoff
+		}
+	}
+}
+
 // Append queues a sample to be sent to the remote storage. It drops the
 // sample on the floor if the queue is full.
 // Always returns nil.
 func (t *QueueManager) Append(s *model.Sample) error {
+	t.append(s, nil)
+	return nil
+}
+
+// AppendWait queues a sample to be sent to the remote storage. Unlike Append
+// it waits for room in the queue instead of dropping the sample. It returns
+// false if cancel was closed before the sample could be queued.
+func (t *QueueManager) AppendWait(s *model.Sample, cancel <-chan struct{}) bool {
+	return t.append(s, cancel)
+}
+
+// LastSeq returns the sequence number of the last queued sample. Sequence
+// numbers start at 1 and are assigned in the order samples are queued.
+func (t *QueueManager) LastSeq() uint64 {
+	t.shardsMtx.Lock()
+	defer t.shardsMtx.Unlock()
+
+	return t.lastSeq
+}
+
+// HandledSeq returns the highest sequence number for which all samples
+// queued up to and including it have either been sent to the remote storage
+// or failed permanently. Samples retried until sent only fail permanently on
+// non-recoverable errors.
+func (t *QueueManager) HandledSeq() uint64 {
+	t.shardsMtx.Lock()
+	defer t.shardsMtx.Unlock()
+
+	if seq, ok := t.shards.handledSeq(t.lastSeq); ok && seq > t.handledSeq {
+		t.handledSeq = seq
+	}
+	return t.handledSeq
+}
+
+// append queues the sample. If cancel is nil, the sample is dropped if the
+// queue is full. Otherwise it blocks until there is room or cancel is closed.
+func (t *QueueManager) append(s *model.Sample, cancel <-chan struct{}) bool {
 	var snew model.Sample
 	snew = *s
 	snew.Metric = s.Metric.Clone()
@@ -210,22 +341,22 @@ func (t *QueueManager) Append(s *model.Sample) error {
 		relabel.Process(model.LabelSet(snew.Metric), t.relabelConfigs...))
 
 	if snew.Metric == nil {
-		return nil
+		return true
 	}
 
 	t.shardsMtx.Lock()
-	enqueued := t.shards.enqueue(&snew)
+	enqueued := t.shards.enqueue(&snew, cancel)
 	t.shardsMtx.Unlock()
 
 	if enqueued {
 		queueLength.WithLabelValues(t.queueName).Inc()
-	} else {
+	} else if cancel == nil {
 		droppedSamplesTotal.WithLabelValues(t.queueName).Inc()
 		if t.logLimiter.Allow() {
 			level.Warn(t.logger).Log("msg", "Remote storage queue full, discarding sample. Multiple subsequent messages of this kind may be suppressed.")
 		}
 	}
-	return nil
+	return enqueued
 }
 
 // NeedsThrottling implements storage.SampleAppender. It will always return
@@ -371,20 +502,40 @@ func (t *QueueManager) reshard(n int) {
 
 type shards struct {
 	qm     *QueueManager
-	queues []chan *model.Sample
+	queues []chan queuedSample
 	done   chan struct{}
 	wg     sync.WaitGroup
+
+	// Sequence numbers of the last sample enqueued to and the last sample
+	// handled by each shard. Each shard handles its samples in order.
+	enqueued []uint64 // Protected by the queue manager's shardsMtx.
+	handled  []uint64 // Accessed atomically.
+	running  int32    // Accessed atomically.
 }
 
+// queuedSample is a sample along with the sequence number it was queued with.
+type queuedSample struct {
+	*model.Sample
+	seq uint64
+}
+
+// newShards must be called with the shardsMtx held.
 func (t *QueueManager) newShards(numShards int) *shards {
-	queues := make([]chan *model.Sample, numShards)
+	queues := make([]chan queuedSample, numShards)
 	for i := 0; i < numShards; i++ {
-		queues[i] = make(chan *model.Sample, t.cfg.Capacity)
+		queues[i] = make(chan queuedSample, t.cfg.Capacity)
 	}
 	s := &shards{
-		qm:     t,
-		queues: queues,
-		done:   make(chan struct{}),
+		qm:       t,
+		queues:   queues,
+		done:     make(chan struct{}),
+		enqueued: make([]uint64, numShards),
+		handled:  make([]uint64, numShards),
+	}
+	// All samples queued so far go to other shards.
+	for i := range s.handled {
+		s.enqueued[i] = t.lastSeq
+		s.handled[i] = t.lastSeq
 	}
 	s.wg.Add(numShards)
 	return s
@@ -395,6 +546,7 @@ func (s *shards) len() int {
 }
 
 func (s *shards) start() {
+	atomic.StoreInt32(&s.running, 1)
 	for i := 0; i < len(s.queues); i++ {
 		go s.runShard(i)
 	}
@@ -407,18 +559,51 @@ func (s *shards) stop() {
 	s.wg.Wait()
 }
 
-func (s *shards) enqueue(sample *model.Sample) bool {
+// enqueue must be called with the shardsMtx held. If cancel is nil, it does
+// not block.
+func (s *shards) enqueue(sample *model.Sample, cancel <-chan struct{}) bool {
 	s.qm.samplesIn.incr(1)
 
 	fp := sample.Metric.FastFingerprint()
 	shard := uint64(fp) % uint64(len(s.queues))
+	qs := queuedSample{Sample: sample, seq: s.qm.lastSeq + 1}
 
-	select {
-	case s.queues[shard] <- sample:
-		return true
-	default:
-		return false
+	if cancel == nil {
+		select {
+		case s.queues[shard] <- qs:
+		default:
+			return false
+		}
+	} else {
+		select {
+		case s.queues[shard] <- qs:
+		case <-cancel:
+			return false
+		}
 	}
+	s.qm.lastSeq = qs.seq
+	s.enqueued[shard] = qs.seq
+	return true
+}
+
+// handledSeq returns the highest sequence number up to which all samples have
+// been handled, given the sequence number of the last enqueued sample. It
+// must be called with the shardsMtx held and returns false if the shards have
+// not been started yet, as samples may still be pending in previous shards.
+func (s *shards) handledSeq(last uint64) (uint64, bool) {
+	if atomic.LoadInt32(&s.running) == 0 {
+		return 0, false
+	}
+	// A sample in any shard is handled if its sequence number is no higher
+	// than the last handled one of every shard that still has pending samples.
+	seq := last
+	for i := range s.queues {
+		h := atomic.LoadUint64(&s.handled[i])
+		if h < s.enqueued[i] && h < seq {
+			seq = h
+		}
+	}
+	return seq, true
 }
 
 func (s *shards) runShard(i int) {
@@ -429,6 +614,23 @@ func (s *shards) runShard(i int) {
 	// If we have fewer samples than that, flush them out after a deadline
 	// anyways.
 	pendingSamples := model.Samples{}
+	// The sequence numbers of the pending samples.
+	pendingSeqs := []uint64{}
+
+	// Once a batch was given up without being handled, the samples after it
+	// are not handled either, so that they are all read again after a restart.
+	aborted := false
+
+	send := func(n int) {
+		if aborted || !s.sendSamples(pendingSamples[:n]) {
+			aborted = true
+		} else {
+			atomic.StoreUint64(&s.handled[i], pendingSeqs[n-1])
+		}
+
+		pendingSamples = pendingSamples[n:]
+		pendingSeqs = pendingSeqs[n:]
+	}
 
 	for {
 		select {
@@ -436,42 +638,50 @@ func (s *shards) runShard(i int) {
 			if !ok {
 				if len(pendingSamples) > 0 {
 					level.Debug(s.qm.logger).Log("msg", "Flushing samples to remote storage...", "count", len(pendingSamples))
-					s.sendSamples(pendingSamples)
+					send(len(pendingSamples))
 					level.Debug(s.qm.logger).Log("msg", "Done flushing.")
 				}
 				return
 			}
 
 			queueLength.WithLabelValues(s.qm.queueName).Dec()
-			pendingSamples = append(pendingSamples, sample)
+			pendingSamples = append(pendingSamples, sample.Sample)
+			pendingSeqs = append(pendingSeqs, sample.seq)
 
 			for len(pendingSamples) >= s.qm.cfg.MaxSamplesPerSend {
-				s.sendSamples(pendingSamples[:s.qm.cfg.MaxSamplesPerSend])
-				pendingSamples = pendingSamples[s.qm.cfg.MaxSamplesPerSend:]
+				send(s.qm.cfg.MaxSamplesPerSend)
 			}
 		case <-time.After(s.qm.cfg.BatchSendDeadline):
 			if len(pendingSamples) > 0 {
-				s.sendSamples(pendingSamples)
-				pendingSamples = pendingSamples[:0]
+				send(len(pendingSamples))
 			}
 		}
 	}
 }
 
-func (s *shards) sendSamples(samples model.Samples) {
+// sendSamples sends the samples and returns whether they were handled, see
+// sendSamplesWithBackoff.
+func (s *shards) sendSamples(samples model.Samples) bool {
 	begin := time.Now()
-	s.sendSamplesWithBackoff(samples)
+	handled := s.sendSamplesWithBackoff(samples)
 
 	// These counters are used to caclulate the dynamic sharding, and as such
 	// should be maintained irrespective of success or failure.
 	s.qm.samplesOut.incr(int64(len(samples)))
 	s.qm.samplesOutDuration.incr(int64(time.Since(begin)))
+
+	return handled
 }
 
 // sendSamples to the remote storage with backoff for recoverable errors.
-func (s *shards) sendSamplesWithBackoff(samples model.Samples) {
+// If the remote storage asks to retry after a certain time, it is waited
+// for at least that long, even if longer than the maximum backoff. Retries
+// are given up once the queue manager is stopped. It returns whether the
+// samples were handled, that is sent or failed permanently. Samples retried
+// until sent are not handled if given up.
+func (s *shards) sendSamplesWithBackoff(samples model.Samples) bool {
 	backoff := s.qm.cfg.MinBackoff
-	for retries := s.qm.cfg.MaxRetries; retries > 0; retries-- {
+	for retries := s.qm.cfg.MaxRetries; ; retries-- {
 		begin := time.Now()
 		req := ToWriteRequest(samples)
 		err := s.qm.client.Store(req)
@@ -479,19 +689,36 @@ func (s *shards) sendSamplesWithBackoff(samples model.Samples) {
 		sentBatchDuration.WithLabelValues(s.qm.queueName).Observe(time.Since(begin).Seconds())
 		if err == nil {
 			succeededSamplesTotal.WithLabelValues(s.qm.queueName).Add(float64(len(samples)))
-			return
+			return true
 		}
 
 		level.Warn(s.qm.logger).Log("msg", "Error sending samples to remote storage", "count", len(samples), "err", err)
-		if _, ok := err.(recoverableError); !ok {
-			break
+		rerr, ok := err.(recoverableError)
+		if !ok || retries <= 1 && !s.qm.retryUntilSent {
+			failedSamplesTotal.WithLabelValues(s.qm.queueName).Add(float64(len(samples)))
+			return true
+		}
+		retriesTotal.WithLabelValues(s.qm.queueName).Inc()
+
+		sleep := backoff
+		if rerr.retryAfter > sleep {
+			sleep = rerr.retryAfter
+		}
+		timer := time.NewTimer(sleep)
+		select {
+		case <-timer.C:
+		case <-s.qm.quit:
+			timer.Stop()
+			// Samples retried until sent are sent again after a restart.
+			if s.qm.retryUntilSent {
+				return false
+			}
+			failedSamplesTotal.WithLabelValues(s.qm.queueName).Add(float64(len(samples)))
+			return true
 		}
-		time.Sleep(backoff)
 		backoff = backoff * 2
 		if backoff > s.qm.cfg.MaxBackoff {
 			backoff = s.qm.cfg.MaxBackoff
 		}
 	}
-
-	failedSamplesTotal.WithLabelValues(s.qm.queueName).Add(float64(len(samples)))
 }
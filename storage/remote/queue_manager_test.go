@@ -14,6 +14,7 @@
 package remote
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"sync"
@@ -23,7 +24,9 @@ import (
 
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/pkg/textparse"
 	"github.com/prometheus/prometheus/prompb"
+	"github.com/prometheus/prometheus/retrieval"
 )
 
 type TestStorageClient struct {
@@ -152,6 +155,239 @@ func TestSampleDeliveryOrder(t *testing.T) {
 	c.waitForExpectedSamples(t)
 }
 
+func TestHandledSeq(t *testing.T) {
+	n := config.DefaultQueueConfig.MaxSamplesPerSend * 2
+
+	samples := make(model.Samples, 0, n)
+	for i := 0; i < n; i++ {
+		name := model.LabelValue(fmt.Sprintf("test_metric_%d", i%10))
+		samples = append(samples, &model.Sample{
+			Metric: model.Metric{
+				model.MetricNameLabel: name,
+			},
+			Value: model.SampleValue(i),
+		})
+	}
+
+	c := NewTestStorageClient()
+	c.expectSamples(samples)
+
+	cfg := config.DefaultQueueConfig
+	cfg.MaxShards = 4
+	m := NewQueueManager(nil, cfg, nil, nil, c)
+	// Spread the samples over multiple shards that send independently.
+	m.shards = m.newShards(4)
+
+	for _, s := range samples {
+		if !m.AppendWait(s, nil) {
+			t.Fatalf("sample %v not queued", s)
+		}
+	}
+	if seq := m.LastSeq(); seq != uint64(n) {
+		t.Fatalf("expected last sequence number %d, got %d", n, seq)
+	}
+	// Nothing has been sent before the queue manager is started.
+	if seq := m.HandledSeq(); seq != 0 {
+		t.Fatalf("expected handled sequence number 0, got %d", seq)
+	}
+
+	m.Start()
+	c.waitForExpectedSamples(t)
+	m.Stop()
+
+	if seq := m.HandledSeq(); seq != uint64(n) {
+		t.Fatalf("expected handled sequence number %d, got %d", n, seq)
+	}
+}
+
+// TestFailingStorageClient is a queue_manager StorageClient which fails the
+// first `failures` calls to Store() with the given error.
+type TestFailingStorageClient struct {
+	numCalls uint64
+	failures uint64
+	err      error
+}
+
+func (c *TestFailingStorageClient) Store(_ *prompb.WriteRequest) error {
+	if atomic.AddUint64(&c.numCalls, 1) <= c.failures {
+		return c.err
+	}
+	return nil
+}
+
+func (c *TestFailingStorageClient) Name() string {
+	return "testfailingstorageclient"
+}
+
+func TestSendSamplesRetries(t *testing.T) {
+	tests := []struct {
+		err           error
+		failures      uint64
+		expectedCalls uint64
+	}{
+		{
+			// Recoverable errors are retried.
+			err:           recoverableError{fmt.Errorf("server returned HTTP status 503"), 0},
+			failures:      2,
+			expectedCalls: 3,
+		},
+		{
+			// The Retry-After time is waited for.
+			err:           recoverableError{fmt.Errorf("server returned HTTP status 429"), 10 * time.Millisecond},
+			failures:      1,
+			expectedCalls: 2,
+		},
+		{
+			// The Retry-After time is waited for even if longer than the
+			// maximum backoff.
+			err:           recoverableError{fmt.Errorf("server returned HTTP status 429"), 50 * time.Millisecond},
+			failures:      1,
+			expectedCalls: 2,
+		},
+		{
+			// Other errors are not retried.
+			err:           fmt.Errorf("server returned HTTP status 400"),
+			failures:      2,
+			expectedCalls: 1,
+		},
+		{
+			// At most MaxRetries attempts are made.
+			err:           recoverableError{fmt.Errorf("server returned HTTP status 500"), 0},
+			failures:      100,
+			expectedCalls: 3,
+		},
+	}
+
+	for i, test := range tests {
+		c := &TestFailingStorageClient{failures: test.failures, err: test.err}
+		cfg := config.DefaultQueueConfig
+		cfg.MaxRetries = 3
+		cfg.MinBackoff = time.Millisecond
+		cfg.MaxBackoff = 20 * time.Millisecond
+		m := NewQueueManager(nil, cfg, nil, nil, c)
+
+		begin := time.Now()
+		m.shards.sendSamplesWithBackoff(model.Samples{{Metric: model.Metric{model.MetricNameLabel: "test_metric"}}})
+
+		if calls := atomic.LoadUint64(&c.numCalls); calls != test.expectedCalls {
+			t.Errorf("%d. Unexpected number of calls; want %d, got %d", i, test.expectedCalls, calls)
+		}
+		if rerr, ok := test.err.(recoverableError); ok {
+			wait := rerr.retryAfter
+			if took := time.Since(begin); took < wait {
+				t.Errorf("%d. Retried before %v passed", i, wait)
+			} else if took > time.Minute {
+				t.Errorf("%d. Retried after %v", i, took)
+			}
+		}
+	}
+}
+
+func TestSendSamplesRetriesUntilSent(t *testing.T) {
+	n := config.DefaultQueueConfig.MaxSamplesPerSend
+
+	c := &TestFailingStorageClient{
+		failures: 5,
+		err:      recoverableError{fmt.Errorf("server returned HTTP status 503"), 0},
+	}
+	cfg := config.DefaultQueueConfig
+	cfg.MaxRetries = 2
+	cfg.MinBackoff = time.Millisecond
+	cfg.MaxBackoff = time.Millisecond
+	m := NewQueueManager(nil, cfg, nil, nil, c)
+	// Samples read from the WAL are retried beyond MaxRetries.
+	m.retryUntilSent = true
+
+	for i := 0; i < n; i++ {
+		if !m.AppendWait(&model.Sample{Metric: model.Metric{model.MetricNameLabel: "test_metric"}, Value: model.SampleValue(i)}, nil) {
+			t.Fatalf("sample %d not queued", i)
+		}
+	}
+	m.Start()
+	defer m.Stop()
+
+	// The samples are only handled once they were sent.
+	deadline := time.Now().Add(5 * time.Second)
+	for m.HandledSeq() != uint64(n) {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected handled sequence number %d, got %d", n, m.HandledSeq())
+		}
+		if seq := m.HandledSeq(); seq != 0 && atomic.LoadUint64(&c.numCalls) <= c.failures {
+			t.Fatalf("samples handled before they were sent")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if calls := atomic.LoadUint64(&c.numCalls); calls != c.failures+1 {
+		t.Fatalf("Unexpected number of calls; want %d, got %d", c.failures+1, calls)
+	}
+}
+
+func TestSendSamplesRetriesStopped(t *testing.T) {
+	for _, retryUntilSent := range []bool{false, true} {
+		c := &TestFailingStorageClient{
+			failures: 1,
+			err:      recoverableError{fmt.Errorf("server returned HTTP status 429"), time.Hour},
+		}
+		cfg := config.DefaultQueueConfig
+		cfg.MaxRetries = 3
+		cfg.MaxBackoff = time.Hour
+		m := NewQueueManager(nil, cfg, nil, nil, c)
+		m.retryUntilSent = retryUntilSent
+
+		var handled bool
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			handled = m.shards.sendSamplesWithBackoff(model.Samples{{Metric: model.Metric{model.MetricNameLabel: "test_metric"}}})
+		}()
+		for atomic.LoadUint64(&c.numCalls) == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		close(m.quit)
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Retry was not given up when stopped")
+		}
+		if calls := atomic.LoadUint64(&c.numCalls); calls != 1 {
+			t.Fatalf("Unexpected number of calls; want 1, got %d", calls)
+		}
+		// Samples retried until sent stay unhandled to be sent again after
+		// a restart, others are dropped.
+		if handled == retryUntilSent {
+			t.Fatalf("Unexpected handled %v of samples retried until sent %v", handled, retryUntilSent)
+		}
+	}
+}
+
+func TestHandledSeqStopped(t *testing.T) {
+	c := &TestFailingStorageClient{
+		failures: 1,
+		err:      recoverableError{fmt.Errorf("server returned HTTP status 429"), time.Hour},
+	}
+	cfg := config.DefaultQueueConfig
+	cfg.BatchSendDeadline = time.Millisecond
+	m := NewQueueManager(nil, cfg, nil, nil, c)
+	m.retryUntilSent = true
+
+	for i := 0; i < 2; i++ {
+		if !m.AppendWait(&model.Sample{Metric: model.Metric{model.MetricNameLabel: "test_metric"}, Value: model.SampleValue(i)}, nil) {
+			t.Fatalf("sample %d not queued", i)
+		}
+	}
+	m.Start()
+	for atomic.LoadUint64(&c.numCalls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	m.Stop()
+
+	// The batch given up when stopping is not handled.
+	if seq := m.HandledSeq(); seq != 0 {
+		t.Fatalf("expected handled sequence number 0, got %d", seq)
+	}
+}
+
 // TestBlockingStorageClient is a queue_manager StorageClient which will block
 // on any calls to Store(), until the `block` channel is closed, at which point
 // the `numCalls` property will contain a count of how many times Store() was
@@ -258,3 +494,44 @@ func TestSpawnNotMoreThanMaxConcurrentSendsGoroutines(t *testing.T) {
 		t.Errorf("Saw %d concurrent sends, expected 1", numCalls)
 	}
 }
+
+// TestMetadataStorageClient is a queue_manager StorageClient which records the
+// metadata of all requests.
+type TestMetadataStorageClient struct {
+	requests [][]*prompb.MetricMetadata
+}
+
+func (c *TestMetadataStorageClient) Store(req *prompb.WriteRequest) error {
+	c.requests = append(c.requests, req.Metadata)
+	return nil
+}
+
+func (c *TestMetadataStorageClient) Name() string {
+	return "testmetadatastorageclient"
+}
+
+func TestAppendMetadata(t *testing.T) {
+	c := &TestMetadataStorageClient{}
+	cfg := config.DefaultQueueConfig
+	cfg.MaxSamplesPerSend = 2
+	m := NewQueueManager(nil, cfg, nil, nil, c)
+
+	m.AppendMetadata(context.Background(), []retrieval.MetricMetadata{
+		{Metric: "up", Type: textparse.MetricTypeGauge, Help: "Target is up."},
+		{Metric: "requests_total", Type: textparse.MetricTypeCounter, Help: "Requests."},
+		{Metric: "latency_seconds", Type: textparse.MetricTypeHistogram, Unit: "seconds"},
+	})
+
+	expected := [][]*prompb.MetricMetadata{
+		{
+			{MetricFamilyName: "up", Type: prompb.MetricMetadata_GAUGE, Help: "Target is up."},
+			{MetricFamilyName: "requests_total", Type: prompb.MetricMetadata_COUNTER, Help: "Requests."},
+		},
+		{
+			{MetricFamilyName: "latency_seconds", Type: prompb.MetricMetadata_HISTOGRAM, Unit: "seconds"},
+		},
+	}
+	if !reflect.DeepEqual(c.requests, expected) {
+		t.Fatalf("unexpected requests: got %v, want %v", c.requests, expected)
+	}
+}
@@ -15,6 +15,7 @@ package remote
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/pkg/labels"
@@ -43,15 +44,51 @@ func (r *Storage) Querier(ctx context.Context, mint, maxt int64) (storage.Querie
 				cmaxt = localStartTime
 			}
 		}
-		queriers = append(queriers, &querier{
+		var q storage.Querier = &querier{
 			ctx:            ctx,
 			mint:           mint,
 			maxt:           cmaxt,
-			client:         c,
+			client:         c.client,
 			externalLabels: r.externalLabels,
-		})
+		}
+		if len(c.requiredMatchers) > 0 {
+			q = requiredMatchersFilter(q, c.requiredMatchers)
+		}
+		queriers = append(queriers, q)
 	}
-	return newMergeQueriers(queriers), nil
+	// Failing endpoints only cause warnings, so that the others and the
+	// local storage can still answer queries.
+	return newMergeQueriers(nil, queriers), nil
+}
+
+// readEndpoint is a remote read endpoint with the filters applied to its
+// queries.
+type readEndpoint struct {
+	client           ReadClient
+	readRecent       bool
+	requiredMatchers []*labels.Matcher
+}
+
+// newReadEndpoint creates the index-th remote read endpoint.
+func newReadEndpoint(index int, conf *ClientConfig) (*readEndpoint, error) {
+	requiredMatchers := make([]*labels.Matcher, 0, len(conf.RequiredMatchers))
+	for name, value := range conf.RequiredMatchers {
+		m, err := labels.NewMatcher(labels.MatchEqual, string(name), string(value))
+		if err != nil {
+			return nil, err
+		}
+		requiredMatchers = append(requiredMatchers, m)
+	}
+
+	c, err := newReadClient(index, conf)
+	if err != nil {
+		return nil, err
+	}
+	return &readEndpoint{
+		client:           c,
+		readRecent:       conf.ReadRecent,
+		requiredMatchers: requiredMatchers,
+	}, nil
 }
 
 // Store it in variable to make it mockable in tests since a mergeQuerier is not publicly exposed.
@@ -61,27 +98,69 @@ var newMergeQueriers = storage.NewMergeQuerier
 type querier struct {
 	ctx            context.Context
 	mint, maxt     int64
-	client         *Client
+	client         ReadClient
 	externalLabels model.LabelSet
 }
 
 // Select returns a set of series that matches the given label matchers.
-func (q *querier) Select(matchers ...*labels.Matcher) storage.SeriesSet {
+func (q *querier) Select(p *storage.SelectParams, matchers ...*labels.Matcher) (storage.SeriesSet, storage.Warnings, error) {
 	m, added := q.addExternalLabels(matchers)
 
-	query, err := ToQuery(q.mint, q.maxt, m)
+	query, err := ToQuery(q.mint, q.maxt, m, p)
 	if err != nil {
-		return errSeriesSet{err: err}
+		return nil, nil, err
 	}
 
 	res, err := q.client.Read(q.ctx, query)
 	if err != nil {
-		return errSeriesSet{err: err}
+		return nil, nil, fmt.Errorf("remote read from %s failed: %v", q.client.Name(), err)
 	}
 
 	seriesSet := FromQueryResult(res)
 
-	return newSeriesSetFilter(seriesSet, added)
+	return newSeriesSetFilter(seriesSet, added), nil, nil
+}
+
+// requiredMatchersFilter returns a Querier which only forwards selections
+// containing all of the required equality matchers to next.
+func requiredMatchersFilter(next storage.Querier, required []*labels.Matcher) storage.Querier {
+	return &requiredMatchersQuerier{Querier: next, requiredMatchers: required}
+}
+
+type requiredMatchersQuerier struct {
+	storage.Querier
+
+	requiredMatchers []*labels.Matcher
+}
+
+// Select returns a set of series that matches the given label matchers, or an
+// empty set if not all of the required matchers are present.
+func (q requiredMatchersQuerier) Select(p *storage.SelectParams, matchers ...*labels.Matcher) (storage.SeriesSet, storage.Warnings, error) {
+	for _, r := range q.requiredMatchers {
+		if !hasEqualMatcher(matchers, r) {
+			return storage.NoopSeriesSet(), nil, nil
+		}
+	}
+	return q.Querier.Select(p, matchers...)
+}
+
+// LabelValues returns nothing as label queries cannot contain the required matchers.
+func (q requiredMatchersQuerier) LabelValues(name string) ([]string, error) {
+	return nil, nil
+}
+
+// LabelNames returns nothing as label queries cannot contain the required matchers.
+func (q requiredMatchersQuerier) LabelNames() ([]string, error) {
+	return nil, nil
+}
+
+func hasEqualMatcher(matchers []*labels.Matcher, r *labels.Matcher) bool {
+	for _, m := range matchers {
+		if m.Type == labels.MatchEqual && m.Name == r.Name && m.Value == r.Value {
+			return true
+		}
+	}
+	return false
 }
 
 type byLabel []storage.Series
@@ -92,8 +171,31 @@ func (a byLabel) Less(i, j int) bool { return labels.Compare(a[i].Labels(), a[j]
 
 // LabelValues returns all potential values for a label name.
 func (q *querier) LabelValues(name string) ([]string, error) {
-	// TODO implement?
-	return nil, nil
+	// External labels are removed from the series returned by Select,
+	// so they must not show up here either.
+	if _, ok := q.externalLabels[model.LabelName(name)]; ok {
+		return nil, nil
+	}
+	res, err := q.client.ReadLabels(q.ctx, ToLabelQuery(q.mint, q.maxt, name))
+	if err != nil {
+		return nil, err
+	}
+	return res.Values, nil
+}
+
+// LabelNames returns all the unique label names present in the remote endpoint.
+func (q *querier) LabelNames() ([]string, error) {
+	res, err := q.client.ReadLabels(q.ctx, ToLabelQuery(q.mint, q.maxt, ""))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(res.Values))
+	for _, n := range res.Values {
+		if _, ok := q.externalLabels[model.LabelName(n)]; !ok {
+			names = append(names, n)
+		}
+	}
+	return names, nil
 }
 
 // Close releases the resources of the Querier.
@@ -15,6 +15,9 @@ package remote
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"reflect"
 	"sort"
 	"testing"
@@ -133,11 +136,79 @@ func TestSeriesSetFilter(t *testing.T) {
 	}
 }
 
+type mockSelectQuerier struct{ selected bool }
+
+func (q *mockSelectQuerier) Select(*storage.SelectParams, ...*labels.Matcher) (storage.SeriesSet, storage.Warnings, error) {
+	q.selected = true
+	return storage.NoopSeriesSet(), nil, nil
+}
+func (*mockSelectQuerier) LabelValues(name string) ([]string, error) { return nil, nil }
+func (*mockSelectQuerier) LabelNames() ([]string, error)             { return nil, nil }
+func (*mockSelectQuerier) Close() error                              { return nil }
+
+func TestRequiredMatchersFilter(t *testing.T) {
+	required := []*labels.Matcher{
+		mustNewLabelMatcher(labels.MatchEqual, "job", "special"),
+		mustNewLabelMatcher(labels.MatchEqual, "env", "prod"),
+	}
+
+	tests := []struct {
+		matchers []*labels.Matcher
+		selected bool
+	}{
+		{
+			matchers: nil,
+			selected: false,
+		},
+		{
+			matchers: []*labels.Matcher{
+				mustNewLabelMatcher(labels.MatchEqual, "job", "special"),
+			},
+			selected: false,
+		},
+		{
+			matchers: []*labels.Matcher{
+				mustNewLabelMatcher(labels.MatchEqual, "job", "special"),
+				mustNewLabelMatcher(labels.MatchRegexp, "env", "prod"),
+			},
+			selected: false,
+		},
+		{
+			matchers: []*labels.Matcher{
+				mustNewLabelMatcher(labels.MatchEqual, "job", "other"),
+				mustNewLabelMatcher(labels.MatchEqual, "env", "prod"),
+			},
+			selected: false,
+		},
+		{
+			matchers: []*labels.Matcher{
+				mustNewLabelMatcher(labels.MatchEqual, "env", "prod"),
+				mustNewLabelMatcher(labels.MatchEqual, "__name__", "up"),
+				mustNewLabelMatcher(labels.MatchEqual, "job", "special"),
+			},
+			selected: true,
+		},
+	}
+
+	for i, test := range tests {
+		next := &mockSelectQuerier{}
+		q := requiredMatchersFilter(next, required)
+		q.Select(nil, test.matchers...)
+
+		if next.selected != test.selected {
+			t.Fatalf("%d. unexpected selection; want %v, got %v", i, test.selected, next.selected)
+		}
+	}
+}
+
 type mockMergeQuerier struct{ queriersCount int }
 
-func (*mockMergeQuerier) Select(...*labels.Matcher) storage.SeriesSet { return nil }
-func (*mockMergeQuerier) LabelValues(name string) ([]string, error)   { return nil, nil }
-func (*mockMergeQuerier) Close() error                                { return nil }
+func (*mockMergeQuerier) Select(*storage.SelectParams, ...*labels.Matcher) (storage.SeriesSet, storage.Warnings, error) {
+	return nil, nil, nil
+}
+func (*mockMergeQuerier) LabelValues(name string) ([]string, error) { return nil, nil }
+func (*mockMergeQuerier) LabelNames() ([]string, error)             { return nil, nil }
+func (*mockMergeQuerier) Close() error                              { return nil }
 
 func TestRemoteStorageQuerier(t *testing.T) {
 	tests := []struct {
@@ -148,35 +219,34 @@ func TestRemoteStorageQuerier(t *testing.T) {
 		expectedQueriersCount int
 	}{
 		{
-			localStartTime:    int64(20),
-			readRecentClients: []bool{true, true, false},
-			mint:              int64(0),
-			maxt:              int64(50),
+			localStartTime:        int64(20),
+			readRecentClients:     []bool{true, true, false},
+			mint:                  int64(0),
+			maxt:                  int64(50),
 			expectedQueriersCount: 3,
 		},
 		{
-			localStartTime:    int64(20),
-			readRecentClients: []bool{true, true, false},
-			mint:              int64(30),
-			maxt:              int64(50),
+			localStartTime:        int64(20),
+			readRecentClients:     []bool{true, true, false},
+			mint:                  int64(30),
+			maxt:                  int64(50),
 			expectedQueriersCount: 2,
 		},
 	}
 
 	for i, test := range tests {
-		s := NewStorage(nil, func() (int64, error) { return test.localStartTime, nil })
-		s.clients = []*Client{}
+		s := NewStorage(nil, func() (int64, error) { return test.localStartTime, nil }, "")
+		s.clients = []*readEndpoint{}
 		for _, readRecent := range test.readRecentClients {
 			c, _ := NewClient(0, &ClientConfig{
 				URL:              nil,
 				Timeout:          model.Duration(30 * time.Second),
 				HTTPClientConfig: config.HTTPClientConfig{},
-				ReadRecent:       readRecent,
 			})
-			s.clients = append(s.clients, c)
+			s.clients = append(s.clients, &readEndpoint{client: c, readRecent: readRecent})
 		}
 		// overrides mergeQuerier to mockMergeQuerier so we can reflect its type
-		newMergeQueriers = func(queriers []storage.Querier) storage.Querier {
+		newMergeQueriers = func(_ storage.Querier, queriers []storage.Querier) storage.Querier {
 			return &mockMergeQuerier{queriersCount: len(queriers)}
 		}
 
@@ -188,3 +258,91 @@ func TestRemoteStorageQuerier(t *testing.T) {
 		}
 	}
 }
+
+func TestQuerierLabels(t *testing.T) {
+	tests := []struct {
+		// Whether the endpoint supports label queries.
+		supported      bool
+		name           string
+		externalLabels model.LabelSet
+		expected       []string
+	}{
+		{
+			supported: true,
+			name:      "job",
+			expected:  []string{"api-server", "node"},
+		},
+		{
+			supported:      true,
+			name:           "region",
+			externalLabels: model.LabelSet{"region": "europe"},
+			expected:       nil,
+		},
+		{
+			supported:      true,
+			externalLabels: model.LabelSet{"region": "europe"},
+			expected:       []string{"__name__", "job"},
+		},
+		{
+			supported: false,
+			name:      "job",
+			expected:  nil,
+		},
+	}
+
+	for i, test := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			req, err := DecodeReadRequest(r)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			resp := &prompb.ReadResponse{}
+			if test.supported {
+				for _, lq := range req.LabelQueries {
+					res := &prompb.LabelQueryResult{Values: []string{"__name__", "job", "region"}}
+					if lq.Name != "" {
+						res.Values = []string{"api-server", "node"}
+					}
+					resp.LabelResults = append(resp.LabelResults, res)
+				}
+			}
+			EncodeReadResponse(resp, w)
+		}))
+
+		serverURL, err := url.Parse(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := NewClient(0, &ClientConfig{
+			URL:     &config.URL{URL: serverURL},
+			Timeout: model.Duration(time.Second),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		q := &querier{
+			ctx:            context.Background(),
+			client:         c,
+			externalLabels: test.externalLabels,
+		}
+
+		var res []string
+		if test.name == "" {
+			res, err = q.LabelNames()
+		} else {
+			res, err = q.LabelValues(test.name)
+		}
+		if err != nil {
+			t.Fatalf("%d. unexpected error: %s", i, err)
+		}
+		if len(res) == 0 {
+			res = nil
+		}
+		if !reflect.DeepEqual(res, test.expected) {
+			t.Fatalf("%d. unexpected labels; want %v, got %v", i, test.expected, res)
+		}
+
+		server.Close()
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// WriteStorage sends batches of samples to an external timeseries database.
+type WriteStorage interface {
+	// Store stores the given samples in the remote storage.
+	Store(*prompb.WriteRequest) error
+	// Name identifies the remote storage implementation.
+	Name() string
+}
+
+// ReadClient reads samples and labels from an external timeseries database.
+type ReadClient interface {
+	// Read returns the result of a single query.
+	Read(context.Context, *prompb.Query) (*prompb.QueryResult, error)
+	// ReadLabels returns the label names or values selected by the query.
+	ReadLabels(context.Context, *prompb.LabelQuery) (*prompb.LabelQueryResult, error)
+	// Name identifies the remote storage implementation.
+	Name() string
+}
+
+// NewWriteStorageFunc creates the WriteStorage of the index-th remote write
+// endpoint.
+type NewWriteStorageFunc func(index int, conf *ClientConfig) (WriteStorage, error)
+
+// NewReadClientFunc creates the ReadClient of the index-th remote read
+// endpoint.
+type NewReadClientFunc func(index int, conf *ClientConfig) (ReadClient, error)
+
+var (
+	registryMtx sync.RWMutex
+	// Constructors of registered implementations by URL scheme.
+	writeStorages = map[string]NewWriteStorageFunc{}
+	readClients   = map[string]NewReadClientFunc{}
+)
+
+// builtinScheme reports whether endpoints with the URL scheme are handled by
+// the built-in HTTP Client.
+func builtinScheme(scheme string) bool {
+	return scheme == "http" || scheme == "https"
+}
+
+// RegisterWriteStorage registers an alternative remote write implementation
+// for endpoints whose URL has the given scheme, like kafka://broker/topic.
+// The samples of these endpoints are still queued, sharded and retried by the
+// QueueManager, but sent with the WriteStorage returned by newStorage.
+//
+// It panics if the scheme is already taken. It is meant to be called from
+// init functions.
+func RegisterWriteStorage(scheme string, newStorage NewWriteStorageFunc) {
+	registryMtx.Lock()
+	defer registryMtx.Unlock()
+
+	if builtinScheme(scheme) {
+		panic(fmt.Sprintf("remote write storage for scheme %q is built in", scheme))
+	}
+	if _, ok := writeStorages[scheme]; ok {
+		panic(fmt.Sprintf("remote write storage for scheme %q registered twice", scheme))
+	}
+	writeStorages[scheme] = newStorage
+}
+
+// RegisterReadClient registers an alternative remote read implementation for
+// endpoints whose URL has the given scheme.
+//
+// It panics if the scheme is already taken. It is meant to be called from
+// init functions.
+func RegisterReadClient(scheme string, newClient NewReadClientFunc) {
+	registryMtx.Lock()
+	defer registryMtx.Unlock()
+
+	if builtinScheme(scheme) {
+		panic(fmt.Sprintf("remote read client for scheme %q is built in", scheme))
+	}
+	if _, ok := readClients[scheme]; ok {
+		panic(fmt.Sprintf("remote read client for scheme %q registered twice", scheme))
+	}
+	readClients[scheme] = newClient
+}
+
+// newWriteStorage creates the WriteStorage for the scheme of the endpoint's
+// URL, falling back to the HTTP Client.
+func newWriteStorage(index int, conf *ClientConfig) (WriteStorage, error) {
+	registryMtx.RLock()
+	newStorage, ok := writeStorages[conf.URL.Scheme]
+	registryMtx.RUnlock()
+
+	if !ok {
+		c, err := NewClient(index, conf)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+	return newStorage(index, conf)
+}
+
+// newReadClient creates the ReadClient for the scheme of the endpoint's URL,
+// falling back to the HTTP Client.
+func newReadClient(index int, conf *ClientConfig) (ReadClient, error) {
+	registryMtx.RLock()
+	newClient, ok := readClients[conf.URL.Scheme]
+	registryMtx.RUnlock()
+
+	if !ok {
+		c, err := NewClient(index, conf)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+	return newClient(index, conf)
+}
@@ -0,0 +1,96 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/prompb"
+)
+
+type testRegisteredClient struct {
+	name string
+}
+
+func (c *testRegisteredClient) Store(*prompb.WriteRequest) error { return nil }
+
+func (c *testRegisteredClient) Read(context.Context, *prompb.Query) (*prompb.QueryResult, error) {
+	return &prompb.QueryResult{}, nil
+}
+
+func (c *testRegisteredClient) ReadLabels(context.Context, *prompb.LabelQuery) (*prompb.LabelQueryResult, error) {
+	return &prompb.LabelQueryResult{}, nil
+}
+
+func (c *testRegisteredClient) Name() string { return c.name }
+
+func TestRegisteredSchemes(t *testing.T) {
+	RegisterWriteStorage("testwrite", func(_ int, conf *ClientConfig) (WriteStorage, error) {
+		return &testRegisteredClient{name: conf.URL.String()}, nil
+	})
+	RegisterReadClient("testread", func(_ int, conf *ClientConfig) (ReadClient, error) {
+		return &testRegisteredClient{name: conf.URL.String()}, nil
+	})
+
+	for _, f := range []func(){
+		func() { RegisterWriteStorage("testwrite", nil) },
+		func() { RegisterReadClient("https", nil) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expected registration to panic")
+				}
+			}()
+			f()
+		}()
+	}
+
+	mustParse := func(u string) *config.URL {
+		parsed, err := url.Parse(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &config.URL{URL: parsed}
+	}
+	rwConf := config.DefaultRemoteWriteConfig
+	rwConf.URL = mustParse("testwrite://broker/topic")
+	rrConf := config.DefaultRemoteReadConfig
+	rrConf.URL = mustParse("testread://store")
+	rrConf.ReadRecent = true
+
+	s := NewStorage(nil, nil, "")
+	defer s.Close()
+
+	err := s.ApplyConfig(&config.Config{
+		RemoteWriteConfigs: []*config.RemoteWriteConfig{&rwConf},
+		RemoteReadConfigs:  []*config.RemoteReadConfig{&rrConf},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c, ok := s.queues[0].client.(*testRegisteredClient); !ok || c.name != "testwrite://broker/topic" {
+		t.Fatalf("unexpected write storage %#v", s.queues[0].client)
+	}
+	if c, ok := s.clients[0].client.(*testRegisteredClient); !ok || c.name != "testread://store" {
+		t.Fatalf("unexpected read client %#v", s.clients[0].client)
+	}
+	if !s.clients[0].readRecent {
+		t.Fatalf("read_recent was not applied to the registered read client")
+	}
+}
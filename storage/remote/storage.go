@@ -14,7 +14,10 @@
 package remote
 
 import (
+	"path/filepath"
+	"reflect"
 	"sync"
+	"time"
 
 	"github.com/go-kit/kit/log"
 	"github.com/prometheus/common/model"
@@ -29,22 +32,40 @@ type startTimeCallback func() (int64, error)
 type Storage struct {
 	logger log.Logger
 	mtx    sync.RWMutex
+	dir    string
 
 	// For writes
-	queues []*QueueManager
+	queues           []*QueueManager
+	watchers         []*WALWatcher
+	metadataWatchers []*MetadataWatcher
+	writeConfigs     []*config.RemoteWriteConfig
+	metadataSource   MetadataSource
 
 	// For reads
-	clients                []*Client
+	clients                []*readEndpoint
+	readConfigs            []*config.RemoteReadConfig
 	localStartTimeCallback startTimeCallback
 	externalLabels         model.LabelSet
 }
 
-// NewStorage returns a remote.Storage.
-func NewStorage(l log.Logger, stCallback startTimeCallback) *Storage {
+// NewStorage returns a remote.Storage. If dir is set, samples are not appended
+// to the Storage but read from the write ahead log of the local TSDB in dir.
+// The positions up to which samples were sent to each remote write endpoint
+// are checkpointed in dir as well.
+func NewStorage(l log.Logger, stCallback startTimeCallback, dir string) *Storage {
 	if l == nil {
 		l = log.NewNopLogger()
 	}
-	return &Storage{logger: l, localStartTimeCallback: stCallback}
+	return &Storage{logger: l, localStartTimeCallback: stCallback, dir: dir}
+}
+
+// SetMetadataSource sets the source of the metric metadata sent to the remote
+// write endpoints. It only applies to endpoints configured afterwards.
+func (s *Storage) SetMetadataSource(source MetadataSource) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.metadataSource = source
 }
 
 // ApplyConfig updates the state as the new config requires.
@@ -52,47 +73,96 @@ func (s *Storage) ApplyConfig(conf *config.Config) error {
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
 
-	// Update write queues
+	// Update write queues. Queues whose configuration did not change keep
+	// sending without interruption.
 
-	newQueues := []*QueueManager{}
-	// TODO: we should only stop & recreate queues which have changes,
-	// as this can be quite disruptive.
+	var (
+		newQueues           = make([]*QueueManager, len(conf.RemoteWriteConfigs))
+		newWatchers         = make([]*WALWatcher, len(conf.RemoteWriteConfigs))
+		newMetadataWatchers = make([]*MetadataWatcher, len(conf.RemoteWriteConfigs))
+		reused              = map[int]bool{}
+	)
 	for i, rwConf := range conf.RemoteWriteConfigs {
-		c, err := NewClient(i, &ClientConfig{
+		if i < len(s.writeConfigs) &&
+			reflect.DeepEqual(s.writeConfigs[i], rwConf) &&
+			reflect.DeepEqual(s.externalLabels, conf.GlobalConfig.ExternalLabels) {
+			newQueues[i] = s.queues[i]
+			if s.dir != "" {
+				newWatchers[i] = s.watchers[i]
+			}
+			newMetadataWatchers[i] = s.metadataWatchers[i]
+			reused[i] = true
+			continue
+		}
+		c, err := newWriteStorage(i, &ClientConfig{
 			URL:              rwConf.URL,
 			Timeout:          rwConf.RemoteTimeout,
+			Headers:          rwConf.Headers,
 			HTTPClientConfig: rwConf.HTTPClientConfig,
 		})
 		if err != nil {
 			return err
 		}
-		newQueues = append(newQueues, NewQueueManager(
+		newQueues[i] = NewQueueManager(
 			s.logger,
 			config.DefaultQueueConfig,
-			conf.GlobalConfig.ExternalLabels,
+			conf.GlobalConfig.ExternalLabels.Merge(rwConf.ExternalLabels),
 			rwConf.WriteRelabelConfigs,
 			c,
-		))
+		)
 	}
 
-	for _, q := range s.queues {
-		q.Stop()
+	for i := range s.queues {
+		if !reused[i] {
+			s.stopWriter(i)
+		}
 	}
 
+	for i, q := range newQueues {
+		if reused[i] {
+			continue
+		}
+		if mConf := conf.RemoteWriteConfigs[i].MetadataConfig; mConf.Send && s.metadataSource != nil {
+			mw := NewMetadataWatcher(s.logger, s.metadataSource, q, time.Duration(mConf.SendInterval))
+			mw.Start()
+			newMetadataWatchers[i] = mw
+		}
+		if s.dir == "" {
+			q.Start()
+			continue
+		}
+		w := NewWALWatcher(
+			s.logger,
+			filepath.Join(s.dir, "wal"),
+			walCheckpointFile(filepath.Join(s.dir, "remote_write"), i, conf.RemoteWriteConfigs[i].URL.String()),
+			q,
+		)
+		w.Start()
+		newWatchers[i] = w
+	}
 	s.queues = newQueues
-	for _, q := range s.queues {
-		q.Start()
+	s.metadataWatchers = newMetadataWatchers
+	s.writeConfigs = conf.RemoteWriteConfigs
+	if s.dir != "" {
+		s.watchers = newWatchers
 	}
 
-	// Update read clients
+	// Update read clients. Clients whose configuration did not change keep
+	// their pooled connections.
 
-	clients := []*Client{}
+	clients := []*readEndpoint{}
 	for i, rrConf := range conf.RemoteReadConfigs {
-		c, err := NewClient(i, &ClientConfig{
+		if i < len(s.readConfigs) && reflect.DeepEqual(s.readConfigs[i], rrConf) {
+			clients = append(clients, s.clients[i])
+			continue
+		}
+		c, err := newReadEndpoint(i, &ClientConfig{
 			URL:              rrConf.URL,
 			Timeout:          rrConf.RemoteTimeout,
 			HTTPClientConfig: rrConf.HTTPClientConfig,
 			ReadRecent:       rrConf.ReadRecent,
+			RequiredMatchers: rrConf.RequiredMatchers,
+			Headers:          rrConf.Headers,
 		})
 		if err != nil {
 			return err
@@ -101,11 +171,23 @@ func (s *Storage) ApplyConfig(conf *config.Config) error {
 	}
 
 	s.clients = clients
+	s.readConfigs = conf.RemoteReadConfigs
 	s.externalLabels = conf.GlobalConfig.ExternalLabels
 
 	return nil
 }
 
+// Notify the remote write endpoints that samples were committed to the
+// write ahead log of the local TSDB. It implements storage.CommitHook.
+func (s *Storage) Notify() {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	for _, w := range s.watchers {
+		w.Notify()
+	}
+}
+
 // StartTime implements the Storage interface.
 func (s *Storage) StartTime() (int64, error) {
 	return int64(model.Latest), nil
@@ -116,9 +198,26 @@ func (s *Storage) Close() error {
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
 
-	for _, q := range s.queues {
-		q.Stop()
-	}
+	s.stopWriters()
 
 	return nil
 }
+
+func (s *Storage) stopWriters() {
+	for i := range s.queues {
+		s.stopWriter(i)
+	}
+}
+
+// stopWriter stops the i-th write queue.
+func (s *Storage) stopWriter(i int) {
+	if mw := s.metadataWatchers[i]; mw != nil {
+		mw.Stop()
+	}
+	// Watchers stop their queues themselves.
+	if s.dir != "" {
+		s.watchers[i].Stop()
+		return
+	}
+	s.queues[i].Stop()
+}
@@ -0,0 +1,155 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/config"
+)
+
+func TestApplyConfigKeepsUnchangedQueues(t *testing.T) {
+	rwConf := func(u string) *config.RemoteWriteConfig {
+		parsed, err := url.Parse(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := config.DefaultRemoteWriteConfig
+		c.URL = &config.URL{URL: parsed}
+		return &c
+	}
+	s := NewStorage(nil, nil, "")
+	defer s.Close()
+
+	conf := &config.Config{
+		RemoteWriteConfigs: []*config.RemoteWriteConfig{
+			rwConf("http://remote1/push"),
+			rwConf("http://remote2/push"),
+		},
+	}
+	if err := s.ApplyConfig(conf); err != nil {
+		t.Fatal(err)
+	}
+	queues := s.queues
+
+	// Only the queue of the changed configuration is replaced.
+	conf = &config.Config{
+		RemoteWriteConfigs: []*config.RemoteWriteConfig{
+			rwConf("http://remote1/push"),
+			rwConf("http://remote3/push"),
+		},
+	}
+	if err := s.ApplyConfig(conf); err != nil {
+		t.Fatal(err)
+	}
+	if s.queues[0] != queues[0] {
+		t.Fatalf("queue of unchanged configuration was replaced")
+	}
+	if s.queues[1] == queues[1] {
+		t.Fatalf("queue of changed configuration was not replaced")
+	}
+	queues = s.queues
+
+	// Queues are recreated if the external labels change.
+	conf.GlobalConfig.ExternalLabels = model.LabelSet{"a": "b"}
+	if err := s.ApplyConfig(conf); err != nil {
+		t.Fatal(err)
+	}
+	if s.queues[0] == queues[0] || s.queues[1] == queues[1] {
+		t.Fatalf("queues were not replaced after external labels changed")
+	}
+
+	conf.RemoteWriteConfigs = conf.RemoteWriteConfigs[:1]
+	if err := s.ApplyConfig(conf); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.queues) != 1 {
+		t.Fatalf("expected 1 queue, got %d", len(s.queues))
+	}
+}
+
+func TestApplyConfigMergesExternalLabels(t *testing.T) {
+	parsed, err := url.Parse("http://remote1/push")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rwConf := config.DefaultRemoteWriteConfig
+	rwConf.URL = &config.URL{URL: parsed}
+	rwConf.ExternalLabels = model.LabelSet{"replica": "b", "source": "backfill"}
+
+	s := NewStorage(nil, nil, "")
+	defer s.Close()
+
+	conf := &config.Config{
+		GlobalConfig:       config.GlobalConfig{ExternalLabels: model.LabelSet{"region": "eu", "replica": "a"}},
+		RemoteWriteConfigs: []*config.RemoteWriteConfig{&rwConf},
+	}
+	if err := s.ApplyConfig(conf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := model.LabelSet{"region": "eu", "replica": "b", "source": "backfill"}
+	if !reflect.DeepEqual(s.queues[0].externalLabels, expected) {
+		t.Fatalf("unexpected external labels: got %v, want %v", s.queues[0].externalLabels, expected)
+	}
+	// The global external labels are not modified.
+	if conf.GlobalConfig.ExternalLabels["replica"] != "a" {
+		t.Fatalf("global external labels were modified: %v", conf.GlobalConfig.ExternalLabels)
+	}
+}
+
+func TestApplyConfigKeepsUnchangedReadClients(t *testing.T) {
+	rrConf := func(u string) *config.RemoteReadConfig {
+		parsed, err := url.Parse(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := config.DefaultRemoteReadConfig
+		c.URL = &config.URL{URL: parsed}
+		return &c
+	}
+	s := NewStorage(nil, nil, "")
+	defer s.Close()
+
+	conf := &config.Config{
+		RemoteReadConfigs: []*config.RemoteReadConfig{
+			rrConf("http://remote1/read"),
+			rrConf("http://remote2/read"),
+		},
+	}
+	if err := s.ApplyConfig(conf); err != nil {
+		t.Fatal(err)
+	}
+	clients := s.clients
+
+	// Unchanged clients keep their pooled connections.
+	conf = &config.Config{
+		RemoteReadConfigs: []*config.RemoteReadConfig{
+			rrConf("http://remote1/read"),
+			rrConf("http://remote3/read"),
+		},
+	}
+	if err := s.ApplyConfig(conf); err != nil {
+		t.Fatal(err)
+	}
+	if s.clients[0] != clients[0] {
+		t.Fatalf("client of unchanged configuration was replaced")
+	}
+	if s.clients[1] == clients[1] {
+		t.Fatalf("client of changed configuration was not replaced")
+	}
+}
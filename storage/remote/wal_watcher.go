@@ -0,0 +1,639 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/tsdb"
+)
+
+const (
+	// How often the WAL is checked for new entries once all existing
+	// ones have been read, unless the watcher is notified of them.
+	walReadPeriod = 1 * time.Second
+	// How often the position up to which all samples have been handled
+	// is persisted.
+	walCheckpointPeriod = 10 * time.Second
+
+	walSegmentHeaderSize = 8
+	walEntryHeaderSize   = 6
+	walEntryMaxSize      = 256 * 1024 * 1024
+)
+
+var (
+	walCurrentSegment = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "wal_current_segment",
+			Help:      "The write ahead log segment samples are currently read from.",
+		},
+		[]string{queue},
+	)
+	walSkippedSegmentsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "wal_skipped_segments_total",
+			Help:      "Total number of write ahead log segments which were corrupted or removed before all of their samples were read.",
+		},
+		[]string{queue},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(walCurrentSegment)
+	prometheus.MustRegister(walSkippedSegmentsTotal)
+}
+
+var walCastagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+// walPosition is a position in the WAL between two entries.
+type walPosition struct {
+	Segment int   `json:"segment"`
+	Offset  int64 `json:"offset"`
+
+	// The sequence number of the last sample queued before this position.
+	seq uint64
+}
+
+func (p walPosition) before(o walPosition) bool {
+	return p.Segment < o.Segment || p.Segment == o.Segment && p.Offset < o.Offset
+}
+
+// WALWatcher reads the samples written to the write ahead log of the local
+// TSDB and sends them to the remote storage using a QueueManager. Instead of
+// dropping samples if the remote storage cannot keep up, it stops reading.
+// The position up to which all samples were sent is checkpointed to a file so
+// that reading continues from there after a restart.
+type WALWatcher struct {
+	logger         log.Logger
+	dir            string
+	checkpointFile string
+	queue          *QueueManager
+
+	// The position to start queuing samples from. If nil, samples are only
+	// queued once the end of the WAL has been reached for the first time.
+	start *walPosition
+	// The current position and segment.
+	pos     walPosition
+	segment *walSegment
+	series  map[uint64]model.Metric
+	// The first segment of the WAL when the series were pruned last.
+	first os.FileInfo
+	// Positions not yet checkpointed in the order they were read.
+	pending []walPosition
+
+	notify chan struct{}
+	quit   chan struct{}
+	done   chan struct{}
+}
+
+// NewWALWatcher creates a WALWatcher reading from the WAL in dir and keeping
+// its checkpoint in checkpointFile. The queue manager retries the samples
+// until they are sent, so that the checkpoint only advances past samples the
+// remote storage received or rejected permanently.
+func NewWALWatcher(logger log.Logger, dir, checkpointFile string, queue *QueueManager) *WALWatcher {
+	if logger == nil {
+		logger = log.NewNopLogger()
+	}
+	queue.retryUntilSent = true
+
+	start, err := readWALCheckpoint(checkpointFile)
+	if err != nil {
+		level.Warn(logger).Log("msg", "Unable to read WAL checkpoint, starting at the end of the WAL", "file", checkpointFile, "err", err)
+	}
+	return &WALWatcher{
+		logger:         logger,
+		dir:            dir,
+		checkpointFile: checkpointFile,
+		queue:          queue,
+		start:          start,
+		series:         map[uint64]model.Metric{},
+		notify:         make(chan struct{}, 1),
+		quit:           make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+}
+
+// walCheckpointFile returns the name of the checkpoint file for the remote
+// write endpoint with the given index and URL in the configuration. The index
+// tells apart endpoints with the same URL.
+func walCheckpointFile(dir string, index int, url string) string {
+	return filepath.Join(dir, fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%d:%s", index, url))))[:16])
+}
+
+// Start the queue manager and reading from the WAL. Does not block.
+func (w *WALWatcher) Start() {
+	w.queue.Start()
+	go w.run()
+}
+
+// Stop reading from the WAL, stop the queue manager and checkpoint the
+// position up to which all samples were sent.
+func (w *WALWatcher) Stop() {
+	close(w.quit)
+	<-w.done
+
+	w.queue.Stop()
+	w.checkpoint()
+
+	if w.segment != nil {
+		w.segment.Close()
+	}
+}
+
+// Notify the watcher that new entries were written to the WAL, which are
+// then read without waiting for the next check. Does not block.
+func (w *WALWatcher) Notify() {
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (w *WALWatcher) run() {
+	defer close(w.done)
+
+	readTicker := time.NewTicker(walReadPeriod)
+	defer readTicker.Stop()
+	checkpointTicker := time.NewTicker(walCheckpointPeriod)
+	defer checkpointTicker.Stop()
+
+	for {
+		if err := w.read(); err != nil {
+			level.Error(w.logger).Log("msg", "Error reading WAL", "err", err)
+		}
+		select {
+		case <-readTicker.C:
+		case <-w.notify:
+		case <-checkpointTicker.C:
+			w.checkpoint()
+			if err := w.pruneSeries(); err != nil {
+				level.Error(w.logger).Log("msg", "Error pruning WAL series", "err", err)
+			}
+		case <-w.quit:
+			return
+		}
+	}
+}
+
+// read processes all available WAL entries. It returns once the end of the
+// last segment has been reached or the watcher is stopped.
+func (w *WALWatcher) read() error {
+	for {
+		if w.segment == nil {
+			ok, err := w.openNextSegment()
+			if err != nil || !ok {
+				return err
+			}
+		}
+
+		typ, b, err := w.segment.next()
+		if err != nil {
+			// Entries are only written to the last segment. An error there
+			// may be an entry that has not been completely written yet.
+			last, lerr := w.isLastSegment()
+			if lerr != nil {
+				return lerr
+			}
+			if last {
+				if w.start == nil {
+					w.start = &walPosition{Segment: w.pos.Segment, Offset: w.pos.Offset}
+				}
+				return nil
+			}
+			// A later segment may have been created after the read failed.
+			// Try once more before moving on.
+			if typ, b, err = w.segment.next(); err != nil {
+				if err != io.EOF {
+					walSkippedSegmentsTotal.WithLabelValues(w.queue.queueName).Inc()
+					level.Warn(w.logger).Log("msg", "Skipping the remainder of corrupted WAL segment", "segment", w.pos.Segment, "offset", w.pos.Offset, "err", err)
+				}
+				w.segment.Close()
+				w.segment = nil
+				continue
+			}
+		}
+
+		if !w.process(typ, b) {
+			return nil
+		}
+		w.pos.Offset = w.segment.offset
+	}
+}
+
+// process handles an entry read at the current position. It returns false
+// if the watcher was stopped while queuing its samples.
+func (w *WALWatcher) process(typ tsdb.WALEntryType, b []byte) bool {
+	switch typ {
+	case tsdb.WALEntrySeries:
+		if err := w.decodeSeries(b); err != nil {
+			level.Warn(w.logger).Log("msg", "Unable to decode WAL series entry", "segment", w.pos.Segment, "offset", w.pos.Offset, "err", err)
+		}
+		return true
+	case tsdb.WALEntrySamples:
+	default:
+		return true
+	}
+
+	if w.start == nil || w.pos.before(*w.start) {
+		return true
+	}
+
+	samples, err := decodeWALSamples(b)
+	if err != nil {
+		level.Warn(w.logger).Log("msg", "Unable to decode WAL samples entry", "segment", w.pos.Segment, "offset", w.pos.Offset, "err", err)
+	}
+	for _, s := range samples {
+		m, ok := w.series[s.Ref]
+		if !ok {
+			level.Debug(w.logger).Log("msg", "Unknown series reference in WAL", "ref", s.Ref)
+			continue
+		}
+		sample := &model.Sample{
+			Metric:    m,
+			Timestamp: model.Time(s.T),
+			Value:     model.SampleValue(s.V),
+		}
+		if !w.queue.AppendWait(sample, w.quit) {
+			return false
+		}
+	}
+
+	// Remember the position after the entry, merging it with the previous
+	// one if no samples were queued in between.
+	p := walPosition{Segment: w.pos.Segment, Offset: w.segment.offset, seq: w.queue.LastSeq()}
+	if n := len(w.pending); n > 0 && w.pending[n-1].seq == p.seq {
+		w.pending[n-1] = p
+	} else {
+		w.pending = append(w.pending, p)
+	}
+	return true
+}
+
+// openNextSegment opens the segment following the current one. It returns
+// false if there is none yet.
+func (w *WALWatcher) openNextSegment() (bool, error) {
+	segments, err := walSegments(w.dir)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	// If the WAL is still empty, all of its samples are new.
+	if len(segments) == 0 && w.start == nil {
+		w.start = &walPosition{}
+	}
+
+	i := sort.SearchInts(segments, w.pos.Segment+1)
+	if i == len(segments) {
+		return false, nil
+	}
+	next := segments[i]
+
+	// Segments with samples that have been persisted in blocks may be
+	// removed by the TSDB at any time.
+	if w.pos.Segment > 0 && next != w.pos.Segment+1 {
+		walSkippedSegmentsTotal.WithLabelValues(w.queue.queueName).Add(float64(next - w.pos.Segment - 1))
+		level.Warn(w.logger).Log("msg", "WAL segments were removed before they were read", "from", w.pos.Segment+1, "to", next-1)
+	}
+	if w.start != nil && w.pos.Segment == 0 && w.start.Segment > 0 && w.start.Segment < next {
+		level.Warn(w.logger).Log("msg", "Checkpointed WAL segment was removed, samples may have been lost", "segment", w.start.Segment)
+	}
+
+	s, err := openWALSegment(w.dir, next)
+	if err != nil {
+		// A newly created segment may not have its header written yet.
+		if i == len(segments)-1 {
+			return false, nil
+		}
+		walSkippedSegmentsTotal.WithLabelValues(w.queue.queueName).Inc()
+		w.pos = walPosition{Segment: next}
+		return false, err
+	}
+	w.segment = s
+	w.pos = walPosition{Segment: next, Offset: s.offset}
+	walCurrentSegment.WithLabelValues(w.queue.queueName).Set(float64(next))
+
+	return true, nil
+}
+
+func (w *WALWatcher) isLastSegment() (bool, error) {
+	segments, err := walSegments(w.dir)
+	if err != nil {
+		return false, err
+	}
+	return len(segments) == 0 || segments[len(segments)-1] <= w.pos.Segment, nil
+}
+
+// pruneSeries drops the known series that are no longer in the WAL. The
+// TSDB truncates the WAL by rewriting its first segments into a single one
+// that only holds the series still in its head. Once it did, the series are
+// read again from the segments up to the current position.
+func (w *WALWatcher) pruneSeries() error {
+	segments, err := walSegments(w.dir)
+	if err != nil {
+		return err
+	}
+	// The last segment is written to and never truncated.
+	if len(segments) < 2 || segments[0] > w.pos.Segment {
+		return nil
+	}
+	first, err := os.Stat(filepath.Join(w.dir, fmt.Sprintf("%0.6d", segments[0])))
+	if err != nil {
+		return err
+	}
+	if w.first == nil || os.SameFile(first, w.first) {
+		w.first = first
+		return nil
+	}
+
+	series := w.series
+	w.series = map[uint64]model.Metric{}
+	for _, i := range segments {
+		if i > w.pos.Segment {
+			break
+		}
+		end := int64(math.MaxInt64)
+		if i == w.pos.Segment {
+			end = w.pos.Offset
+		}
+		if err := w.readSeries(i, end); err != nil {
+			w.series = series
+			return err
+		}
+	}
+	w.first = first
+	return nil
+}
+
+// readSeries reads the series of the segment with the given index up to the
+// offset end. The remainder of corrupted segments is skipped.
+func (w *WALWatcher) readSeries(index int, end int64) error {
+	s, err := openWALSegment(w.dir, index)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	for s.offset < end {
+		typ, b, err := s.next()
+		if err != nil {
+			return nil
+		}
+		if typ == tsdb.WALEntrySeries {
+			if err := w.decodeSeries(b); err != nil {
+				level.Warn(w.logger).Log("msg", "Unable to decode WAL series entry", "segment", index, "offset", s.offset, "err", err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkpoint persists the last position before which all queued samples
+// have been handled by the queue manager.
+func (w *WALWatcher) checkpoint() {
+	seq := w.queue.HandledSeq()
+
+	var (
+		p  walPosition
+		ok bool
+	)
+	for len(w.pending) > 0 && w.pending[0].seq <= seq {
+		p, ok = w.pending[0], true
+		w.pending = w.pending[1:]
+	}
+	if !ok {
+		return
+	}
+	if err := writeWALCheckpoint(w.checkpointFile, p); err != nil {
+		level.Error(w.logger).Log("msg", "Unable to write WAL checkpoint", "file", w.checkpointFile, "err", err)
+	}
+}
+
+func readWALCheckpoint(fn string) (*walPosition, error) {
+	b, err := ioutil.ReadFile(fn)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var p walPosition
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func writeWALCheckpoint(fn string, p walPosition) error {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(fn), 0777); err != nil {
+		return err
+	}
+	tmp := fn + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0666); err != nil {
+		return err
+	}
+	return os.Rename(tmp, fn)
+}
+
+// walSegments returns the sorted indices of the segments in a WAL directory.
+func walSegments(dir string) ([]int, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var segments []int
+	for _, fi := range files {
+		i, err := strconv.Atoi(fi.Name())
+		if err != nil {
+			continue
+		}
+		segments = append(segments, i)
+	}
+	sort.Ints(segments)
+	return segments, nil
+}
+
+// walSegment reads the entries of a segment file written by tsdb.SegmentWAL.
+type walSegment struct {
+	*os.File
+	offset int64 // The offset after the last entry read.
+	buf    []byte
+}
+
+func openWALSegment(dir string, index int) (*walSegment, error) {
+	f, err := os.Open(filepath.Join(dir, fmt.Sprintf("%0.6d", index)))
+	if err != nil {
+		return nil, err
+	}
+	var hdr [walSegmentHeaderSize]byte
+	if _, err := f.ReadAt(hdr[:], 0); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("unable to read header of WAL segment %d: %s", index, err)
+	}
+	if m := binary.BigEndian.Uint32(hdr[:4]); m != tsdb.WALMagic {
+		f.Close()
+		return nil, fmt.Errorf("invalid magic header %x in WAL segment %d", m, index)
+	}
+	if hdr[4] != tsdb.WALFormatDefault {
+		f.Close()
+		return nil, fmt.Errorf("unknown format %d of WAL segment %d", hdr[4], index)
+	}
+	return &walSegment{File: f, offset: walSegmentHeaderSize}, nil
+}
+
+// next returns the next entry of the segment. It returns io.EOF if there
+// are no more entries.
+func (s *walSegment) next() (tsdb.WALEntryType, []byte, error) {
+	var hdr [walEntryHeaderSize]byte
+	n, err := s.ReadAt(hdr[:], s.offset)
+	if n == 0 && err == io.EOF {
+		return 0, nil, io.EOF
+	}
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to read entry header: %s", err)
+	}
+
+	typ := tsdb.WALEntryType(hdr[0])
+	length := int(binary.BigEndian.Uint32(hdr[2:]))
+
+	// Segments are preallocated with zeros.
+	if typ == 0 {
+		return 0, nil, io.EOF
+	}
+	if typ != tsdb.WALEntrySeries && typ != tsdb.WALEntrySamples && typ != tsdb.WALEntryDeletes {
+		return 0, nil, fmt.Errorf("invalid entry type %d", typ)
+	}
+	if length > walEntryMaxSize {
+		return 0, nil, fmt.Errorf("invalid entry length %d", length)
+	}
+
+	if cap(s.buf) < length+4 {
+		s.buf = make([]byte, length+4)
+	}
+	buf := s.buf[:length+4]
+	if _, err := s.ReadAt(buf, s.offset+walEntryHeaderSize); err != nil {
+		return 0, nil, fmt.Errorf("unable to read entry: %s", err)
+	}
+
+	crc := crc32.Update(crc32.Checksum(hdr[:], walCastagnoliTable), walCastagnoliTable, buf[:length])
+	if exp := binary.BigEndian.Uint32(buf[length:]); crc != exp {
+		return 0, nil, fmt.Errorf("unexpected CRC32 checksum %x, want %x", crc, exp)
+	}
+
+	s.offset += int64(walEntryHeaderSize + length + 4)
+	return typ, buf[:length], nil
+}
+
+// decodeSeries adds the series of a WAL series entry to the known series.
+func (w *WALWatcher) decodeSeries(b []byte) error {
+	for len(b) > 0 {
+		if len(b) < 8 {
+			return fmt.Errorf("unexpected %d bytes left in entry", len(b))
+		}
+		ref := binary.BigEndian.Uint64(b)
+		b = b[8:]
+
+		n, k := binary.Uvarint(b)
+		if k <= 0 {
+			return fmt.Errorf("invalid number of labels")
+		}
+		b = b[k:]
+
+		m := make(model.Metric, n)
+		for i := uint64(0); i < n; i++ {
+			var name, value string
+			var err error
+			if name, b, err = decodeWALString(b); err != nil {
+				return err
+			}
+			if value, b, err = decodeWALString(b); err != nil {
+				return err
+			}
+			m[model.LabelName(name)] = model.LabelValue(value)
+		}
+		w.series[ref] = m
+	}
+	return nil
+}
+
+func decodeWALString(b []byte) (string, []byte, error) {
+	n, k := binary.Uvarint(b)
+	if k <= 0 || uint64(len(b)-k) < n {
+		return "", nil, fmt.Errorf("invalid string")
+	}
+	return string(b[k : k+int(n)]), b[k+int(n):], nil
+}
+
+// decodeWALSamples decodes the samples of a WAL samples entry. The samples
+// decoded before an error occurred are returned along with it.
+func decodeWALSamples(b []byte) ([]tsdb.RefSample, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	if len(b) < 16 {
+		return nil, fmt.Errorf("invalid entry length %d", len(b))
+	}
+	var (
+		baseRef  = binary.BigEndian.Uint64(b)
+		baseTime = int64(binary.BigEndian.Uint64(b[8:]))
+		samples  []tsdb.RefSample
+	)
+	b = b[16:]
+
+	for len(b) > 0 {
+		dref, k := binary.Varint(b)
+		if k <= 0 {
+			return samples, fmt.Errorf("decode error after %d samples", len(samples))
+		}
+		b = b[k:]
+		dtime, k := binary.Varint(b)
+		if k <= 0 || len(b)-k < 8 {
+			return samples, fmt.Errorf("decode error after %d samples", len(samples))
+		}
+		b = b[k:]
+
+		samples = append(samples, tsdb.RefSample{
+			Ref: uint64(int64(baseRef) + dref),
+			T:   baseTime + dtime,
+			V:   math.Float64frombits(binary.BigEndian.Uint64(b)),
+		})
+		b = b[8:]
+	}
+	return samples, nil
+}
@@ -0,0 +1,235 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/tsdb"
+	"github.com/prometheus/tsdb/labels"
+)
+
+func logWALSamples(t *testing.T, wal *tsdb.SegmentWAL, series int, from, to int64) model.Samples {
+	var (
+		refSamples []tsdb.RefSample
+		samples    model.Samples
+	)
+	for ts := from; ts < to; ts++ {
+		for i := 0; i < series; i++ {
+			refSamples = append(refSamples, tsdb.RefSample{Ref: uint64(i), T: ts, V: float64(ts)})
+			samples = append(samples, &model.Sample{
+				Metric:    model.Metric{model.MetricNameLabel: model.LabelValue(fmt.Sprintf("test_metric_%d", i))},
+				Timestamp: model.Time(ts),
+				Value:     model.SampleValue(ts),
+			})
+		}
+	}
+	if err := wal.LogSamples(refSamples); err != nil {
+		t.Fatal(err)
+	}
+	if err := wal.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	return samples
+}
+
+func TestWALWatcher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wal_watcher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var (
+		walDir         = filepath.Join(dir, "wal")
+		checkpointFile = walCheckpointFile(filepath.Join(dir, "remote_write"), 0, "http://remote/write")
+		numSeries      = 10
+	)
+
+	wal, err := tsdb.OpenSegmentWAL(walDir, nil, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wal.Close()
+
+	c := NewTestStorageClient()
+	w := NewWALWatcher(nil, walDir, checkpointFile, NewQueueManager(nil, config.DefaultQueueConfig, nil, nil, c))
+
+	// As the WAL is still empty, all samples logged to it are new.
+	if err := w.read(); err != nil {
+		t.Fatal(err)
+	}
+
+	series := make([]tsdb.RefSeries, 0, numSeries)
+	for i := 0; i < numSeries; i++ {
+		series = append(series, tsdb.RefSeries{
+			Ref:    uint64(i),
+			Labels: labels.FromStrings("__name__", fmt.Sprintf("test_metric_%d", i)),
+		})
+	}
+	if err := wal.LogSeries(series); err != nil {
+		t.Fatal(err)
+	}
+
+	samples := logWALSamples(t, wal, numSeries, 0, 10)
+	c.expectSamples(samples)
+
+	w.Start()
+	c.waitForExpectedSamples(t)
+	w.Stop()
+
+	p, err := readWALCheckpoint(checkpointFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p == nil {
+		t.Fatal("no checkpoint written")
+	}
+
+	// A restarted watcher must only send the samples logged after the
+	// checkpoint.
+	c = NewTestStorageClient()
+	samples = logWALSamples(t, wal, numSeries, 10, 20)
+	c.expectSamples(samples)
+
+	w = NewWALWatcher(nil, walDir, checkpointFile, NewQueueManager(nil, config.DefaultQueueConfig, nil, nil, c))
+	w.Start()
+	c.waitForExpectedSamples(t)
+	w.Stop()
+
+	next, err := readWALCheckpoint(checkpointFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.before(*next) {
+		t.Fatalf("checkpoint did not advance: %v, %v", p, next)
+	}
+}
+
+func TestWALCheckpointFile(t *testing.T) {
+	// Endpoints with the same URL keep separate checkpoints.
+	if walCheckpointFile("dir", 0, "http://remote/write") == walCheckpointFile("dir", 1, "http://remote/write") {
+		t.Fatal("expected different checkpoint files for endpoints with the same URL")
+	}
+	if walCheckpointFile("dir", 0, "http://remote/write") != walCheckpointFile("dir", 0, "http://remote/write") {
+		t.Fatal("expected the same checkpoint file for the same endpoint")
+	}
+}
+
+func TestWALWatcherSkipsOldSamples(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wal_watcher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	walDir := filepath.Join(dir, "wal")
+	wal, err := tsdb.OpenSegmentWAL(walDir, nil, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wal.Close()
+
+	if err := wal.LogSeries([]tsdb.RefSeries{{Ref: 0, Labels: labels.FromStrings("__name__", "test_metric_0")}}); err != nil {
+		t.Fatal(err)
+	}
+	logWALSamples(t, wal, 1, 0, 10)
+
+	// Without a checkpoint, samples already in the WAL are not sent.
+	c := NewTestStorageClient()
+	w := NewWALWatcher(nil, walDir, filepath.Join(dir, "checkpoint"), NewQueueManager(nil, config.DefaultQueueConfig, nil, nil, c))
+	if err := w.read(); err != nil {
+		t.Fatal(err)
+	}
+	if w.start == nil || *w.start != w.pos {
+		t.Fatalf("expected to start at the end of the WAL %v, got %v", w.pos, w.start)
+	}
+
+	samples := logWALSamples(t, wal, 1, 10, 20)
+	c.expectSamples(samples)
+
+	w.Start()
+	c.waitForExpectedSamples(t)
+	w.Stop()
+}
+
+func TestWALWatcherPrunesSeries(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wal_watcher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	walDir := filepath.Join(dir, "wal")
+	wal, err := tsdb.OpenSegmentWAL(walDir, nil, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var series []tsdb.RefSeries
+	for i := 0; i < 3; i++ {
+		series = append(series, tsdb.RefSeries{Ref: uint64(i), Labels: labels.FromStrings("__name__", fmt.Sprintf("test_metric_%d", i))})
+	}
+	if err := wal.LogSeries(series); err != nil {
+		t.Fatal(err)
+	}
+	logWALSamples(t, wal, 3, 0, 10)
+	if err := wal.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopening the WAL cuts a new segment. A reopened WAL has to be read
+	// before it is written to, or the previous segment is truncated.
+	wal, err = tsdb.OpenSegmentWAL(walDir, nil, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wal.Close()
+	if err := wal.Reader().Read(func([]tsdb.RefSeries) {}, func([]tsdb.RefSample) {}, func([]tsdb.Stone) {}); err != nil {
+		t.Fatal(err)
+	}
+	if err := wal.LogSeries([]tsdb.RefSeries{{Ref: 3, Labels: labels.FromStrings("__name__", "test_metric_3")}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := wal.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewWALWatcher(nil, walDir, filepath.Join(dir, "checkpoint"), NewQueueManager(nil, config.DefaultQueueConfig, nil, nil, NewTestStorageClient()))
+	if err := w.read(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.pruneSeries(); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.series) != 4 {
+		t.Fatalf("expected 4 series, got %v", w.series)
+	}
+
+	// Series dropped by the truncation of the WAL are forgotten.
+	if err := wal.Truncate(100, func(ref uint64) bool { return ref == 1 }); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.pruneSeries(); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.series) != 2 || w.series[1] == nil || w.series[3] == nil {
+		t.Fatalf("expected series 1 and 3, got %v", w.series)
+	}
+}
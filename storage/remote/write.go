@@ -15,6 +15,7 @@ package remote
 
 import (
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/exemplar"
 	"github.com/prometheus/prometheus/pkg/labels"
 	"github.com/prometheus/prometheus/storage"
 )
@@ -26,6 +27,11 @@ func (s *Storage) Appender() (storage.Appender, error) {
 
 // Add implements storage.Appender.
 func (s *Storage) Add(l labels.Labels, t int64, v float64) (uint64, error) {
+	// Samples are read from the write ahead log instead.
+	if s.dir != "" {
+		return 0, nil
+	}
+
 	s.mtx.RLock()
 	defer s.mtx.RUnlock()
 	for _, q := range s.queues {
@@ -44,6 +50,17 @@ func (s *Storage) AddFast(l labels.Labels, _ uint64, t int64, v float64) error {
 	return err
 }
 
+// AddExemplar implements storage.Appender. Exemplars are not sent to
+// remote storages.
+func (*Storage) AddExemplar(labels.Labels, exemplar.Exemplar) error {
+	return nil
+}
+
+// AddBatch implements storage.Appender.
+func (s *Storage) AddBatch(samples []storage.Sample) error {
+	return storage.AppendBatch(s, samples)
+}
+
 // Commit implements storage.Appender.
 func (*Storage) Commit() error {
 	return nil
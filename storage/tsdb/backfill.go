@@ -0,0 +1,163 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"sort"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/tsdb"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/textparse"
+)
+
+// backfillCommitSize is the number of samples after which the appender of a
+// block is committed to bound the memory used by pending samples.
+const backfillCommitSize = 5000
+
+// backfillBlock collects the samples of one block range in a head.
+type backfillBlock struct {
+	head    *tsdb.Head
+	app     tsdb.Appender
+	pending int
+}
+
+// add appends a sample to the head of the block and commits the appender
+// every backfillCommitSize samples.
+func (b *backfillBlock) add(lset labels.Labels, t int64, v float64) error {
+	if _, err := b.app.Add(toTSDBLabels(lset), t, v); err != nil {
+		return errors.Wrapf(err, "add sample of series %s", lset)
+	}
+	b.pending++
+	if b.pending < backfillCommitSize {
+		return nil
+	}
+	if err := b.app.Commit(); err != nil {
+		return errors.Wrap(err, "commit samples")
+	}
+	b.app = b.head.Appender()
+	b.pending = 0
+	return nil
+}
+
+// CreateBlocksFromOpenMetrics writes the samples of the OpenMetrics input
+// into blocks in dir. Every block covers one range of blockDuration aligned
+// to multiples of it, like the blocks persisted from the head of a database.
+// All samples must have a timestamp and samples of the same series must be
+// ordered by time.
+//
+// The input is parsed once and every sample is appended to the head of the
+// block range it falls into.
+//
+// The blocks are written as if persisted from the head, so they must not
+// overlap with blocks that already exist in dir.
+func CreateBlocksFromOpenMetrics(input []byte, dir string, blockDuration time.Duration, l log.Logger) error {
+	if l == nil {
+		l = log.NewNopLogger()
+	}
+	blockRange := int64(blockDuration / time.Millisecond)
+	if blockRange <= 0 {
+		return errors.Errorf("invalid block duration %s", blockDuration)
+	}
+
+	var (
+		blocks = map[int64]*backfillBlock{}
+		// The head drops out of order samples on commit, so the
+		// latest timestamp of each series is tracked to report them.
+		latest = map[string]int64{}
+	)
+	rollback := func() {
+		for _, b := range blocks {
+			b.app.Rollback()
+		}
+	}
+
+	p := textparse.NewOpenMetricsParser(input)
+	for p.Next() {
+		_, ts, v := p.At()
+		// The head keeps the labels of new series, so they must not be reused.
+		var lset labels.Labels
+		series := p.Metric(&lset)
+
+		if ts == nil {
+			rollback()
+			return errors.Errorf("sample of series %s has no timestamp", lset)
+		}
+		if t, ok := latest[series]; ok && *ts <= t {
+			rollback()
+			return errors.Errorf("sample of series %s at %d is out of order", lset, *ts)
+		}
+		latest[series] = *ts
+
+		start := blockStart(*ts, blockRange)
+		b, ok := blocks[start]
+		if !ok {
+			// The head only accepts samples within half its chunk range of the
+			// highest timestamp, so twice the block range fits all samples of it.
+			head, err := tsdb.NewHead(nil, nil, nil, 2*blockRange)
+			if err != nil {
+				rollback()
+				return errors.Wrap(err, "create head")
+			}
+			b = &backfillBlock{head: head, app: head.Appender()}
+			blocks[start] = b
+		}
+		if err := b.add(lset, *ts, v); err != nil {
+			rollback()
+			return err
+		}
+	}
+	if err := p.Err(); err != nil {
+		rollback()
+		return errors.Wrap(err, "parse input")
+	}
+	if len(blocks) == 0 {
+		level.Info(l).Log("msg", "No samples found in input")
+		return nil
+	}
+
+	starts := make([]int64, 0, len(blocks))
+	for start, b := range blocks {
+		if err := b.app.Commit(); err != nil {
+			return errors.Wrap(err, "commit samples")
+		}
+		starts = append(starts, start)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+
+	compactor, err := tsdb.NewLeveledCompactor(nil, l, []int64{blockRange}, nil)
+	if err != nil {
+		return errors.Wrap(err, "create compactor")
+	}
+	for _, start := range starts {
+		if err := compactor.Write(dir, blocks[start].head, start, start+blockRange); err != nil {
+			return errors.Wrapf(err, "write block for range [%d, %d)", start, start+blockRange)
+		}
+		// Release the samples of the written block early.
+		delete(blocks, start)
+	}
+	return nil
+}
+
+// blockStart returns the start of the block range containing t.
+func blockStart(t, blockRange int64) int64 {
+	if t >= 0 {
+		return t - t%blockRange
+	}
+	return t - (blockRange+t%blockRange)%blockRange
+}
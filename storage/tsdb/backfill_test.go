@@ -0,0 +1,126 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prometheus/tsdb"
+	"github.com/prometheus/tsdb/labels"
+)
+
+func TestCreateBlocksFromOpenMetrics(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backfill")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	input := []byte(`# TYPE http_requests_total counter
+http_requests_total{code="200"} 1 1
+http_requests_total{code="200"} 2 2
+http_requests_total{code="200"} 5 11
+http_requests_total{code="500"} 1 2
+http_requests_total{code="500"} 3 25.5
+# EOF
+`)
+	if err := CreateBlocksFromOpenMetrics(input, dir, 10*time.Second, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := tsdb.Open(dir, nil, nil, &tsdb.Options{
+		BlockRanges: []int64{10000},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var ranges [][2]int64
+	for _, b := range db.Blocks() {
+		ranges = append(ranges, [2]int64{b.Meta().MinTime, b.Meta().MaxTime})
+	}
+	expRanges := [][2]int64{{0, 10000}, {10000, 20000}, {20000, 30000}}
+	if !reflect.DeepEqual(expRanges, ranges) {
+		t.Fatalf("expected block ranges %v, got %v", expRanges, ranges)
+	}
+
+	q, err := db.Querier(math.MinInt64, math.MaxInt64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+
+	ss := q.Select(labels.NewEqualMatcher("__name__", "http_requests_total"))
+	type sample struct {
+		t int64
+		v float64
+	}
+	got := map[string][]sample{}
+	for ss.Next() {
+		s := ss.At()
+		it := s.Iterator()
+		for it.Next() {
+			ts, v := it.At()
+			got[s.Labels().Get("code")] = append(got[s.Labels().Get("code")], sample{ts, v})
+		}
+		if err := it.Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ss.Err(); err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string][]sample{
+		"200": {{1000, 1}, {2000, 2}, {11000, 5}},
+		"500": {{2000, 1}, {25500, 3}},
+	}
+	if !reflect.DeepEqual(exp, got) {
+		t.Fatalf("expected samples %v, got %v", exp, got)
+	}
+}
+
+func TestCreateBlocksFromOpenMetricsErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backfill")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, input := range []string{
+		"metric 1\n# EOF\n",
+		"metric 1 1\n",
+		"metric 1 2\nmetric 2 1\n# EOF\n",
+	} {
+		if err := CreateBlocksFromOpenMetrics([]byte(input), dir, time.Hour, nil); err == nil {
+			t.Fatalf("expected error for input %q", input)
+		}
+	}
+
+	if err := CreateBlocksFromOpenMetrics([]byte("# EOF\n"), dir, time.Hour, nil); err != nil {
+		t.Fatal(err)
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Fatalf("expected no blocks to be written, got %d", len(files))
+	}
+}
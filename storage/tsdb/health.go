@@ -0,0 +1,176 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/prometheus/pkg/timestamp"
+	"github.com/prometheus/tsdb"
+)
+
+// maxHealthEvents is the number of most recent health events retained.
+const maxHealthEvents = 10
+
+// Health holds health information about a TSDB database.
+type Health struct {
+	// The duration of opening the database, which is dominated by replaying
+	// the WAL.
+	WALReplayDuration time.Duration
+	// The start time and duration of the compaction that wrote the most
+	// recent block, zero if there is none.
+	LastCompaction         time.Time
+	LastCompactionDuration time.Duration
+	HeadSeries             uint64
+	// The most recent warnings and errors, like corruptions, repairs and
+	// failures, oldest first.
+	Events []HealthEvent
+}
+
+// HealthEvent is a warning or error logged by the database.
+type HealthEvent struct {
+	Time        time.Time
+	Description string
+	// The details logged by the database, like the error.
+	Details string
+}
+
+// healthEvents retains the most recent health events.
+type healthEvents struct {
+	mtx    sync.Mutex
+	events []HealthEvent
+}
+
+func (e *healthEvents) observe(t time.Time, keyvals []interface{}) {
+	var (
+		lvl     interface{}
+		msg     string
+		details []string
+	)
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if keyvals[i] == level.Key() {
+			lvl = keyvals[i+1]
+			continue
+		}
+		switch k := fmt.Sprint(keyvals[i]); k {
+		case "msg":
+			msg = fmt.Sprint(keyvals[i+1])
+		case "ts", "caller", "component":
+		default:
+			details = append(details, fmt.Sprintf("%s=%v", k, keyvals[i+1]))
+		}
+	}
+	if lvl != level.ErrorValue() && lvl != level.WarnValue() {
+		return
+	}
+
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	e.events = append(e.events, HealthEvent{
+		Time:        t,
+		Description: msg,
+		Details:     strings.Join(details, " "),
+	})
+	if len(e.events) > maxHealthEvents {
+		e.events = e.events[len(e.events)-maxHealthEvents:]
+	}
+}
+
+func (e *healthEvents) get() []HealthEvent {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	return append([]HealthEvent(nil), e.events...)
+}
+
+// HealthLogger returns a logger passing log lines on to l, which may be nil,
+// that records the warnings and errors of the database logging to it as
+// health events of the storage.
+func (s *ReadyStorage) HealthLogger(l log.Logger) log.Logger {
+	if l == nil {
+		l = log.NewNopLogger()
+	}
+	return log.LoggerFunc(func(keyvals ...interface{}) error {
+		s.events.observe(time.Now(), keyvals)
+		return l.Log(keyvals...)
+	})
+}
+
+// Health returns the current health of the storage.
+func (s *ReadyStorage) Health() Health {
+	s.mtx.RLock()
+	a, replayDuration := s.a, s.replayDuration
+	s.mtx.RUnlock()
+
+	h := Health{
+		WALReplayDuration: replayDuration.Round(time.Millisecond),
+		Events:            s.events.get(),
+	}
+	if a == nil {
+		return h
+	}
+	h.HeadSeries = headSeries(a.db.Head())
+	h.LastCompaction, h.LastCompactionDuration = lastCompaction(a.db.Blocks())
+	h.LastCompactionDuration = h.LastCompactionDuration.Round(time.Millisecond)
+	return h
+}
+
+// headSeries returns the number of series in the head block.
+func headSeries(h *tsdb.Head) uint64 {
+	ir, err := h.Index()
+	if err != nil {
+		return 0
+	}
+	defer ir.Close()
+
+	p, err := ir.Postings("", "")
+	if err != nil {
+		return 0
+	}
+	var n uint64
+	for p.Next() {
+		n++
+	}
+	return n
+}
+
+// lastCompaction returns the start time and duration of the compaction that
+// wrote the most recent of the blocks. Blocks are identified by a ULID
+// created when their compaction starts, and their index is written at its
+// end and not modified afterwards, unlike their meta file.
+func lastCompaction(blocks []*tsdb.Block) (time.Time, time.Duration) {
+	var last *tsdb.Block
+	for _, b := range blocks {
+		if last == nil || b.Meta().ULID.Time() > last.Meta().ULID.Time() {
+			last = b
+		}
+	}
+	if last == nil {
+		return time.Time{}, 0
+	}
+	start := timestamp.Time(int64(last.Meta().ULID.Time()))
+	fi, err := os.Stat(filepath.Join(last.Dir(), "index"))
+	if err != nil || fi.ModTime().Before(start) {
+		return start, 0
+	}
+	return start, fi.ModTime().Sub(start)
+}
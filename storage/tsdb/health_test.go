@@ -0,0 +1,108 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/tsdb"
+	"github.com/prometheus/tsdb/labels"
+)
+
+func TestHealth(t *testing.T) {
+	dir, err := ioutil.TempDir("", "health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := &ReadyStorage{}
+	if err := s.StartWALReplay(dir); err != nil {
+		t.Logf("WAL replay progress unknown: %s", err)
+	}
+	db, err := tsdb.Open(dir, s.HealthLogger(nil), nil, &tsdb.Options{
+		WALFlushInterval: 10 * time.Second,
+		BlockRanges:      []int64{1000},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Set(db, 0, nil, nil)
+	defer s.Close()
+
+	h := s.Health()
+	if !h.LastCompaction.IsZero() || h.HeadSeries != 0 {
+		t.Fatalf("unexpected health of empty database: %+v", h)
+	}
+
+	// Samples spanning several block ranges trigger compactions.
+	app := db.Appender()
+	for ts := int64(0); ts < 3000; ts += 100 {
+		if _, err := app.Add(labels.FromStrings("a", "b"), ts, float64(ts)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := app.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for len(db.Blocks()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 2 blocks, got %d", len(db.Blocks()))
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	h = s.Health()
+	if h.LastCompaction.IsZero() || time.Since(h.LastCompaction) > time.Minute {
+		t.Fatalf("expected last compaction to be recorded, got %s", h.LastCompaction)
+	}
+	if h.HeadSeries != 1 {
+		t.Fatalf("expected 1 head series, got %d", h.HeadSeries)
+	}
+	if len(h.Events) != 0 {
+		t.Fatalf("expected no events, got %v", h.Events)
+	}
+}
+
+func TestHealthEvents(t *testing.T) {
+	s := &ReadyStorage{}
+	l := s.HealthLogger(nil)
+
+	level.Info(l).Log("msg", "head GC completed", "duration", time.Second)
+	for i := 0; i < maxHealthEvents; i++ {
+		level.Error(l).Log("msg", "compaction failed", "err", "disk full")
+	}
+	level.Warn(l).Log("msg", "invalid segment file detected, truncating WAL", "err", "unexpected checksum", "file", "000002")
+
+	h := s.Health()
+	if len(h.Events) != maxHealthEvents {
+		t.Fatalf("expected %d events, got %d", maxHealthEvents, len(h.Events))
+	}
+	last := h.Events[len(h.Events)-1]
+	if last.Description != "invalid segment file detected, truncating WAL" {
+		t.Fatalf("unexpected description %q", last.Description)
+	}
+	if last.Details != "err=unexpected checksum file=000002" {
+		t.Fatalf("unexpected details %q", last.Details)
+	}
+	if h.Events[0].Description != "compaction failed" || h.Events[0].Details != "err=disk full" {
+		t.Fatalf("unexpected event %+v", h.Events[0])
+	}
+}
@@ -0,0 +1,629 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"context"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/oklog/ulid"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/tsdb"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/value"
+	"github.com/prometheus/prometheus/storage"
+)
+
+// rewriteMtx keeps blocks from being rewritten or deleted concurrently, which
+// would leave overlapping blocks behind or delete blocks being rewritten.
+var rewriteMtx sync.Mutex
+
+var (
+	compactionsMtx sync.Mutex
+	// The number of callers that disabled the compactions of each database.
+	// Compactions are enabled again once all of them are done.
+	compactionsDisabled = map[*tsdb.DB]int{}
+)
+
+// disableCompactions disables the compactions of db until enableCompactions
+// was called as often as disableCompactions.
+func disableCompactions(db *tsdb.DB) {
+	compactionsMtx.Lock()
+	defer compactionsMtx.Unlock()
+
+	if compactionsDisabled[db] == 0 {
+		db.DisableCompactions()
+	}
+	compactionsDisabled[db]++
+}
+
+// enableCompactions enables the compactions of db again if no other caller
+// keeps them disabled.
+func enableCompactions(db *tsdb.DB) {
+	compactionsMtx.Lock()
+	defer compactionsMtx.Unlock()
+
+	if compactionsDisabled[db]--; compactionsDisabled[db] > 0 {
+		return
+	}
+	delete(compactionsDisabled, db)
+	db.EnableCompactions()
+}
+
+// The directory of the write ahead log of the out of order samples within
+// the database directory, and the file within it recording up to which time
+// the samples were merged into the blocks.
+const (
+	outOfOrderWALDir       = "wal_out_of_order"
+	outOfOrderMergedUpFile = "merged_up_to"
+)
+
+// OutOfOrderHead accepts samples that are out of order for their series as
+// long as they are within a time window of the newest sample of the database.
+// The samples of each series are kept sorted by time in memory, so that they
+// may arrive in any order, and are merged into the results of queries.
+// Committed samples are written to a separate write ahead log and restored
+// from it when the database is opened again.
+//
+// Once the database persisted the range of out of order samples into a
+// block, the block is rewritten with the out of order samples merged in.
+// Like the blocks deleted by the retention, the rewritten block replaces the
+// original one with the next reload of the database.
+type OutOfOrderHead struct {
+	db     *tsdb.DB
+	dir    string
+	wal    *tsdb.SegmentWAL
+	window int64
+	logger log.Logger
+
+	mtx     sync.RWMutex
+	series  map[uint64][]*outOfOrderSeries // By label hash.
+	refs    map[uint64]*outOfOrderSeries   // By reference in the WAL.
+	lastRef uint64
+
+	// The time up to which the blocks of the database were checked for
+	// out of order samples to merge.
+	mergedUpTo int64
+	// The blocks that were replaced by merged blocks but are still loaded.
+	replaced map[ulid.ULID]struct{}
+
+	appended prometheus.Counter
+	merges   prometheus.Counter
+}
+
+// outOfOrderSeries holds the out of order samples of a series.
+type outOfOrderSeries struct {
+	ref     uint64
+	lset    labels.Labels
+	samples []outOfOrderSample // Sorted by timestamp.
+}
+
+type outOfOrderSample struct {
+	t int64
+	v float64
+}
+
+// index returns the index of the first sample at or after t.
+func (s *outOfOrderSeries) index(t int64) int {
+	return sort.Search(len(s.samples), func(i int) bool { return s.samples[i].t >= t })
+}
+
+// insert adds a sample at its position in time. A sample at the same
+// timestamp is replaced.
+func (s *outOfOrderSeries) insert(t int64, v float64) {
+	i := s.index(t)
+	if i < len(s.samples) && s.samples[i].t == t {
+		s.samples[i].v = v
+		return
+	}
+	s.samples = append(s.samples, outOfOrderSample{})
+	copy(s.samples[i+1:], s.samples[i:])
+	s.samples[i] = outOfOrderSample{t: t, v: v}
+}
+
+// NewOutOfOrderHead returns a new OutOfOrderHead for db with the window
+// configured in opts and restores the samples from its write ahead log. The
+// window must not exceed half the minimum block duration, as older samples
+// are out of the bounds of the database's head.
+func NewOutOfOrderHead(db *tsdb.DB, opts *Options, l log.Logger, r prometheus.Registerer) (*OutOfOrderHead, error) {
+	if l == nil {
+		l = log.NewNopLogger()
+	}
+	window := int64(time.Duration(opts.OutOfOrderTimeWindow) / time.Millisecond)
+	blockRange := int64(time.Duration(opts.MinBlockDuration) / time.Millisecond)
+	if window <= 0 {
+		return nil, errors.Errorf("invalid out of order time window %s", opts.OutOfOrderTimeWindow)
+	}
+	if window > blockRange/2 {
+		return nil, errors.Errorf("out of order time window %s exceeds half the minimum block duration %s", opts.OutOfOrderTimeWindow, opts.MinBlockDuration)
+	}
+	dir := filepath.Join(db.Dir(), outOfOrderWALDir)
+	mergedUpTo, err := readMergedUpTo(dir)
+	if err != nil {
+		return nil, err
+	}
+	// The WAL metrics are not registered as they would collide with the
+	// ones of the database's WAL. It is flushed like the database's WAL.
+	wal, err := tsdb.OpenSegmentWAL(dir, l, 10*time.Second, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "open WAL")
+	}
+	h := &OutOfOrderHead{
+		db:         db,
+		dir:        dir,
+		wal:        wal,
+		window:     window,
+		logger:     l,
+		series:     map[uint64][]*outOfOrderSeries{},
+		refs:       map[uint64]*outOfOrderSeries{},
+		mergedUpTo: mergedUpTo,
+		replaced:   map[ulid.ULID]struct{}{},
+		appended: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "prometheus_tsdb_out_of_order_samples_appended_total",
+			Help: "Total number of out of order samples that were appended within the out of order time window.",
+		}),
+		merges: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "prometheus_tsdb_out_of_order_block_merges_total",
+			Help: "Total number of persisted blocks that were rewritten to merge in out of order samples.",
+		}),
+	}
+	if err := h.replay(); err != nil {
+		wal.Close()
+		return nil, errors.Wrap(err, "replay WAL")
+	}
+	if r != nil {
+		r.MustRegister(h.appended, h.merges)
+	}
+	return h, nil
+}
+
+// readMergedUpTo returns the time up to which the out of order samples were
+// merged into the blocks, as recorded in dir.
+func readMergedUpTo(dir string) (int64, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, outOfOrderMergedUpFile))
+	if os.IsNotExist(err) {
+		return math.MinInt64, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	t, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "parse %s", outOfOrderMergedUpFile)
+	}
+	return t, nil
+}
+
+// writeMergedUpTo records the time up to which the out of order samples were
+// merged into the blocks, so that they are not merged again after a restart.
+func (h *OutOfOrderHead) writeMergedUpTo(t int64) error {
+	fn := filepath.Join(h.dir, outOfOrderMergedUpFile)
+	tmp := fn + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(strconv.FormatInt(t, 10)), 0666); err != nil {
+		return err
+	}
+	return os.Rename(tmp, fn)
+}
+
+// replay restores the samples from the WAL that were not merged yet.
+func (h *OutOfOrderHead) replay() error {
+	return h.wal.Reader().Read(
+		func(series []tsdb.RefSeries) {
+			for _, s := range series {
+				h.addSeries(s.Ref, toLabels(s.Labels))
+				if s.Ref > h.lastRef {
+					h.lastRef = s.Ref
+				}
+			}
+		},
+		func(samples []tsdb.RefSample) {
+			for _, s := range samples {
+				if ser, ok := h.refs[s.Ref]; ok && s.T >= h.mergedUpTo {
+					ser.insert(s.T, s.V)
+				}
+			}
+		},
+		func([]tsdb.Stone) {},
+	)
+}
+
+// getSeries returns the series with the given labels or nil.
+func (h *OutOfOrderHead) getSeries(lset labels.Labels) *outOfOrderSeries {
+	for _, s := range h.series[lset.Hash()] {
+		if labels.Equal(s.lset, lset) {
+			return s
+		}
+	}
+	return nil
+}
+
+func (h *OutOfOrderHead) addSeries(ref uint64, lset labels.Labels) *outOfOrderSeries {
+	s := &outOfOrderSeries{ref: ref, lset: lset}
+	hash := lset.Hash()
+	h.series[hash] = append(h.series[hash], s)
+	h.refs[ref] = s
+	return s
+}
+
+func (h *OutOfOrderHead) deleteSeries(s *outOfOrderSeries) {
+	hash := s.lset.Hash()
+	all := h.series[hash]
+	for i, o := range all {
+		if o == s {
+			all = append(all[:i], all[i+1:]...)
+			break
+		}
+	}
+	if len(all) == 0 {
+		delete(h.series, hash)
+	} else {
+		h.series[hash] = all
+	}
+	delete(h.refs, s.ref)
+}
+
+// accepts returns whether an out of order sample with timestamp t and value v
+// is within the window and was not merged into the blocks yet. Stale markers
+// are never accepted, as they are only valid as the newest sample of a series
+// and are rejected as out of order like without the window.
+func (h *OutOfOrderHead) accepts(t int64, v float64) bool {
+	if value.IsStaleNaN(v) {
+		return false
+	}
+	return t >= h.db.Head().MaxTime()-h.window && t >= atomic.LoadInt64(&h.mergedUpTo)
+}
+
+// check returns an error if a different sample of the series was committed
+// for the same timestamp before.
+func (h *OutOfOrderHead) check(lset labels.Labels, t int64, v float64) error {
+	h.mtx.RLock()
+	defer h.mtx.RUnlock()
+
+	s := h.getSeries(lset)
+	if s == nil {
+		return nil
+	}
+	if i := s.index(t); i < len(s.samples) && s.samples[i].t == t && s.samples[i].v != v {
+		return tsdb.ErrAmendSample
+	}
+	return nil
+}
+
+// commit writes the samples to the WAL and adds them to memory.
+func (h *OutOfOrderHead) commit(samples []storage.Sample) error {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	var (
+		newSeries  []tsdb.RefSeries
+		refSamples = make([]tsdb.RefSample, 0, len(samples))
+		series     = make([]*outOfOrderSeries, 0, len(samples))
+	)
+	for _, smpl := range samples {
+		s := h.getSeries(smpl.Labels)
+		if s == nil {
+			h.lastRef++
+			s = h.addSeries(h.lastRef, smpl.Labels)
+			newSeries = append(newSeries, tsdb.RefSeries{Ref: s.ref, Labels: toTSDBLabels(s.lset)})
+		}
+		series = append(series, s)
+		refSamples = append(refSamples, tsdb.RefSample{Ref: s.ref, T: smpl.T, V: smpl.V})
+	}
+	if len(newSeries) > 0 {
+		if err := h.wal.LogSeries(newSeries); err != nil {
+			return err
+		}
+	}
+	if err := h.wal.LogSamples(refSamples); err != nil {
+		return err
+	}
+	for i, s := range series {
+		s.insert(samples[i].T, samples[i].V)
+	}
+	h.appended.Add(float64(len(samples)))
+	return nil
+}
+
+// truncate drops the samples before mint from memory and the WAL.
+func (h *OutOfOrderHead) truncate(mint int64) error {
+	h.mtx.Lock()
+	for _, s := range h.refs {
+		i := s.index(mint)
+		if i == len(s.samples) {
+			h.deleteSeries(s)
+			continue
+		}
+		s.samples = append([]outOfOrderSample(nil), s.samples[i:]...)
+	}
+	h.mtx.Unlock()
+
+	return h.wal.Truncate(mint, func(ref uint64) bool {
+		h.mtx.RLock()
+		defer h.mtx.RUnlock()
+
+		_, ok := h.refs[ref]
+		return ok
+	})
+}
+
+// Close closes the WAL of the out of order samples.
+func (h *OutOfOrderHead) Close() error {
+	return h.wal.Close()
+}
+
+// querier returns a querier of the out of order samples within [mint, maxt].
+func (h *OutOfOrderHead) querier(mint, maxt int64) storage.Querier {
+	return &outOfOrderQuerier{h: h, mint: mint, maxt: maxt}
+}
+
+// Run merges the out of order samples into the persisted blocks at the given
+// interval until the context is canceled.
+func (h *OutOfOrderHead) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := h.merge(); err != nil {
+			level.Error(h.logger).Log("msg", "Merging out of order samples failed", "err", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// merge rewrites the persisted blocks that were not checked yet with the out
+// of order samples of their range merged in. The merged samples are dropped
+// from memory and the WAL once the database loaded all rewritten blocks.
+func (h *OutOfOrderHead) merge() error {
+	rewriteMtx.Lock()
+	defer rewriteMtx.Unlock()
+
+	// Keep compactions from reloading the blocks while both the original
+	// and the rewritten block exist, as they overlap.
+	disableCompactions(h.db)
+	defer enableCompactions(h.db)
+
+	blocks := h.db.Blocks()
+
+	loaded := false
+	for _, b := range blocks {
+		if _, ok := h.replaced[b.Meta().ULID]; ok {
+			loaded = true
+			break
+		}
+	}
+	if !loaded {
+		h.replaced = map[ulid.ULID]struct{}{}
+		if err := h.truncate(h.mergedUpTo); err != nil {
+			return errors.Wrap(err, "truncate out of order samples")
+		}
+	}
+
+	// The range of the database's head is not persisted yet.
+	headMin := h.db.Head().MinTime()
+	for _, b := range blocks {
+		meta := b.Meta()
+		if meta.MaxTime <= h.mergedUpTo || meta.MaxTime > headMin {
+			continue
+		}
+		// Blocks that were removed before are still listed until the
+		// database is reloaded.
+		if _, err := os.Stat(b.Dir()); os.IsNotExist(err) {
+			continue
+		}
+		// Compactions may have joined merged and unmerged ranges.
+		mint := meta.MinTime
+		if h.mergedUpTo > mint {
+			mint = h.mergedUpTo
+		}
+		if h.hasSamples(mint, meta.MaxTime-1) {
+			level.Info(h.logger).Log("msg", "Merging out of order samples into block", "block", meta.ULID)
+			if err := h.mergeBlock(b, mint); err != nil {
+				return errors.Wrapf(err, "merge into block %s", meta.ULID)
+			}
+			h.replaced[meta.ULID] = struct{}{}
+			h.merges.Inc()
+		}
+		if err := h.writeMergedUpTo(meta.MaxTime); err != nil {
+			return errors.Wrap(err, "record merged time range")
+		}
+		atomic.StoreInt64(&h.mergedUpTo, meta.MaxTime)
+	}
+	return nil
+}
+
+// hasSamples returns whether there are out of order samples within
+// [mint, maxt].
+func (h *OutOfOrderHead) hasSamples(mint, maxt int64) bool {
+	h.mtx.RLock()
+	defer h.mtx.RUnlock()
+
+	for _, s := range h.refs {
+		if i := s.index(mint); i < len(s.samples) && s.samples[i].t <= maxt {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeBlock writes a new block with the samples of b and the out of order
+// samples from mint until the end of b that replaces b.
+func (h *OutOfOrderHead) mergeBlock(b *tsdb.Block, mint int64) error {
+	meta := b.Meta()
+	bq, err := newBlockQuerier(b)
+	if err != nil {
+		return errors.Wrap(err, "create block querier")
+	}
+	q := storage.NewMergeQuerier(bq, []storage.Querier{h.querier(mint, meta.MaxTime-1)})
+	defer q.Close()
+
+	// Without matchers, both queriers select all series.
+	set, _, err := q.Select(nil)
+	if err != nil {
+		return errors.Wrap(err, "select series")
+	}
+
+	tmp := filepath.Join(h.db.Dir(), meta.ULID.String()+rewriteTmpSuffix)
+	if err := os.RemoveAll(tmp); err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := writeBlock(tmp, set, meta.MinTime, meta.MaxTime, h.logger); err != nil {
+		return err
+	}
+	return replaceBlock(h.db.Dir(), tmp, b)
+}
+
+// outOfOrderQuerier queries the out of order samples within [mint, maxt].
+type outOfOrderQuerier struct {
+	h          *OutOfOrderHead
+	mint, maxt int64
+}
+
+// Select returns the series matching the matchers that have samples within
+// the querier's range. Their samples are copied as later samples may be
+// inserted between them.
+func (q *outOfOrderQuerier) Select(_ *storage.SelectParams, ms ...*labels.Matcher) (storage.SeriesSet, storage.Warnings, error) {
+	q.h.mtx.RLock()
+	defer q.h.mtx.RUnlock()
+
+	var res []storage.Series
+Outer:
+	for _, s := range q.h.refs {
+		for _, m := range ms {
+			if !m.Matches(s.lset.Get(m.Name)) {
+				continue Outer
+			}
+		}
+		i, j := s.index(q.mint), s.index(q.maxt+1)
+		if i == j {
+			continue
+		}
+		res = append(res, &outOfOrderSeriesData{
+			lset:    s.lset,
+			samples: append([]outOfOrderSample(nil), s.samples[i:j]...),
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return labels.Compare(res[i].Labels(), res[j].Labels()) < 0
+	})
+	return &outOfOrderSeriesSet{series: res, i: -1}, nil, nil
+}
+
+// LabelValues returns the values of the label name of all series.
+func (q *outOfOrderQuerier) LabelValues(name string) ([]string, error) {
+	q.h.mtx.RLock()
+	defer q.h.mtx.RUnlock()
+
+	set := map[string]struct{}{}
+	for _, s := range q.h.refs {
+		if v := s.lset.Get(name); v != "" {
+			set[v] = struct{}{}
+		}
+	}
+	return sortedKeys(set), nil
+}
+
+// LabelNames returns the label names of all series.
+func (q *outOfOrderQuerier) LabelNames() ([]string, error) {
+	q.h.mtx.RLock()
+	defer q.h.mtx.RUnlock()
+
+	set := map[string]struct{}{}
+	for _, s := range q.h.refs {
+		for _, l := range s.lset {
+			set[l.Name] = struct{}{}
+		}
+	}
+	return sortedKeys(set), nil
+}
+
+func (q *outOfOrderQuerier) Close() error { return nil }
+
+func sortedKeys(set map[string]struct{}) []string {
+	res := make([]string, 0, len(set))
+	for k := range set {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
+
+type outOfOrderSeriesSet struct {
+	series []storage.Series
+	i      int
+}
+
+func (s *outOfOrderSeriesSet) Next() bool {
+	s.i++
+	return s.i < len(s.series)
+}
+func (s *outOfOrderSeriesSet) At() storage.Series { return s.series[s.i] }
+func (s *outOfOrderSeriesSet) Err() error         { return nil }
+
+type outOfOrderSeriesData struct {
+	lset    labels.Labels
+	samples []outOfOrderSample
+}
+
+func (s *outOfOrderSeriesData) Labels() labels.Labels { return s.lset }
+func (s *outOfOrderSeriesData) Iterator() storage.SeriesIterator {
+	return &outOfOrderIterator{samples: s.samples, i: -1}
+}
+
+type outOfOrderIterator struct {
+	samples []outOfOrderSample
+	i       int
+}
+
+func (it *outOfOrderIterator) Seek(t int64) bool {
+	if it.i < 0 {
+		it.i = 0
+	}
+	for ; it.i < len(it.samples); it.i++ {
+		if it.samples[it.i].t >= t {
+			return true
+		}
+	}
+	return false
+}
+
+func (it *outOfOrderIterator) At() (int64, float64) {
+	s := it.samples[it.i]
+	return s.t, s.v
+}
+
+func (it *outOfOrderIterator) Next() bool {
+	it.i++
+	return it.i < len(it.samples)
+}
+
+func (it *outOfOrderIterator) Err() error { return nil }
@@ -0,0 +1,193 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"context"
+	"io/ioutil"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/tsdb"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/value"
+	"github.com/prometheus/prometheus/storage"
+)
+
+func TestOutOfOrderHead(t *testing.T) {
+	dir, err := ioutil.TempDir("", "out_of_order")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tsdbOpts := &tsdb.Options{
+		WALFlushInterval: 10 * time.Second,
+		BlockRanges:      []int64{1000},
+	}
+	db, err := tsdb.Open(dir, nil, nil, tsdbOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := &Options{
+		MinBlockDuration:     model.Duration(time.Second),
+		OutOfOrderTimeWindow: model.Duration(time.Second),
+	}
+	if _, err := NewOutOfOrderHead(db, opts, nil, nil); err == nil {
+		t.Fatal("expected error for window exceeding half the block duration")
+	}
+	opts.OutOfOrderTimeWindow = model.Duration(400 * time.Millisecond)
+	ooo, err := NewOutOfOrderHead(db, opts, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var s ReadyStorage
+	s.Set(db, 0, nil, ooo)
+
+	lset := labels.FromStrings("__name__", "a")
+	add := func(ts ...int64) error {
+		app, err := s.Appender()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, t := range ts {
+			if _, err := app.Add(lset, t, float64(t)); err != nil {
+				app.Rollback()
+				return err
+			}
+		}
+		return app.Commit()
+	}
+	query := func(mint, maxt int64) []int64 {
+		q, err := s.Querier(context.Background(), mint, maxt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer q.Close()
+
+		m, err := labels.NewMatcher(labels.MatchEqual, "__name__", "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		set, _, err := q.Select(nil, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var res []int64
+		for set.Next() {
+			it := set.At().Iterator()
+			for it.Next() {
+				ts, _ := it.At()
+				res = append(res, ts)
+			}
+		}
+		return res
+	}
+
+	if err := add(0, 200, 400, 600, 800, 1000); err != nil {
+		t.Fatal(err)
+	}
+	if err := add(750); err != nil {
+		t.Fatalf("expected sample within the window to be accepted, got %v", err)
+	}
+	// Late samples may arrive in any order within the window.
+	if err := add(700); err != nil {
+		t.Fatalf("expected sample older than a previous out of order sample to be accepted, got %v", err)
+	}
+	if err := add(500); err != storage.ErrOutOfOrderSample {
+		t.Fatalf("expected ErrOutOfOrderSample outside the window, got %v", err)
+	}
+	// Stale markers are only valid as the newest sample of a series.
+	app, err := s.Appender()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.Add(lset, 900, math.Float64frombits(value.StaleNaN)); err != storage.ErrOutOfOrderSample {
+		t.Fatalf("expected ErrOutOfOrderSample for stale marker within the window, got %v", err)
+	}
+	app.Rollback()
+	exp := []int64{600, 700, 750, 800, 1000}
+	if res := query(600, 1000); !equalTimestamps(res, exp) {
+		t.Fatalf("expected samples %v, got %v", exp, res)
+	}
+
+	// Out of order samples are restored from their WAL.
+	if err := ooo.Close(); err != nil {
+		t.Fatal(err)
+	}
+	ooo, err = NewOutOfOrderHead(db, opts, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ooo.Close()
+	s.Set(db, 0, nil, ooo)
+
+	if res := query(600, 1000); !equalTimestamps(res, exp) {
+		t.Fatalf("expected samples %v after restoring the WAL, got %v", exp, res)
+	}
+
+	// Persist the range of the out of order sample into a block and merge
+	// the sample into it.
+	if err := add(1200, 1400, 1600, 1800, 2000, 2200, 2400, 2600); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for len(db.Blocks()) < 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected a persisted block")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if err := ooo.merge(); err != nil {
+		t.Fatal(err)
+	}
+	// Samples of merged ranges are rejected.
+	if ooo.accepts(999, 0) {
+		t.Fatal("expected samples of merged blocks to be rejected")
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The merged block is loaded when the database is opened again.
+	db, err = tsdb.Open(dir, nil, nil, tsdbOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	s.Set(db, 0, nil, nil)
+
+	exp = []int64{0, 200, 400, 600, 700, 750, 800}
+	if res := query(0, 999); !equalTimestamps(res, exp) {
+		t.Fatalf("expected samples %v after merge, got %v", exp, res)
+	}
+}
+
+func equalTimestamps(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
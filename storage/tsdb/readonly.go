@@ -0,0 +1,179 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/oklog/ulid"
+	"github.com/pkg/errors"
+	"github.com/prometheus/tsdb"
+
+	"github.com/prometheus/prometheus/storage"
+)
+
+// ErrReadOnly is returned when appending to a storage opened read-only.
+var ErrReadOnly = errors.New("TSDB opened in read-only mode")
+
+// ReadOnlyDB provides read access to the persisted blocks of a database
+// directory that may be in use by a running Prometheus server at the same
+// time. It neither acquires the lockfile, nor compacts blocks, nor reads or
+// writes the write ahead log. Consequently, samples that are only in the
+// head block of the server are not visible.
+type ReadOnlyDB struct {
+	dir    string
+	logger log.Logger
+
+	mtx    sync.RWMutex
+	blocks []*tsdb.Block
+}
+
+// OpenReadOnly opens the blocks of the database in dir for reading. Unlike
+// Open, it does not create the directory if it does not exist.
+func OpenReadOnly(dir string, l log.Logger) (*ReadOnlyDB, error) {
+	if l == nil {
+		l = log.NewNopLogger()
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	db := &ReadOnlyDB{dir: dir, logger: l}
+	if err := db.Reload(); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// Reload opens the blocks that were added to the directory and closes the
+// ones that were deleted since the last reload, for example by compactions
+// of the server using the directory.
+func (db *ReadOnlyDB) Reload() error {
+	files, err := ioutil.ReadDir(db.dir)
+	if err != nil {
+		return err
+	}
+
+	db.mtx.Lock()
+	defer db.mtx.Unlock()
+
+	open := make(map[ulid.ULID]*tsdb.Block, len(db.blocks))
+	for _, b := range db.blocks {
+		open[b.Meta().ULID] = b
+	}
+	var blocks, opened []*tsdb.Block
+	for _, f := range files {
+		id, err := ulid.Parse(f.Name())
+		if err != nil || !f.IsDir() {
+			continue
+		}
+		if b, ok := open[id]; ok {
+			blocks = append(blocks, b)
+			delete(open, id)
+			continue
+		}
+		b, err := tsdb.OpenBlock(filepath.Join(db.dir, f.Name()), nil)
+		if os.IsNotExist(errors.Cause(err)) {
+			// The block was deleted after listing the directory.
+			level.Debug(db.logger).Log("msg", "Block vanished while opening it", "block", id)
+			continue
+		}
+		if err != nil {
+			for _, b := range opened {
+				b.Close()
+			}
+			return errors.Wrapf(err, "open block %s", id)
+		}
+		blocks = append(blocks, b)
+		opened = append(opened, b)
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].Meta().MinTime < blocks[j].Meta().MinTime
+	})
+	db.blocks = blocks
+
+	// Closing waits for pending readers of the deleted blocks.
+	for _, b := range open {
+		if err := b.Close(); err != nil {
+			level.Warn(db.logger).Log("msg", "Closing deleted block failed", "block", b.Meta().ULID, "err", err)
+		}
+	}
+	return nil
+}
+
+// Blocks returns the opened blocks ordered by time.
+func (db *ReadOnlyDB) Blocks() []*tsdb.Block {
+	db.mtx.RLock()
+	defer db.mtx.RUnlock()
+
+	return append([]*tsdb.Block(nil), db.blocks...)
+}
+
+// StartTime implements the Storage interface. It is the minimum timestamp
+// of the blocks, or the current time if there are none.
+func (db *ReadOnlyDB) StartTime() (int64, error) {
+	if blocks := db.Blocks(); len(blocks) > 0 {
+		return blocks[0].Meta().MinTime, nil
+	}
+	return int64(time.Now().Unix() * 1000), nil
+}
+
+// Querier implements the Storage interface.
+func (db *ReadOnlyDB) Querier(_ context.Context, mint, maxt int64) (storage.Querier, error) {
+	db.mtx.RLock()
+	defer db.mtx.RUnlock()
+
+	var queriers []storage.Querier
+	for _, b := range db.blocks {
+		if m := b.Meta(); m.MaxTime < mint || m.MinTime > maxt {
+			continue
+		}
+		q, err := tsdb.NewBlockQuerier(b, mint, maxt)
+		if err != nil {
+			for _, q := range queriers {
+				q.Close()
+			}
+			return nil, errors.Wrapf(err, "open querier for block %s", b.Meta().ULID)
+		}
+		queriers = append(queriers, querier{q: q, readers: []tsdb.BlockReader{b}})
+	}
+	return storage.NewMergeQuerier(nil, queriers), nil
+}
+
+// Appender implements the Storage interface. It always returns ErrReadOnly.
+func (db *ReadOnlyDB) Appender() (storage.Appender, error) {
+	return nil, ErrReadOnly
+}
+
+// Close closes all blocks.
+func (db *ReadOnlyDB) Close() error {
+	db.mtx.Lock()
+	defer db.mtx.Unlock()
+
+	var merr error
+	for _, b := range db.blocks {
+		if err := b.Close(); err != nil && merr == nil {
+			merr = err
+		}
+	}
+	db.blocks = nil
+	return merr
+}
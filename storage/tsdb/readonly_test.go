@@ -0,0 +1,115 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"context"
+	"io/ioutil"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+)
+
+func TestReadOnlyDB(t *testing.T) {
+	dir, err := ioutil.TempDir("", "readonly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	input := []byte(`metric{a="1"} 1 1
+metric{a="1"} 2 11
+metric{a="2"} 3 21
+# EOF
+`)
+	if err := CreateBlocksFromOpenMetrics(input, dir, 10*time.Second, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Hold the lock of the directory like a running server.
+	live, err := Open(dir, nil, nil, &Options{
+		MinBlockDuration: model.Duration(2 * time.Hour),
+		MaxBlockDuration: model.Duration(2 * time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer live.Close()
+
+	db, err := OpenReadOnly(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if n := len(db.Blocks()); n != 3 {
+		t.Fatalf("expected 3 blocks, got %d", n)
+	}
+	if start, err := db.StartTime(); err != nil || start != 0 {
+		t.Fatalf("expected start time 0, got %d (err: %v)", start, err)
+	}
+	if _, err := db.Appender(); err != ErrReadOnly {
+		t.Fatalf("expected ErrReadOnly, got %v", err)
+	}
+
+	q, err := db.Querier(context.Background(), 5000, math.MaxInt64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := labels.NewMatcher(labels.MatchEqual, "__name__", "metric")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss, _, err := q.Select(nil, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var samples []float64
+	for ss.Next() {
+		it := ss.At().Iterator()
+		for it.Next() {
+			_, v := it.At()
+			samples = append(samples, v)
+		}
+	}
+	if err := ss.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 || samples[0] != 2 || samples[1] != 3 {
+		t.Fatalf("unexpected samples %v", samples)
+	}
+	names, err := q.LabelNames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "__name__" || names[1] != "a" {
+		t.Fatalf("unexpected label names %v", names)
+	}
+	q.Close()
+
+	// Deleted blocks are dropped on reload.
+	if err := os.RemoveAll(db.Blocks()[0].Dir()); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(db.Blocks()); n != 2 {
+		t.Fatalf("expected 2 blocks after reload, got %d", n)
+	}
+}
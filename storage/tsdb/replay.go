@@ -0,0 +1,143 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+)
+
+// errOpenFilesUnsupported is returned where the offsets of open files cannot
+// be determined.
+var errOpenFilesUnsupported = errors.New("listing open files is not supported on this platform")
+
+// WALReplayProgress is the progress of replaying the write ahead log while
+// the database is opened.
+type WALReplayProgress struct {
+	// The number of segments of the WAL and of those not completely read yet.
+	Segments          int
+	SegmentsRemaining int
+	// The size of the segments and the number of bytes read. Segments are
+	// preallocated, so the progress of the last segment is underestimated.
+	Bytes     int64
+	BytesRead int64
+}
+
+// Ratio returns the fraction of the WAL that was read.
+func (p WALReplayProgress) Ratio() float64 {
+	if p.Bytes == 0 {
+		return 0
+	}
+	return float64(p.BytesRead) / float64(p.Bytes)
+}
+
+func (p WALReplayProgress) String() string {
+	return fmt.Sprintf("WAL replay %d%% done, %d of %d segments remaining", int(100*p.Ratio()), p.SegmentsRemaining, p.Segments)
+}
+
+type walSegment struct {
+	path string
+	size int64
+}
+
+// walReplay tracks the replay of the WAL of a database being opened. The
+// database does not report its progress, so it is derived from the offsets
+// of the segment files it holds open. Segments are read in order and closed
+// once they were read completely, except for the last one.
+type walReplay struct {
+	segments []walSegment
+	bytes    int64
+
+	// openFiles returns the offsets of the open files by absolute path.
+	openFiles func() (map[string]int64, error)
+}
+
+// newWALReplay returns a walReplay of the database in dir. The segments are
+// listed right away as the database may write new ones once it is open.
+func newWALReplay(dir string) (*walReplay, error) {
+	walDir, err := filepath.Abs(filepath.Join(dir, "wal"))
+	if err != nil {
+		return nil, err
+	}
+	r := &walReplay{openFiles: openFiles}
+
+	fis, err := ioutil.ReadDir(walDir)
+	if err != nil {
+		// The database creates the WAL directory if it does not exist yet.
+		return r, nil
+	}
+	// Open files are reported by their resolved path.
+	if walDir, err = filepath.EvalSymlinks(walDir); err != nil {
+		return nil, err
+	}
+	// ReadDir sorts by name, which is the order segments are read in.
+	for _, fi := range fis {
+		if _, err := strconv.ParseUint(fi.Name(), 10, 64); err != nil {
+			continue
+		}
+		r.segments = append(r.segments, walSegment{
+			path: filepath.Join(walDir, fi.Name()),
+			size: fi.Size(),
+		})
+		r.bytes += fi.Size()
+	}
+	return r, nil
+}
+
+// progress returns the current progress of the replay. It fails if the
+// offsets of open files are unknown on this platform.
+func (r *walReplay) progress() (WALReplayProgress, error) {
+	p := WALReplayProgress{
+		Segments:          len(r.segments),
+		SegmentsRemaining: len(r.segments),
+		Bytes:             r.bytes,
+	}
+	if len(r.segments) == 0 {
+		return p, nil
+	}
+	offsets, err := r.openFiles()
+	if err != nil {
+		return p, err
+	}
+
+	var (
+		remaining int
+		read      int64
+		current   = -1
+	)
+	for i, s := range r.segments {
+		off, ok := offsets[s.path]
+		if !ok {
+			continue
+		}
+		if current < 0 {
+			current = i
+			read += off
+		}
+		remaining++
+	}
+	// None of the segments are open before the database opened its WAL.
+	if current < 0 {
+		return p, nil
+	}
+	for _, s := range r.segments[:current] {
+		read += s.size
+	}
+	p.SegmentsRemaining = remaining
+	p.BytesRead = read
+	return p, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// openFiles returns the offsets of the files opened by the process by their
+// path, as reported by procfs.
+func openFiles() (map[string]int64, error) {
+	fds, err := filepath.Glob("/proc/self/fd/*")
+	if err != nil {
+		return nil, err
+	}
+	offsets := make(map[string]int64, len(fds))
+	for _, fd := range fds {
+		// Files may be closed while they are listed.
+		path, err := os.Readlink(fd)
+		if err != nil {
+			continue
+		}
+		off, err := fdOffset(filepath.Join("/proc/self/fdinfo", filepath.Base(fd)))
+		if err != nil {
+			continue
+		}
+		offsets[path] = off
+	}
+	return offsets, nil
+}
+
+// fdOffset reads the offset of a file descriptor from its fdinfo file.
+func fdOffset(fdinfo string) (int64, error) {
+	f, err := os.Open(fdinfo)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if v := strings.TrimPrefix(s.Text(), "pos:"); v != s.Text() {
+			return strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return 0, err
+	}
+	return 0, os.ErrNotExist
+}
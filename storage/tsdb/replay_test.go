@@ -0,0 +1,149 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/prometheus/tsdb"
+)
+
+func TestWALReplayProgress(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.MkdirAll(filepath.Join(dir, "wal"), 0777); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"000001", "000002", "000003", "lock"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, "wal", name), make([]byte, 100), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := newWALReplay(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	walDir := filepath.Dir(r.segments[0].path)
+
+	for _, c := range []struct {
+		open     map[string]int64
+		expected WALReplayProgress
+	}{
+		{
+			// The WAL was not opened yet.
+			open:     map[string]int64{},
+			expected: WALReplayProgress{Segments: 3, SegmentsRemaining: 3, Bytes: 300},
+		}, {
+			open: map[string]int64{
+				filepath.Join(walDir, "000001"): 8,
+				filepath.Join(walDir, "000002"): 8,
+				filepath.Join(walDir, "000003"): 8,
+			},
+			expected: WALReplayProgress{Segments: 3, SegmentsRemaining: 3, Bytes: 300, BytesRead: 8},
+		}, {
+			open: map[string]int64{
+				filepath.Join(walDir, "000002"): 50,
+				filepath.Join(walDir, "000003"): 8,
+			},
+			expected: WALReplayProgress{Segments: 3, SegmentsRemaining: 2, Bytes: 300, BytesRead: 150},
+		},
+	} {
+		r.openFiles = func() (map[string]int64, error) { return c.open, nil }
+		p, err := r.progress()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p != c.expected {
+			t.Fatalf("unexpected progress: got %+v, want %+v", p, c.expected)
+		}
+	}
+
+	// The offsets of segments are read from procfs on Linux.
+	if runtime.GOOS == "linux" {
+		f, err := os.Open(r.segments[1].path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.Seek(42, 0); err != nil {
+			t.Fatal(err)
+		}
+		r.openFiles = openFiles
+		p, err := r.progress()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.SegmentsRemaining != 1 || p.BytesRead != 142 {
+			t.Fatalf("unexpected progress %+v", p)
+		}
+	}
+
+	if s := (WALReplayProgress{Segments: 3, SegmentsRemaining: 2, Bytes: 300, BytesRead: 150}).String(); s != "WAL replay 50% done, 2 of 3 segments remaining" {
+		t.Fatalf("unexpected description %q", s)
+	}
+}
+
+func TestReadyStorageNotReadyReason(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := &ReadyStorage{}
+	if reason := s.NotReadyReason(); reason != "TSDB is starting" {
+		t.Fatalf("unexpected reason %q", reason)
+	}
+	if err := s.StartWALReplay(dir); err != nil {
+		t.Fatal(err)
+	}
+	if reason := s.NotReadyReason(); reason != "WAL replay 0% done, 0 of 0 segments remaining" {
+		t.Fatalf("unexpected reason %q", reason)
+	}
+
+	db, err := tsdb.Open(dir, nil, nil, &tsdb.Options{
+		WALFlushInterval: 10 * time.Second,
+		BlockRanges:      []int64{1000},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// The segment created by the database is held open, but it was not
+	// there when the replay started.
+	if runtime.GOOS == "linux" {
+		if p, ok := s.WALReplayProgress(); !ok || p.Segments != 0 {
+			t.Fatalf("unexpected progress %+v", p)
+		}
+	}
+
+	s.Set(db, 0, nil, nil)
+	if reason := s.NotReadyReason(); reason != "" {
+		t.Fatalf("unexpected reason %q", reason)
+	}
+	if _, ok := s.WALReplayProgress(); ok {
+		t.Fatalf("unexpected progress after the storage was set")
+	}
+}
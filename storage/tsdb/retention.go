@@ -0,0 +1,161 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/tsdb"
+)
+
+// SizeRetention deletes the oldest persisted blocks of a database while the
+// total size of all blocks exceeds a limit. It complements the time based
+// retention of the database.
+type SizeRetention struct {
+	db       *tsdb.DB
+	maxBytes int64
+	logger   log.Logger
+
+	size      prometheus.Gauge
+	limit     prometheus.Gauge
+	deletions prometheus.Counter
+}
+
+// NewSizeRetention returns a new SizeRetention for the blocks of db. A
+// maxBytes of zero or less disables deleting blocks, but the size of the
+// blocks is still reported.
+func NewSizeRetention(db *tsdb.DB, maxBytes int64, l log.Logger, r prometheus.Registerer) *SizeRetention {
+	if l == nil {
+		l = log.NewNopLogger()
+	}
+	s := &SizeRetention{
+		db:       db,
+		maxBytes: maxBytes,
+		logger:   l,
+		size: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "prometheus_tsdb_storage_blocks_bytes",
+			Help: "The number of bytes that are currently used for local storage by all blocks.",
+		}),
+		limit: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "prometheus_tsdb_retention_limit_bytes",
+			Help: "Max number of bytes to be retained in the tsdb blocks, configured 0 means disabled.",
+		}),
+		deletions: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "prometheus_tsdb_size_retentions_total",
+			Help: "The number of times that blocks were deleted because the maximum number of bytes was exceeded.",
+		}),
+	}
+	if maxBytes > 0 {
+		s.limit.Set(float64(maxBytes))
+	}
+	if r != nil {
+		r.MustRegister(s.size, s.limit, s.deletions)
+	}
+	return s
+}
+
+// Run checks the size of the blocks at the given interval until the context
+// is canceled.
+func (s *SizeRetention) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.cutoff(); err != nil {
+			level.Error(s.logger).Log("msg", "Size retention cutoff failed", "err", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// cutoff deletes the directories of the oldest blocks until the size of the
+// remaining blocks does not exceed the limit. Like the time based retention,
+// the deleted blocks are dropped from the database with its next reload.
+func (s *SizeRetention) cutoff() error {
+	// Keep blocks from being rewritten while their size is determined and
+	// they are deleted.
+	rewriteMtx.Lock()
+	defer rewriteMtx.Unlock()
+
+	blocks := s.db.Blocks()
+
+	var total int64
+	sizes := make([]int64, len(blocks))
+	for i, b := range blocks {
+		size, err := dirSize(b.Dir())
+		if err != nil {
+			return err
+		}
+		sizes[i] = size
+		total += size
+	}
+	s.size.Set(float64(total))
+
+	if s.maxBytes <= 0 || total <= s.maxBytes {
+		return nil
+	}
+
+	// Keep compactions from reading blocks while they are deleted.
+	disableCompactions(s.db)
+	defer enableCompactions(s.db)
+
+	s.deletions.Inc()
+	// Blocks are ordered by time, so the oldest ones are deleted first.
+	for i, b := range blocks {
+		if total <= s.maxBytes {
+			break
+		}
+		// Blocks that were deleted before are still listed until the
+		// database is reloaded.
+		if sizes[i] == 0 {
+			continue
+		}
+		level.Info(s.logger).Log("msg", "Deleting block to stay below the maximum size", "block", b.Meta().ULID, "bytes", sizes[i])
+		if err := os.RemoveAll(b.Dir()); err != nil {
+			return err
+		}
+		total -= sizes[i]
+	}
+	s.size.Set(float64(total))
+	return nil
+}
+
+// dirSize returns the total size of the files in dir. It is zero if the
+// directory does not exist.
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	return size, err
+}
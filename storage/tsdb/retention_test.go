@@ -0,0 +1,111 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/prometheus/tsdb"
+	"github.com/prometheus/tsdb/labels"
+)
+
+func TestSizeRetention(t *testing.T) {
+	dir, err := ioutil.TempDir("", "size_retention")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := tsdb.Open(dir, nil, nil, &tsdb.Options{
+		WALFlushInterval: 10 * time.Second,
+		BlockRanges:      []int64{1000},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Samples spanning several block ranges trigger persisting the head
+	// into nine blocks.
+	app := db.Appender()
+	for ts := int64(0); ts < 10000; ts += 100 {
+		if _, err := app.Add(labels.FromStrings("a", "b"), ts, float64(ts)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := app.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for len(db.Blocks()) < 9 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 9 blocks, got %d", len(db.Blocks()))
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	blocks := db.Blocks()
+
+	exists := func(dir string) bool {
+		_, err := os.Stat(dir)
+		if err != nil && !os.IsNotExist(err) {
+			t.Fatal(err)
+		}
+		return err == nil
+	}
+
+	// Without a limit the size is only reported.
+	r := NewSizeRetention(db, 0, nil, nil)
+	if err := r.cutoff(); err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range blocks {
+		if !exists(b.Dir()) {
+			t.Fatalf("block %s was deleted without a limit", b.Meta().ULID)
+		}
+	}
+
+	// With a limit just below the total size only the oldest block is deleted.
+	var total int64
+	for _, b := range blocks {
+		size, err := dirSize(b.Dir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		total += size
+	}
+	r = NewSizeRetention(db, total-1, nil, nil)
+	if err := r.cutoff(); err != nil {
+		t.Fatal(err)
+	}
+	if exists(blocks[0].Dir()) {
+		t.Fatalf("oldest block was not deleted")
+	}
+	for _, b := range blocks[1:] {
+		if !exists(b.Dir()) {
+			t.Fatalf("block %s was deleted although the size was below the limit", b.Meta().ULID)
+		}
+	}
+
+	// Deleted blocks that are still loaded do not count towards the size.
+	if err := r.cutoff(); err != nil {
+		t.Fatal(err)
+	}
+	if !exists(blocks[1].Dir()) {
+		t.Fatalf("block %s was deleted although the size was below the limit", blocks[1].Meta().ULID)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/tsdb"
+	tsdbLabels "github.com/prometheus/tsdb/labels"
+)
+
+// Stat is a single cardinality statistic.
+type Stat struct {
+	Name  string `json:"name"`
+	Value uint64 `json:"value"`
+}
+
+// HeadStats holds cardinality statistics about the series in the head block.
+type HeadStats struct {
+	NumSeries uint64 `json:"numSeries"`
+	NumChunks uint64 `json:"chunkCount"`
+	// The top entries of the respective statistics, sorted in descending order.
+	SeriesCountByMetricName     []Stat `json:"seriesCountByMetricName"`
+	LabelValueCountByLabelName  []Stat `json:"labelValueCountByLabelName"`
+	MemoryInBytesByLabelName    []Stat `json:"memoryInBytesByLabelName"`
+	SeriesCountByLabelValuePair []Stat `json:"seriesCountByLabelValuePair"`
+}
+
+// ComputeHeadStats scans the index of the head block of the database and
+// returns its cardinality statistics. Each of the ranked statistics is
+// limited to the given number of top entries.
+func ComputeHeadStats(db *tsdb.DB, limit int) (*HeadStats, error) {
+	ir, err := db.Head().Index()
+	if err != nil {
+		return nil, errors.Wrap(err, "open head index")
+	}
+	defer ir.Close()
+
+	var (
+		stats          = &HeadStats{}
+		seriesByMetric = map[string]uint64{}
+		valuesByName   = map[string]uint64{}
+		memoryByName   = map[string]uint64{}
+		seriesByPair   = map[string]uint64{}
+	)
+
+	names, err := ir.LabelIndices()
+	if err != nil {
+		return nil, errors.Wrap(err, "read label indices")
+	}
+	for _, n := range names {
+		// The head only holds indices for single label names.
+		if len(n) != 1 || n[0] == "" {
+			continue
+		}
+		name := n[0]
+
+		tpls, err := ir.LabelValues(name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read values of label %q", name)
+		}
+		valuesByName[name] = uint64(tpls.Len())
+
+		for i := 0; i < tpls.Len(); i++ {
+			vals, err := tpls.At(i)
+			if err != nil {
+				return nil, err
+			}
+			value := vals[0]
+			// Label values are interned, so they are only held once.
+			memoryByName[name] += uint64(len(value))
+
+			p, err := ir.Postings(name, value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "read postings of %s=%q", name, value)
+			}
+			var count uint64
+			for p.Next() {
+				count++
+			}
+			if err := p.Err(); err != nil {
+				return nil, err
+			}
+			seriesByPair[name+"="+value] = count
+
+			if name == labels.MetricName {
+				seriesByMetric[value] = count
+			}
+		}
+	}
+
+	// The empty label pair holds the postings of all series.
+	p, err := ir.Postings("", "")
+	if err != nil {
+		return nil, errors.Wrap(err, "read all postings")
+	}
+	var (
+		lset tsdbLabels.Labels
+		chks []tsdb.ChunkMeta
+	)
+	for p.Next() {
+		if err := ir.Series(p.At(), &lset, &chks); err != nil {
+			// Series may be garbage collected while scanning.
+			if err == tsdb.ErrNotFound {
+				continue
+			}
+			return nil, err
+		}
+		stats.NumSeries++
+		stats.NumChunks += uint64(len(chks))
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+
+	stats.SeriesCountByMetricName = topStats(seriesByMetric, limit)
+	stats.LabelValueCountByLabelName = topStats(valuesByName, limit)
+	stats.MemoryInBytesByLabelName = topStats(memoryByName, limit)
+	stats.SeriesCountByLabelValuePair = topStats(seriesByPair, limit)
+
+	return stats, nil
+}
+
+// topStats returns the entries with the highest values in descending order.
+// Entries with equal values are sorted by name.
+func topStats(m map[string]uint64, limit int) []Stat {
+	res := make([]Stat, 0, len(m))
+	for name, v := range m {
+		res = append(res, Stat{Name: name, Value: v})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Value != res[j].Value {
+			return res[i].Value > res[j].Value
+		}
+		return res[i].Name < res[j].Name
+	})
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return res
+}
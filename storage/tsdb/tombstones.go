@@ -0,0 +1,556 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/tsdb"
+	"github.com/prometheus/tsdb/chunks"
+	tsdbLabels "github.com/prometheus/tsdb/labels"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/storage"
+)
+
+// DeleteSeries marks the samples of the series matching all matchers
+// within [mint, maxt] as deleted. The samples are hidden from queries
+// right away but only removed from disk when the blocks are compacted or
+// their tombstones are cleaned.
+func DeleteSeries(db *tsdb.DB, mint, maxt int64, ms ...*labels.Matcher) error {
+	tms := make([]tsdbLabels.Matcher, 0, len(ms))
+	for _, m := range ms {
+		tms = append(tms, convertMatcher(m))
+	}
+	return db.Delete(mint, maxt, tms...)
+}
+
+// CleanTombstones rewrites the persisted blocks of db that have tombstones
+// without the deleted data and removes the original blocks from disk.
+// Like the deletions of the retention, the rewritten blocks replace the
+// original ones in the database with its next reload. Until then, queries
+// keep reading the original blocks with their tombstones applied.
+func CleanTombstones(db *tsdb.DB, l log.Logger) error {
+	if l == nil {
+		l = log.NewNopLogger()
+	}
+	rewriteMtx.Lock()
+	defer rewriteMtx.Unlock()
+
+	// Keep compactions from reloading the blocks while both the original
+	// and the rewritten block exist, as they overlap.
+	disableCompactions(db)
+	defer enableCompactions(db)
+
+	for _, b := range db.Blocks() {
+		meta := b.Meta()
+		if meta.Stats.NumTombstones == 0 {
+			continue
+		}
+		// Blocks that were removed before are still listed until the
+		// database is reloaded.
+		if _, err := os.Stat(b.Dir()); os.IsNotExist(err) {
+			continue
+		}
+		level.Info(l).Log("msg", "Rewriting block without tombstones", "block", meta.ULID, "tombstones", meta.Stats.NumTombstones)
+		if err := rewriteBlock(db.Dir(), b, l); err != nil {
+			return errors.Wrapf(err, "rewrite block %s", meta.ULID)
+		}
+	}
+	return nil
+}
+
+// rewriteBlock writes the samples of b that are not deleted into a new block
+// in dir that replaces b. No block is written if all samples of b are deleted.
+//
+// The block is written by the compactor, which drops the chunks that are
+// deleted entirely. The compactor of the vendored tsdb version discards the
+// chunks it re-encodes without the deleted samples, so the chunks deleted in
+// part are re-encoded while they are read instead. The block is written into
+// a temporary directory first to find out whether any samples are left.
+func rewriteBlock(dir string, b *tsdb.Block, l log.Logger) error {
+	meta := b.Meta()
+	deleted, err := deletedChunks(b)
+	if err != nil {
+		return err
+	}
+	compactor, err := tsdb.NewLeveledCompactor(nil, l, []int64{meta.MaxTime - meta.MinTime}, nil)
+	if err != nil {
+		return errors.Wrap(err, "create compactor")
+	}
+	tmp := filepath.Join(dir, meta.ULID.String()+rewriteTmpSuffix)
+	if err := os.RemoveAll(tmp); err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := compactor.Write(tmp, deletedChunksBlock{BlockReader: b, deleted: deleted}, meta.MinTime, meta.MaxTime); err != nil {
+		return errors.Wrap(err, "write block")
+	}
+	blocks, err := ioutil.ReadDir(tmp)
+	if err != nil {
+		return err
+	}
+	for _, fi := range blocks {
+		bdir := filepath.Join(tmp, fi.Name())
+		b, err := ioutil.ReadFile(filepath.Join(bdir, "meta.json"))
+		if err != nil {
+			return errors.Wrap(err, "read meta of rewritten block")
+		}
+		var m tsdb.BlockMeta
+		if err := json.Unmarshal(b, &m); err != nil {
+			return errors.Wrap(err, "read meta of rewritten block")
+		}
+		if m.Stats.NumSamples == 0 {
+			if err := os.RemoveAll(bdir); err != nil {
+				return err
+			}
+		}
+	}
+	return replaceBlock(dir, tmp, b)
+}
+
+const (
+	// rewriteTmpSuffix is the suffix of the temporary directories that
+	// rewritten blocks are written into.
+	rewriteTmpSuffix = ".rewrite.tmp"
+	// deletableFile marks a block that is replaced by a rewritten block. It
+	// holds the directory name of the replacement, or nothing if the block
+	// is removed without one.
+	deletableFile = "deletable"
+)
+
+// replaceBlock moves the block written into tmp into dir and removes b. If
+// tmp holds no block, b is only removed. b is marked deletable first, so that
+// removeReplacedBlocks keeps either b or its replacement after a crash in
+// between, as the database does not open overlapping blocks.
+func replaceBlock(dir, tmp string, b *tsdb.Block) error {
+	fis, err := ioutil.ReadDir(tmp)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	var name string
+	switch len(fis) {
+	case 0:
+	case 1:
+		name = fis[0].Name()
+	default:
+		return errors.Errorf("expected at most one rewritten block, got %d", len(fis))
+	}
+
+	fn := filepath.Join(b.Dir(), deletableFile)
+	if err := ioutil.WriteFile(fn+".tmp", []byte(name), 0666); err != nil {
+		return errors.Wrap(err, "mark block deletable")
+	}
+	if err := os.Rename(fn+".tmp", fn); err != nil {
+		return errors.Wrap(err, "mark block deletable")
+	}
+	if name != "" {
+		if err := os.Rename(filepath.Join(tmp, name), filepath.Join(dir, name)); err != nil {
+			return errors.Wrap(err, "move rewritten block")
+		}
+	}
+	if err := os.RemoveAll(b.Dir()); err != nil {
+		return errors.Wrap(err, "remove block")
+	}
+	return nil
+}
+
+// removeReplacedBlocks finishes the block replacements in dir that were
+// interrupted by a crash. Blocks marked deletable are removed if their
+// replacement is in dir and kept otherwise. Temporary directories of
+// rewritten blocks are removed.
+func removeReplacedBlocks(dir string) error {
+	fis, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, fi := range fis {
+		if !fi.IsDir() {
+			continue
+		}
+		bdir := filepath.Join(dir, fi.Name())
+		if strings.HasSuffix(fi.Name(), rewriteTmpSuffix) {
+			if err := os.RemoveAll(bdir); err != nil {
+				return err
+			}
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(bdir, deletableFile))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if name := string(b); name != "" {
+			if _, err := os.Stat(filepath.Join(dir, name)); os.IsNotExist(err) {
+				// The replacement was not moved into dir.
+				if err := os.Remove(filepath.Join(bdir, deletableFile)); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if err := os.RemoveAll(bdir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deletedChunks returns the deleted intervals of the chunks of b that
+// overlap with the tombstones of their series, by chunk reference.
+func deletedChunks(b tsdb.BlockReader) (map[uint64]tsdb.Intervals, error) {
+	ir, err := b.Index()
+	if err != nil {
+		return nil, errors.Wrap(err, "open index reader")
+	}
+	defer ir.Close()
+	tr, err := b.Tombstones()
+	if err != nil {
+		return nil, errors.Wrap(err, "open tombstone reader")
+	}
+	defer tr.Close()
+
+	// The postings of the empty label hold all series.
+	p, err := ir.Postings("", "")
+	if err != nil {
+		return nil, err
+	}
+	res := map[uint64]tsdb.Intervals{}
+	for p.Next() {
+		ivs := tr.Get(p.At())
+		if len(ivs) == 0 {
+			continue
+		}
+		var (
+			lset tsdbLabels.Labels
+			chks []tsdb.ChunkMeta
+		)
+		if err := ir.Series(p.At(), &lset, &chks); err != nil {
+			return nil, errors.Wrapf(err, "read series %d", p.At())
+		}
+		for _, c := range chks {
+			for _, iv := range ivs {
+				if c.MinTime <= iv.Maxt && iv.Mint <= c.MaxTime {
+					res[c.Ref] = ivs
+					break
+				}
+			}
+		}
+	}
+	return res, p.Err()
+}
+
+// deletedChunksBlock is a block whose chunks are read without the samples
+// deleted from them.
+type deletedChunksBlock struct {
+	tsdb.BlockReader
+	deleted map[uint64]tsdb.Intervals
+}
+
+func (b deletedChunksBlock) Chunks() (tsdb.ChunkReader, error) {
+	cr, err := b.BlockReader.Chunks()
+	if err != nil {
+		return nil, err
+	}
+	return deletedChunkReader{ChunkReader: cr, deleted: b.deleted}, nil
+}
+
+type deletedChunkReader struct {
+	tsdb.ChunkReader
+	deleted map[uint64]tsdb.Intervals
+}
+
+// Chunk returns the chunk with the given reference, re-encoded without its
+// deleted samples.
+func (r deletedChunkReader) Chunk(ref uint64) (chunks.Chunk, error) {
+	c, err := r.ChunkReader.Chunk(ref)
+	if err != nil {
+		return nil, err
+	}
+	ivs, ok := r.deleted[ref]
+	if !ok {
+		return c, nil
+	}
+	res := chunks.NewXORChunk()
+	app, err := res.Appender()
+	if err != nil {
+		return nil, err
+	}
+	it := c.Iterator()
+	for it.Next() {
+		if t, v := it.At(); !isDeleted(ivs, t) {
+			app.Append(t, v)
+		}
+	}
+	return res, it.Err()
+}
+
+// isDeleted returns whether t is within any of the deleted intervals.
+func isDeleted(ivs tsdb.Intervals, t int64) bool {
+	for _, iv := range ivs {
+		if t >= iv.Mint && t <= iv.Maxt {
+			return true
+		}
+	}
+	return false
+}
+
+// writeBlock writes the samples of all series of set into a new block for
+// the range [mint, maxt) in dir. No block is written if there are no samples.
+func writeBlock(dir string, set storage.SeriesSet, mint, maxt int64, l log.Logger) error {
+	// Like for backfilling, twice the block range fits all samples of the
+	// block into the head.
+	head, err := tsdb.NewHead(nil, l, nil, 2*(maxt-mint))
+	if err != nil {
+		return errors.Wrap(err, "create head")
+	}
+
+	var (
+		app     = head.Appender()
+		pending int
+		total   int
+	)
+	for set.Next() {
+		s := set.At()
+		lset := toTSDBLabels(s.Labels())
+
+		it := s.Iterator()
+		for it.Next() {
+			t, v := it.At()
+			if _, err := app.Add(lset, t, v); err != nil {
+				app.Rollback()
+				return errors.Wrapf(err, "add sample of series %s", lset)
+			}
+			pending++
+			total++
+
+			if pending >= backfillCommitSize {
+				if err := app.Commit(); err != nil {
+					return errors.Wrap(err, "commit samples")
+				}
+				app = head.Appender()
+				pending = 0
+			}
+		}
+		if err := it.Err(); err != nil {
+			app.Rollback()
+			return errors.Wrapf(err, "read samples of series %s", lset)
+		}
+	}
+	if err := set.Err(); err != nil {
+		app.Rollback()
+		return errors.Wrap(err, "select series")
+	}
+	if err := app.Commit(); err != nil {
+		return errors.Wrap(err, "commit samples")
+	}
+	if total == 0 {
+		return nil
+	}
+
+	compactor, err := tsdb.NewLeveledCompactor(nil, l, []int64{maxt - mint}, nil)
+	if err != nil {
+		return errors.Wrap(err, "create compactor")
+	}
+	return compactor.Write(dir, head, mint, maxt)
+}
+
+// blockQuerier selects all series of a block regardless of the matchers,
+// including series without a metric name, which no matcher selects from a
+// block. Deleted samples are left out.
+type blockQuerier struct {
+	ir tsdb.IndexReader
+	cr tsdb.ChunkReader
+	tr tsdb.TombstoneReader
+}
+
+func newBlockQuerier(b tsdb.BlockReader) (*blockQuerier, error) {
+	ir, err := b.Index()
+	if err != nil {
+		return nil, errors.Wrap(err, "open index reader")
+	}
+	cr, err := b.Chunks()
+	if err != nil {
+		ir.Close()
+		return nil, errors.Wrap(err, "open chunk reader")
+	}
+	tr, err := b.Tombstones()
+	if err != nil {
+		ir.Close()
+		cr.Close()
+		return nil, errors.Wrap(err, "open tombstone reader")
+	}
+	return &blockQuerier{ir: ir, cr: cr, tr: tr}, nil
+}
+
+// Select returns all series of the block sorted by their labels.
+func (q *blockQuerier) Select(*storage.SelectParams, ...*labels.Matcher) (storage.SeriesSet, storage.Warnings, error) {
+	// The postings of the empty label hold all series.
+	p, err := q.ir.Postings("", "")
+	if err != nil {
+		return nil, nil, err
+	}
+	return &blockSeriesSet{q: q, p: q.ir.SortedPostings(p)}, nil, nil
+}
+
+func (q *blockQuerier) LabelValues(name string) ([]string, error) {
+	tpls, err := q.ir.LabelValues(name)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]string, 0, tpls.Len())
+	for i := 0; i < tpls.Len(); i++ {
+		vals, err := tpls.At(i)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, vals[0])
+	}
+	return res, nil
+}
+
+func (q *blockQuerier) LabelNames() ([]string, error) {
+	tpls, err := q.ir.LabelIndices()
+	if err != nil {
+		return nil, err
+	}
+	var res []string
+	for _, tpl := range tpls {
+		if len(tpl) == 1 {
+			res = append(res, tpl[0])
+		}
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+func (q *blockQuerier) Close() error {
+	q.tr.Close()
+	q.cr.Close()
+	return q.ir.Close()
+}
+
+type blockSeriesSet struct {
+	q   *blockQuerier
+	p   tsdb.Postings
+	cur *blockSeries
+	err error
+}
+
+func (s *blockSeriesSet) Next() bool {
+	for s.p.Next() {
+		ref := s.p.At()
+
+		var (
+			lset tsdbLabels.Labels
+			chks []tsdb.ChunkMeta
+		)
+		if err := s.q.ir.Series(ref, &lset, &chks); err != nil {
+			s.err = errors.Wrapf(err, "read series %d", ref)
+			return false
+		}
+		for i := range chks {
+			c, err := s.q.cr.Chunk(chks[i].Ref)
+			if err != nil {
+				s.err = errors.Wrapf(err, "read chunk %d", chks[i].Ref)
+				return false
+			}
+			chks[i].Chunk = c
+		}
+		s.cur = &blockSeries{lset: toLabels(lset), chks: chks, deleted: s.q.tr.Get(ref)}
+		return true
+	}
+	s.err = s.p.Err()
+	return false
+}
+
+func (s *blockSeriesSet) At() storage.Series { return s.cur }
+func (s *blockSeriesSet) Err() error         { return s.err }
+
+type blockSeries struct {
+	lset    labels.Labels
+	chks    []tsdb.ChunkMeta
+	deleted tsdb.Intervals
+}
+
+func (s *blockSeries) Labels() labels.Labels { return s.lset }
+func (s *blockSeries) Iterator() storage.SeriesIterator {
+	return &blockSeriesIterator{chks: s.chks, deleted: s.deleted, i: -1}
+}
+
+// blockSeriesIterator iterates over the samples of the chunks of a series
+// that are not deleted.
+type blockSeriesIterator struct {
+	chks    []tsdb.ChunkMeta
+	deleted tsdb.Intervals
+
+	i   int
+	cur chunks.Iterator
+}
+
+func (it *blockSeriesIterator) Seek(t int64) bool {
+	if it.cur == nil && !it.Next() {
+		return false
+	}
+	for {
+		if ts, _ := it.At(); ts >= t {
+			return true
+		}
+		if !it.Next() {
+			return false
+		}
+	}
+}
+
+func (it *blockSeriesIterator) At() (int64, float64) { return it.cur.At() }
+
+func (it *blockSeriesIterator) Next() bool {
+	for {
+		if it.cur != nil && it.cur.Next() {
+			if t, _ := it.cur.At(); isDeleted(it.deleted, t) {
+				continue
+			}
+			return true
+		}
+		if it.cur != nil && it.cur.Err() != nil {
+			return false
+		}
+		it.i++
+		if it.i >= len(it.chks) {
+			return false
+		}
+		it.cur = it.chks[it.i].Chunk.Iterator()
+	}
+}
+
+func (it *blockSeriesIterator) Err() error {
+	if it.cur == nil {
+		return nil
+	}
+	return it.cur.Err()
+}
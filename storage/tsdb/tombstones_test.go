@@ -0,0 +1,175 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/tsdb"
+	tsdbLabels "github.com/prometheus/tsdb/labels"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+)
+
+func TestCleanTombstones(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clean_tombstones")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	opts := &tsdb.Options{
+		WALFlushInterval: 10 * time.Second,
+		BlockRanges:      []int64{1000},
+	}
+	db, err := tsdb.Open(dir, nil, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := db.Appender()
+	for ts := int64(0); ts < 5000; ts += 100 {
+		for _, lset := range []tsdbLabels.Labels{
+			tsdbLabels.FromStrings("__name__", "a", "i", "1"),
+			tsdbLabels.FromStrings("__name__", "a", "i", "2"),
+			tsdbLabels.FromStrings("i", "3"),
+		} {
+			if _, err := app.Add(lset, ts, float64(ts)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := app.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for len(db.Blocks()) < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 3 blocks, got %d", len(db.Blocks()))
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	m, err := labels.NewMatcher(labels.MatchEqual, "i", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteSeries(db, 0, 1500, m); err != nil {
+		t.Fatal(err)
+	}
+	if err := CleanTombstones(db, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The rewritten blocks are loaded when the database is opened again.
+	db, err = tsdb.Open(dir, nil, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, b := range db.Blocks() {
+		if n := b.Meta().Stats.NumTombstones; n != 0 {
+			t.Fatalf("block %s has %d tombstones after cleaning", b.Meta().ULID, n)
+		}
+	}
+
+	q, err := db.Querier(0, 5000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+
+	set := q.Select(tsdbLabels.NewEqualMatcher("i", "1"))
+	if !set.Next() {
+		t.Fatal("expected series i=1")
+	}
+	it := set.At().Iterator()
+	if !it.Next() {
+		t.Fatal("expected samples after the deleted range")
+	}
+	if ts, _ := it.At(); ts != 1600 {
+		t.Fatalf("expected first sample at 1600, got %d", ts)
+	}
+
+	// Series without a metric name are kept as well.
+	set = q.Select(tsdbLabels.NewEqualMatcher("i", "3"))
+	if !set.Next() {
+		t.Fatal("expected series i=3")
+	}
+	it = set.At().Iterator()
+	if !it.Next() {
+		t.Fatal("expected samples of series i=3")
+	}
+	if ts, _ := it.At(); ts != 0 {
+		t.Fatalf("expected first sample at 0, got %d", ts)
+	}
+}
+
+func TestRemoveReplacedBlocks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "remove_replaced_blocks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for name, deletable := range map[string]string{
+		"replaced":          "replacement",
+		"replacement":       "",
+		"interrupted":       "missing",
+		"emptied":           "",
+		"untouched":         "",
+		"merge.rewrite.tmp": "",
+	} {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if deletable == "" && name != "emptied" {
+			continue
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, name, deletableFile), []byte(deletable), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := removeReplacedBlocks(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, exists := range map[string]bool{
+		// The replacement was moved in, so the original block is removed.
+		"replaced":    false,
+		"replacement": true,
+		// The replacement was not moved in, so the original block is kept.
+		"interrupted": true,
+		// Blocks without samples left are removed without a replacement.
+		"emptied":           false,
+		"untouched":         true,
+		"merge.rewrite.tmp": false,
+	} {
+		if _, err := os.Stat(filepath.Join(dir, name)); os.IsNotExist(err) == exists {
+			t.Fatalf("expected block %s to exist: %v, got %v", name, exists, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "interrupted", deletableFile)); !os.IsNotExist(err) {
+		t.Fatalf("expected deletable marker of kept block to be removed, got %v", err)
+	}
+}
@@ -15,14 +15,19 @@ package tsdb
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"sort"
 	"sync"
 	"time"
 	"unsafe"
 
+	"github.com/alecthomas/units"
 	"github.com/go-kit/kit/log"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/exemplar"
 	"github.com/prometheus/prometheus/pkg/labels"
 	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/tsdb"
@@ -35,16 +40,133 @@ var ErrNotReady = errors.New("TSDB not ready")
 // ReadyStorage implements the Storage interface while allowing to set the actual
 // storage at a later point in time.
 type ReadyStorage struct {
-	mtx sync.RWMutex
-	a   *adapter
+	mtx    sync.RWMutex
+	a      *adapter
+	hooks  []storage.CommitHook
+	replay *walReplay
+	// The start and duration of opening the database.
+	replayStart    time.Time
+	replayDuration time.Duration
+	events         healthEvents
 }
 
-// Set the storage.
-func (s *ReadyStorage) Set(db *tsdb.DB, startTimeMargin int64) {
+// Set the storage. The exemplars added to its appenders are stored in es,
+// which may be nil to drop them. Out of order samples are accepted into ooo,
+// which may be nil to reject them.
+func (s *ReadyStorage) Set(db *tsdb.DB, startTimeMargin int64, es storage.ExemplarStorage, ooo *OutOfOrderHead) {
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
 
-	s.a = &adapter{db: db, startTimeMargin: startTimeMargin}
+	s.a = &adapter{db: db, startTimeMargin: startTimeMargin, exemplars: es, ooo: ooo, hooks: s.hooks}
+	s.replay = nil
+	if !s.replayStart.IsZero() {
+		s.replayDuration = time.Since(s.replayStart)
+	}
+}
+
+// StartWALReplay records that the database in dir is being opened, which
+// replays its write ahead log, until the storage is set.
+func (s *ReadyStorage) StartWALReplay(dir string) error {
+	s.mtx.Lock()
+	s.replayStart = time.Now()
+	s.mtx.Unlock()
+
+	r, err := newWALReplay(dir)
+	if err != nil {
+		return err
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.replay = r
+	return nil
+}
+
+// WALReplayProgress returns the progress of the WAL replay. It returns false
+// if no replay is in progress or its progress is unknown on this platform.
+func (s *ReadyStorage) WALReplayProgress() (WALReplayProgress, bool) {
+	s.mtx.RLock()
+	r := s.replay
+	s.mtx.RUnlock()
+
+	if r == nil {
+		return WALReplayProgress{}, false
+	}
+	p, err := r.progress()
+	if err != nil {
+		return WALReplayProgress{}, false
+	}
+	return p, true
+}
+
+// NotReadyReason describes why the storage is not ready yet. It returns an
+// empty string once the storage is ready.
+func (s *ReadyStorage) NotReadyReason() string {
+	s.mtx.RLock()
+	a, r := s.a, s.replay
+	s.mtx.RUnlock()
+
+	switch {
+	case a != nil:
+		return ""
+	case r == nil:
+		return "TSDB is starting"
+	}
+	p, err := r.progress()
+	if err != nil {
+		return fmt.Sprintf("replaying WAL of %d segments", p.Segments)
+	}
+	return p.String()
+}
+
+var (
+	walReplayProgressDesc = prometheus.NewDesc(
+		"prometheus_tsdb_wal_replay_progress_ratio",
+		"Fraction of the write ahead log replayed while opening the TSDB. It is 1 once the TSDB is open.",
+		nil, nil,
+	)
+	walReplaySegmentsRemainingDesc = prometheus.NewDesc(
+		"prometheus_tsdb_wal_replay_segments_remaining",
+		"Number of write ahead log segments left to replay while opening the TSDB.",
+		nil, nil,
+	)
+)
+
+// Describe implements prometheus.Collector.
+func (s *ReadyStorage) Describe(ch chan<- *prometheus.Desc) {
+	ch <- walReplayProgressDesc
+	ch <- walReplaySegmentsRemainingDesc
+}
+
+// Collect implements prometheus.Collector. The progress of the WAL replay is
+// only collected while it is known.
+func (s *ReadyStorage) Collect(ch chan<- prometheus.Metric) {
+	var ratio, remaining float64
+	if s.get() != nil {
+		ratio = 1
+	} else if p, ok := s.WALReplayProgress(); ok {
+		ratio, remaining = p.Ratio(), float64(p.SegmentsRemaining)
+	} else {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(walReplayProgressDesc, prometheus.GaugeValue, ratio)
+	ch <- prometheus.MustNewConstMetric(walReplaySegmentsRemainingDesc, prometheus.GaugeValue, remaining)
+}
+
+// AddCommitHook registers a hook that is called after every batch of samples
+// committed to the WAL of the storage.
+func (s *ReadyStorage) AddCommitHook(h storage.CommitHook) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	// Appenders keep the hooks of the adapter they were created from, so
+	// the adapter is replaced rather than modified.
+	s.hooks = append(s.hooks[:len(s.hooks):len(s.hooks)], h)
+	if s.a != nil {
+		a := *s.a
+		a.hooks = s.hooks
+		s.a = &a
+	}
 }
 
 // Get the storage.
@@ -70,6 +192,24 @@ func (s *ReadyStorage) StartTime() (int64, error) {
 	return int64(model.Latest), ErrNotReady
 }
 
+// HeadMinTime returns the minimum timestamp of the data in the head block.
+// It is math.MinInt64 as long as no data was appended to the head.
+func (s *ReadyStorage) HeadMinTime() (int64, error) {
+	if x := s.get(); x != nil {
+		return x.db.Head().MinTime(), nil
+	}
+	return int64(model.Latest), ErrNotReady
+}
+
+// HeadMaxTime returns the maximum timestamp of the data in the head block.
+// It is math.MinInt64 as long as no data was appended to the head.
+func (s *ReadyStorage) HeadMaxTime() (int64, error) {
+	if x := s.get(); x != nil {
+		return x.db.Head().MaxTime(), nil
+	}
+	return int64(model.Latest), ErrNotReady
+}
+
 // Querier implements the Storage interface.
 func (s *ReadyStorage) Querier(ctx context.Context, mint, maxt int64) (storage.Querier, error) {
 	if x := s.get(); x != nil {
@@ -88,7 +228,7 @@ func (s *ReadyStorage) Appender() (storage.Appender, error) {
 
 // Close implements the Storage interface.
 func (s *ReadyStorage) Close() error {
-	if x := s.Get(); x != nil {
+	if x := s.get(); x != nil {
 		return x.Close()
 	}
 	return nil
@@ -103,6 +243,9 @@ func Adapter(db *tsdb.DB, startTimeMargin int64) storage.Storage {
 type adapter struct {
 	db              *tsdb.DB
 	startTimeMargin int64
+	exemplars       storage.ExemplarStorage
+	ooo             *OutOfOrderHead
+	hooks           []storage.CommitHook
 }
 
 // Options of the DB storage.
@@ -120,8 +263,16 @@ type Options struct {
 	// Duration for how long to retain data.
 	Retention model.Duration
 
+	// Maximum number of bytes in blocks to be retained. The oldest blocks
+	// are deleted by SizeRetention first. Zero disables the limit.
+	MaxBytes units.Base2Bytes
+
 	// Disable creation and consideration of lockfile.
 	NoLockfile bool
+
+	// How far behind the newest sample of the database out of order
+	// samples are still accepted. Zero disables out of order samples.
+	OutOfOrderTimeWindow model.Duration
 }
 
 // Open returns a new storage backed by a TSDB database that is configured for Prometheus.
@@ -140,6 +291,9 @@ func Open(path string, l log.Logger, r prometheus.Registerer, opts *Options) (*t
 		}
 	}
 
+	if err := removeReplacedBlocks(path); err != nil {
+		return nil, errors.Wrap(err, "remove replaced blocks")
+	}
 	db, err := tsdb.Open(path, l, r, &tsdb.Options{
 		WALFlushInterval:  10 * time.Second,
 		RetentionDuration: uint64(time.Duration(opts.Retention).Seconds() * 1000),
@@ -152,12 +306,15 @@ func Open(path string, l log.Logger, r prometheus.Registerer, opts *Options) (*t
 	return db, nil
 }
 
-// StartTime implements the Storage interface.
+// StartTime implements the Storage interface. It is the minimum timestamp of
+// the persisted blocks, or of the head block if there are none yet.
 func (a adapter) StartTime() (int64, error) {
 	var startTime int64
 
-	if len(a.db.Blocks()) > 0 {
-		startTime = a.db.Blocks()[0].Meta().MinTime
+	if blocks := a.db.Blocks(); len(blocks) > 0 {
+		startTime = blocks[0].Meta().MinTime
+	} else if mint, ok := headMinTimestamp(a.db.Head()); ok {
+		startTime = mint
 	} else {
 		startTime = int64(time.Now().Unix() * 1000)
 	}
@@ -166,41 +323,133 @@ func (a adapter) StartTime() (int64, error) {
 	return startTime + a.startTimeMargin, nil
 }
 
+// headMinTimestamp returns the timestamp of the oldest sample in the head
+// block, and false if it has none. Unlike the minimum time of the head, it is
+// not aligned to the chunk range.
+func headMinTimestamp(h *tsdb.Head) (int64, bool) {
+	ir, err := h.Index()
+	if err != nil {
+		return 0, false
+	}
+	defer ir.Close()
+
+	p, err := ir.Postings("", "")
+	if err != nil {
+		return 0, false
+	}
+	var (
+		mint  int64 = math.MaxInt64
+		found bool
+		lset  tsdbLabels.Labels
+		chks  []tsdb.ChunkMeta
+	)
+	for p.Next() {
+		if err := ir.Series(p.At(), &lset, &chks); err != nil {
+			continue
+		}
+		// The chunks of a series are ordered by time.
+		if len(chks) > 0 && chks[0].MinTime < mint {
+			mint, found = chks[0].MinTime, true
+		}
+	}
+	return mint, found
+}
+
 func (a adapter) Querier(_ context.Context, mint, maxt int64) (storage.Querier, error) {
 	q, err := a.db.Querier(mint, maxt)
 	if err != nil {
 		return nil, err
 	}
-	return querier{q: q}, nil
+	var readers []tsdb.BlockReader
+	for _, b := range a.db.Blocks() {
+		if m := b.Meta(); m.MaxTime >= mint && m.MinTime <= maxt {
+			readers = append(readers, b)
+		}
+	}
+	if h := a.db.Head(); h.MaxTime() >= mint && h.MinTime() <= maxt {
+		readers = append(readers, h)
+	}
+	if a.ooo == nil {
+		return querier{q: q, readers: readers}, nil
+	}
+	return storage.NewMergeQuerier(querier{q: q, readers: readers}, []storage.Querier{a.ooo.querier(mint, maxt)}), nil
 }
 
 // Appender returns a new appender against the storage.
 func (a adapter) Appender() (storage.Appender, error) {
-	return appender{a: a.db.Appender()}, nil
+	return &appender{a: a.db.Appender(), exemplars: a.exemplars, ooo: a.ooo, hooks: a.hooks}, nil
 }
 
 // Close closes the storage and all its underlying resources.
 func (a adapter) Close() error {
+	if a.ooo != nil {
+		if err := a.ooo.Close(); err != nil {
+			a.db.Close()
+			return err
+		}
+	}
 	return a.db.Close()
 }
 
 type querier struct {
 	q tsdb.Querier
+
+	// The blocks and head overlapping the querier's time range.
+	readers []tsdb.BlockReader
 }
 
-func (q querier) Select(oms ...*labels.Matcher) storage.SeriesSet {
+func (q querier) Select(_ *storage.SelectParams, oms ...*labels.Matcher) (storage.SeriesSet, storage.Warnings, error) {
 	ms := make([]tsdbLabels.Matcher, 0, len(oms))
 
 	for _, om := range oms {
 		ms = append(ms, convertMatcher(om))
 	}
 
-	return seriesSet{set: q.q.Select(ms...)}
+	return seriesSet{set: q.q.Select(ms...)}, nil, nil
 }
 
 func (q querier) LabelValues(name string) ([]string, error) { return q.q.LabelValues(name) }
 func (q querier) Close() error                              { return q.q.Close() }
 
+// LabelNames returns the label names of the blocks and head overlapping
+// the querier's time range.
+func (q querier) LabelNames() ([]string, error) {
+	var irs []tsdb.IndexReader
+	defer func() {
+		for _, ir := range irs {
+			ir.Close()
+		}
+	}()
+
+	for _, r := range q.readers {
+		ir, err := r.Index()
+		if err != nil {
+			return nil, err
+		}
+		irs = append(irs, ir)
+	}
+
+	set := map[string]struct{}{}
+	for _, ir := range irs {
+		tpls, err := ir.LabelIndices()
+		if err != nil {
+			return nil, err
+		}
+		for _, tpl := range tpls {
+			// Indices over multiple label names hold no additional names.
+			if len(tpl) == 1 {
+				set[tpl[0]] = struct{}{}
+			}
+		}
+	}
+	names := make([]string, 0, len(set))
+	for n := range set {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
 type seriesSet struct {
 	set tsdb.SeriesSet
 }
@@ -218,10 +467,35 @@ func (s series) Iterator() storage.SeriesIterator { return storage.SeriesIterato
 
 type appender struct {
 	a tsdb.Appender
+
+	ooo *OutOfOrderHead
+	// The out of order samples added since the last commit or rollback.
+	oooPending []storage.Sample
+
+	exemplars storage.ExemplarStorage
+	// The exemplars added since the last commit or rollback.
+	pending []pendingExemplar
+
+	hooks []storage.CommitHook
+	// Whether samples were added to the database since the last commit or
+	// rollback. Out of order samples are not written to its WAL.
+	added bool
+}
+
+type pendingExemplar struct {
+	lset labels.Labels
+	e    exemplar.Exemplar
 }
 
-func (a appender) Add(lset labels.Labels, t int64, v float64) (uint64, error) {
+func (a *appender) Add(lset labels.Labels, t int64, v float64) (uint64, error) {
 	ref, err := a.a.Add(toTSDBLabels(lset), t, v)
+	if err == nil {
+		a.added = true
+	} else if errors.Cause(err) == tsdb.ErrOutOfOrderSample && a.ooo != nil && a.ooo.accepts(t, v) {
+		// The reference still refers to the series in the database, so
+		// later samples are tried in order first.
+		err = a.addOutOfOrder(lset, t, v)
+	}
 
 	switch errors.Cause(err) {
 	case tsdb.ErrNotFound:
@@ -236,8 +510,13 @@ func (a appender) Add(lset labels.Labels, t int64, v float64) (uint64, error) {
 	return ref, err
 }
 
-func (a appender) AddFast(_ labels.Labels, ref uint64, t int64, v float64) error {
+func (a *appender) AddFast(lset labels.Labels, ref uint64, t int64, v float64) error {
 	err := a.a.AddFast(ref, t, v)
+	if err == nil {
+		a.added = true
+	} else if errors.Cause(err) == tsdb.ErrOutOfOrderSample && a.ooo != nil && a.ooo.accepts(t, v) {
+		err = a.addOutOfOrder(lset, t, v)
+	}
 
 	switch errors.Cause(err) {
 	case tsdb.ErrNotFound:
@@ -252,8 +531,70 @@ func (a appender) AddFast(_ labels.Labels, ref uint64, t int64, v float64) error
 	return err
 }
 
-func (a appender) Commit() error   { return a.a.Commit() }
-func (a appender) Rollback() error { return a.a.Rollback() }
+// AddBatch implements storage.Appender.
+func (a *appender) AddBatch(samples []storage.Sample) error {
+	return storage.AppendBatch(a, samples)
+}
+
+// addOutOfOrder adds a sample that is out of order for the database. It is
+// committed to the out of order head.
+func (a *appender) addOutOfOrder(lset labels.Labels, t int64, v float64) error {
+	if err := a.ooo.check(lset, t, v); err != nil {
+		return err
+	}
+	a.oooPending = append(a.oooPending, storage.Sample{Labels: lset, T: t, V: v})
+	return nil
+}
+
+// AddExemplar implements storage.Appender. The exemplars are stored once
+// the samples were committed.
+func (a *appender) AddExemplar(lset labels.Labels, e exemplar.Exemplar) error {
+	if a.exemplars != nil {
+		a.pending = append(a.pending, pendingExemplar{lset: lset, e: e})
+	}
+	return nil
+}
+
+func (a *appender) Commit() error {
+	defer func() {
+		a.pending = a.pending[:0]
+		a.added = false
+	}()
+
+	if err := a.a.Commit(); err != nil {
+		a.rollbackOutOfOrder()
+		return err
+	}
+	if len(a.oooPending) > 0 {
+		pending := a.oooPending
+		a.rollbackOutOfOrder()
+		if err := a.ooo.commit(pending); err != nil {
+			return err
+		}
+	}
+	// Exemplars that are out of order or duplicates are counted by the
+	// exemplar storage and must not fail the commit of the samples.
+	for _, p := range a.pending {
+		a.exemplars.AddExemplar(p.lset, p.e)
+	}
+	if a.added {
+		for _, h := range a.hooks {
+			h()
+		}
+	}
+	return nil
+}
+
+func (a *appender) Rollback() error {
+	a.pending = a.pending[:0]
+	a.added = false
+	a.rollbackOutOfOrder()
+	return a.a.Rollback()
+}
+
+func (a *appender) rollbackOutOfOrder() {
+	a.oooPending = nil
+}
 
 func convertMatcher(m *labels.Matcher) tsdbLabels.Matcher {
 	switch m.Type {
@@ -0,0 +1,254 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/prometheus/tsdb"
+	"github.com/prometheus/tsdb/labels"
+
+	"github.com/prometheus/prometheus/pkg/exemplar"
+	promLabels "github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/storage"
+)
+
+func TestReadyStorageTimes(t *testing.T) {
+	var s ReadyStorage
+	if _, err := s.StartTime(); err != ErrNotReady {
+		t.Fatalf("expected ErrNotReady, got %v", err)
+	}
+	if _, err := s.HeadMinTime(); err != ErrNotReady {
+		t.Fatalf("expected ErrNotReady, got %v", err)
+	}
+
+	dir, err := ioutil.TempDir("", "ready_storage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := tsdb.Open(dir, nil, nil, &tsdb.Options{
+		WALFlushInterval: 10 * time.Second,
+		BlockRanges:      []int64{1000},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	s.Set(db, 0, nil, nil)
+
+	// Without any data the start time is the current time.
+	before := time.Now().Unix() * 1000
+	st, err := s.StartTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st < before {
+		t.Fatalf("expected start time of empty storage to be at least %d, got %d", before, st)
+	}
+	mint, err := s.HeadMinTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mint != math.MinInt64 {
+		t.Fatalf("expected empty head to have minimum time %d, got %d", int64(math.MinInt64), mint)
+	}
+
+	app := db.Appender()
+	if _, err := app.Add(labels.FromStrings("a", "b"), 100, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.Add(labels.FromStrings("a", "b"), 500, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The minimum time of the head is aligned to the block range, the start
+	// time is the timestamp of the oldest sample.
+	for _, c := range []struct {
+		name string
+		f    func() (int64, error)
+		exp  int64
+	}{
+		{name: "start time", f: s.StartTime, exp: 100},
+		{name: "head minimum time", f: s.HeadMinTime, exp: 0},
+		{name: "head maximum time", f: s.HeadMaxTime, exp: 500},
+	} {
+		v, err := c.f()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != c.exp {
+			t.Fatalf("expected %s %d, got %d", c.name, c.exp, v)
+		}
+	}
+}
+
+func TestAppenderExemplars(t *testing.T) {
+	dir, err := ioutil.TempDir("", "appender_exemplars")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := tsdb.Open(dir, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var (
+		s    ReadyStorage
+		es   = storage.NewCircularExemplarStorage(10, nil)
+		lset = promLabels.FromStrings("__name__", "a")
+	)
+	s.Set(db, 0, es, nil)
+
+	m, err := promLabels.NewMatcher(promLabels.MatchEqual, "__name__", "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Exemplars are only stored once their samples are committed.
+	for i, commit := range []bool{false, true} {
+		app, err := s.Appender()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ts := int64(1000 * (i + 1))
+		if _, err := app.Add(lset, ts, 1); err != nil {
+			t.Fatal(err)
+		}
+		e := exemplar.Exemplar{Labels: promLabels.FromStrings("trace_id", "x"), Value: 1, Ts: ts}
+		if err := app.AddExemplar(lset, e); err != nil {
+			t.Fatal(err)
+		}
+		if res, err := es.SelectExemplars(0, 10000, []*promLabels.Matcher{m}); err != nil || len(res) != 0 {
+			t.Fatalf("expected no exemplars before commit, got %v, %v", res, err)
+		}
+		if !commit {
+			if err := app.Rollback(); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := app.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		res, err := es.SelectExemplars(0, 10000, []*promLabels.Matcher{m})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res) != 1 || len(res[0].Exemplars) != 1 || !res[0].Exemplars[0].Equals(e) {
+			t.Fatalf("expected committed exemplar %v, got %v", e, res)
+		}
+	}
+}
+
+func TestAppenderCommitHooks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "appender_commit_hooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := tsdb.Open(dir, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var (
+		s         ReadyStorage
+		committed int
+	)
+	s.AddCommitHook(func() {
+		committed++
+	})
+	s.Set(db, 0, nil, nil)
+
+	batch := []storage.Sample{
+		{Labels: promLabels.FromStrings("__name__", "a"), T: 1000, V: 1},
+		{Labels: promLabels.FromStrings("__name__", "b"), T: 1000, V: 2},
+	}
+	// Hooks are only called for committed batches.
+	for _, commit := range []bool{false, true} {
+		app, err := s.Appender()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := app.AddBatch(batch); err != nil {
+			t.Fatal(err)
+		}
+		if !commit {
+			if err := app.Rollback(); err != nil {
+				t.Fatal(err)
+			}
+			if committed != 0 {
+				t.Fatalf("expected no committed batches after rollback, got %d", committed)
+			}
+			continue
+		}
+		if err := app.Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if committed != 1 {
+		t.Fatalf("expected one committed batch, got %d", committed)
+	}
+	for _, s := range batch {
+		if s.Ref == 0 {
+			t.Fatalf("expected reference of added sample %v", s)
+		}
+	}
+
+	// Samples of later batches are added by their reference.
+	batch[0].T, batch[1].T = 2000, 2000
+	app, err := s.Appender()
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch = append(batch, storage.Sample{Labels: promLabels.FromStrings("__name__", "a"), Ref: batch[0].Ref, T: 500, V: 3})
+	err = app.AddBatch(batch)
+	if berr, ok := err.(*storage.BatchError); !ok || berr.Index != 2 || berr.Err != storage.ErrOutOfOrderSample {
+		t.Fatalf("expected out of order error for the third sample, got %v", err)
+	}
+	if err := app.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if committed != 2 {
+		t.Fatalf("expected second committed batch, got %d", committed)
+	}
+
+	// Batches without added samples are not signaled.
+	app, err = s.Appender()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.AddBatch(batch[2:]); err == nil {
+		t.Fatal("expected out of order error")
+	}
+	if err := app.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if committed != 2 {
+		t.Fatalf("expected no committed batch without samples, got %d", committed)
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"net"
 	"net/url"
 	"regexp"
 	"sort"
@@ -246,6 +247,38 @@ func NewTemplateExpander(ctx context.Context, text string, name string, data int
 				t := model.TimeFromUnixNano(int64(v * 1e9)).Time().UTC()
 				return fmt.Sprint(t)
 			},
+			"humanizePercentage": func(v float64) string {
+				return fmt.Sprintf("%.4g%%", v*100)
+			},
+			"parseDuration": func(d string) (float64, error) {
+				v, err := model.ParseDuration(d)
+				if err != nil {
+					return 0, err
+				}
+				return time.Duration(v).Seconds(), nil
+			},
+			"toTime": func(v float64) (*time.Time, error) {
+				if math.IsNaN(v) || math.IsInf(v, 0) {
+					return nil, fmt.Errorf("cannot convert %v to time", v)
+				}
+				t := model.TimeFromUnixNano(int64(v * 1e9)).Time().UTC()
+				return &t, nil
+			},
+			"stripDomain": func(hostPort string) string {
+				host, port, err := net.SplitHostPort(hostPort)
+				if err != nil {
+					host = hostPort
+				}
+				// IP addresses have no domain to strip.
+				if net.ParseIP(host) != nil {
+					return hostPort
+				}
+				host = strings.SplitN(host, ".", 2)[0]
+				if port != "" {
+					return net.JoinHostPort(host, port)
+				}
+				return host
+			},
 			"pathPrefix": func() string {
 				return externalURL.Path
 			},
@@ -163,6 +163,40 @@ func TestTemplateExpansion(t *testing.T) {
 			text:   "{{ 1435065584.128 | humanizeTimestamp }}",
 			output: "2015-06-23 13:19:44.128 +0000 UTC",
 		},
+		{
+			// HumanizePercentage.
+			text:   "{{ range . }}{{ humanizePercentage . }}:{{ end }}",
+			input:  []float64{0, 0.5, 0.1234567, 1, 1.5, -0.25},
+			output: "0%:50%:12.35%:100%:150%:-25%:",
+		},
+		{
+			// ParseDuration.
+			text:   "{{ range . }}{{ parseDuration . }}:{{ end }}",
+			input:  []string{"0s", "1m", "90m", "2d", "250ms"},
+			output: "0:60:5400:172800:0.25:",
+		},
+		{
+			// ParseDuration - invalid duration.
+			text:       "{{ \"1x\" | parseDuration }}",
+			shouldFail: true,
+		},
+		{
+			// ToTime.
+			text:   "{{ (1435065584.128 | toTime).Format \"2006-01-02T15:04:05.000Z07:00\" }}",
+			output: "2015-06-23T13:19:44.128Z",
+		},
+		{
+			// ToTime - NaN.
+			text:       "{{ range . }}{{ toTime . }}{{ end }}",
+			input:      []float64{math.NaN()},
+			shouldFail: true,
+		},
+		{
+			// StripDomain.
+			text:   "{{ range . }}{{ stripDomain . }}:{{ end }}",
+			input:  []string{"foo.example.com", "foo.example.com:9090", "foo:9090", "192.168.0.1:9090", "[2001:db8::1]:9090", "localhost"},
+			output: "foo:foo:9090:foo:9090:192.168.0.1:9090:[2001:db8::1]:9090:localhost:",
+		},
 		{
 			// Title.
 			text:   "{{ \"aa bb CC\" | title }}",
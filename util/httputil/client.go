@@ -18,11 +18,14 @@ import (
 	"crypto/x509"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/mwitkow/go-conntrack"
+	"golang.org/x/net/http2"
+
 	"github.com/prometheus/prometheus/config"
 )
 
@@ -38,22 +41,37 @@ func NewClientFromConfig(cfg config.HTTPClientConfig, name string) (*http.Client
 	if err != nil {
 		return nil, err
 	}
-	// The only timeout we care about is the configured scrape timeout.
-	// It is applied on request. So we leave out any timings here.
-	var rt http.RoundTripper = &http.Transport{
-		Proxy:              http.ProxyURL(cfg.ProxyURL.URL),
-		MaxIdleConns:       20000,
-		DisableKeepAlives:  false,
-		TLSClientConfig:    tlsConfig,
-		DisableCompression: true,
-		// 5 minutes is typically above the maximum sane scrape interval. So we can
-		// use keepalive for all configurations.
-		IdleConnTimeout: 5 * time.Minute,
+	// 5 minutes is typically above the maximum sane scrape interval. So we can
+	// use keepalive for all configurations.
+	idleConnTimeout := 5 * time.Minute
+	if cfg.IdleConnTimeout > 0 {
+		idleConnTimeout = time.Duration(cfg.IdleConnTimeout)
+	}
+	// The timeout we care about most is the configured scrape timeout.
+	// It is applied on request. So we leave out any timings here unless
+	// explicitly configured.
+	transport := &http.Transport{
+		Proxy:               http.ProxyURL(cfg.ProxyURL.URL),
+		MaxIdleConns:        20000,
+		MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost,
+		DisableKeepAlives:   cfg.DisableKeepAlives,
+		TLSClientConfig:     tlsConfig,
+		DisableCompression:  true,
+		IdleConnTimeout:     idleConnTimeout,
 		DialContext: conntrack.NewDialContextFunc(
 			conntrack.DialWithTracing(),
 			conntrack.DialWithName(name),
+			conntrack.DialWithDialer(&net.Dialer{Timeout: time.Duration(cfg.DialTimeout)}),
 		),
 	}
+	// HTTP/2 is not enabled automatically for transports with a custom
+	// dialer and TLS configuration.
+	if cfg.EnableHTTP2 {
+		if err := http2.ConfigureTransport(transport); err != nil {
+			return nil, err
+		}
+	}
+	var rt http.RoundTripper = transport
 
 	// If a bearer token is provided, create a round tripper that will set the
 	// Authorization header correctly on each request.
@@ -71,7 +89,11 @@ func NewClientFromConfig(cfg config.HTTPClientConfig, name string) (*http.Client
 	}
 
 	if cfg.BasicAuth != nil {
-		rt = NewBasicAuthRoundTripper(cfg.BasicAuth.Username, string(cfg.BasicAuth.Password), rt)
+		password, err := config.ReadSecret(cfg.BasicAuth.Password, cfg.BasicAuth.PasswordFile)
+		if err != nil {
+			return nil, err
+		}
+		rt = NewBasicAuthRoundTripper(cfg.BasicAuth.Username, password, rt)
 	}
 
 	// Return a new client with the configured round tripper.
@@ -135,7 +157,19 @@ func cloneRequest(r *http.Request) *http.Request {
 
 // NewTLSConfig creates a new tls.Config from the given config.TLSConfig.
 func NewTLSConfig(cfg config.TLSConfig) (*tls.Config, error) {
-	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+		MinVersion:         uint16(cfg.MinVersion),
+	}
+
+	switch cfg.Renegotiation {
+	case config.TLSRenegotiationOnce:
+		tlsConfig.Renegotiation = tls.RenegotiateOnceAsClient
+	case config.TLSRenegotiationFreely:
+		tlsConfig.Renegotiation = tls.RenegotiateFreelyAsClient
+	default:
+		tlsConfig.Renegotiation = tls.RenegotiateNever
+	}
 
 	// If a CA cert is provided then let's read it in so we can validate the
 	// scrape target's certificate properly.
@@ -23,7 +23,9 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/util/testutil"
 )
@@ -42,6 +44,7 @@ const (
 	BearerToken            = "theanswertothegreatquestionoflifetheuniverseandeverythingisfortytwo"
 	BearerTokenFile        = "testdata/bearer.token"
 	MissingBearerTokenFile = "missing/bearer.token"
+	MissingPasswordFile    = "missing/password"
 	ExpectedBearer         = "Bearer " + BearerToken
 	ExpectedUsername       = "arthurdent"
 	ExpectedPassword       = "42"
@@ -229,6 +232,14 @@ func TestNewClientFromInvalidConfig(t *testing.T) {
 					InsecureSkipVerify: false},
 			},
 			errorMsg: fmt.Sprintf("unable to read bearer token file %s:", MissingBearerTokenFile),
+		}, {
+			clientConfig: config.HTTPClientConfig{
+				BasicAuth: &config.BasicAuth{
+					Username:     ExpectedUsername,
+					PasswordFile: MissingPasswordFile,
+				},
+			},
+			errorMsg: fmt.Sprintf("unable to read secret file %s:", MissingPasswordFile),
 		},
 	}
 
@@ -367,6 +378,94 @@ func TestTLSConfigEmpty(t *testing.T) {
 	}
 }
 
+func TestTLSConfigMinVersionRenegotiation(t *testing.T) {
+	configTLSConfig := config.TLSConfig{
+		MinVersion:    config.TLSVersion(tls.VersionTLS12),
+		Renegotiation: config.TLSRenegotiationOnce,
+	}
+
+	tlsConfig, err := NewTLSConfig(configTLSConfig)
+	if err != nil {
+		t.Fatalf("Can't create a new TLS Config from a configuration (%s).", err)
+	}
+	if tlsConfig.MinVersion != tls.VersionTLS12 {
+		t.Fatalf("Unexpected minimum TLS version %x", tlsConfig.MinVersion)
+	}
+	if tlsConfig.Renegotiation != tls.RenegotiateOnceAsClient {
+		t.Fatalf("Unexpected TLS renegotiation setting %v", tlsConfig.Renegotiation)
+	}
+}
+
+func TestNewClientFromConfigTransport(t *testing.T) {
+	cfg := config.HTTPClientConfig{
+		DisableKeepAlives: true,
+		IdleConnTimeout:   model.Duration(time.Minute),
+	}
+	client, err := NewClientFromConfig(cfg, "test")
+	if err != nil {
+		t.Fatalf("Can't create a client from this config: %+v", cfg)
+	}
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Unexpected transport %T", client.Transport)
+	}
+	if !transport.DisableKeepAlives {
+		t.Fatalf("Expected keep-alives to be disabled")
+	}
+	if transport.IdleConnTimeout != time.Minute {
+		t.Fatalf("Unexpected idle connection timeout %s", transport.IdleConnTimeout)
+	}
+
+	client, err = NewClientFromConfig(config.HTTPClientConfig{}, "test")
+	if err != nil {
+		t.Fatalf("Can't create a client from an empty config")
+	}
+	if timeout := client.Transport.(*http.Transport).IdleConnTimeout; timeout != 5*time.Minute {
+		t.Fatalf("Unexpected default idle connection timeout %s", timeout)
+	}
+}
+
+func TestNewClientFromConfigHTTP2(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Proto)
+	}))
+	server.TLS = &tls.Config{NextProtos: []string{"h2"}}
+	server.StartTLS()
+	defer server.Close()
+
+	for _, enable := range []bool{false, true} {
+		cfg := config.HTTPClientConfig{
+			TLSConfig:           config.TLSConfig{InsecureSkipVerify: true},
+			MaxIdleConnsPerHost: 50,
+			EnableHTTP2:         enable,
+		}
+		client, err := NewClientFromConfig(cfg, "test")
+		if err != nil {
+			t.Fatalf("Can't create a client from this config: %+v", cfg)
+		}
+		if n := client.Transport.(*http.Transport).MaxIdleConnsPerHost; n != 50 {
+			t.Fatalf("Unexpected maximum number of idle connections per host %d", n)
+		}
+
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := "HTTP/1.1"
+		if enable {
+			expected = "HTTP/2.0"
+		}
+		if string(b) != expected {
+			t.Fatalf("Expected request with protocol %s, got %s", expected, b)
+		}
+	}
+}
+
 func TestTLSConfigInvalidCA(t *testing.T) {
 	var invalidTLSConfig = []struct {
 		configTLSConfig config.TLSConfig
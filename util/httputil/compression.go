@@ -18,21 +18,43 @@ import (
 	"compress/zlib"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
+
+	"github.com/golang/snappy"
 )
 
 const (
 	acceptEncodingHeader  = "Accept-Encoding"
 	contentEncodingHeader = "Content-Encoding"
+	varyHeader            = "Vary"
 	gzipEncoding          = "gzip"
 	deflateEncoding       = "deflate"
+	snappyEncoding        = "snappy"
 )
 
+// compressor is a compressing writer that can be reused for further
+// responses after being reset.
+type compressor interface {
+	io.WriteCloser
+	Reset(io.Writer)
+}
+
+// compressorPools hold the compressors of all supported encodings. Setting up
+// a compressor allocates large buffers, so they are reused across requests.
+var compressorPools = map[string]*sync.Pool{
+	gzipEncoding:    {New: func() interface{} { return gzip.NewWriter(nil) }},
+	deflateEncoding: {New: func() interface{} { return zlib.NewWriter(nil) }},
+	snappyEncoding:  {New: func() interface{} { return snappy.NewBufferedWriter(nil) }},
+}
+
 // Wrapper around http.Handler which adds suitable response compression based
 // on the client's Accept-Encoding headers.
 type compressedResponseWriter struct {
 	http.ResponseWriter
-	writer io.Writer
+	encoding string
+	writer   compressor
 }
 
 // Writes HTTP response content data.
@@ -40,42 +62,55 @@ func (c *compressedResponseWriter) Write(p []byte) (int, error) {
 	return c.writer.Write(p)
 }
 
-// Closes the compressedResponseWriter and ensures to flush all data before.
+// Closes the compressedResponseWriter, flushing all data, and returns the
+// compressor to its pool.
 func (c *compressedResponseWriter) Close() {
-	if zlibWriter, ok := c.writer.(*zlib.Writer); ok {
-		zlibWriter.Flush()
-	}
-	if gzipWriter, ok := c.writer.(*gzip.Writer); ok {
-		gzipWriter.Flush()
-	}
-	if closer, ok := c.writer.(io.Closer); ok {
-		defer closer.Close()
+	c.writer.Close()
+	c.writer.Reset(nil)
+	compressorPools[c.encoding].Put(c.writer)
+}
+
+// Constructs a new compressedResponseWriter for the given encoding.
+func newCompressedResponseWriter(writer http.ResponseWriter, encoding string) *compressedResponseWriter {
+	writer.Header().Set(contentEncodingHeader, encoding)
+
+	comp := compressorPools[encoding].Get().(compressor)
+	comp.Reset(writer)
+
+	return &compressedResponseWriter{
+		ResponseWriter: writer,
+		encoding:       encoding,
+		writer:         comp,
 	}
 }
 
-// Constructs a new compressedResponseWriter based on client request headers.
-func newCompressedResponseWriter(writer http.ResponseWriter, req *http.Request) *compressedResponseWriter {
-	encodings := strings.Split(req.Header.Get(acceptEncodingHeader), ",")
-	for _, encoding := range encodings {
-		switch strings.TrimSpace(encoding) {
-		case gzipEncoding:
-			writer.Header().Set(contentEncodingHeader, gzipEncoding)
-			return &compressedResponseWriter{
-				ResponseWriter: writer,
-				writer:         gzip.NewWriter(writer),
-			}
-		case deflateEncoding:
-			writer.Header().Set(contentEncodingHeader, deflateEncoding)
-			return &compressedResponseWriter{
-				ResponseWriter: writer,
-				writer:         zlib.NewWriter(writer),
-			}
+// negotiateEncoding returns the first supported encoding in the given
+// Accept-Encoding header that is not explicitly rejected with a quality of 0.
+// It returns an empty string if the response must not be compressed.
+func negotiateEncoding(accept string) string {
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		encoding := strings.ToLower(strings.TrimSpace(params[0]))
+		if _, ok := compressorPools[encoding]; !ok {
+			continue
+		}
+		if !rejectsEncoding(params[1:]) {
+			return encoding
 		}
 	}
-	return &compressedResponseWriter{
-		ResponseWriter: writer,
-		writer:         writer,
+	return ""
+}
+
+func rejectsEncoding(params []string) bool {
+	for _, p := range params {
+		p = strings.TrimSpace(p)
+		if !strings.HasPrefix(p, "q=") {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimPrefix(p, "q="), 64)
+		return err != nil || q == 0
 	}
+	return false
 }
 
 // CompressionHandler is a wrapper around http.Handler which adds suitable
@@ -86,7 +121,24 @@ type CompressionHandler struct {
 
 // ServeHTTP adds compression to the original http.Handler's ServeHTTP() method.
 func (c CompressionHandler) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
-	compWriter := newCompressedResponseWriter(writer, req)
-	c.Handler.ServeHTTP(compWriter, req)
-	compWriter.Close()
+	writer.Header().Add(varyHeader, acceptEncodingHeader)
+
+	encoding := negotiateEncoding(req.Header.Get(acceptEncodingHeader))
+	if encoding == "" {
+		c.Handler.ServeHTTP(writer, req)
+		return
+	}
+	compWriter := newCompressedResponseWriter(writer, encoding)
+	defer compWriter.Close()
+
+	// The wrapped handler must not compress the response a second time.
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		r.Header[k] = v
+	}
+	r.Header.Del(acceptEncodingHeader)
+
+	c.Handler.ServeHTTP(compWriter, r)
 }
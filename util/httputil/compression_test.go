@@ -0,0 +1,124 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httputil
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/snappy"
+
+	"github.com/prometheus/prometheus/util/testutil"
+)
+
+var compressionTestBody = strings.Repeat("metric_name{label=\"value\"} 1\n", 1000)
+
+func newCompressionTestHandler() CompressionHandler {
+	return CompressionHandler{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Compressed responses must not be compressed again.
+			if _, ok := w.(*compressedResponseWriter); ok && r.Header.Get(acceptEncodingHeader) != "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			io.WriteString(w, compressionTestBody)
+		}),
+	}
+}
+
+func TestCompressionHandler(t *testing.T) {
+	h := newCompressionTestHandler()
+
+	for _, tc := range []struct {
+		accept   string
+		encoding string
+		reader   func(io.Reader) (io.Reader, error)
+	}{
+		{
+			accept: "",
+		}, {
+			accept: "br, identity",
+		}, {
+			accept:   "gzip",
+			encoding: gzipEncoding,
+			reader:   func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		}, {
+			accept:   "deflate, gzip",
+			encoding: deflateEncoding,
+			reader:   func(r io.Reader) (io.Reader, error) { return zlib.NewReader(r) },
+		}, {
+			accept:   "deflate;q=0, gzip;q=0.5",
+			encoding: gzipEncoding,
+			reader:   func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		}, {
+			accept:   "snappy",
+			encoding: snappyEncoding,
+			reader:   func(r io.Reader) (io.Reader, error) { return snappy.NewReader(r), nil },
+		},
+	} {
+		// Run each case twice to exercise pooled compressors.
+		for i := 0; i < 2; i++ {
+			req, err := http.NewRequest("GET", "/federate", nil)
+			testutil.Ok(t, err)
+			if tc.accept != "" {
+				req.Header.Set(acceptEncodingHeader, tc.accept)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			testutil.Equals(t, http.StatusOK, w.Code)
+			testutil.Equals(t, tc.encoding, w.Header().Get(contentEncodingHeader))
+			testutil.Equals(t, acceptEncodingHeader, w.Header().Get(varyHeader))
+
+			var body io.Reader = w.Body
+			if tc.reader != nil {
+				body, err = tc.reader(w.Body)
+				testutil.Ok(t, err)
+			}
+			b, err := ioutil.ReadAll(body)
+			testutil.Ok(t, err)
+			testutil.Equals(t, compressionTestBody, string(b))
+		}
+	}
+}
+
+func BenchmarkCompressionHandler(b *testing.B) {
+	h := newCompressionTestHandler()
+
+	for _, encoding := range []string{"", gzipEncoding, deflateEncoding, snappyEncoding} {
+		b.Run("encoding="+encoding, func(b *testing.B) {
+			req, err := http.NewRequest("GET", "/federate", nil)
+			if err != nil {
+				b.Fatal(err)
+			}
+			req.Header.Set(acceptEncodingHeader, encoding)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				w := httptest.NewRecorder()
+				w.Body = bytes.NewBuffer(make([]byte, 0, len(compressionTestBody)))
+				h.ServeHTTP(w, req)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httputil
+
+import (
+	"net/http"
+	"regexp"
+)
+
+// matchAllOrigins is the anchored form of the default origin pattern ".*".
+const matchAllOrigins = "^(?:.*)$"
+
+var corsHeaders = map[string]string{
+	"Access-Control-Allow-Headers":  "Accept, Authorization, Content-Type, Origin",
+	"Access-Control-Allow-Methods":  "GET, OPTIONS",
+	"Access-Control-Expose-Headers": "Date",
+}
+
+// SetCORS enables cross-site script calls from origins matching the given
+// regular expression. Requests without an Origin header are left untouched,
+// as are all requests if o is nil.
+func SetCORS(w http.ResponseWriter, o *regexp.Regexp, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	if origin == "" || o == nil {
+		return
+	}
+
+	for k, v := range corsHeaders {
+		w.Header().Set(k, v)
+	}
+
+	if o.String() == matchAllOrigins {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+	// The response differs by origin, so caches must not share it.
+	w.Header().Add("Vary", "Origin")
+	if o.MatchString(origin) {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+	}
+}
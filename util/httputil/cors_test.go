@@ -0,0 +1,52 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/prometheus/prometheus/util/testutil"
+)
+
+func TestSetCORS(t *testing.T) {
+	for _, tc := range []struct {
+		pattern, origin string
+		allowOrigin     string
+	}{
+		{pattern: ".*", origin: "", allowOrigin: ""},
+		{pattern: ".*", origin: "https://grafana.example.com", allowOrigin: "*"},
+		{pattern: `https://(grafana|dashboards)\.example\.com`, origin: "https://grafana.example.com", allowOrigin: "https://grafana.example.com"},
+		// Patterns are anchored.
+		{pattern: `https://grafana\.example\.com`, origin: "https://grafana.example.com.evil.org", allowOrigin: ""},
+		{pattern: `https://grafana\.example\.com`, origin: "https://evil.org", allowOrigin: ""},
+	} {
+		o := regexp.MustCompile("^(?:" + tc.pattern + ")$")
+
+		req, err := http.NewRequest("GET", "/api/v1/query", nil)
+		testutil.Ok(t, err)
+		if tc.origin != "" {
+			req.Header.Set("Origin", tc.origin)
+		}
+		w := httptest.NewRecorder()
+		SetCORS(w, o, req)
+
+		testutil.Equals(t, tc.allowOrigin, w.Header().Get("Access-Control-Allow-Origin"))
+		if tc.origin != "" {
+			testutil.Equals(t, "GET, OPTIONS", w.Header().Get("Access-Control-Allow-Methods"))
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httputil
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	requestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "prometheus_http_request_duration_seconds",
+			Help:    "Histogram of latencies for HTTP requests.",
+			Buckets: []float64{.1, .2, .4, 1, 3, 8, 20, 60, 120},
+		},
+		[]string{"handler"},
+	)
+	responseSize = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "prometheus_http_response_size_bytes",
+			Help:    "Histogram of response size for HTTP requests.",
+			Buckets: prometheus.ExponentialBuckets(100, 10, 8),
+		},
+		[]string{"handler"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(requestDuration)
+	prometheus.MustRegister(responseSize)
+}
+
+// InstrumentHandler wraps the given handler to observe the duration and
+// response size of its requests. The handler name should be a normalized
+// route, such as "/api/v1/label/:name/values", to keep cardinality bounded.
+func InstrumentHandler(handlerName string, handler http.Handler) http.HandlerFunc {
+	var (
+		duration = requestDuration.WithLabelValues(handlerName)
+		size     = responseSize.WithLabelValues(handlerName)
+	)
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &sizeResponseWriter{ResponseWriter: w}
+
+		handler.ServeHTTP(sw, r)
+
+		duration.Observe(time.Since(start).Seconds())
+		size.Observe(float64(sw.size))
+	}
+}
+
+// sizeResponseWriter counts the bytes written to the wrapped ResponseWriter.
+type sizeResponseWriter struct {
+	http.ResponseWriter
+	size int
+}
+
+// Write implements http.ResponseWriter.
+func (w *sizeResponseWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+	return n, err
+}
+
+// Flush implements http.Flusher if the wrapped ResponseWriter supports it.
+func (w *sizeResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/prometheus/prometheus/util/testutil"
+)
+
+func TestInstrumentHandler(t *testing.T) {
+	h := InstrumentHandler("/test/:name", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+		w.Write([]byte("world"))
+	}))
+
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequest("GET", "/test/foo", nil)
+		testutil.Ok(t, err)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		testutil.Equals(t, "helloworld", w.Body.String())
+	}
+
+	var m dto.Metric
+	testutil.Ok(t, requestDuration.WithLabelValues("/test/:name").Write(&m))
+	testutil.Equals(t, uint64(2), m.GetHistogram().GetSampleCount())
+
+	m.Reset()
+	testutil.Ok(t, responseSize.WithLabelValues("/test/:name").Write(&m))
+	testutil.Equals(t, uint64(2), m.GetHistogram().GetSampleCount())
+	testutil.Equals(t, float64(20), m.GetHistogram().GetSampleSum())
+}
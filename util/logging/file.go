@@ -0,0 +1,52 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logging provides the loggers of the server, writing to standard
+// error or files.
+package logging
+
+import (
+	"os"
+
+	"github.com/go-kit/kit/log"
+)
+
+// JSONFileLogger appends JSON log lines to a file.
+type JSONFileLogger struct {
+	logger log.Logger
+	file   *os.File
+}
+
+// NewJSONFileLogger returns a logger appending to the file with the given
+// name, creating it if necessary.
+func NewJSONFileLogger(filename string) (*JSONFileLogger, error) {
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return nil, err
+	}
+	l := log.NewJSONLogger(log.NewSyncWriter(f))
+	return &JSONFileLogger{
+		logger: log.With(l, "ts", log.DefaultTimestampUTC),
+		file:   f,
+	}, nil
+}
+
+// Log implements log.Logger.
+func (l *JSONFileLogger) Log(keyvals ...interface{}) error {
+	return l.logger.Log(keyvals...)
+}
+
+// Close closes the underlying file.
+func (l *JSONFileLogger) Close() error {
+	return l.file.Close()
+}
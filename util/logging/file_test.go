@@ -0,0 +1,49 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/prometheus/util/testutil"
+)
+
+func TestJSONFileLogger(t *testing.T) {
+	f, err := ioutil.TempFile("", "logging")
+	testutil.Ok(t, err)
+	testutil.Ok(t, f.Close())
+	defer os.Remove(f.Name())
+
+	l, err := NewJSONFileLogger(f.Name())
+	testutil.Ok(t, err)
+	testutil.Ok(t, l.Log("query", "up", "duration", 0.5))
+	testutil.Ok(t, l.Log("query", "down"))
+	testutil.Ok(t, l.Close())
+
+	b, err := ioutil.ReadFile(f.Name())
+	testutil.Ok(t, err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	testutil.Equals(t, 2, len(lines))
+
+	var entry map[string]interface{}
+	testutil.Ok(t, json.Unmarshal([]byte(lines[0]), &entry))
+	testutil.Equals(t, "up", entry["query"])
+	testutil.Equals(t, 0.5, entry["duration"])
+	_, ok := entry["ts"]
+	testutil.Assert(t, ok, "missing timestamp")
+}
@@ -0,0 +1,79 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"io"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/promlog"
+)
+
+// FormatFlagName is the canonical flag name to configure the log format
+// within Prometheus projects.
+const FormatFlagName = "log.format"
+
+// FormatFlagHelp is the help description for the log.format flag.
+const FormatFlagHelp = "Output format of log messages. One of: [logfmt, json]"
+
+// Format is the output format of log lines. It implements flag.Value.
+type Format struct {
+	s string
+}
+
+func (f *Format) String() string {
+	return f.s
+}
+
+// Set updates the value of the format.
+func (f *Format) Set(s string) error {
+	switch s {
+	case "logfmt", "json":
+		f.s = s
+	default:
+		return errors.Errorf("unrecognized log format %q", s)
+	}
+	return nil
+}
+
+// New returns a logger writing lines of the given format to w, which only
+// logs messages with the given severity or above. Each line carries a
+// timestamp and the caller.
+func New(w io.Writer, l promlog.AllowedLevel, f Format) log.Logger {
+	var logger log.Logger
+	if f.s == "json" {
+		logger = log.NewJSONLogger(log.NewSyncWriter(w))
+	} else {
+		logger = log.NewLogfmtLogger(log.NewSyncWriter(w))
+	}
+	logger = level.NewFilter(logger, levelOption(l))
+	return log.With(logger, "ts", log.DefaultTimestampUTC, "caller", log.DefaultCaller)
+}
+
+// levelOption returns the filter option of the allowed level, which is only
+// exposed by its name. It defaults to allowing info messages.
+func levelOption(l promlog.AllowedLevel) level.Option {
+	switch l.String() {
+	case "debug":
+		return level.AllowDebug()
+	case "warn":
+		return level.AllowWarn()
+	case "error":
+		return level.AllowError()
+	default:
+		return level.AllowInfo()
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/common/promlog"
+
+	"github.com/prometheus/prometheus/util/testutil"
+)
+
+func TestNew(t *testing.T) {
+	var lvl promlog.AllowedLevel
+	testutil.Ok(t, lvl.Set("warn"))
+
+	var f Format
+	testutil.NotOk(t, f.Set("xml"))
+	testutil.Ok(t, f.Set("json"))
+
+	var buf bytes.Buffer
+	l := log.With(New(&buf, lvl, f), "component", "web")
+	level.Info(l).Log("msg", "dropped")
+	level.Warn(l).Log("msg", "kept")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	testutil.Equals(t, 1, len(lines))
+
+	var entry map[string]interface{}
+	testutil.Ok(t, json.Unmarshal([]byte(lines[0]), &entry))
+	testutil.Equals(t, "kept", entry["msg"])
+	testutil.Equals(t, "warn", entry["level"])
+	testutil.Equals(t, "web", entry["component"])
+	_, ok := entry["ts"]
+	testutil.Assert(t, ok, "missing timestamp")
+
+	buf.Reset()
+	testutil.Ok(t, f.Set("logfmt"))
+	level.Error(New(&buf, lvl, f)).Log("msg", "kept")
+	testutil.Assert(t, strings.HasPrefix(buf.String(), "level=error ts="), "unexpected logfmt line %q", buf.String())
+	testutil.Assert(t, strings.Contains(buf.String(), " caller=logger_test.go:"), "unexpected logfmt line %q", buf.String())
+}
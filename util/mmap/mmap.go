@@ -0,0 +1,64 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mmap provides portable writable memory mappings of files.
+package mmap
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// MappedFile is a file of fixed size mapped into memory. Writes to the bytes
+// returned by Bytes are written back to the file by the operating system,
+// even if the process crashes.
+type MappedFile struct {
+	f *os.File
+	b []byte
+}
+
+// Create creates or truncates the file with the given name, resizes it to
+// size zeroed bytes and maps it into memory.
+func Create(fileName string, size int) (*MappedFile, error) {
+	if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(fileName, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0666)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Truncate(int64(size)); err != nil {
+		f.Close()
+		return nil, err
+	}
+	b, err := mmap(f, size)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &MappedFile{f: f, b: b}, nil
+}
+
+// Bytes returns the mapped contents of the file.
+func (m *MappedFile) Bytes() []byte {
+	return m.b
+}
+
+// Close unmaps and closes the file.
+func (m *MappedFile) Close() error {
+	if err := munmap(m.b); err != nil {
+		m.f.Close()
+		return err
+	}
+	return m.f.Close()
+}
@@ -13,6 +13,13 @@
 
 package stats
 
+import (
+	"context"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
 // QueryTiming identifies the code area or functionality in which time is spent
 // during a query.
 type QueryTiming int
@@ -25,6 +32,7 @@ const (
 	InnerEvalTime
 	ResultAppendTime
 	ExecQueueTime
+	QueryParseTime
 )
 
 // Return a string representation of a QueryTiming identifier.
@@ -42,7 +50,116 @@ func (s QueryTiming) String() string {
 		return "Result append time"
 	case ExecQueueTime:
 		return "Exec queue wait time"
+	case QueryParseTime:
+		return "Query parse time"
 	default:
 		return "Unknown query timing"
 	}
 }
+
+// SpanOperation returns the name of the tracing span of the QueryTiming.
+func (s QueryTiming) SpanOperation() string {
+	switch s {
+	case TotalEvalTime:
+		return "promqlEval"
+	case ResultSortTime:
+		return "promqlSort"
+	case QueryPreparationTime:
+		return "promqlPrepare"
+	case InnerEvalTime:
+		return "promqlInnerEval"
+	case ResultAppendTime:
+		return "promqlResultAppend"
+	case ExecQueueTime:
+		return "promqlExecQueue"
+	case QueryParseTime:
+		return "promqlParse"
+	default:
+		return "promqlUnknown"
+	}
+}
+
+// SpanTimer is a Timer of a QueryTiming which also traces the timed code in
+// a span.
+type SpanTimer struct {
+	timer     *Timer
+	observers []prometheus.Observer
+
+	span opentracing.Span
+}
+
+// NewSpanTimer starts the timer of the QueryTiming in the group and a span of
+// it as a child of the span in the context, if any. The returned context
+// carries the new span.
+func NewSpanTimer(ctx context.Context, tg *TimerGroup, qt QueryTiming, observers ...prometheus.Observer) (*SpanTimer, context.Context) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, qt.SpanOperation())
+	timer := tg.GetTimer(qt).Start()
+
+	return &SpanTimer{
+		timer:     timer,
+		observers: observers,
+		span:      span,
+	}, ctx
+}
+
+// Finish stops the timer and the span and passes the elapsed time to the
+// observers.
+func (s *SpanTimer) Finish() {
+	s.timer.Stop()
+	s.span.Finish()
+
+	for _, obs := range s.observers {
+		obs.Observe(s.timer.ElapsedTime().Seconds())
+	}
+}
+
+// QuerySamples counts the series and samples a query processed.
+type QuerySamples struct {
+	// Series is the number of series selected from storage.
+	Series int `json:"series"`
+	// TotalSamples is the number of samples loaded from storage.
+	TotalSamples int `json:"totalSamples"`
+	// PeakSamples is the maximum number of samples held in memory at once.
+	PeakSamples int `json:"peakSamples"`
+}
+
+// queryTimings with all query timers mapped to durations in seconds.
+type queryTimings struct {
+	ParseTime            float64 `json:"parseTime"`
+	ExecQueueTime        float64 `json:"execQueueTime"`
+	QueryPreparationTime float64 `json:"queryPreparationTime"`
+	InnerEvalTime        float64 `json:"innerEvalTime"`
+	ResultSortTime       float64 `json:"resultSortTime"`
+	EvalTotalTime        float64 `json:"evalTotalTime"`
+}
+
+// QueryStats holds the statistics of a single query as exposed through the
+// API.
+type QueryStats struct {
+	Timings queryTimings  `json:"timings"`
+	Samples *QuerySamples `json:"samples,omitempty"`
+}
+
+// NewQueryStats makes a QueryStats struct with the timers of the given group
+// and the given sample counts.
+func NewQueryStats(tg *TimerGroup, samples *QuerySamples) *QueryStats {
+	var qs QueryStats
+	for name, t := range tg.timers {
+		switch name {
+		case QueryParseTime:
+			qs.Timings.ParseTime = t.Duration().Seconds()
+		case ExecQueueTime:
+			qs.Timings.ExecQueueTime = t.Duration().Seconds()
+		case QueryPreparationTime:
+			qs.Timings.QueryPreparationTime = t.Duration().Seconds()
+		case InnerEvalTime:
+			qs.Timings.InnerEvalTime = t.Duration().Seconds()
+		case ResultSortTime:
+			qs.Timings.ResultSortTime = t.Duration().Seconds()
+		case TotalEvalTime:
+			qs.Timings.EvalTotalTime = t.Duration().Seconds()
+		}
+	}
+	qs.Samples = samples
+	return &qs
+}
@@ -0,0 +1,92 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracing sets up the Jaeger tracer that queries are traced with.
+package tracing
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
+	jaeger "github.com/uber/jaeger-client-go"
+	jaeger_transport "github.com/uber/jaeger-client-go/transport"
+	jaeger_prometheus "github.com/uber/jaeger-lib/metrics/prometheus"
+)
+
+// Options of the tracer.
+type Options struct {
+	// The address of a Jaeger agent that spans are sent to over UDP, or the
+	// HTTP(S) URL of a Jaeger collector.
+	Endpoint string
+	// The ratio of traces started by the tracer that are sampled, between 0
+	// and 1. Traces continued from other services are sampled if they were
+	// sampled there.
+	SampleRate float64
+	// The interval at which finished spans are sent.
+	FlushInterval time.Duration
+}
+
+// New returns a Jaeger tracer for the named service, along with a closer that
+// sends the spans still buffered and stops the tracer.
+func New(service string, o Options, l log.Logger, r prometheus.Registerer) (opentracing.Tracer, io.Closer, error) {
+	if l == nil {
+		l = log.NewNopLogger()
+	}
+	var transport jaeger.Transport
+	if strings.HasPrefix(o.Endpoint, "http://") || strings.HasPrefix(o.Endpoint, "https://") {
+		transport = jaeger_transport.NewHTTPTransport(o.Endpoint)
+	} else {
+		var err error
+		if transport, err = jaeger.NewUDPTransport(o.Endpoint, 0); err != nil {
+			return nil, nil, fmt.Errorf("error creating transport to Jaeger agent %q: %s", o.Endpoint, err)
+		}
+	}
+	sampler, err := jaeger.NewProbabilisticSampler(o.SampleRate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		logger  = jaegerLogger{l}
+		metrics = jaeger.NewMetrics(jaeger_prometheus.New(jaeger_prometheus.WithRegisterer(r)), nil)
+	)
+	reporter := jaeger.NewRemoteReporter(transport,
+		jaeger.ReporterOptions.BufferFlushInterval(o.FlushInterval),
+		jaeger.ReporterOptions.Logger(logger),
+		jaeger.ReporterOptions.Metrics(metrics),
+	)
+	tracer, closer := jaeger.NewTracer(service, sampler, reporter,
+		jaeger.TracerOptions.Logger(logger),
+		jaeger.TracerOptions.Metrics(metrics),
+	)
+	return tracer, closer, nil
+}
+
+// jaegerLogger adapts a go-kit logger to the logger interface of Jaeger.
+type jaegerLogger struct {
+	logger log.Logger
+}
+
+func (l jaegerLogger) Error(msg string) {
+	level.Error(l.logger).Log("msg", msg)
+}
+
+func (l jaegerLogger) Infof(msg string, args ...interface{}) {
+	level.Debug(l.logger).Log("msg", fmt.Sprintf(msg, args...))
+}
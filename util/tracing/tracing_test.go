@@ -0,0 +1,56 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/prometheus/prometheus/util/testutil"
+)
+
+func TestNewCollector(t *testing.T) {
+	received := make(chan *http.Request, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r
+	}))
+	defer server.Close()
+
+	tracer, closer, err := New("test", Options{
+		Endpoint:      server.URL + "/api/traces",
+		SampleRate:    1,
+		FlushInterval: time.Hour,
+	}, nil, prometheus.NewRegistry())
+	testutil.Ok(t, err)
+
+	tracer.StartSpan("query").Finish()
+	testutil.Ok(t, closer.Close())
+
+	select {
+	case r := <-received:
+		testutil.Equals(t, "/api/traces", r.URL.Path)
+		testutil.Equals(t, "application/x-thrift", r.Header.Get("Content-Type"))
+	default:
+		t.Fatal("Expected spans to be sent on close")
+	}
+}
+
+func TestNewInvalidSampleRate(t *testing.T) {
+	_, _, err := New("test", Options{Endpoint: "localhost:6831", SampleRate: 2}, nil, prometheus.NewRegistry())
+	testutil.NotOk(t, err)
+}
@@ -0,0 +1,207 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package watchdog captures heap and goroutine profiles of the process when
+// its heap size or number of goroutines exceed thresholds, so that the cause
+// of excessive resource usage can be analyzed after the process was killed.
+package watchdog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// profiles are the profiles captured, by the prefix of their file names.
+var profiles = []string{"heap", "goroutine"}
+
+// profileExt is the extension of the files of captured profiles, which are
+// gzipped protocol buffers as read by `go tool pprof`.
+const profileExt = ".pb.gz"
+
+// Options of the Watchdog.
+type Options struct {
+	// The directory the profiles are written to.
+	Dir string
+	// The heap size in bytes and the number of goroutines above which the
+	// profiles are captured. Zero disables the respective threshold.
+	HeapThreshold      uint64
+	GoroutineThreshold int
+	// The interval at which the thresholds are checked.
+	CheckInterval time.Duration
+	// The minimum interval between two captures while a threshold remains
+	// exceeded.
+	MinCaptureInterval time.Duration
+	// The number of most recent captures that are kept. Zero keeps all of
+	// them.
+	MaxCaptures int
+}
+
+// Watchdog periodically checks the heap size and the number of goroutines
+// of the process and captures profiles when they exceed their thresholds.
+type Watchdog struct {
+	opts   Options
+	logger log.Logger
+
+	lastCapture time.Time
+	// Read the heap size and number of goroutines, replaced in tests.
+	readUsage func() (heap uint64, goroutines int)
+
+	captures        *prometheus.CounterVec
+	captureFailures prometheus.Counter
+}
+
+// New returns a new Watchdog.
+func New(o Options, l log.Logger, r prometheus.Registerer) *Watchdog {
+	if l == nil {
+		l = log.NewNopLogger()
+	}
+	w := &Watchdog{
+		opts:      o,
+		logger:    l,
+		readUsage: readUsage,
+		captures: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "prometheus_watchdog_captures_total",
+				Help: "The number of times profiles were captured, by the exceeded threshold.",
+			},
+			[]string{"threshold"},
+		),
+		captureFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "prometheus_watchdog_capture_failures_total",
+			Help: "The number of times capturing profiles failed.",
+		}),
+	}
+	if r != nil {
+		r.MustRegister(w.captures, w.captureFailures)
+	}
+	return w
+}
+
+func readUsage() (uint64, int) {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return ms.HeapAlloc, runtime.NumGoroutine()
+}
+
+// Run checks the thresholds at the configured interval until the context is
+// canceled.
+func (w *Watchdog) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.check(time.Now())
+		}
+	}
+}
+
+// check captures the profiles if a threshold is exceeded and the last
+// capture is long enough ago.
+func (w *Watchdog) check(now time.Time) {
+	heap, goroutines := w.readUsage()
+
+	var threshold string
+	switch {
+	case w.opts.HeapThreshold > 0 && heap > w.opts.HeapThreshold:
+		threshold = "heap"
+	case w.opts.GoroutineThreshold > 0 && goroutines > w.opts.GoroutineThreshold:
+		threshold = "goroutines"
+	default:
+		return
+	}
+	if now.Sub(w.lastCapture) < w.opts.MinCaptureInterval {
+		return
+	}
+	w.lastCapture = now
+
+	level.Warn(w.logger).Log("msg", "Threshold exceeded, capturing profiles", "threshold", threshold, "heap_bytes", heap, "goroutines", goroutines, "dir", w.opts.Dir)
+	if err := w.capture(now); err != nil {
+		w.captureFailures.Inc()
+		level.Error(w.logger).Log("msg", "Capturing profiles failed", "err", err)
+		return
+	}
+	w.captures.WithLabelValues(threshold).Inc()
+}
+
+// capture writes the profiles to files named after the profile and the given
+// time and deletes the profiles of the oldest captures exceeding the maximum.
+func (w *Watchdog) capture(now time.Time) error {
+	if err := os.MkdirAll(w.opts.Dir, 0777); err != nil {
+		return err
+	}
+	ts := now.UTC().Format("20060102T150405.000Z")
+	for _, name := range profiles {
+		fn := filepath.Join(w.opts.Dir, fmt.Sprintf("%s-%s%s", name, ts, profileExt))
+		if err := writeProfile(name, fn); err != nil {
+			return fmt.Errorf("write %s profile: %s", name, err)
+		}
+		if err := w.prune(name); err != nil {
+			return fmt.Errorf("delete old %s profiles: %s", name, err)
+		}
+	}
+	return nil
+}
+
+// writeProfile writes the named profile to fn. It is written to a temporary
+// file first, so that profiles are never truncated.
+func writeProfile(name, fn string) error {
+	tmp := fn + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := pprof.Lookup(name).WriteTo(f, 0); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, fn)
+}
+
+// prune deletes the oldest profiles of the given name exceeding the maximum
+// number of captures.
+func (w *Watchdog) prune(name string) error {
+	files, err := filepath.Glob(filepath.Join(w.opts.Dir, name+"-*"+profileExt))
+	if err != nil {
+		return err
+	}
+	if w.opts.MaxCaptures <= 0 || len(files) <= w.opts.MaxCaptures {
+		return nil
+	}
+	// The timestamps in the file names sort chronologically.
+	sort.Strings(files)
+	for _, fn := range files[:len(files)-w.opts.MaxCaptures] {
+		if err := os.Remove(fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
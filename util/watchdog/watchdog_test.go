@@ -0,0 +1,88 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watchdog
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/util/testutil"
+)
+
+func TestWatchdog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watchdog")
+	testutil.Ok(t, err)
+	defer os.RemoveAll(dir)
+
+	w := New(Options{
+		Dir:                dir,
+		HeapThreshold:      1000,
+		GoroutineThreshold: 10,
+		MinCaptureInterval: time.Minute,
+		MaxCaptures:        2,
+	}, nil, nil)
+
+	var (
+		heap       uint64
+		goroutines int
+	)
+	w.readUsage = func() (uint64, int) { return heap, goroutines }
+
+	captured := func() []string {
+		files, err := filepath.Glob(filepath.Join(dir, "*"))
+		testutil.Ok(t, err)
+		for i, f := range files {
+			files[i] = filepath.Base(f)
+		}
+		return files
+	}
+
+	start := time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Below both thresholds.
+	heap, goroutines = 1000, 10
+	w.check(start)
+	testutil.Equals(t, 0, len(captured()))
+
+	// The heap threshold is exceeded.
+	heap = 1001
+	w.check(start)
+	testutil.Equals(t, []string{
+		"goroutine-20180301T120000.000Z.pb.gz",
+		"heap-20180301T120000.000Z.pb.gz",
+	}, captured())
+
+	// No captures within the minimum capture interval.
+	heap, goroutines = 1000, 11
+	w.check(start.Add(59 * time.Second))
+	testutil.Equals(t, 2, len(captured()))
+
+	w.check(start.Add(time.Minute))
+	w.check(start.Add(2 * time.Minute))
+	// Only the profiles of the two most recent captures are kept.
+	testutil.Equals(t, []string{
+		"goroutine-20180301T120100.000Z.pb.gz",
+		"goroutine-20180301T120200.000Z.pb.gz",
+		"heap-20180301T120100.000Z.pb.gz",
+		"heap-20180301T120200.000Z.pb.gz",
+	}, captured())
+
+	// The profiles are not empty.
+	b, err := ioutil.ReadFile(filepath.Join(dir, "heap-20180301T120200.000Z.pb.gz"))
+	testutil.Ok(t, err)
+	testutil.Assert(t, len(b) > 0, "expected heap profile to be written")
+}
@@ -18,26 +18,41 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"math"
+	"math/rand"
+	"net"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
 	"sort"
 	"strconv"
 	"time"
 
-	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/common/route"
+	libtsdb "github.com/prometheus/tsdb"
+	yaml "gopkg.in/yaml.v2"
 
 	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/discovery"
+	"github.com/prometheus/prometheus/pkg/exemplar"
 	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/rulefmt"
+	"github.com/prometheus/prometheus/pkg/textparse"
 	"github.com/prometheus/prometheus/pkg/timestamp"
 	"github.com/prometheus/prometheus/prompb"
 	"github.com/prometheus/prometheus/promql"
 	"github.com/prometheus/prometheus/retrieval"
+	"github.com/prometheus/prometheus/rules"
 	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/storage/remote"
+	"github.com/prometheus/prometheus/storage/tsdb"
 	"github.com/prometheus/prometheus/util/httputil"
+	"github.com/prometheus/prometheus/util/stats"
 )
 
 type status string
@@ -50,21 +65,16 @@ const (
 type errorType string
 
 const (
-	errorNone     errorType = ""
-	errorTimeout            = "timeout"
-	errorCanceled           = "canceled"
-	errorExec               = "execution"
-	errorBadData            = "bad_data"
-	errorInternal           = "internal"
+	errorNone        errorType = ""
+	errorTimeout               = "timeout"
+	errorCanceled              = "canceled"
+	errorExec                  = "execution"
+	errorBadData               = "bad_data"
+	errorInternal              = "internal"
+	errorUnavailable           = "unavailable"
+	errorNotFound              = "not_found"
 )
 
-var corsHeaders = map[string]string{
-	"Access-Control-Allow-Headers":  "Accept, Authorization, Content-Type, Origin",
-	"Access-Control-Allow-Methods":  "GET, OPTIONS",
-	"Access-Control-Allow-Origin":   "*",
-	"Access-Control-Expose-Headers": "Date",
-}
-
 type apiError struct {
 	typ errorType
 	err error
@@ -76,10 +86,26 @@ func (e *apiError) Error() string {
 
 type targetRetriever interface {
 	Targets() []*retrieval.Target
+	DroppedTargets() []*retrieval.Target
 }
 
 type alertmanagerRetriever interface {
 	Alertmanagers() []*url.URL
+	DroppedAlertmanagers() []*url.URL
+}
+
+type discoveryRetriever interface {
+	ProviderStatus() []discovery.ProviderStatus
+}
+
+type rulesRetriever interface {
+	RuleGroups() []*rules.Group
+	AlertingRules() []*rules.AlertingRule
+}
+
+type rulesWriter interface {
+	SetManagedGroup(rulefmt.RuleGroup) error
+	DeleteManagedGroup(name string) error
 }
 
 type response struct {
@@ -87,16 +113,15 @@ type response struct {
 	Data      interface{} `json:"data,omitempty"`
 	ErrorType errorType   `json:"errorType,omitempty"`
 	Error     string      `json:"error,omitempty"`
+	// ErrorPosition is the range of the offending part of a query that
+	// failed to parse.
+	ErrorPosition *promql.PositionRange `json:"errorPosition,omitempty"`
+	// Warnings report storage problems that still allowed to return a
+	// possibly partial result.
+	Warnings []string `json:"warnings,omitempty"`
 }
 
-// Enables cross-site script calls.
-func setCORS(w http.ResponseWriter) {
-	for h, v := range corsHeaders {
-		w.Header().Set(h, v)
-	}
-}
-
-type apiFunc func(r *http.Request) (interface{}, *apiError)
+type apiFunc func(r *http.Request) (interface{}, *apiError, storage.Warnings)
 
 // API can register a set of endpoints in a router and handle
 // them using the provided storage and query engine.
@@ -106,10 +131,55 @@ type API struct {
 
 	targetRetriever       targetRetriever
 	alertmanagerRetriever alertmanagerRetriever
+	rulesRetriever        rulesRetriever
+	rulesWriter           rulesWriter
+	scrapeDiscovery       discoveryRetriever
+	notifyDiscovery       discoveryRetriever
+
+	now         func() time.Time
+	config      func() config.Config
+	flagsMap    map[string]string
+	buildInfo   *PrometheusVersion
+	runtimeInfo func() RuntimeInfo
+	db          func() *libtsdb.DB
+	corsOrigin  *regexp.Regexp
+	ready       func(http.HandlerFunc) http.HandlerFunc
+	enableAdmin bool
+	// In agent mode the endpoints querying the storage and the rules are
+	// disabled.
+	agent     bool
+	exemplars storage.ExemplarQuerier
+	tenant    TenantOptions
+
+	// The maximum lookback delta queries may request.
+	maxLookbackDelta time.Duration
+	// Caches the results of range queries, may be nil.
+	queryCache *queryCache
+	// Makes scrape URLs reachable from outside of the server, may be nil.
+	globalURL func(*url.URL) *url.URL
+}
 
-	now    func() time.Time
-	config func() config.Config
-	ready  func(http.HandlerFunc) http.HandlerFunc
+// PrometheusVersion contains build information about Prometheus.
+type PrometheusVersion struct {
+	Version   string `json:"version"`
+	Revision  string `json:"revision"`
+	Branch    string `json:"branch"`
+	BuildUser string `json:"buildUser"`
+	BuildDate string `json:"buildDate"`
+	GoVersion string `json:"goVersion"`
+}
+
+// RuntimeInfo contains runtime information about Prometheus.
+type RuntimeInfo struct {
+	StartTime           time.Time `json:"startTime"`
+	CWD                 string    `json:"CWD"`
+	ReloadConfigSuccess bool      `json:"reloadConfigSuccess"`
+	LastConfigTime      time.Time `json:"lastConfigTime"`
+	GoroutineCount      int       `json:"goroutineCount"`
+	GOMAXPROCS          int       `json:"GOMAXPROCS"`
+	GOGC                string    `json:"GOGC"`
+	GODEBUG             string    `json:"GODEBUG"`
+	StorageRetention    string    `json:"storageRetention"`
 }
 
 // NewAPI returns an initialized API type.
@@ -118,198 +188,430 @@ func NewAPI(
 	q promql.Queryable,
 	tr targetRetriever,
 	ar alertmanagerRetriever,
+	rr rulesRetriever,
+	rw rulesWriter,
+	sdScrape discoveryRetriever,
+	sdNotify discoveryRetriever,
 	configFunc func() config.Config,
+	flagsMap map[string]string,
+	buildInfo *PrometheusVersion,
+	runtimeInfo func() RuntimeInfo,
+	db func() *libtsdb.DB,
+	corsOrigin *regexp.Regexp,
 	readyFunc func(http.HandlerFunc) http.HandlerFunc,
+	enableAdmin bool,
+	agent bool,
+	exemplars storage.ExemplarQuerier,
+	tenant TenantOptions,
+	maxLookbackDelta time.Duration,
+	queryCacheMaxBytes int64,
+	outOfOrderTimeWindow time.Duration,
+	globalURL func(*url.URL) *url.URL,
 ) *API {
 	return &API{
 		QueryEngine:           qe,
 		Queryable:             q,
 		targetRetriever:       tr,
 		alertmanagerRetriever: ar,
-		now:    time.Now,
-		config: configFunc,
-		ready:  readyFunc,
+		rulesRetriever:        rr,
+		rulesWriter:           rw,
+		scrapeDiscovery:       sdScrape,
+		notifyDiscovery:       sdNotify,
+		now:                   time.Now,
+		config:                configFunc,
+		flagsMap:              flagsMap,
+		buildInfo:             buildInfo,
+		runtimeInfo:           runtimeInfo,
+		db:                    db,
+		corsOrigin:            corsOrigin,
+		ready:                 readyFunc,
+		enableAdmin:           enableAdmin,
+		agent:                 agent,
+		exemplars:             exemplars,
+		tenant:                tenant,
+		maxLookbackDelta:      maxLookbackDelta,
+		queryCache:            newQueryCache(queryCacheMaxBytes, outOfOrderTimeWindow),
+		globalURL:             globalURL,
 	}
 }
 
-// Register the API's endpoints in the given router.
+// Register the API's endpoints in the given router. Requests are
+// instrumented by their route below /api/v1.
 func (api *API) Register(r *route.Router) {
-	instr := func(name string, f apiFunc) http.HandlerFunc {
+	instr := func(pattern string, f apiFunc) http.HandlerFunc {
 		hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			setCORS(w)
-			if data, err := f(r); err != nil {
-				respondError(w, err, data)
-			} else if data != nil {
-				respond(w, data)
+			httputil.SetCORS(w, api.corsOrigin, r)
+			if data, err, warnings := f(r); err != nil {
+				respondError(w, err, data, warnings)
+			} else if data != nil || len(warnings) > 0 {
+				respond(w, data, warnings)
 			} else {
 				w.WriteHeader(http.StatusNoContent)
 			}
 		})
-		return api.ready(prometheus.InstrumentHandler(name, httputil.CompressionHandler{
+		return httputil.InstrumentHandler("/api/v1"+pattern, api.ready(httputil.CompressionHandler{
 			Handler: hf,
-		}))
+		}.ServeHTTP))
 	}
 
-	r.Options("/*path", instr("options", api.options))
-
-	r.Get("/query", instr("query", api.query))
-	r.Post("/query", instr("query", api.query))
-	r.Get("/query_range", instr("query_range", api.queryRange))
-	r.Post("/query_range", instr("query_range", api.queryRange))
-
-	r.Get("/label/:name/values", instr("label_values", api.labelValues))
-
-	r.Get("/series", instr("series", api.series))
-	r.Del("/series", instr("drop_series", api.dropSeries))
+	// Endpoints that query the storage or depend on the query engine and
+	// the rule manager, which do not run in agent mode.
+	query := func(pattern string, f apiFunc) http.HandlerFunc {
+		if api.agent {
+			f = api.agentModeDisabled
+		}
+		return instr(pattern, f)
+	}
 
-	r.Get("/targets", instr("targets", api.targets))
-	r.Get("/alertmanagers", instr("alertmanagers", api.alertmanagers))
+	r.Options("/*path", instr("/*path", api.options))
+
+	r.Get("/query", query("/query", api.query))
+	r.Post("/query", query("/query", api.query))
+	r.Get("/query_range", query("/query_range", api.queryRange))
+	r.Post("/query_range", query("/query_range", api.queryRange))
+	r.Get("/query_exemplars", query("/query_exemplars", api.queryExemplars))
+	r.Post("/query_exemplars", query("/query_exemplars", api.queryExemplars))
+
+	r.Get("/labels", query("/labels", api.labelNames))
+	r.Get("/label/:name/values", query("/label/:name/values", api.labelValues))
+
+	r.Get("/series", query("/series", api.series))
+	r.Del("/series", instr("/series", api.dropSeries))
+
+	r.Get("/targets", instr("/targets", api.targets))
+	r.Get("/targets/metadata", instr("/targets/metadata", api.targetMetadata))
+	r.Get("/metadata", instr("/metadata", api.metricMetadata))
+	r.Get("/alertmanagers", query("/alertmanagers", api.alertmanagers))
+	r.Get("/sd", instr("/sd", api.serviceDiscovery))
+	r.Get("/alerts", query("/alerts", api.alerts))
+	r.Get("/rules", query("/rules", api.rules))
+	r.Put("/rules/:group", query("/rules/:group", api.setRuleGroup))
+	r.Del("/rules/:group", query("/rules/:group", api.deleteRuleGroup))
+
+	r.Get("/status/config", instr("/status/config", api.serveConfig))
+	r.Get("/status/runtimeinfo", instr("/status/runtimeinfo", api.serveRuntimeInfo))
+	r.Get("/status/buildinfo", instr("/status/buildinfo", api.serveBuildInfo))
+	r.Get("/status/flags", instr("/status/flags", api.serveFlags))
+	r.Get("/status/tsdb", instr("/status/tsdb", api.serveTSDBStatus))
+	if api.agent {
+		r.Post("/read", instr("/read", api.agentModeDisabled))
+	} else {
+		r.Post("/read", httputil.InstrumentHandler("/api/v1/read", api.ready(api.remoteRead)))
+	}
 
-	r.Get("/status/config", instr("config", api.serveConfig))
-	r.Post("/read", api.ready(prometheus.InstrumentHandler("read", http.HandlerFunc(api.remoteRead))))
+	// Admin APIs
+	r.Post("/admin/tsdb/delete_series", instr("/admin/tsdb/delete_series", api.deleteSeries))
+	r.Put("/admin/tsdb/delete_series", instr("/admin/tsdb/delete_series", api.deleteSeries))
+	r.Post("/admin/tsdb/clean_tombstones", instr("/admin/tsdb/clean_tombstones", api.cleanTombstones))
+	r.Put("/admin/tsdb/clean_tombstones", instr("/admin/tsdb/clean_tombstones", api.cleanTombstones))
+	r.Post("/admin/tsdb/snapshot", instr("/admin/tsdb/snapshot", api.snapshot))
+	r.Put("/admin/tsdb/snapshot", instr("/admin/tsdb/snapshot", api.snapshot))
 }
 
 type queryData struct {
-	ResultType promql.ValueType `json:"resultType"`
-	Result     promql.Value     `json:"result"`
+	ResultType promql.ValueType  `json:"resultType"`
+	Result     promql.Value      `json:"result"`
+	Stats      *stats.QueryStats `json:"stats,omitempty"`
+}
+
+// queryStats returns the statistics of the executed query if they were
+// requested with the stats parameter.
+func queryStats(r *http.Request, qry promql.Query) *stats.QueryStats {
+	if r.FormValue("stats") != "all" {
+		return nil
+	}
+	return stats.NewQueryStats(qry.Stats(), qry.SampleStats())
 }
 
-func (api *API) options(r *http.Request) (interface{}, *apiError) {
-	return nil, nil
+func (api *API) options(r *http.Request) (interface{}, *apiError, storage.Warnings) {
+	return nil, nil, nil
 }
 
-func (api *API) query(r *http.Request) (interface{}, *apiError) {
+func (api *API) query(r *http.Request) (interface{}, *apiError, storage.Warnings) {
 	var ts time.Time
 	if t := r.FormValue("time"); t != "" {
 		var err error
 		ts, err = parseTime(t)
 		if err != nil {
-			return nil, &apiError{errorBadData, err}
+			return nil, &apiError{errorBadData, err}, nil
 		}
 	} else {
 		ts = api.now()
 	}
 
-	ctx := r.Context()
+	ctx := queryOrigin(r)
 	if to := r.FormValue("timeout"); to != "" {
 		var cancel context.CancelFunc
 		timeout, err := parseDuration(to)
 		if err != nil {
-			return nil, &apiError{errorBadData, err}
+			return nil, &apiError{errorBadData, err}, nil
 		}
 
 		ctx, cancel = context.WithTimeout(ctx, timeout)
 		defer cancel()
 	}
 
-	qry, err := api.QueryEngine.NewInstantQuery(r.FormValue("query"), ts)
+	tm, apiErr := api.tenantMatcher(r)
+	if apiErr != nil {
+		return nil, apiErr, nil
+	}
+	query, err := enforceQuery(r.FormValue("query"), tm)
+	if err != nil {
+		return nil, &apiError{errorBadData, err}, nil
+	}
+
+	lookbackDelta, err := api.lookbackDelta(r)
+	if err != nil {
+		return nil, &apiError{errorBadData, err}, nil
+	}
+
+	qry, err := api.QueryEngine.NewInstantQuery(query, ts)
 	if err != nil {
-		return nil, &apiError{errorBadData, err}
+		return nil, &apiError{errorBadData, err}, nil
 	}
+	qry.Statement().(*promql.EvalStmt).LookbackDelta = lookbackDelta
 
 	res := qry.Exec(ctx)
 	if res.Err != nil {
 		switch res.Err.(type) {
 		case promql.ErrQueryCanceled:
-			return nil, &apiError{errorCanceled, res.Err}
+			return nil, &apiError{errorCanceled, res.Err}, res.Warnings
 		case promql.ErrQueryTimeout:
-			return nil, &apiError{errorTimeout, res.Err}
+			return nil, &apiError{errorTimeout, res.Err}, res.Warnings
 		case promql.ErrStorage:
-			return nil, &apiError{errorInternal, res.Err}
+			return nil, &apiError{errorInternal, res.Err}, res.Warnings
 		}
-		return nil, &apiError{errorExec, res.Err}
+		return nil, &apiError{errorExec, res.Err}, res.Warnings
 	}
 	return &queryData{
 		ResultType: res.Value.Type(),
 		Result:     res.Value,
-	}, nil
+		Stats:      queryStats(r, qry),
+	}, nil, res.Warnings
 }
 
-func (api *API) queryRange(r *http.Request) (interface{}, *apiError) {
+// lookbackDelta returns the lookback delta requested with the lookback_delta
+// parameter, or zero for the default one. It may not exceed the default or
+// the configured maximum, whichever is greater.
+func (api *API) lookbackDelta(r *http.Request) (time.Duration, error) {
+	s := r.FormValue("lookback_delta")
+	if s == "" {
+		return 0, nil
+	}
+	d, err := parseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, errors.New("zero or negative lookback delta is not accepted")
+	}
+	max := api.maxLookbackDelta
+	if max < promql.LookbackDelta {
+		max = promql.LookbackDelta
+	}
+	if d > max {
+		return 0, fmt.Errorf("lookback delta %s exceeds the maximum of %s", d, max)
+	}
+	return d, nil
+}
+
+// queryOrigin returns the context of the request annotated with the client
+// issuing the query, for the query log.
+func queryOrigin(r *http.Request) context.Context {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	return promql.NewOriginContext(r.Context(), map[string]interface{}{
+		"httpRequest": map[string]string{
+			"clientIP": ip,
+			"method":   r.Method,
+			"path":     r.URL.Path,
+		},
+	})
+}
+
+func (api *API) queryRange(r *http.Request) (interface{}, *apiError, storage.Warnings) {
 	start, err := parseTime(r.FormValue("start"))
 	if err != nil {
-		return nil, &apiError{errorBadData, err}
+		return nil, &apiError{errorBadData, err}, nil
 	}
 	end, err := parseTime(r.FormValue("end"))
 	if err != nil {
-		return nil, &apiError{errorBadData, err}
+		return nil, &apiError{errorBadData, err}, nil
 	}
 	if end.Before(start) {
 		err := errors.New("end timestamp must not be before start time")
-		return nil, &apiError{errorBadData, err}
+		return nil, &apiError{errorBadData, err}, nil
 	}
 
 	step, err := parseDuration(r.FormValue("step"))
 	if err != nil {
-		return nil, &apiError{errorBadData, err}
+		return nil, &apiError{errorBadData, err}, nil
 	}
 
 	if step <= 0 {
 		err := errors.New("zero or negative query resolution step widths are not accepted. Try a positive integer")
-		return nil, &apiError{errorBadData, err}
+		return nil, &apiError{errorBadData, err}, nil
 	}
 
 	// For safety, limit the number of returned points per timeseries.
 	// This is sufficient for 60s resolution for a week or 1h resolution for a year.
 	if end.Sub(start)/step > 11000 {
 		err := errors.New("exceeded maximum resolution of 11,000 points per timeseries. Try decreasing the query resolution (?step=XX)")
-		return nil, &apiError{errorBadData, err}
+		return nil, &apiError{errorBadData, err}, nil
 	}
 
-	ctx := r.Context()
+	ctx := queryOrigin(r)
 	if to := r.FormValue("timeout"); to != "" {
 		var cancel context.CancelFunc
 		timeout, err := parseDuration(to)
 		if err != nil {
-			return nil, &apiError{errorBadData, err}
+			return nil, &apiError{errorBadData, err}, nil
 		}
 
 		ctx, cancel = context.WithTimeout(ctx, timeout)
 		defer cancel()
 	}
 
-	qry, err := api.QueryEngine.NewRangeQuery(r.FormValue("query"), start, end, step)
+	tm, apiErr := api.tenantMatcher(r)
+	if apiErr != nil {
+		return nil, apiErr, nil
+	}
+	query, err := enforceQuery(r.FormValue("query"), tm)
 	if err != nil {
-		return nil, &apiError{errorBadData, err}
+		return nil, &apiError{errorBadData, err}, nil
 	}
 
+	lookbackDelta, err := api.lookbackDelta(r)
+	if err != nil {
+		return nil, &apiError{errorBadData, err}, nil
+	}
+
+	// Only results of ranges aligned to the step are cached, as dashboards
+	// request them repeatedly. Ranges reading samples from the future, also
+	// by negative offsets, are not complete yet.
+	var (
+		now     time.Time
+		dataEnd time.Time
+	)
+	if api.queryCache != nil {
+		now = api.now()
+		if expr, err := promql.ParseExpr(query); err == nil {
+			dataEnd = end.Add(-minOffset(expr))
+		}
+	}
+	cacheable := api.queryCache != nil && r.FormValue("stats") != "all" && !dataEnd.IsZero() &&
+		start.UnixNano()%int64(step) == 0 && end.UnixNano()%int64(step) == 0 && !dataEnd.After(now)
+	key := queryCacheKey{
+		query:         query,
+		start:         timestamp.FromTime(start),
+		end:           timestamp.FromTime(end),
+		step:          step,
+		lookbackDelta: lookbackDelta,
+	}
+	if cacheable {
+		if v, warnings, ok := api.queryCache.get(key, now); ok {
+			return &queryData{
+				ResultType: v.Type(),
+				Result:     v,
+			}, nil, warnings
+		}
+	}
+
+	qry, err := api.QueryEngine.NewRangeQuery(query, start, end, step)
+	if err != nil {
+		return nil, &apiError{errorBadData, err}, nil
+	}
+	qry.Statement().(*promql.EvalStmt).LookbackDelta = lookbackDelta
+
 	res := qry.Exec(ctx)
 	if res.Err != nil {
 		switch res.Err.(type) {
 		case promql.ErrQueryCanceled:
-			return nil, &apiError{errorCanceled, res.Err}
+			return nil, &apiError{errorCanceled, res.Err}, res.Warnings
 		case promql.ErrQueryTimeout:
-			return nil, &apiError{errorTimeout, res.Err}
+			return nil, &apiError{errorTimeout, res.Err}, res.Warnings
 		}
-		return nil, &apiError{errorExec, res.Err}
+		return nil, &apiError{errorExec, res.Err}, res.Warnings
+	}
+
+	// Results with warnings, like from failed remote reads, may be incomplete.
+	if cacheable && len(res.Warnings) == 0 {
+		var expires time.Time
+		if now.Sub(dataEnd) < api.queryCache.settleTime {
+			expires = now.Add(step)
+		}
+		api.queryCache.put(key, res.Value, res.Warnings, expires)
 	}
 
 	return &queryData{
 		ResultType: res.Value.Type(),
 		Result:     res.Value,
-	}, nil
+		Stats:      queryStats(r, qry),
+	}, nil, res.Warnings
 }
 
-func (api *API) labelValues(r *http.Request) (interface{}, *apiError) {
+func (api *API) labelValues(r *http.Request) (interface{}, *apiError, storage.Warnings) {
 	ctx := r.Context()
 	name := route.Param(ctx, "name")
 
 	if !model.LabelNameRE.MatchString(name) {
-		return nil, &apiError{errorBadData, fmt.Errorf("invalid label name: %q", name)}
+		return nil, &apiError{errorBadData, fmt.Errorf("invalid label name: %q", name)}, nil
 	}
-	q, err := api.Queryable.Querier(ctx, math.MinInt64, math.MaxInt64)
+	start, err := parseTimeParam(r, "start", minTime)
 	if err != nil {
-		return nil, &apiError{errorExec, err}
+		return nil, &apiError{errorBadData, err}, nil
+	}
+	end, err := parseTimeParam(r, "end", maxTime)
+	if err != nil {
+		return nil, &apiError{errorBadData, err}, nil
+	}
+	tm, apiErr := api.tenantMatcher(r)
+	if apiErr != nil {
+		return nil, apiErr, nil
+	}
+
+	// Only the blocks overlapping the time range have to be read.
+	q, err := api.Queryable.Querier(ctx, timestamp.FromTime(start), timestamp.FromTime(end))
+	if err != nil {
+		return nil, &apiError{errorExec, err}, nil
 	}
 	defer q.Close()
 
+	if tm != nil {
+		return tenantLabelValues(q, name, tm)
+	}
+
 	// TODO(fabxc): add back request context.
 	vals, err := q.LabelValues(name)
 	if err != nil {
-		return nil, &apiError{errorExec, err}
+		return nil, &apiError{errorExec, err}, nil
 	}
+	if vals == nil {
+		vals = []string{}
+	}
+
+	return vals, nil, nil
+}
 
-	return vals, nil
+// tenantLabelValues returns the values of the label with the given name of
+// the series selected by the tenant matcher.
+func tenantLabelValues(q storage.Querier, name string, tm *labels.Matcher) (interface{}, *apiError, storage.Warnings) {
+	set, warnings, err := q.Select(nil, tm)
+	if err != nil {
+		return nil, &apiError{errorExec, err}, warnings
+	}
+	vals := []string{}
+	for set.Next() {
+		if v := set.At().Labels().Get(name); v != "" {
+			vals = append(vals, v)
+		}
+	}
+	if set.Err() != nil {
+		return nil, &apiError{errorExec, set.Err()}, warnings
+	}
+	return uniqueSortedStrings(vals), nil, warnings
 }
 
 var (
@@ -317,53 +619,150 @@ var (
 	maxTime = time.Unix(math.MaxInt64/1000-62135596801, 999999999)
 )
 
-func (api *API) series(r *http.Request) (interface{}, *apiError) {
+func (api *API) queryExemplars(r *http.Request) (interface{}, *apiError, storage.Warnings) {
+	start, err := parseTimeParam(r, "start", minTime)
+	if err != nil {
+		return nil, &apiError{errorBadData, err}, nil
+	}
+	end, err := parseTimeParam(r, "end", maxTime)
+	if err != nil {
+		return nil, &apiError{errorBadData, err}, nil
+	}
+	if end.Before(start) {
+		return nil, &apiError{errorBadData, fmt.Errorf("end timestamp must not be before start time")}, nil
+	}
+
+	expr, err := promql.ParseExpr(r.FormValue("query"))
+	if err != nil {
+		return nil, &apiError{errorBadData, err}, nil
+	}
+	tm, apiErr := api.tenantMatcher(r)
+	if apiErr != nil {
+		return nil, apiErr, nil
+	}
+
+	res := []exemplar.QueryResult{}
+	if api.exemplars == nil {
+		return res, nil, nil
+	}
+	selected, err := api.exemplars.SelectExemplars(timestamp.FromTime(start), timestamp.FromTime(end), enforceMatcher(promql.ExtractSelectors(expr), tm)...)
+	if err != nil {
+		return nil, &apiError{errorExec, err}, nil
+	}
+	return append(res, selected...), nil, nil
+}
+
+func (api *API) labelNames(r *http.Request) (interface{}, *apiError, storage.Warnings) {
 	r.ParseForm()
-	if len(r.Form["match[]"]) == 0 {
-		return nil, &apiError{errorBadData, fmt.Errorf("no match[] parameter provided")}
+
+	start, err := parseTimeParam(r, "start", minTime)
+	if err != nil {
+		return nil, &apiError{errorBadData, err}, nil
+	}
+	end, err := parseTimeParam(r, "end", maxTime)
+	if err != nil {
+		return nil, &apiError{errorBadData, err}, nil
+	}
+	matcherSets, err := parseMatchersParam(r.Form["match[]"])
+	if err != nil {
+		return nil, &apiError{errorBadData, err}, nil
+	}
+	tm, apiErr := api.tenantMatcher(r)
+	if apiErr != nil {
+		return nil, apiErr, nil
+	}
+	if tm != nil && len(matcherSets) == 0 {
+		// Only the series of the tenant are considered.
+		matcherSets = [][]*labels.Matcher{{}}
 	}
+	matcherSets = enforceMatcher(matcherSets, tm)
 
-	var start time.Time
-	if t := r.FormValue("start"); t != "" {
-		var err error
-		start, err = parseTime(t)
+	q, err := api.Queryable.Querier(r.Context(), timestamp.FromTime(start), timestamp.FromTime(end))
+	if err != nil {
+		return nil, &apiError{errorExec, err}, nil
+	}
+	defer q.Close()
+
+	if len(matcherSets) == 0 {
+		names, err := q.LabelNames()
 		if err != nil {
-			return nil, &apiError{errorBadData, err}
+			return nil, &apiError{errorExec, err}, nil
 		}
-	} else {
-		start = minTime
+		if names == nil {
+			names = []string{}
+		}
+		return names, nil, nil
 	}
 
-	var end time.Time
-	if t := r.FormValue("end"); t != "" {
-		var err error
-		end, err = parseTime(t)
+	// Restrict the label names to those of the series selected by any of
+	// the matcher sets.
+	var (
+		set      storage.SeriesSet
+		warnings storage.Warnings
+	)
+	for _, mset := range matcherSets {
+		s, wrn, err := q.Select(nil, mset...)
+		warnings = append(warnings, wrn...)
 		if err != nil {
-			return nil, &apiError{errorBadData, err}
+			return nil, &apiError{errorExec, err}, warnings
 		}
-	} else {
-		end = maxTime
+		set = storage.DeduplicateSeriesSet(set, s)
 	}
 
-	var matcherSets [][]*labels.Matcher
-	for _, s := range r.Form["match[]"] {
-		matchers, err := promql.ParseMetricSelector(s)
-		if err != nil {
-			return nil, &apiError{errorBadData, err}
+	names := []string{}
+	for set.Next() {
+		for _, l := range set.At().Labels() {
+			names = append(names, l.Name)
 		}
-		matcherSets = append(matcherSets, matchers)
+	}
+	if set.Err() != nil {
+		return nil, &apiError{errorExec, set.Err()}, warnings
+	}
+	return uniqueSortedStrings(names), nil, warnings
+}
+
+func (api *API) series(r *http.Request) (interface{}, *apiError, storage.Warnings) {
+	r.ParseForm()
+	if len(r.Form["match[]"]) == 0 {
+		return nil, &apiError{errorBadData, fmt.Errorf("no match[] parameter provided")}, nil
 	}
 
+	start, err := parseTimeParam(r, "start", minTime)
+	if err != nil {
+		return nil, &apiError{errorBadData, err}, nil
+	}
+	end, err := parseTimeParam(r, "end", maxTime)
+	if err != nil {
+		return nil, &apiError{errorBadData, err}, nil
+	}
+
+	matcherSets, err := parseMatchersParam(r.Form["match[]"])
+	if err != nil {
+		return nil, &apiError{errorBadData, err}, nil
+	}
+	tm, apiErr := api.tenantMatcher(r)
+	if apiErr != nil {
+		return nil, apiErr, nil
+	}
+	matcherSets = enforceMatcher(matcherSets, tm)
+
 	q, err := api.Queryable.Querier(r.Context(), timestamp.FromTime(start), timestamp.FromTime(end))
 	if err != nil {
-		return nil, &apiError{errorExec, err}
+		return nil, &apiError{errorExec, err}, nil
 	}
 	defer q.Close()
 
-	var set storage.SeriesSet
-
+	var (
+		set      storage.SeriesSet
+		warnings storage.Warnings
+	)
 	for _, mset := range matcherSets {
-		set = storage.DeduplicateSeriesSet(set, q.Select(mset...))
+		s, wrn, err := q.Select(nil, mset...)
+		warnings = append(warnings, wrn...)
+		if err != nil {
+			return nil, &apiError{errorExec, err}, warnings
+		}
+		set = storage.DeduplicateSeriesSet(set, s)
 	}
 
 	metrics := []labels.Labels{}
@@ -372,14 +771,14 @@ func (api *API) series(r *http.Request) (interface{}, *apiError) {
 		metrics = append(metrics, set.At().Labels())
 	}
 	if set.Err() != nil {
-		return nil, &apiError{errorExec, set.Err()}
+		return nil, &apiError{errorExec, set.Err()}, warnings
 	}
 
-	return metrics, nil
+	return metrics, nil, warnings
 }
 
-func (api *API) dropSeries(r *http.Request) (interface{}, *apiError) {
-	return nil, &apiError{errorInternal, fmt.Errorf("not implemented")}
+func (api *API) dropSeries(r *http.Request) (interface{}, *apiError, storage.Warnings) {
+	return nil, &apiError{errorInternal, fmt.Errorf("not implemented")}, nil
 }
 
 // Target has the information for one target.
@@ -390,44 +789,249 @@ type Target struct {
 	Labels map[string]string `json:"labels"`
 
 	ScrapeURL string `json:"scrapeUrl"`
+	// The scrape URL as reachable from outside of the server.
+	GlobalURL string `json:"globalUrl"`
+
+	LastError          string                 `json:"lastError"`
+	LastScrape         time.Time              `json:"lastScrape"`
+	LastScrapeDuration float64                `json:"lastScrapeDuration"`
+	Health             retrieval.TargetHealth `json:"health"`
+}
+
+// DroppedTarget has the information for one target that was dropped during relabeling.
+type DroppedTarget struct {
+	// Labels before any processing.
+	DiscoveredLabels map[string]string `json:"discoveredLabels"`
+	// The relabel configuration that dropped the target.
+	DroppedBy *DroppingRelabelConfig `json:"droppedBy"`
+}
 
-	LastError  string                 `json:"lastError"`
-	LastScrape time.Time              `json:"lastScrape"`
-	Health     retrieval.TargetHealth `json:"health"`
+// DroppingRelabelConfig identifies the relabel configuration of a scrape
+// config that dropped a target.
+type DroppingRelabelConfig struct {
+	// The position among the relabel configurations of the scrape config.
+	Index  int                  `json:"index"`
+	Action config.RelabelAction `json:"action"`
 }
 
-// TargetDiscovery has all the active targets.
+// TargetDiscovery has all the active and dropped targets.
 type TargetDiscovery struct {
-	ActiveTargets []*Target `json:"activeTargets"`
+	ActiveTargets  []*Target        `json:"activeTargets"`
+	DroppedTargets []*DroppedTarget `json:"droppedTargets"`
+}
+
+func (api *API) targets(r *http.Request) (interface{}, *apiError, storage.Warnings) {
+	var showActive, showDropped bool
+	switch state := r.FormValue("state"); state {
+	case "", "any":
+		showActive, showDropped = true, true
+	case "active":
+		showActive = true
+	case "dropped":
+		showDropped = true
+	default:
+		return nil, &apiError{errorBadData, fmt.Errorf("invalid target state %q", state)}, nil
+	}
+
+	res := &TargetDiscovery{
+		ActiveTargets:  []*Target{},
+		DroppedTargets: []*DroppedTarget{},
+	}
+	if showActive {
+		for _, t := range api.targetRetriever.Targets() {
+			lastErrStr := ""
+			lastErr := t.LastError()
+			if lastErr != nil {
+				lastErrStr = lastErr.Error()
+			}
+
+			globalURL := t.URL()
+			if api.globalURL != nil {
+				globalURL = api.globalURL(globalURL)
+			}
+
+			res.ActiveTargets = append(res.ActiveTargets, &Target{
+				DiscoveredLabels:   t.DiscoveredLabels().Map(),
+				Labels:             t.Labels().Map(),
+				ScrapeURL:          t.URL().String(),
+				GlobalURL:          globalURL.String(),
+				LastError:          lastErrStr,
+				LastScrape:         t.LastScrape(),
+				LastScrapeDuration: t.LastScrapeDuration().Seconds(),
+				Health:             t.Health(),
+			})
+		}
+	}
+	if showDropped {
+		for _, t := range api.targetRetriever.DroppedTargets() {
+			dt := &DroppedTarget{
+				DiscoveredLabels: t.DiscoveredLabels().Map(),
+			}
+			if i, rc := t.DroppedBy(); rc != nil {
+				dt.DroppedBy = &DroppingRelabelConfig{Index: i, Action: rc.Action}
+			}
+			res.DroppedTargets = append(res.DroppedTargets, dt)
+		}
+	}
+
+	return res, nil, nil
 }
 
-func (api *API) targets(r *http.Request) (interface{}, *apiError) {
+// metricMetadata is the metadata of a metric family exposed by a target.
+type metricMetadata struct {
+	Target labels.Labels        `json:"target"`
+	Metric string               `json:"metric,omitempty"`
+	Type   textparse.MetricType `json:"type"`
+	Help   string               `json:"help"`
+	Unit   string               `json:"unit"`
+}
+
+func (api *API) targetMetadata(r *http.Request) (interface{}, *apiError, storage.Warnings) {
+	limit, err := parseLimit(r.FormValue("limit"))
+	if err != nil {
+		return nil, &apiError{errorBadData, err}, nil
+	}
+
+	var matchers []*labels.Matcher
+	if s := r.FormValue("match_target"); s != "" {
+		matchers, err = promql.ParseMetricSelector(s)
+		if err != nil {
+			return nil, &apiError{errorBadData, err}, nil
+		}
+	}
+	metric := r.FormValue("metric")
+
 	targets := api.targetRetriever.Targets()
-	res := &TargetDiscovery{ActiveTargets: make([]*Target, len(targets))}
+	sort.Sort(retrieval.Targets(targets))
+
+	res := []metricMetadata{}
+	for _, t := range targets {
+		if limit >= 0 && len(res) >= limit {
+			break
+		}
+		lset := t.Labels()
+		if !matchLabels(lset, matchers) {
+			continue
+		}
+		// The metric name is omitted if the metadata of a single metric was requested.
+		if metric != "" {
+			if md, ok := t.Metadata(metric); ok {
+				res = append(res, metricMetadata{
+					Target: lset,
+					Type:   md.Type,
+					Help:   md.Help,
+					Unit:   md.Unit,
+				})
+			}
+			continue
+		}
+		mds := t.MetadataList()
+		sort.Slice(mds, func(i, j int) bool { return mds[i].Metric < mds[j].Metric })
+
+		for _, md := range mds {
+			if limit >= 0 && len(res) >= limit {
+				break
+			}
+			res = append(res, metricMetadata{
+				Target: lset,
+				Metric: md.Metric,
+				Type:   md.Type,
+				Help:   md.Help,
+				Unit:   md.Unit,
+			})
+		}
+	}
+	return res, nil, nil
+}
+
+// metadata is the metadata of a metric family, independent of the targets
+// exposing it.
+type metadata struct {
+	Type textparse.MetricType `json:"type"`
+	Help string               `json:"help"`
+	Unit string               `json:"unit"`
+}
+
+func (api *API) metricMetadata(r *http.Request) (interface{}, *apiError, storage.Warnings) {
+	limit, err := parseLimit(r.FormValue("limit"))
+	if err != nil {
+		return nil, &apiError{errorBadData, err}, nil
+	}
+	metric := r.FormValue("metric")
+
+	// Deduplicate the metadata of all targets.
+	metrics := map[string]map[metadata]struct{}{}
+
+	for _, t := range api.targetRetriever.Targets() {
+		for _, md := range t.MetadataList() {
+			if metric != "" && md.Metric != metric {
+				continue
+			}
+			m := metadata{Type: md.Type, Help: md.Help, Unit: md.Unit}
+
+			if _, ok := metrics[md.Metric]; !ok {
+				metrics[md.Metric] = map[metadata]struct{}{}
+			}
+			metrics[md.Metric][m] = struct{}{}
+		}
+	}
 
-	for i, t := range targets {
-		lastErrStr := ""
-		lastErr := t.LastError()
-		if lastErr != nil {
-			lastErrStr = lastErr.Error()
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	res := map[string][]metadata{}
+	for _, name := range names {
+		if limit >= 0 && len(res) >= limit {
+			break
+		}
+		mds := make([]metadata, 0, len(metrics[name]))
+		for m := range metrics[name] {
+			mds = append(mds, m)
 		}
+		sort.Slice(mds, func(i, j int) bool {
+			if mds[i].Type != mds[j].Type {
+				return mds[i].Type < mds[j].Type
+			}
+			if mds[i].Help != mds[j].Help {
+				return mds[i].Help < mds[j].Help
+			}
+			return mds[i].Unit < mds[j].Unit
+		})
+		res[name] = mds
+	}
+	return res, nil, nil
+}
 
-		res.ActiveTargets[i] = &Target{
-			DiscoveredLabels: t.DiscoveredLabels().Map(),
-			Labels:           t.Labels().Map(),
-			ScrapeURL:        t.URL().String(),
-			LastError:        lastErrStr,
-			LastScrape:       t.LastScrape(),
-			Health:           t.Health(),
+// matchLabels returns whether the label set satisfies all matchers.
+func matchLabels(lset labels.Labels, matchers []*labels.Matcher) bool {
+	for _, m := range matchers {
+		if !m.Matches(lset.Get(m.Name)) {
+			return false
 		}
 	}
+	return true
+}
 
-	return res, nil
+// parseLimit parses the maximum number of returned entries. It returns -1
+// if no limit is set.
+func parseLimit(s string) (int, error) {
+	if s == "" {
+		return -1, nil
+	}
+	limit, err := strconv.Atoi(s)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("cannot parse %q to a valid limit", s)
+	}
+	return limit, nil
 }
 
-// AlertmanagerDiscovery has all the active Alertmanagers.
+// AlertmanagerDiscovery has all the active and dropped Alertmanagers.
 type AlertmanagerDiscovery struct {
-	ActiveAlertmanagers []*AlertmanagerTarget `json:"activeAlertmanagers"`
+	ActiveAlertmanagers  []*AlertmanagerTarget `json:"activeAlertmanagers"`
+	DroppedAlertmanagers []*AlertmanagerTarget `json:"droppedAlertmanagers"`
 }
 
 // AlertmanagerTarget has info on one AM.
@@ -435,29 +1039,416 @@ type AlertmanagerTarget struct {
 	URL string `json:"url"`
 }
 
-func (api *API) alertmanagers(r *http.Request) (interface{}, *apiError) {
+func (api *API) alertmanagers(r *http.Request) (interface{}, *apiError, storage.Warnings) {
 	urls := api.alertmanagerRetriever.Alertmanagers()
-	ams := &AlertmanagerDiscovery{ActiveAlertmanagers: make([]*AlertmanagerTarget, len(urls))}
+	droppedURLs := api.alertmanagerRetriever.DroppedAlertmanagers()
+	ams := &AlertmanagerDiscovery{
+		ActiveAlertmanagers:  make([]*AlertmanagerTarget, len(urls)),
+		DroppedAlertmanagers: make([]*AlertmanagerTarget, len(droppedURLs)),
+	}
 
 	for i, url := range urls {
 		ams.ActiveAlertmanagers[i] = &AlertmanagerTarget{URL: url.String()}
 	}
+	for i, url := range droppedURLs {
+		ams.DroppedAlertmanagers[i] = &AlertmanagerTarget{URL: url.String()}
+	}
+
+	return ams, nil, nil
+}
 
-	return ams, nil
+// DiscoveryProvider has info for a service discovery provider.
+type DiscoveryProvider struct {
+	TargetSet    string    `json:"targetSet"`
+	Provider     string    `json:"provider"`
+	LastUpdate   time.Time `json:"lastUpdate"`
+	Updates      int       `json:"updates"`
+	Failures     int       `json:"failures"`
+	TargetGroups int       `json:"targetGroups"`
+	Targets      int       `json:"targets"`
+}
+
+// ServiceDiscovery has info for the service discovery providers of scrape
+// jobs and Alertmanagers.
+type ServiceDiscovery struct {
+	Scrape        []*DiscoveryProvider `json:"scrape"`
+	Alertmanagers []*DiscoveryProvider `json:"alertmanagers"`
+}
+
+func discoveryProviders(dr discoveryRetriever) []*DiscoveryProvider {
+	res := []*DiscoveryProvider{}
+	if dr == nil {
+		return res
+	}
+	for _, s := range dr.ProviderStatus() {
+		res = append(res, &DiscoveryProvider{
+			TargetSet:    s.TargetSet,
+			Provider:     s.Provider,
+			LastUpdate:   s.LastUpdate,
+			Updates:      s.Updates,
+			Failures:     s.Failures,
+			TargetGroups: s.TargetGroups,
+			Targets:      s.Targets,
+		})
+	}
+	return res
+}
+
+func (api *API) serviceDiscovery(r *http.Request) (interface{}, *apiError, storage.Warnings) {
+	return &ServiceDiscovery{
+		Scrape:        discoveryProviders(api.scrapeDiscovery),
+		Alertmanagers: discoveryProviders(api.notifyDiscovery),
+	}, nil, nil
+}
+
+// AlertDiscovery has info for all active alerts.
+type AlertDiscovery struct {
+	Alerts []*Alert `json:"alerts"`
+}
+
+// Alert has info for an alert.
+type Alert struct {
+	Labels      labels.Labels `json:"labels"`
+	Annotations labels.Labels `json:"annotations"`
+	State       string        `json:"state"`
+	ActiveAt    *time.Time    `json:"activeAt,omitempty"`
+	Value       string        `json:"value"`
+}
+
+func (api *API) alerts(r *http.Request) (interface{}, *apiError, storage.Warnings) {
+	alerts := []*Alert{}
+	for _, ar := range api.rulesRetriever.AlertingRules() {
+		alerts = append(alerts, rulesAlertsToAPIAlerts(ar.ActiveAlerts())...)
+	}
+	return &AlertDiscovery{Alerts: alerts}, nil, nil
+}
+
+func rulesAlertsToAPIAlerts(rulesAlerts []*rules.Alert) []*Alert {
+	apiAlerts := make([]*Alert, len(rulesAlerts))
+	for i, ra := range rulesAlerts {
+		activeAt := ra.ActiveAt
+		apiAlerts[i] = &Alert{
+			Labels:      ra.Labels,
+			Annotations: ra.Annotations,
+			State:       ra.State.String(),
+			ActiveAt:    &activeAt,
+			Value:       strconv.FormatFloat(ra.Value, 'f', -1, 64),
+		}
+	}
+	return apiAlerts
+}
+
+// RuleDiscovery has info for all rules.
+type RuleDiscovery struct {
+	RuleGroups []*RuleGroup `json:"groups"`
+}
+
+// RuleGroup has info for rules which are part of a group.
+type RuleGroup struct {
+	Name string `json:"name"`
+	File string `json:"file"`
+	// Rules holds alertingRule and recordingRule values, which are
+	// distinguished by their type field.
+	Rules          []interface{} `json:"rules"`
+	Interval       float64       `json:"interval"`
+	EvaluationTime float64       `json:"evaluationTime"`
+	LastEvaluation time.Time     `json:"lastEvaluation"`
+}
+
+type alertingRule struct {
+	Name           string           `json:"name"`
+	Query          string           `json:"query"`
+	Duration       float64          `json:"duration"`
+	Labels         labels.Labels    `json:"labels"`
+	Annotations    labels.Labels    `json:"annotations"`
+	Alerts         []*Alert         `json:"alerts"`
+	Health         rules.RuleHealth `json:"health"`
+	LastError      string           `json:"lastError,omitempty"`
+	Warnings       []string         `json:"warnings,omitempty"`
+	EvaluationTime float64          `json:"evaluationTime"`
+	LastEvaluation time.Time        `json:"lastEvaluation"`
+	Type           string           `json:"type"`
+}
+
+type recordingRule struct {
+	Name           string           `json:"name"`
+	Query          string           `json:"query"`
+	Labels         labels.Labels    `json:"labels,omitempty"`
+	Health         rules.RuleHealth `json:"health"`
+	LastError      string           `json:"lastError,omitempty"`
+	Warnings       []string         `json:"warnings,omitempty"`
+	EvaluationTime float64          `json:"evaluationTime"`
+	LastEvaluation time.Time        `json:"lastEvaluation"`
+	Type           string           `json:"type"`
+}
+
+func (api *API) rules(r *http.Request) (interface{}, *apiError, storage.Warnings) {
+	ruleGroups := api.rulesRetriever.RuleGroups()
+	res := &RuleDiscovery{RuleGroups: make([]*RuleGroup, len(ruleGroups))}
+
+	for i, grp := range ruleGroups {
+		apiRuleGroup := &RuleGroup{
+			Name:           grp.Name(),
+			File:           grp.File(),
+			Interval:       grp.Interval().Seconds(),
+			Rules:          []interface{}{},
+			EvaluationTime: grp.EvaluationDuration().Seconds(),
+			LastEvaluation: grp.LastEvaluation(),
+		}
+
+		for _, r := range grp.Rules() {
+			var enrichedRule interface{}
+
+			lastError := ""
+			if r.LastError() != nil {
+				lastError = r.LastError().Error()
+			}
+			var warnings []string
+			for _, w := range r.Warnings() {
+				warnings = append(warnings, w.Error())
+			}
+
+			switch rule := r.(type) {
+			case *rules.AlertingRule:
+				enrichedRule = alertingRule{
+					Name:           rule.Name(),
+					Query:          rule.Query().String(),
+					Duration:       rule.Duration().Seconds(),
+					Labels:         rule.Labels(),
+					Annotations:    rule.Annotations(),
+					Alerts:         rulesAlertsToAPIAlerts(rule.ActiveAlerts()),
+					Health:         rule.Health(),
+					LastError:      lastError,
+					Warnings:       warnings,
+					EvaluationTime: rule.EvaluationDuration().Seconds(),
+					LastEvaluation: rule.LastEvaluation(),
+					Type:           "alerting",
+				}
+			case *rules.RecordingRule:
+				enrichedRule = recordingRule{
+					Name:           rule.Name(),
+					Query:          rule.Query().String(),
+					Labels:         rule.Labels(),
+					Health:         rule.Health(),
+					LastError:      lastError,
+					Warnings:       warnings,
+					EvaluationTime: rule.EvaluationDuration().Seconds(),
+					LastEvaluation: rule.LastEvaluation(),
+					Type:           "recording",
+				}
+			default:
+				err := fmt.Errorf("failed to assert type of rule '%v'", rule.Name())
+				return nil, &apiError{errorInternal, err}, nil
+			}
+
+			apiRuleGroup.Rules = append(apiRuleGroup.Rules, enrichedRule)
+		}
+		res.RuleGroups[i] = apiRuleGroup
+	}
+	return res, nil, nil
+}
+
+// maxRuleGroupSize is the maximum size of a rule group written through the
+// API in bytes.
+const maxRuleGroupSize = 1 << 20
+
+func (api *API) setRuleGroup(r *http.Request) (interface{}, *apiError, storage.Warnings) {
+	name := route.Param(r.Context(), "group")
+
+	b, err := ioutil.ReadAll(io.LimitReader(r.Body, maxRuleGroupSize+1))
+	if err != nil {
+		return nil, &apiError{errorInternal, err}, nil
+	}
+	if len(b) > maxRuleGroupSize {
+		return nil, &apiError{errorBadData, fmt.Errorf("rule group exceeds %d bytes", maxRuleGroupSize)}, nil
+	}
+	// JSON bodies are accepted as well as YAML is a superset of JSON.
+	var rg rulefmt.RuleGroup
+	if err := yaml.Unmarshal(b, &rg); err != nil {
+		return nil, &apiError{errorBadData, err}, nil
+	}
+	if rg.Name == "" {
+		rg.Name = name
+	}
+	if rg.Name != name {
+		return nil, &apiError{errorBadData, fmt.Errorf("rule group name %q does not match %q", rg.Name, name)}, nil
+	}
+	rgs := rulefmt.RuleGroups{Groups: []rulefmt.RuleGroup{rg}}
+	if errs := rgs.Validate(); len(errs) > 0 {
+		return nil, &apiError{errorBadData, errs[0]}, nil
+	}
+
+	if err := api.rulesWriter.SetManagedGroup(rg); err != nil {
+		return nil, rulesWriterError(err), nil
+	}
+	api.ClearQueryCache()
+
+	// The rules were validated, so their expressions parse.
+	var warnings storage.Warnings
+	for _, r := range rg.Rules {
+		expr, err := promql.ParseExpr(r.Expr)
+		if err != nil {
+			continue
+		}
+		warnings = append(warnings, promql.Check(expr)...)
+	}
+	return nil, nil, warnings
+}
+
+func (api *API) deleteRuleGroup(r *http.Request) (interface{}, *apiError, storage.Warnings) {
+	if err := api.rulesWriter.DeleteManagedGroup(route.Param(r.Context(), "group")); err != nil {
+		return nil, rulesWriterError(err), nil
+	}
+	api.ClearQueryCache()
+	return nil, nil, nil
+}
+
+func rulesWriterError(err error) *apiError {
+	switch err {
+	case rules.ErrManagedRulesDisabled:
+		return &apiError{errorUnavailable, err}
+	case rules.ErrManagedGroupNotFound:
+		return &apiError{errorNotFound, err}
+	}
+	return &apiError{errorInternal, err}
 }
 
 type prometheusConfig struct {
 	YAML string `json:"yaml"`
 }
 
-func (api *API) serveConfig(r *http.Request) (interface{}, *apiError) {
+func (api *API) serveConfig(r *http.Request) (interface{}, *apiError, storage.Warnings) {
 	cfg := &prometheusConfig{
 		YAML: api.config().String(),
 	}
-	return cfg, nil
+	return cfg, nil, nil
+}
+
+func (api *API) serveRuntimeInfo(r *http.Request) (interface{}, *apiError, storage.Warnings) {
+	return api.runtimeInfo(), nil, nil
+}
+
+func (api *API) serveBuildInfo(r *http.Request) (interface{}, *apiError, storage.Warnings) {
+	return api.buildInfo, nil, nil
+}
+
+func (api *API) serveFlags(r *http.Request) (interface{}, *apiError, storage.Warnings) {
+	return api.flagsMap, nil, nil
+}
+
+// tsdbStatsLimit is the number of top entries returned for each of the ranked
+// TSDB statistics.
+const tsdbStatsLimit = 10
+
+func (api *API) serveTSDBStatus(r *http.Request) (interface{}, *apiError, storage.Warnings) {
+	db := api.db()
+	if db == nil {
+		return nil, &apiError{errorInternal, tsdb.ErrNotReady}, nil
+	}
+	stats, err := tsdb.ComputeHeadStats(db, tsdbStatsLimit)
+	if err != nil {
+		return nil, &apiError{errorInternal, err}, nil
+	}
+	return stats, nil, nil
+}
+
+var errAdminDisabled = errors.New("admin APIs disabled")
+
+var errAgentMode = errors.New("not available in agent mode")
+
+func (api *API) agentModeDisabled(r *http.Request) (interface{}, *apiError, storage.Warnings) {
+	return nil, &apiError{errorUnavailable, errAgentMode}, nil
+}
+
+func (api *API) deleteSeries(r *http.Request) (interface{}, *apiError, storage.Warnings) {
+	if !api.enableAdmin {
+		return nil, &apiError{errorUnavailable, errAdminDisabled}, nil
+	}
+	db := api.db()
+	if db == nil {
+		return nil, &apiError{errorUnavailable, tsdb.ErrNotReady}, nil
+	}
+
+	r.ParseForm()
+	if len(r.Form["match[]"]) == 0 {
+		return nil, &apiError{errorBadData, fmt.Errorf("no match[] parameter provided")}, nil
+	}
+	start, err := parseTimeParam(r, "start", minTime)
+	if err != nil {
+		return nil, &apiError{errorBadData, err}, nil
+	}
+	end, err := parseTimeParam(r, "end", maxTime)
+	if err != nil {
+		return nil, &apiError{errorBadData, err}, nil
+	}
+	if end.Before(start) {
+		return nil, &apiError{errorBadData, fmt.Errorf("end timestamp must not be before start time")}, nil
+	}
+	matcherSets, err := parseMatchersParam(r.Form["match[]"])
+	if err != nil {
+		return nil, &apiError{errorBadData, err}, nil
+	}
+
+	for _, ms := range matcherSets {
+		if err := tsdb.DeleteSeries(db, timestamp.FromTime(start), timestamp.FromTime(end), ms...); err != nil {
+			return nil, &apiError{errorInternal, err}, nil
+		}
+	}
+	// Cached results may contain the deleted samples.
+	api.ClearQueryCache()
+	return nil, nil, nil
+}
+
+func (api *API) snapshot(r *http.Request) (interface{}, *apiError, storage.Warnings) {
+	if !api.enableAdmin {
+		return nil, &apiError{errorUnavailable, errAdminDisabled}, nil
+	}
+	db := api.db()
+	if db == nil {
+		return nil, &apiError{errorUnavailable, tsdb.ErrNotReady}, nil
+	}
+
+	var (
+		snapdir = filepath.Join(db.Dir(), "snapshots")
+		// The timestamp format avoids characters that are not allowed in
+		// file names on all platforms.
+		name = fmt.Sprintf("%s-%x", time.Now().UTC().Format("20060102T150405Z0700"), rand.Int())
+		dir  = filepath.Join(snapdir, name)
+	)
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return nil, &apiError{errorInternal, fmt.Errorf("create snapshot directory: %s", err)}, nil
+	}
+	if err := db.Snapshot(dir); err != nil {
+		// Do not leave partial snapshots behind.
+		os.RemoveAll(dir)
+		return nil, &apiError{errorInternal, fmt.Errorf("create snapshot: %s", err)}, nil
+	}
+	return struct {
+		Name string `json:"name"`
+	}{name}, nil, nil
+}
+
+func (api *API) cleanTombstones(r *http.Request) (interface{}, *apiError, storage.Warnings) {
+	if !api.enableAdmin {
+		return nil, &apiError{errorUnavailable, errAdminDisabled}, nil
+	}
+	db := api.db()
+	if db == nil {
+		return nil, &apiError{errorUnavailable, tsdb.ErrNotReady}, nil
+	}
+
+	if err := tsdb.CleanTombstones(db, nil); err != nil {
+		return nil, &apiError{errorInternal, err}, nil
+	}
+	return nil, nil, nil
 }
 
 func (api *API) remoteRead(w http.ResponseWriter, r *http.Request) {
+	tm, apiErr := api.tenantMatcher(r)
+	if apiErr != nil {
+		http.Error(w, apiErr.err.Error(), http.StatusBadRequest)
+		return
+	}
 	req, err := remote.DecodeReadRequest(r)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -468,7 +1459,7 @@ func (api *API) remoteRead(w http.ResponseWriter, r *http.Request) {
 		Results: make([]*prompb.QueryResult, len(req.Queries)),
 	}
 	for i, query := range req.Queries {
-		from, through, matchers, err := remote.FromQuery(query)
+		from, through, matchers, selectParams, err := remote.FromQuery(query)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
@@ -499,8 +1490,17 @@ func (api *API) remoteRead(w http.ResponseWriter, r *http.Request) {
 				filteredMatchers = append(filteredMatchers, m)
 			}
 		}
+		if tm != nil {
+			filteredMatchers = append(filteredMatchers, tm)
+		}
 
-		resp.Results[i], err = remote.ToQueryResult(querier.Select(filteredMatchers...))
+		// The remote read protocol cannot carry warnings.
+		set, _, err := querier.Select(selectParams, filteredMatchers...)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		resp.Results[i], err = remote.ToQueryResult(set)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -523,12 +1523,60 @@ func (api *API) remoteRead(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	resp.LabelResults = make([]*prompb.LabelQueryResult, len(req.LabelQueries))
+	for i, query := range req.LabelQueries {
+		from, through, name := remote.FromLabelQuery(query)
+
+		querier, err := api.Queryable.Querier(r.Context(), from, through)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer querier.Close()
+
+		var values []string
+		if name == "" {
+			values, err = querier.LabelNames()
+		} else {
+			values, err = querier.LabelValues(name)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// External labels are attached to all series read remotely and
+		// thus have to be reported as well.
+		externalLabels := api.config().GlobalConfig.ExternalLabels
+		if name == "" {
+			for ln := range externalLabels {
+				values = append(values, string(ln))
+			}
+		} else if v, ok := externalLabels[model.LabelName(name)]; ok {
+			values = append(values, string(v))
+		}
+		resp.LabelResults[i] = &prompb.LabelQueryResult{Values: uniqueSortedStrings(values)}
+	}
+
 	if err := remote.EncodeReadResponse(&resp, w); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 }
 
+// uniqueSortedStrings sorts the strings and removes duplicates in place.
+func uniqueSortedStrings(s []string) []string {
+	sort.Strings(s)
+	i := 0
+	for j := range s {
+		if j == 0 || s[j] != s[i-1] {
+			s[i] = s[j]
+			i++
+		}
+	}
+	return s[:i]
+}
+
 // mergeLabels merges two sets of sorted proto labels, preferring those in
 // primary to those in secondary when there is an overlap.
 func mergeLabels(primary, secondary []*prompb.Label) []*prompb.Label {
@@ -556,13 +1604,14 @@ func mergeLabels(primary, secondary []*prompb.Label) []*prompb.Label {
 	return result
 }
 
-func respond(w http.ResponseWriter, data interface{}) {
+func respond(w http.ResponseWriter, data interface{}, warnings storage.Warnings) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
 	b, err := json.Marshal(&response{
-		Status: statusSuccess,
-		Data:   data,
+		Status:   statusSuccess,
+		Data:     data,
+		Warnings: warningStrings(warnings),
 	})
 	if err != nil {
 		return
@@ -570,7 +1619,7 @@ func respond(w http.ResponseWriter, data interface{}) {
 	w.Write(b)
 }
 
-func respondError(w http.ResponseWriter, apiErr *apiError, data interface{}) {
+func respondError(w http.ResponseWriter, apiErr *apiError, data interface{}, warnings storage.Warnings) {
 	w.Header().Set("Content-Type", "application/json")
 
 	var code int
@@ -579,27 +1628,47 @@ func respondError(w http.ResponseWriter, apiErr *apiError, data interface{}) {
 		code = http.StatusBadRequest
 	case errorExec:
 		code = 422
-	case errorCanceled, errorTimeout:
+	case errorCanceled, errorTimeout, errorUnavailable:
 		code = http.StatusServiceUnavailable
 	case errorInternal:
 		code = http.StatusInternalServerError
+	case errorNotFound:
+		code = http.StatusNotFound
 	default:
 		code = http.StatusInternalServerError
 	}
 	w.WriteHeader(code)
 
-	b, err := json.Marshal(&response{
+	resp := &response{
 		Status:    statusError,
 		ErrorType: apiErr.typ,
 		Error:     apiErr.err.Error(),
 		Data:      data,
-	})
+		Warnings:  warningStrings(warnings),
+	}
+	if perr, ok := apiErr.err.(*promql.ParseErr); ok {
+		resp.ErrorPosition = &perr.PositionRange
+	}
+	b, err := json.Marshal(resp)
 	if err != nil {
 		return
 	}
 	w.Write(b)
 }
 
+// warningStrings returns the messages of the warnings, or nil if there
+// are none so that they are omitted from the response.
+func warningStrings(warnings storage.Warnings) []string {
+	if len(warnings) == 0 {
+		return nil
+	}
+	ws := make([]string, 0, len(warnings))
+	for _, w := range warnings {
+		ws = append(ws, w.Error())
+	}
+	return ws
+}
+
 func parseTime(s string) (time.Time, error) {
 	if t, err := strconv.ParseFloat(s, 64); err == nil {
 		s, ns := math.Modf(t)
@@ -611,6 +1680,29 @@ func parseTime(s string) (time.Time, error) {
 	return time.Time{}, fmt.Errorf("cannot parse %q to a valid timestamp", s)
 }
 
+// parseTimeParam parses the time of the named request parameter and returns
+// the default if it is not set.
+func parseTimeParam(r *http.Request, name string, def time.Time) (time.Time, error) {
+	s := r.FormValue(name)
+	if s == "" {
+		return def, nil
+	}
+	return parseTime(s)
+}
+
+// parseMatchersParam parses the label matchers of all match[] parameters.
+func parseMatchersParam(matchers []string) ([][]*labels.Matcher, error) {
+	var matcherSets [][]*labels.Matcher
+	for _, s := range matchers {
+		ms, err := promql.ParseMetricSelector(s)
+		if err != nil {
+			return nil, err
+		}
+		matcherSets = append(matcherSets, ms)
+	}
+	return matcherSets, nil
+}
+
 func parseDuration(s string) (time.Duration, error) {
 	if d, err := strconv.ParseFloat(s, 64); err == nil {
 		ts := d * float64(time.Second)
@@ -23,35 +23,83 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/go-kit/kit/log"
 	"github.com/gogo/protobuf/proto"
 	"github.com/golang/snappy"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/common/route"
+	libtsdb "github.com/prometheus/tsdb"
+	tsdbLabels "github.com/prometheus/tsdb/labels"
 
 	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/discovery"
+	"github.com/prometheus/prometheus/pkg/exemplar"
 	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/rulefmt"
+	"github.com/prometheus/prometheus/pkg/textparse"
 	"github.com/prometheus/prometheus/pkg/timestamp"
 	"github.com/prometheus/prometheus/prompb"
 	"github.com/prometheus/prometheus/promql"
 	"github.com/prometheus/prometheus/retrieval"
+	"github.com/prometheus/prometheus/rules"
+	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/storage/remote"
+	"github.com/prometheus/prometheus/storage/tsdb"
+	"github.com/prometheus/prometheus/util/stats"
 )
 
-type targetRetrieverFunc func() []*retrieval.Target
+type testTargetRetriever struct {
+	active, dropped []*retrieval.Target
+}
+
+func (t testTargetRetriever) Targets() []*retrieval.Target {
+	return t.active
+}
+
+func (t testTargetRetriever) DroppedTargets() []*retrieval.Target {
+	return t.dropped
+}
+
+type testDiscoveryRetriever []discovery.ProviderStatus
+
+func (t testDiscoveryRetriever) ProviderStatus() []discovery.ProviderStatus {
+	return t
+}
 
-func (f targetRetrieverFunc) Targets() []*retrieval.Target {
-	return f()
+// testMetadataStore is a metric metadata store backed by a fixed list.
+type testMetadataStore []retrieval.MetricMetadata
+
+func (s testMetadataStore) ListMetadata() []retrieval.MetricMetadata {
+	return s
+}
+
+func (s testMetadataStore) GetMetadata(metric string) (retrieval.MetricMetadata, bool) {
+	for _, m := range s {
+		if m.Metric == metric {
+			return m, true
+		}
+	}
+	return retrieval.MetricMetadata{}, false
+}
+
+type testAlertmanagerRetriever struct {
+	active, dropped []*url.URL
 }
 
-type alertmanagerRetrieverFunc func() []*url.URL
+func (t testAlertmanagerRetriever) Alertmanagers() []*url.URL {
+	return t.active
+}
 
-func (f alertmanagerRetrieverFunc) Alertmanagers() []*url.URL {
-	return f()
+func (t testAlertmanagerRetriever) DroppedAlertmanagers() []*url.URL {
+	return t.dropped
 }
 
 var samplePrometheusCfg = config.Config{
@@ -81,8 +129,8 @@ func TestEndpoints(t *testing.T) {
 
 	now := time.Now()
 
-	tr := targetRetrieverFunc(func() []*retrieval.Target {
-		return []*retrieval.Target{
+	tr := testTargetRetriever{
+		active: []*retrieval.Target{
 			retrieval.NewTarget(
 				labels.FromMap(map[string]string{
 					model.SchemeLabel:      "http",
@@ -92,25 +140,59 @@ func TestEndpoints(t *testing.T) {
 				nil,
 				url.Values{},
 			),
-		}
-	})
+		},
+		dropped: []*retrieval.Target{
+			retrieval.NewDroppedTarget(
+				labels.FromMap(map[string]string{
+					model.AddressLabel:     "http://dropped.example.com:9115",
+					model.MetricsPathLabel: "/probe",
+					model.SchemeLabel:      "http",
+					model.JobLabel:         "blackbox",
+				}),
+				url.Values{},
+				1,
+				&config.RelabelConfig{Action: config.RelabelKeep},
+			),
+		},
+	}
 
-	ar := alertmanagerRetrieverFunc(func() []*url.URL {
-		return []*url.URL{{
+	ar := testAlertmanagerRetriever{
+		active: []*url.URL{{
 			Scheme: "http",
 			Host:   "alertmanager.example.com:8080",
 			Path:   "/api/v1/alerts",
-		}}
-	})
+		}},
+		dropped: []*url.URL{{
+			Scheme: "http",
+			Host:   "dropped.alertmanager.example.com:8080",
+			Path:   "/api/v1/alerts",
+		}},
+	}
+
+	sdr := testDiscoveryRetriever{
+		{TargetSet: "node", Provider: "consul/0", LastUpdate: now, Updates: 3, Failures: 1, TargetGroups: 2, Targets: 5},
+	}
 
 	api := &API{
 		Queryable:             suite.Storage(),
 		QueryEngine:           suite.QueryEngine(),
 		targetRetriever:       tr,
 		alertmanagerRetriever: ar,
-		now:    func() time.Time { return now },
-		config: func() config.Config { return samplePrometheusCfg },
-		ready:  func(f http.HandlerFunc) http.HandlerFunc { return f },
+		scrapeDiscovery:       sdr,
+		now:                   func() time.Time { return now },
+		config:                func() config.Config { return samplePrometheusCfg },
+		flagsMap: map[string]string{
+			"storage.tsdb.retention": "15d",
+		},
+		buildInfo: &PrometheusVersion{
+			Version:   "2.0.0",
+			Revision:  "abcdef",
+			GoVersion: "go1.9",
+		},
+		runtimeInfo: func() RuntimeInfo {
+			return RuntimeInfo{StartTime: now, CWD: "/prometheus", StorageRetention: "15d"}
+		},
+		ready: func(f http.HandlerFunc) http.HandlerFunc { return f },
 	}
 
 	start := time.Unix(0, 0)
@@ -299,6 +381,28 @@ func TestEndpoints(t *testing.T) {
 				"boo",
 			},
 		},
+		// Label values outside of the stored time range.
+		{
+			endpoint: api.labelValues,
+			params: map[string]string{
+				"name": "foo",
+			},
+			query: url.Values{
+				"start": []string{"-2"},
+				"end":   []string{"-1"},
+			},
+			response: []string{},
+		},
+		{
+			endpoint: api.labelValues,
+			params: map[string]string{
+				"name": "foo",
+			},
+			query: url.Values{
+				"end": []string{"foo"},
+			},
+			errType: errorBadData,
+		},
 		// Bad name parameter.
 		{
 			endpoint: api.labelValues,
@@ -307,6 +411,49 @@ func TestEndpoints(t *testing.T) {
 			},
 			errType: errorBadData,
 		},
+		{
+			endpoint: api.labelNames,
+			response: []string{"__name__", "foo"},
+		},
+		{
+			endpoint: api.labelNames,
+			query: url.Values{
+				"match[]": []string{`test_metric2`},
+			},
+			response: []string{"__name__", "foo"},
+		},
+		// Label names outside of the stored time range.
+		{
+			endpoint: api.labelNames,
+			query: url.Values{
+				"start": []string{"100000"},
+				"end":   []string{"100001"},
+			},
+			response: []string{},
+		},
+		{
+			endpoint: api.labelNames,
+			query: url.Values{
+				"start":   []string{"100000"},
+				"match[]": []string{`test_metric2`},
+			},
+			response: []string{},
+		},
+		// Invalid parameters.
+		{
+			endpoint: api.labelNames,
+			query: url.Values{
+				"match[]": []string{`{foo=""}`},
+			},
+			errType: errorBadData,
+		},
+		{
+			endpoint: api.labelNames,
+			query: url.Values{
+				"start": []string{"foo"},
+			},
+			errType: errorBadData,
+		},
 		{
 			endpoint: api.series,
 			query: url.Values{
@@ -428,10 +575,67 @@ func TestEndpoints(t *testing.T) {
 						DiscoveredLabels: map[string]string{},
 						Labels:           map[string]string{},
 						ScrapeURL:        "http://example.com:8080/metrics",
+						GlobalURL:        "http://example.com:8080/metrics",
+						Health:           "unknown",
+					},
+				},
+				DroppedTargets: []*DroppedTarget{
+					{
+						DiscoveredLabels: map[string]string{
+							"__address__":      "http://dropped.example.com:9115",
+							"__metrics_path__": "/probe",
+							"__scheme__":       "http",
+							"job":              "blackbox",
+						},
+						DroppedBy: &DroppingRelabelConfig{Index: 1, Action: "keep"},
+					},
+				},
+			},
+		},
+		{
+			endpoint: api.targets,
+			query: url.Values{
+				"state": []string{"active"},
+			},
+			response: &TargetDiscovery{
+				ActiveTargets: []*Target{
+					{
+						DiscoveredLabels: map[string]string{},
+						Labels:           map[string]string{},
+						ScrapeURL:        "http://example.com:8080/metrics",
+						GlobalURL:        "http://example.com:8080/metrics",
 						Health:           "unknown",
 					},
 				},
+				DroppedTargets: []*DroppedTarget{},
+			},
+		},
+		{
+			endpoint: api.targets,
+			query: url.Values{
+				"state": []string{"dropped"},
+			},
+			response: &TargetDiscovery{
+				ActiveTargets: []*Target{},
+				DroppedTargets: []*DroppedTarget{
+					{
+						DiscoveredLabels: map[string]string{
+							"__address__":      "http://dropped.example.com:9115",
+							"__metrics_path__": "/probe",
+							"__scheme__":       "http",
+							"job":              "blackbox",
+						},
+						DroppedBy: &DroppingRelabelConfig{Index: 1, Action: "keep"},
+					},
+				},
+			},
+		},
+		{
+			endpoint: api.targets,
+			query: url.Values{
+				"state": []string{"unknown"},
 			},
+			errType: errorBadData,
 		},
 		{
 			endpoint: api.alertmanagers,
@@ -441,6 +645,28 @@ func TestEndpoints(t *testing.T) {
 						URL: "http://alertmanager.example.com:8080/api/v1/alerts",
 					},
 				},
+				DroppedAlertmanagers: []*AlertmanagerTarget{
+					{
+						URL: "http://dropped.alertmanager.example.com:8080/api/v1/alerts",
+					},
+				},
+			},
+		},
+		{
+			endpoint: api.serviceDiscovery,
+			response: &ServiceDiscovery{
+				Scrape: []*DiscoveryProvider{
+					{
+						TargetSet:    "node",
+						Provider:     "consul/0",
+						LastUpdate:   now,
+						Updates:      3,
+						Failures:     1,
+						TargetGroups: 2,
+						Targets:      5,
+					},
+				},
+				Alertmanagers: []*DiscoveryProvider{},
 			},
 		},
 		{
@@ -449,6 +675,28 @@ func TestEndpoints(t *testing.T) {
 				YAML: samplePrometheusCfg.String(),
 			},
 		},
+		{
+			endpoint: api.serveFlags,
+			response: map[string]string{
+				"storage.tsdb.retention": "15d",
+			},
+		},
+		{
+			endpoint: api.serveBuildInfo,
+			response: &PrometheusVersion{
+				Version:   "2.0.0",
+				Revision:  "abcdef",
+				GoVersion: "go1.9",
+			},
+		},
+		{
+			endpoint: api.serveRuntimeInfo,
+			response: RuntimeInfo{
+				StartTime:        now,
+				CWD:              "/prometheus",
+				StorageRetention: "15d",
+			},
+		},
 	}
 
 	methods := func(f apiFunc) []string {
@@ -481,7 +729,7 @@ func TestEndpoints(t *testing.T) {
 			if err != nil {
 				t.Fatal(err)
 			}
-			resp, apiErr := test.endpoint(req.WithContext(ctx))
+			resp, apiErr, _ := test.endpoint(req.WithContext(ctx))
 			if apiErr != nil {
 				if test.errType == errorNone {
 					t.Fatalf("Unexpected error: %s", apiErr)
@@ -501,85 +749,1148 @@ func TestEndpoints(t *testing.T) {
 	}
 }
 
-func TestReadEndpoint(t *testing.T) {
-	suite, err := promql.NewTest(t, `
-		load 1m
-			test_metric1{foo="bar",baz="qux"} 1
-	`)
-	if err != nil {
-		t.Fatal(err)
+func TestMetadataEndpoints(t *testing.T) {
+	newTarget := func(job, addr string, md testMetadataStore) *retrieval.Target {
+		t := retrieval.NewTarget(
+			labels.FromMap(map[string]string{
+				model.JobLabel:         job,
+				model.SchemeLabel:      "http",
+				model.AddressLabel:     addr,
+				model.MetricsPathLabel: "/metrics",
+			}),
+			nil,
+			url.Values{},
+		)
+		t.SetMetadataStore(md)
+		return t
 	}
-	defer suite.Close()
-
-	if err := suite.Run(); err != nil {
-		t.Fatal(err)
+	targets := []*retrieval.Target{
+		newTarget("prometheus", "localhost:9090", testMetadataStore{
+			{Metric: "go_threads", Type: textparse.MetricTypeGauge, Help: "Number of OS threads created."},
+			{Metric: "prometheus_tsdb_head_samples_appended_total", Type: textparse.MetricTypeCounter, Help: "Total number of appended samples."},
+		}),
+		newTarget("node", "localhost:9100", testMetadataStore{
+			{Metric: "go_threads", Type: textparse.MetricTypeGauge, Help: "Number of OS threads created."},
+			{Metric: "node_cpu_seconds_total", Type: textparse.MetricTypeCounter, Help: "Seconds the CPUs spent in each mode.", Unit: "seconds"},
+		}),
 	}
 
 	api := &API{
-		Queryable:   suite.Storage(),
-		QueryEngine: suite.QueryEngine(),
-		config: func() config.Config {
-			return config.Config{
-				GlobalConfig: config.GlobalConfig{
-					ExternalLabels: model.LabelSet{
-						"baz": "a",
-						"b":   "c",
-						"d":   "e",
-					},
+		targetRetriever: testTargetRetriever{active: targets},
+	}
+
+	nodeLabels := labels.FromStrings(model.JobLabel, "node")
+	promLabels := labels.FromStrings(model.JobLabel, "prometheus")
+
+	var tests = []struct {
+		endpoint apiFunc
+		query    url.Values
+		response interface{}
+		errType  errorType
+	}{
+		{
+			endpoint: api.targetMetadata,
+			response: []metricMetadata{
+				{Target: promLabels, Metric: "go_threads", Type: textparse.MetricTypeGauge, Help: "Number of OS threads created."},
+				{Target: promLabels, Metric: "prometheus_tsdb_head_samples_appended_total", Type: textparse.MetricTypeCounter, Help: "Total number of appended samples."},
+				{Target: nodeLabels, Metric: "go_threads", Type: textparse.MetricTypeGauge, Help: "Number of OS threads created."},
+				{Target: nodeLabels, Metric: "node_cpu_seconds_total", Type: textparse.MetricTypeCounter, Help: "Seconds the CPUs spent in each mode.", Unit: "seconds"},
+			},
+		},
+		{
+			endpoint: api.targetMetadata,
+			query:    url.Values{"match_target": []string{`{job="node"}`}},
+			response: []metricMetadata{
+				{Target: nodeLabels, Metric: "go_threads", Type: textparse.MetricTypeGauge, Help: "Number of OS threads created."},
+				{Target: nodeLabels, Metric: "node_cpu_seconds_total", Type: textparse.MetricTypeCounter, Help: "Seconds the CPUs spent in each mode.", Unit: "seconds"},
+			},
+		},
+		{
+			endpoint: api.targetMetadata,
+			query:    url.Values{"metric": []string{"go_threads"}},
+			response: []metricMetadata{
+				{Target: promLabels, Type: textparse.MetricTypeGauge, Help: "Number of OS threads created."},
+				{Target: nodeLabels, Type: textparse.MetricTypeGauge, Help: "Number of OS threads created."},
+			},
+		},
+		{
+			endpoint: api.targetMetadata,
+			query:    url.Values{"limit": []string{"1"}},
+			response: []metricMetadata{
+				{Target: promLabels, Metric: "go_threads", Type: textparse.MetricTypeGauge, Help: "Number of OS threads created."},
+			},
+		},
+		{
+			endpoint: api.targetMetadata,
+			query:    url.Values{"metric": []string{"does_not_exist"}},
+			response: []metricMetadata{},
+		},
+		{
+			endpoint: api.targetMetadata,
+			query:    url.Values{"match_target": []string{`{job=}`}},
+			errType:  errorBadData,
+		},
+		{
+			endpoint: api.targetMetadata,
+			query:    url.Values{"limit": []string{"-1"}},
+			errType:  errorBadData,
+		},
+		{
+			endpoint: api.metricMetadata,
+			response: map[string][]metadata{
+				"go_threads": {
+					{Type: textparse.MetricTypeGauge, Help: "Number of OS threads created."},
 				},
-			}
+				"node_cpu_seconds_total": {
+					{Type: textparse.MetricTypeCounter, Help: "Seconds the CPUs spent in each mode.", Unit: "seconds"},
+				},
+				"prometheus_tsdb_head_samples_appended_total": {
+					{Type: textparse.MetricTypeCounter, Help: "Total number of appended samples."},
+				},
+			},
+		},
+		{
+			endpoint: api.metricMetadata,
+			query:    url.Values{"limit": []string{"2"}},
+			response: map[string][]metadata{
+				"go_threads": {
+					{Type: textparse.MetricTypeGauge, Help: "Number of OS threads created."},
+				},
+				"node_cpu_seconds_total": {
+					{Type: textparse.MetricTypeCounter, Help: "Seconds the CPUs spent in each mode.", Unit: "seconds"},
+				},
+			},
+		},
+		{
+			endpoint: api.metricMetadata,
+			query:    url.Values{"metric": []string{"node_cpu_seconds_total"}},
+			response: map[string][]metadata{
+				"node_cpu_seconds_total": {
+					{Type: textparse.MetricTypeCounter, Help: "Seconds the CPUs spent in each mode.", Unit: "seconds"},
+				},
+			},
+		},
+		{
+			endpoint: api.metricMetadata,
+			query:    url.Values{"limit": []string{"foo"}},
+			errType:  errorBadData,
 		},
 	}
 
-	// Encode the request.
-	matcher1, err := labels.NewMatcher(labels.MatchEqual, "__name__", "test_metric1")
-	if err != nil {
-		t.Fatal(err)
-	}
-	matcher2, err := labels.NewMatcher(labels.MatchEqual, "d", "e")
-	if err != nil {
-		t.Fatal(err)
-	}
-	query, err := remote.ToQuery(0, 1, []*labels.Matcher{matcher1, matcher2})
-	if err != nil {
-		t.Fatal(err)
+	for _, test := range tests {
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://example.com?%s", test.query.Encode()), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, apiErr, _ := test.endpoint(req)
+		if apiErr != nil {
+			if test.errType == errorNone {
+				t.Fatalf("Unexpected error: %s", apiErr)
+			}
+			if test.errType != apiErr.typ {
+				t.Fatalf("Expected error of type %q but got type %q", test.errType, apiErr.typ)
+			}
+			continue
+		}
+		if test.errType != errorNone {
+			t.Fatalf("Expected error of type %q but got none", test.errType)
+		}
+		if !reflect.DeepEqual(resp, test.response) {
+			t.Fatalf("Response does not match, expected:\n%+v\ngot:\n%+v", test.response, resp)
+		}
 	}
-	req := &prompb.ReadRequest{Queries: []*prompb.Query{query}}
-	data, err := proto.Marshal(req)
-	if err != nil {
-		t.Fatal(err)
+}
+
+type rulesRetrieverMock []*rules.Group
+
+func (m rulesRetrieverMock) RuleGroups() []*rules.Group {
+	return m
+}
+
+func (m rulesRetrieverMock) AlertingRules() []*rules.AlertingRule {
+	var ars []*rules.AlertingRule
+	for _, g := range m {
+		for _, r := range g.Rules() {
+			if ar, ok := r.(*rules.AlertingRule); ok {
+				ars = append(ars, ar)
+			}
+		}
 	}
-	compressed := snappy.Encode(nil, data)
-	request, err := http.NewRequest("POST", "", bytes.NewBuffer(compressed))
+	return ars
+}
+
+func TestRulesEndpoints(t *testing.T) {
+	suite, err := promql.NewTest(t, `
+		load 1m
+			test_metric1{foo="bar"} 0+100x100
+	`)
 	if err != nil {
 		t.Fatal(err)
 	}
-	recorder := httptest.NewRecorder()
-	api.remoteRead(recorder, request)
+	defer suite.Close()
 
-	// Decode the response.
-	compressed, err = ioutil.ReadAll(recorder.Result().Body)
-	if err != nil {
+	if err := suite.Run(); err != nil {
 		t.Fatal(err)
 	}
-	uncompressed, err := snappy.Decode(nil, compressed)
+
+	expr, err := promql.ParseExpr(`test_metric1 > 0`)
 	if err != nil {
 		t.Fatal(err)
 	}
+	alertRule := rules.NewAlertingRule("test_alert", expr, 5*time.Minute,
+		labels.FromStrings("severity", "page"), labels.FromStrings("summary", "test"), nil)
 
-	var resp prompb.ReadResponse
-	err = proto.Unmarshal(uncompressed, &resp)
+	expr, err = promql.ParseExpr(`"foo"`)
 	if err != nil {
 		t.Fatal(err)
 	}
+	recordRule := rules.NewRecordingRule("test_record", expr, nil)
 
-	if len(resp.Results) != 1 {
-		t.Fatalf("Expected 1 result, got %d", len(resp.Results))
-	}
+	group := rules.NewGroup("grp", "/path/to/file", time.Minute, []rules.Rule{alertRule, recordRule}, &rules.ManagerOptions{
+		QueryEngine: suite.QueryEngine(),
+		Appendable:  suite.Storage(),
+		Context:     context.Background(),
+		Logger:      log.NewNopLogger(),
+	})
+	ts := time.Unix(120, 0)
+	group.Eval(ts)
 
-	result := resp.Results[0]
-	expected := &prompb.QueryResult{
-		Timeseries: []*prompb.TimeSeries{
+	api := &API{rulesRetriever: rulesRetrieverMock{group}}
+
+	res, apiErr, _ := api.alerts(&http.Request{})
+	if apiErr != nil {
+		t.Fatalf("Unexpected error: %s", apiErr)
+	}
+	expAlerts := &AlertDiscovery{
+		Alerts: []*Alert{
+			{
+				// The metric name is dropped by the comparison operator.
+				Labels:      labels.FromStrings("alertname", "test_alert", "foo", "bar", "severity", "page"),
+				Annotations: labels.FromStrings("summary", "test"),
+				State:       "pending",
+				ActiveAt:    &ts,
+				Value:       "200",
+			},
+		},
+	}
+	if !reflect.DeepEqual(expAlerts, res) {
+		t.Fatalf("Alerts do not match, expected:\n%+v\ngot:\n%+v", expAlerts, res)
+	}
+
+	res, apiErr, _ = api.rules(&http.Request{})
+	if apiErr != nil {
+		t.Fatalf("Unexpected error: %s", apiErr)
+	}
+	groups := res.(*RuleDiscovery).RuleGroups
+	if len(groups) != 1 {
+		t.Fatalf("Expected 1 rule group, got %d", len(groups))
+	}
+	g := groups[0]
+	if g.Name != "grp" || g.File != "/path/to/file" || g.Interval != 60 || len(g.Rules) != 2 {
+		t.Fatalf("Unexpected rule group %+v", g)
+	}
+
+	ar := g.Rules[0].(alertingRule)
+	ar.EvaluationTime, ar.LastEvaluation = 0, time.Time{}
+	expAlertRule := alertingRule{
+		Name:        "test_alert",
+		Query:       "test_metric1 > 0",
+		Duration:    300,
+		Labels:      labels.FromStrings("severity", "page"),
+		Annotations: labels.FromStrings("summary", "test"),
+		Alerts:      expAlerts.Alerts,
+		Health:      rules.HealthGood,
+		Type:        "alerting",
+	}
+	if !reflect.DeepEqual(expAlertRule, ar) {
+		t.Fatalf("Alerting rule does not match, expected:\n%+v\ngot:\n%+v", expAlertRule, ar)
+	}
+
+	rr := g.Rules[1].(recordingRule)
+	rr.EvaluationTime, rr.LastEvaluation = 0, time.Time{}
+	expRecordRule := recordingRule{
+		Name:      "test_record",
+		Query:     `"foo"`,
+		Health:    rules.HealthBad,
+		LastError: "rule result is not a vector or scalar",
+		Type:      "recording",
+	}
+	if !reflect.DeepEqual(expRecordRule, rr) {
+		t.Fatalf("Recording rule does not match, expected:\n%+v\ngot:\n%+v", expRecordRule, rr)
+	}
+}
+
+type rulesWriterMock map[string]rulefmt.RuleGroup
+
+func (m rulesWriterMock) SetManagedGroup(rg rulefmt.RuleGroup) error {
+	m[rg.Name] = rg
+	return nil
+}
+
+func (m rulesWriterMock) DeleteManagedGroup(name string) error {
+	if _, ok := m[name]; !ok {
+		return rules.ErrManagedGroupNotFound
+	}
+	delete(m, name)
+	return nil
+}
+
+func TestRuleGroupWrites(t *testing.T) {
+	groups := rulesWriterMock{}
+	api := &API{rulesWriter: groups}
+
+	request := func(method, group, body string) *http.Request {
+		r, err := http.NewRequest(method, "http://example.com/api/v1/rules/"+group, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r.WithContext(route.WithParam(r.Context(), "group", group))
+	}
+
+	// JSON and YAML bodies are accepted and the name defaults to the path.
+	_, apiErr, _ := api.setRuleGroup(request("PUT", "a", `{"rules": [{"record": "one", "expr": "vector(1)"}]}`))
+	if apiErr != nil {
+		t.Fatalf("Unexpected error: %s", apiErr)
+	}
+	_, apiErr, _ = api.setRuleGroup(request("PUT", "b", "name: b\nrules:\n- alert: Down\n  expr: up == 0\n  for: 5m\n"))
+	if apiErr != nil {
+		t.Fatalf("Unexpected error: %s", apiErr)
+	}
+	if len(groups) != 2 || groups["a"].Rules[0].Record != "one" || groups["b"].Rules[0].Alert != "Down" {
+		t.Fatalf("Unexpected rule groups %+v", groups)
+	}
+
+	// Suspicious vector matching is accepted with a warning.
+	_, apiErr, warnings := api.setRuleGroup(request("PUT", "c", `{"rules": [{"record": "ratio", "expr": "sum by(job)(a) / sum(b)"}]}`))
+	if apiErr != nil {
+		t.Fatalf("Unexpected error: %s", apiErr)
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("Expected a warning about the vector matching, got %v", warnings)
+	}
+	if _, apiErr, _ = api.deleteRuleGroup(request("DELETE", "c", "")); apiErr != nil {
+		t.Fatalf("Unexpected error: %s", apiErr)
+	}
+
+	for _, body := range []string{
+		`{"name": "c", "rules": []}`,
+		`{"rules": [{"record": "one", "expr": "vector("}]}`,
+		`{"rules": [{"record": "one", "expr": "vector(1)", "unknown": 1}]}`,
+		`rules: [`,
+	} {
+		_, apiErr, _ = api.setRuleGroup(request("PUT", "a", body))
+		if apiErr == nil || apiErr.typ != errorBadData {
+			t.Fatalf("Expected bad data error for %s, got %v", body, apiErr)
+		}
+	}
+
+	if _, apiErr, _ = api.deleteRuleGroup(request("DELETE", "a", "")); apiErr != nil {
+		t.Fatalf("Unexpected error: %s", apiErr)
+	}
+	if _, apiErr, _ = api.deleteRuleGroup(request("DELETE", "a", "")); apiErr == nil || apiErr.typ != errorNotFound {
+		t.Fatalf("Expected not found error, got %v", apiErr)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("Unexpected rule groups %+v", groups)
+	}
+}
+
+func TestTSDBStatus(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tsdb-status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The storage is not ready yet.
+	api := &API{db: func() *libtsdb.DB { return nil }}
+	if _, apiErr, _ := api.serveTSDBStatus(&http.Request{}); apiErr == nil {
+		t.Fatal("Expected error for unavailable TSDB")
+	}
+
+	db, err := libtsdb.Open(dir, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	app := db.Appender()
+	for _, lset := range []tsdbLabels.Labels{
+		tsdbLabels.FromStrings("__name__", "a", "job", "foo", "instance", "1"),
+		tsdbLabels.FromStrings("__name__", "a", "job", "foo", "instance", "2"),
+		tsdbLabels.FromStrings("__name__", "a", "job", "bar", "instance", "1"),
+		tsdbLabels.FromStrings("__name__", "bb", "job", "foo"),
+	} {
+		if _, err := app.Add(lset, 1000, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := app.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	api = &API{db: func() *libtsdb.DB { return db }}
+	res, apiErr, _ := api.serveTSDBStatus(&http.Request{})
+	if apiErr != nil {
+		t.Fatalf("Unexpected error: %s", apiErr)
+	}
+	exp := &tsdb.HeadStats{
+		NumSeries: 4,
+		NumChunks: 4,
+		SeriesCountByMetricName: []tsdb.Stat{
+			{Name: "a", Value: 3},
+			{Name: "bb", Value: 1},
+		},
+		LabelValueCountByLabelName: []tsdb.Stat{
+			{Name: "__name__", Value: 2},
+			{Name: "instance", Value: 2},
+			{Name: "job", Value: 2},
+		},
+		MemoryInBytesByLabelName: []tsdb.Stat{
+			{Name: "job", Value: 6},
+			{Name: "__name__", Value: 3},
+			{Name: "instance", Value: 2},
+		},
+		SeriesCountByLabelValuePair: []tsdb.Stat{
+			{Name: "__name__=a", Value: 3},
+			{Name: "job=foo", Value: 3},
+			{Name: "instance=1", Value: 2},
+			{Name: "__name__=bb", Value: 1},
+			{Name: "instance=2", Value: 1},
+			{Name: "job=bar", Value: 1},
+		},
+	}
+	if !reflect.DeepEqual(exp, res) {
+		t.Fatalf("Response does not match, expected:\n%+v\ngot:\n%+v", exp, res)
+	}
+}
+
+func TestQueryExemplars(t *testing.T) {
+	es := storage.NewCircularExemplarStorage(10, nil)
+	for _, x := range []struct {
+		lset labels.Labels
+		e    exemplar.Exemplar
+	}{
+		{
+			lset: labels.FromStrings("__name__", "requests_bucket", "job", "foo", "le", "0.5"),
+			e:    exemplar.Exemplar{Labels: labels.FromStrings("trace_id", "a"), Value: 0.3, Ts: 1000},
+		},
+		{
+			lset: labels.FromStrings("__name__", "requests_bucket", "job", "bar", "le", "0.5"),
+			e:    exemplar.Exemplar{Labels: labels.FromStrings("trace_id", "b"), Value: 0.4, Ts: 2000},
+		},
+		{
+			lset: labels.FromStrings("__name__", "errors_total", "job", "foo"),
+			e:    exemplar.Exemplar{Labels: labels.FromStrings("trace_id", "c"), Value: 1, Ts: 3000},
+		},
+	} {
+		if err := es.AddExemplar(x.lset, x.e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	api := &API{exemplars: es}
+
+	request := func(query url.Values) *http.Request {
+		r, err := http.NewRequest("GET", "http://example.com?"+query.Encode(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	for _, query := range []url.Values{
+		{"query": []string{"rate("}},
+		{"query": []string{"up"}, "start": []string{"10"}, "end": []string{"5"}},
+		{"query": []string{"up"}, "start": []string{"invalid"}},
+	} {
+		if _, apiErr, _ := api.queryExemplars(request(query)); apiErr == nil || apiErr.typ != errorBadData {
+			t.Fatalf("Expected error of type %q for %v, got %v", errorBadData, query, apiErr)
+		}
+	}
+
+	cases := []struct {
+		query    url.Values
+		response interface{}
+	}{
+		{
+			query: url.Values{"query": []string{`sum(rate(requests_bucket{job="foo"}[5m])) / rate(errors_total[5m])`}},
+			response: []exemplar.QueryResult{
+				{
+					SeriesLabels: labels.FromStrings("__name__", "errors_total", "job", "foo"),
+					Exemplars:    []exemplar.Exemplar{{Labels: labels.FromStrings("trace_id", "c"), Value: 1, Ts: 3000}},
+				},
+				{
+					SeriesLabels: labels.FromStrings("__name__", "requests_bucket", "job", "foo", "le", "0.5"),
+					Exemplars:    []exemplar.Exemplar{{Labels: labels.FromStrings("trace_id", "a"), Value: 0.3, Ts: 1000}},
+				},
+			},
+		},
+		{
+			query: url.Values{"query": []string{"requests_bucket"}, "start": []string{"1.5"}, "end": []string{"2.5"}},
+			response: []exemplar.QueryResult{
+				{
+					SeriesLabels: labels.FromStrings("__name__", "requests_bucket", "job", "bar", "le", "0.5"),
+					Exemplars:    []exemplar.Exemplar{{Labels: labels.FromStrings("trace_id", "b"), Value: 0.4, Ts: 2000}},
+				},
+			},
+		},
+		{
+			query:    url.Values{"query": []string{"up"}},
+			response: []exemplar.QueryResult{},
+		},
+	}
+	for i, c := range cases {
+		res, apiErr, _ := api.queryExemplars(request(c.query))
+		if apiErr != nil {
+			t.Fatalf("%d: unexpected error: %s", i, apiErr)
+		}
+		if !reflect.DeepEqual(c.response, res) {
+			t.Fatalf("%d: response does not match, expected:\n%+v\ngot:\n%+v", i, c.response, res)
+		}
+	}
+
+	// Without an exemplar storage no exemplars are returned.
+	api = &API{}
+	res, apiErr, _ := api.queryExemplars(request(url.Values{"query": []string{"requests_bucket"}}))
+	if apiErr != nil {
+		t.Fatalf("Unexpected error: %s", apiErr)
+	}
+	if !reflect.DeepEqual([]exemplar.QueryResult{}, res) {
+		t.Fatalf("Expected no exemplars, got %+v", res)
+	}
+}
+
+func TestAdminEndpoints(t *testing.T) {
+	dir, err := ioutil.TempDir("", "admin-api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := libtsdb.Open(dir, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	app := db.Appender()
+	for _, lset := range []tsdbLabels.Labels{
+		tsdbLabels.FromStrings("__name__", "a", "job", "foo"),
+		tsdbLabels.FromStrings("__name__", "b", "job", "bar"),
+	} {
+		if _, err := app.Add(lset, 1000, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := app.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	request := func(query url.Values) *http.Request {
+		r, err := http.NewRequest("POST", "http://example.com?"+query.Encode(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	endpoints := map[string]func(*API) apiFunc{
+		"delete_series":    func(api *API) apiFunc { return api.deleteSeries },
+		"snapshot":         func(api *API) apiFunc { return api.snapshot },
+		"clean_tombstones": func(api *API) apiFunc { return api.cleanTombstones },
+	}
+
+	// The endpoints are unavailable if the admin APIs are disabled or the
+	// storage is not ready yet.
+	for name, f := range endpoints {
+		for _, api := range []*API{
+			{db: func() *libtsdb.DB { return db }},
+			{db: func() *libtsdb.DB { return nil }, enableAdmin: true},
+		} {
+			_, apiErr, _ := f(api)(request(url.Values{"match[]": []string{"a"}}))
+			if apiErr == nil || apiErr.typ != errorUnavailable {
+				t.Fatalf("%s: expected error of type %q, got %v", name, errorUnavailable, apiErr)
+			}
+		}
+	}
+
+	api := &API{db: func() *libtsdb.DB { return db }, enableAdmin: true}
+
+	for _, query := range []url.Values{
+		{},
+		{"match[]": []string{"invalid{"}},
+		{"match[]": []string{"a"}, "start": []string{"10"}, "end": []string{"5"}},
+	} {
+		if _, apiErr, _ := api.deleteSeries(request(query)); apiErr == nil || apiErr.typ != errorBadData {
+			t.Fatalf("Expected error of type %q for %v, got %v", errorBadData, query, apiErr)
+		}
+	}
+	if _, apiErr, _ := api.deleteSeries(request(url.Values{"match[]": []string{`{job="foo"}`}})); apiErr != nil {
+		t.Fatalf("Unexpected error: %s", apiErr)
+	}
+	q, err := db.Querier(0, 2000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for set := q.Select(tsdbLabels.NewEqualMatcher("job", "foo")); set.Next(); {
+		if it := set.At().Iterator(); it.Next() {
+			names = append(names, set.At().Labels().Get("__name__"))
+		}
+	}
+	q.Close()
+	if len(names) != 0 {
+		t.Fatalf("Expected deleted series to have no samples, got %v", names)
+	}
+
+	res, apiErr, _ := api.snapshot(request(nil))
+	if apiErr != nil {
+		t.Fatalf("Unexpected error: %s", apiErr)
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var snap struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(b, &snap); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "snapshots", snap.Name)); err != nil {
+		t.Fatalf("Snapshot %q was not created: %s", snap.Name, err)
+	}
+
+	if _, apiErr, _ := api.cleanTombstones(request(nil)); apiErr != nil {
+		t.Fatalf("Unexpected error: %s", apiErr)
+	}
+}
+
+func TestQueryStats(t *testing.T) {
+	suite, err := promql.NewTest(t, `
+		load 1m
+			test_metric1{foo="bar"} 0+100x100
+			test_metric1{foo="boo"} 1+0x100
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer suite.Close()
+
+	if err := suite.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	api := &API{
+		Queryable:   suite.Storage(),
+		QueryEngine: suite.QueryEngine(),
+	}
+
+	for _, c := range []struct {
+		endpoint apiFunc
+		query    url.Values
+		samples  *stats.QuerySamples
+	}{
+		{
+			endpoint: api.query,
+			query: url.Values{
+				"query": []string{"test_metric1"},
+				"time":  []string{"120"},
+			},
+		},
+		{
+			endpoint: api.query,
+			query: url.Values{
+				"query": []string{"test_metric1[2m]"},
+				"time":  []string{"120"},
+				"stats": []string{"all"},
+			},
+			samples: &stats.QuerySamples{Series: 2, TotalSamples: 6, PeakSamples: 6},
+		},
+		{
+			endpoint: api.queryRange,
+			query: url.Values{
+				"query": []string{"test_metric1"},
+				"start": []string{"0"},
+				"end":   []string{"120"},
+				"step":  []string{"60"},
+				"stats": []string{"all"},
+			},
+			samples: &stats.QuerySamples{Series: 2, TotalSamples: 6, PeakSamples: 6},
+		},
+	} {
+		req, err := http.NewRequest("GET", "http://example.com?"+c.query.Encode(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, apiErr, _ := c.endpoint(req)
+		if apiErr != nil {
+			t.Fatalf("Unexpected error: %s", apiErr)
+		}
+		qs := resp.(*queryData).Stats
+		if c.samples == nil {
+			if qs != nil {
+				t.Fatalf("Unexpected stats for %q: %+v", c.query.Encode(), qs)
+			}
+			continue
+		}
+		if qs == nil {
+			t.Fatalf("Missing stats for %q", c.query.Encode())
+		}
+		if !reflect.DeepEqual(qs.Samples, c.samples) {
+			t.Fatalf("Unexpected sample stats for %q, expected %+v, got %+v", c.query.Encode(), c.samples, qs.Samples)
+		}
+		if qs.Timings.EvalTotalTime <= 0 || qs.Timings.ParseTime <= 0 {
+			t.Fatalf("Missing timings for %q: %+v", c.query.Encode(), qs.Timings)
+		}
+	}
+}
+
+func TestQueryLookbackDelta(t *testing.T) {
+	suite, err := promql.NewTest(t, `
+		load 1m
+			sparse_metric 1
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer suite.Close()
+
+	if err := suite.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	api := &API{
+		Queryable:        suite.Storage(),
+		QueryEngine:      suite.QueryEngine(),
+		maxLookbackDelta: time.Hour,
+	}
+
+	for _, c := range []struct {
+		endpoint apiFunc
+		query    url.Values
+		samples  int
+		errType  errorType
+	}{
+		{
+			endpoint: api.query,
+			query:    url.Values{"query": []string{"sparse_metric"}, "time": []string{"600"}},
+		},
+		{
+			endpoint: api.query,
+			query:    url.Values{"query": []string{"sparse_metric"}, "time": []string{"600"}, "lookback_delta": []string{"15m"}},
+			samples:  1,
+		},
+		{
+			endpoint: api.queryRange,
+			query: url.Values{
+				"query":          []string{"sparse_metric"},
+				"start":          []string{"0"},
+				"end":            []string{"1200"},
+				"step":           []string{"600"},
+				"lookback_delta": []string{"15m"},
+			},
+			samples: 2,
+		},
+		{
+			endpoint: api.query,
+			query:    url.Values{"query": []string{"sparse_metric"}, "time": []string{"600"}, "lookback_delta": []string{"2h"}},
+			errType:  errorBadData,
+		},
+		{
+			endpoint: api.query,
+			query:    url.Values{"query": []string{"sparse_metric"}, "time": []string{"600"}, "lookback_delta": []string{"0s"}},
+			errType:  errorBadData,
+		},
+	} {
+		req, err := http.NewRequest("GET", "http://example.com?"+c.query.Encode(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, apiErr, _ := c.endpoint(req)
+		if c.errType != errorNone {
+			if apiErr == nil || apiErr.typ != c.errType {
+				t.Fatalf("Expected error of type %q for %q, got %v", c.errType, c.query.Encode(), apiErr)
+			}
+			continue
+		}
+		if apiErr != nil {
+			t.Fatalf("Unexpected error for %q: %s", c.query.Encode(), apiErr)
+		}
+		var samples int
+		switch v := resp.(*queryData).Result.(type) {
+		case promql.Vector:
+			samples = len(v)
+		case promql.Matrix:
+			for _, s := range v {
+				samples += len(s.Points)
+			}
+		}
+		if samples != c.samples {
+			t.Fatalf("Expected %d samples for %q, got %d", c.samples, c.query.Encode(), samples)
+		}
+	}
+}
+
+func TestQueryCache(t *testing.T) {
+	suite, err := promql.NewTest(t, `
+		load 1m
+			test_metric{foo="bar"} 0+1x10
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer suite.Close()
+
+	if err := suite.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(3600, 0)
+	api := &API{
+		Queryable:   suite.Storage(),
+		QueryEngine: suite.QueryEngine(),
+		now:         func() time.Time { return now },
+		queryCache:  newQueryCache(1<<20, 0),
+	}
+
+	series := func(start, end string) int {
+		q := url.Values{
+			"query": []string{"test_metric"},
+			"start": []string{start},
+			"end":   []string{end},
+			"step":  []string{"60"},
+		}
+		req, err := http.NewRequest("GET", "http://example.com?"+q.Encode(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, apiErr, _ := api.queryRange(req)
+		if apiErr != nil {
+			t.Fatalf("Unexpected error for %q: %s", q.Encode(), apiErr)
+		}
+		return len(resp.(*queryData).Result.(promql.Matrix))
+	}
+
+	if n := series("0", "600"); n != 1 {
+		t.Fatalf("Expected 1 series, got %d", n)
+	}
+
+	app, err := suite.Storage().Appender()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.Add(labels.FromStrings("__name__", "test_metric", "foo", "boo"), 300000, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Aligned ranges are answered from the cache, others are not cached.
+	if n := series("0", "600"); n != 1 {
+		t.Fatalf("Expected cached result with 1 series, got %d", n)
+	}
+	if n := series("30", "570"); n != 2 {
+		t.Fatalf("Expected uncached result with 2 series, got %d", n)
+	}
+
+	api.ClearQueryCache()
+	if n := series("0", "600"); n != 2 {
+		t.Fatalf("Expected 2 series after clearing the cache, got %d", n)
+	}
+
+	// Negative offsets read samples after the end of the range, which are
+	// not complete yet.
+	promql.EnableNegativeOffset = true
+	defer func() { promql.EnableNegativeOffset = false }()
+	key := queryCacheKey{query: "test_metric offset -1h", start: 0, end: 600000, step: time.Minute}
+	q := url.Values{
+		"query": []string{key.query},
+		"start": []string{"0"},
+		"end":   []string{"600"},
+		"step":  []string{"60"},
+	}
+	req, err := http.NewRequest("GET", "http://example.com?"+q.Encode(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, apiErr, _ := api.queryRange(req); apiErr != nil {
+		t.Fatalf("Unexpected error for %q: %s", q.Encode(), apiErr)
+	}
+	if _, _, ok := api.queryCache.get(key, now); ok {
+		t.Fatal("Expected result of range reading samples from the future not to be cached")
+	}
+
+	// Results with warnings may be incomplete.
+	api.QueryEngine = promql.NewEngine(warningQueryable{suite.Storage()}, nil)
+	key = queryCacheKey{query: "test_metric", start: 60000, end: 660000, step: time.Minute}
+	q = url.Values{
+		"query": []string{key.query},
+		"start": []string{"60"},
+		"end":   []string{"660"},
+		"step":  []string{"60"},
+	}
+	req, err = http.NewRequest("GET", "http://example.com?"+q.Encode(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, apiErr, warnings := api.queryRange(req); apiErr != nil || len(warnings) == 0 {
+		t.Fatalf("Expected warnings without error for %q, got %v and %s", q.Encode(), warnings, apiErr)
+	}
+	if _, _, ok := api.queryCache.get(key, now); ok {
+		t.Fatal("Expected result with warnings not to be cached")
+	}
+}
+
+// warningQueryable returns a warning with the series of all selects.
+type warningQueryable struct {
+	promql.Queryable
+}
+
+func (q warningQueryable) Querier(ctx context.Context, mint, maxt int64) (storage.Querier, error) {
+	querier, err := q.Queryable.Querier(ctx, mint, maxt)
+	if err != nil {
+		return nil, err
+	}
+	return warningQuerier{querier}, nil
+}
+
+type warningQuerier struct {
+	storage.Querier
+}
+
+func (q warningQuerier) Select(p *storage.SelectParams, ms ...*labels.Matcher) (storage.SeriesSet, storage.Warnings, error) {
+	set, warnings, err := q.Querier.Select(p, ms...)
+	return set, append(warnings, errors.New("remote read failed")), err
+}
+
+func TestQueryCacheEviction(t *testing.T) {
+	m := promql.Matrix{{
+		Metric: labels.FromStrings("__name__", "test_metric"),
+		Points: []promql.Point{{T: 0, V: 1}, {T: 60000, V: 2}},
+	}}
+	size := resultSize(m, nil)
+	c := newQueryCache(2*size, 0)
+	now := time.Unix(0, 0)
+
+	keys := []queryCacheKey{{query: "a"}, {query: "b"}, {query: "c"}}
+	for _, k := range keys {
+		c.put(k, m, nil, time.Time{})
+	}
+	if _, _, ok := c.get(keys[0], now); ok {
+		t.Fatal("Expected least recently used result to be evicted")
+	}
+	for _, k := range keys[1:] {
+		if _, _, ok := c.get(k, now); !ok {
+			t.Fatalf("Expected result for %v to be cached", k)
+		}
+	}
+
+	// Results larger than the cache are not cached.
+	c.put(queryCacheKey{query: "d"}, append(m, m...), nil, time.Time{})
+	if _, _, ok := c.get(queryCacheKey{query: "d"}, now); !ok {
+		t.Fatal("Expected result of twice the size to be cached")
+	}
+	c.put(queryCacheKey{query: "e"}, append(m, m[0], m[0]), nil, time.Time{})
+	if _, _, ok := c.get(queryCacheKey{query: "e"}, now); ok {
+		t.Fatal("Expected result exceeding the cache size not to be cached")
+	}
+
+	c.put(queryCacheKey{query: "f"}, m, nil, now.Add(time.Minute))
+	if _, _, ok := c.get(queryCacheKey{query: "f"}, now.Add(time.Minute)); ok {
+		t.Fatal("Expected expired result not to be returned")
+	}
+}
+
+func TestTenantIsolation(t *testing.T) {
+	suite, err := promql.NewTest(t, `
+		load 1m
+			up{job="a", team="x"} 1
+			up{job="b", team="y"} 2
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer suite.Close()
+
+	if err := suite.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	api := &API{
+		Queryable:   suite.Storage(),
+		QueryEngine: suite.QueryEngine(),
+		now:         func() time.Time { return time.Unix(0, 0) },
+		tenant:      TenantOptions{Label: "team", Header: DefaultTenantHeader},
+	}
+
+	request := func(query url.Values, tenant string) *http.Request {
+		r, err := http.NewRequest("GET", "http://example.com?"+query.Encode(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tenant != "" {
+			r.Header.Set(DefaultTenantHeader, tenant)
+		}
+		return r
+	}
+
+	// Requests without a tenant are rejected.
+	for _, endpoint := range []apiFunc{api.query, api.series, api.labelNames} {
+		query := url.Values{"query": []string{"up"}, "match[]": []string{"up"}}
+		if _, apiErr, _ := endpoint(request(query, "")); apiErr == nil || apiErr.typ != errorBadData {
+			t.Fatalf("Expected error of type %q without tenant, got %v", errorBadData, apiErr)
+		}
+	}
+
+	for _, c := range []struct {
+		query url.Values
+		exp   float64
+	}{
+		{query: url.Values{"query": []string{"sum(up)"}, "time": []string{"0"}}, exp: 1},
+		{query: url.Values{"query": []string{`sum(count_over_time(up{team="y"}[5m]))`}, "time": []string{"0"}}},
+		{query: url.Values{"query": []string{"count(up) + sum(count_over_time(up[5m]))"}, "time": []string{"0"}}, exp: 2},
+	} {
+		res, apiErr, _ := api.query(request(c.query, "x"))
+		if apiErr != nil {
+			t.Fatalf("Unexpected error for %q: %s", c.query.Get("query"), apiErr)
+		}
+		vec := res.(*queryData).Result.(promql.Vector)
+		if c.exp == 0 {
+			if len(vec) != 0 {
+				t.Fatalf("Expected empty result for %q, got %v", c.query.Get("query"), vec)
+			}
+			continue
+		}
+		if len(vec) != 1 || vec[0].V != c.exp {
+			t.Fatalf("Expected %v for %q, got %v", c.exp, c.query.Get("query"), vec)
+		}
+	}
+
+	res, apiErr, _ := api.series(request(url.Values{"match[]": []string{"up"}}, "x"))
+	if apiErr != nil {
+		t.Fatalf("Unexpected error: %s", apiErr)
+	}
+	exp := []labels.Labels{labels.FromStrings("__name__", "up", "job", "a", "team", "x")}
+	if !reflect.DeepEqual(exp, res) {
+		t.Fatalf("Expected series %v, got %v", exp, res)
+	}
+
+	r := request(url.Values{}, "x")
+	r = r.WithContext(route.WithParam(r.Context(), "name", "job"))
+	res, apiErr, _ = api.labelValues(r)
+	if apiErr != nil {
+		t.Fatalf("Unexpected error: %s", apiErr)
+	}
+	if !reflect.DeepEqual([]string{"a"}, res) {
+		t.Fatalf("Expected label values [a], got %v", res)
+	}
+
+	// Requests without the header fall back to the default tenant.
+	api.tenant.Default = "y"
+	res, apiErr, _ = api.labelNames(request(url.Values{}, ""))
+	if apiErr != nil {
+		t.Fatalf("Unexpected error: %s", apiErr)
+	}
+	if !reflect.DeepEqual([]string{"__name__", "job", "team"}, res) {
+		t.Fatalf("Expected label names, got %v", res)
+	}
+	res, apiErr, _ = api.series(request(url.Values{"match[]": []string{"up"}}, ""))
+	if apiErr != nil {
+		t.Fatalf("Unexpected error: %s", apiErr)
+	}
+	exp = []labels.Labels{labels.FromStrings("__name__", "up", "job", "b", "team", "y")}
+	if !reflect.DeepEqual(exp, res) {
+		t.Fatalf("Expected series %v, got %v", exp, res)
+	}
+}
+
+func TestReadEndpoint(t *testing.T) {
+	suite, err := promql.NewTest(t, `
+		load 1m
+			test_metric1{foo="bar",baz="qux"} 1
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer suite.Close()
+
+	if err := suite.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	api := &API{
+		Queryable:   suite.Storage(),
+		QueryEngine: suite.QueryEngine(),
+		config: func() config.Config {
+			return config.Config{
+				GlobalConfig: config.GlobalConfig{
+					ExternalLabels: model.LabelSet{
+						"baz": "a",
+						"b":   "c",
+						"d":   "e",
+					},
+				},
+			}
+		},
+	}
+
+	// Encode the request.
+	matcher1, err := labels.NewMatcher(labels.MatchEqual, "__name__", "test_metric1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	matcher2, err := labels.NewMatcher(labels.MatchEqual, "d", "e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	query, err := remote.ToQuery(0, 1, []*labels.Matcher{matcher1, matcher2}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &prompb.ReadRequest{
+		Queries: []*prompb.Query{query},
+		LabelQueries: []*prompb.LabelQuery{
+			remote.ToLabelQuery(0, 1, ""),
+			remote.ToLabelQuery(0, 1, "baz"),
+		},
+	}
+	data, err := proto.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compressed := snappy.Encode(nil, data)
+	request, err := http.NewRequest("POST", "", bytes.NewBuffer(compressed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := httptest.NewRecorder()
+	api.remoteRead(recorder, request)
+
+	// Decode the response.
+	compressed, err = ioutil.ReadAll(recorder.Result().Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uncompressed, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var resp prompb.ReadResponse
+	err = proto.Unmarshal(uncompressed, &resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(resp.Results))
+	}
+
+	result := resp.Results[0]
+	expected := &prompb.QueryResult{
+		Timeseries: []*prompb.TimeSeries{
 			{
 				Labels: []*prompb.Label{
 					{Name: "__name__", Value: "test_metric1"},
@@ -595,11 +1906,19 @@ func TestReadEndpoint(t *testing.T) {
 	if !reflect.DeepEqual(result, expected) {
 		t.Fatalf("Expected response \n%v\n but got \n%v\n", result, expected)
 	}
+
+	expectedLabels := []*prompb.LabelQueryResult{
+		{Values: []string{"__name__", "b", "baz", "d", "foo"}},
+		{Values: []string{"a", "qux"}},
+	}
+	if !reflect.DeepEqual(resp.LabelResults, expectedLabels) {
+		t.Fatalf("Expected label results \n%v\n but got \n%v\n", expectedLabels, resp.LabelResults)
+	}
 }
 
 func TestRespondSuccess(t *testing.T) {
 	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		respond(w, "test")
+		respond(w, "test", storage.Warnings{errors.New("partial result")})
 	}))
 	defer s.Close()
 
@@ -626,8 +1945,9 @@ func TestRespondSuccess(t *testing.T) {
 	}
 
 	exp := &response{
-		Status: statusSuccess,
-		Data:   "test",
+		Status:   statusSuccess,
+		Data:     "test",
+		Warnings: []string{"partial result"},
 	}
 	if !reflect.DeepEqual(&res, exp) {
 		t.Fatalf("Expected response \n%v\n but got \n%v\n", res, exp)
@@ -636,7 +1956,7 @@ func TestRespondSuccess(t *testing.T) {
 
 func TestRespondError(t *testing.T) {
 	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		respondError(w, &apiError{errorTimeout, errors.New("message")}, "test")
+		respondError(w, &apiError{errorTimeout, errors.New("message")}, "test", nil)
 	}))
 	defer s.Close()
 
@@ -673,6 +1993,25 @@ func TestRespondError(t *testing.T) {
 	}
 }
 
+func TestRespondParseError(t *testing.T) {
+	_, perr := promql.ParseExpr("sum(foo) bar")
+	if perr == nil {
+		t.Fatalf("Expected parse error")
+	}
+
+	w := httptest.NewRecorder()
+	respondError(w, &apiError{errorBadData, perr}, nil, nil)
+
+	var res response
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("Error unmarshaling JSON body: %s", err)
+	}
+	exp := &promql.PositionRange{Start: 9, End: 12}
+	if !reflect.DeepEqual(res.ErrorPosition, exp) {
+		t.Fatalf("Expected error position %v but got %v", exp, res.ErrorPosition)
+	}
+}
+
 func TestParseTime(t *testing.T) {
 	ts, err := time.Parse(time.RFC3339Nano, "2015-06-03T13:21:58.555Z")
 	if err != nil {
@@ -780,7 +2119,10 @@ func TestParseDuration(t *testing.T) {
 
 func TestOptionsMethod(t *testing.T) {
 	r := route.New()
-	api := &API{ready: func(f http.HandlerFunc) http.HandlerFunc { return f }}
+	api := &API{
+		corsOrigin: regexp.MustCompile("^(?:.*)$"),
+		ready:      func(f http.HandlerFunc) http.HandlerFunc { return f },
+	}
 	api.Register(r)
 
 	s := httptest.NewServer(r)
@@ -790,6 +2132,7 @@ func TestOptionsMethod(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Error creating OPTIONS request: %s", err)
 	}
+	req.Header.Set("Origin", "https://dashboards.example.com")
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
@@ -800,9 +2143,52 @@ func TestOptionsMethod(t *testing.T) {
 		t.Fatalf("Expected status %d, got %d", http.StatusNoContent, resp.StatusCode)
 	}
 
-	for h, v := range corsHeaders {
-		if resp.Header.Get(h) != v {
-			t.Fatalf("Expected %q for header %q, got %q", v, h, resp.Header.Get(h))
+	if h := resp.Header.Get("Access-Control-Allow-Origin"); h != "*" {
+		t.Fatalf("Expected %q for header %q, got %q", "*", "Access-Control-Allow-Origin", h)
+	}
+}
+
+func TestAgentMode(t *testing.T) {
+	r := route.New()
+	api := &API{
+		agent:    true,
+		flagsMap: map[string]string{"agent": "true"},
+		ready:    func(f http.HandlerFunc) http.HandlerFunc { return f },
+	}
+	api.Register(r)
+
+	s := httptest.NewServer(r)
+	defer s.Close()
+
+	for _, req := range []struct{ method, path string }{
+		{"GET", "/query?query=up"},
+		{"POST", "/query_range"},
+		{"GET", "/series?match[]=up"},
+		{"GET", "/labels"},
+		{"GET", "/rules"},
+		{"GET", "/alerts"},
+		{"POST", "/read"},
+	} {
+		hr, err := http.NewRequest(req.method, s.URL+req.path, nil)
+		if err != nil {
+			t.Fatal(err)
 		}
+		resp, err := http.DefaultClient.Do(hr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("Expected status %d for %s %s, got %d", http.StatusServiceUnavailable, req.method, req.path, resp.StatusCode)
+		}
+	}
+
+	resp, err := http.Get(s.URL + "/status/flags")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
 	}
 }
@@ -0,0 +1,215 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/storage"
+)
+
+// queryCacheSettleMargin is the time after which the results of a range are
+// not expected to change anymore, in addition to the out of order time
+// window of the storage. Until then late samples may still be ingested for
+// it, so results of ranges ending within it expire after one step.
+const queryCacheSettleMargin = time.Minute
+
+var (
+	queryCacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "prometheus_api_query_cache_requests_total",
+		Help: "Total number of cacheable range queries by whether their result was cached.",
+	}, []string{"result"})
+	queryCacheSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "prometheus_api_query_cache_size_bytes",
+		Help: "Estimated memory used by the cached range query results.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(queryCacheRequests)
+	prometheus.MustRegister(queryCacheSize)
+}
+
+// minOffset returns the smallest offset of the selectors of the expression,
+// or zero if none of them is negative.
+func minOffset(expr promql.Expr) time.Duration {
+	var min time.Duration
+	promql.Inspect(expr, func(node promql.Node) bool {
+		switch n := node.(type) {
+		case *promql.VectorSelector:
+			if n.Offset < min {
+				min = n.Offset
+			}
+		case *promql.MatrixSelector:
+			if n.Offset < min {
+				min = n.Offset
+			}
+		}
+		return true
+	})
+	return min
+}
+
+// queryCacheKey identifies the result of a range query.
+type queryCacheKey struct {
+	query         string
+	start, end    int64
+	step          time.Duration
+	lookbackDelta time.Duration
+}
+
+type queryCacheEntry struct {
+	key      queryCacheKey
+	value    promql.Value
+	warnings storage.Warnings
+	size     int64
+	// The entry is not returned anymore after this time, unless it is zero.
+	expires time.Time
+}
+
+// queryCache is a least recently used cache of range query results. The
+// least recently used results are evicted once the estimated memory of all
+// results exceeds the maximum size.
+type queryCache struct {
+	maxBytes int64
+	// Results of ranges ending within the settle time expire after one step.
+	settleTime time.Duration
+
+	mtx     sync.Mutex
+	size    int64
+	lru     *list.List
+	entries map[queryCacheKey]*list.Element
+}
+
+// newQueryCache returns a queryCache holding up to maxBytes of results of
+// a storage accepting samples up to outOfOrderTimeWindow into the past. It
+// returns nil, which caches nothing, if maxBytes is not positive.
+func newQueryCache(maxBytes int64, outOfOrderTimeWindow time.Duration) *queryCache {
+	if maxBytes <= 0 {
+		return nil
+	}
+	return &queryCache{
+		maxBytes:   maxBytes,
+		settleTime: outOfOrderTimeWindow + queryCacheSettleMargin,
+		lru:        list.New(),
+		entries:    map[queryCacheKey]*list.Element{},
+	}
+}
+
+// get returns the cached result for the key, if any.
+func (c *queryCache) get(key queryCacheKey, now time.Time) (promql.Value, storage.Warnings, bool) {
+	if c == nil {
+		return nil, nil, false
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	el, ok := c.entries[key]
+	if ok {
+		e := el.Value.(*queryCacheEntry)
+		if e.expires.IsZero() || now.Before(e.expires) {
+			c.lru.MoveToFront(el)
+			queryCacheRequests.WithLabelValues("hit").Inc()
+			return e.value, e.warnings, true
+		}
+		c.remove(el)
+	}
+	queryCacheRequests.WithLabelValues("miss").Inc()
+	return nil, nil, false
+}
+
+// put caches the result for the key until it is evicted or, if expires is
+// not zero, until expires.
+func (c *queryCache) put(key queryCacheKey, v promql.Value, warnings storage.Warnings, expires time.Time) {
+	if c == nil {
+		return
+	}
+	size := resultSize(v, warnings)
+	if size > c.maxBytes {
+		return
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+	c.entries[key] = c.lru.PushFront(&queryCacheEntry{
+		key:      key,
+		value:    v,
+		warnings: warnings,
+		size:     size,
+		expires:  expires,
+	})
+	c.size += size
+
+	for c.size > c.maxBytes {
+		c.remove(c.lru.Back())
+	}
+	queryCacheSize.Set(float64(c.size))
+}
+
+// reset drops all cached results.
+func (c *queryCache) reset() {
+	if c == nil {
+		return
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.lru.Init()
+	c.entries = map[queryCacheKey]*list.Element{}
+	c.size = 0
+	queryCacheSize.Set(0)
+}
+
+func (c *queryCache) remove(el *list.Element) {
+	e := c.lru.Remove(el).(*queryCacheEntry)
+	delete(c.entries, e.key)
+	c.size -= e.size
+	queryCacheSize.Set(float64(c.size))
+}
+
+// resultSize estimates the memory used by a query result.
+func resultSize(v promql.Value, warnings storage.Warnings) int64 {
+	size := int64(0)
+	for _, w := range warnings {
+		size += int64(len(w.Error()))
+	}
+	m, ok := v.(promql.Matrix)
+	if !ok {
+		// Range queries always return matrices.
+		return size
+	}
+	for _, s := range m {
+		for _, l := range s.Metric {
+			// The two strings and their headers.
+			size += int64(len(l.Name)+len(l.Value)) + 32
+		}
+		// A point is a timestamp and a value of 8 bytes each.
+		size += int64(len(s.Points))*16 + 48
+	}
+	return size
+}
+
+// ClearQueryCache drops all cached query results. It is called when the
+// configuration or the rules are reloaded, as they may change the results.
+func (api *API) ClearQueryCache() {
+	api.queryCache.reset()
+}
@@ -0,0 +1,99 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/promql"
+)
+
+// DefaultTenantHeader is the HTTP header carrying the tenant of a request
+// unless configured otherwise.
+const DefaultTenantHeader = "X-Prometheus-Tenant"
+
+// TenantOptions configure the isolation of tenants in the query API. Every
+// query, series and label request of a tenant only selects the series with
+// the tenant label set to the tenant.
+type TenantOptions struct {
+	// The label holding the tenant of a series. Tenants are not isolated
+	// if it is empty.
+	Label string
+	// The HTTP header carrying the tenant of a request.
+	Header string
+	// The tenant of requests without the header. Such requests are
+	// rejected if it is empty.
+	Default string
+}
+
+// tenantMatcher returns the matcher selecting the series of the tenant of
+// the request, or nil if tenants are not isolated.
+func (api *API) tenantMatcher(r *http.Request) (*labels.Matcher, *apiError) {
+	if api.tenant.Label == "" {
+		return nil, nil
+	}
+	header := api.tenant.Header
+	if header == "" {
+		header = DefaultTenantHeader
+	}
+	tenant := r.Header.Get(header)
+	if tenant == "" {
+		tenant = api.tenant.Default
+	}
+	if tenant == "" {
+		return nil, &apiError{errorBadData, fmt.Errorf("missing tenant header %q", header)}
+	}
+	m, err := labels.NewMatcher(labels.MatchEqual, api.tenant.Label, tenant)
+	if err != nil {
+		return nil, &apiError{errorInternal, err}
+	}
+	return m, nil
+}
+
+// enforceMatcher returns the matcher sets with m added to each of them. A
+// nil matcher leaves them unchanged.
+func enforceMatcher(matcherSets [][]*labels.Matcher, m *labels.Matcher) [][]*labels.Matcher {
+	if m == nil {
+		return matcherSets
+	}
+	res := make([][]*labels.Matcher, 0, len(matcherSets))
+	for _, ms := range matcherSets {
+		res = append(res, append(ms[:len(ms):len(ms)], m))
+	}
+	return res
+}
+
+// enforceQuery returns the query with m added to all of its selectors. A nil
+// matcher leaves it unchanged.
+func enforceQuery(query string, m *labels.Matcher) (string, error) {
+	if m == nil {
+		return query, nil
+	}
+	expr, err := promql.ParseExpr(query)
+	if err != nil {
+		return "", err
+	}
+	promql.Inspect(expr, func(node promql.Node) bool {
+		switch n := node.(type) {
+		case *promql.VectorSelector:
+			n.LabelMatchers = append(n.LabelMatchers, m)
+		case *promql.MatrixSelector:
+			n.LabelMatchers = append(n.LabelMatchers, m)
+		}
+		return true
+	})
+	return expr.String(), nil
+}
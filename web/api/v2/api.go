@@ -84,13 +84,14 @@ func (api *API) RegisterGRPC(srv *grpc.Server) {
 }
 
 // HTTPHandler returns an HTTP handler for a REST API gateway to the given grpc address.
-func (api *API) HTTPHandler(grpcAddr string) (http.Handler, error) {
+// Additional dial options, e.g. a custom dialer, are applied when connecting to it.
+func (api *API) HTTPHandler(grpcAddr string, dialOpts ...grpc.DialOption) (http.Handler, error) {
 	ctx := context.Background()
 
 	enc := new(protoutil.JSONPb)
 	mux := runtime.NewServeMux(runtime.WithMarshalerOption(enc.ContentType(), enc))
 
-	opts := []grpc.DialOption{grpc.WithInsecure()}
+	opts := append([]grpc.DialOption{grpc.WithInsecure()}, dialOpts...)
 
 	err := pb.RegisterAdminHandlerFromEndpoint(ctx, mux, grpcAddr, opts)
 	if err != nil {
@@ -169,13 +170,17 @@ func (s *Admin) TSDBSnapshot(_ old_ctx.Context, _ *pb.TSDBSnapshotRequest) (*pb.
 	}
 	var (
 		snapdir = filepath.Join(db.Dir(), "snapshots")
-		name    = fmt.Sprintf("%s-%x", time.Now().UTC().Format(time.RFC3339), rand.Int())
-		dir     = filepath.Join(snapdir, name)
+		// The timestamp format avoids characters that are not allowed in
+		// file names on all platforms.
+		name = fmt.Sprintf("%s-%x", time.Now().UTC().Format("20060102T150405Z0700"), rand.Int())
+		dir  = filepath.Join(snapdir, name)
 	)
 	if err := os.MkdirAll(dir, 0777); err != nil {
-		return nil, status.Errorf(codes.Internal, "created snapshot directory: %s", err)
+		return nil, status.Errorf(codes.Internal, "create snapshot directory: %s", err)
 	}
 	if err := db.Snapshot(dir); err != nil {
+		// Do not leave partial snapshots behind.
+		os.RemoveAll(dir)
 		return nil, status.Errorf(codes.Internal, "create snapshot: %s", err)
 	}
 	return &pb.TSDBSnapshotResponse{Name: name}, nil
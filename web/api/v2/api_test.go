@@ -0,0 +1,56 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_v2
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/tsdb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	pb "github.com/prometheus/prometheus/prompb"
+	"github.com/prometheus/prometheus/util/testutil"
+)
+
+func TestAdminTSDBSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "api-v2-snapshot")
+	testutil.Ok(t, err)
+	defer os.RemoveAll(dir)
+
+	// A TSDB that is not ready yet must be reported as unavailable.
+	admin := NewAdmin(func() *tsdb.DB { return nil })
+	_, err = admin.TSDBSnapshot(context.Background(), &pb.TSDBSnapshotRequest{})
+	testutil.Equals(t, codes.Unavailable, grpc.Code(err))
+
+	db, err := tsdb.Open(dir, nil, nil, nil)
+	testutil.Ok(t, err)
+	defer db.Close()
+
+	admin = NewAdmin(func() *tsdb.DB { return db })
+	resp, err := admin.TSDBSnapshot(context.Background(), &pb.TSDBSnapshotRequest{})
+	testutil.Ok(t, err)
+	testutil.Assert(t, resp.Name != "", "empty snapshot name")
+
+	fi, err := os.Stat(filepath.Join(dir, "snapshots", resp.Name))
+	testutil.Ok(t, err)
+	testutil.Assert(t, fi.IsDir(), "snapshot is not a directory")
+
+	_, err = (&adminDisabled{}).TSDBSnapshot(context.Background(), &pb.TSDBSnapshotRequest{})
+	testutil.Equals(t, codes.Unavailable, grpc.Code(err))
+}
@@ -0,0 +1,149 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+	old_ctx "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// authFile is the format of the file configuring basic authentication.
+type authFile struct {
+	// BasicAuthUsers maps user names to bcrypt hashed passwords.
+	BasicAuthUsers map[string]string `yaml:"basic_auth_users"`
+
+	// Catches all undefined fields and must be empty after parsing.
+	XXX map[string]interface{} `yaml:",inline"`
+}
+
+// authenticator verifies HTTP basic authentication credentials against
+// the users read from a file. The file is re-read on reload.
+type authenticator struct {
+	file string
+
+	mtx   sync.RWMutex
+	users map[string][]byte
+}
+
+func newAuthenticator(file string) *authenticator {
+	return &authenticator{file: file}
+}
+
+// reload reads the users from the authentication file. The previous users
+// are kept if the file is invalid.
+func (a *authenticator) reload() error {
+	b, err := ioutil.ReadFile(a.file)
+	if err != nil {
+		return fmt.Errorf("unable to read authentication file %q: %s", a.file, err)
+	}
+	af := authFile{}
+	if err := yaml.Unmarshal(b, &af); err != nil {
+		return fmt.Errorf("unable to parse authentication file %q: %s", a.file, err)
+	}
+	if len(af.XXX) > 0 {
+		var keys []string
+		for k := range af.XXX {
+			keys = append(keys, k)
+		}
+		return fmt.Errorf("unknown fields in authentication file %q: %s", a.file, strings.Join(keys, ", "))
+	}
+
+	users := make(map[string][]byte, len(af.BasicAuthUsers))
+	for u, h := range af.BasicAuthUsers {
+		if _, err := bcrypt.Cost([]byte(h)); err != nil {
+			return fmt.Errorf("invalid password hash for user %q: %s", u, err)
+		}
+		users[u] = []byte(h)
+	}
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.users = users
+
+	return nil
+}
+
+// dummyHash is compared against the passwords of unknown users, so that they
+// take as long to reject as wrong passwords and do not reveal which users exist.
+var dummyHash = []byte("$2a$10$TITkyTSKEepT2Ux.525C0OpctvapNIfZ/XIuxjvMBAZjbPLg8F4mG")
+
+// authenticate reports whether the user exists and the password matches.
+func (a *authenticator) authenticate(user, pass string) bool {
+	a.mtx.RLock()
+	hash, ok := a.users[user]
+	a.mtx.RUnlock()
+
+	if !ok {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(pass))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword(hash, []byte(pass)) == nil
+}
+
+// handler rejects requests to next that do not carry valid credentials.
+func (a *authenticator) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || !a.authenticate(user, pass) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="Prometheus"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// unaryInterceptor rejects gRPC calls that do not carry valid credentials
+// in their authorization metadata. The gRPC gateway forwards the HTTP
+// Authorization header as such.
+func (a *authenticator) unaryInterceptor(ctx old_ctx.Context, req interface{}, _ *grpc.UnaryServerInfo, next grpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md["authorization"] {
+		if user, pass, ok := parseBasicAuth(v); ok && a.authenticate(user, pass) {
+			return next(ctx, req)
+		}
+	}
+	return nil, status.Error(codes.Unauthenticated, "invalid or missing credentials")
+}
+
+// parseBasicAuth parses an HTTP Basic Authentication string.
+func parseBasicAuth(auth string) (user, pass string, ok bool) {
+	const prefix = "Basic "
+	if !strings.HasPrefix(auth, prefix) {
+		return "", "", false
+	}
+	c, err := base64.StdEncoding.DecodeString(auth[len(prefix):])
+	if err != nil {
+		return "", "", false
+	}
+	cs := string(c)
+	s := strings.IndexByte(cs, ':')
+	if s < 0 {
+		return "", "", false
+	}
+	return cs[:s], cs[s+1:], true
+}
@@ -0,0 +1,88 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/prometheus/prometheus/util/testutil"
+)
+
+func TestAuthenticator(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	testutil.Ok(t, err)
+
+	f, err := ioutil.TempFile("", "web-auth")
+	testutil.Ok(t, err)
+	defer os.Remove(f.Name())
+
+	_, err = fmt.Fprintf(f, "basic_auth_users:\n  alice: %s\n", hash)
+	testutil.Ok(t, err)
+	testutil.Ok(t, f.Close())
+
+	a := newAuthenticator(f.Name())
+	testutil.Ok(t, a.reload())
+
+	h := a.handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, tc := range []struct {
+		user, pass string
+		setAuth    bool
+		code       int
+	}{
+		{code: http.StatusUnauthorized},
+		{user: "alice", pass: "secret", setAuth: true, code: http.StatusOK},
+		{user: "alice", pass: "wrong", setAuth: true, code: http.StatusUnauthorized},
+		{user: "bob", pass: "secret", setAuth: true, code: http.StatusUnauthorized},
+	} {
+		req, err := http.NewRequest("GET", "/metrics", nil)
+		testutil.Ok(t, err)
+		if tc.setAuth {
+			req.SetBasicAuth(tc.user, tc.pass)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		testutil.Equals(t, tc.code, w.Code)
+	}
+
+	// Unknown users are compared against a valid hash of the default cost,
+	// which takes as long as comparing a wrong password.
+	cost, err := bcrypt.Cost(dummyHash)
+	testutil.Ok(t, err)
+	testutil.Equals(t, bcrypt.DefaultCost, cost)
+
+	user, pass, ok := parseBasicAuth("Basic YWxpY2U6c2VjcmV0")
+	testutil.Assert(t, ok, "unable to parse basic auth")
+	testutil.Equals(t, "alice", user)
+	testutil.Equals(t, "secret", pass)
+
+	_, _, ok = parseBasicAuth("Bearer YWxpY2U6c2VjcmV0")
+	testutil.Assert(t, !ok, "parsed non-basic auth")
+
+	// Invalid files must be rejected and leave the current users in place.
+	testutil.Ok(t, ioutil.WriteFile(f.Name(), []byte("basic_auth_users:\n  alice: plain\n"), 0644))
+	testutil.NotOk(t, a.reload())
+	testutil.Assert(t, a.authenticate("alice", "secret"), "users changed after failed reload")
+
+	testutil.Ok(t, ioutil.WriteFile(f.Name(), []byte("users:\n  alice: plain\n"), 0644))
+	testutil.NotOk(t, a.reload())
+}
@@ -0,0 +1,130 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	old_ctx "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RouteGroup is a group of routes that share authorization requirements.
+type RouteGroup string
+
+// The route groups an AuthorizeFunc is consulted for.
+const (
+	// RouteGroupQuery covers the read endpoints of the v1 API.
+	RouteGroupQuery RouteGroup = "query"
+	// RouteGroupAdmin covers the endpoints that modify the database or the
+	// rules, that is series deletion, rule group writes and the /admin/
+	// endpoints in the v1 API and the whole v2 API.
+	RouteGroupAdmin RouteGroup = "admin"
+	// RouteGroupLifecycle covers the quit and reload endpoints.
+	RouteGroupLifecycle RouteGroup = "lifecycle"
+	// RouteGroupFederation covers the federation endpoint.
+	RouteGroupFederation RouteGroup = "federation"
+)
+
+// AuthorizeFunc decides whether a request may access a route of the given
+// group. A non-nil error denies the request and is returned to the client.
+type AuthorizeFunc func(r *http.Request, group RouteGroup) error
+
+// authorize returns a handler that only calls f for requests authorized to
+// access the route group.
+func (h *Handler) authorize(group RouteGroup, f http.HandlerFunc) http.HandlerFunc {
+	if h.options.AuthorizeFunc == nil {
+		return f
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h.options.AuthorizeFunc(r, group); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		f(w, r)
+	}
+}
+
+// authorizeAPIV1 authorizes requests to the v1 API. Only series deletion,
+// rule group writes and the /admin/ endpoints, whatever the method, modify
+// state, all other endpoints are read-only.
+func (h *Handler) authorizeAPIV1(next http.Handler) http.HandlerFunc {
+	var (
+		query = h.authorize(RouteGroupQuery, next.ServeHTTP)
+		admin = h.authorize(RouteGroupAdmin, next.ServeHTTP)
+	)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete || r.Method == http.MethodPut || strings.HasPrefix(path.Clean(r.URL.Path), "/admin/") {
+			admin(w, r)
+			return
+		}
+		query(w, r)
+	}
+}
+
+// authorizeUnaryInterceptor authorizes gRPC calls, which all belong to the
+// admin group. Calls through the gRPC gateway were already authorized as HTTP
+// requests. For all others, the AuthorizeFunc is passed a request carrying
+// the call's metadata as headers and its full method name as path.
+func (h *Handler) authorizeUnaryInterceptor(ctx old_ctx.Context, req interface{}, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (interface{}, error) {
+	p, ok := peer.FromContext(ctx)
+	if ok {
+		if _, ok := p.Addr.(pipeAddr); ok {
+			return next(ctx, req)
+		}
+	}
+
+	r := &http.Request{
+		Method: http.MethodPost,
+		URL:    &url.URL{Path: info.FullMethod},
+		Header: http.Header{},
+	}
+	if p != nil {
+		r.RemoteAddr = p.Addr.String()
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for k, vs := range md {
+		for _, v := range vs {
+			r.Header.Add(k, v)
+		}
+	}
+	r = r.WithContext(ctx)
+
+	if err := h.options.AuthorizeFunc(r, RouteGroupAdmin); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	return next(ctx, req)
+}
+
+// chainUnaryInterceptors returns an interceptor calling the given ones in
+// order before the handler.
+func chainUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx old_ctx.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(ctx old_ctx.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, inner)
+			}
+		}
+		return next(ctx, req)
+	}
+}
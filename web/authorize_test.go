@@ -0,0 +1,139 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	old_ctx "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/prometheus/prometheus/util/testutil"
+)
+
+func TestAuthorize(t *testing.T) {
+	var groups []RouteGroup
+
+	h := &Handler{options: &Options{
+		AuthorizeFunc: func(r *http.Request, group RouteGroup) error {
+			groups = append(groups, group)
+			if group != RouteGroupQuery && r.Header.Get("X-User") != "admin" {
+				return errors.New("forbidden")
+			}
+			return nil
+		},
+	}}
+	api := h.authorizeAPIV1(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, tc := range []struct {
+		method, user string
+		group        RouteGroup
+		code         int
+	}{
+		{method: "GET", group: RouteGroupQuery, code: http.StatusOK},
+		{method: "DELETE", group: RouteGroupAdmin, code: http.StatusForbidden},
+		{method: "DELETE", user: "admin", group: RouteGroupAdmin, code: http.StatusOK},
+		{method: "PUT", group: RouteGroupAdmin, code: http.StatusForbidden},
+	} {
+		groups = nil
+
+		req, err := http.NewRequest(tc.method, "/series", nil)
+		testutil.Ok(t, err)
+		if tc.user != "" {
+			req.Header.Set("X-User", tc.user)
+		}
+		w := httptest.NewRecorder()
+		api.ServeHTTP(w, req)
+
+		testutil.Equals(t, tc.code, w.Code)
+		testutil.Equals(t, []RouteGroup{tc.group}, groups)
+	}
+
+	// The admin endpoints are admin routes whatever the method.
+	for _, path := range []string{
+		"/admin/tsdb/delete_series",
+		"/admin/tsdb/clean_tombstones",
+		"/admin/tsdb/snapshot",
+		"/admin/tsdb/../tsdb/snapshot",
+	} {
+		for _, method := range []string{"GET", "POST", "PUT"} {
+			groups = nil
+
+			w := httptest.NewRecorder()
+			api.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+
+			testutil.Equals(t, http.StatusForbidden, w.Code)
+			testutil.Equals(t, []RouteGroup{RouteGroupAdmin}, groups)
+		}
+	}
+
+	// Without an AuthorizeFunc all requests are served.
+	h = &Handler{options: &Options{}}
+	w := httptest.NewRecorder()
+	h.authorizeAPIV1(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(w, httptest.NewRequest("DELETE", "/series", nil))
+	testutil.Equals(t, http.StatusOK, w.Code)
+}
+
+func TestAuthorizeUnaryInterceptor(t *testing.T) {
+	h := &Handler{options: &Options{
+		AuthorizeFunc: func(r *http.Request, group RouteGroup) error {
+			if group != RouteGroupAdmin || r.URL.Path != "/prometheus.Admin/TSDBSnapshot" {
+				return errors.New("unexpected route")
+			}
+			if r.Header.Get("x-user") != "admin" {
+				return errors.New("forbidden")
+			}
+			return nil
+		},
+	}}
+	var (
+		info    = &grpc.UnaryServerInfo{FullMethod: "/prometheus.Admin/TSDBSnapshot"}
+		handler = func(ctx old_ctx.Context, req interface{}) (interface{}, error) { return "ok", nil }
+		tcpPeer = &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1234}}
+	)
+
+	ctx := peer.NewContext(context.Background(), tcpPeer)
+	_, err := h.authorizeUnaryInterceptor(ctx, nil, info, handler)
+	testutil.Equals(t, codes.PermissionDenied, grpc.Code(err))
+
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-user", "admin"))
+	resp, err := h.authorizeUnaryInterceptor(ctx, nil, info, handler)
+	testutil.Ok(t, err)
+	testutil.Equals(t, "ok", resp)
+
+	// Calls through the gateway were authorized as HTTP requests already.
+	ctx = peer.NewContext(context.Background(), &peer.Peer{Addr: pipeAddr{}})
+	_, err = h.authorizeUnaryInterceptor(ctx, nil, info, handler)
+	testutil.Ok(t, err)
+
+	// Chained interceptors are called in order.
+	var calls []string
+	record := func(name string) grpc.UnaryServerInterceptor {
+		return func(ctx old_ctx.Context, req interface{}, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (interface{}, error) {
+			calls = append(calls, name)
+			return next(ctx, req)
+		}
+	}
+	_, err = chainUnaryInterceptors(record("a"), record("b"))(context.Background(), nil, info, handler)
+	testutil.Ok(t, err)
+	testutil.Equals(t, []string{"a", "b"}, calls)
+}
@@ -0,0 +1,82 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// readConsoleTemplate reads the console template with the given path below
+// /consoles, such as "/index.html", from the first of the directories that
+// contains it. It is read on every request so that changes are visible
+// without a restart or reload.
+func readConsoleTemplate(dirs []string, name string) (string, error) {
+	for _, dir := range dirs {
+		f, err := http.Dir(dir).Open(name)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+
+		fi, err := f.Stat()
+		if err != nil {
+			return "", err
+		}
+		if fi.IsDir() {
+			continue
+		}
+		b, err := ioutil.ReadAll(f)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+	return "", os.ErrNotExist
+}
+
+// consoleLibraries returns the console library files in the given directories,
+// sorted by file name. Libraries in earlier directories take precedence over
+// libraries with the same file name in later ones.
+func consoleLibraries(dirs []string) ([]string, error) {
+	libs := map[string]string{}
+	for _, dir := range dirs {
+		filenames, err := filepath.Glob(filepath.Join(dir, "*.lib"))
+		if err != nil {
+			return nil, err
+		}
+		for _, fn := range filenames {
+			if _, ok := libs[filepath.Base(fn)]; !ok {
+				libs[filepath.Base(fn)] = fn
+			}
+		}
+	}
+	names := make([]string, 0, len(libs))
+	for name := range libs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	filenames := make([]string, 0, len(names))
+	for _, name := range names {
+		filenames = append(filenames, libs[name])
+	}
+	return filenames, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/common/route"
+
+	"github.com/prometheus/prometheus/util/testutil"
+)
+
+func writeConsoleFile(t *testing.T, path, text string) {
+	testutil.Ok(t, os.MkdirAll(filepath.Dir(path), 0755))
+	testutil.Ok(t, ioutil.WriteFile(path, []byte(text), 0644))
+}
+
+func TestConsoleFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "web-consoles")
+	testutil.Ok(t, err)
+	defer os.RemoveAll(dir)
+
+	writeConsoleFile(t, filepath.Join(dir, "a", "index.html"), "a index")
+	writeConsoleFile(t, filepath.Join(dir, "a", "node", "cpu.html"), "a cpu")
+	writeConsoleFile(t, filepath.Join(dir, "b", "index.html"), "b index")
+	writeConsoleFile(t, filepath.Join(dir, "b", "other.html"), "b other")
+	writeConsoleFile(t, filepath.Join(dir, "b", "node", "mem.html"), "b mem")
+	writeConsoleFile(t, filepath.Join(dir, "liba", "menu.lib"), "a menu")
+	writeConsoleFile(t, filepath.Join(dir, "liba", "ignored.txt"), "ignored")
+	writeConsoleFile(t, filepath.Join(dir, "liba", "zzz.lib"), "a zzz")
+	writeConsoleFile(t, filepath.Join(dir, "libb", "menu.lib"), "b menu")
+	writeConsoleFile(t, filepath.Join(dir, "libb", "prom.lib"), "b prom")
+
+	// Directories linked to are followed.
+	testutil.Ok(t, os.Symlink(filepath.Join(dir, "b"), filepath.Join(dir, "link")))
+
+	dirs := []string{filepath.Join(dir, "missing"), filepath.Join(dir, "a"), filepath.Join(dir, "link")}
+	for name, expected := range map[string]string{
+		"/index.html":    "a index",
+		"/node/cpu.html": "a cpu",
+		"/node/mem.html": "b mem",
+		"/other.html":    "b other",
+	} {
+		text, err := readConsoleTemplate(dirs, name)
+		testutil.Ok(t, err)
+		testutil.Equals(t, expected, text)
+	}
+	for _, name := range []string{"/missing.html", "/node", "/../a/index.html"} {
+		_, err := readConsoleTemplate(dirs, name)
+		testutil.Assert(t, os.IsNotExist(err), "unexpected error for %s: %v", name, err)
+	}
+
+	filenames, err := consoleLibraries([]string{filepath.Join(dir, "liba"), filepath.Join(dir, "libb")})
+	testutil.Ok(t, err)
+	testutil.Equals(t, []string{
+		filepath.Join(dir, "liba", "menu.lib"),
+		filepath.Join(dir, "libb", "prom.lib"),
+		filepath.Join(dir, "liba", "zzz.lib"),
+	}, filenames)
+}
+
+func TestConsolesChanges(t *testing.T) {
+	dir, err := ioutil.TempDir("", "web-consoles")
+	testutil.Ok(t, err)
+	defer os.RemoveAll(dir)
+
+	writeConsoleFile(t, filepath.Join(dir, "consoles", "index.html"), `{{ template "head" }}old`)
+	writeConsoleFile(t, filepath.Join(dir, "libs", "head.lib"), `{{ define "head" }}head {{ end }}`)
+
+	h := New(nil, &Options{
+		ConsoleTemplatesPaths: []string{filepath.Join(dir, "consoles")},
+		ConsoleLibrariesPaths: []string{filepath.Join(dir, "libs")},
+		ExternalURL:           &url.URL{},
+		RoutePrefix:           "/",
+		Flags:                 map[string]string{},
+	})
+	r := route.New()
+	r.Get("/consoles/*filepath", h.consoles)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w
+	}
+
+	w := get("/consoles/index.html")
+	testutil.Equals(t, http.StatusOK, w.Code)
+	testutil.Equals(t, "head old", w.Body.String())
+
+	testutil.Equals(t, http.StatusNotFound, get("/consoles/new.html").Code)
+	testutil.Equals(t, http.StatusNotFound, get("/consoles/../libs/head.lib").Code)
+
+	// Changes are visible right away.
+	writeConsoleFile(t, filepath.Join(dir, "consoles", "index.html"), `{{ template "head" }}new`)
+	writeConsoleFile(t, filepath.Join(dir, "consoles", "new.html"), `new`)
+	writeConsoleFile(t, filepath.Join(dir, "libs", "head.lib"), `{{ define "head" }}new head {{ end }}`)
+
+	testutil.Equals(t, "new head new", get("/consoles/index.html").Body.String())
+	testutil.Equals(t, http.StatusOK, get("/consoles/new.html").Code)
+
+	// A broken template only fails its own requests.
+	writeConsoleFile(t, filepath.Join(dir, "consoles", "broken.html"), `{{ template "head" }`)
+
+	testutil.Equals(t, http.StatusInternalServerError, get("/consoles/broken.html").Code)
+	testutil.Equals(t, http.StatusOK, get("/consoles/new.html").Code)
+}
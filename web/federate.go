@@ -21,7 +21,6 @@ import (
 	"github.com/gogo/protobuf/proto"
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
-	"github.com/prometheus/common/expfmt"
 	"github.com/prometheus/common/model"
 
 	"github.com/prometheus/prometheus/pkg/labels"
@@ -57,8 +56,8 @@ func (h *Handler) federation(w http.ResponseWriter, req *http.Request) {
 	var (
 		mint   = timestamp.FromTime(h.now().Time().Add(-promql.LookbackDelta))
 		maxt   = timestamp.FromTime(h.now().Time())
-		format = expfmt.Negotiate(req.Header)
-		enc    = expfmt.NewEncoder(w, format)
+		format = negotiateFormat(req.Header)
+		enc    = newEncoder(w, format)
 	)
 	w.Header().Set("Content-Type", string(format))
 
@@ -75,9 +74,19 @@ func (h *Handler) federation(w http.ResponseWriter, req *http.Request) {
 	var set storage.SeriesSet
 
 	for _, mset := range matcherSets {
-		set = storage.DeduplicateSeriesSet(set, q.Select(mset...))
+		s, wrn, err := q.Select(nil, mset...)
+		for _, warning := range wrn {
+			level.Warn(h.logger).Log("msg", "Federation returns partial results", "err", warning)
+		}
+		if err != nil {
+			federationErrors.Inc()
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		set = storage.DeduplicateSeriesSet(set, s)
 	}
 	if set == nil {
+		enc.Close()
 		return
 	}
 
@@ -204,8 +213,11 @@ func (h *Handler) federation(w http.ResponseWriter, req *http.Request) {
 		if err := enc.Encode(protMetricFam); err != nil {
 			federationErrors.Inc()
 			level.Error(h.logger).Log("msg", "federation failed", "err", err)
+			return
 		}
 	}
+	// Truncated OpenMetrics expositions lack the # EOF line.
+	enc.Close()
 }
 
 // byName makes a model.Vector sortable by metric name.
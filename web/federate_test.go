@@ -176,6 +176,31 @@ test_metric2{foo="boo",instance="i"} 1 6000000
 test_metric_old{instance="baz"} 981 5880000
 # TYPE test_metric_without_labels untyped
 test_metric_without_labels{instance="baz"} 1001 6000000
+`,
+	},
+	"openmetrics": {
+		params: "match[]=test_metric1",
+		accept: "application/openmetrics-text;version=0.0.1;q=1,text/plain;version=0.0.4;q=0.5",
+		code:   200,
+		body: `# TYPE test_metric1 unknown
+test_metric1{foo="bar",instance="i"} 10000 6000
+test_metric1{foo="boo",instance="i"} 1 6000
+# EOF
+`,
+	},
+	"openmetrics match nothing": {
+		params: "match[]=does_not_match_anything",
+		accept: "application/openmetrics-text;version=0.0.1",
+		code:   200,
+		body: `# EOF
+`,
+	},
+	"text preferred over openmetrics": {
+		params: "match[]=test_metric2",
+		accept: "application/openmetrics-text;version=0.0.1;q=0.5,text/plain;version=0.0.4",
+		code:   200,
+		body: `# TYPE test_metric2 untyped
+test_metric2{foo="boo",instance="i"} 1 6000000
 `,
 	},
 }
@@ -210,8 +235,12 @@ func TestFederation(t *testing.T) {
 
 	for name, scenario := range scenarios {
 		h.config.GlobalConfig.ExternalLabels = scenario.externalLabels
+		header := ""
+		if scenario.accept != "" {
+			header = "Accept: " + scenario.accept + "\r\n"
+		}
 		req, err := http.ReadRequest(bufio.NewReader(strings.NewReader(
-			"GET http://example.org/federate?" + scenario.params + " HTTP/1.0\r\n\r\n",
+			"GET http://example.org/federate?" + scenario.params + " HTTP/1.0\r\n" + header + "\r\n",
 		)))
 		if err != nil {
 			t.Fatal(err)
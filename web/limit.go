@@ -0,0 +1,150 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// limitRetryAfter is the time clients exceeding their limit are asked to
+// wait before retrying.
+const limitRetryAfter = time.Second
+
+var (
+	limitedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "prometheus_web_limited_requests_total",
+		Help: "Total number of HTTP requests rejected because the client exceeded its limit of concurrent requests.",
+	}, []string{"limit"})
+)
+
+func init() {
+	prometheus.MustRegister(limitedRequests)
+}
+
+// clientLimiter limits the number of concurrent requests of each client.
+type clientLimiter struct {
+	max int
+
+	mtx      sync.Mutex
+	inflight map[string]int
+}
+
+// newClientLimiter returns a clientLimiter allowing max concurrent requests
+// per client. It returns nil, which allows all requests, if max is not
+// positive.
+func newClientLimiter(max int) *clientLimiter {
+	if max <= 0 {
+		return nil
+	}
+	return &clientLimiter{max: max, inflight: map[string]int{}}
+}
+
+// acquire reserves a request of the client and reports whether the client
+// was below its limit. Successful calls must be followed by a call to release.
+func (l *clientLimiter) acquire(client string) bool {
+	if l == nil {
+		return true
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.inflight[client] >= l.max {
+		return false
+	}
+	l.inflight[client]++
+	return true
+}
+
+// release frees a request reserved with acquire.
+func (l *clientLimiter) release(client string) {
+	if l == nil {
+		return
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	// Forget idle clients so that the map does not grow without bounds.
+	if l.inflight[client] <= 1 {
+		delete(l.inflight, client)
+		return
+	}
+	l.inflight[client]--
+}
+
+// limitHandler rejects requests of clients exceeding their limit of
+// concurrent requests with 429 Too Many Requests. Requests to the expensive
+// paths count against a separate, usually lower, limit as well.
+type limitHandler struct {
+	handler http.Handler
+
+	requests       *clientLimiter
+	expensive      *clientLimiter
+	expensivePaths map[string]bool
+}
+
+func newLimitHandler(h http.Handler, maxRequests, maxExpensive int, expensivePaths ...string) *limitHandler {
+	lh := &limitHandler{
+		handler:        h,
+		requests:       newClientLimiter(maxRequests),
+		expensive:      newClientLimiter(maxExpensive),
+		expensivePaths: make(map[string]bool, len(expensivePaths)),
+	}
+	for _, p := range expensivePaths {
+		lh.expensivePaths[p] = true
+	}
+	return lh
+}
+
+func (lh *limitHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	client := clientIP(r)
+
+	if !lh.requests.acquire(client) {
+		lh.reject(w, "client")
+		return
+	}
+	defer lh.requests.release(client)
+
+	if lh.expensivePaths[r.URL.Path] {
+		if !lh.expensive.acquire(client) {
+			lh.reject(w, "expensive")
+			return
+		}
+		defer lh.expensive.release(client)
+	}
+
+	lh.handler.ServeHTTP(w, r)
+}
+
+func (lh *limitHandler) reject(w http.ResponseWriter, limit string) {
+	limitedRequests.WithLabelValues(limit).Inc()
+
+	w.Header().Set("Retry-After", strconv.Itoa(int(limitRetryAfter/time.Second)))
+	http.Error(w, "too many concurrent requests", http.StatusTooManyRequests)
+}
+
+// clientIP returns the IP address a request was sent from. Requests over Unix
+// domain sockets all share the same, possibly empty, remote address.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
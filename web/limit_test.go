@@ -0,0 +1,86 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/prometheus/util/testutil"
+)
+
+func TestLimitHandler(t *testing.T) {
+	var (
+		started = make(chan struct{})
+		block   = make(chan struct{})
+	)
+	lh := newLimitHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("block") != "" {
+			started <- struct{}{}
+			<-block
+		}
+	}), 3, 1, "/api/v1/query_range")
+
+	request := func(addr, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.RemoteAddr = addr
+		w := httptest.NewRecorder()
+		lh.ServeHTTP(w, req)
+		return w
+	}
+
+	done := make(chan struct{})
+	for _, p := range []string{"/api/v1/query_range?block=1", "/api/v1/query?block=1"} {
+		go func(p string) {
+			request("10.0.0.1:1234", p)
+			done <- struct{}{}
+		}(p)
+		<-started
+	}
+
+	// The expensive limit is reached, other endpoints can still be used.
+	w := request("10.0.0.1:1235", "/api/v1/query_range")
+	testutil.Equals(t, http.StatusTooManyRequests, w.Code)
+	testutil.Equals(t, "1", w.Header().Get("Retry-After"))
+	testutil.Equals(t, http.StatusOK, request("10.0.0.1:1235", "/api/v1/query").Code)
+
+	// Other clients are not limited.
+	testutil.Equals(t, http.StatusOK, request("10.0.0.2:1234", "/api/v1/query_range").Code)
+
+	go func() {
+		request("10.0.0.1:1236", "/graph?block=1")
+		done <- struct{}{}
+	}()
+	<-started
+
+	// All requests of the client are rejected once the overall limit is reached.
+	testutil.Equals(t, http.StatusTooManyRequests, request("10.0.0.1:1237", "/graph").Code)
+
+	close(block)
+	for i := 0; i < 3; i++ {
+		<-done
+	}
+	testutil.Equals(t, http.StatusOK, request("10.0.0.1:1237", "/api/v1/query_range").Code)
+	testutil.Equals(t, 0, len(lh.requests.inflight))
+	testutil.Equals(t, 0, len(lh.expensive.inflight))
+}
+
+func TestClientLimiterDisabled(t *testing.T) {
+	l := newClientLimiter(0)
+	for i := 0; i < 10; i++ {
+		testutil.Assert(t, l.acquire("client"), "request %d was limited", i)
+	}
+	l.release("client")
+}
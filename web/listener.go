@@ -0,0 +1,158 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	unixAddrPrefix    = "unix://"
+	systemdAddrPrefix = "systemd://"
+
+	// systemdListenFDsStart is the first file descriptor passed by systemd
+	// socket activation.
+	systemdListenFDsStart = 3
+)
+
+var errListenerClosed = errors.New("listener closed")
+
+// listen creates a listener for the given listen address. Besides TCP
+// addresses, it accepts "unix:///path/to.sock" for a Unix domain socket and
+// "systemd://" or "systemd://<n>" for the first or n-th file descriptor
+// passed by systemd socket activation.
+func listen(addr string) (net.Listener, error) {
+	switch {
+	case strings.HasPrefix(addr, unixAddrPrefix):
+		return listenUnix(strings.TrimPrefix(addr, unixAddrPrefix))
+	case strings.HasPrefix(addr, systemdAddrPrefix):
+		return listenSystemd(strings.TrimPrefix(addr, systemdAddrPrefix))
+	default:
+		return net.Listen("tcp", addr)
+	}
+}
+
+func listenUnix(path string) (net.Listener, error) {
+	if path == "" {
+		return nil, errors.New("empty unix socket path")
+	}
+	// Remove a socket left behind by a previous run. Other files are left
+	// untouched and make listening fail.
+	if fi, err := os.Lstat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	return net.Listen("unix", path)
+}
+
+func listenSystemd(index string) (net.Listener, error) {
+	n := 0
+	if index != "" {
+		var err error
+		if n, err = strconv.Atoi(index); err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid systemd file descriptor index %q", index)
+		}
+	}
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, errors.New("no file descriptors passed by systemd socket activation")
+	}
+	fds, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n >= fds {
+		return nil, fmt.Errorf("systemd passed %d file descriptors, cannot use index %d", fds, n)
+	}
+
+	fd := systemdListenFDsStart + n
+	syscall.CloseOnExec(fd)
+
+	f := os.NewFile(uintptr(fd), "systemd-listener-"+strconv.Itoa(n))
+	// FileListener duplicates the file descriptor, so the original one can
+	// be closed.
+	defer f.Close()
+
+	return net.FileListener(f)
+}
+
+// pipeListener is an in-memory net.Listener. It allows the gRPC gateway to
+// reach the gRPC server without going through the external listener, which
+// may require TLS client certificates the gateway does not have.
+type pipeListener struct {
+	conns  chan net.Conn
+	closed chan struct{}
+	once   sync.Once
+}
+
+func newPipeListener() *pipeListener {
+	return &pipeListener{
+		conns:  make(chan net.Conn),
+		closed: make(chan struct{}),
+	}
+}
+
+// Accept implements net.Listener.
+func (l *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.closed:
+		return nil, errListenerClosed
+	}
+}
+
+// Close implements net.Listener.
+func (l *pipeListener) Close() error {
+	l.once.Do(func() { close(l.closed) })
+	return nil
+}
+
+// Addr implements net.Listener.
+func (l *pipeListener) Addr() net.Addr {
+	return pipeAddr{}
+}
+
+// Dial returns the client end of a new connection to the listener. Its
+// signature matches the one expected by grpc.WithDialer.
+func (l *pipeListener) Dial(_ string, timeout time.Duration) (net.Conn, error) {
+	var expire <-chan time.Time
+	if timeout > 0 {
+		expire = time.After(timeout)
+	}
+	server, client := net.Pipe()
+	select {
+	case l.conns <- server:
+		return client, nil
+	case <-l.closed:
+		server.Close()
+		client.Close()
+		return nil, errListenerClosed
+	case <-expire:
+		server.Close()
+		client.Close()
+		return nil, errors.New("dial timeout")
+	}
+}
+
+type pipeAddr struct{}
+
+func (pipeAddr) Network() string { return "pipe" }
+func (pipeAddr) String() string  { return "pipe" }
@@ -0,0 +1,87 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/prometheus/prometheus/util/testutil"
+)
+
+func TestListenUnix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "web-listen")
+	testutil.Ok(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "prometheus.sock")
+
+	l, err := listen("unix://" + path)
+	testutil.Ok(t, err)
+	testutil.Equals(t, "unix", l.Addr().Network())
+
+	go func() {
+		c, err := l.Accept()
+		if err == nil {
+			c.Close()
+		}
+	}()
+	c, err := net.Dial("unix", path)
+	testutil.Ok(t, err)
+	c.Close()
+	testutil.Ok(t, l.Close())
+
+	// A stale socket left behind by a previous process must be replaced.
+	stale, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	testutil.Ok(t, err)
+	stale.SetUnlinkOnClose(false)
+	testutil.Ok(t, stale.Close())
+
+	l, err = listen("unix://" + path)
+	testutil.Ok(t, err)
+	testutil.Ok(t, l.Close())
+
+	// Regular files must not be removed.
+	file := filepath.Join(dir, "file")
+	testutil.Ok(t, ioutil.WriteFile(file, nil, 0644))
+	_, err = listen("unix://" + file)
+	testutil.NotOk(t, err)
+	_, err = os.Stat(file)
+	testutil.Ok(t, err)
+}
+
+func TestListenSystemd(t *testing.T) {
+	defer os.Unsetenv("LISTEN_PID")
+	defer os.Unsetenv("LISTEN_FDS")
+
+	// Without socket activation no file descriptors are available.
+	os.Unsetenv("LISTEN_PID")
+	_, err := listen("systemd://")
+	testutil.NotOk(t, err)
+
+	os.Setenv("LISTEN_PID", "1")
+	os.Setenv("LISTEN_FDS", "1")
+	_, err = listen("systemd://")
+	testutil.NotOk(t, err)
+
+	os.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+	_, err = listen("systemd://1")
+	testutil.NotOk(t, err)
+	_, err = listen("systemd://foo")
+	testutil.NotOk(t, err)
+}
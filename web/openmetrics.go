@@ -0,0 +1,246 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// openMetricsFormat is the content type of the OpenMetrics text format.
+const openMetricsFormat expfmt.Format = `application/openmetrics-text; version=0.0.1; charset=utf-8`
+
+// encoder encodes metric families into an exposition format.
+type encoder interface {
+	expfmt.Encoder
+	// Close terminates the exposition. It must only be called after all
+	// metric families were encoded successfully, so that clients can
+	// detect truncated expositions.
+	Close() error
+}
+
+// negotiateFormat returns the OpenMetrics format if the Accept header prefers
+// it and the format negotiated by expfmt otherwise.
+func negotiateFormat(h http.Header) expfmt.Format {
+	var (
+		best  string
+		bestQ float64
+	)
+	for _, accept := range h["Accept"] {
+		for _, part := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err != nil {
+				continue
+			}
+			q := 1.0
+			if s, ok := params["q"]; ok {
+				if q, err = strconv.ParseFloat(s, 64); err != nil {
+					continue
+				}
+			}
+			if q > bestQ {
+				best, bestQ = mediaType, q
+			}
+		}
+	}
+	if best == "application/openmetrics-text" {
+		return openMetricsFormat
+	}
+	return expfmt.Negotiate(h)
+}
+
+// newEncoder returns an encoder writing to w in the given format.
+func newEncoder(w io.Writer, format expfmt.Format) encoder {
+	if format == openMetricsFormat {
+		return &openMetricsEncoder{w: w}
+	}
+	return nopCloseEncoder{expfmt.NewEncoder(w, format)}
+}
+
+type nopCloseEncoder struct {
+	expfmt.Encoder
+}
+
+func (nopCloseEncoder) Close() error { return nil }
+
+// openMetricsEncoder encodes metric families in the OpenMetrics text format.
+type openMetricsEncoder struct {
+	w   io.Writer
+	buf bytes.Buffer
+}
+
+// Encode writes the metric family. Counters are exposed under their name
+// without the _total suffix, which their samples carry. No _created samples
+// are written, as the creation times of series are not known.
+func (e *openMetricsEncoder) Encode(mf *dto.MetricFamily) error {
+	name := mf.GetName()
+	if name == "" {
+		return fmt.Errorf("metric family has no name: %s", mf)
+	}
+	family := name
+	if mf.GetType() == dto.MetricType_COUNTER {
+		family = strings.TrimSuffix(name, "_total")
+	}
+
+	e.buf.Reset()
+	fmt.Fprintf(&e.buf, "# TYPE %s %s\n", family, openMetricsType(mf.GetType()))
+	if mf.Help != nil {
+		fmt.Fprintf(&e.buf, "# HELP %s %s\n", family, escapeOpenMetrics(mf.GetHelp()))
+	}
+	for _, m := range mf.Metric {
+		switch mf.GetType() {
+		case dto.MetricType_COUNTER:
+			e.sample(family+"_total", m, "", 0, m.GetCounter().GetValue())
+		case dto.MetricType_GAUGE:
+			e.sample(family, m, "", 0, m.GetGauge().GetValue())
+		case dto.MetricType_SUMMARY:
+			s := m.GetSummary()
+			for _, q := range s.GetQuantile() {
+				e.sample(family, m, "quantile", q.GetQuantile(), q.GetValue())
+			}
+			e.sample(family+"_sum", m, "", 0, s.GetSampleSum())
+			e.sample(family+"_count", m, "", 0, float64(s.GetSampleCount()))
+		case dto.MetricType_HISTOGRAM:
+			h := m.GetHistogram()
+			inf := false
+			for _, b := range h.GetBucket() {
+				e.sample(family+"_bucket", m, "le", b.GetUpperBound(), float64(b.GetCumulativeCount()))
+				inf = math.IsInf(b.GetUpperBound(), 1)
+			}
+			// The +Inf bucket is mandatory in OpenMetrics.
+			if !inf {
+				e.sample(family+"_bucket", m, "le", math.Inf(1), float64(h.GetSampleCount()))
+			}
+			e.sample(family+"_sum", m, "", 0, h.GetSampleSum())
+			e.sample(family+"_count", m, "", 0, float64(h.GetSampleCount()))
+		default:
+			e.sample(family, m, "", 0, m.GetUntyped().GetValue())
+		}
+	}
+	_, err := e.w.Write(e.buf.Bytes())
+	return err
+}
+
+// Close writes the terminating # EOF line.
+func (e *openMetricsEncoder) Close() error {
+	_, err := io.WriteString(e.w, "# EOF\n")
+	return err
+}
+
+// sample writes a sample of m with the given name and value. The label with
+// the name extra and the value ev is added if extra is not empty.
+func (e *openMetricsEncoder) sample(name string, m *dto.Metric, extra string, ev, v float64) {
+	e.buf.WriteString(name)
+	e.labels(m, extra, ev)
+	e.buf.WriteByte(' ')
+	e.buf.WriteString(formatOpenMetricsFloat(v))
+	if m.TimestampMs != nil {
+		// Timestamps are given in seconds.
+		e.buf.WriteByte(' ')
+		e.buf.WriteString(strconv.FormatFloat(float64(m.GetTimestampMs())/1000, 'f', -1, 64))
+	}
+	e.buf.WriteByte('\n')
+}
+
+func (e *openMetricsEncoder) labels(m *dto.Metric, extra string, ev float64) {
+	if len(m.Label) == 0 && extra == "" {
+		return
+	}
+	e.buf.WriteByte('{')
+	for i, lp := range m.Label {
+		if i > 0 {
+			e.buf.WriteByte(',')
+		}
+		fmt.Fprintf(&e.buf, `%s="%s"`, lp.GetName(), escapeOpenMetrics(lp.GetValue()))
+	}
+	if extra != "" {
+		if len(m.Label) > 0 {
+			e.buf.WriteByte(',')
+		}
+		fmt.Fprintf(&e.buf, `%s="%s"`, extra, formatOpenMetricsFloat(ev))
+	}
+	e.buf.WriteByte('}')
+}
+
+func openMetricsType(t dto.MetricType) string {
+	switch t {
+	case dto.MetricType_COUNTER:
+		return "counter"
+	case dto.MetricType_GAUGE:
+		return "gauge"
+	case dto.MetricType_SUMMARY:
+		return "summary"
+	case dto.MetricType_HISTOGRAM:
+		return "histogram"
+	}
+	return "unknown"
+}
+
+var openMetricsEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeOpenMetrics(s string) string {
+	return openMetricsEscaper.Replace(s)
+}
+
+func formatOpenMetricsFloat(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// metricsHandler returns a handler serving Prometheus's own metrics in the
+// OpenMetrics format to clients preferring it and calling next otherwise.
+func (h *Handler) metricsHandler(next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		format := negotiateFormat(r.Header)
+		if format != openMetricsFormat {
+			next.ServeHTTP(w, r)
+			return
+		}
+		mfs, err := prometheus.DefaultGatherer.Gather()
+		if err != nil {
+			http.Error(w, "An error has occurred during metrics collection:\n\n"+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		// Buffer the exposition to fail with a proper status code.
+		var buf bytes.Buffer
+		enc := newEncoder(&buf, format)
+		for _, mf := range mfs {
+			if err := enc.Encode(mf); err != nil {
+				http.Error(w, "An error has occurred during metrics encoding:\n\n"+err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		enc.Close()
+
+		w.Header().Set("Content-Type", string(format))
+		w.Write(buf.Bytes())
+	}
+}
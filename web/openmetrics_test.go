@@ -0,0 +1,162 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"bytes"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/prometheus/prometheus/pkg/textparse"
+	"github.com/prometheus/prometheus/util/testutil"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	for accept, exp := range map[string]expfmt.Format{
+		"":                         expfmt.FmtText,
+		"text/plain;version=0.0.4": expfmt.FmtText,
+		"application/openmetrics-text;version=0.0.1;q=1,text/plain;version=0.0.4;q=0.5,*/*;q=0.1":                                       openMetricsFormat,
+		"text/plain;version=0.0.4;q=0.5,application/openmetrics-text;version=0.0.1;q=0.4":                                               expfmt.FmtText,
+		"application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited,application/openmetrics-text;q=0.5": expfmt.FmtProtoDelim,
+	} {
+		h := http.Header{}
+		if accept != "" {
+			h.Set("Accept", accept)
+		}
+		testutil.Equals(t, exp, negotiateFormat(h))
+	}
+}
+
+func TestOpenMetricsEncoder(t *testing.T) {
+	label := func(name, value string) *dto.LabelPair {
+		return &dto.LabelPair{Name: proto.String(name), Value: proto.String(value)}
+	}
+	mfs := []*dto.MetricFamily{
+		{
+			Name: proto.String("requests_total"),
+			Help: proto.String("Total \"requests\"\nhandled."),
+			Type: dto.MetricType_COUNTER.Enum(),
+			Metric: []*dto.Metric{
+				{
+					Label:   []*dto.LabelPair{label("path", `/a\b`)},
+					Counter: &dto.Counter{Value: proto.Float64(3)},
+				},
+			},
+		},
+		{
+			Name: proto.String("temperature"),
+			Type: dto.MetricType_GAUGE.Enum(),
+			Metric: []*dto.Metric{
+				{
+					Gauge:       &dto.Gauge{Value: proto.Float64(math.Inf(-1))},
+					TimestampMs: proto.Int64(1500),
+				},
+			},
+		},
+		{
+			Name: proto.String("latency_seconds"),
+			Type: dto.MetricType_HISTOGRAM.Enum(),
+			Metric: []*dto.Metric{
+				{
+					Histogram: &dto.Histogram{
+						SampleCount: proto.Uint64(3),
+						SampleSum:   proto.Float64(1.5),
+						Bucket: []*dto.Bucket{
+							{UpperBound: proto.Float64(0.5), CumulativeCount: proto.Uint64(2)},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: proto.String("duration_seconds"),
+			Type: dto.MetricType_SUMMARY.Enum(),
+			Metric: []*dto.Metric{
+				{
+					Label: []*dto.LabelPair{label("job", "a")},
+					Summary: &dto.Summary{
+						SampleCount: proto.Uint64(2),
+						SampleSum:   proto.Float64(4),
+						Quantile: []*dto.Quantile{
+							{Quantile: proto.Float64(0.5), Value: proto.Float64(math.NaN())},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	enc := newEncoder(&buf, openMetricsFormat)
+	for _, mf := range mfs {
+		testutil.Ok(t, enc.Encode(mf))
+	}
+	testutil.Ok(t, enc.Close())
+
+	exp := `# TYPE requests counter
+# HELP requests Total \"requests\"\nhandled.
+requests_total{path="/a\\b"} 3
+# TYPE temperature gauge
+temperature -Inf 1.5
+# TYPE latency_seconds histogram
+latency_seconds_bucket{le="0.5"} 2
+latency_seconds_bucket{le="+Inf"} 3
+latency_seconds_sum 1.5
+latency_seconds_count 3
+# TYPE duration_seconds summary
+duration_seconds{job="a",quantile="0.5"} NaN
+duration_seconds_sum{job="a"} 4
+duration_seconds_count{job="a"} 2
+# EOF
+`
+	testutil.Equals(t, exp, buf.String())
+
+	// The exposition is understood by the scraper.
+	p := textparse.New(buf.Bytes(), string(openMetricsFormat))
+	n := 0
+	for p.Next() {
+		n++
+	}
+	testutil.Ok(t, p.Err())
+	testutil.Equals(t, 9, n)
+}
+
+func TestMetricsHandlerOpenMetrics(t *testing.T) {
+	h := &Handler{}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("text"))
+	})
+
+	r := httptest.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	h.metricsHandler(next).ServeHTTP(w, r)
+	testutil.Equals(t, "text", w.Body.String())
+
+	r.Header.Set("Accept", "application/openmetrics-text; version=0.0.1")
+	w = httptest.NewRecorder()
+	h.metricsHandler(next).ServeHTTP(w, r)
+	testutil.Equals(t, string(openMetricsFormat), w.Header().Get("Content-Type"))
+
+	body := w.Body.String()
+	testutil.Assert(t, strings.HasSuffix(body, "\n# EOF\n"), "expected exposition to end with # EOF")
+	// The creation times of series are not known.
+	testutil.Assert(t, !strings.Contains(body, "_created "), "expected no created timestamps, got:\n%s", body)
+}
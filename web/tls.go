@@ -0,0 +1,98 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"sync"
+)
+
+// tlsLoader holds the certificate and client CA pool the web server uses
+// for TLS. They are read from disk on reload so that they can be rotated
+// without restarting the server.
+type tlsLoader struct {
+	certFile           string
+	keyFile            string
+	clientCAFile       string
+	clientAuthOptional bool
+
+	mtx       sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+}
+
+func newTLSLoader(certFile, keyFile, clientCAFile string, clientAuthOptional bool) *tlsLoader {
+	return &tlsLoader{
+		certFile:           certFile,
+		keyFile:            keyFile,
+		clientCAFile:       clientCAFile,
+		clientAuthOptional: clientAuthOptional,
+	}
+}
+
+// reload reads the certificate, key and client CA files from disk. The
+// previously loaded state is kept if any of them cannot be read.
+func (l *tlsLoader) reload() error {
+	cert, err := tls.LoadX509KeyPair(l.certFile, l.keyFile)
+	if err != nil {
+		return fmt.Errorf("unable to load TLS certificate %q and key %q: %s", l.certFile, l.keyFile, err)
+	}
+
+	var pool *x509.CertPool
+	if l.clientCAFile != "" {
+		b, err := ioutil.ReadFile(l.clientCAFile)
+		if err != nil {
+			return fmt.Errorf("unable to read client CA file %q: %s", l.clientCAFile, err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return fmt.Errorf("unable to parse client CA file %q", l.clientCAFile)
+		}
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l.cert = &cert
+	l.clientCAs = pool
+
+	return nil
+}
+
+// config returns a TLS configuration that always serves the most recently
+// loaded certificate and client CA pool.
+func (l *tlsLoader) config() *tls.Config {
+	return &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			l.mtx.RLock()
+			defer l.mtx.RUnlock()
+
+			cfg := &tls.Config{
+				Certificates: []tls.Certificate{*l.cert},
+				NextProtos:   []string{"h2", "http/1.1"},
+			}
+			if l.clientCAs != nil {
+				cfg.ClientCAs = l.clientCAs
+				cfg.ClientAuth = tls.RequireAndVerifyClientCert
+				if l.clientAuthOptional {
+					cfg.ClientAuth = tls.VerifyClientCertIfGiven
+				}
+			}
+			return cfg, nil
+		},
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2017 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"context"
+	"crypto/tls"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/storage/tsdb"
+	"github.com/prometheus/prometheus/util/testutil"
+	libtsdb "github.com/prometheus/tsdb"
+	"golang.org/x/net/http2"
+)
+
+const (
+	testCertFile = "../util/httputil/testdata/server.crt"
+	testKeyFile  = "../util/httputil/testdata/server.key"
+	testCAFile   = "../util/httputil/testdata/tls-ca-chain.pem"
+)
+
+func TestTLSLoaderReload(t *testing.T) {
+	l := newTLSLoader(testCertFile, testKeyFile, testCAFile, false)
+	testutil.Ok(t, l.reload())
+	testutil.Assert(t, l.cert != nil, "certificate not loaded")
+	testutil.Assert(t, l.clientCAs != nil, "client CAs not loaded")
+
+	cfg, err := l.config().GetConfigForClient(nil)
+	testutil.Ok(t, err)
+	testutil.Equals(t, tls.RequireAndVerifyClientCert, cfg.ClientAuth)
+
+	// A failed reload must keep the previously loaded certificate.
+	cert := l.cert
+	l.keyFile = "does-not-exist"
+	testutil.NotOk(t, l.reload())
+	testutil.Assert(t, l.cert == cert, "certificate changed after failed reload")
+
+	l = newTLSLoader(testCertFile, testKeyFile, testKeyFile, false)
+	testutil.NotOk(t, l.reload())
+
+	l = newTLSLoader(testCertFile, testKeyFile, testCAFile, true)
+	testutil.Ok(t, l.reload())
+	cfg, err = l.config().GetConfigForClient(nil)
+	testutil.Ok(t, err)
+	testutil.Equals(t, tls.VerifyClientCertIfGiven, cfg.ClientAuth)
+}
+
+func TestTLSServer(t *testing.T) {
+	t.Parallel()
+	dbDir, err := ioutil.TempDir("", "tsdb-tls")
+	testutil.Ok(t, err)
+	defer os.RemoveAll(dbDir)
+
+	db, err := libtsdb.Open(dbDir, nil, nil, nil)
+	testutil.Ok(t, err)
+
+	opts := &Options{
+		ListenAddress:        ":9092",
+		ReadTimeout:          30 * time.Second,
+		MaxRequestsPerClient: 512,
+		Storage:              &tsdb.ReadyStorage{},
+		RoutePrefix:          "/",
+		MetricsPath:          "/metrics",
+		EnableAdminAPI:       true,
+		TSDB:                 func() *libtsdb.DB { return db },
+		TLSCertFile:          testCertFile,
+		TLSKeyFile:           testKeyFile,
+		Flags:                map[string]string{},
+	}
+
+	webHandler := New(nil, opts)
+	go webHandler.Run(context.Background())
+	webHandler.Ready()
+
+	// Give some time for the web goroutine to run since we need the server
+	// to be up before starting tests.
+	time.Sleep(5 * time.Second)
+
+	// Clients negotiate HTTP/2 through ALPN like curl and browsers do.
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	testutil.Ok(t, http2.ConfigureTransport(tr))
+	client := &http.Client{Transport: tr}
+
+	resp, err := client.Get("https://localhost:9092/-/healthy")
+	testutil.Ok(t, err)
+	testutil.Equals(t, http.StatusOK, resp.StatusCode)
+	testutil.Equals(t, 2, resp.ProtoMajor)
+
+	// HTTP/1.1 clients keep working.
+	http1Client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	resp, err = http1Client.Get("https://localhost:9092/-/healthy")
+	testutil.Ok(t, err)
+	testutil.Equals(t, http.StatusOK, resp.StatusCode)
+	testutil.Equals(t, 1, resp.ProtoMajor)
+
+	// The gRPC gateway must keep working behind TLS.
+	resp, err = client.Post("https://localhost:9092/api/v2/admin/tsdb/snapshot", "", strings.NewReader(""))
+	testutil.Ok(t, err)
+	testutil.Equals(t, http.StatusOK, resp.StatusCode)
+
+	_, err = http.Get("http://localhost:9092/-/healthy")
+	testutil.NotOk(t, err)
+}
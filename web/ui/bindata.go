@@ -19,6 +19,7 @@
 // web/ui/static/js/graph.js
 // web/ui/static/js/graph_template.handlebar
 // web/ui/static/js/prom_console.js
+// web/ui/static/js/settings.js
 // web/ui/static/js/targets.js
 // web/ui/static/vendor/bootstrap-3.3.1/css/bootstrap-theme.min.css
 // web/ui/static/vendor/bootstrap-3.3.1/css/bootstrap.min.css
@@ -109,7 +110,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _webUiTemplates_baseHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xbc\x56\xdd\x6e\xdc\x36\x13\xbd\xcf\x53\xcc\xc7\x04\x5f\xec\x0b\xad\x50\xe4\xa6\x88\x25\x15\x89\xe3\x34\x06\x82\x66\x61\x6f\x83\x16\x45\x61\xcc\x4a\x23\x89\x0e\x45\x2a\xe4\x48\xf5\x62\xb1\xef\x5e\x70\xf5\x53\xad\xec\xb5\xfb\x87\x5e\x89\xa2\x0e\xcf\x0c\xcf\x9c\xa1\x18\xfd\xef\xdd\xa7\xf3\xd5\xcf\xcb\x0b\x28\xb9\x52\xc9\xb3\xc8\x3f\x40\xa1\x2e\x62\x41\x5a\x24\xcf\x00\xa2\x92\x30\xf3\x03\x80\xa8\x22\x46\x28\x99\xeb\x80\xbe\x36\xb2\x8d\xc5\xb9\xd1\x4c\x9a\x83\xd5\xa6\x26\x01\x69\xf7\x16\x0b\xa6\x3b\x0e\x3d\xd5\x19\xa4\x25\x5a\x47\x1c\x37\x9c\x07\xdf\x8a\x9e\x87\x25\x2b\x4a\x96\xd6\x54\xc4\x25\x35\x0e\x56\xb2\x22\xb8\x26\x2b\xc9\xc1\xb9\x51\x8a\x52\x96\x46\x03\xea\x0c\x96\xd6\xa4\xe4\x9c\xd4\x85\x07\xb4\x64\xa3\xb0\x5b\xde\x51\x29\xa9\xbf\x80\x25\x15\x0b\x57\x1a\xcb\x69\xc3\x20\x53\xa3\x05\x94\x96\xf2\x58\x6c\xb7\x50\x23\x97\x4b\x4b\xb9\xbc\x83\xdd\x2e\x74\x8c\x2c\xd3\x50\x56\x45\x98\x63\xeb\xa1\x0b\x99\x9a\xef\xda\x78\xbb\x85\x75\x23\x55\xf6\x99\xac\xf3\xb1\x77\xbb\x21\x5b\x97\x5a\x59\x33\x38\x9b\x1e\xe7\x6b\x49\x67\xc6\x86\xb7\x2e\xbc\xfd\xda\x90\xdd\x2c\x2a\xa9\x17\xb7\xee\x08\x6f\x14\x76\x9c\x7f\x3d\xc0\xda\x18\x76\x6c\xb1\x0e\x5e\x2d\x5e\x2d\xbe\xf1\x01\xc7\xa9\x3f\x1b\x73\x22\x1c\x6f\x6a\xea\xcb\x95\x3a\x27\x7a\x21\x79\xa3\xc8\x95\x44\xfc\x94\x8a\x47\x92\x4a\xdd\x3c\xab\xd4\x1d\x4b\xeb\xdf\x4b\xc6\x47\xad\x47\x4b\x3d\x16\x72\xaa\x7a\x97\x00\x40\x8b\x16\x96\x6f\x56\x1f\x6e\x96\x57\x17\xef\x2f\x7f\x82\x18\xee\x05\x12\x67\x13\xec\xdb\x1f\x2f\x3f\xbe\xbb\xf9\x7c\x71\x75\x7d\xf9\xe9\x87\x1e\x3d\x8f\x34\xe0\x5f\x9c\xe4\x8d\xee\x1c\x7d\x72\x0a\xdb\x7e\xd6\xcf\xbf\xfc\x25\x43\xc6\x80\x4d\x51\x28\xbf\x77\x63\x14\xcb\x5a\xfc\xfa\xf2\x74\xd1\x8f\x4f\x4e\x7b\xf8\xae\x1b\xcc\xca\xb8\xdd\x32\x55\xb5\x42\x26\x10\xbe\x51\x05\x2c\x76\x3b\xdf\xb5\x61\xd7\xb6\x7e\xb8\x36\xd9\xa6\xd7\x59\x63\x0b\xa9\x42\xe7\x62\xa1\xb1\x5d\xa3\x85\xee\x11\x48\xdd\x92\x75\x34\xbc\xe6\xf2\x8e\xb2\x80\x4d\x2d\x06\x7d\xa2\x4c\x8e\x4b\x7d\x9f\xa3\xd4\x64\x83\x5c\x35\x32\x1b\x31\x87\xa8\x9e\xca\xe7\x41\x76\x82\xf1\x19\x35\xcc\x46\xf7\x05\xef\x5e\xc4\x6c\x59\x27\x09\xa4\x46\x29\xac\x1d\x65\x02\x0e\x94\x1a\xe6\x87\x69\xb4\x05\x71\x2c\x9e\x77\xab\x05\xa0\x95\x18\xd0\x5d\x8d\x3a\xa3\x2c\x16\x39\x2a\x8f\xdd\xcf\xfa\xec\xad\x51\x63\xa8\x83\xd4\xbc\x2f\x6a\xd4\x43\x32\xce\x06\x46\xab\x8d\x48\x56\x5d\x3a\x1a\x5b\x59\xa0\xaf\x64\x14\x7a\xdc\x23\x4b\xfd\xd1\x12\xec\xe9\xff\x2b\x68\x14\x76\x52\x1e\xcc\xe1\x4c\xd7\xb5\x45\x9d\x1d\x6d\x25\x31\x39\x94\xa3\x10\x27\x85\x0d\x33\xd9\xce\xea\x2c\xb3\x51\xc2\x59\x90\xa1\x3a\x63\xf9\x0e\xcb\xdf\xa8\x09\x7e\xb0\xdc\x64\xa8\x28\xe7\x59\x55\xb6\xdb\x17\xa9\xd1\xce\x28\x72\xf0\x3a\x86\x61\xbc\x44\x2e\xf7\x7e\x9f\x22\x65\x0e\x23\x78\xf6\x31\x52\x32\x89\x70\xdc\xfd\x04\x26\x92\xf3\x7e\xec\xf7\x1d\x85\x4a\xce\x13\x00\xd2\x19\x3c\xce\x37\x53\x13\x15\x59\x76\x22\x79\xb3\x7f\x3e\xcc\xfb\x38\x43\x61\xb1\x2e\x45\xf2\xbd\x7f\x1c\x5d\x3f\x88\x99\x59\x53\x67\xe6\x37\x3d\x93\x6e\x6f\x82\x8e\xff\xb9\x98\x63\xfb\x86\x9a\x75\xd7\xc8\x04\xd6\xa8\x49\x8b\xee\xfb\xa7\x44\x57\x9b\xba\xa9\x63\xc1\xb6\xa1\x23\xad\x96\x5c\x33\x72\xe3\x0e\xcd\x9b\xa2\x25\x1e\x9d\x7b\xe0\xaf\x7b\xce\x18\x13\xac\x48\x37\xf7\x76\xf4\x94\x6e\x6e\x1f\x5d\x24\x57\x8d\x66\x7f\xb5\xf8\x3f\x56\xf5\x19\xbc\xf5\xe7\x33\x5c\xea\xdc\xd8\xaa\x6f\xe2\x87\x24\x7d\x9a\x3e\x57\x58\x38\xef\x98\xaa\x42\x9d\x05\x1f\xa5\x26\x78\xef\xe7\xfe\x2e\x61\x6a\x74\x2e\x8b\xbd\x07\x73\x59\x34\xf6\x1f\x65\x67\x1b\x45\xfb\xbd\x1f\x35\xf3\xd3\x1c\xdd\x81\xea\x44\xb2\xea\x06\xc7\x78\xa2\xb0\x51\x33\x43\x3e\x68\xf1\x63\x8e\xf4\x97\x49\xf7\x3a\x9c\xfe\xb8\xa5\x09\x33\x93\x3a\x01\xc3\xa1\x7e\xb3\x56\xa8\xbf\x88\xe4\x03\xa9\xfa\x9e\x69\xe6\xe1\x0e\x13\x3a\x38\xb6\x26\x2f\x51\xa8\xb1\x7d\xe0\x17\xda\xdf\x60\xff\xf8\x8b\x76\xff\xce\x28\xec\xae\xc7\xbf\x07\x00\x00\xff\xff\x54\x18\xdc\x13\x2f\x0b\x00\x00")
+var _webUiTemplates_baseHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xcd\x58\xdb\x6e\xdc\x36\x10\x7d\xcf\x57\x4c\x99\xa0\xb1\x1f\xb4\x42\x90\x97\x22\x96\x54\x38\xb6\xd3\x18\x70\x9a\x85\xbd\x0d\x5a\x14\xc5\x82\x2b\x51\x12\x6d\x4a\x54\x48\x6a\x6b\x63\xb1\xff\xde\xa1\x6e\x95\xb4\x57\x17\x69\xd1\x17\x93\x1a\x1f\xce\x0c\xcf\x5c\x34\x5a\xef\xbb\xcb\xcf\x17\xb3\xdf\xa6\x57\x90\x9a\x4c\x04\x2f\x3c\xbb\x80\xa0\x79\xe2\x13\x96\x93\xe0\x05\x80\x97\x32\x1a\xd9\x0d\x6e\x33\x66\x28\x22\x4d\xe1\xb0\xaf\x25\x5f\xfa\xe4\x42\xe6\x86\xe5\xc6\x99\x3d\x15\x8c\x40\x58\x3f\xf9\xc4\xb0\x47\xe3\x5a\x55\x67\x10\xa6\x54\x69\x66\xfc\xd2\xc4\xce\x0f\xa4\xd1\x63\xb8\x11\x2c\x98\x2a\x89\x0a\x53\x56\x6a\x98\xf1\x8c\xc1\x1d\x53\x9c\x69\xb8\x90\x42\xb0\xd0\x70\x99\x03\xcd\x23\x40\x54\xc8\xb4\xe6\x79\x62\x01\x4b\xa6\x3c\xb7\x3e\x5e\xab\x12\x3c\x7f\x00\xc5\x84\x4f\x74\x2a\x95\x09\x4b\x03\x1c\xfd\x20\x90\x2a\x16\xfb\x64\xb5\x82\x82\x9a\x74\x8a\x0f\xfc\x11\xd6\x6b\x57\x1b\x6a\x78\xe8\xf2\x2c\x71\x63\xba\xb4\xd0\x09\xfe\xf9\x71\xe9\x23\x72\x51\x72\x11\x7d\x61\x4a\x5b\xdb\xeb\x75\xeb\xad\x0e\x15\x2f\x0c\x68\x15\xee\xd6\xb7\x64\x79\x24\x95\x7b\xaf\xdd\xfb\xaf\x25\x53\x4f\x93\x8c\xe7\x93\x7b\xbd\x43\xaf\xe7\xd6\x3a\x9f\x6f\x60\x21\xa5\xd1\x46\xd1\xc2\x79\x3b\x79\x3b\x79\x63\x0d\x76\xa2\x63\x6d\xf6\x88\x33\x18\xb7\x26\x5c\xa1\xd6\xa4\x21\xd2\x3c\x09\xa6\x53\xc6\xcc\x21\x16\x77\x38\x85\xaa\x46\x5e\xa1\x64\x2f\xc5\xdf\xc2\x19\x6b\xb5\xe8\x52\xea\xa0\xc9\x63\x48\x47\x76\x31\x77\x0d\xe6\x9e\x3e\x9e\xd8\x7e\x68\x01\x96\x54\xc1\xf4\x7c\xf6\x71\x3e\xbd\xbd\xfa\x70\xfd\x2b\xf8\xb0\x61\x8d\x9c\xf5\xb0\xef\x7f\xb9\xbe\xb9\x9c\x7f\xb9\xba\xbd\xbb\xfe\xfc\x73\x83\x1e\x9b\x6c\xf1\xaf\x4e\xe2\x32\xaf\x2b\xe5\xe4\x14\x56\x8d\xd4\xca\x5f\xff\x1e\x51\x43\x1d\x23\x93\x44\x58\x4e\xa5\x14\x86\x17\xe4\x8f\xd7\xa7\x93\x66\x7f\x72\xda\xc0\xd7\xf5\x66\x74\x8b\xd5\xca\xb0\xac\x10\xd4\x30\x20\xb6\x01\x10\x98\xac\xd7\xb6\x1b\xb8\x75\x3b\xb0\xdb\x85\x8c\x9e\x1a\x32\x73\xba\x84\x50\x50\xad\x7d\x82\xdb\x05\xde\xa3\x5e\x1c\x9e\x63\xc5\x6a\xd6\x3e\xe2\x85\x59\x84\x6e\x15\xa4\xe5\xc7\x8b\x78\x77\xd4\xf6\x0f\xca\x73\x86\x38\x51\xf2\xa8\xc3\x0c\x51\x8d\x2a\xeb\x07\x53\x3d\x8c\xf5\xa8\x34\x06\xc9\xa8\x13\xa9\x7e\x20\xa3\x63\x35\x25\xd8\xaa\x84\xa0\x85\x66\x78\xb1\x01\x53\xad\xbc\x15\x53\x95\x60\xf3\x22\x2f\xeb\xd3\x04\xa8\xe2\xd4\x61\x8f\x05\x76\x26\x16\xf9\x24\xa6\xc2\x62\x2b\xa9\xf5\x5e\x49\xd1\x99\x1a\xb8\x66\xf3\x02\x0f\xb5\xce\x68\xe5\xc8\x5c\x3c\x91\x60\x56\xbb\x83\x27\x78\x42\x6d\x24\x31\x0e\x88\xdb\x73\xd4\xb6\x2c\xa7\x52\xff\x5f\x41\x3d\xb7\xa6\x72\x20\xa3\x23\x5e\x17\x0a\x29\xd9\x59\xa2\xa4\xd7\xec\x3d\x97\xf6\x02\xeb\x62\x64\x47\x71\xe6\x51\x47\xe1\xc8\x48\x1b\x9d\x2e\x7c\xc3\xf0\x97\xa2\x87\x6f\x53\xae\xb7\x15\x2c\x36\xa3\xa8\xac\x56\x3c\x86\x5c\x1a\xa0\x09\xbe\xb9\x3e\xc9\x88\x55\x69\xde\x07\xbc\x42\x6a\xb4\xc4\x26\x04\xef\x7c\x68\xf7\x53\xbc\xde\x06\x12\x55\x75\xe0\xd1\x3f\xb1\xc3\x05\xc8\x59\x4b\x4f\x0f\x46\x82\x8b\x66\x6f\x89\xf1\x5c\x04\x8e\xd4\x02\x76\x59\xd8\xaf\x6f\x44\x37\x15\x4c\x19\x4d\x82\xf3\x6a\xdd\xae\x77\xbf\x86\x04\x3b\x77\x4a\x82\x9f\xec\xf2\x4c\xbf\xda\x18\x44\x4a\x16\x91\xfc\x33\x1f\x31\x5e\xe5\x4e\x6d\xf5\x25\x19\x63\x9b\x3a\x1c\x15\x65\xa7\x09\xb0\xbe\x7a\x95\x5d\x95\x5d\x4a\x75\x21\x8b\xb2\xc0\x2e\xa7\x4a\xb6\xa3\x42\x83\x3b\x6c\xe8\x38\x67\x0c\x72\x3e\xa4\x0a\xdf\x2a\x6d\xc2\x0f\xd2\x72\x23\xa1\x3a\x07\x33\x96\x97\x1b\x37\x3a\xc4\xa6\xae\xac\x93\xe0\xb6\xcc\x8d\x9d\x74\xbe\xa7\x59\x71\x06\xef\x6d\x5b\x87\xeb\x3c\x96\x2a\x6b\x6a\x7f\x1b\xd1\x87\xd5\xc7\x82\x26\xda\xe6\x51\x96\xe1\xad\x9d\x1b\x6c\xa1\xf0\xc1\xca\xfe\xa9\x42\xcc\xce\x98\x27\x55\x66\xe2\x5a\xaa\x03\xde\x1d\x2c\xa2\xc3\x16\x55\x89\x05\x60\x09\xda\x59\x07\xbb\x73\xee\xb0\xf6\xba\x8d\xa3\xfe\x59\xbd\xd9\x65\xc1\x73\x4b\x31\xaa\x92\xad\x75\xb3\x2b\xa1\xed\x68\xac\xdf\xb9\xfd\x31\x84\x4b\x37\x92\x21\x4e\x33\xed\xab\x64\xbe\xc0\xf1\xfa\x81\x04\x1f\x99\x28\x36\x72\x6e\x6c\x6e\xec\xd0\xc1\x16\xa7\x78\x92\x8e\x7b\xdc\xff\xb6\x26\x9b\xd1\xea\xb9\x55\xd9\xbe\x23\xda\xd1\x6c\x5e\xd5\xe4\xd6\x4a\x85\x21\x66\x4b\x1d\xf4\x06\x0b\x5b\x87\x4e\xa2\x64\x59\x6c\x01\x5a\x1a\xe9\x82\x89\x60\x96\xb2\x8c\x61\x9c\xaa\x87\x6d\xb0\xc1\x0b\x6d\x53\x41\x6b\x4d\xd1\x88\x4b\x9c\x8f\x70\xf4\x45\x2e\x3c\x9e\x17\xf8\xe9\x52\x4f\x2e\xd5\xbf\x08\x86\x34\xb3\xb3\x9b\xb5\x47\x70\x36\x14\x25\x3e\x89\x3a\xbe\x70\x63\xd7\x3d\x5e\x7c\x1b\x6b\x11\x55\x98\xa9\x70\x89\xcb\xbf\x6e\x8b\x96\x46\xa2\xad\x73\x5c\xf6\xb1\xeb\x6e\xa5\x77\x97\xb8\x3f\x5c\xa6\x2c\x7c\x58\xc8\xc7\x7d\xb1\x1d\xf8\xda\x1d\x68\xdc\xad\xbe\xeb\xe6\x29\xd7\x46\x2a\x9c\xdc\xe0\x96\x85\x52\x45\x50\x89\xa1\x11\xef\xf4\xfc\x08\x07\x8f\x4a\x3f\x40\x54\x2f\xf3\xab\xd7\xf4\x1c\x47\xaf\x04\x99\xbe\x64\x31\x2d\x85\x81\x4a\x08\x95\x70\x1f\x93\xfd\xbb\xda\x8f\x2e\x32\x2c\xaa\xbe\xea\x86\x81\x81\xa8\xef\x75\x33\xfa\x42\xa5\xd2\xd1\x19\x01\xfc\x74\x08\x59\x2a\x05\x8e\xe8\x3e\x79\x93\x92\x23\x19\xd9\x22\xdc\xdf\x12\x07\x07\x7a\x0f\x9e\x8b\x0d\x71\xcb\xb7\x4c\xf3\x13\xc5\xdf\x9f\x33\xf5\x47\x8c\xe7\xd6\xbf\x7f\xfc\x05\x37\x39\xa4\x13\x10\x11\x00\x00")
 
 func webUiTemplates_baseHtmlBytes() ([]byte, error) {
 	return bindataRead(
@@ -124,7 +125,7 @@ func webUiTemplates_baseHtml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "web/ui/templates/_base.html", size: 2863, mode: os.FileMode(436), modTime: time.Unix(1504897784, 0)}
+	info := bindataFileInfo{name: "web/ui/templates/_base.html", size: 4368, mode: os.FileMode(436), modTime: time.Unix(1792185174, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -189,7 +190,7 @@ func webUiTemplatesFlagsHtml() (*asset, error) {
 	return a, nil
 }
 
-var _webUiTemplatesGraphHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xbc\x95\xc1\x92\x9b\x30\x0c\x86\xef\x79\x0a\x8f\xef\x84\x43\xae\x21\x9d\x9e\x7a\xed\xa9\xd7\x1d\x61\x2b\xb5\x12\x63\x53\x4b\xb0\x21\x0c\xef\xde\x21\x59\x48\x3a\xd3\xdd\xee\x4e\xc3\x5e\x40\xd8\xb2\xfe\x4f\xb6\xb0\xfa\xde\xe2\x9e\x02\x2a\xed\x10\xac\x1e\x86\x95\x52\x4a\x6d\x3d\x85\xa3\x92\xae\xc6\x42\x0b\x9e\x24\x37\xcc\x5a\x25\xf4\x85\x66\xe9\x3c\xb2\x43\x14\xad\x5c\xc2\x7d\xa1\xfb\x5e\xd5\x20\xee\x7b\xc2\x3d\x9d\xd4\x30\xe4\x2c\x20\x64\xc6\x35\xf9\xcf\x04\xb5\x5b\x1b\xe6\x2f\x6d\xd1\xf7\xaa\x6c\xc8\xdb\x1f\x98\x98\x62\x50\xc3\xa0\x77\xab\xc7\xc9\xb5\x18\x6c\x4c\x79\x22\x73\x64\x07\xcf\xb3\xb1\xae\x28\xbc\x45\xf0\x68\x00\x8c\x01\xd8\x42\xc8\xca\x18\x85\x25\x41\x9d\x59\x10\x14\xaa\xb0\x26\x73\xc4\x94\xbf\x36\xf1\x2f\xd2\x2b\x2a\x9b\x44\xb5\x28\x4e\xe6\xfd\x7b\xf1\xf2\x6d\x37\xeb\x76\xb3\x3e\xbc\x26\xb0\xcd\xaf\xb1\x77\x8f\x10\xf2\xd0\xc5\x46\x2e\x29\x2d\x29\xf8\xc7\x29\x2f\x20\x54\xc5\x0a\x83\xbc\xbc\x3e\x45\x24\x1b\x0b\xe2\x1c\x03\x66\xcf\x24\x6e\x2c\x11\x58\x4a\xf7\x3f\x4b\x75\x01\xa2\x59\x6f\x93\x8d\x7f\x23\x8c\x97\xd2\x5f\x07\x97\x02\xd8\x37\xe7\x73\x77\x7d\xbe\x27\xfc\xc7\x8f\xba\x61\x01\xe3\x70\x36\x96\x4a\xe4\xc0\xf9\xe1\x57\x83\xa9\x5b\x33\x7a\x34\x42\x71\x61\x19\x17\xe5\x88\x1d\x3f\x76\xd7\x0e\x53\x0b\xf9\x68\x54\xb2\x85\xbe\xac\x7c\x12\xac\x6a\x0f\x82\xfa\xfe\x7e\x3f\x65\x0e\x82\xf5\x58\x42\xe2\x6c\xf6\xb8\x0b\xd6\xf7\x18\xec\x30\xac\x56\xb7\x06\x69\x62\x10\x0c\x32\xf7\x48\x4b\xed\x9d\xcc\x38\x0b\x14\x30\x69\x65\x3c\x30\x17\x7a\x1e\xc9\xf6\xbe\x21\x3b\xb5\x9a\xdc\x52\xbb\xbb\x45\x78\xd3\xf9\xea\xb3\xdb\x52\xa8\x1b\x99\x5c\x4b\x09\xaa\x94\x90\xd5\x89\x2a\x48\xdd\x94\x17\x37\x65\x45\xa2\x55\x0b\xbe\xc1\x42\x7f\xb5\x56\x7d\x1b\xc9\xf4\x05\x12\xac\x7d\xba\x80\x8e\x49\xde\x08\xae\xe6\x94\xec\xef\x00\x00\x00\xff\xff\x1e\x45\xed\x26\x0d\x08\x00\x00")
+var _webUiTemplatesGraphHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xbd\x55\x4d\x73\x9b\x30\x10\xbd\xe7\x57\x68\x74\xc7\x1c\x72\xeb\x18\x77\x3a\x3d\xf4\xda\x53\xaf\x9e\x05\xad\x8b\x6c\x21\x51\x69\xe5\x18\x33\xfe\xef\x5d\x20\x10\x9c\x36\x69\x32\x31\x65\x06\xd8\x45\xbb\xfb\xf6\x43\xe2\xb5\xad\xc2\x9d\xb6\x28\x64\x89\xa0\xe4\xe5\x72\x27\xf8\x5a\x1b\x6d\x0f\x82\x9a\x1a\x33\x49\x78\xa2\xb4\x08\x41\x0a\x8f\x26\x93\x81\x1a\x83\xa1\x44\x24\x29\x4a\x8f\xbb\x4c\xb6\xad\xa8\x81\xca\xef\xac\xe8\x93\xb8\x5c\xd2\x40\x40\xba\xe8\x7c\xd2\x9f\x1e\xea\x72\xc5\xd2\xe7\x63\xc6\x76\x79\xd4\x46\xfd\x40\x1f\xb4\xb3\x6c\x29\x37\x77\xb7\x83\x3b\xa2\x55\xce\xa7\x5e\x17\x87\x50\xc2\xc3\x24\xac\x2a\x6d\x5f\xcb\xe0\xd6\x09\xa0\xb3\x10\x14\xd8\x24\x77\x8e\x02\x71\x03\x12\x05\x84\xa4\x2b\xac\x39\x25\xf4\xe9\x4b\x0b\xff\xca\x74\x48\x35\x14\x5e\xd7\x24\x82\x2f\xde\xde\x8b\x47\x5d\xdd\xaf\x8e\xf7\xab\xfd\x4b\x00\xeb\x74\x88\xbd\xb9\x05\x90\x81\xc6\x45\xea\x4b\x5a\x12\xf0\x6a\xca\x0b\x00\x55\xae\x42\x4b\x8f\xaf\xff\x02\x92\x74\x1b\xe2\xec\x2c\x26\x0f\x9a\xca\x6e\x8b\xc0\x52\xb8\x1f\xdc\xaa\x0b\x64\x34\xe1\xdd\x27\xdd\x69\x84\xee\xa7\xf4\xd7\x8f\x4b\x25\xb0\x8b\xe7\x73\x33\x3c\xdf\x12\xfe\xfd\xa3\x8e\xac\x17\x25\x4e\xc2\x52\x85\xec\x43\xba\xff\x15\xd1\x37\xab\x80\x06\x0b\xe2\x88\xcb\xc2\x94\x8e\x0e\xd8\x84\xdb\x76\x6d\x3f\x52\xc8\x7b\xa3\x6a\x95\xc9\xde\x73\x4b\x58\xd5\x86\x37\xaf\x9c\xff\xdf\x4f\x49\x09\x56\x19\xcc\xc1\x87\x64\xb2\x98\x05\x6b\x5b\xae\x8f\xd9\x90\x85\x91\x20\x0b\x67\x89\xcf\xe7\xc4\x91\x4a\x1f\x67\x30\xdd\x2a\xb0\x9d\x97\xa2\x30\x10\x42\x26\xa7\x2f\xc9\xce\x44\xad\x46\xaa\x49\xd9\x6f\xf3\x14\xe1\x55\xe3\x6b\x9b\x9d\xf3\x55\xa2\x2d\x73\x15\x4e\xeb\x6c\xa1\x6d\x1d\x69\xb4\xc9\xc9\x0a\xbe\x93\xda\xeb\x0a\x7c\x33\x16\x1d\x62\x5e\x69\x66\xb0\x23\x98\xc8\xea\x17\xa5\xc4\xb7\x2e\x6d\xd9\x57\x00\x4a\x6d\xfb\x2a\xe6\x61\xf3\x48\xc4\x3d\x1e\xfc\x07\x45\x3e\x47\xe1\xd6\x40\x34\x34\x44\xa9\xd1\x57\xd0\x11\x29\x83\x6a\x32\xec\xf5\xd5\xd5\x8d\x00\x31\x90\xab\x13\x60\x8c\xe8\x61\x82\xdc\xf4\x4b\x93\xc7\x3a\x1d\x00\xfe\xa8\xea\x69\x64\xf2\xaa\x0b\x5d\xbb\xbc\x33\xcf\x80\xb7\xd1\x9b\x8e\xb6\x41\x39\x6b\x1a\xd1\x53\x77\x26\x95\x0e\x3c\xde\xe6\x93\xb0\x6e\xd6\xb8\xf9\x1c\x06\x71\x1c\xf9\x6f\xc2\xc3\x15\x3a\x13\x09\x00\x00")
 
 func webUiTemplatesGraphHtmlBytes() ([]byte, error) {
 	return bindataRead(
@@ -204,12 +205,12 @@ func webUiTemplatesGraphHtml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "web/ui/templates/graph.html", size: 2061, mode: os.FileMode(436), modTime: time.Unix(1509022446, 0)}
+	info := bindataFileInfo{name: "web/ui/templates/graph.html", size: 2323, mode: os.FileMode(436), modTime: time.Unix(1792182618, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _webUiTemplatesRulesHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\x8f\x41\x4f\xf3\x30\x0c\x86\xef\xf9\x15\xef\x97\x7b\x5a\x69\xc7\x8f\x2c\x47\xe0\x00\x08\x01\x7f\x20\x2c\x2e\xb5\xd4\x65\x51\xd2\x4e\x93\x2c\xff\x77\xd4\x95\x09\xe1\x8b\xad\xe7\xb1\x6c\xbd\x22\x89\x06\xce\x04\x3b\x52\x4c\x56\xd5\xff\x73\x0e\x99\x2f\x70\x2e\x88\x50\x4e\xaa\xc6\xfc\x6e\x1d\x4e\x79\xa6\x3c\x5b\x55\x03\xf8\xc4\x67\x1c\xa6\xd8\xda\xfe\x2a\x22\x67\xaa\x6e\x98\x16\x4e\x36\x18\x00\xf0\xe3\x0e\x9c\xf6\xb6\x2e\x13\x35\x1b\xde\xd6\xe6\xfb\x71\xb7\x59\x91\x1a\xf3\x17\xa1\x5b\xf9\x43\x3d\x2d\xa5\xa9\xfa\x52\x29\xdc\xf3\x44\xff\x21\xd2\xad\x83\xea\x1d\xae\x16\x39\x1e\x37\xfc\x12\x8f\xa4\x6a\xfe\x1c\x68\xaa\x6e\x2b\xff\x59\xfb\x20\xd2\x3d\x7e\x3c\x3f\xbd\x67\x2e\x85\x66\x94\x38\x8f\xaf\x95\x06\xbe\xa8\xfe\xe4\xf2\xfd\xfa\xea\x16\x12\xf0\x7d\xe2\x73\x30\x37\xf0\x1d\x00\x00\xff\xff\x2c\x32\x5a\x97\x1b\x01\x00\x00")
+var _webUiTemplatesRulesHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xad\x54\x4d\x8f\xd3\x30\x10\xbd\xf7\x57\x0c\xb9\x27\x95\x96\x1b\xa4\xb9\xc0\xc2\x22\x2d\x15\xa2\x3d\x71\x41\x6e\x3c\xdd\x58\x4a\xed\xc8\x76\xaa\x82\xc9\x7f\x67\x26\x1f\x4d\x1a\xa2\x22\x24\x2e\x69\xc7\x6f\xfc\xe6\xcd\xf3\xd8\x21\x48\x3c\x2a\x8d\x10\x15\x28\x64\xd4\x34\xe9\xab\x38\x06\xad\x2e\x10\xc7\x59\x08\xa8\x65\xd3\xac\x56\xe1\x9a\x95\x1b\xed\x51\x7b\x4a\x5c\x01\xa4\x52\x9d\x21\x2f\x85\x73\x9b\x16\x10\x94\x62\xe3\x63\x59\x2b\x19\x65\x84\x53\x46\xf1\x00\x4a\x6e\x22\x5b\x97\xe8\xa2\xec\x2b\xff\xa4\xeb\xe2\xa1\x47\xbd\x38\x94\x38\x30\x74\x41\xfb\x8d\x89\x4d\xa2\x76\x28\xfb\xf8\x60\xac\x44\x8b\x03\x6d\x08\x56\xe8\x17\x84\x84\x09\x3f\x5a\x53\x57\xae\x15\xd4\x91\x72\x27\x59\x1f\x71\x6c\xc7\xa0\x85\x21\x37\xa5\xab\x84\xde\x44\xaf\xa3\xec\x83\x2a\xf1\x0d\x11\x26\xfc\xa7\x69\xde\x42\x4b\x07\x5a\x9c\xba\xe5\x2d\xfd\x21\x57\xd6\xbe\x98\xb1\x90\x3b\xea\x08\xc9\xb3\x70\xfe\xf1\x2c\xca\x5a\x78\x65\x74\xf2\xc9\x7d\x43\x6b\x9a\x66\x8b\x67\xb4\xe4\x5f\xe9\x68\x77\x08\x4e\xe9\x1c\xe7\xc9\x4d\x03\xe2\xc5\xf4\x26\x2f\x57\x28\xea\x93\xd0\xea\x27\xbe\xaf\x6d\xbb\x05\x92\x71\xfb\xb0\x96\xec\x90\xed\x72\x73\x0e\x8a\xae\x9d\x33\x32\x71\x25\xf5\x07\x23\x7f\xdc\xf1\x48\xb6\x47\x45\xbb\xe4\x7c\x7d\xe7\x85\x5f\x04\x1e\xad\x35\x76\x09\xe0\xa6\x61\x94\xbd\xb8\xf7\x8a\xc2\x5e\x9d\x66\xf4\xd3\x3e\x66\x47\x3f\x9e\xfa\x62\x0f\x69\x65\x91\x4c\x4c\x9e\xf6\x9f\x9f\x77\x5a\x55\x15\x7a\xa8\x84\x2f\xbe\x58\x9a\xe6\x0b\xfb\xc5\x09\x4b\x82\x26\x21\x2d\xf0\xb0\x0c\x53\x2a\x4a\xb4\x1e\xda\x6f\x1c\x02\x24\x4f\x28\x4a\x1a\xa9\x5f\xc0\x23\xde\x05\x7b\xf3\x8e\x73\x81\xce\xd7\xb1\x5b\xdf\x95\x96\x2a\x17\xde\x58\xf0\x78\xf1\x71\x4d\x42\x6c\x2e\x1c\x46\xb7\x75\xb8\xb9\x9e\x6f\xd2\x57\x67\x01\x4b\xb8\x11\xf9\x37\xd1\x93\xe9\xe4\x83\x99\x11\xde\x69\x4a\xb2\xbd\x76\xae\x3c\x62\x1f\x27\x6c\x7f\x2a\xe2\x92\xdd\x73\x71\x5f\xe5\x7f\xbf\x36\x0b\x15\xfe\xf9\xda\xdc\x19\xb7\x69\x4f\x84\x8d\x17\x67\x90\x79\xe7\x4e\xb1\x9a\xad\x69\x47\xc3\x41\xf7\x84\xca\xb6\xda\xec\x6e\xde\x90\x5e\xeb\x11\xc0\x8f\x1f\x03\xe9\x9a\x9e\xda\x6c\x35\xa0\xbf\x01\x61\x2c\xa0\x1d\xb6\x05\x00\x00")
 
 func webUiTemplatesRulesHtmlBytes() ([]byte, error) {
 	return bindataRead(
@@ -224,12 +225,12 @@ func webUiTemplatesRulesHtml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "web/ui/templates/rules.html", size: 283, mode: os.FileMode(436), modTime: time.Unix(1510002749, 0)}
+	info := bindataFileInfo{name: "web/ui/templates/rules.html", size: 1462, mode: os.FileMode(436), modTime: time.Unix(1792175185, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _webUiTemplatesStatusHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xcc\x55\xc1\x8e\xdb\x20\x14\xbc\xfb\x2b\x5e\x39\xae\xea\x20\xed\xb1\x22\x48\xcd\xa6\x6a\x2b\xf5\x50\xa5\x4d\xf7\x4c\xcc\x4b\x40\x75\x20\x02\x92\x6d\x84\xf8\xf7\x0a\x27\x76\x6c\x29\xbb\xd9\xca\x87\xee\x25\x61\x60\x34\xf3\x98\x07\x38\x46\x89\x6b\x6d\x10\x88\x42\x21\x49\x4a\xec\x5d\x59\x82\xd1\x7f\xa0\x2c\x79\x8c\x68\x64\x4a\x45\x71\x61\x55\xd6\x04\x34\x81\xa4\x54\x00\x30\xa9\x0f\x50\xd5\xc2\xfb\x69\xb3\x20\xb4\x41\x57\xae\xeb\xbd\x96\x84\x17\x00\x00\x4c\xdd\x83\x96\x53\xe2\xf6\x26\xe8\x2d\x12\xbe\x38\x0d\xe0\xab\x59\x5b\xb7\x15\x41\x5b\xc3\xa8\xba\x3f\xb3\x83\x58\xd5\xd8\x2a\x9e\x40\xf3\x5b\x56\xd6\x48\x34\x1e\xe5\x19\xaf\xac\x93\xe8\x3a\xe8\x83\xd3\xbb\x0e\x29\x7b\x40\x77\x2e\x20\x8b\xae\xac\x3c\xb6\x28\x63\x77\x01\x19\x2a\xbe\xdc\xe5\x9a\x18\x0d\x6a\xb8\x22\x79\x8c\x93\x99\x76\x41\x4d\x96\x3f\x1f\x52\x62\x34\xc8\x9e\x10\xed\x2b\x5d\x91\x7d\xb4\xee\xb7\x36\x1b\x98\x6b\x87\x55\xb0\xee\xf8\x8c\xc3\xc3\xe3\xfc\x25\x6d\x46\x7b\x3b\x60\xb4\xd9\x23\x2f\x06\xf1\xae\xf6\xba\x96\xfa\x12\x29\xe1\xb3\x3c\xf3\xa6\x52\x06\x5f\xd9\x1d\x4e\x89\xb3\x4f\x84\xff\x42\xe7\x9b\xa2\xae\x06\x72\x5e\x6d\xff\xff\x35\xf8\x81\xd3\x02\x0f\xfa\x15\x56\x2d\x6d\x94\xd7\xcc\x09\x53\xa9\x1b\x4e\x27\xd2\x38\x9f\xdc\xdc\xa5\x47\x77\xcb\xaa\xe5\x8d\x77\x9b\x8b\xf0\xdc\x05\x19\xb8\x65\xde\x28\xb7\xcf\xf6\x75\x67\xa3\xe3\x8d\xbc\x3a\xa2\x46\x17\xb6\xc2\x88\x0d\x3a\x4f\xf8\xc7\x3e\xfc\xbf\x77\xa6\x79\x43\x3e\x19\xb9\xb3\xda\x84\x61\x1a\xc3\x44\x63\x74\xc2\x6c\x10\x26\x83\xe2\x9b\x17\xfa\xaa\x70\x8c\xf4\x0e\xfa\x5c\x58\x2e\xbe\x79\x10\xf5\x93\x38\x7a\x50\xe2\x80\xf0\xa3\x52\xb8\xc5\xf7\xf0\xc5\xfa\x00\xc2\x48\xf8\x2e\x72\x9f\x30\xc0\x1d\xed\x09\x77\x5d\x39\xf1\x53\xfa\x40\x29\x13\xa0\x1c\xae\xa7\x64\x38\x1d\xe3\x24\x8b\xa5\x44\x78\x37\x64\x54\x64\x90\xb5\x5f\x3e\x33\xed\x87\xe8\x46\x57\x19\x95\xfa\xc0\x8b\x96\xfd\x37\x00\x00\xff\xff\x3e\x79\x94\x2b\xdc\x06\x00\x00")
+var _webUiTemplatesStatusHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xcd\x57\x4d\x6f\xdb\x30\x0c\xbd\xe7\x57\x70\x3e\x6d\x45\x13\x03\x3d\x16\xae\x81\xb6\x19\xd6\x02\x45\x31\xa4\xcd\x0a\xec\x26\x5b\x4c\x2d\xd4\xb1\x0c\x49\x49\x16\x18\xfe\xef\xa3\xfc\x19\xa7\x71\x93\x2e\x87\xec\xd2\x9a\xe2\x03\x29\x92\x4f\x8f\x48\x96\x71\x9c\x89\x04\xc1\x89\x90\x71\x27\xcf\xbd\x2f\xc3\x21\x24\xe2\x0f\x0c\x87\x7e\x96\x61\xc2\xf3\x7c\x30\xc8\x1a\x54\x28\x13\x83\x89\x21\xe0\x00\xc0\xe3\x62\x09\x61\xcc\xb4\xbe\x2a\x1c\x8c\x20\x6a\x38\x8b\x17\x82\x3b\x3e\xf9\x09\x11\x5d\x80\xe0\x57\x8e\x5a\x24\x46\xcc\xd1\xf1\x27\xe5\x07\xdc\x27\x33\xa9\xe6\xcc\x08\x99\x78\x6e\x74\x51\xa1\x0d\x0b\x62\xac\x23\x96\x46\xf1\x77\x48\xd1\x39\x26\x1a\x79\x65\x07\x52\x71\x54\x8d\xa9\x8d\x12\x69\x63\x45\x72\x89\xaa\xba\x80\x0d\x1a\x48\xbe\xae\x2d\x6b\xab\xd6\xb0\x66\xe4\x4f\x53\x7b\x27\xcf\xa5\xcf\x8e\x87\x53\x07\x46\x37\x42\x99\x68\x34\x7d\xbe\xa5\xde\xb8\x74\xd4\x06\x72\x37\x23\xed\x08\xfb\x22\xd5\x9b\x48\x5e\x61\x2c\x14\x86\x46\xaa\x75\x4f\x86\xdb\x97\xf1\x47\xb1\xe9\xbb\xad\x80\x0c\x5b\xa3\x3f\x28\xac\x2c\x5b\x09\x13\xc1\xe8\x89\xa2\xb3\x57\xbc\x43\x16\x9b\xa8\x18\x4d\xdb\x7a\x5d\xfa\xa2\xc2\xe7\xf8\x15\x14\x4a\xec\xc9\x7b\x0f\x3a\x94\x29\x12\x41\xe4\xca\xf1\x5f\xae\x1f\x60\x82\x69\xcc\xd6\x30\x5e\xa8\x8a\x1c\x3b\x5b\x46\xc8\x12\x58\xe3\x3e\x3b\x9c\x4e\xde\x07\xa6\x0d\xdc\xca\x79\xca\xc2\xfe\x9c\x62\x06\x23\x0b\x6c\x71\xa3\x7b\xfd\x1b\x95\xcc\xf3\x47\xa4\xa2\xe9\xb1\xc4\x1a\xf3\x9c\x6e\xb7\x05\x2b\xb8\x03\x5f\x8d\x94\x6f\xf0\xce\xdb\x16\xf0\xad\x7a\x6e\x47\x14\x42\x43\xe5\xf0\x84\x4a\xa0\xee\x69\x9c\x45\x94\x80\xcf\x53\xae\x64\x9c\xed\xc3\xf7\x25\x69\x80\xae\x89\x96\xfa\x13\x0c\xe9\x00\x56\x4c\x25\x44\x78\x0d\x2c\xe1\x80\x4a\x49\xa5\xcf\x21\x16\x6f\x44\x2b\xa9\xd4\x22\xb5\x75\xd2\x89\xc2\x94\x09\x55\xa2\x66\x4c\xc4\x0b\x85\xfa\xd2\x73\xd3\xd3\x8a\xc0\xf3\x4e\x09\x88\xfc\xa2\xd6\x5d\x8e\x31\x92\xe0\xc5\x5b\x8d\xee\x8e\x2b\xcb\x14\x4b\xe8\xb1\x75\x1b\xb6\xf3\x02\xc5\x74\xec\x1d\x76\x49\x4d\x03\x18\xa3\x0e\xa9\xca\x5d\x8c\xdf\xc0\x14\xf7\xfa\x98\x48\xb5\xb4\xef\x9f\x77\x8b\x6b\xd6\xc1\xa6\xbc\x04\x0b\x11\x73\xd1\xaa\xb9\xe3\xdf\xd8\x93\xff\x4a\xe0\x3b\x6f\xe4\x17\x2a\xdd\x2f\x2c\x95\xb7\xfe\x7f\xd4\x6b\x9c\xe0\x52\x1c\x90\xaa\x86\x1d\x95\xeb\x86\x88\x16\x46\x7b\x32\x95\xa0\xe3\xf2\xd8\xe1\x4e\x35\xaa\x7d\xa9\x6a\xdc\xf1\xd9\xc6\xcc\xe0\x21\xd9\x2c\xee\xa8\x6c\x3f\xe4\x61\xdc\x68\x70\xff\xb8\xb5\xeb\xa7\xc3\x62\x54\x66\xce\x12\x5a\xc7\x4a\x3b\xfe\xf5\xa6\x79\xda\x37\x53\x0a\x5f\xc2\x53\x29\xb6\xb5\xaf\x47\xe0\x3a\x97\xef\xd7\xb9\x2c\x73\xcf\x60\x13\x0b\xd3\xc9\x03\x6d\x82\x78\xc5\xd6\x1a\x22\xb6\x44\x78\x0a\x23\x9c\xe3\x39\xdc\x49\x5a\xca\x76\x45\xfc\x64\x76\x4e\x68\xe0\xcc\xdd\x08\xdc\x4c\xa5\xc4\xe7\xf9\xa5\xeb\x7a\x0c\x22\x85\xb3\x2b\xa7\x7b\x6c\xd7\x1e\x05\xcb\x73\xc7\x6f\x3e\x3d\x97\x59\xc3\xc6\xde\x2b\x94\xc5\x5a\xff\x48\xb9\x1f\x65\xa7\x26\x0d\x5c\x10\xaf\x96\x76\x16\xa3\x63\x45\xb8\xc3\x18\xae\x64\x4a\x23\x1d\x6e\x31\x67\x5c\x1e\x43\x1f\x83\x52\xbf\xef\x7a\x10\x2c\x0c\x54\x51\x21\x58\xd3\x6a\x8e\x59\x80\x71\x4c\x5b\xfc\x1c\x74\x24\x57\x09\x04\x48\x42\x8e\x9b\x9e\xd1\x49\x77\xf5\xe7\xb9\x59\xb5\xe7\x40\x8a\xbe\xa7\x55\x43\x9a\x43\x09\x73\xc8\x50\x3d\x97\x7e\x3c\xf9\x83\x1a\xfd\x17\x57\x84\xe4\x74\x88\x0d\x00\x00")
 
 func webUiTemplatesStatusHtmlBytes() ([]byte, error) {
 	return bindataRead(
@@ -244,12 +245,12 @@ func webUiTemplatesStatusHtml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "web/ui/templates/status.html", size: 1756, mode: os.FileMode(436), modTime: time.Unix(1496168267, 0)}
+	info := bindataFileInfo{name: "web/ui/templates/status.html", size: 3464, mode: os.FileMode(436), modTime: time.Unix(1792191952, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _webUiTemplatesTargetsHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc4\x56\x4b\x8b\xe4\xb6\x13\xbf\xf7\xa7\x28\xfc\x6f\xfe\x24\xb0\x6e\xc3\x42\x2e\x13\xd9\x81\x24\x0b\x1b\x98\x84\xc9\xce\x6e\x0e\xb9\x2c\xb2\x55\xdd\xd6\x8c\x46\x72\xa4\x72\xb3\x8d\x56\xdf\x3d\x48\xb6\xfb\x31\xd3\xee\x49\x26\x59\x72\x31\x96\xaa\xea\x57\x0f\xd5\xcb\x7b\x81\x6b\xa9\x11\xb2\x16\xb9\xc8\x42\x58\x30\x25\xf5\x3d\xd0\xae\xc3\x32\x23\xfc\x44\x45\xe3\x5c\x06\x16\x55\x99\x39\xda\x29\x74\x2d\x22\x65\xd0\x5a\x5c\x97\x99\xf7\xd0\x71\x6a\x6f\x2c\xae\xe5\x27\x08\xa1\x70\xc4\x49\x36\x51\xa6\x20\x6e\x37\x48\x6e\xd5\x38\xf7\xdd\xb6\xf4\x1e\xea\x5e\x2a\xf1\x1b\x5a\x27\x8d\x86\x10\xb2\x6a\xc1\x5c\x63\x65\x47\xe0\x6c\x33\x8f\x75\x77\x80\xba\x9b\x43\x62\xc5\x80\x54\x2d\xbc\x47\x2d\x42\x58\x2c\x0e\x9e\x35\x46\x13\x6a\x8a\xce\x01\x30\x21\xb7\xd0\x28\xee\x5c\x99\x08\x5c\x6a\xb4\xf9\x5a\xf5\x52\x64\xd5\x02\x00\x80\xb5\xaf\x41\x8a\x32\x1b\x95\x66\xd5\xfb\xe1\x87\x15\xed\xeb\x81\x03\x80\x11\xaf\x15\x4e\x38\xc3\x21\x7d\xf3\xc6\x68\x81\xda\xa1\x18\xcf\xb5\xb1\x02\xed\xfe\xd8\x9a\x2d\xda\x6c\x82\x01\xf0\xde\x72\xbd\x41\x58\xde\x99\xfa\x15\x2c\x3b\x63\x14\x5c\x95\xb0\x1a\x74\xde\x18\xa3\x1c\x24\xbb\x0f\x02\xcb\x16\xb9\xa2\x76\x17\xf9\x74\xff\xf0\x76\x3c\x25\xd9\xc7\xac\x64\x88\x27\x40\x85\xfa\x0c\x47\x74\xc4\x4e\x5e\xdc\x99\xfa\x63\x4c\x02\xb4\xde\xcb\x35\x28\x82\xbd\xa6\x01\x27\x04\x10\xd1\x58\x3b\xc6\xf8\xc8\x8d\x09\x4c\x40\x63\x94\xeb\xb8\x2e\xb3\x6f\x9e\x90\x01\x98\x9c\x94\xc9\xc6\xe8\xbc\x69\x71\x6b\x8d\xce\xfb\x2e\xbe\xa0\xac\x18\x4f\x81\xbf\x33\x75\xee\x7d\x8c\x48\x08\x53\xa2\xfd\xef\xe4\xb2\x9a\xfe\xe0\xab\x43\x3c\x42\x28\x26\x8f\x43\x80\xbe\xfb\x9a\x15\xfc\x89\x85\x05\x89\xd3\x3b\x56\x90\xad\x2e\x85\x44\x20\x71\xa9\xdc\x39\x67\xcf\x38\xf8\xd2\xbc\x70\x64\x65\x37\x9b\x25\xc7\x0a\xe2\x13\x9d\xa3\x24\xc3\xcf\x13\x92\x58\xf5\x46\x8b\xce\x48\x4d\xac\xa0\xf6\x12\xdf\x2d\x71\xc2\xe7\x98\xae\x79\x8d\xca\x3d\xcf\xe5\x08\x6e\x1b\xcb\xbb\x67\x01\xdf\x58\x6b\xec\x3c\xd3\xd3\x67\xda\xdf\xcf\x05\x84\x51\x6d\xc4\xee\x1c\x65\x5f\x74\x67\x0a\xe2\x20\x7d\x21\x98\x33\x0f\x90\x88\x7c\xdf\x1a\x57\x1f\xde\x5d\xc3\x67\xd8\x28\x53\x73\xf5\xe1\xdd\xf5\x90\xb8\xf1\x76\x75\xdb\xb4\xf8\x80\x21\x5c\x15\xc5\x78\xf3\xd6\x38\x0a\x61\x3c\xdc\x70\x6a\x43\x88\xf9\xcb\xea\x59\x33\x8e\xfc\x50\xf1\x35\x5e\xc1\x72\xcb\x55\x8f\x2e\x35\x90\x08\xf3\x6b\x8f\x76\x07\x33\x0e\x3e\x82\x90\x93\x78\x94\x1e\x81\x2e\x4a\x02\xb0\x58\xe8\x53\xae\x27\x13\x20\x7d\xf3\xce\xca\x07\x6e\x77\xa9\x4c\xd3\x4d\x08\x31\x1e\x03\x6a\x08\x19\x2b\xa2\xe4\xbc\x5f\xd1\xac\xa1\x8b\xbf\x8c\xfe\xb4\xca\x8f\x68\x17\x1f\xef\xd8\x23\xae\xd0\x12\xa4\x6f\xee\x3d\xac\x86\x46\x0b\x9f\x61\xe8\x37\xef\xcd\x0f\x91\x0f\x42\x80\x38\xa8\xf0\xa3\xd4\x42\x36\x9c\x8c\x85\x38\x36\xf3\xbe\xeb\xd0\x36\xdc\xe1\xd9\x3a\x3e\x38\x32\xe2\x5e\x70\xf6\x72\xb8\xfe\x1d\x67\x9b\xde\x3a\x63\xf3\xd4\x20\xd0\x66\x20\x38\xf1\x9c\xcc\x66\xa3\xe2\x1a\x60\x8c\x22\xd9\x65\x40\x92\xe2\x79\x24\xb7\xf4\xa0\x4a\xb2\x3d\x0e\x47\x63\xe5\x46\x6a\xae\xf2\x91\x8b\xd5\xd5\xf7\xb8\x36\x16\xe3\xf2\x10\xb3\x40\xea\xcd\x15\x2b\xea\x6a\x9f\x73\xf7\x31\xe7\x52\xb6\xfe\x28\x5d\x13\x7b\x1e\x8a\xa1\xb1\xac\x7e\xe6\x5d\x08\x31\xf9\xbd\x5f\xe2\x76\xc8\xc9\x18\x7a\x7a\x50\xb1\x4a\x96\xf7\x21\x94\xff\xff\xa3\x37\xf4\x6d\x62\x08\x61\x3a\x9c\x9f\x4c\xa7\x21\x1f\x92\x32\xd5\x49\xea\xbb\x83\x4e\x58\x1d\x74\x43\x6c\xfd\xd9\x5f\xab\x9c\x93\xe2\x4b\x96\x0e\xf0\xff\x69\xf5\x28\x87\x2f\xd5\x2f\x70\xcd\x7b\x45\x59\xa5\x8d\xc6\x7f\x5e\xaa\x5f\x28\x7b\xd3\x8e\xb2\x8a\x03\x66\x98\x2f\xab\x9f\xdc\xef\x68\x4d\x08\xbf\xe0\x36\x6d\x28\x29\x02\xde\x3b\xa9\x1b\x3c\x66\x0c\x01\xf8\xc6\x7c\xa1\x06\x72\xb0\x2a\xcd\xb3\x4b\x61\x99\x6b\x35\xc3\x8a\xf5\xb8\xa7\xa4\xc9\x71\x84\xfb\xdc\xbb\xbc\xd4\xbf\xb9\x39\x3b\x8f\xc7\x8a\x99\x39\xcb\x8a\xb4\xcc\xfc\xdd\x15\xec\x54\xd3\x11\x08\x2b\x84\xdc\x1e\x16\xfc\x3f\x03\x00\x00\xff\xff\x29\x32\x52\xa9\xba\x0c\x00\x00")
+var _webUiTemplatesTargetsHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8d\x53\xc1\x8e\x9c\x30\x0c\xbd\xef\x57\x58\xb9\x33\x48\x7b\x6c\x81\x1e\xaa\xf6\x54\xa9\x3d\x54\xbd\x07\x62\x86\xec\x9a\x04\x25\x66\x34\x68\x34\xff\x5e\x07\x18\x96\x69\x67\xd5\x22\x81\x6c\xe2\xbc\xf7\x1c\xbf\x5c\x2e\x06\x5b\xeb\x10\x54\x87\xda\xa8\xeb\xf5\xa9\x20\xeb\x5e\x81\xa7\x01\x4b\xc5\x78\xe6\xbc\x89\x51\x41\x40\x2a\x55\xe4\x89\x30\x76\x88\xac\xa0\x0b\xd8\x96\xea\x72\x81\x41\x73\xf7\x43\x12\x7b\x86\xeb\x35\x8f\xac\xd9\x36\x69\x4f\xce\x3a\x1c\x91\xe3\x41\xe2\x4f\xa7\x52\x2a\xeb\xd1\x92\xf9\x85\x21\x5a\xef\xa4\x56\x55\x4f\x45\x6c\x82\x1d\x18\x62\x68\xde\xc7\x7a\x79\x83\x7a\x79\x0f\xa9\xc8\x17\xa4\xea\xe9\x72\x41\x67\xa4\x0d\x09\x6e\x9d\x35\xde\x31\x3a\x4e\xcd\x01\x14\xc6\x9e\xa0\x21\x1d\x63\x39\x2f\x68\x29\x09\x59\x4b\xa3\x35\x22\x08\xe4\x29\xba\x67\xb0\x46\x9a\x5f\x48\x55\xf5\x73\x09\x8a\xbc\x7b\x5e\x2b\x5a\x1f\xfa\x1b\x48\x8a\x33\xeb\x28\x51\xad\x5b\xb2\xd6\x12\x63\x50\xe0\x5d\x1c\xeb\xde\x72\xa9\x02\xf2\x18\x1c\xb4\x9a\x22\x7e\x5c\x89\xee\xc5\xcc\x38\xc7\xe0\xc7\x61\x5b\x96\x02\xeb\x86\x91\x77\xd3\x50\x3b\x69\x59\x44\x1d\x9a\x4e\xdd\x21\xa4\x9e\x82\x27\x05\x03\xe9\x06\x3b\x4f\x06\x43\xa9\xbe\xce\x82\xa0\x9e\x40\x4e\x67\xf0\xd6\x31\xf8\x00\xa4\x6b\x24\x19\xae\x1e\xd9\x37\xbe\x1f\x08\x59\x68\x7c\xdb\xbe\x09\xcc\x45\xe1\x23\xb5\x4d\x87\xcd\x6b\xed\xcf\x7b\xad\x33\x5c\x75\x27\x79\x2b\xdb\xcb\x1e\x9d\x78\x8d\xb8\x9b\x54\x05\xdf\x1d\x4d\xb0\xfd\x28\xf2\x05\xe3\x21\x7b\x3d\x32\xcb\xb8\x17\xe0\x25\xd9\xc3\xc6\x0c\xcf\x83\x76\x66\x3b\x8e\x9a\x1d\xc8\x9b\x89\x0d\xf4\x48\x3c\xc7\xb1\x57\xd5\x97\xb9\x0c\x34\x51\x91\x2f\x30\xff\x4f\xd1\x78\x22\x3d\x44\xfc\x27\xc9\xe7\xb5\xf0\x21\x4d\x14\x01\x77\xb0\x71\xec\x7b\x1d\xa6\x0d\xf5\xcf\xff\xc9\xdf\xb2\x67\x75\x5f\x9e\x06\xbd\xc6\x69\x24\x77\x67\x10\x82\x0f\x1b\x8e\x26\x0c\x0c\xf3\x37\x33\xda\x1d\x93\x27\xe7\x4b\x5c\x2a\x63\xa3\x38\x64\xfa\x00\xce\x3b\x4c\x04\xdb\x51\xff\x85\x49\x5e\x1b\xeb\x8e\xaa\xfa\xb6\x04\x37\x9b\x1f\x0e\x87\xfd\x36\xd6\x35\xe1\x7e\xce\x83\xf7\xc9\x5d\x5b\x4f\x69\x79\xfe\x26\x93\x1a\x74\x11\xcd\x9a\xd7\x3e\x88\x4d\xb7\xb4\xf3\x27\x51\x2a\x9a\xe6\x34\xc1\xaf\x3c\xb7\xcb\xfd\x1b\xd5\x4c\x73\x07\xb6\x04\x00\x00")
 
 func webUiTemplatesTargetsHtmlBytes() ([]byte, error) {
 	return bindataRead(
@@ -264,7 +265,7 @@ func webUiTemplatesTargetsHtml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "web/ui/templates/targets.html", size: 3258, mode: os.FileMode(436), modTime: time.Unix(1510150909, 0)}
+	info := bindataFileInfo{name: "web/ui/templates/targets.html", size: 1206, mode: os.FileMode(436), modTime: time.Unix(1792182204, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -289,7 +290,7 @@ func webUiStaticCssAlertsCss() (*asset, error) {
 	return a, nil
 }
 
-var _webUiStaticCssGraphCss = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x56\xff\x8e\xa3\x36\x10\xfe\x3f\x4f\x31\xdd\x55\xa5\x3b\x29\x20\x48\x37\xb7\x7b\x44\x3d\xa9\xff\xf5\x1d\x4e\x2b\x34\xc0\x40\xac\x18\x1b\xd9\x93\x1f\xdb\xea\xde\xbd\xb2\x1d\x08\x24\x24\xed\x49\xdd\x4d\x22\x99\x19\xcf\x37\x9e\x6f\xe6\x33\x85\xae\x3e\xe0\xef\x05\x40\x8b\xa6\x11\x2a\x83\x64\xb3\xf8\xb1\x58\xc4\x74\x40\x99\x5b\x46\xb6\xde\x5a\x6b\xc5\x91\x15\x7f\x51\x06\x69\xda\x9d\x82\x4f\x63\xb0\xdb\xe6\x47\x83\x5d\x47\x66\x14\x24\x62\xdd\x65\x90\xae\x26\x7e\xde\xde\x69\x2b\x58\x68\x95\x81\x21\x89\x2c\x0e\xb4\x59\x00\x48\xaa\x39\x83\x97\xc4\xf9\x03\xb4\x42\x45\x5b\x12\xcd\xd6\x3f\x4b\xce\x41\x9e\xb1\xaa\xf2\x4b\xa0\x09\x50\xef\x13\x2b\x3c\x44\x8c\x85\xfd\x2f\x2e\xdf\x40\x0a\xf8\x06\x18\xf2\xc2\xaa\x12\xaa\xc9\x60\xdd\x9d\x46\xce\x8c\x45\xd4\xa1\x22\xef\x53\x68\x53\x91\x89\x42\xb2\x69\x77\x02\xab\xa5\xa8\xe0\xb9\xaa\xaa\xcd\xc5\x6c\x42\xe2\x77\xed\x85\x66\xd6\xed\x9c\xc3\x38\x87\x71\xdd\xec\xa1\x19\xe3\x87\xf3\x5c\x76\x23\xe2\x43\xf8\xa9\x7d\x06\xde\x3b\x38\x38\x49\x0d\xa9\xca\x63\x55\xc2\x76\x12\x3f\x32\x10\x4a\x0a\x45\x51\x21\x75\xb9\x73\x61\x0e\x64\x58\x94\x28\x23\x94\xa2\x51\x19\xb0\xee\x36\xe3\xe6\xf1\xff\x5f\x92\x69\x87\xa0\x21\x7c\x40\xbf\xef\xad\x1a\x5b\x21\x3f\x32\xf8\xc3\x08\x94\x4b\xf8\x93\xe4\x81\x1c\xd2\x12\x2c\x2a\x1b\x59\x32\xa2\x1e\x23\x39\xa2\x12\xff\xbb\x1a\xd0\x3e\x72\x3c\x89\x40\xbe\x3e\x90\xa9\xa5\x3e\x66\x70\x10\x56\x14\xd2\x03\x5d\xe0\xb1\xb0\x5a\xee\xd9\x3f\xed\x0b\x1a\xaa\x14\xca\x93\xb8\xc5\x51\x54\xbc\xed\xfb\x72\x14\xbf\x27\x64\x06\xe3\xc2\x5a\x5c\x11\xa3\x90\x10\x0b\xa6\x36\xc6\xd2\x1d\xd6\xef\xf2\xf5\xec\xfb\x3b\x8d\x5f\xa8\x9d\x90\x9f\xc4\x6b\xf7\xc4\xf3\x81\x05\xc9\x3b\xe3\x77\x1d\x67\x3a\x93\x53\xf4\x7e\x95\xdb\x23\x72\x19\xe6\xa7\x96\x1a\x39\x03\xdf\x2e\x9b\x47\x84\x9f\x8b\x90\x9e\x87\x73\x00\xec\x87\xf5\x4c\xc7\xca\x11\xe1\x29\x79\xf3\x86\x1f\x8b\x85\x50\xdd\x9e\xbf\xb3\x60\x49\xef\xd9\xd6\x15\x2b\xc3\x9a\xcf\x42\x51\x6a\xc5\xa4\x38\x03\x64\x36\x9f\xbc\xd3\xe7\x70\x80\x52\xab\x5a\x34\xe0\x77\x2f\xa1\x5f\x5a\x92\x54\xb2\xdf\x3a\xa4\xb0\x1a\xa7\x10\x8d\xa8\x1b\x85\xf1\x35\x9c\x6b\xe9\xc1\xcb\x6a\x49\x39\x63\x21\x69\x22\x83\xbe\xbf\x02\xc0\xa8\xf8\x49\xfc\x76\x66\x27\x24\xf4\x5d\x61\x4b\xbf\x3f\x09\x65\xc9\x70\xde\x12\x1b\x51\x3e\xbd\x8f\xe5\x67\x48\xab\x27\xa8\x42\xa6\x4e\x94\xbb\x73\x21\xc6\xcc\x26\x1d\x7b\x9f\x50\xb9\x10\x9a\x54\x95\xfb\xf5\xd3\xfb\x12\xc6\x06\x83\xaa\xa1\xde\x34\x46\x0c\x02\x15\xa5\x93\xea\x9c\x75\x21\x1a\xfa\x84\x8c\xd1\xe6\x46\xfb\xae\x28\x0d\xae\x05\xab\x25\xc4\xb5\x36\x6d\xe4\x68\x33\x5a\x2e\xe1\x8e\x8e\xf6\x2a\x84\x95\xd8\xdb\x81\x8b\xce\xe8\x96\x78\x4b\x7b\x1b\xf2\xcd\x1b\xa3\xf7\xdd\x63\xa1\xe9\xb3\x70\x8d\xd6\x67\x76\x19\x38\xdc\xb3\x7e\x14\x3b\x1e\x55\xe7\xb6\x36\xeb\xaf\xfd\xd1\xee\x64\xe6\x8e\x7c\xcd\xce\x85\xfa\xbb\xbb\x2e\x70\xc3\xd4\x5c\x8d\xcd\xea\x6b\x58\x0f\x35\xff\xe2\xee\x9b\xd5\x4d\x9f\xa5\x2f\x73\x43\x1e\xbf\xac\xde\xd6\xaf\xe9\xcb\x6f\x1b\x3f\x41\x52\x9b\x0c\x9e\xd7\xeb\xb5\x57\x2e\x2c\x77\x2e\x0d\x55\x45\xbd\xa5\xae\xeb\x2b\x8b\x68\xb1\xa1\x0c\x94\x56\x74\xb9\x13\x26\x97\x41\x59\x96\xce\x12\x1d\xa9\xd8\x09\x8e\x0a\x7d\x8a\xec\x16\x2b\x57\x73\xd7\xe4\x0c\x89\xf7\x76\x5f\xd3\x14\xf8\x29\x59\x42\xf8\xc4\xc9\xeb\xfa\x73\x08\xfa\xd3\x5b\x7a\x34\x36\xa8\x7a\x85\x3e\x77\x92\x3f\x0b\x10\x5a\x8a\x84\x8a\xf4\x9e\x21\x4e\xd7\x76\x39\x93\xe0\x8d\x93\x8f\xac\x7f\x26\xe8\xbf\x04\xfb\xbf\x22\x3d\x6a\x21\xa7\x0e\xf9\x4d\x1f\xad\x86\xd7\xa0\x98\x4e\x9d\x21\x6b\x85\x56\xb7\x6e\x69\x92\xfc\x0a\xbf\x88\xb6\xd3\x86\x51\xf1\x8c\x38\xa6\x73\x71\x46\xda\xda\xe3\xf9\xa9\x9b\x8d\x14\x26\xe8\x75\x7a\xc1\x3b\x59\x40\xa1\xc8\x40\x6c\x44\xb9\xb3\x5b\x3c\xe6\xa3\xb7\x89\x99\xde\x5c\xf9\xbf\xcd\x1d\xc9\xf8\x27\x00\x00\xff\xff\x8e\x28\x6b\x00\x95\x0a\x00\x00")
+var _webUiStaticCssGraphCss = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xad\x56\xdb\x6e\xdb\x38\x10\x7d\xcf\x57\x70\x13\x2c\xd0\x02\x96\x20\x7b\x6d\x27\xb5\xd1\x02\xfb\xb6\xff\x50\x04\x02\x2d\x8e\x64\xc2\x14\x29\x90\xf4\x6d\x8b\xfd\xf7\x1d\x92\xba\x5a\xb2\xd3\x02\x8d\x62\x01\xd4\x0c\xe7\x76\x66\x0e\xb9\x53\xec\x4a\x7e\x3c\x11\x52\x52\x5d\x70\xb9\x21\xc9\xf6\xe9\xbf\xa7\xa7\x18\x4e\x54\xa4\xc6\x52\x6b\xbc\x34\x57\xd2\x46\x86\xff\x0b\x1b\x32\x9f\x57\x97\xa0\x53\x68\x5a\xed\xd3\x33\xbe\x2b\xd0\x3d\x23\x91\x55\x15\xea\x2d\x06\x7a\x5e\x5e\x29\xc3\x2d\x57\xe8\x46\x83\xa0\x96\x9f\x60\x8b\x5f\x05\xe4\x76\x43\x96\x89\xd3\x47\x1b\x68\x60\x0f\xbc\xd8\xfb\x6f\x49\x6d\xe4\x85\x32\x96\x76\x86\x06\x8e\x1a\x9d\x58\xd2\x53\x64\xe9\xce\xfc\x8c\xca\x37\x22\x38\xbe\x68\x88\x0b\xad\x73\x59\x6c\xc8\xaa\xba\xf4\x94\x51\x31\xaa\xa8\x04\xaf\xb3\x53\x9a\x81\x8e\x42\xb0\x58\x03\x62\x94\xe0\x8c\xbc\x30\xc6\xb6\x9d\x58\x87\xc0\xef\xca\x77\xca\x5a\x55\x4e\x29\xf4\x63\xe8\xd7\xcd\x9c\x8a\xbe\xff\x90\x4f\xb7\x9b\x52\xfa\xd0\xfd\x50\x3e\xe1\xde\x2b\x38\x77\x02\x0a\x90\xcc\xfb\x62\xdc\x54\x82\x5e\x37\x84\x4b\xc1\x25\x44\x3b\xa1\xb2\x83\x33\x73\x02\x6d\x79\x46\x45\x44\x05\x2f\x10\x46\x8c\x66\xdb\x6f\x1e\xff\xac\x93\x61\x87\x50\x0d\xf4\x01\xfc\xbe\xb7\x72\x5a\x72\x81\x0e\xff\xd6\x9c\x8a\x19\xf9\x07\xc4\x09\x9c\xa7\x19\x31\x54\x9a\xc8\x80\xe6\x79\xdf\x93\x03\x2a\xf1\xef\x45\xeb\xed\x9a\xd2\x0b\x0f\xe0\x2b\x0c\x34\x17\xea\xbc\x21\x27\x6e\xf8\x4e\x78\x47\x9d\x7b\x6c\x00\x25\x8e\xd6\x7f\x6d\x0a\x1a\xaa\x14\xca\x93\xb8\xc5\x99\x33\xbb\x6f\xfa\xb2\x67\xbf\x01\x64\xc2\x47\x87\x5a\xcc\xc0\x52\x2e\x48\xcc\x2d\x94\x31\xcd\x5c\xb2\x7e\x97\xaf\x67\xd3\xdf\xf3\x78\x09\xe5\x00\xfc\x24\x5e\xb9\x2f\x1e\x0f\xba\x03\x71\x67\xfc\x6e\xed\x0c\x67\x72\xe8\xbd\x59\xa5\xe6\x4c\x6d\x16\xe6\x07\xe3\xa6\xb8\xcf\xb7\xcb\xf6\x11\xe0\x75\x11\xe6\xf5\x70\xb6\x0e\x9b\x61\xad\xe1\x58\x38\x20\x3c\x24\x6f\x5e\x80\xa1\x70\x59\x1d\xed\x77\xcb\xad\x80\xf7\xcd\xde\x15\x6b\x43\x73\x5b\x13\x45\x86\x09\x81\x44\x43\xd4\x5a\xfd\xc9\x2b\x7d\x0e\x09\xa0\x24\xe7\x05\xf1\xbb\x67\xa4\x59\x1a\x10\x90\x59\xbf\xb5\x0d\x61\xd1\x0f\x21\xea\x41\xd7\x33\xe3\x6b\x38\xd5\xd2\xad\x16\x36\x02\xa4\x38\xe8\x02\x06\x34\xe8\xfb\x2b\x38\xe8\x15\x3f\x89\xdf\x6a\x74\x42\x40\xdf\x25\x2d\xe1\xeb\x33\x97\xd8\x9f\x36\x2d\xc1\x6a\x9e\x3d\xbf\xf7\xe9\xa7\x0d\xab\x01\x88\x51\x0b\x15\xcf\x0e\x75\x21\xfa\xc8\x26\x95\xf5\x3a\xa1\x72\xc1\x34\x4e\x64\xea\xd7\xcf\xef\x33\xd2\x17\x68\x2a\x0b\x68\x44\x7d\x8f\x81\xa0\xa2\xf9\xa0\x3a\x35\x2f\x44\x6d\x9f\x80\xd6\x4a\x8f\xb8\xef\x06\xd2\xa0\xba\xb3\x12\x81\xc8\x95\x2e\x23\x07\x9b\x56\x38\x9f\x77\x78\xb4\x61\x21\xca\xf8\xd1\xb4\x58\x54\x5a\x61\x69\xf6\x70\x34\x21\x5e\xe4\x71\x75\xac\x1e\x13\x4d\x13\x85\x6b\xb4\x26\xb2\x6e\xe0\xe8\xd1\xaa\x47\xb6\xe3\x5e\x75\xc6\xb5\x59\x7d\x69\x52\xbb\x13\x99\x4b\xf9\x16\x9d\x0e\xfa\xbb\xbb\x3a\x77\xed\xd4\xdc\x8c\xcd\xe2\x4b\x58\xb7\x35\x5f\xbb\xf3\x66\x31\xea\xb3\xf9\x72\x6a\xc8\xe3\xe5\xe2\x6d\xf5\x3a\x5f\xfe\xb5\xf5\x13\x24\x94\xde\x90\x97\xd5\x6a\xe5\x99\x8b\x66\x07\x17\x86\x64\x51\x23\xc9\xf3\xfc\x46\xc2\x4b\x5a\xa0\x75\xa9\x24\x74\x67\xc2\xe0\x30\xc8\xb2\xcc\x49\xa2\x33\xec\x0e\xdc\x62\xf7\x5e\x22\xb3\xa7\xcc\xd5\xdc\x35\xb9\xc5\x01\x77\xda\xee\xa7\x8b\x1d\xfd\x94\xcc\x48\xf8\x8f\x93\xd7\xd5\xe7\x60\xf4\x97\xb7\x34\xde\x2c\xa2\xd6\x30\x74\xdd\x49\x3e\x17\x02\xd4\x40\x84\xf0\x29\x2c\x6f\x3c\x5f\x99\xd9\x44\x80\x23\x25\x6f\x59\xfd\x8a\xd1\x0f\x8c\xfd\x2e\x4b\x8f\x5a\xc8\xb1\x43\x3a\xea\xa3\x45\x7b\x0d\x8a\xe1\x52\x69\x30\x06\x83\x48\xa7\xda\xed\x4f\xf2\x07\x2f\x2b\xa5\x2d\x95\x76\x82\x1c\xe7\x53\x76\x7a\xdc\xda\xf8\xf3\x53\x37\x69\x29\x4c\xd0\xeb\xf0\x80\x77\xb4\x40\xb1\x55\x35\x89\x91\x00\x0f\x98\xf9\x39\xed\xdd\x26\x26\x7a\x73\xe1\xff\xb6\x77\x29\x63\xcf\x8d\x55\xfa\x8a\x94\x2a\x8f\xf5\x00\x5f\xa2\x3a\xba\x75\xd2\x1e\x3e\x97\xdb\x9b\x62\x47\x12\xd1\xb5\x4f\x13\x03\x7b\xed\xca\x9f\x8e\x3f\x6e\xaf\x20\xa5\x92\xca\x54\x34\x83\x21\xe7\xec\x39\x63\x20\x7d\x23\xc0\xc5\x46\x9d\x00\x84\xe0\x95\xe1\x01\xd9\x17\xbc\x0a\x97\x78\x39\x92\x87\xf4\xa8\x45\xbf\xa8\xcb\x64\x70\x66\xde\x5e\x4c\xf3\xa3\xcc\x5c\x6f\xa5\x4c\x65\x77\x32\x1e\xa9\xc5\x95\xaa\x5c\x1c\x91\x3f\x40\x1f\x66\xe2\xaf\xb3\x7b\x28\x21\x62\x54\x1f\xc8\xe4\xd5\xb6\x01\x67\xb9\x5c\x8e\x37\x7c\x4c\x7a\x53\x30\x33\xf7\x6c\xc7\x2e\x6a\xd6\x6a\x96\x6c\xe9\x9e\xb1\xd3\xc9\x1b\x70\xb3\x69\xbd\x5e\x8f\x77\xb4\xfd\x57\x5f\x84\x2e\x29\xde\x22\x5d\xbe\xae\x42\xb3\xc7\xba\x57\xaf\x6b\x3c\xbe\x37\xaa\xf5\xd5\xcf\x49\x42\x99\xb9\x10\xbd\xb8\x3f\xce\x64\xec\xab\xd0\x48\xb8\xb1\xf3\x38\xfb\xa9\x14\xee\x01\x45\x88\xc1\x33\xf9\x00\x1d\x70\xff\x03\xad\x21\x05\x9b\xd4\x0d\x00\x00")
 
 func webUiStaticCssGraphCssBytes() ([]byte, error) {
 	return bindataRead(
@@ -304,7 +305,7 @@ func webUiStaticCssGraphCss() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "web/ui/static/css/graph.css", size: 2709, mode: os.FileMode(436), modTime: time.Unix(1510002749, 0)}
+	info := bindataFileInfo{name: "web/ui/static/css/graph.css", size: 3540, mode: os.FileMode(436), modTime: time.Unix(1792182618, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -329,7 +330,7 @@ func webUiStaticCssProm_consoleCss() (*asset, error) {
 	return a, nil
 }
 
-var _webUiStaticCssPrometheusCss = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4c\x8f\x4d\x6a\xc3\x40\x0c\x46\xf7\x3e\xc5\xb7\x0e\x4c\x13\x4a\xbb\x99\x9e\xa1\x67\x08\xb2\x47\x8e\x05\x63\x69\xb0\xe5\x9f\x50\x7a\xf7\x10\x4f\x16\xd9\x68\xf1\x78\xdf\x03\x9d\x4f\xf8\xb5\x95\x91\x6c\x53\x74\xa6\xce\xea\x68\xb9\xa3\x65\x66\x6c\x8c\x81\x56\x06\xa1\x97\x9d\x13\x94\xd6\x96\x26\xf8\x40\x0e\x99\xf1\x7d\x29\x3b\x9c\x72\xc6\xe9\xdc\xb4\x96\xee\xf8\x6b\x80\x42\x29\x89\xde\x82\x5b\x89\x87\xf2\xf3\x06\x5b\x73\xb7\x31\xe2\xf3\xe0\xff\x4d\xf3\x31\x3b\x39\x5f\x45\x93\x74\xe4\x36\xbd\x27\x22\x2e\xf8\x2a\x7b\xbd\xd5\xce\xe2\x3c\x51\xbe\xda\xe2\x65\x71\x78\x3a\xfc\xde\xd4\x43\x4f\xa3\xe4\x7b\xc4\x68\x6a\x73\xa1\x8e\xeb\xa2\x5b\xa6\xd9\xa6\x50\x4c\xd4\xb9\xe6\x2b\x8a\x78\xb1\xea\xb9\x59\x76\x29\x41\x54\x5f\xda\x48\x7b\xd8\x24\xf9\x10\xa1\xa6\xfc\x7c\xc3\x79\xf7\x40\x59\x6e\x1a\x91\xb9\xf7\xe7\xf4\x11\x00\x00\xff\xff\xa0\xeb\x4b\x6c\x42\x01\x00\x00")
+var _webUiStaticCssPrometheusCss = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xad\x55\xdb\x6e\xdb\x30\x0c\x7d\xcf\x57\x10\xd8\xcb\x56\x44\x6e\x9b\xc6\x05\xea\x02\xfd\x83\xbd\xed\xbd\x90\x2d\x3a\xd1\x2a\x8b\x82\x4c\x37\x09\x86\xfd\xfb\x24\x5f\x92\x26\x8e\xb3\xb6\x1b\x04\xd8\x30\x79\x7c\x48\x1e\x51\xd4\xf5\x15\x7c\xa7\x57\x04\x45\x1b\x0b\x05\x59\x46\xcb\x90\x63\x21\x9b\x1a\x61\x83\xb0\x96\xc1\x29\xa1\xd4\x5b\x54\x60\xe5\x6b\x2e\x3d\xf0\x5a\x32\xe8\x1a\xd2\x1b\xb7\x05\x96\xc6\xc0\xd5\xf5\x2c\x27\xb5\x83\x5f\x33\x00\x27\x95\xd2\x76\x25\x98\x5c\xd6\x42\x1e\xdf\x18\x73\x62\xa6\x2a\x83\x45\x6b\xff\x3d\x9b\x25\x35\x4b\xc6\x67\x6d\x95\x2e\x24\x93\x7f\x4b\x91\xc1\x0d\x2c\x43\x88\xf6\xd9\xa1\x8d\x66\xf4\xd2\x3c\x53\xc3\xae\x61\x60\xd5\xe2\xcb\x90\xb7\x28\x65\xa5\xcd\x2e\x83\x8a\x2c\xd5\x4e\x16\xd8\xfd\x51\x34\xbe\x26\x2f\x1c\xe9\x50\x5b\x47\xdf\x99\x32\xe8\x6d\x1d\x8e\x89\x0c\x6b\x27\xb4\xb5\x3d\xac\x92\x5b\xb1\xd1\x8a\xd7\x19\x58\xb2\x18\xcb\x60\xdc\xb2\x90\x46\xaf\x6c\x06\x06\x4b\xee\x4b\x40\xe6\x90\x6e\xfd\x5c\xa1\x6d\x8e\x0b\xb8\x8d\x12\xdd\xa6\x9d\x08\x95\xb6\x03\xe1\xe2\x7e\x10\xe0\xfa\x0a\x7e\xac\x83\xfe\xd2\xbf\x04\x61\xb1\xc2\x39\xa0\x95\xb9\x09\x72\xe7\x3b\x18\xa8\x93\x9f\x35\x90\x8d\x80\xb0\x53\x45\x53\xc5\x5d\xf2\x44\x9c\x44\xe9\x93\xf6\x3f\xd1\x52\xec\xb7\x21\x97\xc5\xcb\xca\x53\x63\x95\x28\xc8\xc4\x72\xbf\xdc\x62\x5c\x31\x93\xc1\xa2\x96\x71\xf5\x02\x1c\x48\x64\x27\x53\x0f\xba\x2f\xf2\x05\xe6\x23\x50\xc2\x31\x4b\x78\x8a\x49\x49\x15\xdf\xbe\xfd\x98\x4f\xa0\xda\xcc\x06\x94\x3a\x87\x0a\xdd\xe1\x15\x7a\xbc\xec\x7c\x5f\xc4\x23\xf8\x71\xe8\x4e\x9e\xd6\xbd\x97\x66\xb9\x5c\x4e\x14\x28\x6a\xf6\xda\x9d\xf0\x64\x96\xd7\x82\x4a\xc1\x3b\x87\x5f\x49\xa9\x6f\x53\x92\x2f\xee\xe3\x9a\xa2\x5e\x87\x93\xe7\x8f\x89\x3b\xd3\x04\xdb\xdd\x4d\x5c\x17\x76\x62\xcf\x93\x28\x69\x57\x78\x4e\xea\x83\x6f\x22\xc8\x52\x2e\xd2\x45\x3a\x0e\x52\x92\xaf\x44\x9c\x10\x9e\xcc\x89\xe2\xda\x86\xc3\x28\x22\x8f\x13\xa1\xf3\x43\xa3\x4e\xc9\xa1\xe2\x7a\x1c\xeb\x9f\xa6\xe9\x3b\xfa\x32\xc9\xd9\x0a\x85\xa5\x6c\x0c\x4f\x6a\x74\x77\xf7\x5f\xf8\xbb\x9d\x98\x4f\xfa\x13\x59\xb0\x0e\xb3\x71\x4a\xc5\xd8\x50\x87\x80\x65\x59\x8e\xa3\x29\x4f\x2e\xce\x5d\xb1\x9f\x1b\x1f\x96\xec\x32\xe5\x13\x18\x1d\x1e\xf2\xa4\x8a\xf1\xbc\xfa\x9b\x2e\xe7\x69\xcf\x4a\x74\x0a\x1d\x74\x7a\xea\xa7\xca\x27\xb5\x0a\x57\x8f\x08\x6d\x5e\xcf\xcf\x9b\xdb\x9c\x46\xa1\xde\x71\xc6\xa7\x08\x2e\xc5\x39\xd4\xfe\xcf\x93\xd6\x79\x3c\x8e\x54\x90\xc2\x0f\xb7\xc2\x89\x80\x53\xbb\xe8\xc8\x5d\x4a\xfa\x63\x9d\xd6\x93\x09\xd6\x6c\xf0\x13\x87\x71\x82\x2f\xdc\xc3\xae\xed\x1a\xef\x69\x93\xc9\x72\xb8\xb3\x7b\x8a\xe0\x1d\xe5\x3b\x9a\x86\xf1\x92\xae\x1a\x46\x75\xd4\xdb\x0f\x0f\x0f\x11\xfb\x07\x83\x14\xeb\x25\xf1\x08\x00\x00")
 
 func webUiStaticCssPrometheusCssBytes() ([]byte, error) {
 	return bindataRead(
@@ -344,12 +345,12 @@ func webUiStaticCssPrometheusCss() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "web/ui/static/css/prometheus.css", size: 322, mode: os.FileMode(436), modTime: time.Unix(1502568872, 0)}
+	info := bindataFileInfo{name: "web/ui/static/css/prometheus.css", size: 2289, mode: os.FileMode(436), modTime: time.Unix(1792182534, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _webUiStaticCssTargetsCss = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\x8e\x51\xaa\xc2\x30\x10\x45\xff\xb3\x8a\xfb\x16\xd0\x47\xf5\x33\x05\xb7\x22\xa9\x33\xb6\x23\x6d\x26\x4c\x46\x14\x4b\xf7\x2e\x14\x91\xfa\x7b\x38\xf7\x72\xdc\xfe\x6f\xda\x9f\x47\x4e\xc4\x86\x25\x00\xc0\x55\xb3\x37\x55\x5e\x1c\x71\x6c\xcb\xb3\xdb\x60\x49\x44\x92\x87\xc6\xb5\x44\x1c\xbe\x78\x73\x1f\x2c\xc3\xe8\x11\xbd\x4e\xf4\x6b\xf7\xea\xae\xf3\x7e\x70\xb9\x5b\x55\x8b\x28\x2a\xd9\xd9\xba\xb0\x86\xf0\x89\x20\xf6\x24\x53\xc5\x09\x4e\xcb\xfe\x26\xa2\xc5\x9f\xcc\x45\xcd\x53\xf6\x2e\xac\xef\x00\x00\x00\xff\xff\x62\x16\x77\x5a\xb6\x00\x00\x00")
+var _webUiStaticCssTargetsCss = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x75\x90\xdd\x0e\x82\x30\x0c\x85\xef\xf7\x14\xf5\x5e\x88\x9a\x78\x33\x13\x5f\xc5\x0c\x56\x60\xca\xd6\xa5\x2b\xf1\x2f\xbe\xbb\x04\x12\x02\xfe\xf4\xf2\xf4\xeb\xe9\x69\x85\xf3\x33\x15\xa7\x06\x8d\x45\x86\xa7\x82\xbe\x2a\x0a\x92\x25\xf7\x40\x0d\xbb\x4d\xbc\x1d\x06\x31\x1a\x6b\x5d\xa8\x33\xa1\xa8\x61\x3b\xc9\x03\x7b\x45\x57\x37\xa2\xa1\xa0\xd6\x2e\xe9\x82\x44\xc8\xcf\x07\xca\x8e\x13\xb1\x86\x48\x2e\x08\xf2\x41\xbd\x94\x92\x31\x84\x45\x31\xae\x4d\x70\x04\xb1\xcf\xb9\x8d\x86\x0d\xac\x9c\x8f\xc4\x62\x82\x0c\x23\xb9\x18\xae\x51\x52\x56\xb9\x56\xa6\xe4\xbe\x17\x5d\xf8\xd8\xfa\x83\xce\x2b\x62\x9f\xd5\x4c\x5d\x5c\x7f\x37\xcb\x06\xcb\x4b\x41\xb7\xa5\x29\x8f\x37\x6e\xf7\x9f\x9e\xa9\xf3\x3d\x72\x5f\xd2\x2d\x56\x13\xfc\xe7\x4f\x2f\xf5\x06\x76\x3f\xa4\x46\x7d\x01\x00\x00")
 
 func webUiStaticCssTargetsCssBytes() ([]byte, error) {
 	return bindataRead(
@@ -364,7 +365,7 @@ func webUiStaticCssTargetsCss() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "web/ui/static/css/targets.css", size: 182, mode: os.FileMode(436), modTime: time.Unix(1502568872, 0)}
+	info := bindataFileInfo{name: "web/ui/static/css/targets.css", size: 381, mode: os.FileMode(436), modTime: time.Unix(1792182204, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -429,7 +430,7 @@ func webUiStaticJsAlertsJs() (*asset, error) {
 	return a, nil
 }
 
-var _webUiStaticJsGraphJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xe4\x7d\x6b\x77\xdb\x38\xb2\xe0\x77\xff\x8a\x0a\x27\x27\xa2\xda\x12\x65\xa7\xa7\x7b\xa7\x65\xcb\xbd\xe9\x3c\x26\xb9\x37\xaf\x49\x9c\x7e\x5c\xc7\xd7\x07\x22\x21\x91\x31\x45\x72\x00\xc8\xb6\x3a\xd1\xcf\xda\x3f\xb0\xbf\x6c\x0f\x0a\x0f\x02\x24\xf5\x48\xf7\xdd\x39\xbb\xe7\xfa\x83\x6c\xe1\x51\x28\x14\x0a\x55\x85\xaa\x02\x7c\x43\x18\xbc\x65\xe5\x82\x8a\x94\x2e\x39\x4c\xdc\x2f\x5f\xbe\xc0\xe7\xf5\xc9\x81\x6c\x32\x67\xa4\x4a\xcf\xe9\xa2\xca\x89\xa0\x27\x07\x58\xf6\xfe\xe9\xe3\x37\xaf\x9f\xc0\x04\x8e\x8f\x8e\x8e\x4e\x0e\x0e\xea\x9e\xd1\xdf\x65\x73\x98\xc0\x6c\x59\xc4\x22\x2b\x8b\x90\xe6\x74\x41\x0b\x31\x80\xb2\x92\xdf\xf9\x00\x52\x52\x24\x39\x7d\x9c\x92\x62\x4e\xcd\xb7\x77\x74\x51\xde\xd0\x3e\x7c\x3e\x00\x10\x69\xc6\x23\x9a\xc3\x04\x74\xdf\x13\x53\x88\xb8\x3c\x3f\x7f\xf5\x12\x26\x50\x2c\xf3\xdc\x56\x68\xd8\x30\x31\xa3\xd8\x1a\x77\x30\x98\x78\x63\x37\xda\x28\x14\x5c\xd4\x15\x3a\xe0\xa1\x18\xca\x1e\x7d\xd9\x75\x6d\xfb\xb3\x2c\xbe\xe6\x29\xb9\x35\x73\xf7\x50\x4b\x88\x20\x30\x81\x8b\xcb\x93\x03\x53\x94\x15\x99\xc8\x48\x9e\xfd\x4e\xc3\xfe\xc9\xc1\xba\x83\x80\x91\xc8\x16\xf4\x19\x89\x45\xc9\xe4\xa4\x24\x1a\xc1\x2a\x18\xc3\xf7\x47\xf0\x8d\xfa\x78\xf8\x57\xf8\x06\xbe\xfd\xfe\xbb\x81\xac\xba\x6d\x57\xfd\x0f\xac\x48\x1a\x15\x58\x98\xd6\x85\xf8\x7d\x81\xdf\xf1\x4f\x1e\x8c\xe1\xb8\x1b\x23\x2e\x68\xf5\x33\xc9\x97\x54\x22\x74\x21\x1b\x1f\xf3\x60\x00\xc1\xf1\x91\xfa\xb5\x90\x9f\xdf\xe1\xe7\xb1\xfa\xf5\xed\x91\xfa\x96\xca\xcf\x87\xf8\xf9\x3d\x7e\x1e\xab\x2f\xc7\x09\x56\x24\x01\x0e\x7d\x7c\x8b\xdf\xf0\xf3\xaf\xf8\xf9\x37\xfc\x3c\x5e\x61\xf9\x2a\x38\xb8\xec\x42\xab\x58\x2e\xf0\x0f\x89\x55\x17\x2b\x46\x15\x2b\x45\x29\x56\x15\x75\xc8\xde\x5e\x64\xc9\xd5\x9c\xe6\x33\x98\xe0\x12\xc9\xd5\x93\x5f\xa3\x2c\xf1\x36\x46\x73\xd0\xc3\x43\x5c\xd5\xd1\x08\xde\x53\x01\x09\x9d\x91\x65\x2e\x0c\x0f\x46\x06\x88\xf9\x8e\xc0\x34\xd8\x93\x66\x25\x93\x2c\x79\x95\x15\xd5\x52\x98\x56\x5d\x55\x5f\xbe\x20\x45\x65\xf7\x6c\x06\xa1\xd7\x4e\x90\x29\x4c\x26\x13\x58\x16\x09\x9d\x65\x05\x4d\x0c\x03\xb7\x5b\xc1\x31\xb2\xb0\x46\xfe\x09\x23\xb7\x6a\xa3\x43\x5c\x16\x82\x95\x39\x07\x52\x24\xf8\x85\x64\x05\x65\x30\x63\xe5\x02\x9e\xe3\x3e\x98\x12\xc6\x41\x68\x81\x10\x1d\x68\xe2\xd5\x3b\x50\x0d\xd9\xab\x88\x48\xdf\x32\x3a\xcb\xee\x7a\x63\x78\xfb\xe8\xfc\xf9\xd5\xdb\x77\x4f\x9f\xbd\xf8\x75\xa0\xaa\xa7\xcb\x2c\x4f\x7e\xa6\x8c\x67\x65\xd1\x1b\xc3\x4f\x1f\x5e\xbc\x7c\x72\xf5\xf3\xd3\x77\xef\x5f\xbc\x79\x6d\x36\xd7\xa7\x7f\x2c\x29\x5b\x45\xf4\x4e\xd0\x22\x09\xad\xfc\x70\x67\xd3\xb7\x74\x74\x65\xc3\xfd\xf0\xd5\x92\x0b\x12\xa7\x34\x62\xb4\x48\x28\x0b\x3d\x29\x66\x65\x51\xbf\xee\x4e\xf3\x88\x54\x95\x1c\xc7\x87\xd6\x37\x0b\xfc\x77\x2a\x80\xd1\x19\x65\xb4\x88\x29\x07\x51\x02\xc9\x73\x10\x29\x85\xac\x10\x94\x51\x2e\xb2\x62\x6e\x24\x16\x87\xac\xc0\xba\x9a\xa8\x8a\x8e\xa4\x48\x14\xb8\x69\x56\x24\x40\x6f\x68\x21\xb4\x78\x61\xc8\x2f\x56\xe2\xfe\xc2\x24\x3a\xcc\xb0\x02\xcd\xa3\x59\x56\x24\x61\xf0\x17\xac\xbd\xba\x55\xd5\x01\x1c\x1a\x86\xaa\xa7\xf2\x4f\x49\xb5\x67\x25\x5b\xc0\xc4\x83\xa5\x21\xa8\xfa\xab\x59\xc9\x16\x81\x9a\x9d\x1a\xe1\xae\x62\xdd\x1d\x04\xbd\x13\x84\x51\x72\x51\x90\x05\x9d\xc8\x76\x97\x81\x43\xb8\xbb\x8a\x45\xd7\x74\x55\x31\xca\x79\x58\x8b\x7d\xc3\x7b\xa3\x11\x3c\x95\x04\x82\x5b\xc2\x01\x1b\xd1\x04\x6e\x33\x91\x96\x4b\x81\x24\xe2\x69\x36\x13\x70\x4d\x57\x11\xb6\x97\x5c\x4d\xa3\xdb\x34\x8b\x53\x98\x4c\xe0\xf8\x5b\x78\xf0\x00\xee\xd1\x08\x9b\xfd\x3b\x5d\x19\xb8\xcd\xc9\x46\x7c\x39\x5d\x64\x22\x44\xcc\xe4\x0f\x8d\x2a\x86\x04\x7e\xa2\xb6\xa5\xa9\x41\xa6\x47\xbc\x1e\x2d\x45\x39\x64\x94\x4b\x89\x20\x31\x91\x13\x05\x39\x53\x28\x0b\xc0\xed\xa6\x50\x42\xfe\x9e\xcd\x38\x15\x5a\x3c\x44\xea\xdb\x73\x9a\xcd\x53\x01\x43\x55\x16\xe7\x19\x2d\x74\xd9\x89\xed\xa7\xc0\x9f\x6b\x12\xfa\x8a\xb1\x9e\x0a\xc0\x7d\xf9\x3d\x8a\x39\x0f\x7b\x29\x82\xe8\x0d\xa0\x47\x96\xa2\xec\x35\x4b\x69\x1e\xf1\x98\x95\x79\xae\x87\x3f\xd4\xb8\x99\xe9\xa9\x5f\xf7\x95\xa2\x8a\xca\x22\xec\x5d\xd3\xd5\xb2\x52\x13\xea\x0d\x3c\xc9\xd7\x40\x4f\x2b\x37\x58\x2b\x05\xd7\x58\xe4\x18\xb5\xa6\xda\x1f\xae\x1e\x75\x98\x08\x25\xd5\x0b\x57\x86\xd5\xeb\xa3\x98\x09\xb1\x50\x9c\xe4\x88\x35\x97\xa1\xe4\xc6\xbd\xa6\xc9\x4f\xa2\xd8\x04\xc3\x34\xb9\x9a\x8a\xa2\xdd\x71\x8f\x91\x75\x4b\x77\xd4\xac\xe0\x94\x89\x57\x54\xb0\x2c\xde\x04\x81\xd3\x9c\xc6\x1a\x84\x6a\x7f\xb5\xc0\x0e\x2e\x20\x46\x67\x8c\xf2\xf4\x85\xe4\xf9\x1b\x92\xef\x03\x4b\x77\xb9\x74\xb7\x63\x5c\x16\xbc\xcc\xe9\x39\x0a\xeb\xae\x5d\xac\x1b\x04\x0d\x09\x28\x3b\xc0\x86\x2e\x4a\x74\x58\x61\xe4\x0e\x27\xc8\x94\x77\xf7\x22\x17\xd2\x82\x19\x8a\x72\x3e\xcf\xe9\xa4\x27\xc8\xb4\xe7\x4e\x57\x76\x8c\xe8\x3f\x5b\x8a\xa8\x2f\x3f\xc2\x80\xa7\xe5\x6d\xb3\x75\x59\xa8\xf2\x22\x9a\x62\xd3\xc0\xe1\x49\x2b\x36\xe4\xde\x11\x84\xcd\x71\xcf\xdd\x0f\x69\xa4\xbe\x68\x26\xef\x50\x68\xaa\x3e\xaa\x08\xa3\x85\x08\xfb\x51\x56\x24\xf4\x2e\x74\xdb\xbb\x3c\x6b\x2a\xa4\xb4\xb9\x1f\x06\x7f\x91\x82\x54\x43\x20\x42\xb0\x30\x20\x2c\x23\x43\xa3\x0c\x83\x7e\x3f\x4a\x09\x7f\x9c\x13\xce\xc3\x80\xd1\xbc\x24\x49\xd0\x6f\x48\x22\x25\x7f\x50\x65\xd5\xa2\x46\xed\x22\x25\xf2\xdf\x51\xb1\x64\x05\x48\x2b\x92\xc3\xac\x8c\x97\x1c\xa6\x24\xbe\x96\xaa\x04\x85\x6f\x56\x70\x41\x49\x02\xe5\x0c\x14\x2c\xa9\x51\xa2\x2e\x06\x8d\xa6\xb8\x34\xd7\x74\x95\x94\xb7\x85\xb4\x8f\x18\xc2\xee\xa4\x64\xbd\x81\x71\x4c\x8f\x24\x58\x7c\x43\xf2\xd0\xff\xd6\xd7\x6d\x14\xd4\x0d\x92\x74\xdd\xaf\x75\x07\x63\xe5\x06\xe5\xa1\xea\x82\x7e\x94\x66\x89\xa6\x7a\xcd\xac\x8f\x94\x48\xdc\xcc\xab\x52\x28\x35\x39\xdc\xec\x28\x0b\xc1\xeb\xe2\xb4\x5e\x3d\xba\xcb\xf8\xc6\xd6\xab\x2b\x72\x97\x71\xa7\x79\x4e\xe7\xb4\x48\x36\xa0\xa3\x2a\x5d\x61\x53\x65\x45\x41\x37\x4d\x5a\xd7\xba\x6a\xf2\x86\xe4\xef\x05\x11\x1b\x76\x19\xd6\x5f\x71\xd9\xc0\x53\xca\x45\xf2\x84\x08\xda\xdd\xc7\x11\x68\xb4\x48\xda\x82\x54\x77\x96\x27\x10\x2a\xcf\x13\x55\x16\x5f\x53\x16\x2a\xae\xc8\xcb\x98\xe4\x74\x0c\x3d\x5a\xf4\x94\x49\x26\x0d\x02\x22\xc6\xd0\xfb\xed\xb7\xdf\x7e\x1b\xbe\x7a\x35\x7c\xf2\x04\x9e\x3f\x1f\x2f\x16\xba\x5e\x94\x65\x3e\x25\xec\x6d\x4e\x62\xb4\x71\xc6\xd0\x9b\x96\x42\x94\xa6\x9e\x67\x09\xfd\x69\xf5\x3e\x4b\xe8\x18\x04\x5b\x52\x5d\x9a\x96\xb7\xe7\x65\x42\x56\x3f\x2d\x85\x28\x8b\x66\xd5\xe3\x9c\x12\xd6\x2e\x2c\xb9\x07\x44\x62\xff\x1f\x65\x21\xd1\xfd\x70\xfe\x18\xc7\x53\xca\xa9\x65\x02\x5b\x42\xf8\xdc\x5f\x53\x82\x84\x3d\xf9\xe7\x79\xb6\xa0\x6f\x91\x1e\xbd\x3e\x12\x68\x13\x18\x65\x26\x37\xe0\x48\x09\x96\x54\x5a\x21\x06\x0d\x95\xda\x21\x0c\x5c\x55\xda\xd0\x0f\x46\xab\xb6\x41\x2c\x2b\x89\xd7\x3b\xd5\xdc\x00\xb1\xd2\x80\xbf\xb7\xda\xae\x75\x5e\xd5\xdb\xd6\x55\x8a\x6a\x5b\xe3\xe9\xa0\x77\xdc\xd3\xc7\x57\x73\xee\x11\xab\x9c\x22\x38\xa5\x73\x5b\xf0\x64\xa3\x2c\x2e\xad\x3e\xae\x35\xb4\xe2\xc4\x5e\x34\xcf\x57\x55\x2a\x9b\xf4\x1c\xb9\xea\x23\x1a\xb6\xe4\x65\x0d\x85\x24\x89\x96\xad\x53\x51\x0c\x2b\x96\x2d\x08\x5b\x05\xd6\x92\x93\x80\x9d\x36\x76\xb0\x61\x9c\xd2\xf8\xba\xd1\x8e\xe1\x31\xbd\xd5\x74\x59\x60\x63\x9a\x98\xe6\x6b\xa0\x39\xa7\x1b\x51\xf2\xc0\x7c\x1d\x56\xad\xa1\xb6\x63\xe6\x4d\x62\x6d\xce\x3e\xde\xa2\x84\xce\xca\x3b\x38\xc6\x79\x16\x5f\x87\xad\xe5\xea\xa2\xbd\x34\xa2\x6b\x39\xf8\x6f\xef\xdf\xbc\xae\x57\x63\x34\x82\x17\x33\xe7\xb4\x22\x0d\x75\x3d\xca\x00\x8b\x4b\x96\xcd\xb3\x82\xe4\xc0\x29\xcb\x28\x07\x74\x69\xcc\x4b\x01\x8b\xa5\x20\x82\x26\x35\x9c\x90\x4b\xa9\x92\xf4\xf1\xf4\x78\x4b\xa1\xa0\x34\x91\xfa\x8d\x51\x69\xae\x08\xb6\x8c\x05\x64\x42\x9d\x26\x3d\xc8\x12\x23\x84\x1b\xb9\xeb\xa1\x7d\x27\xca\x74\x60\xa4\xe0\x52\x46\x3d\x91\x9b\xb8\x31\x97\x9a\x78\xd0\x66\xfb\x16\x2d\x7e\x84\xde\x51\x0f\xc6\x72\x27\x18\x65\xd8\xa4\xb6\x05\xa4\x76\x21\x9e\xf6\x43\x6b\x15\xb7\x4e\x5a\xe6\xf0\xd1\x5a\x8b\x86\x2d\xe7\xf0\x8b\xb1\x22\x9c\xb1\x8c\x01\xb7\xbd\x55\x87\x9d\xa1\x37\xfc\x8c\xe4\x9c\x36\x2c\x77\xad\x89\xac\xfa\x6d\xa3\xae\x94\xc9\x14\xc5\xb3\xb1\x6d\xe3\x2b\x34\xce\x2f\x83\x7e\x07\x93\x19\x7b\x24\x66\x94\x70\xfa\x4e\x9b\x53\xee\xa0\xdb\x80\x27\x74\x0f\xe0\x09\xed\x00\xbe\x2f\xea\xb4\x48\xf6\x41\xfc\x69\x91\x7c\x25\xda\x3b\x00\x1b\xa4\x1d\xc0\x9d\xc6\x5b\x87\xc4\x6f\x58\x64\xea\x70\x20\xeb\x02\x46\x2b\xa9\x70\x83\x01\x7c\x96\xc7\xd3\x71\x07\x3c\x14\xed\x03\x58\x94\x52\xf3\x06\x53\x3a\x2b\x19\x0d\xd6\x2d\x33\xcf\x58\x7f\x72\x9f\x32\x8a\xdf\xb2\x62\x5e\x73\xb4\x3a\xad\x4a\x11\xa5\xd4\x40\x87\xc5\x61\x8e\x2b\xb2\x91\xb6\x34\x6c\x8f\x4d\xd2\x48\x2b\x3d\xf4\x9d\x6e\x61\x57\x7b\xee\x91\xd2\x50\x6a\xe7\x27\x2c\x9b\x09\xc7\x60\xac\xca\x6a\x99\x13\x41\x5f\xe0\xd4\xc9\x34\xa7\x6a\xfa\x5c\x73\xb5\x95\x7a\x8e\x15\xeb\xa2\xd0\xda\x36\xeb\x6e\x3f\x67\xed\x2f\xf4\x51\xd9\xa4\x18\x1b\x5e\x43\x55\x38\x65\xe5\x2d\xa7\x4c\x76\x86\x09\x14\xf4\x16\xa4\xdd\x10\xf6\xa3\x39\x15\xb2\x30\xec\xc3\x48\x3b\xcf\xf1\xb0\x1e\x91\x4f\xe4\x2e\xac\xbd\x01\x12\xa5\x32\x19\x43\xf0\xf7\xa7\xe7\xc1\xc0\x16\x2f\x59\xee\xf9\xd2\xe0\x10\x82\x11\xa9\xb2\xd1\xcd\xf1\x08\x99\xf7\x47\xfc\x9c\x08\x1c\xc2\xe9\x28\x05\xe9\xf9\xaa\x92\xfc\xf1\x89\x97\x85\x53\x83\xf4\x59\xc6\x31\xe5\x7c\x5c\x4f\x50\x36\x1a\xa0\x3f\x44\xda\xac\x4b\xee\x7a\x2a\x8c\x8a\x91\x6d\xa4\x9c\x15\x4b\x0e\xf7\x26\x13\x08\x34\x98\xa0\xd9\xb8\x5e\x82\xb4\xbc\x7d\x2a\x8f\x03\x61\x80\xbf\x00\xb1\xcd\x8a\x39\x1a\x78\x51\xad\x2e\xeb\x1f\xc5\x2a\x7e\xf9\xda\xfb\xa6\xd6\x80\xdd\x58\x6a\x23\x5e\xa8\x4a\x18\xe5\xcb\x5c\x5c\x1c\x5d\x9e\xb4\x7a\x24\xd9\x4c\xae\xda\x2b\x22\xd2\x88\x4c\x79\xe8\x2e\xd8\xd0\x81\xa7\x78\xcb\x9f\x38\xf6\x3d\x9b\xc0\xb7\x47\xed\x99\xde\x6f\x7a\xe8\x8e\x82\xbe\x3c\x3d\xa1\x67\xb1\x35\x3b\x80\xe0\x34\xc9\x6e\x20\x96\xc2\x7e\xf2\x31\x20\x39\x65\x02\xf0\x73\x78\x4b\x58\x91\x15\xf3\x8f\xc1\xd9\x29\x17\xac\x2c\xe6\x67\xbf\xa8\x92\x7b\xa7\x23\x5d\x00\x4f\xa8\xa0\xb1\xa0\x09\x04\x70\xd8\x01\x5c\x22\x1a\x89\xf2\x59\x76\x47\x93\xf0\x61\xbf\xb3\x4d\x00\x5c\xea\xe6\x84\xe3\x1a\x60\x17\xe5\xe2\x84\x29\x15\xb7\x94\x16\xb0\x2a\x97\x96\xa1\x51\xaf\xa3\xd3\x0e\x29\x14\xb9\x31\x23\x46\x73\x69\x1c\x94\x05\x90\x38\x5e\x32\x79\x6c\x41\x90\xd8\x05\x61\xe3\x36\x5a\xa0\xd3\x2a\x26\x4b\x4e\x61\x59\xd0\xbb\x4a\xcd\x00\x59\x01\xd4\x8a\xf1\xe8\x74\x94\x64\x37\x67\x41\x03\xdf\xfe\x26\x3e\x58\xd7\xfc\x8c\xc7\xcd\x71\x7b\xab\x9a\x9f\x6e\x46\x94\x4a\xb6\x93\x0f\xd5\x18\xeb\x4d\x61\x9a\x5a\x58\x6c\x14\x4f\x7b\xc5\x1a\x1a\x02\xa0\x73\xfb\x6f\xdb\xfc\x39\x99\xd2\x7c\x74\x75\x25\xe5\xf3\xd5\xd5\xe8\x06\xe3\x34\xb6\xe7\xa6\xdd\xff\x75\xfb\xfe\x2b\xf6\xfc\x76\x22\x93\x1b\x92\xe5\x92\x42\xa0\xbc\x67\xfc\x9e\xbf\xf3\x9b\x7b\xbe\x5e\x67\x49\xb9\x85\x25\xab\xdd\xe8\x75\xd3\x59\xc9\x20\xc4\xe3\x0a\x86\x83\x20\x83\x53\xd3\x21\xca\x69\x31\x17\xe9\x09\x64\x87\x87\x1d\xd8\xba\x1a\xf5\xe2\xe8\xd2\x9e\x04\x49\x92\x84\x52\x7e\xbf\xc1\xef\xa1\x06\x76\x91\x5d\x0e\xa0\xfe\xbb\xef\x71\xcc\x81\x07\x78\xb6\xfc\xfd\xf7\xd5\x3b\xe4\x6b\x1b\x1c\x51\x3f\xc8\xf2\x63\x8c\x16\x0e\xbc\xe9\xcb\xb6\xed\xf2\x05\xa9\xc6\xf0\x79\xbd\x71\x20\xd4\x7b\x92\x17\x49\x4a\x49\x12\x7a\x33\x2c\x97\x2c\xa6\x63\x83\xb1\x0b\x35\x13\x74\xc1\xc7\x10\x90\x3c\x0f\xfc\xd1\x44\x9c\x52\x77\x27\xc9\x96\xcd\xdd\xa4\x8e\x0e\xb7\x14\x52\x72\x43\x35\xe6\xb8\x08\xf1\x92\x31\x5a\x08\x35\xc7\x01\xf0\xeb\xac\x6a\xc9\xd1\x26\x79\x94\xfd\x85\x7c\x85\x0e\x75\xfc\xda\x16\xb1\x1b\xba\xb9\x9d\x9a\x7a\xa4\xd5\x65\x41\x2a\xb9\x18\xeb\x9d\x0d\x99\x59\x38\x2c\x8c\x66\x59\x2e\x28\x0b\xeb\x91\x22\x6d\x9f\x85\x23\x18\xcd\x07\xd0\xeb\xf5\x2d\x5f\x0c\x3a\xd4\x60\xc5\xe8\x18\x7a\x46\xa0\xf7\x06\xed\x06\x25\x17\xb2\x85\x11\xf1\xbd\x46\x8b\x75\x4b\x47\x6e\x40\x39\x9a\x95\xec\x29\x89\xd3\xda\x20\x63\x1b\xf5\x72\x83\x32\x17\x2c\x32\xe7\xb2\x4b\x98\x00\x6b\x8e\xd8\xc4\x61\xed\xab\x48\x6d\xdd\x49\x76\x81\xac\xe8\x1c\xc1\xed\xbf\x1e\x1c\x78\x9c\xca\x44\x8b\xeb\xda\xe6\x87\x2c\x8c\x64\xdb\x7a\x7a\x64\x30\x6d\x4f\xd0\x88\x82\xce\x69\x4e\x2f\x23\x1e\x97\x4c\x29\xfc\x8e\x7a\xa2\xeb\x9b\xf3\x37\x13\x44\x6f\xca\x11\xfc\x08\x24\x52\xde\xb4\xc7\xe5\xa2\x22\x8c\x86\xd3\x3e\x8c\x21\x6b\x10\xa9\x41\x34\x87\x4a\x7c\x33\x39\xd2\x6c\x9e\xe6\x52\x61\xba\x34\x81\xce\xad\xa8\x01\xde\x0f\x7b\xd2\xa4\x38\xeb\x99\xc8\x60\x73\x56\xb2\xef\x65\xc4\x05\x93\xa2\xf8\x50\xb2\x1a\x36\xef\xfb\x38\x74\xa1\x3d\x1a\xc1\x79\x9a\x71\x3c\xd4\x63\x00\x34\xc5\x88\x29\x90\x99\x90\x96\x81\x10\x24\x4e\x51\x89\xa6\x14\xac\x1c\x82\x2a\x5f\xce\xb3\x62\x00\x84\x43\x26\x5c\x58\xa5\x48\x29\xbb\xcd\x38\x85\x29\xa3\xe4\x9a\x37\xfa\x99\xd9\x92\x3c\x13\xab\xa8\x43\xd4\x79\xde\x6c\x07\xe9\x6d\x16\xc0\x1f\x57\x4c\x6b\xe3\x74\xdc\x61\x07\xcc\xa9\x78\x63\x43\xe1\xbb\x15\x7f\x23\x74\x5e\x3b\xe5\x54\x21\x86\xd2\x4c\xc2\x05\x40\xe0\x84\xcc\xb4\xb4\x0e\xac\xab\xd2\x14\x70\x41\xab\x66\x09\x7a\x3e\xa4\x39\x75\xb9\xf9\x18\xad\xba\xf4\x23\xea\x49\x0d\x0c\xa3\x0c\x4c\x5c\xdb\x3d\xf8\x48\x5b\xa3\xce\xd1\x89\xe4\x57\x27\xa6\x12\x65\xc5\x23\xc6\xc8\x2a\x94\xe5\x03\x6f\x3a\x7d\x69\x3c\x3b\xb6\x33\x46\x7c\x35\x14\xb4\x5c\xb4\xaa\x86\x33\xf0\x2c\x6c\x4d\x27\x3c\x84\x5e\x3a\x23\x63\x1f\xbb\x4e\x5e\xe0\xc5\x76\x32\xe1\xed\xc6\x09\xd1\x6d\xa1\xc2\x48\xcd\xc8\x92\x3a\xe3\xe2\xd6\xb2\xa9\x45\xbb\x4c\x41\xc2\x38\x7d\x22\x2d\xe0\xac\xf4\xfc\xa9\xb8\x7a\xe7\xf4\x4e\xd4\xec\x80\x45\xef\x9e\xea\x43\xe2\x3b\x3a\x7f\x7a\x57\x85\xc1\x7f\x86\x17\x47\xc3\x1f\x2e\x0f\xfb\xe1\xc5\xea\x36\x49\x17\xfc\xf2\xb0\x7f\x5f\xf1\x22\x9a\x40\xa8\x9b\x25\x5b\x58\x88\x11\x96\x85\x1a\x9c\xf5\x8d\xdf\xd3\x4d\x55\xa8\x17\xcd\x2a\xa4\x8d\xac\xd3\x55\x86\xd8\xf7\x26\xf0\x6d\xc3\x81\xfc\xfd\x91\xf1\x7e\xcb\x51\x91\xcc\x30\x01\x9c\xde\x8b\x42\x18\x00\x17\xc7\x97\x16\xb3\x65\x91\x49\x65\x69\x6a\x1e\x5e\x3a\xe4\x53\xfd\xbf\x69\x67\xd3\x38\xb9\x4e\x17\x12\xc0\xe5\x4e\x0a\x7b\xbe\xa7\xbd\xf7\x19\x12\xe7\xbd\x3e\xed\xe8\x95\xf6\xd6\x2a\x6c\xc4\xb0\x9d\x58\x58\x97\x61\xb9\x25\x45\xaa\xcb\xd8\x94\x34\xf7\x50\x38\xed\x42\x61\x0b\x50\x34\x34\x7d\x87\x75\x03\xd7\x1d\x9d\x4f\x9c\x0d\xb7\xc1\x55\x02\x5b\xbc\x8c\xb5\x25\xee\x5a\xe8\xeb\x7d\x5c\x29\x9e\x3f\xef\x5f\xbf\x60\xbb\x57\x0a\x86\x70\x2c\x57\xf5\x4c\xad\xee\x70\xb8\x71\xd5\xce\xfe\xfb\xac\xda\x9c\x8a\xa7\x36\x00\xb9\x7b\xc9\x50\xe0\x78\x61\xcb\x2f\x5f\xc0\x2b\xf0\xb1\x66\x26\x1e\xbe\xc0\x88\xbd\x91\x35\x6e\xf4\x6a\x9f\xc0\xdd\x7e\x3a\x99\xbd\xff\xba\xc9\xa0\x93\x48\x35\x56\xbe\x79\xdb\xdd\xf1\x49\xf2\xba\x50\xb6\xed\x3b\xd2\x2e\xc1\x6c\xd9\x1d\x88\xf1\x4e\x9c\x10\xd4\xd6\xac\xc4\x7d\xc8\xa2\x11\xda\x53\x92\x3e\x2d\x3a\x22\x89\x1b\xc8\x52\xd0\x5b\x8d\xb2\x5e\x3a\x43\x20\x97\xc8\x7a\x1b\xea\xb6\x78\x8c\xde\x7b\xff\xc2\x08\x1e\x0e\xa0\xa7\x1d\x53\xbd\x4e\x7a\x6b\xc0\x4e\x9d\xcf\xfa\x7b\x0a\xa4\xff\xdb\xf3\xe6\xcb\xa9\x60\x24\x16\xff\x4f\x4d\xde\x69\xbd\x7f\x26\x6c\x9c\x53\xc2\x94\xd9\xdc\x6f\xec\xf6\x96\x3c\xaa\x25\xcd\xfa\xa0\x19\x88\x92\xd6\x77\xd8\x91\x17\x11\xd1\x45\x25\x56\x61\xdf\x09\x4b\x13\x26\xb6\x78\xd0\xff\x2b\xb4\x84\xce\xd0\x2b\xf3\xa5\xb6\xd5\xac\x71\xb3\xd9\x44\x36\x29\x64\xc6\xca\xbe\x0c\xfa\x66\xf6\x5f\xbe\x28\x8f\xf2\x82\xdc\x85\xf8\xc7\x2c\x2f\x4b\xe6\xeb\x8f\x11\x3c\xfc\xee\xa8\x3f\x80\x63\x8b\x40\x9d\xe4\xd1\x92\x34\x36\x4a\xe0\x06\x38\x10\xab\x5f\x53\xe6\x85\x37\x4c\x61\x44\xa6\xf2\x58\xdc\x77\x2d\xb7\x25\xcb\xcd\x58\xda\x5f\x67\xbe\x56\x84\x91\x45\x9d\xb4\x1b\x20\x94\x60\xdc\x34\x93\x4d\x50\x7a\x63\xc6\xb1\xb5\xd3\x15\xc0\x08\xd7\x4e\x9a\xe8\x7a\x6a\x43\x6f\x95\x4e\xdc\xa6\x2a\xe7\x46\x37\x3c\xf1\x81\xd0\x4a\xda\xb8\x76\x7d\x54\xed\x92\xe5\x52\xa5\x6f\x89\x82\xa8\xf8\x62\xa0\x03\x60\x6a\xc6\x2e\xa3\x77\x78\x39\xdd\x0c\x31\xdc\x2e\xef\x28\xaf\xca\x82\xd3\x76\xe3\x13\x45\x0b\x2f\x7f\x40\x63\x2c\x14\xb7\xd6\x9c\xeb\x06\x79\x76\xe3\xfd\x87\x31\x7e\xac\x02\xcc\xbb\x71\xb6\xc1\x34\xb3\xee\xea\x8f\xc6\xa1\xf0\xd7\x54\x1e\x95\x36\xf8\xa4\x1b\x1b\x43\x65\xcb\xa9\xca\xa0\xef\xf9\xaa\x97\x2c\xdf\xe5\x81\x96\xe5\x63\x8d\xc4\xbf\xda\x2b\x8d\xbd\xd0\x59\xb0\xa7\xf7\x59\x43\x0d\xad\xdf\xd9\x27\xf1\x2e\x3f\xc4\x5d\xca\x06\x92\x99\xab\x26\xfa\xb2\x4c\x1e\xbf\x02\xdc\xba\x0d\xa4\x51\x40\x30\xcf\x07\x27\xfb\xdc\xa5\x2c\x62\x7a\xb9\x31\x77\xe2\x5e\x57\xde\xbf\xf9\xa1\x4c\x2e\x68\xb3\x8f\x9a\xbc\xe7\x7c\xf2\x73\x62\x9a\x9d\x15\x89\xe5\x71\xd3\xeb\xb4\xd3\xf1\x4f\xef\x68\xbc\xc4\xf4\x78\xed\xf2\x0e\xe0\x50\x82\x6d\xc5\x59\x1c\xea\xc5\xe5\xa2\xca\xa9\xa0\x7b\x13\x70\xb2\x81\x80\xdb\xa3\x09\x49\x7d\x4c\xef\x8c\xd2\x0e\xeb\xcd\x7c\xe2\x75\x14\xa5\x20\xb9\x2c\x7e\xaf\x72\x62\xf0\xf6\xc9\xb6\x15\x52\xc9\x2c\x5b\x96\x69\x63\x27\xed\xd1\x95\xfb\x07\x85\x6d\xc0\x63\x92\x13\xd6\x8a\xb3\xb6\x51\x3a\xde\xb9\xb8\xed\x3e\xdb\x50\x30\xc7\xda\xce\xd5\x5f\x37\x7c\x74\x56\xb1\xa7\x62\x91\x87\xc1\xcb\x92\xa8\x38\xa0\x5a\x7e\x4b\xf8\x43\x08\x16\x1c\x4e\xa7\x0c\x46\x67\xf0\xce\xca\x7a\xd5\xca\xd1\xcd\x87\x10\x98\x66\xb2\x26\x38\x97\x98\xab\xc0\xa2\x4a\x4b\x52\x3d\x1a\x13\x72\x58\xac\x33\x1d\xa6\x46\x7d\x0f\xdf\x9e\x65\x6c\x57\x34\x2f\xf8\x7c\x87\xb1\x2e\x7b\x44\x52\x52\x60\xdb\x46\xb9\x31\x87\x76\xe5\x22\x58\xeb\xeb\x8f\x8e\xdd\xeb\x35\x87\x36\x34\xd8\x31\xb4\x97\x87\xb8\x87\xbd\xe8\xda\x09\x72\x79\xca\xa5\x78\xf1\xc4\xf0\xea\x6d\x56\x24\xe5\xad\x9a\xce\xb9\xaa\x6c\xb6\xb4\x66\x63\xd6\x48\xa1\xef\x32\xea\x1a\xc9\x94\xb5\x65\x87\xe6\xa9\x81\xe0\xbb\xbf\x6c\x32\xba\x19\x12\x26\x06\x2f\xae\x36\xbe\xc4\xaa\x3b\x91\xa5\xe3\x80\xcd\xbb\x92\x35\xe5\x1c\x06\xf5\x0c\xbe\xd1\x57\x26\x77\x53\x5b\xdd\x57\x7a\x49\xa6\x34\xf7\x2c\x00\x8c\xf0\xf2\x9a\xe4\xf8\xfd\x3d\x7a\xf1\xb9\xbe\x5e\xe8\x38\x3d\xb0\x16\xb2\x02\xdc\x6e\x8a\x28\xaa\x4a\xaa\x1b\x13\x2e\x76\x04\x89\x0b\x35\xaa\x96\x3c\x0d\x03\x13\xac\x92\x9b\x4b\xf5\x3d\x84\xc0\xc6\xa7\xb4\x2c\xe7\x31\xa9\xe8\xf3\xf3\x57\x2f\x35\x9e\x17\xf8\xcb\xc6\x45\xd7\xfe\xd1\x3e\x37\xb3\xf3\xb3\x1f\x54\xf1\xc7\xa0\x1e\xca\x60\xf2\xa9\xcc\x8a\x30\x38\x9d\xb2\xb3\xa0\xaf\x86\xc7\xf4\x80\x9d\xc4\x54\x6e\xfc\xf3\xf2\x9c\xbf\x56\xce\xea\x8d\xe4\x14\xa6\x85\xae\x89\x0c\x71\xa4\x4d\xdf\xeb\xe1\xa8\x9f\x83\x93\x6d\xc4\xdf\x49\xfd\xdd\xe4\xef\xa0\xbf\x25\xf9\xe4\x63\x60\xe9\x62\xe8\x2b\xcb\x3f\x06\x36\x48\x81\x12\x58\x7e\xe8\xd9\x1c\x4e\xba\xc8\x38\x50\x34\x5c\x07\x8e\xb7\x42\x75\xd8\xcf\xb3\xfd\xb3\xf6\x03\x5b\x5a\xa2\x63\xb7\x26\xa5\xda\xb1\xd8\xf4\x59\x5e\x12\xa1\xeb\xcd\xa6\xcc\xf8\x6b\xf2\x5a\x96\xf5\x9d\x1b\x62\xc1\xe1\x8b\x62\x16\x0c\x20\x18\xea\xdf\xf8\x1d\x6e\xb3\x3c\x87\x29\x55\xc0\x12\xb9\x9d\x4a\x78\x4d\x5e\xc3\x74\xe5\xc2\xef\x47\x70\x9e\x52\x03\x2a\x26\x45\x4f\xc8\x4e\x98\x86\x43\x93\x01\xf0\x12\xb3\xca\x41\xa4\x74\x01\x84\xc3\x9c\x54\x1c\xc2\x62\x99\xe7\xfd\xc8\x75\x44\x99\x6b\xbb\x6b\xcf\x67\xbd\x93\x28\x5e\x62\x6a\xd3\x68\xdf\xea\x50\xa8\x48\x4e\x85\x30\xe7\xdb\x77\xfa\x16\x71\xf4\xb8\xcc\x4b\x16\xbd\x55\x95\xf5\x61\x1b\xcd\x4e\xc7\x14\x90\x3c\xb4\x20\x82\x65\x77\x81\x2f\xa2\x6a\xf3\x4b\xa7\x1d\x64\x1c\x8a\x52\x40\x39\x03\xd5\x1e\xa3\x6c\xf7\xe0\x6d\x4e\x09\xa7\x40\xf1\x76\x1e\x81\xb8\x64\x8c\xc6\x02\xef\xa2\x50\xce\xb3\xb2\xb0\x99\x30\x9a\x1a\x8a\xcf\xd7\xb5\x77\x8c\x98\x2c\x0c\x66\xe3\x8b\xb5\xdc\x14\xbc\x19\x2d\xaa\xf3\xe3\x14\x17\xd7\xe1\x22\xc1\xf5\x5e\x45\x03\x07\x97\xc6\x6e\x0a\x1d\x67\x32\x56\xcf\x89\x2b\xaa\xb8\x13\xc5\x6f\xd8\x37\x26\x3c\x55\x8b\x26\xa4\x8e\x2f\x12\xea\x81\xeb\x14\x0e\x0b\xd8\xd6\xd5\x42\xcc\x92\xc2\x1d\x65\x8c\x9f\x03\xaf\xfb\x58\xff\xf6\x0f\x3a\x82\xab\x60\x15\xf7\x29\xe5\x6c\x20\xf5\xd3\x18\x44\xfe\xdc\x8d\x55\x00\xe5\xe2\xe8\xd2\xcd\x1a\x58\x8d\x1d\xdd\x88\x3b\x53\x41\xbb\x38\xbe\xac\x23\xba\x36\xcd\x61\xdd\xaf\xcd\xeb\x5c\x1e\x4e\x34\x07\x46\xf8\x35\x54\x3d\xd6\x27\x36\x78\x86\xa6\x5f\x2b\x91\x80\x3b\x1b\x57\xa5\x3b\xe1\x8a\x71\x14\x80\x24\xcf\x61\x91\x71\x2e\xad\x7d\x79\x80\xe7\xf5\x15\xca\x82\xde\x5a\x2b\x53\x8b\x4c\xb5\x0d\x4a\xc7\x7c\xb6\x42\x54\x38\x6a\xdf\xba\x14\x4e\x40\xc0\xa9\x5f\x4e\x8b\x44\x96\x1e\x36\x5b\xd3\xca\xcb\x70\x7f\x94\xe7\xe5\x2d\x42\x9f\x49\xa1\x21\xd1\xab\xca\xac\x10\x90\x15\x2a\x5d\x2d\xb6\x41\x66\xb4\x5e\x94\xd9\x6b\x03\x91\x12\xc7\x07\x0f\x40\x15\x5f\x54\x25\xbf\x8c\xee\xe0\x54\x8e\xdb\x1a\x56\x1d\xfa\xdd\xe5\xb4\x13\x57\x22\xdd\x01\xe2\x98\xa7\x55\x89\xb7\xc9\xf5\x42\x35\x6d\xf5\x06\x88\xcf\x77\x63\x10\x03\xd0\xd9\x43\xeb\x7e\x3b\xfa\x09\x60\x9f\x1e\xb0\x7d\xeb\x85\xad\x9d\xd4\x64\x4f\xfb\xaf\xf5\xae\xc3\xd6\x30\x00\x37\xd9\xfb\x86\x82\xc6\x49\xe4\x9b\x61\x78\x29\x0e\x5f\x5d\x20\xc5\x0a\x04\x23\x31\xe5\x52\x4c\x91\x02\xe8\x5d\xa6\x6e\x54\xa3\x18\x8f\xfc\x4b\x5a\xb5\xaf\xd0\x19\xae\xbe\xe1\x15\xa7\x59\x9e\x30\x5a\x84\xfd\x8e\x48\x72\xdd\xb6\x91\x95\x8c\x15\x78\x67\xcc\xab\x58\x37\x2f\x9f\xe9\x0c\x0b\x6d\xb6\x04\xea\xd6\xd9\x99\x49\xa3\x38\x69\xde\x3e\x6b\x34\xd7\xd7\xce\xda\xed\x6b\xf4\x5b\xf7\xd0\x77\x35\xc2\xa1\x6a\xc7\x29\x2d\x12\xed\x36\xdd\xe8\x4f\x94\x94\x7f\x5c\x16\x37\x72\xef\x8a\x12\x3e\xbc\x7e\xf1\x2b\x1e\xa5\xb8\x20\x8b\xca\xdc\x43\x77\xce\xc6\xfb\x7b\xaf\xbf\x7c\x81\x6f\xbf\xd7\x23\x1c\xa7\xe6\x49\x84\xa8\xc3\xa7\x6b\xd0\x1c\xda\x81\xec\x34\x77\xcb\x9d\xb7\x24\xc1\x94\x0d\x7d\x23\xe5\x36\x13\x29\x64\xc5\x4d\xc6\xb3\x69\x4e\x21\x90\xbb\x22\x50\x02\x93\x03\x51\xf7\xcc\xe3\xb2\x98\x65\xf3\x25\xa3\x09\xdc\x0d\xe5\x22\xc0\xb4\x5c\x16\x09\x41\x00\xb4\xe0\x4b\x46\xb9\x01\x2f\x52\x22\x14\xe7\x71\x20\x8c\x42\x92\xf1\x2a\x27\x2b\x7d\x73\x1d\x08\xcc\xb2\xbb\x1a\x0e\x52\xc1\xbb\xbe\x59\x90\xaa\xc2\x54\x98\x12\x87\xb6\x89\x25\x16\xbe\x9c\xb8\xe9\x86\x4d\xea\xbb\x2f\xb5\xf8\xb9\x38\x92\x52\xe6\xac\xa6\x9a\x13\x47\x54\x34\x5a\x16\x78\x2d\x1e\xe5\x81\x6d\xd5\x92\x0b\xeb\x26\x5c\x5f\xba\x0d\xe1\x58\x49\x33\xbd\x22\xad\x51\xac\xc8\xd1\x0d\x3a\x07\xa8\xef\xb9\xbe\x2e\x6f\x21\x66\x14\x13\x80\x53\x8a\xb6\x8d\xbf\x89\x5b\xef\xa5\xb8\xd6\x8f\xba\x6a\xa3\x30\xd0\x19\x1e\x63\x87\xf9\xad\xfe\x53\xf7\xe1\xc7\xb5\xc3\xdd\xd9\xd8\x78\xc6\x57\xd7\xe3\xc3\xfe\x00\xc5\xf1\x40\x1f\x3f\x13\x91\x6e\xe9\xf3\x8b\xac\x47\xb7\xcf\xdf\x8e\x06\xf0\xd0\xf6\x53\xa7\x32\xca\xc6\x1d\x37\xab\x7e\xd4\x09\x36\x01\x8c\x21\xc8\xb3\x82\x1a\x37\x28\x9e\xfe\xaa\x32\x27\xda\x9f\x21\xeb\x08\xd3\xbe\x4f\xe3\xb3\xb0\xfc\xae\x8a\x17\x99\x6c\x49\x96\xa2\x0c\x06\x1e\x51\x9f\x65\x45\x82\xa9\xd4\x9c\x6a\xce\xec\x71\x58\x90\xbb\xd1\x22\x2b\x0e\x36\xdc\xf9\x92\x42\x57\xb0\xa5\xfb\x14\xc3\x2f\x29\x2d\xcc\xe5\x2e\x69\x17\xaa\x6b\xdd\x89\xd5\xc5\x0b\x72\x57\xeb\xe2\x2d\x7b\x51\xd4\x1e\x16\xcb\x2d\xb2\x7f\xbc\x64\x4c\x95\xbf\x72\x21\xa9\x2b\x9c\x5a\x83\x75\x43\x94\xa5\x6f\xa5\x46\x6e\x7a\xf7\x6c\x45\xb4\x82\xb3\xc6\x00\x0f\x1e\x80\x5b\x7d\xaf\x69\x3b\xa2\xa9\xd3\x40\xc9\xe9\xd0\xe1\x7f\xb4\xaa\x54\x52\xe2\x70\xe2\xf7\xd6\xdc\xee\x2a\x0c\x8f\x97\x23\x45\xbe\x05\xb9\xfb\xe6\x38\x3a\xfa\x6e\x73\xb3\xac\x30\xb4\xf1\x34\x3d\xae\x00\xd6\xbd\x28\x66\x59\x91\x89\xd5\x49\x63\x65\x86\x7e\xc5\x57\xae\xd0\x7f\xcd\x22\x9c\x22\x8e\xfb\x90\x5e\xcd\x65\x2b\xc1\xbb\xd6\x78\xb1\xe7\xca\x2e\xf6\x5f\xcf\xb5\x73\x2f\x15\xb1\x9a\xe0\x32\x35\x13\x33\xba\x17\x13\x0e\x6b\x4f\xea\xc6\xd5\x94\x9f\x43\xd3\xae\xeb\x72\xe9\x66\xe0\xe1\x51\x74\xfc\x4d\x68\xef\xa2\xc8\xc2\xa1\x84\xd7\xaf\x0f\x25\x3b\x86\xdd\x09\x61\x6d\x9c\x6a\x92\x95\xee\xb4\x69\xd2\x96\xbb\x11\x9a\x3f\xe8\xfb\xfe\xac\xa4\xcc\xb8\x4b\x64\x3b\x37\xc6\x56\x3b\x60\xfd\xa6\x45\xf9\x46\x60\x4a\xee\x95\x2c\xa3\x85\xb0\x92\x92\xce\x4c\xf2\xa2\xc8\xe2\xeb\x67\xfa\x66\x3a\xa6\x5d\xab\x6b\xea\xff\xfe\xea\xa7\xf3\x41\x87\x8e\x40\x74\xb4\x8e\x70\xaf\x95\xf9\xa4\xd3\x8f\x02\xd5\xb3\x48\xcb\x1b\xca\x9e\x50\x41\xb2\xbc\x7b\x2e\xcf\xeb\x06\xfb\x4d\x48\xa1\xe9\xe7\x32\x2b\x99\x3f\x80\xbb\x01\xac\x7c\xb1\xa9\x33\x4d\x7a\xa7\xbc\x22\x85\x31\x15\x65\x61\x80\x89\xbc\x36\x34\x71\x07\xdf\xa0\x01\xd7\x8f\x44\xf9\xe1\xfc\xb1\x72\xec\x84\x7d\x95\xc7\x2b\xfb\x9e\xf5\x4e\x1c\xb0\xfc\x96\x88\x38\x6d\x03\xc6\x79\x5c\xa9\xda\x40\x5d\x7e\x9d\x04\x53\x12\x5f\xcf\x99\x34\x89\x86\xfa\x74\xa8\x72\x88\x51\x5c\x60\x89\x1c\x46\x5a\xae\xed\x81\xe2\xb2\x10\xb4\xc0\x23\x9b\x1a\xf2\x10\xf4\x6c\xa3\x2e\x7f\x1a\x1a\x66\xca\xa9\x36\x06\xd7\xc1\xb8\xd2\x33\xd1\xc9\xef\x66\x08\x27\xa7\x06\x1b\x4c\x19\x92\xc5\x8c\xea\x14\x69\xaf\x70\xed\x43\xf5\xd1\x68\xdb\x2b\xe8\x8d\x30\x2f\x3f\x74\x2c\xfc\x4b\xac\xeb\xb4\x47\x54\x37\x6b\x90\x6c\x65\x08\x67\x34\x27\xa7\xbb\x7b\xc8\x9f\x68\x4a\x6e\xb2\x92\x45\x5a\x54\x3f\x37\x1d\x42\xd8\x8b\xf5\x14\x5e\x63\xfd\xdb\x1f\x9c\xa7\x34\xbf\x91\x96\xe9\x5e\x23\x9f\xa3\x75\xb0\x1f\xc3\x6f\x1a\xd5\x0d\x53\xdb\xa7\x57\x76\x3a\xc1\x79\xf6\xfb\x1f\x39\x72\xfa\x62\xea\x5e\xc3\x97\xd4\x21\x09\xec\xa1\xc0\xc6\xb9\xff\xa8\x89\xb8\xc5\x2a\xa8\xc5\xcd\x1e\x49\x77\x1d\x39\x08\x3b\x32\x01\xba\x69\x22\xcf\xd6\x1a\x0b\x7d\x4f\x9f\x43\x45\xf0\xf5\x2d\xf7\x1a\xff\xac\x64\xd6\x1e\x54\x07\x1e\x74\x98\x3a\x77\xf7\x39\xb9\xa1\x07\xfa\x54\xe4\xdc\xd8\x7f\xf4\x6f\x8f\x7e\x05\x13\x28\x94\xa7\x98\x92\x25\x94\xa9\xcb\xfe\x43\xeb\x13\x85\x4c\x28\xb7\xad\x33\xa6\x02\x76\x2b\x2d\x51\x09\x71\xc9\x29\x93\x07\x2c\x79\x3e\x52\x97\x00\x10\x1f\xf7\xed\x1b\x7b\xd1\x5f\xfb\x1b\xbd\x83\x62\xf7\x03\x01\xe8\x7c\xdd\xe9\x8e\xe8\xf4\x9a\xbe\x2e\x11\x4d\x74\x0f\x71\x98\x49\x89\xd8\xf0\x84\xb6\xfd\x02\xe7\x64\xea\xbf\xef\xe0\x5e\xdc\x77\x22\x44\xf6\x21\x81\xbd\xb8\xa0\x91\xd7\xd1\x48\x12\x24\x7b\xf1\x81\x4a\xde\xaa\x5f\x20\xd8\x8e\xa5\x4b\x69\xe5\x0f\x37\x01\x92\x9f\xca\x64\x65\x48\xed\x80\xf3\x5f\xa3\xba\xc2\x9b\x8f\x20\xa6\x65\xa2\x5f\xca\xc0\x7e\x5e\x6e\x17\xbf\xcd\x44\x9c\x86\x8d\xc8\xb6\xc2\x3f\x26\x9c\x42\x70\x43\x63\x51\xb2\x60\x7c\xe0\x9a\x87\x7e\x08\xda\x5f\x41\x33\x8c\x76\x8a\x04\xa7\x82\x9d\x9d\x8a\x04\xe2\x32\x97\xba\x6a\xd2\x7b\xd8\x3b\x3b\xcd\xce\x0a\xb5\xb0\xa7\xa3\xec\xec\x74\x24\x12\xf9\xc1\xce\xea\x2b\x1e\xcd\xfc\xd8\xee\xac\xef\x8e\x70\xb8\x7f\xa5\x10\xd7\x40\xdb\xa5\xe6\x0a\x72\x76\xe9\x6a\x4b\x1b\x6c\xea\xf2\x48\x5b\x87\xf4\xc9\xb6\xa9\x9d\x35\xc2\x6e\x0a\xa4\x0e\x8e\xc9\xa9\xe9\x26\xda\xe1\x7c\x71\x7c\x59\x57\xb9\xb3\x56\xf3\xc4\x0b\x38\x27\x96\xfe\x3a\xaa\xf0\xff\x31\xfd\x6f\xfe\x38\xfd\x6f\x9a\xf4\xb7\x77\x1f\xce\xe9\x9d\xb4\x70\x02\x1b\x82\xb0\xe8\x7d\x52\xe8\x7d\x82\x53\xb8\x31\x1e\x7e\x83\xdb\x27\xff\xba\x69\x0d\xe9\x70\x62\x1b\x5f\x7c\xba\xd4\x2b\x04\xff\x53\xae\x9a\x5b\x7e\xa4\x56\x6e\xca\x46\x67\x81\xef\xe6\xfd\x93\xac\xe1\x60\xb2\x37\x67\xe8\x18\x8c\xe2\x8c\xee\xd1\x55\x13\x6f\x24\x77\x25\x36\x31\x62\x73\x20\xb4\x6c\xb7\x0f\x84\x4d\xbc\x81\x9c\x59\xfb\x63\xf6\x77\x0c\xaa\xdd\x94\xe3\x4e\x7d\xf0\xa1\xe0\xcb\xaa\x2a\x99\xa0\x89\xbe\xc4\x82\xf1\xb3\x16\x90\x9d\xaa\x9d\x6d\x78\x61\xb8\xeb\x42\x78\xf3\x19\x52\xcf\x27\xed\xd8\x54\xef\xba\x8b\xf7\x36\xb5\xea\xe3\x94\x8b\xd7\xaa\x46\x8c\x4c\xf9\xd5\xca\x7d\x2a\x61\x65\xd5\xaa\xaa\x3a\x9b\xc0\x31\x7d\xf8\xd7\x46\x56\x7f\xb8\x82\x91\x2a\x8f\x44\xe9\x9c\x53\x82\xdf\x02\xc7\xed\xd1\x84\x72\xbc\x01\xca\x71\x13\xca\x7f\x6c\x81\x72\xfc\xb7\x6e\x28\xc7\x7f\x6b\x42\x79\xba\x0d\xca\x77\x1b\xa0\x7c\xd7\x84\xf2\x76\x1b\x94\x87\x1b\xa0\x3c\x6c\x42\x39\xdf\x02\xe5\x87\x6e\x20\x3f\x34\x61\xfc\x7d\x0b\x8c\xef\xbb\x61\x7c\xdf\x84\xf1\x6a\x0b\x8c\xe6\x05\x31\x0d\xe3\xdb\x26\x8c\xeb\xcd\x30\x1a\x10\x56\x5d\xed\x3c\xdd\xb2\xad\xe1\xa9\x44\x6a\xb8\x89\xf7\x86\x6d\xe6\x5b\x75\x23\xa6\xe1\x6c\xe0\xbe\x61\x9b\xfd\x7e\xdf\x06\x67\x13\xff\x0d\xdb\x0c\x48\xb6\xc2\xd9\xc0\x81\xc3\x36\x0b\xce\xb6\xc2\xd9\xc0\x83\xc3\x36\x13\x56\xdb\xe0\xfc\x50\xeb\xb1\x06\xa0\x16\x23\x16\xdb\xe0\x6c\xe0\xc4\x61\x8b\x15\xff\xf7\xff\xda\x04\xe6\x98\x0e\x37\xf0\xe2\xb0\xc5\x8c\x8b\xcd\xb8\x74\xf1\xd8\xc1\xfa\xe0\xc0\xde\xb4\x76\xb3\x07\x10\x64\x2d\x17\x69\x21\x32\xb1\x7a\xa5\x1e\x12\x40\x28\xc1\x83\x60\x0c\xc1\x03\xb2\xa8\x4e\xcc\xcd\xdb\x53\x2c\xc9\x85\x2d\x38\xc3\x82\xb9\x2d\xe8\x05\xbd\x31\xf4\x1e\xfc\x73\x59\x8a\x13\xfd\x1c\x40\xd0\x0b\x64\xd1\x5f\xbe\xfd\xc1\x96\x8c\x54\xc9\xdd\xc3\x67\x27\x3d\xfb\x74\x9f\x46\x5a\x4f\x55\xa3\x57\xbf\x47\x70\xf1\xe0\xf4\x2c\xe8\x7d\x1c\x5d\x8e\xe6\x03\xe7\xea\x38\x6f\xcc\xd9\x4e\xe3\x82\x5f\x9a\xf8\xf0\xda\xd3\x18\x6f\x49\xd7\x95\xbd\xfa\x3d\x7e\x13\xce\x6f\x28\x1a\xd9\xad\xf1\xf8\x7a\xb7\xe6\x43\x20\xf5\x9d\x69\x04\x8c\xa1\xc6\x0f\xef\x5e\xd6\x21\x5e\xb7\x55\xa7\x0d\xea\x35\x50\x11\xab\x75\x9d\x4b\xe8\xd5\x1a\xb7\x37\x0e\x45\x92\x44\x79\x31\x40\xbf\xec\x7f\xa0\x5e\xf1\x21\x49\x72\xa5\x5f\x14\xd5\x4f\x5b\x79\xcd\xd5\x13\xac\xb2\x68\x00\x9f\xd7\xfd\xb6\xa2\x6d\xcc\xdf\xcc\xa8\x4d\x03\x39\x3b\x9d\x7e\x98\x97\x31\xba\x40\x23\x4e\x09\x53\xef\x5f\x07\x41\x63\xc1\x4c\x12\x8e\xa6\x1e\x66\x54\xbf\x35\xe9\xfc\xdd\x70\x22\xbe\x9c\x2a\xfe\x08\x8f\xfb\x11\xaf\xf2\x4c\x84\xbd\x07\x3d\x7b\x01\xa5\x86\xf1\x9c\xe6\x95\x75\x4b\x35\x27\xf3\x8f\x46\xb3\xd0\x4d\x25\x68\xc2\x50\x13\xae\xbb\xf0\xd0\xc1\x74\x27\xb5\x0c\x95\x5d\x6a\x99\x37\xdb\x7d\xc6\x69\xe3\xaa\x8e\xd8\x48\xb2\xfa\x35\x26\xe7\xd1\x63\xed\x70\xd6\xaf\xc9\x2b\x03\x53\xae\xac\x3a\xa0\x7f\x78\xf7\xb2\x5e\xda\xbe\x53\xad\xec\xaf\xc6\xda\xf7\x0f\xf0\x55\x22\x7f\x3f\x28\xee\xab\x23\xf7\xf7\xf5\xf2\xf6\xb5\x5f\xab\x9d\x5a\x6a\xd2\x11\xac\xd7\xab\x7e\x6a\x50\xd2\x69\x34\x82\xd7\x6f\xce\x9f\x8e\x1b\xcf\x2f\x4c\x29\x5c\xd3\x4a\xe0\x23\x1b\xab\x22\x56\xa1\xe9\xd1\x52\x64\xf9\x88\x0b\x66\x7e\xc7\x65\x71\x13\xcd\xcb\x31\xc2\x7d\x99\x15\xd7\xcf\x4a\xf6\xd4\xa6\x78\x6d\x59\x03\x4b\x8f\xee\x6d\x8b\xcb\xa9\x84\x8f\xd9\xb5\x7a\xfa\x5e\x6e\xd3\x5c\xed\x2d\x7c\x46\xc0\xcd\x07\x6b\xec\x7a\x45\x81\xfa\xf1\x04\x93\x94\xf1\xa7\xd9\xd3\x01\xf1\x66\xfa\x89\xc6\x52\x08\xb5\x78\x75\x4e\x0b\xca\x88\x50\xec\xaa\x9a\x79\x02\xc7\xe0\xef\x65\xc3\xdd\x57\x49\x3f\xa1\x03\xdb\xe4\xfd\xaa\xa7\xd7\x55\xba\xe5\x03\xfd\x9e\x6f\x9a\x71\x51\xb2\x15\x32\xc7\x7b\x41\x04\x0d\x3f\xaf\x07\x10\x04\x03\x50\x29\x24\x3f\xca\x03\x8c\x43\xd4\x9d\x7b\xc4\x61\x48\x77\x85\x14\xdf\x75\xc8\x68\x77\x89\xf4\x3b\x36\x75\xa7\x3e\x7c\xd6\xd3\x9a\xa3\xdb\x14\xdb\x75\xa4\xc4\x77\x52\xba\xc1\x20\xfb\x74\x69\x4a\xc6\x7f\x78\x62\xcc\x42\x73\x65\x86\xe5\x3c\x74\x34\xd2\xc4\xef\xa2\x62\x43\x38\xad\x17\xc5\x0d\xc9\xb3\xa4\x43\xec\xa8\x27\x63\x5c\xb1\xa5\xba\x51\x11\x9b\xa5\x7e\xc6\xca\xc5\x1b\x35\x80\x06\xd0\x1e\x6e\x00\x47\x7b\x52\x26\xaa\x47\x57\x41\x2c\x98\xc0\xe8\x3f\xe7\x1f\x93\xc3\x8f\x51\x74\x38\x89\x0e\xef\x8f\xbe\x8e\x58\x1d\x33\x74\xe9\x85\x1c\x79\xbe\xac\x72\x13\xf5\xd5\xd3\x74\xca\x5b\x6b\x5f\xd7\x35\x34\xcd\x57\x4f\x2e\x12\x94\x0b\x17\xde\x49\xf7\xbd\x8a\x9d\x93\xdc\xb6\x1e\x1b\xd8\x63\xa0\x58\xf6\x45\x2d\x67\xa4\x5e\x75\x1a\xd4\x46\x43\xeb\x6c\xd1\x50\xa9\x15\xfe\xd7\x92\x37\x33\x29\x6d\x11\x9e\xf7\xb6\x14\x42\x53\xff\xd8\x24\x74\x86\x34\xba\xb4\x58\x2e\xa6\x94\xbd\x99\xa9\x41\x9f\x95\x4c\x42\x31\x9b\xd4\x45\x67\xef\x65\xa8\x2b\x54\x0e\x24\xff\x25\x13\x69\xd8\x42\x52\x13\xdb\x5e\xd1\xd1\x14\xd8\x86\xcf\x6e\x4a\xec\x9a\x84\xb4\x25\x62\x1a\x1e\x0d\xb6\xcc\x5b\x89\xbf\x4e\x50\xed\x42\x5f\x79\xec\x45\x13\x6b\xdb\xb4\x48\xa2\x69\xe1\x3e\xdb\xeb\xbf\xb7\x53\xdb\x9a\xce\xee\x7e\x33\x7b\x53\x68\x2d\xdc\xc6\xcf\xae\xb3\x02\xf2\x28\x8e\x97\x8b\x65\x4e\x04\xde\xcb\xd9\x43\x98\x6c\xe0\x58\x38\xd4\xf7\x81\x5b\x60\x6d\x8a\x57\xfd\x0f\x6f\x9a\x2f\xd2\x38\xad\xbf\x7a\xab\x6d\x9e\xfc\x6e\x31\xec\x3d\x5b\x04\x3e\x73\xb7\xb2\x51\xdc\x45\xac\x7b\xbf\x26\x0b\xfa\xa8\x48\xcc\x95\x02\xa1\x56\x54\x19\xa8\x93\x9e\xa3\xc0\xeb\xe6\xf6\x7f\x7c\xb9\x7d\xf1\x8d\xcf\x46\x63\x03\x34\xa1\x71\x99\xd0\x0f\xef\x5e\x3c\x2e\x17\x55\x59\xd0\xc2\xd0\xd2\x03\x70\x7c\x59\x1f\x9d\x3e\x1e\xca\x33\x53\x00\x41\xdf\x3c\xff\x29\x77\x92\x8b\xc2\x04\x02\x41\xa6\xce\xcd\x0d\x7f\x48\x7b\x8b\xdc\x29\x56\x0f\xef\x0a\x32\x85\x8c\x63\x6a\xd8\x9c\x32\xed\x68\x75\x0d\xd2\x8b\x7a\x98\x4b\x3b\xd5\x9f\xcd\x0b\x47\xeb\x8e\xe5\x6f\x3f\x48\xb4\x6b\xd1\x9b\x72\xcc\x5d\x6a\xc7\x50\xd3\xa3\x04\x73\x69\x99\x64\x9a\x4d\x83\xa8\x7d\xed\x66\xd7\x78\x1d\xe6\x55\xcb\x62\x69\x58\x5a\x96\xcb\x2a\x83\x61\xb7\x04\xce\x3c\xe1\xeb\x9b\x79\x8a\x2d\xd5\xd7\xe8\x9a\xae\xb8\x37\x52\xbf\xcd\xa4\xd7\xf5\x7f\x17\x72\x20\x5d\x68\x14\x0e\xe1\x9a\xae\x2e\x8d\xad\xaa\xa1\x5c\xc8\xb2\x56\x5e\xb5\xd3\x5b\x11\xcb\x9e\xbf\xe5\x31\x58\x1b\xd1\xea\x7e\xf5\x7b\x2a\x96\x95\x0e\x3e\xc7\x24\x4e\xe9\x58\xbd\x8b\x5c\x2f\xb6\x77\x0f\xbb\xf3\x11\x50\x2e\x88\xc8\xe2\xd1\x27\x3e\x52\x87\x1d\xfb\xcf\xb9\x52\xf3\x0f\xbb\x7e\xbc\x99\xc8\x45\xf4\xfe\xcb\x96\xce\x43\x6c\xdd\xb6\x4e\x88\x20\x12\x43\xcd\xd9\xde\x7f\xce\xd2\x61\x15\x13\x87\xb0\xff\x65\x0b\x19\x5e\xf5\x34\x75\xea\xae\xcf\x13\x5a\x31\x1a\x13\x41\xd5\x79\x0e\x8f\xf4\xfe\x4d\x87\x24\x63\x34\x16\xe7\xe5\xab\x6c\x2e\x79\x24\xb1\xa7\x7e\xe8\xca\x83\xc7\x7f\x5a\xa8\x1c\x12\x1d\x67\x80\xd0\xc9\xa7\x47\xa6\x54\xe4\x6e\x67\xc7\x6b\x2f\x07\x1e\xad\xce\x53\xca\x29\x88\xdb\x52\x5f\x71\xe7\xdd\x78\x63\xf2\x65\x27\xba\x7d\x09\x85\x30\x0a\x24\x49\x68\x02\x65\x91\xaf\x30\x34\x34\x25\xf1\xf5\x2d\x61\x09\xde\x65\x26\x22\x9b\x66\x79\x26\x56\xf2\xe4\x56\xe6\xe6\xc1\x5b\xe5\x7e\x8f\x1c\x06\xe9\x24\xd9\x46\x47\x41\x4a\x78\xba\xc5\xb2\xa9\x9f\xd8\x36\xca\x4f\x49\xc3\xe4\x19\x23\xf3\x85\xca\xd8\xe9\x90\x8f\x5d\xa3\xa8\x68\x2e\x5b\xd9\xc5\xc0\xcb\xc1\x7a\xe1\x7d\xa0\x5a\x27\x87\xc7\x7d\x25\xf4\x12\x56\x56\x18\xd8\x97\x70\xe0\x2f\xe8\x8d\x8b\x31\x4d\x28\xa4\x2d\x9f\xa2\x83\x72\x6d\xa5\x33\x29\xfe\x5c\xc7\xdc\x06\xbe\xb1\x62\xe3\xcf\x4d\xb3\xe3\x80\xfa\x67\x66\xdb\x2d\x9a\x9a\x5e\x29\xcf\xf2\x29\x7d\x71\x58\xeb\x4d\x2b\x0f\x3b\xc4\xb2\x6c\xe3\x8a\xbb\x72\x1f\x49\xb7\x5d\xd6\x95\x0d\x31\x07\xde\xff\x06\xb3\x13\xc3\xe7\x22\xba\x8f\xc3\x0d\x22\x77\x3c\x81\xd1\x38\xfe\xe2\x42\xdf\x0f\xe5\xd6\xed\x9f\x1c\xfc\x9f\x00\x00\x00\xff\xff\x61\x8b\xd1\x88\xa6\x74\x00\x00")
+var _webUiStaticJsGraphJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xe5\x7d\xeb\x76\xdb\x46\xd2\xe0\x7f\x3d\x05\x8c\xf1\x86\x60\x4c\x42\x92\x3d\xc9\x37\xd1\x2d\xeb\xf8\x12\x7b\xbe\xf8\x12\x5b\x49\x66\x46\x56\x74\x20\x12\x22\x11\x83\x04\x03\x80\x92\x98\x58\xfb\xf3\x3b\x67\xdf\x63\x5f\x61\x5f\x60\x5f\xe0\x7b\x87\x7d\x92\xad\x4b\xdf\xd1\x00\x29\x3b\x33\x67\xf7\x6c\xce\x8c\x2c\xa1\xbb\xab\xab\xab\xab\xab\xab\xab\xab\xaa\x2f\x93\x32\x78\x5d\x16\xb3\xb4\x9e\xa6\xcb\x2a\x38\x34\xff\xf8\xf0\x21\xf8\xfd\x66\x7f\xeb\x12\xaa\x4c\xca\x64\x31\x3d\x4e\x67\x8b\x3c\xa9\xd3\xfd\x2d\xfa\xf6\xf6\xc9\xa3\x57\x2f\x1f\x43\x93\xdd\x9d\x9d\x1d\xf8\xa6\x5b\xc6\xdf\x62\x75\x28\xb9\x58\xce\x47\x75\x56\xcc\xa3\x34\x4f\x67\xe9\xbc\x1e\x04\xc5\x02\xff\xae\x06\xc1\x34\x99\x8f\xf3\xf4\x11\xfc\x33\x49\xe5\x5f\x6f\xd2\x59\x71\x99\xf6\x83\xdf\xb7\x82\xa0\x9e\x66\x55\x9c\xe6\x00\x44\xb4\xdd\x97\x1f\x09\x97\x67\xc7\x2f\xbe\x83\xb2\xf9\x32\xcf\x55\x81\x80\x0d\x9f\xc5\x6f\xaa\xc4\xec\x0c\x8a\xcd\x3f\x9d\x3a\x8c\x82\x89\x3a\xa3\x13\x58\x28\x46\xd8\xa2\x8f\x4d\x6f\x54\xfb\x32\x1b\xbd\xaf\xa6\xc9\x95\x1c\xbb\x85\xda\x38\xa9\x13\xf8\x76\x72\x0a\x74\x12\x9f\xb2\x79\x56\x67\x49\x9e\xfd\x96\x46\x00\xe9\xc6\x43\xc0\xb8\xce\x66\xe9\xd3\x64\x54\x17\x25\x0e\x0a\xd1\x08\x57\xe1\x5e\xf0\xe5\x4e\xf0\x39\xff\xb8\xff\x67\xf8\xf1\xe0\xcb\x2f\x06\x58\x74\xd5\x2c\xfa\x37\x2a\x18\x3b\x05\xf4\x71\xaa\x3f\xd2\xdf\x33\xfa\x9b\x7e\xad\xe0\xd7\x5d\x3f\x46\x55\x9d\x2e\x7e\x4c\xf2\x65\x8a\x08\x9d\x60\xe5\xdd\x2a\x1c\xc0\xcf\x1d\xfe\x67\x86\x3f\xbf\xa0\x9f\xbb\xfc\xcf\x83\x1d\xfe\x6b\x8a\x3f\xef\xd3\xcf\x2f\xe9\xe7\x2e\xff\xb1\x3b\xa6\x02\xf8\x49\xd0\xae\xe8\x2f\xfa\xf9\x67\xfa\xf9\x17\xfa\xb9\xbb\xa2\xef\xab\x70\x0b\x29\xb8\xbd\x1d\x1c\x4f\xd3\xa0\xca\x26\xf3\xa4\x5e\x96\x80\x0c\xcc\x4d\x30\x4e\xab\x51\x99\x09\x1e\x28\x2e\x80\xca\x29\x71\xf3\xf7\xdf\xa9\xc9\x04\xbe\xab\xa6\xc5\xd5\x3c\xb8\x9a\xa6\x73\x04\x33\x2a\x80\xa5\xd3\x3a\x9b\x4f\x08\x44\x3a\xce\xe8\x77\x59\x3f\x18\x25\x79\x5e\x05\xd9\x9c\x80\xa5\xd7\x0b\xe8\xab\xc2\xef\xd9\x7c\xb1\xac\xe3\x26\x7d\x64\xc3\xc7\xc5\x48\x4e\x59\x72\x5e\xed\x09\x16\x52\x08\xef\x05\x21\x7c\x8e\x2e\x01\x4e\x55\x27\xf3\x7a\x78\x99\xe2\x2c\xf7\x89\x08\x41\x30\x2e\x46\x6d\x35\x82\x32\x05\x00\x30\x40\x44\x88\xb0\x08\xb8\x24\xb8\xca\xea\x69\x00\xe8\x06\x55\x82\x63\x0a\x2e\x79\x9a\x46\xc5\xfc\x32\x2d\xeb\x74\x1c\xd4\x05\x36\xca\x4a\xc4\xa8\xc8\x97\xb5\xa8\x12\x87\xc8\xc6\x03\xc6\x14\xd6\x59\x0b\xb2\x50\xb2\x1e\x5f\x6f\x25\x85\x72\x32\x0f\x40\x82\xd4\x2b\x89\x71\xc6\x73\x24\xfe\x5a\x24\x55\xc5\x58\x66\x35\x2c\x36\xac\xbe\x92\x6b\x9f\x27\x38\x09\x76\x87\xe2\x83\x35\x68\x02\x82\x43\x09\x76\xd7\xc1\x9c\x17\x0a\xa4\x31\xee\xcb\xc9\x19\x2c\xea\xf2\x0c\xd7\x9b\x77\xf8\x66\x85\xa8\x44\xb1\xe1\xa5\x00\x32\x65\x02\xf5\x92\x89\x44\x08\xd8\x10\xa7\x64\x51\x64\x38\x08\xc1\x47\xd5\x22\x1d\x65\x17\x19\x20\x06\x5f\xd3\x12\x6a\x6a\x5c\x46\x69\x96\xfb\x50\xc0\xef\x6b\xe8\xef\xaf\x12\x94\xc5\x72\x3e\x66\x7e\xb1\x39\x43\xe0\xa6\x48\x0c\xd8\x5d\x06\xcb\x85\xe0\x93\x60\x9e\x26\xc0\xed\x35\xe1\x38\x49\x4b\x03\x45\x92\x9b\x5e\xa6\x16\x45\x80\x45\x3b\x91\x9e\xc2\xbc\xa4\xc9\x68\x2a\xb8\x17\x49\x1a\x54\x69\x99\xa5\xb0\x38\x5b\xda\x5b\x4c\x3f\x5f\xce\xce\xd3\x92\x16\x38\x34\x05\xb4\x01\x75\x26\x36\x4e\x30\x43\x18\x13\x63\x08\x72\x2f\xca\xe2\x32\x1b\x23\x1b\x60\x57\x04\x38\x20\xf6\x92\xa4\x12\xdc\x62\x8c\x30\x07\x42\x9d\xcd\x92\x6b\xef\x18\x65\x61\x83\xd6\x83\x00\xbe\x06\x15\xc8\x8c\xc4\x9d\x9a\xcd\xda\x70\xc7\x1b\xcf\x15\x4c\xd4\x14\xd8\x0d\x47\xb2\x5c\x2c\x80\x26\x79\x36\x03\x3e\x87\xba\x00\xb3\x31\x9a\x6c\xde\x31\x9a\x6c\xee\xc3\x0c\x7a\xe9\x18\xcd\xba\x36\x1f\x3b\x9a\x20\x2f\xae\xac\xc1\x64\x73\x63\x30\xc0\xcd\x75\xf7\x6a\x75\xaa\xac\x59\xaf\x54\x5b\x62\x24\xf0\xdb\x60\x9d\x8e\x93\xd5\x59\x71\x71\x36\x2b\xe6\xf5\xd4\x87\x84\x59\x1e\x5d\x1e\x72\xef\x11\xe1\xd3\xef\x37\xa8\x56\x5c\x5c\x54\x69\x7d\xb8\xe3\x25\xf7\xa7\x81\xb2\xd6\x0e\x80\x92\x3b\x23\x81\x0b\x2e\xe4\x62\x14\x5f\x27\xd9\x65\x3a\x97\x0b\x8b\xa9\x40\xab\xe6\xb7\x62\x9e\x0a\xd0\xc1\xb4\x58\x82\x3e\x92\x26\x15\x91\xed\x87\xe3\x47\x0d\xaa\x5c\xa5\xe9\xfb\x0e\xa2\x60\xf1\x1f\x43\x93\x8f\x83\xd4\x46\x12\x84\xf6\x4f\xa0\x48\x75\x96\xcd\x3b\x19\x45\x57\xf8\x74\xaa\x7c\x0a\x2c\x45\x17\x2d\x62\x11\xa2\x1c\xf6\x3f\x87\x63\xd2\xbc\x4e\xbc\x74\xc1\x82\xce\x8d\xc4\x5b\x03\x35\xb6\xd1\x12\x4f\x29\x62\x7a\xb3\x8b\x8b\xb4\x4c\xe7\xa3\x34\x38\x4f\x6b\x98\x61\x46\xf1\x22\x2b\x01\x19\x54\x2a\x72\xc4\x4a\x6d\xd6\x34\x32\x63\x4b\x92\x32\x0a\x87\x96\x88\xbd\x43\xe8\x16\x97\x03\x41\x2f\x56\x1e\x9d\xcd\x44\x6b\x26\x4c\x20\x42\x96\xd5\x98\xf4\xd7\x65\x06\x1d\x22\xd4\x3c\x39\x4f\xf3\xca\xa4\x46\x99\x5d\xfa\xa9\x01\x05\x6b\xa8\xe1\xa9\xe1\x52\x03\x36\x89\x61\x95\x82\x4a\x38\xe6\xae\x92\x1a\x90\x93\x33\x69\x0e\xdb\x3b\xdc\x65\x85\x43\xad\x32\x12\xe5\x79\x86\x0a\x02\x90\x60\x22\x74\x62\x3d\x0a\xd0\x93\x7d\x63\x80\xcf\x6b\x34\x18\x6f\x0d\x77\x0c\x50\x09\x58\x6b\x8e\x67\x27\xad\xa7\x23\x57\x36\xb6\x14\x8d\xd1\x45\x5e\x14\xa5\x0f\x27\x2a\x58\x83\x55\x4b\x9d\x5b\x2a\x56\x63\x3c\x76\xac\x53\xad\xe0\x5c\x58\x17\x70\xbe\x9d\x9d\xfd\xba\x84\xae\xb2\xdc\xbb\xc1\x35\x6b\x45\xff\xf9\x1f\x38\xc6\x04\x0e\xd8\xe7\x9d\x63\xb9\x5d\x53\x97\xf4\xff\xf9\x1f\x43\xd9\x2c\x88\x76\x82\xff\xfd\xdf\xff\x07\x7c\xa2\x7f\x76\xfb\xc1\x05\x9c\x85\xa8\xd6\xf9\x72\xf4\x3e\x85\x61\x9f\x13\x21\xf4\x98\x8c\x61\x16\x79\x7d\x76\x45\x9b\xaa\x57\x8d\x34\xcb\x1d\x96\x86\x03\xdc\x85\x90\x57\x83\xa0\xbe\xf0\x8a\xc1\xdb\x37\x47\x1d\x71\xbc\x1c\xe1\x41\x32\xa8\x66\x45\x01\xc3\x18\x0b\xa1\x80\x9c\x65\xae\x8c\xf3\x04\x4f\x14\x05\x8b\x11\x5e\x21\x36\xaf\xa1\xb0\xf3\x0f\x6a\x59\x7e\xa2\x7c\xff\x38\x10\xd6\x76\x87\x20\xe4\x82\xc7\xad\xef\x0f\x16\xe7\x59\xab\x3c\xcf\xd6\x0b\xf4\xec\x53\x24\x3a\xc9\xf1\xfa\xaa\x10\x6b\x51\x61\xff\x4f\x97\xd9\xd9\x7c\x54\x02\x2d\xbc\x2b\x55\x96\x75\x0f\xbb\xa5\x92\x3b\x70\x59\xcf\x9a\x17\x2d\xaf\xdd\xc1\x1a\x18\x96\x00\xc3\x8b\x1e\x16\x74\xe3\xe6\xab\xd1\xb1\xab\x48\x82\x62\x33\x64\x0e\x85\xb3\x7f\x8f\x69\xc7\x99\xa8\x7c\xf6\x4b\xe1\x3f\xb5\xe8\x52\xcf\x11\x64\x5c\xd5\x67\x54\x21\xa8\xea\x12\x26\x1a\xd6\x7c\xba\x48\x00\x23\x98\x61\xf5\xa5\x1c\x71\x9d\xb3\x5d\xcf\xb7\xfb\xea\x5b\x1c\xc7\x2d\xa7\x57\x5a\x22\x6a\x20\xc0\x58\xff\x52\x9c\x02\xec\xa7\xa2\x6d\x46\x59\x3f\xd4\xc6\x43\x3b\x92\x6c\x58\xc9\x6d\x5b\xf5\x87\xfc\x6c\x8a\x04\x63\x24\x6a\x01\x30\xae\x1a\x6b\x98\xdc\x3a\xc9\x68\xd9\x60\x31\xf6\x2e\x25\xa4\x3b\x67\x65\xba\xc8\x93\x51\xda\x3e\x6d\xa2\xc2\x46\x54\x12\x75\x49\x05\x6b\xd0\xc4\xa8\x35\x49\xaf\xc5\x5f\xb7\x9b\xae\x7f\x32\x2e\x70\x10\xaf\x47\x53\xb1\x4c\xa0\x04\x16\x4d\x69\x9a\x11\xb9\x72\x32\x49\xb0\x77\x83\xf0\x0a\xac\x41\x5c\xff\x42\x98\xaf\x51\x5e\x7c\x15\xdc\xf5\x4b\xd0\x40\x9b\xca\x8b\x49\x52\x02\x07\xcc\xd6\xa9\x53\x50\x71\x77\xc7\x8b\x0e\x16\xac\xc3\xc8\x5f\xa7\x21\xe6\xe1\xec\x3d\xbb\x25\x52\xf7\x5b\x70\xba\xbf\x1e\xa5\xfb\xeb\x31\x3a\xcf\xe6\x49\xb9\xda\x1c\xa1\x59\x72\xdd\x6d\xa8\xb0\x2a\xac\x31\x53\x40\xdd\x6c\xb6\x9c\x7d\xac\x59\x71\x06\xa7\xc2\x6e\x5c\xcc\x0a\xeb\x70\x01\x41\xf0\x69\xb8\x2c\xeb\x36\x24\x96\xb8\xdd\x7c\x92\x92\xf4\xb1\x40\x2c\x99\xc8\x40\xe4\xae\x45\x4a\xd3\x1f\xac\x29\xb5\x1a\x04\xfe\x08\x43\xc0\x1f\x61\x2b\xe2\xe3\xbe\x18\xec\x0a\xcf\x7a\x7f\x30\x05\x40\x0a\x8e\xb3\x11\x48\x59\x3a\x49\xfa\x48\x61\xd7\x68\xa8\xf2\xb5\x77\xec\x9b\x36\x92\xfd\x57\xc6\xfd\x81\x30\x2a\x4b\x0d\x05\xea\x92\x5a\x53\xf1\xe1\x66\x5e\x5c\x0d\x7c\xfa\xff\xed\x4f\xc8\xf2\x14\xd5\xbd\x26\x9b\xb5\x22\x79\x74\xe9\x5e\xa0\x9d\xe7\x34\x31\x79\x9b\x9b\x3a\xdb\xb4\xc7\xb5\xca\xe3\x2d\x75\x47\x79\x73\xf2\xc9\xba\x23\x90\x1b\xce\x9f\x5e\x94\xa9\xe4\xa3\xaf\x27\xfc\xcd\x5b\x6e\x27\xc8\xb0\x9c\x96\xa2\xd1\xa7\x5d\x48\x90\xa9\xc1\x3b\x20\x2c\xf0\x68\x2f\x75\x71\x26\x8c\x0c\x87\xbb\xde\x55\x72\x9b\x86\xb7\xb4\x74\xac\x33\x72\x30\x54\xdf\x68\xb8\x64\xcd\x66\xfd\x2d\x49\x1d\x38\xa7\xc3\x52\xcb\xd3\xa1\x36\xd0\xe9\xbb\xd0\x41\xd0\x06\xc9\x9a\x2b\x73\x2c\xcc\x64\x49\x2d\xc0\x2a\xbb\x5f\x42\x26\x01\x82\x66\x8c\xa0\x28\xbd\xd7\xa4\xf8\x7d\x0d\xf6\xfe\x2a\x0a\x2d\x21\x4a\x14\x41\xb1\x3a\x30\xca\xf9\x4a\x5c\xdd\x5a\xe4\x1f\x90\x99\xae\x1a\xa5\xf3\x31\x8a\x9d\xa2\x1c\xa7\x0e\x92\x67\x78\x27\xde\x86\x29\x15\x46\x36\x7a\x6f\x93\x19\xf1\x22\x96\x83\xac\x03\x8a\xe2\x6f\x34\xb1\x58\xbb\xa5\xa7\x5f\x5b\xc8\xf1\xeb\x7a\x72\x78\xab\xb8\x42\xa2\x02\x69\x56\xc2\x4a\x29\x8a\xda\xcb\x72\x06\x2a\xf5\x78\x9c\x5e\x76\x8b\x55\xb7\xce\x1a\x6d\x67\x51\x2c\x10\x15\x54\xd7\x11\xc9\x71\x52\xa2\xf1\xf4\x32\xe3\x4f\xb7\x96\xa6\xd0\xfb\x65\x52\xae\xc5\xd0\xaa\xf3\x11\x18\x42\xfb\x2c\x41\x1b\xc9\xed\x11\x5c\xce\xd6\x60\x67\x56\x58\x83\x1a\xd4\xbd\xfd\xdd\x5a\x5b\xc7\xac\xc6\x58\x7d\xf0\xa7\x16\x09\x2c\x37\x6f\x58\xd2\x40\x88\xbf\x26\xf3\x25\xea\xef\xbb\x83\x60\xf7\xab\x7f\xdb\xb1\x75\x11\x52\x62\x6a\x58\x5c\x6d\xfd\x52\xe1\x1a\x66\xee\xa8\xd7\x3c\x70\x63\x3d\x75\xe3\x20\x26\x49\x9a\xae\x2c\xfd\x4a\x88\x84\xe4\x13\x46\xc7\x20\x7c\x43\x13\x0a\x62\xe5\xdd\x23\xdc\x42\x5b\x78\xd2\xb7\xa0\x62\x09\x69\x1a\xd0\xe6\x45\xc3\x46\xb6\x6a\x51\xf0\x56\xa4\xa1\x7d\x92\xaa\xfb\x71\x20\xac\xa1\xfc\xd1\xca\xad\xdf\x75\x0a\x66\x8e\x7e\x41\xbf\x20\x9f\xbb\x5c\x0c\x6a\x41\x5d\xd4\xab\x45\x6a\xb8\x86\x35\x1d\xd1\xd0\xf3\xae\x4a\xf3\x0b\x28\x41\x37\x32\xf4\x30\xc3\x3f\xe3\x6c\x6c\x39\xef\xb9\x9d\xde\xbb\x47\x9e\x67\xdb\xdb\xc1\x5b\x40\x7c\x9c\x5e\x24\xcb\xbc\x96\x7e\x72\xb1\x04\x22\xff\x26\x60\x02\xec\xbe\x5b\x48\x2b\xfe\x8c\xf7\xdb\xc3\xf6\xa2\x0f\x1f\x4c\x74\xa0\x57\xf4\xac\xaa\xe2\x49\x5a\x47\x21\xb9\xf0\xbd\xc1\xca\x21\x79\xd1\x65\x17\x41\x64\x01\xaa\x93\xf3\xe0\xf0\xf0\x30\x00\xbd\x23\xbd\x40\x5b\x93\xf4\xc2\x6b\xd6\x0a\x76\xc9\x0f\x4f\x8c\xee\x71\x99\x5c\xb1\xb7\x22\xd9\xac\xca\x22\x67\x03\xae\x30\x60\xc1\xd2\x21\x55\xfe\x19\x39\xf3\x9d\x27\x30\x7d\xb5\xf0\x6a\x8c\xb7\x04\x75\xb5\x1b\x21\x77\xd9\x5b\x24\xf5\xf4\x75\x09\x78\x5c\xf7\xf6\x82\xd7\x0f\x8f\x9f\x9d\xbd\x7e\xf3\xe4\xe9\xf3\xbf\x31\x1b\xf6\xce\x97\x59\x3e\xfe\x31\x2d\x51\xc5\x87\x0a\xdf\xfc\xf0\xfc\xbb\xc7\x67\x3f\x3e\x79\xf3\xf6\xf9\xab\x97\xd2\x43\xf0\x97\xef\x97\x69\xb9\x8a\xd3\xeb\x1a\xb6\xd0\x48\x39\x41\x9a\xa3\xe9\x2b\x42\x9b\x0e\x8e\x77\xa3\x17\x4b\x60\xe5\xd1\x34\x8d\x4b\x68\x9a\x96\x91\xe5\x8a\xa9\x1c\x2a\xfb\xba\x79\x9a\xc7\xc9\x62\x81\xfd\xd8\xd0\xfa\x92\x03\xbe\x05\x0e\x80\xe1\xb0\x11\xbd\x42\x7d\x4d\x5a\x0d\x49\x16\x83\x6c\xc2\x4d\xde\xdc\x61\x69\x41\x28\xa2\x32\x1d\x81\x82\x0c\xee\x3c\x43\x03\xf9\x25\x6a\x4b\xec\x23\x59\x12\x43\x29\xb7\xd1\x9f\xca\x84\xbc\x53\x0e\x15\x7a\x30\xa3\xe3\x28\xfc\x13\x95\x9e\x5d\x71\x71\x18\xdc\x93\x1c\xa7\x87\xf2\x2b\x52\x0d\x34\xf2\x19\x34\x36\x61\x09\x08\x5c\x7e\x06\x4b\x77\x16\xf2\xe8\xb8\x87\xeb\x45\xe9\x6f\x50\xc3\x04\x80\x26\x91\x9c\xcc\x41\xcf\x39\xc4\x7a\xa7\xa1\x41\x38\xf8\x3b\x7e\x9f\xae\xc8\x3c\x17\x69\xdf\x55\xc9\x7b\x30\xd6\x27\xa4\xd2\x5f\x81\xcc\xa3\x4a\xc2\xd1\xa8\x58\xb2\xf1\xae\x9a\x66\x17\x75\x00\x10\x62\xaa\x8f\x5c\x9d\xc6\x57\xd3\x0c\xa4\x0a\xf0\xf2\xee\x83\xe0\xb3\xcf\x82\x3b\x69\x4c\xd5\xfe\x3d\x5d\x49\xb8\xee\x60\xe3\x6a\x79\x3e\xcb\xea\x88\x30\xc3\xff\x52\x90\x0d\x44\xe0\xc7\xbc\x6e\x65\x09\x31\x3d\xe1\xf5\x70\x59\x17\x43\xc0\x08\x45\x06\x89\x2a\x18\x68\x80\x23\x0d\xb4\xb3\x22\x56\x25\xfe\x66\xe1\x75\x28\x9c\x66\xe9\xaf\x67\x69\x36\x99\xd6\xc1\x90\xbf\x8d\xf2\x0c\x3a\xe3\x6f\xfb\xaa\x1d\x83\x3f\x16\x24\xb4\xbd\x7b\xf5\x50\x02\x60\x59\xf8\x3b\x1e\x01\x09\x7b\x53\x02\xd1\x1b\x04\xbd\x04\x10\xec\xb9\x5f\x81\x15\xaa\x11\x2c\xd1\x5c\x74\x7f\x4f\xe0\x26\x87\xc7\xff\xdc\x65\x6f\xdb\x18\x3a\xea\x01\x6d\x97\x0b\x1e\x10\xb4\x37\x45\xa3\x83\x9e\xf0\xd0\x0d\x6e\xd8\x4b\xd7\x99\x64\xf6\x12\xe3\xf5\x61\x3a\x03\x1b\x4c\x44\xa2\xec\xb9\x29\xe4\xf4\xfc\x30\x33\x11\x16\xcc\x49\x86\xdc\x33\x19\x0a\x17\xee\xfb\x74\xfc\x4d\x3d\x6f\x83\x21\xab\x9c\x9d\xd7\xf3\x66\xc3\x0d\x7a\x16\x35\xcd\x5e\x61\xef\x4b\xcb\xfa\x45\x5a\x97\xd9\xa8\x0d\x02\x7c\x84\x9d\x91\x41\x70\xfd\xb3\x19\x35\x30\x01\x81\x8c\x00\xa2\x4e\x9f\x0b\x05\x6d\x13\x58\xa2\xc9\xa9\xb9\x1c\x41\x64\x54\x45\x9e\x1e\x93\xb0\xf6\xad\x62\x51\x21\x74\x24\x20\x36\x08\x5a\x9a\xb0\xe8\x50\xc2\xc8\xec\x0e\x36\x85\xca\xdf\x2a\x39\x41\x37\xec\x61\x5d\x4c\xe0\x84\x77\xd8\x83\x8a\x3d\x73\xb8\xd8\x30\x4e\x7f\x6d\x6c\x44\x7d\xfc\x01\xc3\x9c\x16\x57\x6e\x6d\x60\x3d\xfa\x3e\x8f\xcf\xa9\x6a\x68\xf0\xa4\x12\x1b\xb8\x76\x80\x27\x27\xb4\xe6\x60\x71\xc4\xfc\x87\x60\x72\xcf\x86\xc6\xe5\xf1\x02\xf8\x78\x0e\x6b\x1d\x26\x74\x9c\x5e\x47\x66\x7d\x93\x67\x65\x01\x4a\x9b\xbb\x20\x55\x51\x90\x0a\x08\x49\x5d\x97\x30\x6c\x38\x09\x0c\xe5\x66\x18\xf6\xfb\xd0\xba\x7a\x94\x27\xb0\x12\xc3\x32\xcd\x8b\x64\x0c\xdf\x6c\x49\xc4\xf2\x87\xb6\x2c\x2d\x6a\x78\x15\xb1\xc8\x7f\x43\xda\x53\x80\xae\xf0\x15\x68\x4e\xa3\x25\xde\x8b\x8f\xde\xe3\x56\x42\xc2\x17\xd5\xaf\x34\x19\x93\x9a\x4a\xb0\x70\x47\x89\x7d\x0c\x1a\x9f\xd3\xd4\xc0\xba\x46\x07\x09\x74\xf2\x66\xcd\xcc\x4b\x49\xbd\x80\xa9\x4f\x8b\x24\xf4\x19\xb8\x34\xb2\xff\xea\x8b\x3a\x0c\xb5\x45\x92\xde\xf4\xf5\xde\x51\x96\x45\xcb\xe6\xc1\x65\x21\xd0\x2f\x1b\x0b\xaa\x53\x93\xab\x84\xee\x93\x5b\x98\x4e\x15\x37\x1b\x52\xed\x87\x2c\x4b\xdb\x99\x1c\xa5\x99\xbb\x34\xe4\x52\x54\x10\xac\x26\x46\xed\xd5\xc3\xeb\xac\x6a\xad\xbd\x3a\x4b\xa0\xd8\xa8\x9e\xa7\x13\xd0\x1b\x5a\xd0\xe1\x42\x53\x4a\x2d\xb2\xf9\x3c\x6d\xa3\x96\x28\x35\xf7\x57\x98\x90\xb7\x75\x52\xb7\x51\x0a\xcb\xcf\x2a\xac\x60\xed\xe6\xf3\xf1\x63\xb4\x08\x7a\xdb\x18\x92\x10\xea\x35\x25\xb0\x68\x8c\xf1\x17\x29\xaa\xef\x8b\x0c\xa4\x65\x19\x31\x3b\xe5\x05\x1c\x06\xe0\x18\xd2\x4b\xe7\x3d\xd6\xe5\x50\x93\x48\x6a\xf8\xf2\x77\xf8\x6f\xf8\xe2\xc5\xf0\xf1\xe3\xe0\xd9\xb3\xbd\xd9\x4c\x94\xd7\x45\x91\x83\xd2\xf8\x5a\xde\x03\x42\xcd\xf3\xa2\xae\x0b\x59\x5e\xc1\x04\x7f\xb3\x7a\x0b\x3f\xf7\x82\xba\x5c\xa6\xe2\x2b\x48\x88\xe3\x62\x9c\xac\xbe\x59\x42\xdd\xb9\x5b\xf4\x28\xa7\xd3\x91\xfb\xb1\xa8\x2c\x20\x88\xfd\x3f\xe0\xec\x01\x5d\xc2\x41\x83\xfa\xbb\xf1\xeb\xce\x8a\x10\xf6\xb2\xd1\x94\x48\xa2\x1e\xfe\x7a\x0c\x10\x5f\x13\x3d\x60\x63\x46\x02\xb5\x81\x61\xfd\xda\x81\x83\xa2\x6f\xbc\x10\x3b\x69\xe8\xec\xc5\x1e\x29\x62\xee\xc1\xce\xc6\x22\xb7\xe3\x26\x88\xe5\x02\xf1\x7a\xc3\xd5\x25\x10\x25\x46\xaa\xb7\x6a\x9b\x6c\x44\xeb\x88\xf5\x6e\xee\xa6\x2c\x0f\xe8\x58\xd1\xdb\xed\x89\xe0\x1d\x79\xa2\xaa\x57\x79\x4a\xe0\x78\xb3\x6e\xc0\xc3\x4a\x19\x08\x51\xb9\x96\xf4\xd6\xce\x9c\xd8\x8b\x27\xf9\x6a\x31\xc5\x2a\x3d\x43\x20\xdb\x88\x46\x0d\x41\xab\xa1\x24\xe3\xb1\x10\xca\xa0\x0a\x0c\x17\x65\x36\x83\xe3\x7c\xa8\x54\x40\x04\x6c\xd4\x51\x9d\x0d\xe1\x64\x30\x7a\xef\xd4\x2b\x29\x48\xa9\x51\x15\xc6\x84\x95\xd3\xb1\xac\x7e\x03\x1a\x58\x95\xb6\xa2\x64\x81\xb9\x1d\x56\x8d\xae\xba\x31\xb3\x06\x71\x23\x0f\x4d\xd6\xa4\x44\xc6\xcc\x1b\x38\x82\xaa\x3a\x7a\x1f\x35\xa6\xcb\x47\x7b\xd4\xbe\xb5\x1c\xfc\xeb\xdb\x57\x2f\xf5\x6c\xc0\x9e\xf6\xfc\xc2\x38\xe6\xa0\x86\x2f\x7a\x19\xd0\xe7\xa2\xcc\x26\xd9\x1c\x94\x20\x71\x01\x41\x01\x5d\x93\xa2\x0e\x66\x4b\x10\x58\xe9\x58\xc3\xa1\x3b\x1a\x3c\xb0\xe2\xb1\xf3\x0a\xed\xe0\x1c\x81\x52\xa2\xbd\xa6\x82\x05\x3d\xaa\x31\x1a\x45\xb9\xcb\x29\xc8\x88\x11\xc1\x8d\xcd\xf9\x10\x91\x63\xac\x73\x80\x9e\x59\xa1\x8c\x7a\x8c\x8b\xd8\x19\x8b\x26\x5e\xd0\x64\xfb\x06\x2d\xbe\x0e\x7a\x3b\xbd\x60\x0f\x57\x82\xdc\x45\x5d\x6a\x2b\x40\xbc\x0a\xc9\x8e\x10\x29\x75\x5a\xce\x05\xb9\xf7\x95\xab\x17\xe9\x7c\xd9\xaa\xe4\x8a\x3a\xa0\x62\xce\x97\xa1\xe7\x74\xe7\xd6\x9b\x94\xc5\x72\x11\xf6\x95\x7a\x85\xda\xd5\xb8\x2c\x16\x42\x45\x68\xcc\xb6\x10\x2a\x78\x3c\x7e\xc6\x20\x22\x57\xed\x37\xd0\x24\xb0\xc4\x37\xa8\x6e\x24\x5e\x4d\xa3\xed\xc4\x45\x62\xa0\x4e\x67\xa4\xc9\xa9\x20\x40\xe6\x37\xfc\x6e\xe8\x56\x23\x14\xeb\x67\xa2\x5f\x53\xc5\x32\xac\x22\x02\xdb\x98\xea\xea\xf3\x1e\x0b\xaf\xc6\x84\x2a\xa5\x86\x7a\x22\x41\x8e\x2e\xb3\xb0\xc5\xae\xd3\x0d\x3b\xce\x97\xe6\x5c\x36\x6a\xf8\x29\xad\x15\x7a\x63\xed\x4b\x55\xd2\xe8\x50\x6a\xf1\xdd\xb5\x3c\xca\xa6\x10\xde\x17\x09\x48\x27\x67\x1e\x85\x56\xa1\x54\xa9\x36\x5e\x3a\xa7\xad\x56\x1e\x70\x46\x67\x74\x42\x03\xcd\xc0\x23\x30\xa4\x52\xca\xb7\x8d\x6f\x04\xdd\xcc\x4e\xbb\x80\x8f\xd3\x0d\x80\x43\xa5\x26\xf0\x4d\x51\x07\xbe\xde\x04\xf1\x27\xd0\xf6\x76\x68\xaf\x01\x2c\x91\x36\x00\x7b\x35\x78\xcf\xee\xed\xf0\x2c\x9f\x10\xb1\x2c\x14\x4e\x54\xb0\xe8\x7e\x47\x1b\xc5\x9e\x07\x1e\x6d\xd3\x03\x38\x5c\xa0\x16\x15\x9e\xa7\x20\xf0\xd2\xf0\xa6\xa1\xeb\xcb\x23\x00\xca\x5c\x50\x28\xf0\x2f\x50\xb3\x35\x47\xb3\xc9\x02\xb7\x1b\xde\xd2\x3d\xda\xa3\x3c\xb3\x62\x25\xa1\x35\xaa\x16\x6d\x3b\x8b\x90\x35\x14\x05\xdc\xc1\xae\xea\xf0\x8b\x3b\x1b\x6a\x5a\x8f\xcb\xec\xa2\x36\x94\x7f\x71\x69\x93\x3e\xa7\xa1\x27\xe7\x79\xca\xc3\xaf\x04\x57\xab\x1d\xcc\x38\xca\x98\x28\x34\x96\x4d\x8b\xd9\x59\x5b\x95\x6d\x54\xda\x94\x1c\xc7\xb6\xcc\x1f\xcf\xcb\xe2\x0a\xd0\xc4\xc6\x18\xd9\x9c\x5e\x05\xa8\x03\xc2\xd1\x14\x4e\x99\xc7\x7c\x05\xb3\x2d\xc2\xc0\xc9\x62\x13\x27\xbf\x24\xd7\x91\x36\x09\x21\x4a\xc5\x18\x2f\x6f\x9f\x1c\x0b\xeb\x3d\xfe\xb7\x2c\x73\xcb\xa0\x0a\x27\xd7\x70\x3b\x59\x64\xdb\x97\xbb\xdb\xc4\xbc\x5f\xd3\xcf\x43\xeb\xe2\x87\x4c\xff\x20\xfb\x8e\x61\x4c\x00\xf1\x97\xaa\x98\x1b\x25\x44\x9f\xe5\x68\x94\x56\xd5\x9e\x1e\x20\x56\x1a\x90\x51\x0c\xcf\x1f\xcb\xca\x34\x57\x49\xf1\x8d\x75\x70\xcf\x84\xe2\xe0\x0e\xe8\x88\xa1\x00\x13\xba\x95\xf5\x14\xc0\xd6\xf4\x04\xcf\x84\x51\x48\xff\x04\x84\x2d\xf9\x48\x02\xc2\xb1\x56\x7d\xf4\x7f\xa6\x64\x97\xff\xdd\x58\x7f\xf1\x1c\x94\x97\x8a\xda\x84\x17\xa9\x05\xa0\x04\xc3\x56\x74\xb2\x73\xba\xdf\x68\x81\x4e\xd1\x50\xf7\x45\x52\x4f\x63\x8c\x1b\x36\x27\x6c\x68\xc0\x63\xde\xb2\x07\x4e\x6d\x8f\x0e\x83\x07\x3b\xcd\x91\xde\x75\xcd\xb4\x3b\x20\x30\x60\x6b\x24\xf3\x72\x63\x74\x41\x10\x1e\x8c\xb3\x4b\x0c\xfc\xab\xaa\xc3\x77\x21\xe8\x41\x65\x1d\xd0\xcf\xa1\x38\x06\xbf\x0b\x8f\x0e\x40\x09\x2a\xe6\x93\xa3\x9f\xf8\xcb\x9d\x83\x6d\xf1\x21\x78\x9c\xd6\x20\x27\x40\x5d\x0a\x83\x7b\x1e\xe0\x88\x68\x5c\x17\x4f\xb3\x6b\x50\x61\xee\xf7\xbd\x75\x42\x75\x2f\x46\xb7\x35\x1e\x67\xf1\x15\x3a\x73\x09\xfa\x90\x8e\x46\x96\x5b\xa2\x50\x6c\x66\x3f\x80\xad\x0a\x15\x3d\x50\xf9\x93\xd1\x68\x49\x4e\x29\x04\x92\x9a\x10\x6c\x5a\x46\x33\xb2\x5c\x8e\x92\x25\x28\xd2\xcb\x39\x2c\x56\x1e\x01\xb1\x42\xc0\x33\x56\xc5\x07\xdb\x40\x96\xa3\xd0\xc1\xb7\xdf\xc6\x07\x37\x9a\x9f\xc9\xe6\xb0\xd7\x5c\xaa\xdd\x8c\x88\x9b\xac\x97\x0f\xb9\x8f\x9b\xb6\x84\x03\x5a\x58\xb4\x8a\xa7\x8d\x6e\xa4\x1c\x01\xe0\x5d\xfe\x5d\x8b\x9f\xee\x0f\xb7\xcf\xce\x50\x3e\x9f\x9d\x6d\xf3\xd5\xb1\x6a\xd9\xb6\xfa\x6f\xb7\xee\x6f\xb1\xe6\xbb\x89\x9c\x5c\x26\x59\x8e\x14\x0a\xd8\x84\x5a\xdd\xb1\x57\xbe\xbb\xe6\xf5\x3c\x23\xe5\x66\x8a\xac\x6a\xa1\xeb\xaa\x78\x29\x19\x91\xce\x49\x97\x86\xf0\xcf\x81\x6c\x10\xe7\xe9\x7c\x52\x4f\xe1\xdb\xbd\x7b\x1e\x6c\xcd\x1d\x15\x24\x86\x3a\xd5\x83\x2a\x16\xa1\xfc\x7e\x45\x7f\x47\x02\xd8\x49\x76\x3a\x08\xf4\xef\x7d\x8b\x63\xb6\x1c\xc0\x59\xfd\x48\x64\x4d\xd0\x00\x8c\x06\x94\x9c\x21\xab\xe8\xdc\x53\x71\x54\x2f\xde\x46\x05\xc9\x05\x5e\x9d\x24\x35\x5e\x66\x49\x3f\x72\x64\xb5\x64\x8a\x96\xc2\x45\xbe\x84\x53\xd0\x00\x6f\x93\xb3\xda\x84\x85\xb1\x38\xe5\x55\x06\xab\xeb\x1c\xb4\x91\xf7\x95\xd3\x4e\x4e\x75\x92\x67\xf5\x2a\xb6\x51\x6d\x5a\x0a\x8d\xa5\xd5\xb5\xb0\x3e\x7e\xbe\x6f\xa4\x5d\xe6\x86\xd3\x54\x8c\x14\xa5\x1e\x15\x73\xba\x94\x91\x17\xcf\x57\xe8\x6c\x34\x4a\xe6\x20\x97\x64\x2d\x10\x1b\x09\x5f\x24\x81\xbc\xa9\x0a\x19\xa7\x83\x70\xdc\xd4\x13\x7b\x54\xed\x3d\xde\xbc\x91\xab\x99\xec\x85\x0f\xab\x8b\xa4\xa4\xf0\xb8\xba\x78\x9f\x62\x07\xa8\x41\x49\x48\x02\x34\x48\xb1\x01\xb1\x17\x3b\x9c\x4b\x87\x22\x76\x07\x9f\x29\xb7\x11\x76\x42\xef\xd4\x2a\x1a\x23\xf4\x0a\x08\xca\xe4\xc2\x39\x5d\x60\x28\x62\x0f\x23\xd5\x82\xb1\xc3\x34\x2f\x31\xc7\x13\x80\x66\xc3\xee\xf3\xd1\x0e\x5f\x11\x49\xed\x11\xd6\x70\x59\x6b\xe5\x0e\x0f\x61\xdc\x3a\x26\x37\xfb\x68\x3b\x3a\x49\x86\xbf\x3d\x1c\xfe\xe3\xec\xf4\xdd\xd5\xe7\xfd\x77\xd5\xe7\xd1\xe1\x7f\xfb\x70\x07\xfe\x77\xf8\xe1\x10\xff\x0c\xa3\xe8\xeb\xbd\x93\x9f\xc3\x77\xef\x4e\x3f\xbc\x7b\x17\xf7\x3f\xef\xdf\xdd\x56\x36\xb4\x99\x63\x3a\xfa\x1d\xa9\x2b\x83\x16\x28\xe7\x49\x28\xa2\x06\xf6\x82\xd9\xc9\xee\xe9\x80\xc9\x8b\x7f\x3c\x38\xbd\x91\x36\x32\xbc\x60\x27\xe4\xab\x60\x96\xac\xe4\x75\x29\xe5\xb2\x00\x3d\xb9\x4c\x46\x18\x94\x36\x08\xaa\x02\x36\x96\x7c\xa5\x66\x3e\x28\x96\x35\x1a\x0f\x05\xdd\x67\xb1\x40\x2a\x12\x03\x94\x71\x0a\xdb\x80\xf6\xf6\x04\x8e\xad\xb0\x0d\x8b\x41\x87\xdb\x93\x3e\x5e\xb6\x9f\x9c\xf6\x85\x4c\x08\xfe\x4b\x70\x9f\xcc\x5c\xbb\x2d\x43\x9a\x17\x73\x1c\x8c\xc0\x3f\x0c\x15\xf6\xd8\xa5\xe8\x11\xe3\xa9\x9e\xe3\xc5\xc3\xab\x0b\x50\x94\x41\x20\x1e\x05\xbe\x92\x53\x7d\xb2\xdd\xb4\x93\xe6\xbc\xbd\x3b\x8f\xce\x57\x1f\xc4\x4d\xea\x87\x62\xfe\x21\x9b\xcc\x0b\x24\xe2\x07\xb2\x04\x9c\xe5\xe9\x45\x2d\x7e\x2d\x71\xbb\xc5\xb9\x7c\x17\x45\x27\xef\xae\xde\x55\x83\x53\x7b\x16\x91\x14\x3e\x44\x7f\x6f\x1d\xc2\x8d\x1e\x82\x87\xa5\x7e\xfe\x70\xf2\x7b\x34\x78\x57\x9d\xf6\x23\x64\x2a\xd1\x95\x1a\xed\x2c\xf8\xda\xe6\x94\x97\xb0\x7a\x42\x83\x37\xee\x9f\xde\x04\x7b\x1f\x43\x14\xec\xf9\xe7\x77\x57\x7b\xd0\xb1\x64\xeb\xbd\xd3\x13\xfc\xa0\xb0\xf0\xe0\xc0\x22\xe9\x56\x08\x34\x44\xd5\xf3\x3a\x9d\x55\x22\xf5\x0d\x2c\x74\xdc\x10\x75\x64\x50\xc6\x85\x3a\x6f\x8e\x76\x96\x19\xb1\x00\x88\x11\xda\x77\x24\x56\x50\x94\xb0\xff\x85\xf0\xfe\x42\x37\xbe\x8b\x14\x63\x62\xd0\x87\x7a\x44\xfe\xbf\xc2\xe4\xc6\xc1\xd8\x9b\x8b\x1b\xc6\xd2\x10\x36\xa3\xfa\x7a\x20\xf1\x6d\xd5\x4c\xf0\x13\x21\x60\xb6\x7c\x9f\xae\x06\xa8\x8f\x34\xac\x87\x86\x70\x43\x17\x8c\x13\xa8\x78\xaa\x37\x0a\xd1\x57\x47\xd5\x76\x83\xce\x47\x6a\x48\xf0\xe9\xe3\xb4\xa0\xc6\xf6\xe6\xc3\xd7\x54\x42\x58\xa4\x58\xdb\xfa\x2b\x94\x58\xb8\x2d\x1a\x8c\x20\xf2\xed\x88\x5d\x25\x43\xab\x69\x96\xe7\xca\xcb\x0f\xb7\x12\xc5\x16\xa6\xe6\xe5\xe0\x10\xd3\x56\x86\x32\x0b\x66\x91\xff\x90\xd6\x5a\xa3\x12\xef\x55\xb2\x16\xfd\x65\xab\x3e\x9b\xcf\x88\x56\xc3\xb4\x4a\x2c\x2f\x04\x2a\x60\x76\x60\x90\x48\xa2\xc2\x7d\x8c\xd0\x15\x5d\xae\xaf\xbd\xad\x46\x77\x5c\x82\xcb\xbf\x42\x0b\xd9\x28\xa9\xa3\x57\xe7\xbf\xc0\xce\x85\x2e\x25\x55\xd4\x99\x2a\xaa\x2f\x35\x2e\xd2\x71\xf6\x55\x6f\x5a\xa2\x70\x87\xc4\xb9\x11\x7f\xa6\x84\x5b\x96\xc2\x5c\x85\x1d\x50\x78\x07\x6b\x82\xd9\xc3\xfb\x62\x45\x4e\x17\xe4\x36\x96\xc2\xf1\xa9\x18\xa7\x3f\xbc\x79\x8e\x7a\x1f\xc5\xde\x47\x06\xfd\x51\x6d\x17\x0a\xba\xdb\xbd\x70\x33\xb3\x89\x75\x72\x6a\x98\x2b\x80\xab\x6c\x85\x52\x28\x88\xc2\xf1\x17\xbd\x46\xf4\x34\x52\x32\x18\x76\x6b\x20\xc1\xa2\x0d\xb7\xe2\x6f\x04\x97\x3b\x82\xc7\xd4\x6f\xa4\x6b\x7a\x23\x91\x17\xb3\x36\x69\x96\x86\xca\x84\xe0\x6c\xe6\x56\x8a\xda\x00\xc6\xb6\x90\xde\xd0\x94\x4e\xac\x4c\x7d\x69\xc2\x6a\x68\x15\xaf\x73\xf5\x33\x46\x6f\x08\x25\xa9\x59\x77\x7a\xfd\x19\x4c\x07\x45\xa2\xc9\xfe\x56\x63\x8d\xa3\x33\x5b\xd7\x16\xe4\x5b\x30\xd8\x46\x17\x5e\x2c\x7f\xfb\x6d\xf5\x86\xce\xb3\xca\x33\x8e\x0e\xb9\x7b\x94\xe9\x6e\x20\x36\x45\x2c\x37\xbf\xcc\x12\x74\xa9\xbd\xd1\x6b\x4b\x29\xe6\x4a\x87\x17\x42\x90\xe4\x09\x26\xdb\xca\x73\x21\xcb\x66\xd9\xfc\x3b\x52\x68\xf6\x82\x1d\x71\x33\x0a\x67\x77\x0c\x2d\x55\x44\x22\x0c\x1c\xa9\xef\x11\x71\xf2\x66\xa2\xa1\xac\xea\x83\x01\xcc\xb5\x70\x99\x41\xae\xb1\x19\x8b\xb6\x2d\x4b\x5f\x13\xbb\x17\x8c\x81\x02\x7e\x50\x31\x4d\xc7\x52\xc2\x75\xca\x37\xa6\x3f\x4a\xd7\x8e\x3a\x42\xca\xf8\x64\x20\x33\x28\xd5\x32\x2f\x15\x1a\xeb\xcb\x77\xf6\xbc\xf1\x53\x87\x76\x52\x17\x1b\x4d\x54\xb1\x6d\x0d\xb6\xc4\x84\xe2\xee\x6d\x9e\x9c\x70\xe2\x34\x22\xc8\xa9\x6c\xfc\x68\xd0\x9c\x07\x08\xf2\x62\x4f\x39\xfb\xb9\x03\xdb\xb7\xee\xe3\xae\x52\x4e\x09\xc5\x6c\x45\xc7\x15\x58\x8b\xe8\x15\x13\x88\x89\xaf\xde\x67\x0b\x97\xea\x06\xa7\xb2\x09\x9c\x8e\xf6\xf4\x5b\x63\x17\x6c\xd6\x15\x35\xf7\xdb\xeb\x01\x4b\xab\xa5\x61\x52\x15\x11\xb8\x43\xd4\x40\x49\xe0\x6b\x66\xcd\x17\xd9\xa7\x8d\x3c\x91\xed\xbc\x63\x4d\xb9\xb5\xdf\x61\xfb\xdf\xf9\xe2\x2b\x85\xb5\x8d\x7d\x63\xec\x4b\x81\xce\xd5\x3b\x37\xc6\x5e\x67\x5f\xf4\xca\xa6\x84\x9e\xd0\x37\xbd\x9d\x0e\x04\x48\xdb\xe6\xb4\x40\xf0\x3d\x69\xc6\xeb\xd9\xc6\xd7\x45\x51\xa1\x5b\x84\x32\xea\xf5\x8c\xd2\x9b\xbe\xcf\x0a\xa2\xb4\x45\x21\xa4\xcd\x73\x13\xfa\xad\x0e\x02\x76\xa1\x04\xf9\x9b\x56\xa3\x64\x61\x38\x5d\x4e\xe1\x34\x90\xe3\x89\x40\xb8\x17\x69\x1a\x96\xb8\x76\xb8\x3a\xc2\x10\x4c\x8a\x8c\x60\xb3\x2b\x91\x22\x96\x24\xc4\x63\xa8\xd3\xc6\x87\xb1\x6f\x6e\x4f\xb0\x36\x6a\x50\x25\x6a\xe3\x7c\x80\xdd\x33\x40\xab\x79\x29\x63\xfa\x05\x95\x72\x73\xea\x6f\x2c\x55\xb1\xab\x0b\x1c\x84\x6e\x29\xd7\x25\x45\x0a\xb9\xcb\xd2\x90\x88\xf4\x67\x4c\xe1\x47\xaa\x4a\x32\x38\x77\x39\x32\x93\xcb\xd6\xed\xfb\xfc\x94\xf1\x26\x73\xb2\xa7\x3c\x11\xe5\x96\xc6\xf8\xe4\x1a\x0e\xbc\x2a\x08\x9e\xdb\x67\xf3\x0b\xdc\xf4\xd2\xdc\x30\xd7\x88\x41\x67\xc4\xe4\x3b\x28\x1c\xb3\xea\x65\xf2\x32\xca\xf0\xa2\x3a\x89\xd9\x51\x07\xb7\x49\x10\xc4\x11\xe0\x0c\xac\xae\xe8\xe6\x28\xd9\x3c\x50\x9b\x36\x8a\x4d\x4c\x02\x05\x1e\xc1\x35\xad\x67\x68\xa7\xe8\xa1\x15\xfb\xa8\x27\x85\x94\x77\x1a\x62\x9e\xe2\x7d\xa3\xf5\xb8\x18\xb5\x09\x3d\x57\xaa\x77\x6a\x83\xdc\xc1\xbe\x21\x56\x00\xb2\x39\x51\x84\xe6\x3d\xc0\x33\x38\xa8\x66\xe8\xbb\xcd\x06\x77\x72\x6f\x1e\xce\x96\xb0\x15\x85\x84\xbd\xc1\xcb\x00\x21\x56\x91\x17\xa8\xb4\xe1\x0a\xc5\xb6\x47\xbd\x16\x0e\xbc\x1b\x71\x37\x58\x93\xc8\xe1\x6c\x04\x30\xbd\xec\x18\x50\xca\xcc\x08\x55\x43\x83\x32\xd5\x27\x75\x82\x64\x7b\x0e\x46\xfe\xa0\xa8\x64\x37\xf2\x80\x6f\xf1\x8c\x28\x9e\xf4\xca\x50\xa4\x98\x53\x44\x77\x9d\x5b\x4f\xaa\xdc\x54\x0d\x3b\x93\x2c\xe4\x48\x3f\x6d\x6a\x32\xcb\x68\x0f\xe7\x32\xdb\xe0\x24\x19\xde\x95\x8d\xd2\x00\x6b\x76\x4e\xbe\x73\x0d\x08\x66\x15\x61\x21\x43\xc4\xf7\x37\x52\x17\x0c\xe5\xdd\x64\x01\x03\x8e\x69\x1c\x22\xdb\xd0\x3b\xf8\x2f\xec\xeb\xcf\x21\x7e\xed\xc1\xb7\x5e\xdf\xde\x68\xd8\xde\x86\x76\xa9\x87\x75\x94\xe2\x41\x07\x65\x4b\x0f\xea\x59\xf2\x91\xfa\xba\x47\x05\x9e\xe3\x13\x6f\x2d\xdd\x83\xb8\x1d\xe7\x63\x35\x3f\x6a\x61\xd4\x24\xc2\x3d\xfa\xbc\x46\xc5\x79\xc4\x3c\x78\x28\xa6\xf9\x1e\x35\x75\x66\x50\xf0\xbd\xc7\x08\x49\x8d\xfa\xa2\x15\xfc\xe3\x56\x41\xfc\xec\xc5\x41\xc6\xee\xb7\xc4\x05\x5e\x1b\xb3\xc9\xa1\xc0\x2d\xf5\x5b\xc9\x30\x6f\xb4\x63\xba\x8b\xfd\xc0\x3f\x28\x35\xa7\x86\xaf\xce\x53\x4d\x53\x8f\x03\xaf\xd6\xc1\xf1\x46\x9e\x5d\xea\xe9\xb6\x9b\x5d\x79\x5b\x3d\x6c\x5a\x60\xbb\xce\xf5\x08\xf4\x3c\x5f\x96\xad\x70\xd0\x84\x60\x42\xc1\xcd\x4c\x41\x12\xa7\x42\xd9\xf2\x61\x2d\xa6\xce\x12\x0e\x86\x85\x5a\x89\xf3\xab\x69\x51\xc9\xa2\x12\x34\xf6\xc9\x92\xc2\x57\x9c\x43\x9c\x68\xe5\x9e\x00\x51\xb9\xc0\xeb\x07\x60\xe3\x64\xbe\xea\x3c\xb1\x35\x30\xbb\x9d\xbd\xdb\x27\x83\xf0\x3b\xe8\x4d\x7e\xd9\xb1\x25\xae\x54\xcc\x73\x28\x9e\x93\xed\x33\x4a\x6c\x18\xc5\x3d\x1c\x0c\xd0\x95\xc1\x98\x6d\x88\x77\xb7\xfb\x80\x95\x87\x99\xcd\xaa\x3f\x73\x5d\xaa\x2a\x2d\xac\xdd\x0b\x78\x76\xea\x98\x83\x67\x86\x65\xfc\x95\xba\xcb\x41\xff\x0b\x11\x71\x34\x4f\xcb\x19\xe8\x8c\x01\x40\x41\x9f\xd7\x71\xc0\x7e\xef\xa0\x30\xc0\x40\x1b\xdb\xc8\x80\x21\x49\x03\x31\x94\x14\x9c\x11\x8b\xb1\x57\x91\x47\xea\x5a\xa1\x8d\x18\x5a\x3a\x3a\x77\x02\x51\xf8\xe1\x6e\x9f\x24\x66\x28\x04\x26\xed\xed\xe9\xa2\x9e\x72\xe4\x9e\x73\x2d\x27\x4d\xca\x6c\x7a\x1f\x06\xbb\x78\x4d\x77\xc4\xd7\x75\xc3\xa1\xe9\x5a\x31\xd2\xb5\x85\x60\xcb\x0c\xf7\xb1\x11\x8b\xca\xbe\x21\xe1\xa8\x53\x8c\xdc\x73\x04\xad\xa8\x6a\x0a\x43\xd2\x12\x18\x47\x54\xa1\x5c\xb5\x8e\x96\x85\xea\xdd\xa2\x46\xd6\xd7\xd7\x27\x6c\x5d\x7e\x57\x11\x77\xec\x9e\xda\xbb\x45\xf7\xc4\x63\x0f\xa7\x8e\x5e\xcd\x1c\x80\x25\x2d\x7a\xff\xb7\x68\xd3\xe7\x88\xf2\x20\x99\x4c\xca\x74\xc2\x61\xcc\x8a\x05\x28\x29\x34\x9c\x05\xb2\x39\x2c\x1a\xa5\x41\xa4\xc4\x2a\x6c\x7b\x49\xab\x78\xab\xe5\x90\x43\xf4\x18\x0e\xed\xad\xe1\x46\xb0\xa3\x44\x8e\x14\xe9\x35\x97\xe1\x0d\xc1\xd7\x5c\xf3\xb4\xd4\x5d\xc1\x46\x1f\x5d\x79\x41\x21\x09\x42\xca\x39\x0d\xe8\x6f\x91\xfe\x08\x24\x31\xca\x2f\x2b\xca\x1c\x29\x69\xd8\x26\x92\x73\x7c\xaa\xa0\xe5\x86\x10\x49\x8a\x4e\x72\x15\x19\xa1\x2e\x98\x03\xf0\x5a\x16\x06\xdc\x29\xdf\x5c\x9c\x8c\xa1\x22\x8c\x56\x7b\x14\xb2\x08\xb3\xd9\xa1\xd0\xc0\x60\x0c\x12\x90\x2d\x14\x2c\x87\x72\xa3\x16\x9e\x86\x05\xb3\x58\xd0\x68\x83\x68\x78\x95\x2d\x8a\x05\x86\xa1\x47\x21\x0c\x12\xce\x9a\xca\x2d\xd9\xee\x43\x2b\xe6\x1b\xf0\xaf\xbd\x91\xf9\x3b\xc0\x20\x53\xfe\x1b\x19\x90\x3d\xc1\x44\x2e\x13\xae\xcf\x96\x22\xbe\xee\xc6\xe4\xe4\x17\xc9\x98\xee\xf7\x02\x72\xdf\xcd\x6a\xa1\xcf\x56\xec\x3a\x05\x05\x1d\xee\xcb\x26\x71\xe8\xdc\xa7\x66\x60\x9d\x9f\xa8\x44\x5e\xd6\x85\xf5\x3e\x99\xa0\xf2\x1c\xce\x30\x54\x3c\x57\xf7\x05\x0b\x1d\xcc\x10\xd6\xc5\x42\x7d\x57\xc1\x55\xe8\x84\x57\x8c\x57\xaa\x40\x06\xcc\xee\xf1\x39\x49\x1e\x3e\xe4\xe8\xe5\x60\xce\x00\xf7\x30\x28\x8b\x3c\x85\x83\x49\x51\xe4\x75\xb6\x08\x8f\xcc\x06\x49\x59\x16\x57\xf0\x89\x4e\x17\x07\xd3\x07\x0e\xa0\x61\x9d\xd5\x79\x8a\xe5\xd3\x07\x47\x9e\x8e\x28\xa4\x09\xd0\x56\x10\xf8\x94\xa2\xb0\xc4\xd6\x7b\x81\x75\xee\x31\x87\x46\x03\xc6\x52\xf8\xbf\xb8\x01\xd0\x13\xae\x63\xbd\x6e\x06\xc1\xfd\x9d\x1d\xbd\x62\x2d\x2f\x63\xd8\xc6\xf0\x8e\x8c\x52\x44\x89\x2f\xe8\xe2\xac\x05\x55\x99\x8e\xa0\x1f\xe0\x86\xf4\x3a\x1d\xe1\xb9\xcc\x5e\xa9\xdd\xa6\x61\xbb\xab\x8d\x03\xc1\x4d\x4f\x67\x7a\x6e\x21\x52\x97\xa3\x77\x5a\x03\xb2\xc9\xe6\xf5\xcc\xf5\x55\x6e\xc0\x13\x71\xc5\xbd\x83\x3c\x93\xb3\x21\xfd\xb2\x87\x68\xca\x4d\xcb\xf0\x08\x35\x15\x36\xa5\x49\x9a\x80\xd4\x19\x67\x15\x7a\x4c\x8c\xd5\xc5\x90\xec\xfd\x60\x3b\xcf\xd4\xd1\xb2\xb9\x72\x91\x56\xf6\xab\x34\xca\x67\x1a\xf1\x56\x23\xa3\x7a\x72\x0f\xb6\x76\xc0\x8f\x1a\xc3\xcb\xc2\x9c\x25\x35\x7b\xc1\x2a\xad\xbb\x30\x66\x2c\x60\x8b\x7d\x92\xc0\x7e\xaa\xbd\xca\xe1\xb3\xa9\x07\x24\xe4\x3d\xde\x3b\x48\x82\x69\x99\x5e\x1c\x62\xc8\x9e\x40\x44\x3a\xc0\xe3\x31\x08\xf9\x3a\x81\x8e\x62\xb2\x53\x13\x0c\x11\xd0\xc7\x0b\x63\x10\xf0\x37\xc3\x0f\x5c\x7c\xda\xef\x1c\xf8\xdd\x28\x84\xa1\x1f\xd1\x30\xe0\x94\x28\xbe\x26\xfd\x2e\x8f\x79\x93\x66\xbc\xa2\x55\x3a\x49\x85\x3b\xac\xbe\x8c\x88\x67\x10\xa8\x0b\xd0\x91\x67\xfc\xb6\xcf\xfc\x11\x45\x46\x49\x36\x42\x6a\x28\xd0\x6b\x36\x6c\xe0\x8d\x57\x2a\x8a\x7f\xfd\xba\x71\xa2\xfe\x75\x58\x10\x7f\xa4\x28\x60\xf9\xe0\x4d\x80\x79\xad\x54\xb4\xaf\x10\x8a\xa1\x0a\x96\x92\x1f\xf0\xa1\x1c\xf7\x0b\xc5\x5e\xa0\x13\xe0\x69\xbb\xf3\x37\x37\xe9\xc7\xa9\xc5\x41\x14\x01\x3a\x90\x21\xf9\x26\x2f\x09\xc5\x4e\x94\xc4\x5a\xd7\xa2\x70\xd0\x38\x9b\x3f\x2c\xcb\x64\x45\x5b\xe9\xc0\x1a\x4e\x9f\x74\x54\x5b\x81\x94\x50\x58\x67\x16\xab\xe9\xc8\xd6\x26\x05\x9d\x78\xbf\x34\x7a\xb6\x0c\x2a\x37\xd6\x91\x53\x35\x92\x91\xf9\x8e\x5f\xb3\x59\x83\x23\x60\xdd\xa0\x58\xc3\xd9\x41\x3d\xed\xb4\xce\x81\x31\x29\xab\xf4\x31\xfa\x6d\x3a\xf7\x6a\x34\x7b\x18\x26\xae\xd9\x81\x3e\xbd\x79\x22\x5c\x9b\xdf\xa4\x93\x27\xd7\x8b\x28\xfc\x39\x3a\xd9\x19\x7e\x75\x7a\xaf\x1f\x9d\xac\xae\xc6\xd3\x59\x05\xbf\xde\x0d\xd5\x89\x40\x5a\x35\x0f\x03\x05\x51\x28\xd2\x02\x9c\x16\xbb\xa2\x2a\x47\xa9\x93\xc0\x50\xde\x36\xa2\x48\x12\xfb\xce\x61\xf0\xc0\x39\x40\x7d\xb9\x63\x0a\x44\x79\x7c\xa4\xe1\x3d\x9f\xd7\x12\x00\x68\xeb\x0a\xb3\xe5\x3c\x43\xdb\x90\x2c\xb9\x7f\x6a\x90\x8f\xdb\x7f\x1e\x74\xbd\x35\x75\x82\x00\x4e\xd7\x52\xd8\x8a\x98\xd8\x78\x9d\x11\x71\xde\x0a\x1f\x5d\x31\xd3\xd6\x5c\x45\x4e\xf8\xbd\x11\xc6\xeb\x73\x87\xec\x78\xa2\xca\xe7\x22\x49\x77\x03\x26\x0a\x07\x3e\x14\x3a\x80\x92\x7b\xa4\x6d\xc7\x71\x70\x5d\xd3\xb8\x61\xf6\x6b\x3a\xf8\x77\xc5\xc6\xb8\x76\x5a\xc3\xba\xb3\x6e\xc2\xac\x28\x94\x7f\xfd\x84\xad\x9f\xa9\xd6\xd3\x73\x63\xd6\x8e\xfe\xff\x99\x35\xd8\xcb\x9e\xa8\x10\xe8\xf5\x53\x46\x02\xc7\x0a\x9c\xfe\xf0\x21\xb0\x3e\xd8\x58\x4b\x33\x4d\x31\xa3\x64\x03\xfb\xf6\xf1\x78\xe3\xd0\xe1\xcd\xf6\xe4\xf2\xed\xed\x06\x43\x87\x37\xae\xcc\x11\x65\xaa\xb9\x11\x49\x53\xe9\x8f\x58\xd7\xf4\x8c\x1b\xd3\x6b\x85\x6b\x10\xab\xbc\x38\x11\xa8\x4e\x45\x7b\x13\xb2\x08\x84\x36\x94\xa4\x4f\xe6\xe3\x8d\xc9\x02\x3b\x95\x40\x59\x4c\x9d\x24\x90\x49\x64\xb1\x0c\x45\x5d\x72\xfe\xde\x78\xfd\x06\xdb\xc1\xfd\x41\xd0\x13\xe1\x14\x3d\x2f\xbd\x05\x60\xa3\xcc\x66\xfd\x0d\x05\xd2\x3f\x7b\xdc\x80\x55\x8d\xae\xb7\xff\x57\x0d\xde\xa8\xbd\xf9\xe1\x8e\x14\x64\xf6\x4a\xef\xdb\x1f\x45\x34\x4d\x65\x9c\xf6\x5a\xe4\x94\x79\x66\x71\xc3\x2a\xf1\xe0\x1b\x79\x32\x36\xe8\x93\xe4\x96\x71\x5d\xd6\x11\x0f\xf6\x47\xec\x1e\x22\xe9\x10\xbe\x55\xc8\x3a\x9c\x52\x7a\xd6\x67\xc5\x91\xda\x37\x06\x38\x8a\xd1\x83\x1c\xa4\xf8\x28\x7c\x86\x8d\x7e\xe1\x87\x5d\x2c\x0c\x61\xda\xbf\xd8\xe9\x0f\x82\x5d\x85\x80\x4e\x3f\xd1\x90\x40\x2a\xe6\xcd\x0c\xd7\x23\xac\xfe\x36\x2d\xad\xf3\xa7\xfc\x18\x27\xe7\x78\x01\xdf\x37\x35\xba\x65\x99\xcb\xbe\x84\xeb\xa6\xba\x12\x80\x13\xd6\x4c\xe7\x21\xe3\x43\x7a\xb8\xe7\xaa\xcf\x32\x5c\xbe\x35\x89\x9a\xd2\xdf\x19\x60\xac\xae\x3a\xc5\xd0\x86\xd6\x2c\xed\x9b\x55\xc5\x8d\x26\x57\xdc\xb7\x81\xa4\xe8\x06\xa3\xe7\x87\x4b\x61\x34\xb8\xd5\x77\xc4\xf4\x71\xb4\xac\xb8\xb0\x13\x23\x36\x17\x80\x27\x66\xc7\x0c\x6c\xa6\x65\xf4\x26\xad\x16\x30\xc2\xb4\x59\x79\x9f\x69\x61\xd9\x82\x05\xc6\x35\x73\xab\xe6\x5c\x33\x64\x71\x3d\xde\x1f\x8d\xf1\x23\x0e\x97\x5e\x8f\xb3\x0a\x0d\x95\xf3\xce\xbf\x38\x87\x45\x60\x23\xb4\x1f\xf8\xfd\x87\x9d\x85\xc1\xf6\x02\x2e\x0c\xfb\x96\x5f\xf1\x06\x9e\xc4\xf8\x7d\x4f\x20\xf1\xaf\x8e\xb1\xa2\x56\x14\xa3\xb3\x61\x2c\x95\xc7\x42\x84\xdb\x9d\x98\x7b\xf6\xfe\xda\x6f\x22\x26\xb3\xf3\xb4\x20\xa3\xa4\xaa\x5d\xd9\xeb\x14\xc4\xc3\x8a\x94\x07\xb5\x3d\xc7\xeb\xe2\x8f\xae\xa7\x25\x25\x98\x5e\xb8\xf4\xc3\x6f\x78\x2e\x0c\x49\x76\x38\x54\x23\x09\x55\x96\x26\x89\xb0\x0d\x00\xc3\x68\x51\xe2\x37\x4a\x2b\x71\xc7\x97\x4b\xd1\x88\x31\x04\x8e\x72\xdb\x30\xf5\x4d\xc8\x1e\x2f\x32\xb3\x31\xcf\x31\x9e\x83\xad\x46\x6b\xe3\xe8\xd8\xba\x86\xc6\x74\xe1\x4f\x4a\x3e\xc7\x65\xe9\xf3\xd5\xd6\x16\x5c\xf6\xc3\xdc\x98\x80\x87\x2d\x04\xec\x0e\xce\x1b\x6b\xfb\x81\x37\xe8\x79\xa8\xa5\xc9\xbe\xd5\x10\xf6\xf8\x24\xc7\xcf\x6f\x39\x5d\x88\xb8\x38\x6c\x9f\x21\xf6\xb9\xef\x98\xa6\xd6\x46\x22\x2c\x18\x17\x30\x5f\x0b\x72\x8a\xd3\x46\xd8\x72\x13\xa5\xdd\xb5\x93\xdb\x6c\xd3\x85\x82\xe3\x4f\xe1\xc4\xb4\x3a\xb1\x79\x4a\xb3\x40\x2f\xa3\x28\xfc\xae\x48\xc6\x22\xf1\x2f\x4e\xbf\x22\x3c\x48\x61\x10\x85\x07\xe7\x65\xb0\x7d\x14\xbc\x51\x9b\x0d\xd7\x32\x94\x03\xa8\x27\xab\x61\x49\x78\x8c\x98\x9b\xd9\xdb\xb9\x85\x33\xa0\xbe\xe3\xe6\xe9\x66\x97\xd0\xa8\x6f\x10\x32\xab\x18\xdb\xf2\xe2\xae\x26\x6b\x4e\x11\xd8\x82\x6d\xbc\x58\xd7\xf9\x2e\xf5\xb1\x75\xa1\xfd\x4a\x2d\xfc\xd8\xbe\x7b\x3d\xb7\x6b\x49\x83\x0d\x46\xfd\x93\xce\x80\xa6\x3a\xb7\x05\x6b\x1b\x06\xb2\x96\x79\x53\xa1\xbe\x35\x0c\xe9\x57\x96\x52\xa5\xea\x19\x86\x6d\x0a\xb3\x16\x26\xf3\x50\xa0\xc5\x33\x7f\xd5\x30\x70\xab\xf6\xb7\x21\xb2\x6f\xa8\xb7\x1f\xa2\x5d\xb0\x21\xa5\xad\x64\x58\x1b\xf4\x6f\xaa\x84\x35\xdf\x34\x3e\x7f\x2c\x29\x78\x05\x2a\x73\x71\xc5\x63\x92\xd7\x90\x4e\x4d\x75\x42\xc8\x9c\x04\x90\x3e\xfd\xdd\xc9\xe8\xa5\x95\x78\x3a\x89\x48\x08\xb6\x05\x54\xa5\x52\x94\x5d\x42\x07\x02\xaf\xae\xcb\xd1\x36\x1b\x8b\x37\x63\x18\x5f\xe1\xa9\x11\x7c\x1e\xbc\x7d\xf2\xe8\xd5\xcb\xc7\xeb\xa9\xcd\xb7\x6f\xdf\xf1\x13\x5a\x06\xb1\x39\xd2\x46\x93\x9c\xfe\x96\x21\x9f\x87\x22\x64\x4a\xd9\xbd\x38\xfa\x24\x9b\x07\x66\x33\x26\x0a\x17\xe1\xc6\x2e\xe3\xdc\x0d\x91\x6d\x42\x8d\x17\x4b\x18\x4a\x28\xfd\xad\x91\x99\xb9\x2d\x88\x39\xe5\x66\x2d\x76\x4d\xed\xf0\xc9\x1d\x9e\xd0\x3f\x2a\xa0\xdb\x71\x7e\xc8\xe5\xe8\xec\xb4\x0d\xfc\xf9\x5d\xa8\xbb\x92\x98\xd0\xeb\x66\x21\x88\x59\x58\x64\xdc\x3d\x2d\xb8\xb5\xc4\x64\xff\xbf\xe3\xe2\xb8\x7a\xc9\xf7\x15\xad\xe4\xac\x65\x0d\x51\x12\x4b\xe2\xe0\xf1\x0d\x84\x14\xf6\xfa\x7b\xb8\xdf\x45\xfc\xb5\xd4\x5f\x4f\x7e\x0f\xfd\x15\xc9\x81\x40\x8a\x2e\x92\xbe\xf8\x1d\x3e\xcb\x1d\x83\xf6\x3a\xfc\x21\x46\x73\xef\xd0\x47\xc6\x01\xd3\xf0\x26\x34\x0c\x56\xdc\x60\xb3\xcb\x8d\x1f\xc5\x55\x80\xa2\x25\xd9\xf6\x35\x29\x79\xc5\x52\xd5\xa7\xf8\xc0\xa7\x28\x97\x8b\x92\x3d\xac\x71\x51\x1a\xf9\x8d\xc3\x7b\xcf\xe7\x17\x18\x58\x36\x14\xff\xd2\xdf\xb0\x2a\xf3\x1c\x03\xd5\x09\x18\xa5\xec\x2f\x02\x68\x8d\xef\x52\x18\xf0\xfb\x31\xba\xa9\x49\x50\xa3\x64\xde\xab\xb1\x11\x85\x77\x92\x03\x7c\xa1\xa2\x07\x67\x18\xe8\x3f\x49\x16\x55\xc0\xde\x7f\xb1\x69\x8b\x94\xce\xed\x37\xd6\xb5\xc5\x5a\xa2\x58\xd9\xd1\xdc\xf3\x59\xa7\x4d\x69\x91\x80\x2e\x59\x4b\x53\xc6\x9b\x6c\xf4\xbe\x9a\x26\x57\xf1\xa3\x22\x87\x7d\xf0\x35\x17\x6a\xbb\x0a\x29\xf8\x86\xd2\x85\x3c\x34\x4b\x60\x6a\xaf\xc3\x86\x9b\xa3\x50\x74\x45\xdc\x14\xba\xdf\x14\xe2\x81\x76\xac\x4f\x79\x0c\xee\x04\xaf\x73\x7a\xe6\x26\xa5\xdc\xd2\x09\xe8\xb6\x65\x99\x8e\x6a\xd3\xfd\xd9\x76\x6d\x11\x7c\x7e\xa3\x0d\xa4\x89\x8c\xdc\x2c\x95\x97\xba\x96\x9b\x75\xe5\x5e\x18\xea\xc4\x3e\xcc\xc5\xfa\xc6\x10\xf4\x31\x11\x61\xe7\xfa\xdd\x04\xf2\xaa\x51\xea\x97\xfb\xa6\xa8\xaa\x8c\x88\x18\x47\x93\x94\x37\x94\x5a\x34\x19\x91\x6b\x52\x24\xe8\x8e\x75\xee\x09\x05\x58\x95\x99\x9e\x2e\x32\xda\xdc\xe8\x65\x8f\x7e\x0e\xac\xe6\x7b\xe2\x5f\xfb\x4c\x0b\x10\x39\xd8\xc4\xa6\x94\xb1\x80\x2c\x0f\x36\x53\x29\xbe\xde\xe3\x3b\xb4\x93\x9d\x53\x33\xf0\x65\xb5\x67\xec\x8d\xb4\x32\x19\x1a\xde\xcb\x69\x1d\x58\x07\x2d\xe8\x83\x4c\x8e\xc7\x40\xc1\x81\x31\xfd\x19\xf5\x75\xfa\x6b\xd6\x57\x48\xc9\x6e\xa8\x43\x95\xb1\x70\x39\x4f\x0b\xcd\x58\xa5\x1e\xa6\x9b\x65\x15\x3f\x48\x55\xa7\x8b\x4a\x27\x00\x07\x26\x57\xfa\xbc\x0c\xf1\xd5\x2e\xa8\xe2\xa0\xa2\x84\x68\x6d\x6c\xfb\xca\x7a\xb4\x0f\x9f\x0f\xec\xef\xb0\x5f\xe2\xd7\x7b\x6e\xed\x74\x61\xa5\x59\x7c\x98\xe7\x20\x02\x10\x3a\xbd\x3a\x8c\xe8\xd1\xd3\x71\x20\x54\x38\xcf\xce\x68\x65\xc5\xd1\xf1\x01\x43\xdd\x45\x23\x8e\x18\x15\x47\x9f\x4f\xe0\xaf\xd3\xf8\x3a\x38\xc0\x7e\x1b\xdd\xb2\x7d\xc7\x9c\x4e\x35\x70\x16\xe9\x06\x10\xe3\x20\x00\x7f\x4a\x97\x4b\xcf\xa9\xc8\x01\xf1\x3b\xb0\x43\x3d\x08\x44\x10\xe4\x4d\xbf\x79\x01\x1e\x08\x5c\x59\xb8\x70\x5b\x3d\xb1\xfa\x9e\x22\xd9\x50\xff\xfb\x56\xe4\xff\xdd\xec\x26\x48\xa5\x90\x74\x7d\x63\x6c\x35\x8c\x52\x3a\xa3\x23\x1b\x25\xaa\x40\x5b\xb9\x78\xdc\x69\x0e\x52\x28\xe3\xf7\x00\x48\x8c\xc7\x76\xa6\x60\xd7\xc1\xc8\x49\x33\x3c\x9a\x66\xf9\x18\x14\xa9\xa8\xef\x71\x26\xd0\x75\x9d\x74\x6a\x3a\x71\xb1\x55\x70\xe3\x66\x40\x46\x17\x1a\xc3\x2d\x8c\x53\x1f\x1f\x99\xc1\x2a\x56\x0a\x64\xa7\xba\xc8\x7d\xdc\xac\xaf\xd1\x6f\xbc\xa2\xb0\xae\x12\x75\xa5\x6d\xe4\xf0\x5d\x58\xc8\x5b\x4d\xc7\x48\xf9\x47\xc5\xfc\x12\xd7\x2e\xec\xa9\x3f\xbc\x7c\xfe\x37\xfd\x3c\x8c\xf4\x65\x36\xac\x10\x9b\x5f\x60\x80\xba\xf4\xe0\x4b\xd1\xc3\xee\x54\x86\x62\xc7\x1e\xf3\xbd\x44\x73\xa8\x3a\x52\xc3\x5c\x2f\x77\x5e\x27\xe3\x31\x3f\x2f\xab\x1f\x90\xcd\xe6\x97\x59\x95\x61\x82\x9c\x10\x57\x45\xa8\x92\x3f\x88\xe4\x36\xc5\xfc\x22\x9b\x2c\x31\xdc\xee\x7a\x88\x93\x10\x9c\xe3\xfb\x62\x09\x01\x48\xe7\x15\x94\x54\x12\x3c\x3d\xc9\x35\xe1\x67\x53\x30\x08\x77\x9c\x55\x8b\x3c\x59\x89\x10\x40\x74\x82\xc7\x64\x60\x12\x8e\x7c\x8e\x5b\x27\x1f\x9f\xc3\xf4\x90\x9b\x38\x07\x7e\xab\xd4\x3d\x0a\xbe\x7e\x81\x4d\x78\xa6\xe9\x04\xac\x5a\xfc\x60\x90\xc6\x35\xde\x39\x4b\xaa\x19\x57\xc9\x4c\xa3\xe5\x9c\x1e\x75\x20\x79\xa0\x6a\x35\xe4\xc2\x8d\x0b\xd7\x96\x6e\xc3\x60\x97\xa5\x99\x98\x91\x46\x2f\x4a\xe4\x88\x0a\xde\x0e\x74\x96\xf6\x97\x20\x68\xf1\x82\xad\x4e\x55\x48\x95\xbd\x88\x4b\xa1\xe9\x48\x99\x62\x69\x3f\x9c\xef\x55\xa4\x43\xcd\x85\x87\xa8\x66\x7e\xb5\xff\xf1\x6b\x0e\x7b\xfa\x6e\xc5\x58\xd8\x64\x4d\xe1\xc7\x1d\x30\xa5\x23\x8a\xe3\x81\x38\x7e\x8e\x31\xac\xbb\xb5\xcd\x4f\x58\x4e\x06\xb6\xbf\xec\xb0\x07\xa6\x0c\x30\xa7\x68\x4a\xd8\x31\x7d\x29\x6d\xd9\xc7\x2a\x0c\x30\x17\x4b\x36\x4f\xa5\xc5\x9b\x4e\x7f\x8b\x82\x9f\xc8\x12\x65\xa0\xc0\x88\x90\x72\x61\x1d\x52\xfc\xae\x62\xcf\x31\x16\x7d\x59\x17\xe1\xc0\x22\xea\x53\x8c\x5c\xc0\x10\x71\x7c\x43\x83\x30\xee\x61\xc8\xea\xf5\x36\xb4\xd8\x6a\x49\x3c\x8c\x42\x17\x33\x79\x1b\xeb\xe6\xa7\x69\x3a\x97\x19\x86\x51\x2f\xe4\x47\x09\xc6\x6a\x2f\x06\x88\x7a\x2f\xee\x58\x8b\xb5\xb6\x65\x59\x71\x70\x18\x27\xcd\xdf\x5f\x98\x90\x38\x8f\xb8\xd8\xc1\xfc\x10\xf1\xeb\x6b\xdc\x91\x5d\x3b\xaa\x2a\x88\x57\xb0\x16\xec\x0e\x60\x4b\x36\x8b\xef\xb8\xba\x23\xa9\x3a\x0e\x4a\x46\x03\x8f\xa5\x57\x6d\xa5\x48\x09\x50\x2a\xac\xd6\xfb\x5b\x66\x9d\x26\x2f\xc7\x4c\x3e\xf8\xf9\xf9\x6e\xbc\xf3\x45\x7b\xb5\x6c\x2e\x69\x63\xed\xf4\x34\x03\x54\xf6\x9c\xa3\x55\x57\xfb\xce\xcc\x0c\xed\x82\x5b\xce\xd0\x1f\x33\x09\x07\x84\xe3\x26\xa4\xe7\xb1\x74\x12\xdc\x37\xc7\xb3\x0d\x67\x76\xb6\xf9\x7c\xde\x18\x11\x2f\x84\xd5\x21\x4d\x93\xeb\x9b\xe3\x9f\x4c\x50\xf2\x76\xf7\x3b\xea\xd1\x28\xf1\xe7\x50\xd6\xf3\x65\x38\x6f\x07\x1e\xed\xc4\xbb\x9f\x47\x2a\x89\x26\x7e\x1c\x22\xbc\x7e\xbf\xbf\x61\xb7\x6b\x21\xdc\x48\xa3\x1a\xb2\xd2\xb5\x50\x4d\x9a\x72\x37\x26\xf5\x87\x6e\x19\x7e\x67\x29\xb3\xe7\x13\xd9\x46\xaa\xdb\xd5\x1a\x58\x7f\x17\xa2\xbc\x15\x18\xcb\xbd\xa2\xcc\x64\xd4\x09\x4a\xca\xf4\x42\xfa\xaf\xd6\x50\xf7\xa9\x78\x1e\x81\xe3\x5a\xe8\x8f\x7f\x7f\xf1\xcd\xf1\xc0\xb3\x47\x10\x3a\x62\x8f\x30\xc3\x0e\x6d\xd2\x89\x27\xad\xf4\x28\xa6\xe8\x7f\xff\x38\xad\x61\x9b\xf6\x8f\xe5\x99\xae\xb0\xd9\x80\x18\x4d\x3b\x6c\x58\xbe\xe7\x7a\x0d\x1b\xa8\x2d\x36\x85\xb3\x51\xef\xa0\x5a\x80\xee\x2b\x3d\x9b\xe1\x23\x07\x54\xab\x4b\xa0\xeb\xe0\x73\x52\xe0\xfa\x71\x5d\xfc\x70\xfc\x88\x0d\x3b\x91\x8c\xab\x86\xb6\x3a\xac\x9a\xd4\xad\xab\x84\x53\x4e\x39\x80\x69\x1c\x67\x5c\x1a\x72\x06\xf6\xc3\x10\x5f\x52\x99\xd0\x93\xab\x43\x71\x3a\xe4\x48\x74\x12\x17\xf4\x05\xbb\x41\xcd\xb5\xd9\x91\x88\x6c\x40\x4d\x91\xbb\xbc\x17\x88\xd1\xc6\x3e\x7b\x1a\x29\x66\x6c\x54\xdb\x0b\x4c\x03\xe3\x4a\x8c\x44\xe4\x70\x70\x42\x64\x89\x4a\x58\xe1\xbc\x24\xb2\xc8\x5e\x8d\x4f\x46\x4a\x76\xb6\xa1\xda\x68\x34\xf5\x15\xb2\x46\xc8\xe7\x47\x3c\x13\xff\x1d\x95\x79\xf5\x11\x6e\xa6\x14\x92\x4e\x86\x30\x7a\x33\xb2\x02\xf8\xbb\xfc\x26\x9d\x26\x97\x59\x51\xc6\x42\x54\x3f\x93\x0d\xa2\x60\x23\xd6\x63\xbc\xf6\xc4\xbf\x76\xe7\xd5\x34\xcd\x2f\x51\x33\xdd\xa8\xe7\x63\xd2\x0e\xa2\x4f\xea\xd5\x9b\x1c\x7e\xad\x11\x1c\xdf\xd4\xfa\x88\x23\xa7\x2d\xa6\xee\xf8\x62\xb8\x6c\x49\xa0\x0e\x05\xca\xa5\xe1\x63\x55\xc4\x0e\xad\x40\x8b\x9b\x0d\xfc\x2e\x3d\xee\x26\x6b\x9c\x3e\xfc\x34\xc1\xb3\xb5\xc0\x42\x3c\x16\x51\x05\x8b\x84\xde\x8e\x33\xdf\x92\x40\x8b\x88\xd4\x07\xf9\xc0\x43\x06\x53\xe3\x01\x89\x2a\xb9\x4c\xb7\xc4\xa9\xc8\x78\x36\xe2\xe1\x5f\x1f\xfe\x2d\x90\x57\xb2\x78\x8a\xa1\xd7\x79\xf9\xc5\x89\xa1\xb2\x89\x52\x14\x20\x9a\x6d\x8d\x3e\x19\xd8\x15\x6a\xa2\x08\x71\x89\x09\x91\x45\xa0\x8e\x78\xe3\x13\xf1\x31\x5f\x6e\x52\xaf\x4d\x08\x7b\xa3\x75\x50\xf4\xbf\x52\x41\xc6\xd7\xb5\xe6\x08\xaf\xd5\xf4\x65\x41\x68\x92\x79\x08\x8d\x5a\x20\x11\x63\x7f\x90\x9f\xfd\xdc\x80\xf5\xc8\x88\xf9\xe2\x80\xef\x35\x8b\x8d\xb8\xc0\x71\xe1\x71\xfc\x44\x93\x8d\xf8\xc0\x7d\x3a\xa1\x1b\x4b\x93\xd2\x6c\x0f\x97\x17\x24\xdf\x14\x63\x23\xb7\x92\x02\x67\xbf\xa5\x76\x46\x29\x9b\x83\x9a\x02\xf7\x08\x2a\xb5\xb3\xdc\xf8\x38\xcd\x5d\xe4\xf8\x10\x98\xc9\xee\xf8\xf5\x54\x91\x34\x4e\xaa\x87\xf6\x65\xbf\x1b\x6e\xcc\xdd\x08\xa3\x48\x78\x50\x97\x47\x07\x35\xbe\xbe\x99\xe3\x5e\x75\xd8\xbb\xdf\x3b\x3a\xc8\x8e\xe6\x3c\xb1\x07\xdb\x18\x86\x54\x8f\xf1\x07\xde\x28\xb5\x67\x4b\xf4\x39\xfe\x7b\x1c\x0f\xec\x5c\xc8\x34\x07\x42\x2f\x95\xb9\xd3\x33\x2b\x1f\x88\xba\x6c\xf2\x59\xa4\x95\x41\x7a\xbf\x6b\x68\x47\xce\xb5\x1b\x83\x14\x97\x63\x38\x34\x51\x45\x18\x9c\x4f\x76\x4f\x75\x91\x39\xea\x1b\x5f\xe2\x3e\x71\xab\xf0\xff\x30\xfd\x2f\x3f\x9e\xfe\x97\x2e\xfd\x55\xf8\xcb\x31\xe7\x3e\x09\xd5\x15\x84\x42\xef\x17\x46\xef\x17\x40\xef\x52\x5a\xf8\x25\x6e\xbf\xd8\x79\xb2\x35\x24\x38\x5c\xca\xca\x27\xbf\x9c\x8a\x19\x0a\xfe\x2b\xce\x9a\xf9\x7d\x87\x67\xee\xbc\xdc\x3e\x6a\x64\x1b\xf9\x24\xd6\x30\x30\xd9\x98\x33\xc4\x1d\x0c\x73\x86\xbf\x77\xae\x62\xf5\x64\xce\x44\x1b\x23\xba\x1d\x91\x66\xdb\xdd\x11\x55\xb1\x3a\x32\x13\x0f\x59\x7d\xf6\xd7\x74\x6a\x65\x8c\x74\xf7\x83\x1f\xe6\xd5\x72\xb1\xc0\x3c\x57\x22\x9f\x2c\xdf\x9f\x35\x80\xdc\xac\x57\x6b\xc8\xd6\xbd\x51\x26\x7b\xf7\x11\x5d\xcb\x26\x6d\xe8\x54\x6f\xfc\x9f\x37\x56\xb5\xf4\x71\xca\xc4\x6b\xa5\x11\x83\xf3\xe4\xd9\xca\x7c\xe3\x61\xa5\xb6\x55\x2e\x3a\x3a\x0c\x76\xd3\xfb\x7f\x76\x02\x3b\xa2\x15\xda\x9a\xf1\x3b\x1c\x55\x8c\x73\x4a\xf8\xf7\xd0\x30\x7b\xb8\x50\x76\x5b\xa0\xec\xba\x50\xfe\xd1\x01\x65\xf7\x2f\x7e\x28\xf0\xdd\x81\xf2\xa4\x0b\xca\x17\x2d\x50\xbe\x70\xa1\xbc\xee\x82\x72\xbf\x05\xca\x7d\x17\xca\x71\x07\x94\xaf\xfc\x40\xbe\x72\x61\x7c\xdb\x01\xe3\x4b\x3f\x8c\x2f\x5d\x18\x2f\x3a\x60\x3c\xf0\xc3\x78\xe0\xc2\x78\xdf\x0e\xc3\x81\xb0\xf2\xd5\xb3\xf6\x96\xae\x8a\x07\x88\xd4\xb0\x8d\xf7\x86\x4d\xe6\x5b\xf9\x11\x13\x70\x76\xdb\xe0\x34\xd8\xef\xb7\x2e\x38\x6d\xfc\x37\x6c\x32\x60\xd2\x09\xe7\x8b\x36\x38\x0d\x16\xbc\xe8\x84\x73\xbf\x0d\x4e\x83\x09\x17\x5d\x70\xbe\x72\x33\x40\x28\x40\x0d\x46\x9c\x77\xc1\x69\xe1\xc4\x61\x83\x15\xff\xd7\xff\x6c\x03\x03\xb5\x5b\x78\x71\xd8\x60\xc6\x59\x3b\x2e\x3e\x1e\xdb\xba\xd9\xda\x52\xa9\x4e\x4c\xef\x01\x02\x69\x64\x5a\x9a\xd7\x59\xbd\x7a\xc1\x49\x39\x39\x50\xe2\xb3\x70\x0f\x7e\x24\xb3\xc5\xbe\x0c\xbe\x3e\xa0\x2f\x79\xad\x3e\x1c\xd1\x87\x89\xfa\xd0\x0b\x7b\x7b\x41\xef\xb3\x5f\x97\x45\xbd\x2f\x72\x48\x84\xbd\x10\x3f\xfd\xe9\xc1\x57\xea\xcb\x36\x7f\xb9\xbe\xff\x74\xbf\xa7\x52\xda\x0a\xa4\xc5\x50\x05\x7a\x3a\xbf\xd0\xc9\x67\x07\x47\x61\xef\xdd\xf6\x29\xe6\x14\xd2\xc9\x07\x2b\x67\xcc\x6a\x18\x27\xd5\xa9\xca\x85\x45\x29\x27\x64\x06\x88\x69\x91\x8f\x2b\x7f\x5a\x09\x33\x5b\x01\xbe\x9c\x55\xd5\xa2\x4e\x70\x91\x95\x55\x3d\x10\x97\x5b\x94\x1f\x19\x73\x29\x06\x08\x31\x99\xa8\x24\x5a\xf2\x39\x1c\x98\x95\x74\xee\x4f\xd4\xb0\xd5\x74\xb0\x17\x04\x7f\x9f\xa2\xcf\xb6\x0c\xda\x47\x4a\xa1\xd1\x60\x2f\xf8\x62\x87\x92\xa1\x4d\x52\x5f\x0a\xb4\x1a\x9a\x3b\x4c\x4c\xee\xc4\x74\xb7\x19\x11\x92\x6f\x19\x47\xbc\x37\xc5\x0c\xb5\x91\xc7\xc3\x1f\xba\xee\xf7\xcd\x4c\xe5\x37\xa0\xb6\x50\xee\xee\x46\x96\x14\x5d\x65\x4b\xa6\x69\x4b\xc6\x63\x03\x33\x33\x4b\xc3\x6d\x92\x65\x60\xf7\x77\xe1\xd0\x9b\xcd\x04\x08\x37\x51\x6b\x53\x9d\xde\x24\xb3\x05\x1c\xe8\xf2\x3a\x2d\xed\x67\xe8\x15\xb3\x90\x49\x05\x41\xec\x2b\x7b\x07\x27\x9f\x90\x17\x91\x46\x22\x08\x93\xd4\x16\x61\xab\x4e\xc2\x0e\x78\x42\x98\x9f\xb3\x8b\x95\xc8\xb1\x51\xe5\x19\x70\xf5\xce\xc0\x87\x38\xce\xbb\xb6\x74\x7b\xa6\x02\x53\xbd\x5a\xfc\x87\xb9\x5e\xcf\x53\x9d\x1e\x04\xdd\x42\x28\x41\x91\x3d\x53\x23\x7e\x22\xb7\x93\x8b\xac\xa1\xb1\x92\xd6\xc5\x36\x1f\x8f\x64\xdc\x1e\xbb\xfb\x3a\xf1\x05\x56\x93\xb9\x5c\x5c\x62\xb3\xc7\x8d\xa7\x99\x93\x13\xdc\xaf\x9c\x12\x10\x9d\xd9\x82\x00\xd3\x8a\xf9\xe1\xcd\x77\xda\x0b\xc3\xac\xe5\x3d\x26\x5a\x15\xf8\x52\xf9\x46\xbb\xfb\x5a\xa5\xf2\x66\x8a\xba\x82\x05\xc3\x86\xc6\x40\x3c\x7d\xb9\xc5\x2f\x84\xc1\xf7\x33\xf1\xf2\xb4\x78\x36\xcf\xaa\xce\x6f\x7c\xe3\xa7\x41\x00\x1d\x19\x46\x50\x57\xd7\x0e\xff\xb4\x48\x41\x0d\xce\xb3\xf9\x7b\xef\xc3\x84\xfa\x0d\x4e\x4a\x71\x75\x68\x37\x39\x5b\x96\xb9\x3c\x0c\x64\xca\x17\x82\x5d\x26\x64\x9d\xa8\xdf\x17\xfe\xde\x22\xd1\x5e\xe4\x59\x25\x32\xf9\x56\x8c\xf2\xf5\x51\x31\x9b\x25\x78\xde\x19\x15\x8b\x55\xd8\xcd\x37\x98\xfb\x06\x7b\x41\xcb\xd9\xaa\xd2\x99\x4e\x29\x73\x35\x34\x47\x83\xef\x39\x48\x72\x80\x17\x6f\x75\x84\x16\x38\x0c\x21\xa7\xb8\xc9\x14\x38\xdd\xc2\x65\x1a\xf9\x9f\x72\x4b\x55\xb0\x56\xf0\xb2\xc1\x94\x42\x8e\xe3\xa0\xf4\x7b\x94\x54\xd1\x8e\xd7\x0e\x14\x95\x08\xf0\xdd\xd7\x8b\xc3\xe8\xe4\xe1\xf0\x1f\xc9\xf0\xb7\x9d\xe1\x57\x67\x43\x4c\x86\xa1\x9f\x5d\x51\x90\x9c\x0e\xdd\x31\x8d\x53\x7c\x4a\xe0\xb5\x9a\x0d\xd5\x4e\xe6\xaf\x50\xb8\x91\x90\x7d\x2d\x23\xe1\x5a\xb0\xd3\x89\xec\x40\x33\xac\x16\x79\x56\x47\xbd\xcf\x74\xd2\x3e\x0d\xe3\x59\x9a\x2f\x94\x99\xdf\x45\xea\x7b\xa7\x5a\x64\xba\x66\xb9\x30\x78\x32\x74\x93\x2a\x32\x30\x5d\x3b\x93\x72\x49\x98\x33\x29\xc2\x35\x9d\x55\xde\xc4\x95\x4d\x96\x5b\xf6\xb3\x7c\x2a\x4b\x97\x74\x76\x10\xd0\xc4\xa5\x1d\x2e\x43\x36\x78\x02\xfb\xe8\x75\xd8\x37\x8a\x59\x54\x3a\x0b\x15\x3d\x10\x79\x9d\x1a\xc2\x8b\x45\x85\xf6\x84\xba\x2b\x58\xaf\x2f\xee\x09\x9a\x4b\x55\xba\x77\xa9\x5b\x84\xc8\xcd\xf3\xf9\xf2\xd5\xf1\x93\x3d\xe7\xc1\x30\x90\xb5\xef\xd3\x45\x4d\x59\x1d\x57\xf3\x11\xbb\xfa\x6c\x2f\xeb\x2c\xc7\x0b\x29\xf9\x2f\x8c\xfc\x32\x9e\x14\x7b\x04\xf7\x3b\x60\xa1\xa7\x20\xaa\x94\x1a\xd4\x31\x07\x8a\x1e\x7e\x19\x4b\xd3\xc9\xca\x9c\x14\xb1\x62\xf8\x96\xaf\xe8\x84\x05\x21\x65\xe6\x31\x25\x93\x23\xa2\x99\x02\x3a\x1f\x91\xf9\x90\xf0\x27\xb1\xa7\x01\x82\x9f\x19\x91\x99\xeb\x4d\x5e\x9d\xa4\xc0\x19\x30\xd8\xef\x75\x35\x6b\x77\x90\xf8\x5b\xde\xc5\x77\xd9\x89\x32\x32\x60\xcb\x38\x0a\x62\x0d\xe1\xbe\xfe\x59\xc8\x0c\x22\x14\x3f\x62\x0e\x34\x81\xa7\xb0\x99\xe0\x03\x55\xf0\x7f\xea\xea\x6b\x34\x08\x19\x44\xd5\x73\x7f\xac\x9e\xdb\x96\xc9\x97\xf8\xa5\x11\x52\x3e\x95\x64\x20\x24\xf9\x52\x23\x6e\xce\xaa\xac\xa5\x29\x7e\x22\x73\x61\xd9\x79\x9a\x74\x02\xa6\x81\x95\xae\xc9\x4e\xd5\x14\x84\x75\x72\x1e\x9e\x32\x7e\x5a\x3c\xca\xe4\xb3\x09\x4b\x77\xe1\x4d\xb6\xc0\xdd\x9e\xfd\xd0\xf0\xc9\x9b\xba\x12\x17\x37\xe4\x06\xaf\xc6\x54\x5c\xc8\x14\xb4\x86\x1f\x9b\x1c\x28\xc6\x31\x26\x23\x54\xe5\x13\x84\x0e\x4c\x39\xac\x92\x0b\x50\xc8\x93\x2a\xfd\xf2\xcf\xaa\x1a\x5d\x04\x24\x98\xc9\x09\x61\xa1\x63\x26\xfe\x6e\xbc\xca\x24\x3c\xec\xa0\xc4\xa5\x48\xdc\xb5\xaf\x18\xf2\xbf\x4d\xdb\xa8\xd6\x2e\x02\x93\xfb\x8b\x8d\x18\x5f\x60\x7b\x18\xac\x9b\x4e\xbb\x37\x5f\xf2\x43\x37\x1f\x95\x19\x40\x89\x1e\x61\xe8\x0c\x66\x55\xb1\xdd\x50\x30\x29\xed\x2c\xa3\xd4\x8c\x59\x8e\x2b\x1e\xf4\xe7\xb4\x56\xd9\xa8\xd8\x57\x6d\x9a\xe5\xa9\x48\x96\x5d\x69\x47\xd6\x5d\x95\xa6\xba\x3a\xb9\x74\xb3\xd8\x9c\x36\x4e\x01\xa2\xca\xa2\x58\x44\x7d\x8f\x1f\x3b\x17\x6b\xc7\x60\xb2\x66\x57\xe4\xf3\xe9\xa8\xe1\x3c\x0f\x32\x15\xe5\x13\xe2\x10\xe2\x81\x1f\x8e\x9f\x0e\xff\x02\xea\x45\xcd\x8c\x60\xa7\x39\x56\x89\x71\x25\x4b\x1d\x06\xe7\x75\x91\x44\xcb\x39\x1f\xad\x23\xcf\x23\x3f\x74\x75\xd7\x67\x67\x74\x23\xc9\xf8\x3d\x4a\x32\x3e\x34\x33\x8c\xbf\xdb\xa6\x6f\x67\xe6\xb7\xc3\x7b\x77\xb7\xe9\xa1\x3a\x63\x33\x75\xb7\x71\xbe\xc4\x04\xf9\xe0\x06\xde\xb3\xe4\x58\x1c\xea\xe7\x87\xc6\x4a\x6c\x38\x3a\x84\x95\x19\xba\x55\x9c\x24\x7a\x61\x0c\xf0\x94\x81\xaf\xb0\xd0\xeb\x43\x17\xfc\x48\x0f\x3a\xad\x42\xe9\xb8\xb9\x5c\xdc\xee\x8c\xa5\x22\xe0\xbb\x2b\x86\xae\xdb\x94\x3a\x49\xd9\x88\x61\x2d\x1f\x4a\x74\x34\x89\x86\x44\xb5\x7b\x26\xd5\xce\xe8\xd3\xb6\xd4\x33\xd5\x12\x34\x0e\xc7\x8c\x90\x35\x53\x62\x0e\x93\xba\x38\x8f\xa0\xaf\xbe\x38\x84\x35\xb5\xd4\x46\x00\x09\x9d\x74\xef\xc6\x59\xc5\x79\xe2\x04\x73\xb5\x56\x17\x1f\x7c\xb2\x80\x39\xd8\x2f\x0c\x64\x68\xc8\xda\xf5\xde\x70\x84\xe3\xc4\x75\x99\x9d\xa7\x4e\xe3\x2b\x3b\xd5\x4b\x31\x3b\xb5\xa3\xa8\x9b\x45\xf6\x23\x2a\x6e\x3e\x3b\x55\x77\xdf\xf3\x3e\x58\x33\x0b\xdd\x26\xea\xbb\xa1\x61\x99\xdc\x63\x08\x4f\xfb\x84\x68\x8a\x5b\xd7\x0a\x80\x86\x2f\x39\x09\x34\x1a\xaa\xd7\x86\xc3\xf7\x4d\x0d\xc3\x94\xf3\x9b\x34\x71\x8f\x21\xdf\x5b\x7a\xb9\xfd\x10\x93\x50\x82\xd5\x72\xa0\x4d\x3b\x1d\xdb\x4d\xd8\x79\x8c\x86\xf5\x9c\xd7\x9c\x47\x8f\xe6\x87\x4a\x4c\xd1\xc1\xcd\xf0\xe1\x32\xc1\x2b\x4f\x01\xf1\x57\xdc\x81\x00\xd0\xec\x6e\x10\xec\x6c\x48\x99\x58\xf7\xce\x5e\x6e\x80\xe9\xf6\xcf\x93\x77\xe3\x7b\xef\xe2\xf8\xde\x61\x0c\xc2\xec\x76\xc4\xf2\x8c\xd0\xa4\x17\xa9\x58\xc7\xcb\x45\x2e\x97\x8c\x18\xa6\xf1\xbd\x31\xf7\xba\x6c\xcd\x29\x6b\xed\xe0\x62\xd8\x23\x6a\x13\x5e\x17\x23\x77\x0d\xb2\x6b\x3e\x5a\xd8\x63\xc0\x2c\xfb\x5c\x2b\xce\xb8\xa4\x8d\x0a\xda\x64\xd1\xb8\x7c\x70\xcf\xaf\x25\xac\xf1\xeb\x57\x17\x78\x7c\x20\x78\x92\xbd\x34\xb4\xd7\x54\x25\x32\xba\x54\x89\xa4\x96\xb3\xf3\xb4\x7c\x75\xc1\x9d\x02\x5d\x10\x8a\x5c\xa4\x26\x3a\x1b\x4f\x83\x2e\xe0\x20\xa9\xea\x27\x50\xcf\xa2\x06\x92\x82\xd8\x2a\x5b\x82\xcc\x76\xdd\x81\xcf\x7a\x4a\xac\x1b\x04\x1e\x8e\xd9\x6e\xd7\xde\x0f\xeb\xf3\x5e\x50\xcd\x8f\xb6\xf0\xdf\x88\x26\xfa\x89\x01\x97\x24\x82\x16\x8a\x0d\x1b\x39\x59\xb5\xa5\xcb\x58\xdd\xaf\x2e\x5e\xcd\xc5\xb1\x72\xe1\x1b\x8c\x09\xe4\xe1\x68\xb4\x9c\xe1\x8b\xe6\x94\x22\x61\x03\x61\xd2\xc2\xb1\xe8\x82\x6c\x24\x2c\x35\xc0\x2a\xd3\xab\x3c\xcf\x9b\x97\x03\x8d\xda\xb7\x5e\x6a\xed\x83\x5f\x2f\x86\x9d\x7d\xd8\x62\xee\x86\xbb\xba\x39\x89\xba\x35\xba\x2e\x3c\x9c\x8f\x65\xcc\x71\xcd\x33\xca\x16\x97\xc3\x9e\xa1\xdb\xeb\xea\x50\xad\xd9\x96\x1e\x92\x70\x2a\x4b\xa0\x1e\xc5\xc6\x03\x60\xf7\xb4\xa1\x8b\x06\xa1\x30\x2a\xf2\x4a\x32\x51\x38\xe4\x13\x9d\xde\xf1\xed\x2e\x55\x46\x31\xe3\x73\x9f\x62\xa6\x30\x79\x16\xa9\x85\x75\x3a\x49\xcb\x2d\xe3\x29\x74\xa9\x31\xe8\x6e\x4e\xd5\x50\x7f\x94\x59\x70\x6f\x3c\xd3\x5f\xdd\x7a\xd2\x5d\x39\x66\x4e\xb5\x61\x79\x10\xbd\x84\x13\x54\x97\x33\xc1\xa6\x61\x1c\xde\xba\x3f\x8f\xbd\xa0\xa1\xb1\x38\xa6\x03\xc5\x65\x0b\x89\xa1\x5f\x02\x67\x96\xf0\xb5\xed\x16\xcc\x96\xe6\x6b\xa9\x66\x4f\xfd\x26\x93\xa2\x01\xdf\x7c\x83\x92\x9b\x9e\x08\x14\xee\x05\xe2\x31\x5b\x13\x0a\x3d\xff\xda\x08\xbc\x34\x5a\x33\xb1\xd4\x05\x1d\x1a\xe1\xc5\x59\x98\x73\x6d\xbd\x85\x16\x0b\xe1\x9d\x3a\xc2\xf7\x31\xf7\x82\x8b\x24\xaf\x52\x3d\xd9\x56\x4e\x2e\xcf\xe3\xbd\xe1\x36\xa6\x48\xca\x46\xdb\xbf\x54\x7c\xc0\x39\x93\xa9\xf4\x85\xb3\xc8\x79\x52\x7e\x7d\x49\x67\x9e\x6f\x7e\x78\xfe\xdd\xe3\xb3\x1f\x9f\xbc\x79\xfb\xfc\xd5\xcb\xc1\x96\x3f\xf3\x16\xba\xd6\x20\x86\x82\xb3\xd9\xff\x51\x40\x14\x7e\x57\x52\x89\x7d\xb1\xac\xe8\x59\x55\x79\x84\xc0\x96\x66\x4a\xd3\xac\x7a\x9c\x02\xf5\xe0\xb8\x90\xb2\x81\x92\x2e\x14\xec\x50\xe8\x71\x86\x61\xe9\xc7\xc5\x8b\x6c\x82\x3c\x32\x56\x77\x0e\xde\x40\x59\x9c\x65\x71\x1d\xe2\x31\x6a\x45\x46\xc0\x2d\x31\x25\x93\xdb\x9f\x3f\x1a\xd6\x9d\xb4\x17\x41\x17\xf5\x55\x21\xd2\x9d\x55\x7e\xbc\x29\x3a\xcb\x8b\x6e\x1f\xa1\xa0\xf9\x25\x19\x8f\xe9\x09\xcf\x7c\x45\xa6\x79\xf4\xb8\xbf\x4a\x4a\x61\x8e\xa9\xb3\xf3\x0c\xe4\xda\x0a\xcd\x3c\x45\x3e\x16\xc9\xec\xd9\x3f\x27\x36\x18\xc4\x4b\xb2\x56\xab\xfc\x34\xa9\xa6\x1d\x9a\x0d\xb1\x92\xb5\xa5\xb3\x34\x1c\x3f\x2d\x93\xc9\x8c\x5d\xfa\x3d\xf2\xd1\xd7\x4b\xdf\x3e\x7f\x06\xcd\xb3\xa3\x02\x2a\xf6\xe4\x68\xb7\xcf\x42\x0f\x13\xe1\xf3\x73\x06\x00\x27\xf8\xd3\x56\xe7\x85\xaa\x81\xb2\xd6\xd2\x4b\x14\x7f\xe6\xcd\x7d\x0b\xdf\x28\xb1\xf1\x69\xc3\xf4\x58\x5c\x3f\x65\xb4\x7e\xd1\xe4\xde\x89\x59\x9a\x4f\x61\x8b\x43\xbd\x6f\x2a\x79\xe8\x11\xcb\x58\xc7\x14\x77\xc5\x26\x92\xae\x5b\xd6\x15\x8e\x98\x53\x4f\x5a\xdb\x32\x97\x52\x07\xfa\xed\xbb\x0e\x91\x3d\xe9\x10\x1d\x7b\x2e\x4d\xf4\xdd\x08\x97\x2e\x00\xf8\x3f\xea\xaa\x34\xc2\x4a\xc8\x00\x00")
 
 func webUiStaticJsGraphJsBytes() ([]byte, error) {
 	return bindataRead(
@@ -444,12 +445,12 @@ func webUiStaticJsGraphJs() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "web/ui/static/js/graph.js", size: 29862, mode: os.FileMode(436), modTime: time.Unix(1510002749, 0)}
+	info := bindataFileInfo{name: "web/ui/static/js/graph.js", size: 51274, mode: os.FileMode(436), modTime: time.Unix(1792184610, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _webUiStaticJsGraph_templateHandlebar = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd4\x58\x5f\x8f\xdb\x36\x12\x7f\xbf\x4f\x31\xc7\x7b\x49\x70\xd0\xea\x92\x03\xf2\x50\x48\x2e\xda\x74\x11\xa0\x40\x90\x22\xd9\xe4\xd5\xa0\xc5\x91\xc5\x86\x22\x15\x72\x24\xdb\x35\xf6\xbb\x17\xa4\xfe\xac\xed\x95\x65\x39\xd9\x06\xc8\x3e\x78\x6d\x72\xfe\x71\xe6\xc7\x1f\x39\x84\xee\x2f\x11\xb2\x01\x29\x52\xb6\xb6\xbc\x2a\x96\x1b\xcb\xab\x0a\xed\x7e\x2f\xc5\xfd\x3d\x83\x4c\x71\xe7\x4e\xe6\xd8\xe2\x5f\x30\xfc\x25\xb9\xb1\x65\x2f\xf6\xa5\x46\xbb\x5b\x86\x11\xff\x11\x49\xad\xa4\xc6\x23\xf9\xce\x61\xa7\x60\xcd\xe6\x64\xf6\x78\x3e\x33\x2a\x52\xeb\xe8\xc5\xff\x1e\x49\x01\x24\x84\x5b\xe2\x16\x39\x58\xb3\x71\x29\x7b\xc1\xa0\x52\x3c\xc3\xc2\x28\x81\x36\x65\xb7\xdb\xca\xa2\x73\xd2\x68\x78\x16\xbe\xc1\x87\x42\xe6\xf4\xdf\x5b\x4d\x68\x7d\x7c\xa0\x71\xe3\xe3\x73\xcf\x19\x68\x5e\x62\xca\x70\x5b\x59\x16\x92\xe1\xbf\x9d\xe4\x20\xac\x28\x33\x9a\xac\x51\x80\x83\xf1\xa5\xd4\x55\x4d\x0c\x04\x27\x1e\x55\xd6\x34\x52\x60\xca\x68\x57\x21\x2f\x90\x0b\x06\xbc\x26\x93\x99\xb2\x52\x48\x98\x32\x93\xe7\x6c\xb1\xdf\x7b\xfd\xfb\xfb\x24\xee\xd7\xf0\x28\x09\xb1\x90\xcd\x8c\xcc\xbc\x1c\x4b\xcc\x81\x18\x36\x5c\x2d\x1d\x71\x72\x50\xd5\x4a\x45\x56\xae\x0b\x62\x8b\x51\xf3\x00\x89\x2c\xd7\xe0\x6c\x96\xb2\xfd\x1e\x2a\x4e\xc5\x1f\x16\x73\xb9\x85\xfb\xfb\xd8\xdb\x90\x59\x2c\xcb\x75\xcc\xff\xe4\xdb\x48\x19\x2e\xd0\xde\xac\x65\xfe\x73\x93\xee\xf7\xb0\xaa\xa5\x12\x9f\xd0\x86\x7c\x1f\x64\xcd\x55\x52\x6b\xb4\x0c\xb8\xa2\x94\x79\xd5\x65\x3f\x34\x63\xcd\x63\x43\x4f\x05\x9f\x50\xb7\x5e\x72\x45\x1a\x56\xa4\xa3\xca\xca\x92\xdb\x1d\xe0\x16\xb3\x9a\x70\xb9\x22\xcd\xc0\x17\x33\x65\xae\x5e\x95\x92\x18\x34\x5c\xd5\xe8\xe1\x15\x24\x7a\xe8\x74\xb3\x23\x7e\x1c\x2a\xcc\xe8\x12\x8a\x5a\xa9\xde\x9a\xd4\x0e\x2d\x2d\x4b\x24\x2b\xb3\x11\xa3\x00\x89\xa9\xc8\xa7\xba\x8b\x86\x2d\x22\x68\x95\xa0\x55\x02\x4e\x90\xd5\xd6\x19\x0b\x51\x12\xb7\xc2\x23\xc1\xc5\xad\xdf\xef\x57\x8a\x8b\x80\xb5\xd6\x58\xe0\xca\xaf\x24\x7c\x46\x82\xeb\xb5\x47\xcb\xf8\x96\x38\x1b\xe8\xf1\xd8\xbf\xa3\xe8\x44\xf3\xee\xdd\x6f\xef\x7e\x82\xd7\x46\x37\xde\x15\x15\xd2\x01\x19\xf8\xd5\x18\x72\x64\x79\x05\x9a\x37\x2b\x6e\x6f\x00\xee\xfc\x94\xc5\x2f\xb5\xb4\xe8\xe0\x77\xde\x70\x97\x59\x59\xd1\x48\x51\x00\x2c\xe6\x16\x5d\x71\x73\x32\x19\x45\xff\x60\xe6\xac\x51\x9e\x71\xf8\xaa\xe2\x1a\xd5\x38\x5a\x6a\xd5\x9b\xd3\xbc\xf1\x6b\x8b\x88\xaf\x1c\x7b\xd0\x55\xd2\x8d\xa1\x37\x28\x2b\xd9\xc9\x79\xb4\xa2\xf6\x4c\x60\x34\x5b\x24\x1c\x0a\x8b\x79\xca\xfe\x13\x8e\x87\x9e\x2e\xb9\x95\xbc\x47\x78\x7f\x74\xf4\x73\x83\xbb\x8e\x2f\xc9\xac\xd7\xfd\xc8\xe2\x8d\x97\x4c\x62\xbe\x48\x62\x25\xaf\x0a\xa5\x5f\x1b\xcf\x48\x36\x78\x18\x59\x66\xb4\x33\x0a\xcf\xc4\x76\x32\x3b\x19\xdd\xeb\x56\x76\x2a\xbe\x24\xae\xd5\xe8\xf8\x41\x35\x89\xaf\x42\x00\xa8\xcf\xa6\x7b\xa4\xa6\x87\xda\x7e\x04\xda\x03\xd9\x1b\xe2\x52\xa3\x05\x8b\x9e\x90\xd9\xc3\x41\xde\xad\x69\xdc\xc5\x09\xc0\x14\x72\x9b\xcb\xed\x59\xe1\x76\xff\xc0\xed\x96\x2c\xcf\x08\x85\xdf\x28\xb9\xb1\x99\x0f\xc3\xd4\x15\x0a\x08\x5c\xea\x6e\x1e\xe1\xfc\x9c\xcb\xca\x9a\x12\xa9\xc0\xda\xb5\xc7\xe7\x32\x18\x02\xeb\xb7\x7a\x3b\xd2\x1e\x57\x0a\xf3\x73\x69\xea\x8c\xae\x6a\x22\xa3\x27\x24\xe0\x94\xe2\x05\xe6\xbc\x56\x87\x0e\x26\xb5\x5b\xf2\x6f\xdd\x4c\x4b\xb6\xd4\x2d\x30\x5b\x86\x75\x5c\x30\x2b\xc9\x57\xf8\x43\x61\xa5\xfe\x0c\x54\x20\x90\x2c\xb1\xcd\xc0\xcd\xe4\x92\xfd\xd1\x35\xdc\xcb\xd4\xae\x2a\x64\x66\x34\x0c\xdf\xa2\x52\xea\xda\x79\xba\x3c\xb3\x87\x3a\x1b\x71\xbb\xa4\x49\x99\x50\x89\x39\xb9\x1d\x72\xd9\x22\x61\x7a\xe9\x1e\xa3\x07\x95\xee\x90\x3a\x27\x5b\x77\x43\x8a\xc0\xe4\xed\x1e\x98\x53\x3c\x7f\xc9\x9a\x53\xba\x83\xa0\xa6\xc5\x9d\xfc\x0b\x53\xf6\xff\x69\xa1\xee\x64\xde\xef\x0f\xcc\x4e\xec\x48\x98\x89\xe6\x6f\xc5\xf3\x35\x88\x86\xe1\x3a\x32\x0b\xd3\x43\x9d\xde\x58\xb3\x79\x52\x4c\x57\xea\x49\x20\x3d\x76\x35\x38\x9a\xff\x3e\x34\x77\x48\x6d\x3f\x20\x1a\x3c\xc3\xa1\x16\x33\xb1\xf0\x1e\x37\x52\x8b\x80\x06\xf4\xff\x65\xf9\x8d\x58\x58\xf1\xec\xf3\x86\x5b\x71\x05\x1e\xbe\x8d\xe3\x46\x58\x4e\x70\xea\xcf\xa9\x19\x74\xd1\x52\x1e\x6a\x31\x87\xea\x86\xc4\xdd\x76\xd9\x1a\xa8\x0e\x9e\x7d\xbc\x7b\xfd\xfc\x92\xf6\x51\x0f\xfc\x51\x93\x54\x97\x34\xc2\x5d\xc7\xf7\x25\x9c\x52\xb6\xdb\xed\x76\xd1\xdb\xb7\x91\xb8\x5c\xdf\x99\xdc\xda\xc3\x06\xb5\x98\xc3\xad\x3d\xbb\xbe\x78\x75\x49\x6e\x20\x58\xd4\xed\x55\xe7\x07\xdc\x4b\x9e\x59\xe7\xef\xa5\x5f\x44\xc3\x75\x86\x4f\xb8\x99\x72\x63\xaf\xdc\x4b\xe7\x25\x2e\x70\xeb\x75\xbc\x38\xb5\xa0\xc3\xc6\xbd\x7b\x7d\x19\xc8\xc6\x19\x55\x87\xc6\x58\x6a\x70\x98\x19\x2d\xdc\xc9\xbb\xd0\x7b\x74\x37\xf0\xcc\x3d\x67\x87\x08\xee\xbb\x78\xc2\xaa\x7f\xd0\xf1\x7b\xf6\xe1\x77\xdf\x23\x0c\xa0\x7b\x98\xf2\xc3\x2d\x66\x5f\x4d\x5d\xa1\xbf\x5b\x7e\xda\x4a\x1d\x83\xf1\x1c\xb4\x1d\xf1\xec\x33\x8a\xf0\xce\x71\x35\x8e\x5a\xd4\xf4\x36\x9e\xe4\xb2\xd9\x45\x5d\x48\x21\x50\x3f\x54\x25\x38\x38\x4a\x7e\x18\x99\xbc\x4e\x9d\x79\xe3\x3a\x9a\x9c\xd1\x1d\xb5\x9d\x16\xb7\xc8\xcf\x3e\x9b\x3d\x56\x52\xb8\xf6\xbb\x7a\x4a\x61\x6a\x6a\x4e\x07\xd8\xf6\xba\xd0\xb5\xb0\x47\x0d\xe0\x71\x5b\x7b\x36\x5e\xdf\xf5\xe3\x81\x5d\x85\x10\x3e\x7d\x7f\x2a\x50\x3b\x7f\x03\x0a\xbf\x0b\xd3\xa0\xed\x3d\x2d\xc3\xd8\x54\xde\xa9\x40\x2e\x26\x4b\x4d\xc5\xe2\x56\x61\x89\x9a\x92\x98\x8a\x4b\xa2\x9f\x7c\xd5\xa7\x05\xfd\xec\xa4\xd3\x84\x56\x46\xec\xa6\x3d\xd9\x45\x42\x02\x32\xa3\x5c\xc5\x75\xca\x5e\xb2\x45\x22\x17\xda\x84\xf3\xd1\x03\x3d\x89\x49\xf8\x0f\x3b\x19\xc7\x94\x9f\x24\x0e\xc9\xbb\x12\x10\xe7\x1e\x6b\xaf\x7b\x0f\x3b\x1a\xfa\x9a\xe7\x27\xf0\x44\xd9\x3f\x20\x8f\xaf\x80\xf7\x8d\x14\x96\xa6\x41\xd6\x3f\xc2\xb0\xc5\xfb\x30\x00\xc3\x0b\xcf\x57\x44\x9d\xc4\xfe\x8a\xf2\x30\xd2\x09\xfc\x1d\x00\x00\xff\xff\x8f\x46\x2a\x3c\xc7\x18\x00\x00")
+var _webUiStaticJsGraph_templateHandlebar = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd5\x59\xdd\x6f\xdb\x36\x10\x7f\xdf\x5f\xc1\x69\x2f\x2d\x06\xc5\x6b\x07\xf4\x61\xb0\x3d\x6c\x5d\xd0\x61\x40\xd1\x21\xfd\x78\x35\x68\xf1\x6c\x71\xa5\x49\x95\xa4\x9c\xa4\x46\xfe\xf7\xdd\x91\x92\x2c\x3b\x92\x2c\x37\xd9\x47\x0d\x54\x91\x8e\x77\xe4\x7d\xfc\xee\x78\x64\x19\x8b\xbf\xa9\x90\x5b\x26\xc5\x2c\x59\x5b\x5e\xe4\x8b\x6b\x7c\x16\x60\x77\x3b\x29\xee\xee\x12\x96\x29\xee\xdc\xd1\x58\x32\xff\x86\x35\xbf\xe9\xca\xd8\x4d\xcd\xf6\xa9\x04\x7b\xbb\x08\x14\x7a\xa4\x52\x2b\xa9\xe1\x80\xbf\x5a\xb0\x12\xb0\xe6\xfa\x68\xf4\x70\x3c\x33\x2a\x55\xeb\xf4\xd9\x0f\xf7\xb8\x90\xcf\xc3\x8d\xe7\x16\x38\xc3\x59\x90\xf7\x59\xc2\x0a\xc5\x33\xc8\x8d\x12\x60\x67\xc9\xe5\x4d\x61\xc1\x39\x69\x34\x7b\x12\xde\xd8\xdb\x5c\xae\xfc\xf7\x97\xda\x83\x25\xfd\x98\x86\x6b\xd2\xcf\x3d\x4d\x98\xe6\x1b\x98\x25\x80\x22\x49\x70\x06\xbd\x1d\xf9\x20\x58\x94\x19\xed\xad\x51\x0c\x9a\xc9\x17\x52\x17\xa5\x4f\x98\xe0\x9e\xa7\x85\x35\x5b\x29\x70\x26\x7f\x5b\x00\xcf\x81\x8b\x84\xf1\xd2\x9b\xcc\x6c\x0a\x05\x1e\x07\xcc\x6a\x95\xcc\x77\x3b\x92\xbf\xbb\x9b\x4e\x6a\x1b\xee\x39\x61\x82\x5e\x18\xe1\x99\xe7\x5d\x8e\x69\xb1\xc1\x96\xab\x85\xf3\xdc\x3b\x56\x94\x4a\xa5\x56\xae\x73\x9f\xcc\x3b\xa7\x47\x49\xb9\x59\x33\x67\xb3\x59\xb2\xdb\xb1\x82\xfb\xfc\x4f\x0b\x2b\x79\xc3\xee\xee\x26\x34\x87\xcc\x26\xc8\x30\xe1\x7f\xf1\x9b\x54\x19\x8e\x5e\xbe\x58\xcb\xd5\xcf\xdb\x19\x72\x2f\x4b\xa9\xc4\x07\xb0\xc1\xdf\x2d\xaf\xb9\x42\x6a\x8d\x98\x61\x5c\xf9\x59\x42\xa2\x8b\x9a\x34\xc2\xe6\x2e\xd2\x63\xc1\x27\xc4\xad\xe6\x5c\x7a\xcd\xf0\x1f\x06\x50\x6e\xb8\xbd\xc5\xf8\x42\x56\x7a\x58\x20\x2d\x61\x14\x4c\xb4\xa4\x5c\x6e\x24\x06\x1a\x3d\x5a\x02\xc1\x2b\x70\xd4\xd0\xa9\x46\x87\xa3\x41\x2b\xac\xad\x29\x0b\x96\x4b\xe7\x0d\xe6\x4a\xf8\xea\x90\x42\xb9\x65\xe9\x3d\xfa\x32\x2e\x1e\x3f\x92\x63\x75\x05\xac\x78\xa9\x3c\x13\xd6\x14\xc2\x5c\xeb\xd4\x9b\xf5\x5a\x41\x85\xc6\xf8\x31\x4b\xea\x51\x0c\x82\x95\x3c\xcd\xb9\x2b\x4c\x51\x16\x88\x52\x5b\x42\x45\x44\x40\x72\x2d\x00\xa1\xbf\xe2\xca\x21\xd5\x4b\x4f\xb2\x57\x90\x81\xf6\xaa\xf1\x88\x68\x41\xdf\x75\x2a\xce\xd8\xef\xd1\x38\x36\x75\x38\x67\x13\x0b\x84\x79\x40\x1e\x11\x3b\x0d\x9e\x44\x23\x3b\xc7\x4a\x55\xcf\xd3\x98\xba\x01\x5d\x36\x7e\xa4\x0f\x9a\xbc\x54\x1d\x11\xe8\x01\xbb\x03\x05\x99\x3f\x95\xde\x91\xab\x0e\xb3\xd4\x0e\xac\xc7\xe5\xbc\x95\x59\x77\xdc\x4c\xe1\x29\x07\x2a\x98\x24\xf3\x94\x45\x21\x16\x85\x18\xc7\x25\x4b\xeb\xb0\xfe\xa4\xd3\x49\x64\xee\xd2\x39\xae\xfb\xef\xe5\xc8\xc9\x4a\x62\x2d\xaa\xcc\x15\x59\x12\x9e\xa9\xe0\x7a\x4d\x69\xdc\xe7\xdf\x96\xf0\x35\xb7\x5a\xea\xb5\x3b\x90\xaf\x88\x3d\x13\xf4\x5b\x7a\x48\xfb\x36\x4d\x8f\x24\xdf\xbd\xf9\xed\xcd\x4f\xec\xa5\xd1\x5b\x5a\xcb\x23\x44\x98\x37\xec\x57\x63\xbc\xf3\xb8\x83\x61\x24\xb7\x4b\x6e\x2f\x90\x91\x86\x2c\x7c\x2a\x25\x06\x9b\xfd\xc1\xb7\xdc\x65\x56\x16\xbe\x13\xd4\x58\x09\x91\x2b\xbf\x38\x1a\x4c\xd3\x7f\xd0\xf5\x08\x45\xda\x4b\xf8\x12\x53\x06\x54\x72\x22\x33\xd0\x2e\xb2\x2d\x45\x7e\x97\xec\x65\x15\xa6\x48\x4f\xa2\x4e\x95\xac\xf8\x08\xee\x98\xe7\x9c\xd0\x88\x01\xe1\x2c\x47\x7b\x67\xc9\x77\x61\xe3\xaf\x37\xc2\x50\x28\xaa\x14\xa9\x9b\x82\x7a\xac\x59\xee\xa8\xf6\x10\x65\xfe\x8a\x38\xa7\x13\x8e\x91\x56\xf2\x2c\x55\x6a\xdb\x78\xe6\xe5\x16\xda\x9a\xa1\x1e\x0e\xf9\x7b\x74\x3b\x1a\x1d\xd4\xee\x65\xe4\x1d\xd2\xaf\xbb\xb0\x1c\x46\x13\xe7\x0a\x0a\xa0\xee\x7d\xee\xee\x88\x69\x5b\x9a\x28\x2c\xb6\x5a\x34\x11\xc7\xde\xc4\x22\xee\x68\xab\x4d\xf6\x2d\x5a\x65\x53\xf7\x12\x47\x00\x53\xc0\x2d\x6e\xe0\xbd\xcc\x31\x7f\xd8\xe5\x0d\x26\x46\x46\xc5\x1d\x13\x05\x0b\x61\x46\x6a\xe0\xc6\x84\x84\xb0\x4b\xba\x8b\x7b\x38\xef\x5b\x12\x3b\x20\xac\x73\x39\x94\x2e\x36\x46\x71\x87\x63\x96\x6a\x45\xa4\xc4\x46\x44\xc1\xca\x0f\xa8\xd5\xec\x7f\x03\x1c\xac\x6f\x37\xdc\x2f\x30\x28\x7d\xb0\xb3\x0e\x72\xc6\xda\x2f\x20\x5b\x04\x3b\x4e\x4c\x1b\x37\xce\xb7\xb9\x95\xfa\x23\x96\x1f\x40\xca\x06\xa2\x07\x2e\x06\x4d\xa6\xa6\xa4\xe9\xb8\xd5\x6d\x91\x4b\x84\x01\x6b\xde\xd2\x8d\xd4\xa5\xa3\x72\x29\x07\x1d\x37\xb0\x8f\x1e\x36\x3f\x63\x7c\xdb\xf8\x32\x22\x61\xd8\x74\xc2\x68\x2b\xd2\x15\x52\xc7\x78\xeb\x5d\xe3\x22\x66\x56\x31\x07\xc6\x04\x8f\xda\xe7\x31\xa1\x6b\x29\x35\xcc\xee\xe4\x67\x64\xff\x71\x98\xa9\xda\xda\x77\xbb\xd6\xb4\x03\x19\x39\x16\xcd\x0f\xc5\xf3\x39\x88\x66\x4d\x3f\x33\x0a\xd3\x4d\x9c\x5e\xe1\x9e\xf6\xa8\x98\x2e\xd4\xa3\x40\xba\xab\x35\xf8\x0f\xca\x5c\xbb\xb4\x7d\x85\x68\xa0\x0a\x07\x5a\x8c\xc4\xc2\x15\x5c\x4b\x2d\x02\x1a\x80\xfe\x22\x22\x1e\x86\x85\x25\xcf\x3e\x62\x53\x28\xce\xc0\xc3\xc3\x6a\x5c\x47\x95\xc3\xf6\xa0\xde\xa7\x46\x94\x8b\x58\xf2\xd0\xfa\x31\xa5\xae\x71\xdc\x65\xe5\xad\xa6\xd4\xb1\x27\xef\xdf\xbd\x7c\x7a\x4a\xfa\xe0\x76\xe3\xbd\xf6\x52\x9d\x92\x08\xbd\x0e\x1d\x6c\x38\x9e\xbd\x6f\xf1\x97\xbe\x7e\x9d\x0a\x31\x0e\x38\xa7\x6b\x6b\x0d\x1b\xb4\x7f\x31\xca\x59\xb1\xba\x3e\x7b\x71\x8a\xaf\x29\xb0\x38\x73\x28\xac\x5f\x69\x65\x1d\x9f\x4b\xbf\x88\x2d\xd7\x58\x8f\x1e\x2f\x99\x30\xec\x67\xe6\xd2\x17\xd7\xd6\xf3\xea\xe2\xa9\x8c\xad\xa7\xaa\xee\xd5\x9a\x62\x83\x3d\x7a\x19\x4e\xd6\x52\x33\x07\x68\xa2\x70\x47\x37\x7e\xc8\x73\xc1\x9e\xd0\x75\x5e\x0b\xc1\xf5\xfd\x8c\x87\xa2\xbe\xaa\xa3\x9c\xdd\x7f\xd7\x67\x84\x06\x74\xfb\x21\x22\x47\xcc\xbe\x48\xfe\x0f\xfe\x39\xe7\x4a\xc8\x79\xac\xa6\x20\xc2\x0d\xd6\xd9\x38\x8a\xa8\xa9\xe7\x78\x94\x66\xb3\xd2\x3a\x97\x42\x80\xde\x47\x25\x2c\x70\xe0\xfc\x40\x19\x6c\xa7\x7a\x2e\x1c\xc6\x44\xa3\x1d\x8b\x78\xd2\xa2\xdb\xd7\xde\x3b\x8c\xfb\x42\x0a\xd6\x94\xd5\x43\x02\x43\x43\x63\x4e\x80\xf1\xac\xcb\xaa\x23\xec\xc1\x01\xf0\xf0\x58\xdb\xab\x2f\x9d\xfa\xa1\x35\x2f\x7e\x84\x27\x9d\x4f\xd1\xfb\x8e\x3a\xa0\xf0\x9d\x9b\x2d\x1e\x31\xab\x59\x17\x81\x36\xe4\x77\x4f\x37\xda\x83\xa1\xf6\xf9\xfc\x52\xc1\x06\xcf\xc0\xd3\x09\xbe\x9f\x60\xfd\x40\x51\x1f\x66\xa4\xd1\xc1\x45\xa7\x7e\x69\xc4\xed\xf0\x4a\x76\x3e\xf5\x02\xcd\x54\x74\xf7\x38\x4b\x9e\x63\xf8\xe4\x5c\x9b\xb0\x3f\x12\xd0\x71\x11\x41\x0f\x3b\xa8\xc7\xd0\x3a\x38\x4c\xce\x3b\x13\x10\x7d\x37\x67\xe7\xdd\x87\x3d\xf8\xfa\x89\x51\xa1\xac\xff\x6b\xa0\xdb\x02\x5e\x1f\xa4\x60\x83\x90\x49\xea\x4b\x98\x64\x7e\x15\x08\xac\xb9\xe1\xf9\x02\xad\xa7\x13\x6a\x51\xf6\x94\x8a\xe1\x6f\xfa\x50\x8e\xac\xa1\x1a\x00\x00")
 
 func webUiStaticJsGraph_templateHandlebarBytes() ([]byte, error) {
 	return bindataRead(
@@ -464,7 +465,7 @@ func webUiStaticJsGraph_templateHandlebar() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "web/ui/static/js/graph_template.handlebar", size: 6343, mode: os.FileMode(436), modTime: time.Unix(1509022446, 0)}
+	info := bindataFileInfo{name: "web/ui/static/js/graph_template.handlebar", size: 6817, mode: os.FileMode(436), modTime: time.Unix(1792184610, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -489,7 +490,27 @@ func webUiStaticJsProm_consoleJs() (*asset, error) {
 	return a, nil
 }
 
-var _webUiStaticJsTargetsJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x93\x4d\x8e\x9c\x30\x10\x85\xf7\x9c\xa2\x62\xf5\xc2\x96\x18\x2b\xfb\x16\xab\xac\x66\x9d\x03\x44\xc6\x14\x60\xe2\xb1\x5b\x76\xd1\x19\x29\xe2\xee\x91\x01\x37\x3f\x33\x69\x4d\x2d\x71\xbd\x72\xbd\xef\x99\x76\x74\x9a\x8c\x77\x40\xbe\xeb\x2c\x72\x5f\x0f\x25\x44\x52\x84\xe2\x6f\x01\x00\x70\x57\x01\x8c\xf6\x0e\x2a\xb8\xa4\x53\x21\x5b\xe3\x1a\xce\x0c\x13\xd7\xb9\xc1\xb4\xc0\x53\x83\xb4\xe8\x3a\xea\xa1\xaa\x2a\xf8\x0e\x02\x16\x79\xaa\x80\x34\x06\xb7\x74\x4f\xc5\x43\x34\xdf\x32\xb7\x53\x18\x71\x2f\x98\xc7\x05\x7c\xf3\x77\xfc\x61\x55\x8c\x9c\xa5\x2f\x2f\xba\xc7\x7b\xf0\xee\xa5\xf1\x7f\x1c\x13\x52\x35\xcd\x67\xa7\xe3\x2d\x6f\x36\x01\xda\x88\x5f\x9f\x9b\x94\xff\x9b\xba\xdc\x79\xf0\xb0\xe2\x70\xf8\x4e\x5c\xc8\x95\xdf\x82\xee\x5a\x4c\x45\xf1\x40\x6b\x9c\x21\x9e\xfd\x5d\x38\x93\x83\xaf\x7f\xf5\xa8\x1a\x0c\x4c\x48\x6d\x8d\xfe\xcd\x73\x33\xdf\x73\x48\xec\x07\x5f\xcf\xe8\xa9\x37\x31\xb3\x57\x69\x4f\xa2\xc0\x99\x69\x98\x28\x1f\xfd\xa9\xf0\xfd\xa6\x5c\x83\xe1\x35\x67\xb6\x17\x1a\xf9\xa9\xa9\x0d\x50\x0b\x7c\x3f\x20\x67\xfa\x2d\x65\xba\xdf\x2c\x95\xf5\x5a\xd9\x9f\xe4\x83\xea\x50\x46\xa4\x57\xc2\x37\x3e\xf8\xba\x84\x56\xd9\x88\x2b\xac\x5c\x2b\x9f\xb4\x4d\xb9\x04\xbe\x9d\x7f\x08\xea\xf9\xf8\x93\xfa\xc3\xf4\xf3\xf5\xd3\x92\x5a\x36\x7a\x4c\x00\x12\x4c\x54\xba\xdf\x22\x30\x25\xa4\x64\x4f\x41\x44\xb4\xa8\xc9\x87\xed\x47\xd8\x22\xb8\x1e\x08\x1e\x36\xef\xd6\xcd\xb3\x5c\xcc\x2f\x9e\x25\x0f\xec\x48\x74\xf5\x90\x13\xbb\xa9\x80\x8e\xe2\xe9\xb9\x3c\x73\x37\x15\xc5\x85\xa7\xc7\x26\xae\xff\x02\x00\x00\xff\xff\xc9\x97\xfe\x65\xd7\x03\x00\x00")
+var _webUiStaticJsSettingsJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9d\x56\xdf\x8f\xdb\x36\x0c\x7e\xcf\x5f\xc1\x6a\x87\xc2\x46\x73\x3e\xec\x35\x87\xa0\x18\x86\x01\xbb\xad\x5b\xb7\x5d\x87\x3d\xdc\xb2\x83\x62\x33\xb1\x50\xdb\xf2\x49\x72\xd2\xa0\xcd\xff\x3e\x52\xf2\xaf\xf4\x9c\xb4\xe8\x4b\xec\xc8\xe4\x47\xf2\xe3\x27\x4a\x3b\x69\xe0\x0f\xa3\x4b\x74\x39\x36\x16\x96\xe3\x3f\x9f\x3e\xc1\xc7\xe3\xed\x6c\x76\x73\x03\xf7\xe8\x9c\xaa\xb6\x16\x72\x5d\x64\x16\xe8\x3b\xfc\x7d\x07\xb5\xc1\x0d\x1a\xac\x52\xb4\xa0\x37\x7e\xb5\xb1\x68\xe6\x50\xa3\xb1\xca\x3a\xcc\x40\x55\x7e\xb9\xd0\xa9\x2c\x18\xc8\x3a\x6d\xe4\x16\x3b\xf3\xb5\xd1\x7b\xf2\x48\x66\x43\xd8\xa4\x8f\xb5\x84\x8f\x33\x80\x0c\x37\xb2\x29\x9c\x5d\xf8\x7f\x00\x04\xf2\xb6\xf2\x00\xa2\x50\xdb\xdc\x89\x39\x88\x4c\x9a\xf7\x02\xb4\x01\x21\x1b\xa7\x69\x65\x9f\xab\x34\x87\x8d\x2e\x0a\xc2\xf7\x91\x86\x5c\xc9\xb5\x03\xe2\x0f\x9a\x92\x95\x1c\x10\xec\x81\x52\x2e\x13\xff\x91\xbe\x94\xb8\xe8\xf0\x3a\xfb\x7f\x72\xce\xd1\x00\x7e\xc0\xb4\xe1\xf2\xf0\x03\xe1\x5a\xab\x74\x65\x41\x1a\x04\x83\xa9\x36\xd9\x50\xf7\x53\x83\xe6\x00\xb9\xe2\xb2\x0f\x01\xd9\x2f\xfd\x1c\x56\x16\xe0\x4c\x83\x3d\xfc\x3b\xf2\x30\xb2\x0a\xf4\x54\xb8\x2f\x0e\x20\x33\x46\xdb\x1a\x59\xe7\x36\xf8\xfb\xf7\xbf\xd8\x8a\xd2\xfb\x3e\x17\xb4\x78\x9c\xcf\xe8\x77\x8b\x6e\x01\x9b\xa6\x4a\x1d\xa5\x13\x55\xb2\xc4\xb8\xa5\xcc\x51\x0e\xe1\x0d\x60\x47\x0d\xdf\xc9\xa2\x41\xa2\xd7\x77\xe5\x3e\x74\x24\x21\xf7\x3b\x2a\x3f\x12\xb6\xe5\x3f\x11\xf0\x0a\x3c\xcc\x6d\xeb\xab\x36\x10\x05\xdf\x17\xcb\x25\x54\x4d\x51\xc4\x3d\x2e\x50\xed\xae\x31\x15\xfc\x72\xff\xf6\xf7\xa4\x96\xc6\x62\xb0\xed\xbd\x8f\xfe\x79\x84\x54\x3a\xea\x4d\x84\x83\x2f\x95\xfe\x86\x53\xe9\xd5\x51\xca\x03\xac\x11\x32\x65\xe5\xba\xa0\xfa\xa9\xb3\xcc\x66\x9b\x19\x91\x4b\xc8\x2a\x0b\x7c\x04\xd8\x36\xf8\x84\x8c\x92\x4e\x40\x0f\x5c\xcb\xea\xb6\xe7\xcb\x3e\xe3\x6b\x1e\x98\x99\xa2\xed\x84\x2a\x7b\x96\xaa\x79\x28\xdf\x3a\x43\xcb\x6a\x73\x68\x29\x68\x39\xf8\x96\xda\x87\x22\x43\xd6\x2c\xf5\x3f\x59\x42\x0b\xd8\xab\x2a\xd3\xfb\xa4\x64\xcc\xdf\x30\x53\x12\x5e\x3f\x5f\x8b\x44\x14\xa4\x6f\xaf\x53\x5d\x68\x73\x6d\xd3\x20\x6d\x06\x8a\x45\x0c\x0b\xdf\x48\x8f\x4d\xb9\xc8\xba\x2e\x0e\xef\xd8\x82\xf9\x09\x5b\x27\x2d\xa4\xed\x37\xb8\xdf\x18\x2c\x6f\xda\xe9\xa0\x83\xca\x33\x9d\x36\x25\x56\x0e\x8c\xd6\x8e\x13\x1e\x50\x46\x0c\x77\x35\xb3\x02\x03\xca\x72\xb2\x5f\x24\xc4\x48\x78\x03\xd1\xf2\xc6\xba\x6b\x3d\x48\x77\x61\x4f\xc6\x27\x8a\x7e\x3a\x83\xd5\xb3\xd5\x89\xb0\x0b\xfc\x04\x2f\x5f\xc2\x53\xe0\x89\xa6\xd7\xeb\x6e\x86\x2c\xba\xa9\x72\x3b\x12\xd7\x55\xd4\x55\x98\x74\x2f\x3f\x15\xc8\x8f\x38\x71\x7a\xbb\x2d\xf0\x47\xa6\xa8\xcd\xfa\xda\x23\xcd\x61\x94\xb1\x5f\x89\x07\xe9\x11\xd1\xaa\x52\x0e\xd6\xd4\x2d\x3b\x96\xb6\x05\x42\x6d\x3a\xae\x2b\xb9\x53\x5b\xc9\xe4\xc1\x5a\x1a\x26\x96\xbd\xce\x50\xea\x1d\x97\x94\xab\xf8\xae\x03\x7b\xe4\xb5\x8e\x44\x8a\xf9\x2b\x62\xed\x81\x43\x90\x1a\x2b\x1e\x94\x05\x75\x38\xa7\x79\xe2\x47\x60\x47\x9c\x77\x61\xb3\x84\xc2\x88\xb4\x50\x29\x97\xd4\x07\x1e\x09\x98\xb6\x83\xd3\x35\x91\x5f\xcb\x90\x6b\xd4\xa9\x9d\x9e\x03\xcc\x86\x4a\x8d\x84\xaa\xea\xc6\xf9\x8d\xb8\xf4\xf4\xac\x1e\xfc\x06\x59\xf2\xfe\xf9\x92\x16\xc8\x44\xac\x44\x9c\xd4\x14\x8a\x52\xca\x31\x7d\x8f\x19\xf3\x6c\xfa\x21\x73\x29\x12\x79\xfa\x32\x31\x7a\x46\x1f\x4c\xc6\xb6\x43\x6c\x6e\xa6\xb2\xc9\xe9\x3c\x9b\xf2\x19\x84\xff\x95\x2c\xf8\xd3\xe0\xb1\x3d\x20\xa6\xaa\x3b\xcb\xca\xf8\x1c\x11\xf1\x37\x15\x77\x02\xd1\xd6\xd8\x46\xfe\x3c\x7b\x56\x98\x3f\x9a\xee\x38\x77\xd2\xd9\x74\x39\xfe\x70\x7a\xf4\x86\xab\x4e\x78\x83\x1b\x13\x18\x9d\x2d\x68\x38\xd8\x2e\x97\xd3\xa7\xc2\x6a\x4f\x68\xd2\x96\xd1\x44\x77\x78\x66\xbc\xb8\xf9\xef\xdf\xec\xd5\xc3\x61\x9f\xe5\xa5\x5d\x5d\xdd\x24\x0e\xad\x8b\xbc\x6f\x3c\x3e\xb9\xae\x3c\x00\xc5\x2c\xb4\x65\x0b\x91\x6c\xb4\x29\xaf\xb7\x46\x37\x35\xb5\x84\xce\xe0\x76\x7f\xe7\xd2\x5e\xa3\x31\xda\x88\x3e\x4e\x77\xf4\x9c\x9e\x72\x5f\xc4\x34\x58\xea\x1d\x9e\x87\x3d\xdb\xb3\x11\x4b\xf3\x40\xc3\xa8\x55\x1c\xbe\xbd\xb1\xfd\xc0\x4a\x1c\x4d\xec\x35\x52\x7c\x0c\x57\x21\x3e\x66\x94\xa5\xc4\xab\x8c\xae\x44\x19\x38\x0d\x72\xa7\x55\x06\x1b\xca\x27\xe7\x39\xe0\xef\x6c\x3c\x07\x67\xe1\x96\x54\xe2\xe4\x15\xed\x33\xbd\x33\xe7\x17\xa7\x30\x0f\xdd\x8b\x06\xcc\xf5\x1b\xbe\x39\x56\x68\x42\x87\xbe\xda\x3c\xba\x9c\x1f\xa5\x77\x9c\xcd\xae\x26\xad\x78\xaa\xd2\xf7\xff\x01\xa3\x88\x0c\xf0\x0c\x0b\x00\x00")
+
+func webUiStaticJsSettingsJsBytes() ([]byte, error) {
+	return bindataRead(
+		_webUiStaticJsSettingsJs,
+		"web/ui/static/js/settings.js",
+	)
+}
+
+func webUiStaticJsSettingsJs() (*asset, error) {
+	bytes, err := webUiStaticJsSettingsJsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "web/ui/static/js/settings.js", size: 2828, mode: os.FileMode(436), modTime: time.Unix(1792182534, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _webUiStaticJsTargetsJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9d\x58\xfb\x6f\xe3\xb8\x11\xfe\x3d\x7f\x05\x97\x9b\xc6\x32\x62\xcb\xde\x3b\xb4\x40\xfd\x5a\xb4\xdb\xdd\x7b\x60\x1f\x87\x4b\x0a\x14\x48\x83\x05\x2d\xd1\x96\x12\x59\x52\x45\x3a\x89\x7b\x97\xff\xbd\x33\xc3\x87\x1e\x96\x73\x8b\xfe\x62\x99\xe4\x70\x38\x1c\x7e\xf3\xcd\x90\x93\x09\xbb\x4e\x24\xd3\xa2\xda\x4a\xad\xd8\xb6\x2a\xf6\xa5\x8c\xd9\xfa\xc0\xee\x8a\xf5\x88\xa9\xa2\xd2\xbe\xc9\x44\x1e\xb3\x34\x57\x5a\xe4\x91\x0c\xcf\x1e\x44\xc5\xca\xa2\xc8\x14\x5b\xb2\x9b\xdb\xf9\xd9\xd9\x66\x9f\x47\x3a\x2d\x72\x26\x55\x24\x4a\xf9\xe3\xf5\xa7\x8f\x81\xd2\x55\x9a\x6f\x87\xec\xb7\x33\xc6\x70\x82\xcc\x75\xaa\x0f\x9f\x44\x09\x93\xb0\x8f\x31\x7e\xc1\x67\xf0\x23\x76\xe5\x9c\x8f\x4c\xcf\x82\x7a\x32\xed\x3b\x56\xd4\xb1\xf5\x1d\x03\x3e\x98\xb1\xc1\xc5\x7f\xf6\x85\x9e\x0f\xac\xcc\x80\x63\xd7\xeb\xef\xff\xea\x7b\x26\xa6\xe7\xe9\xbb\x0f\xf3\x01\xf4\x3c\x83\x89\x8c\x55\x52\xef\xab\x9c\x5d\x91\x5d\xce\xbc\xb0\x92\x65\x26\x22\x19\x4c\x6e\x2e\x16\x2b\x3e\xf8\xf7\xe4\x76\xb2\x1d\x31\xbf\xa1\x40\x0d\xad\xb5\x76\xba\xdf\xc6\x8d\x82\x9d\x83\xee\xe1\xfc\xec\xb9\xe1\x81\x44\x8a\x4c\x27\xd7\xc5\xbb\x4c\x28\x15\x98\x96\x51\xa1\x1e\x53\x1d\x25\xac\xd5\xc7\x58\x24\x94\x64\x7c\x5f\xf2\x19\x35\xfd\x3a\x5c\xed\xa3\x48\x2a\xc5\xe7\x4d\xb1\xfc\x3e\x2f\x1e\xf3\x23\xd9\x47\x51\xe5\xb0\x1b\x2b\x1b\xcb\x8d\xd8\x67\xba\x2b\x14\x8b\x7c\x2b\x2b\x92\x79\x6e\x99\x9c\x89\xb5\xcc\xae\x4a\x91\xab\x80\xfe\xaa\x11\x2b\x76\xa9\xae\x8f\x2e\x17\x3b\x89\x67\xfd\x65\x7d\x27\x23\x1d\xde\xcb\x83\x93\x1c\x86\x88\x93\x60\x38\xb7\x92\x0a\xb5\x58\x54\x30\xb6\x29\x2a\x16\x60\x77\x0a\x5d\xd3\x39\x7c\x16\x46\x57\x98\xc9\x7c\xab\x13\xe8\xb9\xbc\x74\x8e\x48\x37\x2c\xa0\xc1\x9b\xf4\x96\x2d\x97\xcb\x86\x0d\xe4\x80\x02\x1c\x9f\xef\xa5\xd9\xe2\x33\xfd\xd2\x6a\x61\xb9\x57\x49\x30\x58\x60\x83\x45\xe8\xf5\x25\x27\xe3\xcc\xbe\xc6\x65\x95\xee\x44\x75\xe0\xab\x01\xbb\x6c\xe2\xd3\xad\x35\x84\xee\xc1\x92\x77\x46\xcd\xf6\x6e\x9c\x90\x91\xe2\x8b\x09\xae\xb2\x1a\x0c\x8d\x13\x8d\xd1\xc6\x0a\xb3\x23\x32\x7c\xda\x81\xcc\x69\xdb\xec\x49\xf1\x55\x5e\xe4\xd2\x29\x77\xba\xed\x6c\xa3\xfe\xae\x48\xf3\x80\x33\xde\x41\x9b\x4a\x21\x20\xc1\x58\xa5\xaf\xa2\x0a\x6c\xaf\xcf\x4c\x83\xcb\xff\x21\xb4\x0c\x4b\x51\xa9\x96\x08\xea\x9f\x98\xe0\xff\xaf\xac\x0a\xa6\xd3\x9d\x64\xc5\x86\xfd\x50\x30\x70\xd4\xbd\xf2\x94\xa0\x13\xa1\xd9\xa3\xac\x24\xcb\xe5\x83\x84\xc3\xa5\xf9\x71\x68\xf7\x9d\xaa\xcf\xe2\x73\x00\x47\xf4\xfb\xef\xb0\xda\xe2\x78\xdf\xfc\x33\x4e\xe3\x6e\x3f\x84\x0f\x19\x21\x3c\x3e\x09\x9d\x84\x3b\xf1\x14\x4c\x47\x2c\x20\x2b\x01\xd6\xc1\x90\x8d\x19\xa8\x9b\xb0\x37\xd3\xe9\x94\xcc\x24\xf7\xe2\x94\x05\xfb\x4b\x57\x3b\xf6\x87\xba\xf8\x90\x3e\xc9\x38\xf8\x1e\xcf\x87\x2b\x26\xb6\x05\xef\xf8\x8f\xd6\xda\x64\x45\x51\x19\x55\x13\x52\x05\xd2\x3b\x0e\xbf\xdd\xd1\x3f\xb9\x51\xa7\x0b\xbc\x0d\xce\x52\x52\x54\x51\x72\x2d\x9f\xb4\x55\x8b\xde\x01\xf2\xc4\x0e\x61\x1d\xc6\x52\x05\x0e\x84\x10\x07\xda\x14\x5b\x81\x74\x89\xf4\x89\x72\x66\x7a\xd8\x38\x37\xaf\x2f\x30\x93\xeb\x83\x83\xf3\xd2\x14\x42\x66\x20\x34\x5e\xff\x67\x95\xb5\x43\x0a\x91\x09\x94\x6c\xd7\x0e\x6d\x38\x5a\x17\x91\x0e\x13\x18\x24\xe7\x11\xde\x92\x26\x74\xdf\x12\xb0\x6b\x44\x5b\xaf\x19\x0d\x1e\x75\xe0\xe8\x8f\x05\x40\xe1\x1d\xd0\x50\xd0\xc1\x60\x56\x88\xf8\xda\x40\x26\x30\x06\x9c\x87\xe2\x0e\x0e\xd7\xd8\xb2\x93\x3a\x29\x62\x60\xf1\x1f\xde\x5f\x5b\x12\xdf\x57\xd9\x8c\xfd\xf2\xb7\xeb\x1f\xbf\xfe\xf2\xeb\xfb\x0f\x3f\xfd\x0b\xfd\x3d\x11\x65\x3a\x79\x78\x33\xb1\xe0\xb3\x92\xb1\xd0\x62\xc6\x7e\x83\xc4\xa3\x25\xa8\x10\xb0\xe2\x83\xe4\xcf\xf5\xe0\xf5\xa1\xc4\x81\x3b\x55\xe4\x9c\xd8\x38\x8c\x21\x94\x02\x67\x5c\x50\x49\x55\x3a\xaf\xa0\xdb\xd6\x87\x9f\x21\x9d\x41\x06\x7a\x9e\xfb\x3e\x87\xf7\x25\x43\xe9\x10\xd5\x86\x66\x25\xbb\x2d\x23\xda\xc7\x66\x76\x6a\x1f\x9f\xd5\x61\x68\x85\x80\x46\xe6\x8d\x91\x3b\xb2\xc3\x9d\x45\x88\x4d\x08\x24\xce\x9d\x0c\x62\xff\x15\x99\x7b\x03\x63\xb7\xb5\x5a\xc6\xea\x5e\xdc\x09\x7c\x67\x26\x67\xdb\x85\x66\x40\xbf\x23\xb6\x2f\x67\x6c\xfa\xec\xb4\x3d\xdb\x2f\xe0\x89\xa0\x07\x13\x9b\x18\x1c\x3a\xb9\x5a\x75\xe8\xf6\x46\x30\xaa\x25\xd0\x2e\x1d\x9a\x24\x46\x74\x87\xc9\xab\xdf\xba\x70\x5f\x5e\x5e\xb6\x2d\x30\xbf\xae\x78\x68\x26\x14\x9a\xe6\xf2\x09\x90\x43\x59\x1f\x22\xe8\x6a\x7b\x15\xe7\xc3\xf4\x7a\x25\xb7\x08\x0e\x78\xc3\x49\x95\x57\x22\x46\x6c\xdd\x34\x93\x4e\x52\x80\x16\xe1\x8e\xc0\x15\x38\xe6\x1c\x46\x2c\xc5\x03\x5a\xf7\x8f\xce\xbd\x1e\x1b\x30\xa0\x6a\x81\x33\xde\xb2\xf1\x1b\x36\xc3\xe6\xca\x34\xb1\x35\xf5\x4e\xf0\x6e\x74\x71\x06\x06\xdb\x94\x66\x87\xce\x03\xfe\xda\xee\x60\x8c\xa1\x85\x79\x7d\x18\x26\x69\x2c\x03\x2b\x51\xc9\x3c\x96\x95\x69\x01\xe0\x37\x22\xcd\xea\x6d\x3e\x25\x95\xdb\xe5\x1f\x6b\x42\x1f\xec\xd4\x16\xb6\x09\xd3\x42\x44\x7f\x91\x2b\xf9\xf3\xd5\x97\xcf\x60\x78\xb7\x2b\x94\x55\x05\x21\x30\xa3\x01\x8c\xc8\xbd\x42\xf0\x1c\x5b\x4d\x72\xc8\x19\x08\x2d\xfe\x9e\x66\xd9\xf5\x6b\x88\x22\xf9\xc2\xd2\x70\xe0\x09\x52\x7f\x5f\x29\x95\xaa\x77\x45\x96\x89\x52\x01\xc1\x7b\x08\x58\xbf\x65\x45\x24\xb2\x2b\x5d\x54\x62\x2b\x43\xd0\xf8\x93\x96\xbb\x80\x83\xd4\x18\x15\x93\x34\x61\x53\x57\x7b\xe9\x59\x7c\x93\x66\x1a\xd2\x59\xdc\xe6\x70\x1b\xfd\x90\x01\xb1\x49\xd0\xa2\xcc\x47\x5c\x4e\x7d\xd1\xbe\x02\x9f\x6b\x3b\x5f\x35\x78\xdc\x69\x0c\x70\x5a\xcd\xe1\x3e\xc6\x6a\xb7\x8c\x4d\x1f\xb8\xe5\x41\x64\xc1\x11\xa1\x9a\x79\xfb\xdc\xc4\xd5\xa1\x3d\xd5\x77\xc3\xec\x54\x05\x7c\x06\x39\x26\xba\x97\x31\xa7\x89\x0d\x24\x79\xe8\x1b\xbb\x6a\x54\xe8\x66\x95\x55\xaf\x72\x71\xc1\x5e\x08\x65\xab\x78\x23\x32\xd5\x2a\xbb\x7c\x02\x36\x9b\xc4\x79\x9c\x8a\x00\xcb\x2d\x10\x2a\xb1\x7c\xfa\xb2\x09\x4c\x73\xc8\x5e\x81\xc8\xf8\x4d\xdf\x11\x1b\x7b\x7f\x2d\x1e\x95\xcd\x83\xaa\x76\x62\x05\xbd\x2f\x96\x92\x2f\x90\xef\x29\xea\xa5\x8b\x08\x01\xd2\x50\xaf\xd2\x06\x9e\x6f\x3b\x45\x9a\xc8\x64\x05\x99\x1d\x7f\xc7\xa6\x6e\x66\x94\x83\xbe\xc2\xd6\xd2\x48\x00\xee\x8e\xea\xc9\x86\x3a\x2a\x16\x5d\x39\x07\x01\xe3\xe8\x02\x77\x64\xe8\xd4\x7a\x98\x2f\x74\xb5\x02\xc0\x7a\x32\x19\x2c\x74\xbc\x5a\x08\x96\x54\x72\x73\x54\x93\xea\x70\x9b\x15\x6b\x91\x41\x2d\x60\xca\xd1\x63\x13\x7c\xb1\x10\xaa\x32\x4b\x21\xfa\xde\xf2\xe1\xcd\x94\xaa\x57\x28\x5e\xc5\x6a\x31\x01\xfd\xc7\x0b\x9e\xda\x3a\xea\x6f\x5f\x68\x1c\x5c\xc8\x80\xae\x4f\xa8\x1e\x1a\xef\xcb\x52\x56\x78\x5f\xe9\xb1\xaf\x9e\xec\x6a\xe9\x6f\xb0\x08\xc2\x0f\xa8\x7c\x5c\x42\x31\x02\xa0\xe6\x94\xf7\xc7\xba\xd8\x6e\x33\xb9\xe4\x1a\x60\xaf\xd3\xd2\xf6\x26\x7a\x97\x2d\x4d\xd0\x43\x5d\xab\x51\x60\xd0\xd0\xcd\x9a\xd6\xf0\xc5\x7a\xf5\x77\x09\xc0\x92\x80\x68\x22\x79\x20\xa8\xd9\x62\xb2\x5e\x2d\xd6\x74\x2a\xcd\x8b\x91\x0e\xe3\x54\x45\xc5\x03\x06\xfb\x47\x53\x6b\xd5\x67\xd0\xd0\xdf\x9a\xe2\x6e\x53\xc8\x4b\xfc\xc5\x4d\xf3\x85\xe9\xb0\x05\xbd\xc1\x92\x2b\xe9\x69\xde\xa9\x09\x06\xcd\xc7\x22\xd8\x86\x5d\x50\xab\x5b\xdc\x11\x0e\x4d\x6d\x67\x2f\x14\x40\x8e\x26\xab\xd8\x8f\xa1\x46\xac\x49\xa0\x2a\xc6\x48\x55\x61\xeb\xb5\x00\xc8\x32\x72\xdc\xec\x45\x98\xa8\x24\x6a\x2a\xf2\xec\x60\xf5\xc0\xe8\x63\x22\x73\xd4\x76\xc0\x61\x26\x9f\x60\xff\xb1\x8c\x47\xd0\x9f\x02\x7b\xdc\x4b\x59\x9a\xc5\x4a\x60\x72\x66\xf3\x0d\x94\x5e\xa8\x08\x2e\xcc\x09\xd0\x70\x7e\xf0\xa1\x5e\x33\x87\x4b\x82\x9e\x2e\xf0\xe8\x3d\x5d\x10\x99\xe2\x4b\x03\x5c\x30\x74\xa1\x45\x46\xac\x71\x82\x47\xa8\x1a\x39\xc5\x22\xb6\xd4\x20\x99\x16\x8d\xd4\x55\x63\x3b\x09\x18\x09\x58\xfc\xd2\xcc\x82\x22\xc8\x74\x19\x3b\x5c\x6f\x87\xbc\x3c\x39\xb7\xfb\xdb\xf7\xc9\xfe\x5b\x30\x1a\x53\x1f\xc6\xb2\x95\x36\x69\x29\xcc\x86\x66\x02\xfa\xa8\x45\x40\x10\x6c\x95\x0b\x33\x10\xfb\x0a\xf1\x09\x5e\xc5\xb0\x0d\xac\xed\xd6\x3f\x1d\x7b\x81\x30\x31\xe2\xe8\x41\x81\x08\xce\x04\x83\x09\x42\x50\x74\xc4\x5d\xde\x90\xe3\xa0\xc1\x80\xc7\x0d\x60\x64\x2c\xf9\x9f\xf9\x6a\x91\x3a\x93\xc8\x90\x7a\x6f\xb0\x68\x0a\x1e\x18\x43\xfa\x7b\xa8\xe0\x1b\xe3\x5b\x08\x2e\xdf\xea\xa5\x1c\x46\xab\x2c\x26\x69\x67\x25\xc1\xd2\x98\x76\x3a\x7e\xd1\x3e\xcb\xbf\xaf\xff\x50\x70\x75\x7a\xb4\x0e\x55\x16\x60\xa0\x3a\x7f\xe2\x5d\xc7\xb7\x3b\x5e\x85\x31\x40\xce\xb0\x87\xa9\x6d\x38\xfb\x76\xf7\xe0\x62\xa9\xa1\x0c\x54\xc7\x1e\x43\x92\x3e\x20\x0f\x02\x7f\x95\x99\x38\xcc\x18\xbe\x35\xf0\x81\x3f\x35\x70\xd3\x31\x03\x8b\x75\x26\x9d\x7a\xd3\xa0\xdf\x31\xf8\x39\x96\x39\x6a\x36\xed\x75\x51\x99\x28\x37\x4d\x7c\x54\x2b\x7d\x2b\x41\xba\xec\x12\x24\x10\x17\x82\x6c\x85\x99\x0f\xfe\xae\xde\xe7\x31\x11\x3b\xec\x2f\xa1\x8e\x2b\xcc\x29\xbe\x65\xb8\xb6\xd1\x84\x4b\xb5\xe1\x45\xdf\x47\x29\xd7\xb4\xd0\x47\xf8\x0f\x17\xe0\xdd\x65\xd7\x45\x7c\x20\xca\x6c\x23\x0a\x01\xd4\x53\x86\x38\xda\x75\xb3\x1a\xcc\x3b\xa1\xed\x75\x0e\x27\x5e\xf5\x11\x6e\xa3\x88\x23\x06\xc1\xfa\x1b\x62\x30\xa0\x40\x74\x0c\x4c\xd2\xcd\x02\x5a\xed\x77\xf4\x64\x65\x4b\xe8\x06\x68\x2c\x87\x00\x4c\x1c\xfd\xc0\x60\x9a\x33\x07\xa8\x16\x92\x00\x17\x8a\x5b\xe5\x83\x9b\xde\xac\x79\x3b\xc0\x4a\x94\xfe\x77\xaf\xf5\x46\x34\x30\x8c\x30\xb2\xc4\x5d\xd3\xad\xb9\xbe\x9e\xdb\xf1\x21\xdd\x9a\x03\x93\xe8\x40\xa3\x7d\x64\xa5\xc5\x5b\xa5\xba\xea\x29\xd5\x47\xec\x95\xd5\x4e\xef\xb4\xc4\xce\x00\xb4\x96\xfa\x0d\x14\x19\x01\x4f\xb9\x2f\x95\x2d\xe0\x5b\x42\x39\x7a\xcb\x3f\x1c\x35\x2d\x66\xa4\x11\xae\x33\x3b\xc0\xa4\xa9\x64\x7a\xa8\x64\x18\x8a\x38\xee\x1b\x45\x4a\x39\xfd\x08\x70\x32\x7f\x18\x3b\x5c\xea\xa0\x2b\x3e\xf2\x79\xeb\x3a\xcb\xdc\x4e\xec\x16\x09\x70\x0e\x26\x0d\x5c\xb6\x92\x0c\xbd\x5b\x0e\x87\xf3\x7e\x1d\xdf\x76\xd6\xfe\xba\x5e\x49\x71\x7f\x7c\x49\x7f\x66\x10\x77\xf2\xdb\x9c\x87\xee\x39\xe5\x3a\xe3\x58\xf7\x30\xdd\xb7\x53\xb9\x2b\xf5\x21\xf0\x21\xe3\x84\x2c\xfe\x3c\x32\x5a\x37\xc3\x1c\x6a\x5b\xfb\xd6\x74\x1c\x61\x70\xdf\xe1\x51\x96\x46\xf7\x70\x87\xe7\x61\x9d\xd6\x78\xfd\xae\x1f\xb8\x03\xb0\xab\xe8\x24\x85\x3a\xed\x9c\xbe\x1e\x6c\x61\x1f\x42\x6c\x7c\xbd\xa2\xbc\xec\xae\x34\x9d\xfb\x2f\x99\x3c\x62\xbe\xc3\xf1\x0d\x4c\x27\xbb\x82\x23\x33\xe8\x5e\x42\xd3\xf0\x62\x02\x46\x84\x69\x6c\x2f\xb0\x2d\xa5\xfc\xff\x42\xe1\xcb\x11\xd8\xc4\x67\x33\x14\x9b\x17\xbe\xe6\x83\x83\x0f\x52\x7c\x38\x2e\xf6\xba\xbd\xfd\xfa\x9e\x8b\xc7\x90\xe6\xe5\x5e\xf7\xf9\x3d\xca\x40\xec\xda\x28\x08\xac\x22\xbb\xa6\x6d\xd1\x0b\x95\x76\x22\xc6\x84\x11\xfb\x6e\xda\xef\xf5\xd6\x1d\x39\x4a\xb0\x32\xb1\x73\x8c\xbd\xad\x47\x4a\x02\xd3\x79\x80\x28\x82\xff\xff\x03\x3f\x79\xac\xf0\x09\x1b\x00\x00")
 
 func webUiStaticJsTargetsJsBytes() ([]byte, error) {
 	return bindataRead(
@@ -504,7 +525,7 @@ func webUiStaticJsTargetsJs() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "web/ui/static/js/targets.js", size: 983, mode: os.FileMode(436), modTime: time.Unix(1502568872, 0)}
+	info := bindataFileInfo{name: "web/ui/static/js/targets.js", size: 6921, mode: os.FileMode(436), modTime: time.Unix(1792182204, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -1020,6 +1041,7 @@ var _bindata = map[string]func() (*asset, error){
 	"web/ui/static/js/graph.js":                                                               webUiStaticJsGraphJs,
 	"web/ui/static/js/graph_template.handlebar":                                               webUiStaticJsGraph_templateHandlebar,
 	"web/ui/static/js/prom_console.js":                                                        webUiStaticJsProm_consoleJs,
+	"web/ui/static/js/settings.js":                                                            webUiStaticJsSettingsJs,
 	"web/ui/static/js/targets.js":                                                             webUiStaticJsTargetsJs,
 	"web/ui/static/vendor/bootstrap-3.3.1/css/bootstrap-theme.min.css":                        webUiStaticVendorBootstrap331CssBootstrapThemeMinCss,
 	"web/ui/static/vendor/bootstrap-3.3.1/css/bootstrap.min.css":                              webUiStaticVendorBootstrap331CssBootstrapMinCss,
@@ -1105,6 +1127,7 @@ var _bintree = &bintree{nil, map[string]*bintree{
 					"graph.js":                 &bintree{webUiStaticJsGraphJs, map[string]*bintree{}},
 					"graph_template.handlebar": &bintree{webUiStaticJsGraph_templateHandlebar, map[string]*bintree{}},
 					"prom_console.js":          &bintree{webUiStaticJsProm_consoleJs, map[string]*bintree{}},
+					"settings.js":              &bintree{webUiStaticJsSettingsJs, map[string]*bintree{}},
 					"targets.js":               &bintree{webUiStaticJsTargetsJs, map[string]*bintree{}},
 				}},
 				"vendor": &bintree{nil, map[string]*bintree{
@@ -31,6 +31,195 @@ Prometheus.Graph.stepValues = [
   "1w", "2w", "4w", "8w", "1y", "2y"
 ];
 
+// The signatures and descriptions of the PromQL functions, shown when
+// completing and editing function calls in the expression input.
+Prometheus.Graph.functionDocs = {
+  abs: {
+    signature: "abs(v instant-vector)",
+    doc: "abs(v instant-vector) returns the input vector with all sample values converted to their absolute value."
+  },
+  absent: {
+    signature: "absent(v instant-vector)",
+    doc: "absent(v instant-vector) returns an empty vector if the vector passed to it has any elements and a 1-element vector with the value 1 if the vector passed to it has no elements."
+  },
+  avg_over_time: {
+    signature: "avg_over_time(range-vector)",
+    doc: "The average value of all points in the specified interval."
+  },
+  ceil: {
+    signature: "ceil(v instant-vector)",
+    doc: "ceil(v instant-vector) rounds the sample values of all elements in v up to the nearest integer."
+  },
+  changes: {
+    signature: "changes(v range-vector)",
+    doc: "For each input time series, changes(v range-vector) returns the number of times its value has changed within the provided time range as an instant vector."
+  },
+  clamp_max: {
+    signature: "clamp_max(v instant-vector, max scalar)",
+    doc: "clamp_max(v instant-vector, max scalar) clamps the sample values of all elements in v to have an upper limit of max."
+  },
+  clamp_min: {
+    signature: "clamp_min(v instant-vector, min scalar)",
+    doc: "clamp_min(v instant-vector, min scalar) clamps the sample values of all elements in v to have a lower limit of min."
+  },
+  count_over_time: {
+    signature: "count_over_time(range-vector)",
+    doc: "The count of all values in the specified interval."
+  },
+  day_of_month: {
+    signature: "day_of_month(v=vector(time()) instant-vector, offset=0 scalar)",
+    doc: "day_of_month(v=vector(time()) instant-vector, offset=0 scalar) returns the day of the month for each of the given times in the time zone offset hours east of UTC."
+  },
+  day_of_week: {
+    signature: "day_of_week(v=vector(time()) instant-vector, offset=0 scalar)",
+    doc: "day_of_week(v=vector(time()) instant-vector, offset=0 scalar) returns the day of the week for each of the given times in the time zone offset hours east of UTC."
+  },
+  days_in_month: {
+    signature: "days_in_month(v=vector(time()) instant-vector, offset=0 scalar)",
+    doc: "days_in_month(v=vector(time()) instant-vector, offset=0 scalar) returns number of days in the month for each of the given times in the time zone offset hours east of UTC."
+  },
+  delta: {
+    signature: "delta(v range-vector)",
+    doc: "delta(v range-vector) calculates the difference between the first and last value of each time series element in a range vector v, returning an instant vector with the given deltas and equivalent labels."
+  },
+  deriv: {
+    signature: "deriv(v range-vector)",
+    doc: "deriv(v range-vector) calculates the per-second derivative of the time series in a range vector v, using simple linear regression."
+  },
+  exp: {
+    signature: "exp(v instant-vector)",
+    doc: "exp(v instant-vector) calculates the exponential function for all elements in v."
+  },
+  floor: {
+    signature: "floor(v instant-vector)",
+    doc: "floor(v instant-vector) rounds the sample values of all elements in v down to the nearest integer."
+  },
+  histogram_quantile: {
+    signature: "histogram_quantile(φ float, b instant-vector)",
+    doc: "histogram_quantile(φ float, b instant-vector) calculates the φ-quantile (0 ≤ φ ≤ 1) from the buckets b of a histogram."
+  },
+  holt_winters: {
+    signature: "holt_winters(v range-vector, sf scalar, tf scalar)",
+    doc: "holt_winters(v range-vector, sf scalar, tf scalar) produces a smoothed value for time series based on the range in v."
+  },
+  hour: {
+    signature: "hour(v=vector(time()) instant-vector, offset=0 scalar)",
+    doc: "hour(v=vector(time()) instant-vector, offset=0 scalar) returns the hour of the day for each of the given times in the time zone offset hours east of UTC."
+  },
+  idelta: {
+    signature: "idelta(v range-vector)",
+    doc: "idelta(v range-vector) calculates the difference between the last two samples in the range vector v, returning an instant vector with the given deltas and equivalent labels."
+  },
+  increase: {
+    signature: "increase(v range-vector)",
+    doc: "increase(v range-vector) calculates the increase in the time series in the range vector."
+  },
+  irate: {
+    signature: "irate(v range-vector)",
+    doc: "irate(v range-vector) calculates the per-second instant rate of increase of the time series in the range vector."
+  },
+  label_join: {
+    signature: "label_join(v instant-vector, dst_label string, separator string, src_label_1 string, src_label_2 string, ...)",
+    doc: "For each timeseries in v, label_join(v instant-vector, dst_label string, separator string, src_label_1 string, src_label_2 string, ...) joins all the values of all the src_labels using separator and returns the timeseries with the label dst_label containing the joined value."
+  },
+  label_replace: {
+    signature: "label_replace(v instant-vector, dst_label string, replacement string, src_label string, regex string)",
+    doc: "For each timeseries in v, label_replace(v instant-vector, dst_label string, replacement string, src_label string, regex string) matches the regular expression regex against the label src_label."
+  },
+  ln: {
+    signature: "ln(v instant-vector)",
+    doc: "ln(v instant-vector) calculates the natural logarithm for all elements in v."
+  },
+  log10: {
+    signature: "log10(v instant-vector)",
+    doc: "log10(v instant-vector) calculates the decimal logarithm for all elements in v."
+  },
+  log2: {
+    signature: "log2(v instant-vector)",
+    doc: "log2(v instant-vector) calculates the binary logarithm for all elements in v."
+  },
+  max_over_time: {
+    signature: "max_over_time(range-vector)",
+    doc: "The maximum value of all points in the specified interval."
+  },
+  min_over_time: {
+    signature: "min_over_time(range-vector)",
+    doc: "The minimum value of all points in the specified interval."
+  },
+  minute: {
+    signature: "minute(v=vector(time()) instant-vector, offset=0 scalar)",
+    doc: "minute(v=vector(time()) instant-vector, offset=0 scalar) returns the minute of the hour for each of the given times in the time zone offset hours east of UTC."
+  },
+  month: {
+    signature: "month(v=vector(time()) instant-vector, offset=0 scalar)",
+    doc: "month(v=vector(time()) instant-vector, offset=0 scalar) returns the month of the year for each of the given times in the time zone offset hours east of UTC."
+  },
+  predict_linear: {
+    signature: "predict_linear(v range-vector, t scalar)",
+    doc: "predict_linear(v range-vector, t scalar) predicts the value of time series t seconds from now, based on the range vector v, using simple linear regression."
+  },
+  quantile_over_time: {
+    signature: "quantile_over_time(scalar, range-vector)",
+    doc: "The φ-quantile (0 ≤ φ ≤ 1) of the values in the specified interval."
+  },
+  rate: {
+    signature: "rate(v range-vector)",
+    doc: "rate(v range-vector) calculates the per-second average rate of increase of the time series in the range vector."
+  },
+  resets: {
+    signature: "resets(v range-vector)",
+    doc: "For each input time series, resets(v range-vector) returns the number of counter resets within the provided time range as an instant vector."
+  },
+  round: {
+    signature: "round(v instant-vector, to_nearest=1 scalar)",
+    doc: "round(v instant-vector, to_nearest=1 scalar) rounds the sample values of all elements in v to the nearest integer."
+  },
+  scalar: {
+    signature: "scalar(v instant-vector)",
+    doc: "Given a single-element input vector, scalar(v instant-vector) returns the sample value of that single element as a scalar."
+  },
+  sort: {
+    signature: "sort(v instant-vector)",
+    doc: "sort(v instant-vector) returns vector elements sorted by their sample values, in ascending order."
+  },
+  sort_desc: {
+    signature: "sort_desc()",
+    doc: "Same as sort, but sorts in descending order."
+  },
+  sqrt: {
+    signature: "sqrt(v instant-vector)",
+    doc: "sqrt(v instant-vector) calculates the square root of all elements in v."
+  },
+  stddev_over_time: {
+    signature: "stddev_over_time(range-vector)",
+    doc: "The population standard deviation of the values in the specified interval."
+  },
+  stdvar_over_time: {
+    signature: "stdvar_over_time(range-vector)",
+    doc: "The population standard variance of the values in the specified interval."
+  },
+  sum_over_time: {
+    signature: "sum_over_time(range-vector)",
+    doc: "The sum of all values in the specified interval."
+  },
+  time: {
+    signature: "time()",
+    doc: "time() returns the number of seconds since January 1, 1970 UTC."
+  },
+  timestamp: {
+    signature: "timestamp(v instant-vector)",
+    doc: "timestamp(v instant-vector) returns the timestamp of each of the samples of the given vector as the number of seconds since January 1, 1970 UTC."
+  },
+  vector: {
+    signature: "vector(s scalar)",
+    doc: "vector(s scalar) returns the scalar s as a vector with no labels."
+  },
+  year: {
+    signature: "year(v=vector(time()) instant-vector, offset=0 scalar)",
+    doc: "year(v=vector(time()) instant-vector, offset=0 scalar) returns the year for each of the given times in the time zone offset hours east of UTC."
+  }
+};
+
 Prometheus.Graph.numGraphs = 0;
 
 Prometheus.Graph.prototype.initialize = function() {
@@ -39,7 +228,7 @@ Prometheus.Graph.prototype.initialize = function() {
 
   // Set default options.
   self.options.id = self.id;
-  self.options.range_input = self.options.range_input || "1h";
+  self.options.range_input = self.options.range_input || Prometheus.Settings.get("graphRange");
   if (self.options.tab === undefined) {
     self.options.tab = 1;
   }
@@ -104,6 +293,7 @@ Prometheus.Graph.prototype.initialize = function() {
   })
 
   self.error = graphWrapper.find(".error").hide();
+  self.warnings = graphWrapper.find(".warnings").hide();
   self.graphArea = graphWrapper.find(".graph_area");
   self.graph = self.graphArea.find(".graph");
   self.yAxis = self.graphArea.find(".y_axis");
@@ -157,6 +347,22 @@ Prometheus.Graph.prototype.initialize = function() {
     self.updateGraph();
   });
 
+  self.historyMenu = self.queryForm.find(".history_menu");
+  self.queryForm.find(".history_group").on("show.bs.dropdown", function() {
+    self.renderHistory();
+  });
+  self.historyMenu.on("click", "a", function(e) {
+    e.preventDefault();
+    var item = $(this);
+    if (item.hasClass("clear_history")) {
+      Prometheus.History.clear();
+      return;
+    }
+    self.expr.val(item.data("expr"));
+    self.handleChange();
+    self.queryForm.submit();
+  });
+
   self.queryForm.submit(function() {
     self.consoleTab.addClass("reload");
     self.graphTab.addClass("reload");
@@ -235,44 +441,7 @@ Prometheus.Graph.prototype.populateInsertableMetrics = function() {
           self.insertMetric[0].options.add(new Option(metrics[i], metrics[i]));
         }
 
-        self.fuzzyResult = {
-          query: null,
-          result: null,
-          map: {}
-        }
-
-        self.expr.typeahead({
-          source: metrics,
-          items: "all",
-          matcher: function(item) {
-            // If we have result for current query, skip
-            if (self.fuzzyResult.query !== this.query) {
-              self.fuzzyResult.query = this.query;
-              self.fuzzyResult.map = {};
-              self.fuzzyResult.result = fuzzy.filter(this.query.replace(/ /g, ''), metrics, {
-                pre: '<strong>',
-                post: '</strong>'
-              });
-              self.fuzzyResult.result.forEach(function(r) {
-                self.fuzzyResult.map[r.original] = r;
-              });
-            }
-
-            return item in self.fuzzyResult.map;
-          },
-
-          sorter: function(items) {
-            items.sort(function(a,b) {
-              var i = self.fuzzyResult.map[b].score - self.fuzzyResult.map[a].score;
-              return i === 0 ? a.localeCompare(b) : i;
-            });
-            return items;
-          },
-
-          highlighter: function (item) {
-            return $('<div>' + self.fuzzyResult.map[item].string + '</div>')
-          },
-        });
+        self.initCompletion(metrics);
         // This needs to happen after attaching the typeahead plugin, as it
         // otherwise breaks the typeahead functionality.
         self.expr.focus();
@@ -283,6 +452,267 @@ Prometheus.Graph.prototype.populateInsertableMetrics = function() {
   });
 };
 
+// completionContext returns what can be completed at the cursor of the
+// expression input: the kind of completion, the partial token before the
+// cursor and, for label values, the name of the label.
+Prometheus.Graph.prototype.completionContext = function() {
+  var el = this.expr[0];
+  var before = el.value.substring(0, el.selectionStart);
+
+  var m = before.match(/([a-zA-Z_]\w*)\s*(=~|!~|!=|=)\s*"((?:[^"\\]|\\.)*)$/);
+  if (m) {
+    return {kind: "labelValue", label: m[1], token: m[3]};
+  }
+  // Strings may contain any characters, so only complete outside of them.
+  if ((before.replace(/\\./g, "").match(/"/g) || []).length % 2 === 1) {
+    return {kind: "none", token: ""};
+  }
+  if (before.lastIndexOf("[") > before.lastIndexOf("]")) {
+    return {kind: "none", token: ""};
+  }
+  m = before.match(/\b(by|without|on|ignoring|group_left|group_right)\s*\(([\w\s,]*)$/);
+  if (m || before.lastIndexOf("{") > before.lastIndexOf("}")) {
+    m = before.match(/(^|[{(,\s])(\w*)$/);
+    return m ? {kind: "labelName", token: m[2]} : {kind: "none", token: ""};
+  }
+  m = before.match(/(^|[^\w:])([a-zA-Z_:][\w:]*)$/);
+  return m ? {kind: "metric", token: m[2]} : {kind: "none", token: ""};
+};
+
+// completionItems calls process with the items completing the given context.
+// Label names and values are fetched once per graph and label.
+Prometheus.Graph.prototype.completionItems = function(ctx, process) {
+  var self = this;
+  var fetch = function(key, url) {
+    if (self.completionCache[key]) {
+      process(self.completionCache[key]);
+      return;
+    }
+    $.ajax({
+      method: "GET",
+      url: PATH_PREFIX + url,
+      dataType: "json",
+      success: function(json) {
+        self.completionCache[key] = json.data || [];
+        // Only show the items if the cursor is still in the same context.
+        if (self.completion.kind === ctx.kind && self.completion.label === ctx.label) {
+          process(self.completionCache[key]);
+        }
+      }
+    });
+  };
+
+  switch (ctx.kind) {
+  case "metric":
+    process(self.metricNames.concat(Object.keys(Prometheus.Graph.functionDocs)));
+    break;
+  case "labelName":
+    fetch("labels", "/api/v1/labels");
+    break;
+  case "labelValue":
+    fetch("label:" + ctx.label, "/api/v1/label/" + encodeURIComponent(ctx.label) + "/values");
+    break;
+  default:
+    process([]);
+  }
+};
+
+// initCompletion attaches the autocompletion of metric names, function names,
+// label names and label values to the expression input. Only the token before
+// the cursor is completed, depending on where in the expression it is.
+Prometheus.Graph.prototype.initCompletion = function(metrics) {
+  var self = this;
+  self.metricNames = metrics;
+  self.completion = {kind: "none", token: ""};
+  self.completionCache = {};
+  self.fuzzyResult = {
+    query: null,
+    result: null,
+    map: {}
+  };
+
+  self.expr.typeahead({
+    items: "all",
+    minLength: 0,
+    source: function(query, process) {
+      self.completion = self.completionContext();
+      // Metric and function names are only completed once typing started.
+      if (self.completion.kind === "none" || (self.completion.kind === "metric" && self.completion.token === "")) {
+        process([]);
+        return;
+      }
+      self.completionItems(self.completion, process);
+    },
+
+    matcher: function(item) {
+      var query = self.completion.kind + ":" + self.completion.token;
+      // If we have result for current query, skip
+      if (self.fuzzyResult.query !== query) {
+        self.fuzzyResult.query = query;
+        self.fuzzyResult.map = {};
+      }
+      if (!(item in self.fuzzyResult.map)) {
+        var r = null;
+        if (self.completion.token === "") {
+          r = {rendered: item, score: 0};
+        } else {
+          r = fuzzy.match(self.completion.token, item, {
+            pre: '<strong>',
+            post: '</strong>'
+          });
+        }
+        // Label values may contain HTML, which is escaped without highlighting.
+        if (r && escapeHTML(item) !== item) {
+          r.rendered = escapeHTML(item);
+        }
+        self.fuzzyResult.map[item] = r ? {string: r.rendered, score: r.score} : null;
+      }
+      return self.fuzzyResult.map[item] !== null;
+    },
+
+    sorter: function(items) {
+      items.sort(function(a,b) {
+        var i = self.fuzzyResult.map[b].score - self.fuzzyResult.map[a].score;
+        // Exact matches score infinitely.
+        return i === 0 || isNaN(i) ? a.localeCompare(b) : i;
+      });
+      return items;
+    },
+
+    highlighter: function (item) {
+      var html = '<div>' + self.fuzzyResult.map[item].string;
+      var doc = self.completion.kind === "metric" && Prometheus.Graph.functionDocs[item];
+      if (doc) {
+        html += ' <small class="text-muted">' + escapeHTML(doc.signature) + '</small>';
+      }
+      return $(html + '</div>');
+    },
+
+    // updater replaces the token before the cursor with the selected item and
+    // returns the new expression.
+    updater: function(item) {
+      var el = self.expr[0];
+      var value = el.value;
+      var start = el.selectionStart - self.completion.token.length;
+      var end = el.selectionStart;
+      var text = item;
+      if (self.completion.kind === "labelValue") {
+        text = item.replace(/\\/g, "\\\\").replace(/"/g, '\\"');
+        if (value.charAt(end) !== '"') {
+          text += '"';
+        }
+      } else if (self.completion.kind === "metric" && Prometheus.Graph.functionDocs[item] && value.charAt(end) !== "(") {
+        text += "(";
+      }
+      self.completionCursor = start + text.length;
+      return value.substring(0, start) + text + value.substring(end);
+    },
+
+    afterSelect: function() {
+      self.expr[0].setSelectionRange(self.completionCursor, self.completionCursor);
+      self.updateFunctionDoc();
+    }
+  });
+
+  self.expr.on("keyup click focus", function() {
+    self.updateFunctionDoc();
+  });
+  self.expr.on("blur", function() {
+    self.showFunctionDoc(null);
+  });
+};
+
+// functionAtCursor returns the name of the function whose name or arguments
+// the cursor of the expression input is in, if any.
+Prometheus.Graph.prototype.functionAtCursor = function() {
+  var el = this.expr[0];
+  var value = el.value;
+  var pos = el.selectionStart;
+
+  // The cursor is on a function name.
+  var m = value.substring(0, pos).match(/[\w:]*$/)[0] + value.substring(pos).match(/^[\w:]*/)[0];
+  if (Prometheus.Graph.functionDocs[m]) {
+    return m;
+  }
+  // Otherwise find the innermost unclosed parenthesis before the cursor,
+  // ignoring those in strings.
+  var before = value.substring(0, pos).replace(/"(?:[^"\\]|\\.)*("|$)/g, '""');
+  var depth = 0;
+  for (var i = before.length - 1; i >= 0; i--) {
+    var c = before.charAt(i);
+    if (c === ")") {
+      depth++;
+    } else if (c === "(") {
+      if (depth === 0) {
+        var name = before.substring(0, i).match(/([\w:]*)\s*$/)[1];
+        if (Prometheus.Graph.functionDocs[name]) {
+          return name;
+        }
+        // Grouping or aggregation parentheses, continue with the enclosing ones.
+      } else {
+        depth--;
+      }
+    }
+  }
+  return null;
+};
+
+Prometheus.Graph.prototype.updateFunctionDoc = function() {
+  this.showFunctionDoc(this.functionAtCursor());
+};
+
+// showFunctionDoc shows the documentation of the named function above the
+// expression input or hides it if name is null.
+Prometheus.Graph.prototype.showFunctionDoc = function(name) {
+  var self = this;
+  if (name === self.docFunction) {
+    return;
+  }
+  self.docFunction = name;
+  if (name === null) {
+    self.expr.popover("destroy");
+    return;
+  }
+  var doc = Prometheus.Graph.functionDocs[name];
+  self.expr.popover("destroy");
+  // Destroying removes the popover only after its fade out transition.
+  setTimeout(function() {
+    if (self.docFunction !== name) {
+      return;
+    }
+    self.expr.popover({
+      trigger: "manual",
+      placement: "top",
+      container: "body",
+      template: '<div class="popover function_doc" role="tooltip"><div class="arrow"></div><h3 class="popover-title"></h3><div class="popover-content"></div></div>',
+      title: doc.signature,
+      content: doc.doc
+    }).popover("show");
+  }, 200);
+};
+
+// renderHistory fills the history menu with the recently executed
+// expressions.
+Prometheus.Graph.prototype.renderHistory = function() {
+  var self = this;
+  self.historyMenu.empty();
+  if (!Prometheus.Settings.get("queryHistory")) {
+    self.historyMenu.append('<li class="dropdown-header">The query history is disabled in the settings.</li>');
+    return;
+  }
+  var exprs = Prometheus.History.get();
+  if (exprs.length === 0) {
+    self.historyMenu.append('<li class="dropdown-header">No expressions executed yet.</li>');
+    return;
+  }
+  exprs.forEach(function(expr) {
+    var a = $('<a href="#" class="history_item"></a>').text(expr).attr("title", expr).data("expr", expr);
+    self.historyMenu.append($("<li></li>").append(a));
+  });
+  self.historyMenu.append('<li role="separator" class="divider"></li>');
+  self.historyMenu.append('<li><a href="#" class="clear_history">Clear history</a></li>');
+};
+
 Prometheus.Graph.prototype.getOptions = function() {
   var self = this;
   var options = {};
@@ -386,6 +816,7 @@ Prometheus.Graph.prototype.decreaseEnd = function() {
 Prometheus.Graph.prototype.submitQuery = function() {
   var self = this;
   self.clearError();
+  self.clearWarnings();
   if (!self.expr.val()) {
     return;
   }
@@ -429,6 +860,10 @@ Prometheus.Graph.prototype.submitQuery = function() {
           self.showError(json.error);
           return;
         }
+        Prometheus.History.add(params.query);
+        if (json.warnings) {
+          self.showWarnings(json.warnings);
+        }
         success(json.data, textStatus);
       },
       error: function(xhr, resp) {
@@ -473,6 +908,21 @@ Prometheus.Graph.prototype.clearError = function(msg) {
   self.error.hide();
 };
 
+Prometheus.Graph.prototype.showWarnings = function(warnings) {
+  var self = this;
+  self.warnings.empty();
+  warnings.forEach(function(w) {
+    self.warnings.append($("<div>").text("Warning: " + w));
+  });
+  self.warnings.show();
+};
+
+Prometheus.Graph.prototype.clearWarnings = function() {
+  var self = this;
+  self.warnings.empty();
+  self.warnings.hide();
+};
+
 Prometheus.Graph.prototype.updateRefresh = function() {
   var self = this;
 
@@ -822,6 +1272,42 @@ function escapeHTML(string) {
   });
 }
 
+// History holds the recently executed expressions, most recent first, in the
+// local storage of the browser if enabled in the settings.
+Prometheus.History = {
+  key: "history",
+  size: 50,
+
+  get: function() {
+    try {
+      return JSON.parse(localStorage.getItem(Prometheus.History.key)) || [];
+    } catch (e) {
+      return [];
+    }
+  },
+
+  add: function(expr) {
+    if (!Prometheus.Settings.get("queryHistory") || $.trim(expr) === "") {
+      return;
+    }
+    var exprs = Prometheus.History.get().filter(function(e) { return e !== expr; });
+    exprs.unshift(expr);
+    try {
+      localStorage.setItem(Prometheus.History.key, JSON.stringify(exprs.slice(0, Prometheus.History.size)));
+    } catch (e) {
+      // Local storage may be disabled or full.
+    }
+  },
+
+  clear: function() {
+    try {
+      localStorage.removeItem(Prometheus.History.key);
+    } catch (e) {
+      // Local storage may be disabled.
+    }
+  }
+};
+
 Prometheus.Page = function() {
   this.graphs = [];
 };
@@ -835,6 +1321,17 @@ Prometheus.Page.prototype.init = function() {
   graphOptions.forEach(this.addGraph, this);
 
   $("#add_graph").click(this.addGraph.bind(this, {}));
+
+  var self = this;
+  $("#permalink").click(function() {
+    var input = $("#permalink_url");
+    input.val(self.permalink()).show().select();
+    try {
+      document.execCommand("copy");
+    } catch (e) {
+      // The link stays selected for copying by hand.
+    }
+  });
 };
 
 Prometheus.Page.prototype.parseURL = function() {
@@ -842,6 +1339,11 @@ Prometheus.Page.prototype.parseURL = function() {
     return [];
   }
 
+  var permalink = window.location.search.match(/^\?p=([A-Za-z0-9_-]+)$/);
+  if (permalink) {
+    return Prometheus.Page.decodePermalink(permalink[1]);
+  }
+
   var queryParams = window.location.search.substring(1).split('&');
   var queryParamHelper = new Prometheus.Page.QueryParamHelper();
   return queryParamHelper.parseQueryParams(queryParams);
@@ -873,6 +1375,55 @@ Prometheus.Page.prototype.updateURL = function() {
   history.pushState({}, "", "graph?" + queryString);
 };
 
+// The graph options encoded in permalinks, in order.
+Prometheus.Page.permalinkOptions = ["expr", "range_input", "stacked", "end_input", "step_input", "tab"];
+
+// permalink returns a link to the page with all its graphs. The options of
+// the graphs are encoded compactly as a URL-safe base64 encoded JSON array
+// of arrays with the values of permalinkOptions.
+Prometheus.Page.prototype.permalink = function() {
+  var graphs = this.graphs.map(function(graph) {
+    var options = graph.getOptions();
+    var values = Prometheus.Page.permalinkOptions.map(function(name) {
+      return options[name] === undefined ? "" : options[name];
+    });
+    // Omit trailing unset options.
+    while (values.length > 1 && values[values.length - 1] === "") {
+      values.pop();
+    }
+    return values;
+  });
+  var json = JSON.stringify(graphs);
+  // Encode the UTF-8 bytes of the expressions.
+  var encoded = btoa(unescape(encodeURIComponent(json)))
+    .replace(/\+/g, "-").replace(/\//g, "_").replace(/=+$/, "");
+  return window.location.origin + PATH_PREFIX + "/graph?p=" + encoded;
+};
+
+// decodePermalink returns the graph options encoded in a permalink, or none
+// if it is invalid.
+Prometheus.Page.decodePermalink = function(encoded) {
+  var graphs;
+  try {
+    var b64 = encoded.replace(/-/g, "+").replace(/_/g, "/");
+    graphs = JSON.parse(decodeURIComponent(escape(atob(b64))));
+  } catch (e) {
+    return [];
+  }
+  if (!$.isArray(graphs)) {
+    return [];
+  }
+  return graphs.map(function(values) {
+    var options = {};
+    Prometheus.Page.permalinkOptions.forEach(function(name, i) {
+      if ($.isArray(values) && values[i] !== undefined && values[i] !== "") {
+        options[name] = values[i];
+      }
+    });
+    return options;
+  });
+};
+
 Prometheus.Page.prototype.removeGraph = function(graph) {
   this.graphs = this.graphs.filter(function(g) {return g !== graph});
 };
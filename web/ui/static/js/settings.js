@@ -0,0 +1,86 @@
+var Prometheus = Prometheus || {};
+
+// Settings holds the UI preferences of the user, persisted in the local
+// storage of the browser.
+Prometheus.Settings = {
+  defaults: {
+    // One of "light", "dark" or "auto", which follows the preference of
+    // the operating system.
+    theme: "auto",
+    // Whether executed expressions are recorded in the query history.
+    queryHistory: true,
+    // The range of newly added graphs.
+    graphRange: "1h"
+  },
+
+  get: function(name) {
+    try {
+      var value = localStorage.getItem("settings." + name);
+      if (value !== null) {
+        return JSON.parse(value);
+      }
+    } catch (e) {
+      // Local storage may be disabled or the setting invalid.
+    }
+    return Prometheus.Settings.defaults[name];
+  },
+
+  set: function(name, value) {
+    try {
+      localStorage.setItem("settings." + name, JSON.stringify(value));
+    } catch (e) {
+      // Local storage may be disabled.
+    }
+  },
+
+  darkQuery: window.matchMedia ? window.matchMedia("(prefers-color-scheme: dark)") : null,
+
+  // applyTheme sets the class of the theme in use on the document root.
+  applyTheme: function() {
+    var theme = Prometheus.Settings.get("theme");
+    if (theme === "auto") {
+      var q = Prometheus.Settings.darkQuery;
+      theme = q && q.matches ? "dark" : "light";
+    }
+    $(document.documentElement).toggleClass("theme-dark", theme === "dark");
+  },
+
+  // init binds the settings menu of the navigation bar.
+  init: function() {
+    var menu = $("#settings_menu");
+    // Keep the menu open while changing settings.
+    menu.on("click", function(e) {
+      e.stopPropagation();
+    });
+
+    menu.find("input[name=theme][value=" + Prometheus.Settings.get("theme") + "]").prop("checked", true);
+    menu.find("input[name=theme]").change(function() {
+      Prometheus.Settings.set("theme", this.value);
+      Prometheus.Settings.applyTheme();
+    });
+
+    menu.find("input[name=query_history]").prop("checked", Prometheus.Settings.get("queryHistory")).change(function() {
+      Prometheus.Settings.set("queryHistory", this.checked);
+    });
+
+    var rangeInput = menu.find("input[name=graph_range]");
+    rangeInput.val(Prometheus.Settings.get("graphRange")).change(function() {
+      var range = $.trim(this.value);
+      if (!/^\d+[ywdhms]$/.test(range)) {
+        $(this).closest(".form-group").addClass("has-error");
+        return;
+      }
+      $(this).closest(".form-group").removeClass("has-error");
+      Prometheus.Settings.set("graphRange", range);
+    });
+  }
+};
+
+// Apply the theme before the page is rendered to avoid flashing the light
+// theme.
+Prometheus.Settings.applyTheme();
+if (Prometheus.Settings.darkQuery && Prometheus.Settings.darkQuery.addListener) {
+  Prometheus.Settings.darkQuery.addListener(Prometheus.Settings.applyTheme);
+}
+
+$(Prometheus.Settings.init);
@@ -1,38 +1,219 @@
-function toggle(obj, state){
-    var icon = $(obj).find("i");
-    if (icon.length === 0 ) {
-        return;
+// The targets grouped by job, sorted by job and instance.
+var pools = [];
+
+function escapeHTML(string) {
+  var entityMap = {
+    "&": "&amp;",
+    "<": "&lt;",
+    ">": "&gt;",
+    '"': '&quot;',
+    "'": '&#39;',
+    "/": '&#x2F;'
+  };
+
+  return String(string).replace(/[&<>"'\/]/g, function (s) {
+    return entityMap[s];
+  });
+}
+
+function healthToClass(health) {
+  switch (health) {
+    case "up":
+      return "success";
+    case "unknown":
+      return "warning";
+    default:
+      return "danger";
+  }
+}
+
+function labelSpans(labels, omit) {
+  var names = Object.keys(labels).sort();
+  var spans = [];
+  for (var i = 0; i < names.length; i++) {
+    if (names[i] === omit) {
+      continue;
     }
+    spans.push('<span class="label label-primary">' + escapeHTML(names[i]) + '="' + escapeHTML(labels[names[i]]) + '"</span>');
+  }
+  if (spans.length === 0) {
+    return '<span class="label label-default">none</span>';
+  }
+  return spans.join(" ");
+}
 
-    if (state === true) {
-        icon.removeClass("icon-chevron-down").addClass("icon-chevron-up");
-    } else {
-        icon.removeClass("icon-chevron-up").addClass("icon-chevron-down");
+function since(lastScrape) {
+  var t = Date.parse(lastScrape);
+  // The zero time of Go marks targets that were never scraped.
+  if (isNaN(t) || t <= 0) {
+    return "Never";
+  }
+  var secs = Math.max(0, (Date.now() - t) / 1000);
+  if (secs < 60) {
+    return secs.toFixed(3) + "s ago";
+  }
+  return Math.floor(secs / 60) + "m" + Math.floor(secs % 60) + "s ago";
+}
+
+// searchText returns the text a target is matched against by the search.
+function searchText(target) {
+  var parts = [target.scrapeUrl];
+  for (var name in target.labels) {
+    parts.push(name + '="' + target.labels[name] + '"');
+  }
+  return parts.join(" ").toLowerCase();
+}
+
+function loadTargets() {
+  $.ajax({
+    method: "GET",
+    url: PATH_PREFIX + "/api/v1/targets",
+    data: {state: "active"},
+    dataType: "json"
+  }).done(function(resp) {
+    var byJob = {};
+    var targets = resp.data.activeTargets;
+    for (var i = 0; i < targets.length; i++) {
+      var t = targets[i];
+      var job = t.labels.job || "";
+      if (!byJob[job]) {
+        byJob[job] = {job: job, targets: [], up: 0};
+      }
+      t.search = searchText(t);
+      byJob[job].targets.push(t);
+      if (t.health === "up") {
+        byJob[job].up++;
+      }
     }
+    pools = Object.keys(byJob).sort().map(function(job) {
+      var pool = byJob[job];
+      pool.targets.sort(function(a, b) {
+        var ia = a.labels.instance || "", ib = b.labels.instance || "";
+        return ia < ib ? -1 : ia > ib ? 1 : 0;
+      });
+      return pool;
+    });
+    $("#targets-loading").hide();
+    render();
+  }).fail(function(xhr) {
+    $("#targets-loading").hide();
+    var msg = xhr.responseJSON ? xhr.responseJSON.error : xhr.statusText;
+    $("#targets-error").text("Error loading targets: " + msg).show();
+  });
+}
 
-    $(obj).next().toggle(state);
+function isCollapsed(job) {
+  return localStorage.getItem("job-" + job) === "true";
+}
+
+// filtered returns the targets of the pool that match the current filters.
+function filtered(pool) {
+  var search = $("#target-search").val().toLowerCase();
+  var unhealthy = $("#target-unhealthy").is(":checked");
+  return pool.targets.filter(function(t) {
+    if (unhealthy && t.health === "up") {
+      return false;
+    }
+    return search === "" || t.search.indexOf(search) !== -1;
+  });
+}
+
+function targetRows(targets) {
+  var rows = [];
+  for (var i = 0; i < targets.length; i++) {
+    var t = targets[i];
+    var error = t.lastError ? '<span class="alert alert-danger state_indicator">' + escapeHTML(t.lastError) + '</span>' : "";
+    rows.push(
+      "<tr>" +
+        '<td><a href="' + escapeHTML(t.globalUrl) + '">' + escapeHTML(t.scrapeUrl.split("?")[0]) + "</a></td>" +
+        '<td><span class="alert alert-' + healthToClass(t.health) + ' state_indicator text-uppercase">' + escapeHTML(t.health) + "</span></td>" +
+        '<td><span class="cursor-pointer" data-toggle="tooltip" data-html="true" title="' +
+          escapeHTML("<b>Before relabeling:</b><br>" + labelSpans(t.discoveredLabels)) + '">' +
+          labelSpans(t.labels, "job") + "</span></td>" +
+        "<td>" + since(t.lastScrape) + "</td>" +
+        "<td>" + error + "</td>" +
+      "</tr>"
+    );
+  }
+  return rows.join("");
+}
+
+// render renders the job sections. The targets of collapsed sections are
+// only rendered when they are expanded, which keeps the page responsive
+// with many targets.
+function render() {
+  var html = [];
+  var up = 0, total = 0;
+  for (var i = 0; i < pools.length; i++) {
+    var pool = pools[i];
+    var targets = filtered(pool);
+    up += pool.up;
+    total += pool.targets.length;
+    if (targets.length === 0) {
+      continue;
+    }
+    var collapsed = isCollapsed(pool.job);
+    html.push(
+      '<tr class="job_header' + (pool.up < pool.targets.length ? " danger" : "") + '" data-job="' + escapeHTML(pool.job) + '">' +
+        '<td colspan="5"><i class="' + (collapsed ? "icon-chevron-down" : "icon-chevron-up") + '"></i>' +
+        '<a id="job-' + escapeHTML(pool.job) + '" href="#job-' + escapeHTML(pool.job) + '">' + escapeHTML(pool.job) +
+        " (" + pool.up + "/" + pool.targets.length + " up)</a></td>" +
+      "</tr>" +
+      '<tr class="job_details"' + (collapsed ? ' style="display: none"' : "") + "><td>" +
+        '<table class="table table-condensed table-bordered table-striped table-hover">' +
+          "<thead><tr><th>Endpoint</th><th>State</th><th>Labels</th><th>Last Scrape</th><th>Error</th></tr></thead>" +
+          "<tbody>" + (collapsed ? "" : targetRows(targets)) + "</tbody>" +
+        "</table>" +
+      "</td></tr>"
+    );
+  }
+  $("#target-pools").html(html.join(""));
+  $("#targets-summary").text(up + "/" + total + " targets up in " + pools.length + " jobs");
+  $('[data-toggle="tooltip"]').tooltip();
+}
+
+function toggle(header, expand) {
+  var job = $(header).data("job").toString();
+  localStorage.setItem("job-" + job, !expand);
+
+  var icon = $(header).find("i");
+  var details = $(header).next();
+  if (expand) {
+    icon.removeClass("icon-chevron-down").addClass("icon-chevron-up");
+    for (var i = 0; i < pools.length; i++) {
+      if (pools[i].job === job) {
+        details.find("tbody").html(targetRows(filtered(pools[i])));
+        details.find('[data-toggle="tooltip"]').tooltip();
+        break;
+      }
+    }
+  } else {
+    icon.removeClass("icon-chevron-up").addClass("icon-chevron-down");
+    details.find("tbody").empty();
+  }
+  details.toggle(expand);
 }
 
 function init() {
-    $(".job_header").click(function() {
-        var job = $(this).find("a").attr("id"),
-            expanderIcon = $(this).find("i.icon-chevron-down");
-
-        if (expanderIcon.length !== 0) {
-            localStorage.setItem(job, false);
-            toggle(this, true);
-        } else {
-            localStorage.setItem(job, true);
-            toggle(this, false);
-        }
-    });
+  $("#target-pools").on("click", ".job_header", function() {
+    toggle(this, $(this).find("i.icon-chevron-down").length !== 0);
+  });
+  $("#targets-expand, #targets-collapse").click(function() {
+    var expand = this.id === "targets-expand";
+    for (var i = 0; i < pools.length; i++) {
+      localStorage.setItem("job-" + pools[i].job, !expand);
+    }
+    render();
+  });
 
-    $(".job_header a").each(function(i, obj) {
-        var selector = $(obj).attr("id");
-        if (localStorage.getItem(selector) === "true") {
-          toggle($(this).parents(".job_header"), false);
-        }
-    });
+  var timeout;
+  $("#target-search").on("input", function() {
+    clearTimeout(timeout);
+    timeout = setTimeout(render, 200);
+  });
+  $("#target-unhealthy").change(render);
+
+  loadTargets();
 }
 
-$(init);
\ No newline at end of file
+$(init);
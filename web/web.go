@@ -16,10 +16,10 @@ package web
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
-	"io/ioutil"
 	stdlog "log"
 	"net"
 	"net/http"
@@ -28,12 +28,15 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
+	"runtime"
 	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"golang.org/x/net/http2"
 	"google.golang.org/grpc"
 
 	pprof_runtime "runtime/pprof"
@@ -49,15 +52,15 @@ import (
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/common/route"
 	"github.com/prometheus/tsdb"
-	"golang.org/x/net/netutil"
 
 	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/discovery"
 	"github.com/prometheus/prometheus/notifier"
-	"github.com/prometheus/prometheus/pkg/labels"
 	"github.com/prometheus/prometheus/promql"
 	"github.com/prometheus/prometheus/retrieval"
 	"github.com/prometheus/prometheus/rules"
 	"github.com/prometheus/prometheus/storage"
+	storage_tsdb "github.com/prometheus/prometheus/storage/tsdb"
 	"github.com/prometheus/prometheus/template"
 	"github.com/prometheus/prometheus/util/httputil"
 	api_v1 "github.com/prometheus/prometheus/web/api/v1"
@@ -71,7 +74,7 @@ var localhostRepresentations = []string{"127.0.0.1", "localhost"}
 type Handler struct {
 	logger log.Logger
 
-	targetManager *retrieval.TargetManager
+	scrapeManager *retrieval.ScrapeManager
 	ruleManager   *rules.Manager
 	queryEngine   *promql.Engine
 	context       context.Context
@@ -83,7 +86,8 @@ type Handler struct {
 
 	router       *route.Router
 	quitCh       chan struct{}
-	reloadCh     chan chan error
+	quitOnce     sync.Once
+	reloadCh     chan chan ReloadResult
 	options      *Options
 	config       *config.Config
 	configString string
@@ -96,15 +100,25 @@ type Handler struct {
 	mtx            sync.RWMutex
 	now            func() model.Time
 
+	tls  *tlsLoader
+	auth *authenticator
+
 	ready uint32 // ready is uint32 rather than boolean to be able to use atomic functions.
 }
 
-// ApplyConfig updates the config field of the Handler struct
+// ApplyConfig updates the config field of the Handler struct and reloads
+// the TLS certificates and basic authentication users if enabled.
 func (h *Handler) ApplyConfig(conf *config.Config) error {
+	if err := h.reloadSecurity(); err != nil {
+		return err
+	}
+
 	h.mtx.Lock()
 	defer h.mtx.Unlock()
 
 	h.config = conf
+	// The rules or external labels may have changed.
+	h.apiV1.ClearQueryCache()
 
 	return nil
 }
@@ -121,28 +135,76 @@ type PrometheusVersion struct {
 
 // Options for the web Handler.
 type Options struct {
-	Context       context.Context
-	TSDB          func() *tsdb.DB
-	Storage       storage.Storage
-	QueryEngine   *promql.Engine
-	TargetManager *retrieval.TargetManager
-	RuleManager   *rules.Manager
-	Notifier      *notifier.Notifier
-	Version       *PrometheusVersion
-	Flags         map[string]string
-
-	ListenAddress        string
-	ReadTimeout          time.Duration
-	MaxConnections       int
-	ExternalURL          *url.URL
-	RoutePrefix          string
-	MetricsPath          string
-	UseLocalAssets       bool
-	UserAssetsPath       string
-	ConsoleTemplatesPath string
-	ConsoleLibrariesPath string
-	EnableLifecycle      bool
-	EnableAdminAPI       bool
+	Context         context.Context
+	TSDB            func() *tsdb.DB
+	TSDBHealth      func() storage_tsdb.Health
+	NotReadyReason  func() string
+	Storage         storage.Storage
+	ExemplarStorage storage.ExemplarQuerier
+	QueryEngine     *promql.Engine
+	ScrapeManager   *retrieval.ScrapeManager
+	RuleManager     *rules.Manager
+	Notifier        *notifier.Notifier
+	Version         *PrometheusVersion
+	Flags           map[string]string
+
+	// Discovery managers of scrape jobs and Alertmanagers.
+	DiscoveryManagerScrape *discovery.Manager
+	DiscoveryManagerNotify *discovery.Manager
+
+	ListenAddress   string
+	ReadTimeout     time.Duration
+	CORSOrigin      *regexp.Regexp
+	ExternalURL     *url.URL
+	RoutePrefix     string
+	MetricsPath     string
+	UseLocalAssets  bool
+	UserAssetsPath  string
+	EnableLifecycle bool
+	EnableAdminAPI  bool
+
+	// In agent mode there is no query engine and rule manager, the pages
+	// and endpoints depending on them are disabled.
+	Agent bool
+
+	// Maximum number of concurrent HTTP requests per client IP, and of
+	// those to expensive endpoints like range queries and federation.
+	// Non-positive values disable the respective limit.
+	MaxRequestsPerClient          int
+	MaxExpensiveRequestsPerClient int
+
+	// Isolation of tenants in the query API.
+	Tenant api_v1.TenantOptions
+	// The maximum lookback delta queries of the API may request.
+	MaxLookbackDelta time.Duration
+	// The maximum estimated memory of cached range query results. Results
+	// are not cached if it is not positive.
+	QueryCacheMaxBytes int64
+	// Samples up to this time into the past may still be ingested, so
+	// cached results of ranges ending within it expire.
+	OutOfOrderTimeWindow time.Duration
+
+	// Console templates and libraries are read from all given directories
+	// on every request.
+	ConsoleTemplatesPaths []string
+	ConsoleLibrariesPaths []string
+
+	// TLS is enabled if both a certificate and key file are set. Clients
+	// are required to present a certificate signed by the client CA if
+	// TLSClientCAFile is set, unless TLSClientAuthOptional is true, in
+	// which case certificates are only verified if presented.
+	TLSCertFile           string
+	TLSKeyFile            string
+	TLSClientCAFile       string
+	TLSClientAuthOptional bool
+
+	// BasicAuthFile is the path to a file with users allowed to access all
+	// endpoints via HTTP basic authentication.
+	BasicAuthFile string
+
+	// AuthorizeFunc, if set, is consulted for every request to a route in
+	// one of the route groups.
+	AuthorizeFunc AuthorizeFunc
 }
 
 // New initializes a new web Handler.
@@ -161,7 +223,7 @@ func New(logger log.Logger, o *Options) *Handler {
 		logger:      logger,
 		router:      router,
 		quitCh:      make(chan struct{}),
-		reloadCh:    make(chan chan error),
+		reloadCh:    make(chan chan ReloadResult),
 		options:     o,
 		versionInfo: o.Version,
 		birth:       time.Now(),
@@ -169,7 +231,7 @@ func New(logger log.Logger, o *Options) *Handler {
 		flagsMap:    o.Flags,
 
 		context:       o.Context,
-		targetManager: o.TargetManager,
+		scrapeManager: o.ScrapeManager,
 		ruleManager:   o.RuleManager,
 		queryEngine:   o.QueryEngine,
 		tsdb:          o.TSDB,
@@ -181,13 +243,45 @@ func New(logger log.Logger, o *Options) *Handler {
 		ready: 0,
 	}
 
-	h.apiV1 = api_v1.NewAPI(h.queryEngine, h.storage, h.targetManager, h.notifier,
+	if o.TLSCertFile != "" && o.TLSKeyFile != "" {
+		h.tls = newTLSLoader(o.TLSCertFile, o.TLSKeyFile, o.TLSClientCAFile, o.TLSClientAuthOptional)
+	}
+	if o.BasicAuthFile != "" {
+		h.auth = newAuthenticator(o.BasicAuthFile)
+	}
+
+	// A nil manager must be passed as a nil interface, as the API only
+	// checks the interface for nil.
+	var sdScrape, sdNotify interface {
+		ProviderStatus() []discovery.ProviderStatus
+	}
+	if o.DiscoveryManagerScrape != nil {
+		sdScrape = o.DiscoveryManagerScrape
+	}
+	if o.DiscoveryManagerNotify != nil {
+		sdNotify = o.DiscoveryManagerNotify
+	}
+	h.apiV1 = api_v1.NewAPI(h.queryEngine, h.storage, h.scrapeManager, h.notifier, h.ruleManager, h.ruleManager,
+		sdScrape, sdNotify,
 		func() config.Config {
 			h.mtx.RLock()
 			defer h.mtx.RUnlock()
 			return *h.config
 		},
+		o.Flags,
+		(*api_v1.PrometheusVersion)(o.Version),
+		h.runtimeInfo,
+		h.tsdb,
+		o.CORSOrigin,
 		h.testReady,
+		o.EnableAdminAPI,
+		o.Agent,
+		o.ExemplarStorage,
+		o.Tenant,
+		o.MaxLookbackDelta,
+		o.QueryCacheMaxBytes,
+		o.OutOfOrderTimeWindow,
+		func(u *url.URL) *url.URL { return globalURL(u, o) },
 	)
 
 	if o.RoutePrefix != "/" {
@@ -198,90 +292,88 @@ func New(logger log.Logger, o *Options) *Handler {
 		router = router.WithPrefix(o.RoutePrefix)
 	}
 
-	instrh := prometheus.InstrumentHandler
-	instrf := prometheus.InstrumentHandlerFunc
+	instrh := httputil.InstrumentHandler
+	instrf := func(handlerName string, f http.HandlerFunc) http.HandlerFunc {
+		return httputil.InstrumentHandler(handlerName, f)
+	}
 	readyf := h.testReady
 
-	router.Get("/", func(w http.ResponseWriter, r *http.Request) {
-		http.Redirect(w, r, path.Join(o.ExternalURL.Path, "/graph"), http.StatusFound)
-	})
+	home := "/graph"
+	if o.Agent {
+		home = "/targets"
+	}
+	router.Get("/", instrf("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, path.Join(o.ExternalURL.Path, home), http.StatusFound)
+	}))
 
-	router.Get("/alerts", readyf(instrf("alerts", h.alerts)))
-	router.Get("/graph", readyf(instrf("graph", h.graph)))
-	router.Get("/status", readyf(instrf("status", h.status)))
-	router.Get("/flags", readyf(instrf("flags", h.flags)))
-	router.Get("/config", readyf(instrf("config", h.serveConfig)))
-	router.Get("/rules", readyf(instrf("rules", h.rules)))
-	router.Get("/targets", readyf(instrf("targets", h.targets)))
-	router.Get("/version", readyf(instrf("version", h.version)))
+	if !o.Agent {
+		router.Get("/alerts", instrf("/alerts", readyf(h.alerts)))
+		router.Get("/graph", instrf("/graph", readyf(h.graph)))
+		router.Get("/rules", instrf("/rules", readyf(h.rules)))
+	}
+	router.Get("/status", instrf("/status", readyf(h.status)))
+	router.Get("/flags", instrf("/flags", readyf(h.flags)))
+	router.Get("/config", instrf("/config", readyf(h.serveConfig)))
+	router.Get("/targets", instrf("/targets", readyf(h.targets)))
+	router.Get("/version", instrf("/version", readyf(h.version)))
 
-	router.Get("/heap", instrf("heap", h.dumpHeap))
+	router.Get("/heap", instrf("/heap", h.dumpHeap))
 
-	router.Get("/metrics", prometheus.Handler().ServeHTTP)
+	router.Get("/metrics", instrh("/metrics", httputil.CompressionHandler{
+		Handler: h.metricsHandler(prometheus.Handler()),
+	}))
 
-	router.Get("/federate", readyf(instrh("federate", httputil.CompressionHandler{
-		Handler: http.HandlerFunc(h.federation),
-	})))
+	if !o.Agent {
+		router.Get("/federate", instrh("/federate", h.authorize(RouteGroupFederation, readyf(httputil.CompressionHandler{
+			Handler: http.HandlerFunc(h.federation),
+		}.ServeHTTP))))
 
-	router.Get("/consoles/*filepath", readyf(instrf("consoles", h.consoles)))
+		router.Get("/consoles/*filepath", instrf("/consoles/*filepath", readyf(h.consoles)))
+	}
 
-	router.Get("/static/*filepath", instrf("static", h.serveStaticAsset))
+	router.Get("/static/*filepath", instrf("/static/*filepath", h.serveStaticAsset))
 
 	if o.UserAssetsPath != "" {
-		router.Get("/user/*filepath", instrf("user", route.FileServe(o.UserAssetsPath)))
+		router.Get("/user/*filepath", instrf("/user/*filepath", route.FileServe(o.UserAssetsPath)))
 	}
 
 	if o.EnableLifecycle {
-		router.Post("/-/quit", h.quit)
-		router.Post("/-/reload", h.reload)
+		router.Post("/-/quit", instrf("/-/quit", h.authorize(RouteGroupLifecycle, h.quit)))
+		router.Post("/-/reload", instrf("/-/reload", h.authorize(RouteGroupLifecycle, h.reload)))
 	} else {
-		router.Post("/-/quit", func(w http.ResponseWriter, _ *http.Request) {
+		router.Post("/-/quit", instrf("/-/quit", func(w http.ResponseWriter, _ *http.Request) {
 			w.WriteHeader(http.StatusForbidden)
 			w.Write([]byte("Lifecycle APIs are not enabled"))
-		})
-		router.Post("/-/reload", func(w http.ResponseWriter, _ *http.Request) {
+		}))
+		router.Post("/-/reload", instrf("/-/reload", func(w http.ResponseWriter, _ *http.Request) {
 			w.WriteHeader(http.StatusForbidden)
 			w.Write([]byte("Lifecycle APIs are not enabled"))
-		})
+		}))
 	}
-	router.Get("/-/quit", func(w http.ResponseWriter, _ *http.Request) {
+	router.Get("/-/quit", instrf("/-/quit", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusMethodNotAllowed)
 		w.Write([]byte("Only POST requests allowed"))
-	})
-	router.Get("/-/reload", func(w http.ResponseWriter, _ *http.Request) {
+	}))
+	router.Get("/-/reload", instrf("/-/reload", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusMethodNotAllowed)
 		w.Write([]byte("Only POST requests allowed"))
-	})
+	}))
 
-	router.Get("/debug/*subpath", serveDebug)
-	router.Post("/debug/*subpath", serveDebug)
+	router.Get("/debug/*subpath", instrf("/debug/*subpath", serveDebug))
+	router.Post("/debug/*subpath", instrf("/debug/*subpath", serveDebug))
 
-	router.Get("/-/healthy", func(w http.ResponseWriter, r *http.Request) {
+	router.Get("/-/healthy", instrf("/-/healthy", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		fmt.Fprintf(w, "Prometheus is Healthy.\n")
-	})
-	router.Get("/-/ready", readyf(func(w http.ResponseWriter, r *http.Request) {
+	}))
+	router.Get("/-/ready", instrf("/-/ready", readyf(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		fmt.Fprintf(w, "Prometheus is Ready.\n")
-	}))
+	})))
 
 	return h
 }
 
-var corsHeaders = map[string]string{
-	"Access-Control-Allow-Headers":  "Accept, Authorization, Content-Type, Origin",
-	"Access-Control-Allow-Methods":  "GET, OPTIONS",
-	"Access-Control-Allow-Origin":   "*",
-	"Access-Control-Expose-Headers": "Date",
-}
-
-// Enables cross-site script calls.
-func setCORS(w http.ResponseWriter) {
-	for h, v := range corsHeaders {
-		w.Header().Set(h, v)
-	}
-}
-
 func serveDebug(w http.ResponseWriter, req *http.Request) {
 	ctx := req.Context()
 	subpath := route.Param(ctx, "subpath")
@@ -356,6 +448,11 @@ func (h *Handler) testReady(f http.HandlerFunc) http.HandlerFunc {
 		} else {
 			w.WriteHeader(http.StatusServiceUnavailable)
 			fmt.Fprintf(w, "Service Unavailable")
+			if h.options.NotReadyReason != nil {
+				if reason := h.options.NotReadyReason(); reason != "" {
+					fmt.Fprintf(w, ": %s", reason)
+				}
+			}
 		}
 	}
 }
@@ -365,44 +462,91 @@ func (h *Handler) testReadyHandler(f http.Handler) http.HandlerFunc {
 	return h.testReady(f.ServeHTTP)
 }
 
+// reloadSecurity reloads the TLS certificates and basic authentication
+// users from disk if they are enabled.
+func (h *Handler) reloadSecurity() error {
+	if h.tls != nil {
+		if err := h.tls.reload(); err != nil {
+			return err
+		}
+	}
+	if h.auth != nil {
+		if err := h.auth.reload(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Quit returns the receive-only quit channel.
 func (h *Handler) Quit() <-chan struct{} {
 	return h.quitCh
 }
 
+// ReloadResult is the outcome of a configuration reload request.
+type ReloadResult struct {
+	// Changes are the sections of the configuration that changed.
+	Changes []config.Change
+	Err     error
+}
+
 // Reload returns the receive-only channel that signals configuration reload requests.
-func (h *Handler) Reload() <-chan chan error {
+func (h *Handler) Reload() <-chan chan ReloadResult {
 	return h.reloadCh
 }
 
 // Run serves the HTTP endpoints.
 func (h *Handler) Run(ctx context.Context) error {
-	level.Info(h.logger).Log("msg", "Start listening for connections", "address", h.options.ListenAddress)
+	level.Info(h.logger).Log("msg", "Start listening for connections", "address", h.options.ListenAddress, "tls", h.tls != nil)
+
+	if err := h.reloadSecurity(); err != nil {
+		return err
+	}
 
-	listener, err := net.Listen("tcp", h.options.ListenAddress)
+	listener, err := listen(h.options.ListenAddress)
 	if err != nil {
 		return err
 	}
-	listener = netutil.LimitListener(listener, h.options.MaxConnections)
 
 	// Monitor incoming connections with conntrack.
 	listener = conntrack.NewListener(listener,
 		conntrack.TrackWithName("http"),
 		conntrack.TrackWithTracing())
 
+	// TLS is terminated before multiplexing so that gRPC and HTTP
+	// connections are secured alike.
+	if h.tls != nil {
+		listener = tls.NewListener(listener, h.tls.config())
+	}
+
 	var (
-		m       = cmux.New(listener)
-		grpcl   = m.Match(cmux.HTTP2HeaderField("content-type", "application/grpc"))
-		httpl   = m.Match(cmux.HTTP1Fast())
-		grpcSrv = grpc.NewServer()
+		m        = cmux.New(listener)
+		grpcl    = m.Match(cmux.HTTP2HeaderField("content-type", "application/grpc"))
+		httpl    = m.Match(cmux.HTTP1Fast())
+		http2l   = m.Match(cmux.HTTP2())
+		gwl      = newPipeListener()
+		grpcOpts []grpc.ServerOption
+
+		interceptors []grpc.UnaryServerInterceptor
 	)
+	if h.auth != nil {
+		interceptors = append(interceptors, h.auth.unaryInterceptor)
+	}
+	if h.options.AuthorizeFunc != nil {
+		interceptors = append(interceptors, h.authorizeUnaryInterceptor)
+	}
+	if len(interceptors) > 0 {
+		grpcOpts = append(grpcOpts, grpc.UnaryInterceptor(chainUnaryInterceptors(interceptors...)))
+	}
+	grpcSrv := grpc.NewServer(grpcOpts...)
+
 	av2 := api_v2.New(
 		time.Now,
 		h.options.TSDB,
 		h.options.QueryEngine,
 		h.options.Storage.Querier,
 		func() []*retrieval.Target {
-			return h.options.TargetManager.Targets()
+			return h.options.ScrapeManager.Targets()
 		},
 		func() []*url.URL {
 			return h.options.Notifier.Alertmanagers()
@@ -411,12 +555,14 @@ func (h *Handler) Run(ctx context.Context) error {
 	)
 	av2.RegisterGRPC(grpcSrv)
 
-	hh, err := av2.HTTPHandler(grpcl.Addr().String())
+	// The gateway talks to the gRPC server in-memory rather than through
+	// the external listener.
+	hh, err := av2.HTTPHandler(gwl.Addr().String(), grpc.WithDialer(gwl.Dial))
 	if err != nil {
 		return err
 	}
 
-	hhFunc := h.testReadyHandler(hh)
+	hhFunc := h.authorize(RouteGroupAdmin, h.testReadyHandler(hh))
 
 	operationName := nethttp.OperationNameFunc(func(r *http.Request) string {
 		return fmt.Sprintf("%s %s", r.Method, r.URL.Path)
@@ -432,19 +578,32 @@ func (h *Handler) Run(ctx context.Context) error {
 		level.Info(h.logger).Log("msg", "router prefix", "prefix", h.options.RoutePrefix)
 	}
 
-	mux.Handle(apiPath+"/v1/", http.StripPrefix(apiPath+"/v1", av1))
+	mux.Handle(apiPath+"/v1/", http.StripPrefix(apiPath+"/v1", h.authorizeAPIV1(av1)))
 
 	mux.Handle(apiPath+"/", http.StripPrefix(apiPath,
-		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			setCORS(w)
+		httputil.InstrumentHandler("/api/v2", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			httputil.SetCORS(w, h.options.CORSOrigin, r)
 			hhFunc(w, r)
-		}),
+		})),
 	))
 
 	errlog := stdlog.New(log.NewStdlibAdapter(level.Error(h.logger)), "", 0)
 
+	var handler http.Handler = mux
+	if h.auth != nil {
+		handler = h.auth.handler(handler)
+	}
+	// Limit requests before authentication so that clients cannot exhaust
+	// resources with invalid credentials either.
+	handler = newLimitHandler(handler,
+		h.options.MaxRequestsPerClient,
+		h.options.MaxExpensiveRequestsPerClient,
+		apiPath+"/v1/query_range",
+		path.Join(h.options.RoutePrefix, "/federate"),
+	)
+
 	httpSrv := &http.Server{
-		Handler:     nethttp.Middleware(opentracing.GlobalTracer(), mux, operationName),
+		Handler:     nethttp.Middleware(opentracing.GlobalTracer(), handler, operationName),
 		ErrorLog:    errlog,
 		ReadTimeout: h.options.ReadTimeout,
 	}
@@ -454,11 +613,29 @@ func (h *Handler) Run(ctx context.Context) error {
 			level.Warn(h.logger).Log("msg", "error serving HTTP", "err", err)
 		}
 	}()
+	go func() {
+		// Clients negotiating HTTP/2 through TLS ALPN, other than gRPC, are
+		// served the same handler as HTTP/1 clients.
+		http2Srv := &http2.Server{}
+		for {
+			conn, err := http2l.Accept()
+			if err != nil {
+				level.Warn(h.logger).Log("msg", "error serving HTTP/2", "err", err)
+				return
+			}
+			go http2Srv.ServeConn(conn, &http2.ServeConnOpts{BaseConfig: httpSrv})
+		}
+	}()
 	go func() {
 		if err := grpcSrv.Serve(grpcl); err != nil {
 			level.Warn(h.logger).Log("msg", "error serving gRPC", "err", err)
 		}
 	}()
+	go func() {
+		if err := grpcSrv.Serve(gwl); err != nil {
+			level.Warn(h.logger).Log("msg", "error serving gRPC gateway", "err", err)
+		}
+	}()
 
 	errCh := make(chan error)
 	go func() {
@@ -493,14 +670,13 @@ func (h *Handler) alerts(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) consoles(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	name := route.Param(ctx, "filepath")
+	name := path.Clean("/" + route.Param(ctx, "filepath"))
 
-	file, err := http.Dir(h.options.ConsoleTemplatesPath).Open(name)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+	text, err := readConsoleTemplate(h.options.ConsoleTemplatesPaths, name)
+	if os.IsNotExist(err) {
+		http.Error(w, fmt.Sprintf("console template %q not found", name), http.StatusNotFound)
 		return
 	}
-	text, err := ioutil.ReadAll(file)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -527,8 +703,8 @@ func (h *Handler) consoles(w http.ResponseWriter, r *http.Request) {
 		Path:      strings.TrimLeft(name, "/"),
 	}
 
-	tmpl := template.NewTemplateExpander(h.context, string(text), "__console_"+name, data, h.now(), h.queryEngine, h.options.ExternalURL)
-	filenames, err := filepath.Glob(h.options.ConsoleLibrariesPath + "/*.lib")
+	tmpl := template.NewTemplateExpander(h.context, text, "__console_"+name, data, h.now(), h.queryEngine, h.options.ExternalURL)
+	filenames, err := consoleLibraries(h.options.ConsoleLibrariesPaths)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -546,19 +722,61 @@ func (h *Handler) graph(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) status(w http.ResponseWriter, r *http.Request) {
+	var storageHealth *storage_tsdb.Health
+	if h.options.TSDBHealth != nil {
+		sh := h.options.TSDBHealth()
+		storageHealth = &sh
+	}
 	h.executeTemplate(w, "status.html", struct {
-		Birth         time.Time
-		CWD           string
-		Version       *PrometheusVersion
-		Alertmanagers []*url.URL
+		Birth                time.Time
+		CWD                  string
+		Version              *PrometheusVersion
+		Alertmanagers        []*url.URL
+		DroppedAlertmanagers []*url.URL
+		StorageHealth        *storage_tsdb.Health
 	}{
-		Birth:         h.birth,
-		CWD:           h.cwd,
-		Version:       h.versionInfo,
-		Alertmanagers: h.notifier.Alertmanagers(),
+		Birth:                h.birth,
+		CWD:                  h.cwd,
+		Version:              h.versionInfo,
+		Alertmanagers:        h.notifier.Alertmanagers(),
+		DroppedAlertmanagers: h.notifier.DroppedAlertmanagers(),
+		StorageHealth:        storageHealth,
 	})
 }
 
+func (h *Handler) runtimeInfo() api_v1.RuntimeInfo {
+	status := api_v1.RuntimeInfo{
+		StartTime:        h.birth,
+		CWD:              h.cwd,
+		GoroutineCount:   runtime.NumGoroutine(),
+		GOMAXPROCS:       runtime.GOMAXPROCS(0),
+		GOGC:             os.Getenv("GOGC"),
+		GODEBUG:          os.Getenv("GODEBUG"),
+		StorageRetention: h.flagsMap["storage.tsdb.retention"],
+	}
+
+	// The outcome of configuration reloads is only tracked by the metrics
+	// of the process reloading the configuration.
+	mfs, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		level.Warn(h.logger).Log("msg", "Error gathering reload status metrics", "err", err)
+		return status
+	}
+	for _, mf := range mfs {
+		if len(mf.GetMetric()) == 0 {
+			continue
+		}
+		v := mf.GetMetric()[0].GetGauge().GetValue()
+		switch mf.GetName() {
+		case "prometheus_config_last_reload_successful":
+			status.ReloadConfigSuccess = v == 1
+		case "prometheus_config_last_reload_success_timestamp_seconds":
+			status.LastConfigTime = time.Unix(int64(v), 0)
+		}
+	}
+	return status
+}
+
 func (h *Handler) flags(w http.ResponseWriter, r *http.Request) {
 	h.executeTemplate(w, "flags.html", h.flagsMap)
 }
@@ -574,25 +792,9 @@ func (h *Handler) rules(w http.ResponseWriter, r *http.Request) {
 	h.executeTemplate(w, "rules.html", h.ruleManager)
 }
 
+// targets serves the targets page, which loads the targets from the API.
 func (h *Handler) targets(w http.ResponseWriter, r *http.Request) {
-	// Bucket targets by job label
-	tps := map[string][]*retrieval.Target{}
-	for _, t := range h.targetManager.Targets() {
-		job := t.Labels().Get(model.JobLabel)
-		tps[job] = append(tps[job], t)
-	}
-
-	for _, targets := range tps {
-		sort.Slice(targets, func(i, j int) bool {
-			return targets[i].Labels().Get(labels.InstanceName) < targets[j].Labels().Get(labels.InstanceName)
-		})
-	}
-
-	h.executeTemplate(w, "targets.html", struct {
-		TargetPools map[string][]*retrieval.Target
-	}{
-		TargetPools: tps,
-	})
+	h.executeTemplate(w, "targets.html", nil)
 }
 
 func (h *Handler) version(w http.ResponseWriter, r *http.Request) {
@@ -604,19 +806,37 @@ func (h *Handler) version(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) quit(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintf(w, "Requesting termination... Goodbye!")
-	close(h.quitCh)
+	// Repeated requests must not close the channel twice.
+	h.quitOnce.Do(func() {
+		close(h.quitCh)
+	})
 }
 
 func (h *Handler) reload(w http.ResponseWriter, r *http.Request) {
-	rc := make(chan error)
-	h.reloadCh <- rc
-	if err := <-rc; err != nil {
-		http.Error(w, fmt.Sprintf("failed to reload config: %s", err), http.StatusInternalServerError)
+	rc := make(chan ReloadResult)
+	select {
+	case h.reloadCh <- rc:
+	case <-r.Context().Done():
+		http.Error(w, "reload request canceled", http.StatusServiceUnavailable)
+		return
+	}
+	res := <-rc
+	if res.Err != nil {
+		http.Error(w, fmt.Sprintf("failed to reload config: %s", res.Err), http.StatusInternalServerError)
+		return
+	}
+	// Report the changed sections so that it is clear what took effect.
+	if len(res.Changes) == 0 {
+		fmt.Fprintln(w, "No configuration changes")
+		return
+	}
+	for _, c := range res.Changes {
+		fmt.Fprintln(w, c)
 	}
 }
 
 func (h *Handler) consolesPath() string {
-	if _, err := os.Stat(h.options.ConsoleTemplatesPath + "/index.html"); !os.IsNotExist(err) {
+	if _, err := readConsoleTemplate(h.options.ConsoleTemplatesPaths, "/index.html"); err == nil {
 		return h.options.ExternalURL.Path + "/consoles/index.html"
 	}
 	if h.options.UserAssetsPath != "" {
@@ -627,6 +847,45 @@ func (h *Handler) consolesPath() string {
 	return ""
 }
 
+// globalURL returns u with the localhost addresses of targets replaced by
+// the externally reachable ones, so that the web UI can link to them.
+func globalURL(u *url.URL, opts *Options) *url.URL {
+	host, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		return u
+	}
+	for _, lhr := range localhostRepresentations {
+		if host == lhr {
+			// Socket listen addresses have no port and never match.
+			_, ownPort, err := net.SplitHostPort(opts.ListenAddress)
+
+			if err == nil && port == ownPort {
+				// Only in the case where the target is on localhost and its port is
+				// the same as the one we're listening on, we know for sure that
+				// we're monitoring our own process and that we need to change the
+				// scheme, hostname, and port to the externally reachable ones as
+				// well. We shouldn't need to touch the path at all, since if a
+				// path prefix is defined, the path under which we scrape ourselves
+				// should already contain the prefix.
+				u.Scheme = opts.ExternalURL.Scheme
+				u.Host = opts.ExternalURL.Host
+			} else {
+				// Otherwise, we only know that localhost is not reachable
+				// externally, so we replace only the hostname by the one in the
+				// external URL. It could be the wrong hostname for the service on
+				// this port, but it's still the best possible guess.
+				host, _, err := net.SplitHostPort(opts.ExternalURL.Host)
+				if err != nil {
+					return u
+				}
+				u.Host = host + ":" + port
+			}
+			break
+		}
+	}
+	return u
+}
+
 func tmplFuncs(consolesPath string, opts *Options) template_text.FuncMap {
 	return template_text.FuncMap{
 		"since": func(t time.Time) time.Duration {
@@ -634,66 +893,16 @@ func tmplFuncs(consolesPath string, opts *Options) template_text.FuncMap {
 		},
 		"consolesPath": func() string { return consolesPath },
 		"pathPrefix":   func() string { return opts.ExternalURL.Path },
+		"agentMode":    func() bool { return opts.Agent },
 		"buildVersion": func() string { return opts.Version.Revision },
-		"stripLabels": func(lset map[string]string, labels ...string) map[string]string {
-			for _, ln := range labels {
-				delete(lset, ln)
-			}
-			return lset
-		},
 		"globalURL": func(u *url.URL) *url.URL {
-			host, port, err := net.SplitHostPort(u.Host)
-			if err != nil {
-				return u
-			}
-			for _, lhr := range localhostRepresentations {
-				if host == lhr {
-					_, ownPort, err := net.SplitHostPort(opts.ListenAddress)
-					if err != nil {
-						return u
-					}
-
-					if port == ownPort {
-						// Only in the case where the target is on localhost and its port is
-						// the same as the one we're listening on, we know for sure that
-						// we're monitoring our own process and that we need to change the
-						// scheme, hostname, and port to the externally reachable ones as
-						// well. We shouldn't need to touch the path at all, since if a
-						// path prefix is defined, the path under which we scrape ourselves
-						// should already contain the prefix.
-						u.Scheme = opts.ExternalURL.Scheme
-						u.Host = opts.ExternalURL.Host
-					} else {
-						// Otherwise, we only know that localhost is not reachable
-						// externally, so we replace only the hostname by the one in the
-						// external URL. It could be the wrong hostname for the service on
-						// this port, but it's still the best possible guess.
-						host, _, err := net.SplitHostPort(opts.ExternalURL.Host)
-						if err != nil {
-							return u
-						}
-						u.Host = host + ":" + port
-					}
-					break
-				}
-			}
-			return u
-		},
-		"numHealthy": func(pool []*retrieval.Target) int {
-			alive := len(pool)
-			for _, p := range pool {
-				if p.Health() != retrieval.HealthGood {
-					alive--
-				}
-			}
-
-			return alive
+			return globalURL(u, opts)
 		},
-		"healthToClass": func(th retrieval.TargetHealth) string {
-			switch th {
-			case retrieval.HealthUnknown:
+		"ruleHealthToClass": func(rh rules.RuleHealth) string {
+			switch rh {
+			case rules.HealthUnknown:
 				return "warning"
-			case retrieval.HealthGood:
+			case rules.HealthGood:
 				return "success"
 			default:
 				return "danger"
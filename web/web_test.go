@@ -25,6 +25,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/notifier"
 	"github.com/prometheus/prometheus/storage/tsdb"
 	"github.com/prometheus/prometheus/util/testutil"
 	libtsdb "github.com/prometheus/tsdb"
@@ -90,19 +93,20 @@ func TestReadyAndHealthy(t *testing.T) {
 	testutil.Ok(t, err)
 
 	opts := &Options{
-		ListenAddress:  ":9090",
-		ReadTimeout:    30 * time.Second,
-		MaxConnections: 512,
-		Context:        nil,
-		Storage:        &tsdb.ReadyStorage{},
-		QueryEngine:    nil,
-		TargetManager:  nil,
-		RuleManager:    nil,
-		Notifier:       nil,
-		RoutePrefix:    "/",
-		MetricsPath:    "/metrics/",
-		EnableAdminAPI: true,
-		TSDB:           func() *libtsdb.DB { return db },
+		ListenAddress:        ":9090",
+		ReadTimeout:          30 * time.Second,
+		MaxRequestsPerClient: 512,
+		Context:              nil,
+		Storage:              &tsdb.ReadyStorage{},
+		QueryEngine:          nil,
+		ScrapeManager:        nil,
+		RuleManager:          nil,
+		Notifier:             nil,
+		RoutePrefix:          "/",
+		MetricsPath:          "/metrics/",
+		EnableAdminAPI:       true,
+		TSDB:                 func() *libtsdb.DB { return db },
+		NotReadyReason:       func() string { return "WAL replay 37% done" },
 	}
 
 	opts.Flags = map[string]string{}
@@ -123,6 +127,9 @@ func TestReadyAndHealthy(t *testing.T) {
 
 	testutil.Ok(t, err)
 	testutil.Equals(t, http.StatusServiceUnavailable, resp.StatusCode)
+	body, err := ioutil.ReadAll(resp.Body)
+	testutil.Ok(t, err)
+	testutil.Equals(t, "Service Unavailable: WAL replay 37% done", string(body))
 
 	resp, err = http.Get("http://localhost:9090/version")
 
@@ -168,6 +175,33 @@ func TestReadyAndHealthy(t *testing.T) {
 	testutil.Equals(t, http.StatusOK, resp.StatusCode)
 }
 
+func TestStatusStorageHealth(t *testing.T) {
+	h := New(nil, &Options{
+		Notifier:    notifier.New(&notifier.Options{}, nil),
+		Flags:       map[string]string{},
+		RoutePrefix: "/",
+		ExternalURL: &url.URL{},
+		Version:     &PrometheusVersion{},
+		TSDBHealth: func() tsdb.Health {
+			return tsdb.Health{
+				WALReplayDuration: 3 * time.Second,
+				HeadSeries:        1234,
+				Events: []tsdb.HealthEvent{
+					{Time: time.Unix(0, 0), Description: "WAL corruption detected; truncating", Details: "file=000001"},
+				},
+			}
+		},
+	})
+
+	w := httptest.NewRecorder()
+	h.status(w, httptest.NewRequest("GET", "/status", nil))
+	body := w.Body.String()
+	testutil.Assert(t, w.Code == http.StatusOK, "unexpected status %d: %s", w.Code, body)
+	for _, s := range []string{"Storage Health", "<td>3s</td>", "<td>1234</td>", "Never", "WAL corruption detected; truncating", "file=000001"} {
+		testutil.Assert(t, strings.Contains(body, s), "expected status page to contain %q", s)
+	}
+}
+
 func TestRoutePrefix(t *testing.T) {
 	t.Parallel()
 	dbDir, err := ioutil.TempDir("", "tsdb-ready")
@@ -181,19 +215,19 @@ func TestRoutePrefix(t *testing.T) {
 	testutil.Ok(t, err)
 
 	opts := &Options{
-		ListenAddress:  ":9091",
-		ReadTimeout:    30 * time.Second,
-		MaxConnections: 512,
-		Context:        nil,
-		Storage:        &tsdb.ReadyStorage{},
-		QueryEngine:    nil,
-		TargetManager:  nil,
-		RuleManager:    nil,
-		Notifier:       nil,
-		RoutePrefix:    "/prometheus",
-		MetricsPath:    "/prometheus/metrics",
-		EnableAdminAPI: true,
-		TSDB:           func() *libtsdb.DB { return db },
+		ListenAddress:        ":9091",
+		ReadTimeout:          30 * time.Second,
+		MaxRequestsPerClient: 512,
+		Context:              nil,
+		Storage:              &tsdb.ReadyStorage{},
+		QueryEngine:          nil,
+		ScrapeManager:        nil,
+		RuleManager:          nil,
+		Notifier:             nil,
+		RoutePrefix:          "/prometheus",
+		MetricsPath:          "/prometheus/metrics",
+		EnableAdminAPI:       true,
+		TSDB:                 func() *libtsdb.DB { return db },
 	}
 
 	opts.Flags = map[string]string{}
@@ -296,3 +330,115 @@ func TestDebugHandler(t *testing.T) {
 		testutil.Equals(t, tc.code, w.Code)
 	}
 }
+
+func TestLifecycle(t *testing.T) {
+	do := func(h *Handler, method, url string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(method, url, nil)
+		testutil.Ok(t, err)
+		w := httptest.NewRecorder()
+		h.router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Lifecycle endpoints are forbidden unless explicitly enabled.
+	h := New(nil, &Options{RoutePrefix: "/", MetricsPath: "/metrics"})
+	testutil.Equals(t, http.StatusForbidden, do(h, "POST", "/-/quit").Code)
+	testutil.Equals(t, http.StatusForbidden, do(h, "POST", "/-/reload").Code)
+	testutil.Equals(t, http.StatusMethodNotAllowed, do(h, "GET", "/-/quit").Code)
+	testutil.Equals(t, http.StatusMethodNotAllowed, do(h, "GET", "/-/reload").Code)
+
+	h = New(nil, &Options{RoutePrefix: "/", MetricsPath: "/metrics", EnableLifecycle: true})
+
+	go func() {
+		rc := <-h.Reload()
+		rc <- ReloadResult{Err: fmt.Errorf("bad config")}
+	}()
+	testutil.Equals(t, http.StatusInternalServerError, do(h, "POST", "/-/reload").Code)
+
+	go func() {
+		rc := <-h.Reload()
+		rc <- ReloadResult{}
+	}()
+	w := do(h, "POST", "/-/reload")
+	testutil.Equals(t, http.StatusOK, w.Code)
+	testutil.Equals(t, "No configuration changes\n", w.Body.String())
+
+	go func() {
+		rc := <-h.Reload()
+		rc <- ReloadResult{Changes: []config.Change{
+			{Section: "global", Kind: config.ChangeChanged},
+			{Section: "scrape_configs", Name: "node", Kind: config.ChangeAdded},
+		}}
+	}()
+	w = do(h, "POST", "/-/reload")
+	testutil.Equals(t, http.StatusOK, w.Code)
+	testutil.Equals(t, "global changed\nscrape_configs[node] added\n", w.Body.String())
+
+	testutil.Equals(t, http.StatusOK, do(h, "POST", "/-/quit").Code)
+	select {
+	case <-h.Quit():
+	default:
+		t.Fatal("quit channel not closed")
+	}
+	// A second request must not panic.
+	testutil.Equals(t, http.StatusOK, do(h, "POST", "/-/quit").Code)
+}
+
+func TestRuntimeInfoReloadStatus(t *testing.T) {
+	success := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "prometheus_config_last_reload_successful",
+		Help: "Whether the last configuration reload attempt was successful.",
+	})
+	successTime := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "prometheus_config_last_reload_success_timestamp_seconds",
+		Help: "Timestamp of the last successful configuration reload.",
+	})
+	prometheus.MustRegister(success, successTime)
+	defer prometheus.Unregister(success)
+	defer prometheus.Unregister(successTime)
+
+	success.Set(1)
+	successTime.Set(1500000000)
+
+	h := New(nil, &Options{RoutePrefix: "/", MetricsPath: "/metrics"})
+	info := h.runtimeInfo()
+	testutil.Assert(t, info.ReloadConfigSuccess, "expected last reload to be successful")
+	testutil.Equals(t, int64(1500000000), info.LastConfigTime.Unix())
+
+	success.Set(0)
+	testutil.Assert(t, !h.runtimeInfo().ReloadConfigSuccess, "expected last reload to have failed")
+}
+
+func TestAgentMode(t *testing.T) {
+	h := New(nil, &Options{
+		Agent:       true,
+		Notifier:    notifier.New(&notifier.Options{}, nil),
+		Flags:       map[string]string{},
+		RoutePrefix: "/",
+		MetricsPath: "/metrics",
+		ExternalURL: &url.URL{},
+		Version:     &PrometheusVersion{},
+	})
+	h.Ready()
+
+	do := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.router.ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+		return w
+	}
+
+	w := do("/")
+	testutil.Equals(t, http.StatusFound, w.Code)
+	testutil.Equals(t, "/targets", w.Header().Get("Location"))
+
+	for _, u := range []string{"/graph", "/alerts", "/rules", "/federate", "/consoles/index.html"} {
+		testutil.Equals(t, http.StatusNotFound, do(u).Code)
+	}
+
+	w = do("/targets")
+	body := w.Body.String()
+	testutil.Assert(t, w.Code == http.StatusOK, "unexpected status %d: %s", w.Code, body)
+	for _, s := range []string{"/graph", "/alerts", "/rules"} {
+		testutil.Assert(t, !strings.Contains(body, `href="`+s+`"`), "expected navigation not to link to %s", s)
+	}
+}